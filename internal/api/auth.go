@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+type tokenCtxKey struct{}
+
+// publicPaths are served without a token, as are share links under
+// /share/. Share links and GitHub webhooks carry their own signature.
+var publicPaths = map[string]bool{"/healthz": true, "/readyz": true, "/openapi.json": true, "/webhooks/github": true}
+
+// withAuth enforces API tokens once any exist (`track token add`). Requests
+// must send "Authorization: Bearer <token>" with a role routeRoles allows.
+// The token is stored on the request context for apiContext and
+// checkStatusChange.
+func withAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if publicPaths[r.URL.Path] || strings.HasPrefix(r.URL.Path, "/share/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := context.Background()
+		store, err := sqlite.Open(ctx)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+		defer store.Close()
+
+		secret, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		tok, ok, err := store.AuthorizeAPIToken(ctx, secret, requiredRole(r))
+		var forbidden *sqlite.ForbiddenError
+		switch {
+		case errors.Is(err, sqlite.ErrTokenRequired) || errors.Is(err, sqlite.ErrTokenInvalid):
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		case errors.As(err, &forbidden):
+			writeError(w, http.StatusForbidden, err.Error())
+			return
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+		if ok {
+			r = r.WithContext(context.WithValue(r.Context(), tokenCtxKey{}, tok))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// routeRoles is the role each method of a route needs once API tokens
+// exist. Any other method, and any method of a route missing here, needs an
+// admin token, so a new mutating route is closed until it is listed. Status
+// changes are further limited by checkStatusChange; POST /issues always
+// creates todo issues.
+var routeRoles = map[string]map[string]string{
+	"/metrics":                     {http.MethodGet: sqlite.RoleRead},
+	"/issues":                      {http.MethodGet: sqlite.RoleRead, http.MethodPost: sqlite.RoleContributor},
+	"/issues:count":                {http.MethodGet: sqlite.RoleRead},
+	"/issues/{id}":                 {http.MethodGet: sqlite.RoleRead, http.MethodPatch: sqlite.RoleContributor},
+	"/issues/{id}/reorder":         {http.MethodPost: sqlite.RoleContributor},
+	"/issues/{id}/comments":        {http.MethodGet: sqlite.RoleRead, http.MethodPost: sqlite.RoleContributor},
+	"/issues/{id}/sections/{name}": {http.MethodGet: sqlite.RoleRead, http.MethodPatch: sqlite.RoleContributor},
+	"/next":                        {http.MethodGet: sqlite.RoleRead},
+}
+
+// requiredRole looks up the role a request needs in routeRoles. Reads of
+// unknown paths only need a read token, so they get their 404.
+func requiredRole(r *http.Request) string {
+	method := r.Method
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+	route := routePattern(r.URL.Path)
+	if role, ok := routeRoles[route][method]; ok {
+		return role
+	}
+	if route == "" && method == http.MethodGet {
+		return sqlite.RoleRead
+	}
+	return sqlite.RoleAdmin
+}
+
+// routePattern names the route of path as routeRoles does, the way
+// issueDetailHandler dispatches it, or returns "" for unknown paths.
+func routePattern(path string) string {
+	if _, ok := routeRoles[path]; ok {
+		return path
+	}
+	id, ok := strings.CutPrefix(path, "/issues/")
+	if !ok || id == "" {
+		return ""
+	}
+	if issueID, name, ok := strings.Cut(id, "/sections/"); ok && issueID != "" && name != "" && !strings.Contains(issueID, "/") && !strings.Contains(name, "/") {
+		return "/issues/{id}/sections/{name}"
+	}
+	for _, sub := range []string{"/reorder", "/comments"} {
+		if issueID, ok := strings.CutSuffix(id, sub); ok && issueID != "" && !strings.Contains(issueID, "/") {
+			return "/issues/{id}" + sub
+		}
+	}
+	if strings.Contains(id, "/") {
+		return ""
+	}
+	return "/issues/{id}"
+}
+
+func requestToken(r *http.Request) (sqlite.APIToken, bool) {
+	tok, ok := r.Context().Value(tokenCtxKey{}).(sqlite.APIToken)
+	return tok, ok
+}
+
+// checkStatusChange reports whether the request's token may move an issue
+// from one status to another.
+func checkStatusChange(r *http.Request, from, to string) bool {
+	tok, ok := requestToken(r)
+	return !ok || tok.MayChangeStatus(from, to)
+}
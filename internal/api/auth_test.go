@@ -0,0 +1,245 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestAPITokensAndRoles(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	for _, st := range []string{issue.StatusTodo, issue.StatusDone} {
+		if _, err := store.CreateIssue(ctx, issue.Item{Title: "t", Status: st, Priority: "p2"}); err != nil {
+			t.Fatalf("create issue: %v", err)
+		}
+	}
+
+	h := NewHandler()
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	if rr := do(http.MethodGet, "/issues", "", ""); rr.Code != http.StatusOK {
+		t.Fatalf("without tokens the API should stay open, got %d", rr.Code)
+	}
+
+	tokens := map[string]string{}
+	for _, role := range sqlite.Roles {
+		secret, err := store.CreateAPIToken(ctx, role+"-bot", role)
+		if err != nil {
+			t.Fatalf("create token: %v", err)
+		}
+		tokens[role] = secret
+	}
+
+	cases := []struct {
+		name, method, path, token, body string
+		want                            int
+	}{
+		{"health stays public", http.MethodGet, "/healthz", "", "", http.StatusOK},
+		{"missing token", http.MethodGet, "/issues", "", "", http.StatusUnauthorized},
+		{"unknown token", http.MethodGet, "/issues", "trk_nope", "", http.StatusUnauthorized},
+		{"read can list", http.MethodGet, "/issues", tokens[sqlite.RoleRead], "", http.StatusOK},
+		{"read cannot patch", http.MethodPatch, "/issues/TRK-1", tokens[sqlite.RoleRead], `{"priority":"p1"}`, http.StatusForbidden},
+		{"contributor can patch", http.MethodPatch, "/issues/TRK-1", tokens[sqlite.RoleContributor], `{"status":"ready"}`, http.StatusOK},
+		{"contributor cannot archive", http.MethodPatch, "/issues/TRK-1", tokens[sqlite.RoleContributor], `{"status":"archived"}`, http.StatusForbidden},
+		{"contributor cannot reopen", http.MethodPatch, "/issues/TRK-2", tokens[sqlite.RoleContributor], `{"status":"todo"}`, http.StatusForbidden},
+		{"admin can reopen", http.MethodPatch, "/issues/TRK-2", tokens[sqlite.RoleAdmin], `{"status":"todo"}`, http.StatusOK},
+	}
+	for _, tc := range cases {
+		if rr := do(tc.method, tc.path, tc.token, tc.body); rr.Code != tc.want {
+			t.Fatalf("%s: status = %d, want %d; body=%s", tc.name, rr.Code, tc.want, rr.Body.String())
+		}
+	}
+
+	entries, err := store.ListActivity(ctx, "", 0)
+	if err != nil {
+		t.Fatalf("ListActivity() error: %v", err)
+	}
+	last := entries[len(entries)-1]
+	if last.Actor != "api:admin-bot" {
+		t.Fatalf("last activity actor = %q, want api:admin-bot", last.Actor)
+	}
+}
+
+func TestRateLimitIsPerToken(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	cfg, err := appconfig.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	cfg.RateLimit = 2
+	if err := appconfig.Save(cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	a, err := store.CreateAPIToken(ctx, "a", sqlite.RoleRead)
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	b, err := store.CreateAPIToken(ctx, "b", sqlite.RoleRead)
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+
+	h := NewHandler()
+	get := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/issues", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr.Code
+	}
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		if got := get(a); got != want {
+			t.Fatalf("request %d with token a: status = %d, want %d", i+1, got, want)
+		}
+	}
+	if got := get(b); got != http.StatusOK {
+		t.Fatalf("token b should have its own budget, got %d", got)
+	}
+
+	// Guessing tokens spends the client's budget, whatever the guess.
+	for i, want := range []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests} {
+		if got := get(fmt.Sprintf("trk_guess%d", i)); got != want {
+			t.Fatalf("guess %d: status = %d, want %d", i+1, got, want)
+		}
+	}
+	if got := get(b); got != http.StatusTooManyRequests {
+		t.Fatalf("a client over its budget is limited before its token is checked, got %d", got)
+	}
+}
+
+func TestEveryRouteChecksRoles(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	for range 2 {
+		if _, err := store.CreateIssue(ctx, issue.Item{Title: "t", Status: issue.StatusTodo, Priority: "p2"}); err != nil {
+			t.Fatalf("create issue: %v", err)
+		}
+	}
+	tokens := map[string]string{}
+	for _, role := range sqlite.Roles {
+		secret, err := store.CreateAPIToken(ctx, role+"-bot", role)
+		if err != nil {
+			t.Fatalf("create token: %v", err)
+		}
+		tokens[role] = secret
+	}
+
+	// One request per route and method in routeRoles.
+	requests := []struct{ route, method, path, body string }{
+		{"/metrics", http.MethodGet, "/metrics", ""},
+		{"/issues", http.MethodGet, "/issues", ""},
+		{"/issues", http.MethodPost, "/issues", `{"title":"new"}`},
+		{"/issues:count", http.MethodGet, "/issues:count", ""},
+		{"/issues/{id}", http.MethodGet, "/issues/TRK-1", ""},
+		{"/issues/{id}", http.MethodPatch, "/issues/TRK-1", `{"priority":"p1"}`},
+		{"/issues/{id}/reorder", http.MethodPost, "/issues/TRK-2/reorder", `{"before":"TRK-1"}`},
+		{"/issues/{id}/comments", http.MethodGet, "/issues/TRK-1/comments", ""},
+		{"/issues/{id}/comments", http.MethodPost, "/issues/TRK-1/comments", `{"body":"hi"}`},
+		{"/issues/{id}/sections/{name}", http.MethodGet, "/issues/TRK-1/sections/spec", ""},
+		{"/issues/{id}/sections/{name}", http.MethodPatch, "/issues/TRK-1/sections/spec", `{"content":"x"}`},
+		{"/next", http.MethodGet, "/next", ""},
+	}
+	covered := map[string]bool{}
+	h := NewHandler()
+	for _, req := range requests {
+		covered[req.route+" "+req.method] = true
+		for _, role := range sqlite.Roles {
+			httpReq := httptest.NewRequest(req.method, req.path, strings.NewReader(req.body))
+			httpReq.Header.Set("Authorization", "Bearer "+tokens[role])
+			httpReq.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httpReq)
+			allowed := sqlite.APIToken{Role: role}.HasRole(routeRoles[req.route][req.method])
+			if got := rr.Code == http.StatusForbidden; got == allowed {
+				t.Fatalf("%s %s as %s: status = %d, allowed = %v; body=%s", req.method, req.path, role, rr.Code, allowed, rr.Body.String())
+			}
+		}
+	}
+	for route, methods := range routeRoles {
+		for method := range methods {
+			if !covered[route+" "+method] {
+				t.Fatalf("no request covers %s %s", method, route)
+			}
+		}
+	}
+
+	// Methods and routes outside the table need an admin token.
+	for _, tc := range []struct{ method, path string }{
+		{http.MethodDelete, "/issues/TRK-1"},
+		{http.MethodPost, "/issues/TRK-1/unknown"},
+	} {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		req.Header.Set("Authorization", "Bearer "+tokens[sqlite.RoleContributor])
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("%s %s as contributor: status = %d, want 403", tc.method, tc.path, rr.Code)
+		}
+	}
+
+	// The create endpoint takes no status, so contributors cannot create
+	// archived or done issues through it.
+	req := httptest.NewRequest(http.MethodPost, "/issues", strings.NewReader(`{"title":"sneaky","status":"archived"}`))
+	req.Header.Set("Authorization", "Bearer "+tokens[sqlite.RoleContributor])
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated || !strings.Contains(rr.Body.String(), `"status":"todo"`) {
+		t.Fatalf("create with status as contributor: status = %d, body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestRouteRolesCoverOpenAPI(t *testing.T) {
+	var doc struct {
+		Paths map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(openAPISpec, &doc); err != nil {
+		t.Fatalf("decode openapi: %v", err)
+	}
+	for path, ops := range doc.Paths {
+		if publicPaths[path] || strings.HasPrefix(path, "/share/") {
+			continue
+		}
+		for method := range ops {
+			if method == "parameters" {
+				continue
+			}
+			if _, ok := routeRoles[path][strings.ToUpper(method)]; !ok {
+				t.Fatalf("routeRoles has no role for %s %s", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
@@ -5,23 +5,38 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/httpmw"
 	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/query"
+	"github.com/myuon/track/internal/service"
 	"github.com/myuon/track/internal/store/sqlite"
 )
 
 type patchIssueRequest struct {
-	Title      *string `json:"title"`
-	Body       *string `json:"body"`
-	Status     *string `json:"status"`
-	Priority   *string `json:"priority"`
-	Assignee   *string `json:"assignee"`
-	Due        *string `json:"due"`
-	NextAction *string `json:"next_action"`
+	Title       *string   `json:"title"`
+	Body        *string   `json:"body"`
+	AppendBody  *string   `json:"append_body"`
+	PrependBody *string   `json:"prepend_body"`
+	Status      *string   `json:"status"`
+	Priority    *string   `json:"priority"`
+	Assignee    *string   `json:"assignee"`
+	Due         *string   `json:"due"`
+	NextAction  *string   `json:"next_action"`
+	Labels      *[]string `json:"labels"`
+	Project     *string   `json:"project"`
+}
+
+type reorderIssueRequest struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
 }
 
 type issueResponse struct {
@@ -33,6 +48,7 @@ type issueResponse struct {
 	Due        string   `json:"due"`
 	Labels     []string `json:"labels"`
 	NextAction string   `json:"next_action"`
+	Project    string   `json:"project,omitempty"`
 	Body       string   `json:"body"`
 	CreatedAt  string   `json:"created_at"`
 	UpdatedAt  string   `json:"updated_at"`
@@ -41,9 +57,63 @@ type issueResponse struct {
 func NewHandler() http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/healthz", healthzHandler)
+	mux.HandleFunc("/readyz", readyzHandler)
+	mux.HandleFunc("/metrics", metricsHandler)
 	mux.HandleFunc("/issues", issuesHandler)
+	mux.HandleFunc("/issues:count", countIssuesHandler)
 	mux.HandleFunc("/issues/", issueDetailHandler)
-	return mux
+	mux.HandleFunc("/next", nextHandler)
+	mux.HandleFunc("/openapi.json", openAPIHandler)
+	mux.HandleFunc("/share/", newShareHandler())
+	mux.HandleFunc("/webhooks/github", githubWebhookHandler)
+
+	// Requests count against their client's budget before authentication,
+	// so guessing tokens is limited too; authenticated ones then move to
+	// their token's budget.
+	limiter := httpmw.NewRateLimiter(configuredRateLimit())
+	return httpmw.Log(httpmw.Recover(httpmw.Limit(limiter, clientRateLimitKey, withAuth(limitTokens(limiter, mux)))))
+}
+
+// configuredRateLimit reads rate_limit from the config; an unreadable config
+// leaves the server unlimited.
+func configuredRateLimit() int {
+	cfg, err := appconfig.Load()
+	if err != nil {
+		return 0
+	}
+	return cfg.RateLimit
+}
+
+func clientRateLimitKey(r *http.Request) string {
+	return "ip:" + httpmw.ClientIP(r)
+}
+
+// limitTokens gives each token its own budget: the request is refunded to
+// its client's budget and charged to its token's instead.
+func limitTokens(l *httpmw.RateLimiter, next http.Handler) http.Handler {
+	perToken := httpmw.Limit(l, func(r *http.Request) string {
+		tok, _ := requestToken(r)
+		return "token:" + tok.Name
+	}, next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := requestToken(r); !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		l.Refund(clientRateLimitKey(r))
+		perToken.ServeHTTP(w, r)
+	})
+}
+
+// apiContext is the context for one request's store calls. Changes made
+// through the API are attributed to "api", or "api:<token name>" when the
+// request carried a token.
+func apiContext(r *http.Request) context.Context {
+	actor := "api"
+	if tok, ok := requestToken(r); ok {
+		actor += ":" + tok.Name
+	}
+	return sqlite.WithActor(context.Background(), actor)
 }
 
 func healthzHandler(w http.ResponseWriter, r *http.Request) {
@@ -51,45 +121,109 @@ func healthzHandler(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"ok": false, "error": err.Error()})
+		return
+	}
+	defer store.Close()
+
+	version, err := store.SchemaVersion(ctx)
+	if err == nil {
+		err = store.Ping(ctx)
+	}
+	if err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"ok": false, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"ok": true, "schema_version": version})
 }
 
-func issuesHandler(w http.ResponseWriter, r *http.Request) {
+// readyzHandler reports ready once the database is migrated to the schema
+// this build expects. A database migrated by a newer build is not ready.
+func readyzHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"ready": false, "error": err.Error()})
+		return
+	}
+	defer store.Close()
 
-	statuses, err := parseStatusesQuery(r.URL.Query().Get("status"))
+	version, err := store.SchemaVersion(ctx)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"ready": false, "error": err.Error()})
 		return
 	}
+	resp := map[string]any{"ready": version == sqlite.SchemaVersion, "schema_version": version, "want_schema_version": sqlite.SchemaVersion}
+	if version != sqlite.SchemaVersion {
+		writeJSON(w, http.StatusServiceUnavailable, resp)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
 
+// metricsHandler reports the database file sizes and the periodic WAL
+// checkpoints in the Prometheus text format.
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
 	ctx := context.Background()
 	store, err := sqlite.Open(ctx)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "internal error")
+		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	defer store.Close()
+	dbSize, walSize, err := sqlite.FileSizes()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	done, busy, failed := sqlite.CheckpointCounts()
 
-	for _, st := range statuses {
-		if err := store.ValidateStatus(ctx, st); err != nil {
-			writeError(w, http.StatusBadRequest, err.Error())
-			return
-		}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HELP track_db_size_bytes Size of the database file.\n# TYPE track_db_size_bytes gauge\ntrack_db_size_bytes %d\n", dbSize)
+	fmt.Fprintf(&b, "# HELP track_db_wal_size_bytes Size of the database write-ahead log.\n# TYPE track_db_wal_size_bytes gauge\ntrack_db_wal_size_bytes %d\n", walSize)
+	b.WriteString("# HELP track_wal_checkpoints_total Periodic WAL checkpoints by result.\n# TYPE track_wal_checkpoints_total counter\n")
+	fmt.Fprintf(&b, "track_wal_checkpoints_total{result=\"ok\"} %d\ntrack_wal_checkpoints_total{result=\"busy\"} %d\ntrack_wal_checkpoints_total{result=\"error\"} %d\n", done, busy, failed)
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = io.WriteString(w, b.String())
+}
+
+func issuesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		listIssuesHandler(w, r)
+	case http.MethodPost:
+		createIssueHandler(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
 	}
+}
 
-	items, err := store.ListIssues(ctx, sqlite.ListFilter{
-		Statuses:        statuses,
-		ExcludeDone:     len(statuses) == 0,
-		ExcludeArchived: len(statuses) == 0,
-		Label:           strings.TrimSpace(r.URL.Query().Get("label")),
-		Assignee:        issue.NormalizeAssignee(r.URL.Query().Get("assignee")),
-		Search:          strings.TrimSpace(r.URL.Query().Get("search")),
-		Sort:            strings.TrimSpace(r.URL.Query().Get("sort")),
-	})
+func listIssuesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := apiContext(r)
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	defer store.Close()
+
+	filter, err := listFilterFromRequest(ctx, store, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	items, err := store.ListIssues(ctx, filter)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "internal error")
 		return
@@ -101,8 +235,169 @@ func issuesHandler(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string][]issueResponse{"items": issues})
 }
 
+func countIssuesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	ctx := apiContext(r)
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	defer store.Close()
+
+	filter, err := listFilterFromRequest(ctx, store, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	count, err := store.CountIssues(ctx, filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"count": count})
+}
+
+// listFilterFromRequest builds a list filter from the status, label,
+// assignee, search, and sort parameters, narrowed by the q query. Without
+// statuses, done and archived issues are excluded.
+func listFilterFromRequest(ctx context.Context, store *sqlite.Store, r *http.Request) (sqlite.ListFilter, error) {
+	params := r.URL.Query()
+	statuses, err := parseStatusesQuery(params.Get("status"))
+	if err != nil {
+		return sqlite.ListFilter{}, err
+	}
+	filter := sqlite.ListFilter{
+		Statuses: statuses,
+		Label:    strings.TrimSpace(params.Get("label")),
+		Assignee: issue.NormalizeAssignee(params.Get("assignee")),
+		Search:   strings.TrimSpace(params.Get("search")),
+		Sort:     strings.TrimSpace(params.Get("sort")),
+	}
+	if q := strings.TrimSpace(params.Get("q")); q != "" {
+		if err := query.Apply(q, &filter); err != nil {
+			return sqlite.ListFilter{}, err
+		}
+	}
+	if filter.Label != "" {
+		if _, err := issue.ParseLabelExpr(filter.Label); err != nil {
+			return sqlite.ListFilter{}, err
+		}
+	}
+	if err := sqlite.ValidateSort(filter.Sort); err != nil {
+		return sqlite.ListFilter{}, err
+	}
+	for _, st := range append(slices.Clone(filter.Statuses), filter.ExcludeStatuses...) {
+		if err := store.ValidateStatus(ctx, st); err != nil {
+			return sqlite.ListFilter{}, err
+		}
+	}
+	filter.ExcludeDone = len(filter.Statuses) == 0
+	filter.ExcludeArchived = len(filter.Statuses) == 0
+	return filter, nil
+}
+
+type createIssueRequest struct {
+	Title      string   `json:"title"`
+	Body       string   `json:"body"`
+	Priority   string   `json:"priority"`
+	Assignee   string   `json:"assignee"`
+	Due        string   `json:"due"`
+	Labels     []string `json:"labels"`
+	NextAction string   `json:"next_action"`
+}
+
+// createIssueHandler creates a todo issue. A request repeated with the same
+// Idempotency-Key header within 24h returns the original issue with 200 and
+// an Idempotent-Replayed header instead of creating another.
+func createIssueHandler(w http.ResponseWriter, r *http.Request) {
+	var req createIssueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json body")
+		return
+	}
+	if err := issue.ValidateTitle(req.Title); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Priority == "" {
+		req.Priority = "none"
+	}
+	if err := issue.ValidatePriority(req.Priority); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := issue.ValidateDue(req.Due); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	for _, label := range req.Labels {
+		if strings.TrimSpace(label) == "" {
+			writeError(w, http.StatusBadRequest, "label must not be empty")
+			return
+		}
+	}
+
+	ctx := apiContext(r)
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	defer store.Close()
+
+	created, replayed, err := service.CreateIssueIdempotent(ctx, store, r.Header.Get("Idempotency-Key"), issue.Item{
+		Title:      req.Title,
+		Body:       req.Body,
+		Status:     issue.StatusTodo,
+		Priority:   req.Priority,
+		Assignee:   issue.NormalizeAssignee(req.Assignee),
+		Due:        req.Due,
+		Labels:     req.Labels,
+		NextAction: strings.TrimSpace(req.NextAction),
+	}, "")
+	if err != nil {
+		writeMutationError(w, err)
+		return
+	}
+	if replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+		writeJSON(w, http.StatusOK, toIssueResponse(created))
+		return
+	}
+	writeJSON(w, http.StatusCreated, toIssueResponse(created))
+}
+
+type patchSectionRequest struct {
+	Content *string `json:"content"`
+	Append  bool    `json:"append"`
+}
+
+type sectionResponse struct {
+	Name    string `json:"name"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	Present bool   `json:"present"`
+}
+
 func issueDetailHandler(w http.ResponseWriter, r *http.Request) {
 	id := strings.TrimPrefix(r.URL.Path, "/issues/")
+	if issueID, name, ok := strings.Cut(id, "/sections/"); ok && issueID != "" && name != "" && !strings.Contains(issueID, "/") && !strings.Contains(name, "/") {
+		issueSectionHandler(w, r, normalizeIssueIDArg(issueID), name)
+		return
+	}
+	if issueID, ok := strings.CutSuffix(id, "/reorder"); ok && issueID != "" && !strings.Contains(issueID, "/") {
+		reorderIssueHandler(w, r, normalizeIssueIDArg(issueID))
+		return
+	}
+	if issueID, ok := strings.CutSuffix(id, "/comments"); ok && issueID != "" && !strings.Contains(issueID, "/") {
+		issueCommentsHandler(w, r, normalizeIssueIDArg(issueID))
+		return
+	}
 	if id == "" || strings.Contains(id, "/") {
 		writeError(w, http.StatusNotFound, "issue not found")
 		return
@@ -119,8 +414,8 @@ func issueDetailHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func getIssueHandler(w http.ResponseWriter, _ *http.Request, id string) {
-	ctx := context.Background()
+func getIssueHandler(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := apiContext(r)
 	store, err := sqlite.Open(ctx)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "internal error")
@@ -137,7 +432,18 @@ func getIssueHandler(w http.ResponseWriter, _ *http.Request, id string) {
 		writeError(w, http.StatusInternalServerError, "internal error")
 		return
 	}
-	writeJSON(w, http.StatusOK, toIssueResponse(item))
+	writeIssueWithProject(ctx, w, store, item)
+}
+
+func writeIssueWithProject(ctx context.Context, w http.ResponseWriter, store *sqlite.Store, item issue.Item) {
+	project, err := store.GetIssueProject(ctx, item.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	resp := toIssueResponse(item)
+	resp.Project = project
+	writeJSON(w, http.StatusOK, resp)
 }
 
 func patchIssueHandler(w http.ResponseWriter, r *http.Request, id string) {
@@ -152,12 +458,12 @@ func patchIssueHandler(w http.ResponseWriter, r *http.Request, id string) {
 		writeError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
-	if req.Title == nil && req.Body == nil && req.Status == nil && req.Priority == nil && req.Assignee == nil && req.Due == nil && req.NextAction == nil {
+	if req.Title == nil && req.Body == nil && req.AppendBody == nil && req.PrependBody == nil && req.Status == nil && req.Priority == nil && req.Assignee == nil && req.Due == nil && req.NextAction == nil && req.Labels == nil && req.Project == nil {
 		writeError(w, http.StatusBadRequest, "no fields to update")
 		return
 	}
 
-	ctx := context.Background()
+	ctx := apiContext(r)
 	store, err := sqlite.Open(ctx)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "internal error")
@@ -165,25 +471,254 @@ func patchIssueHandler(w http.ResponseWriter, r *http.Request, id string) {
 	}
 	defer store.Close()
 
-	updated, err := store.UpdateIssue(ctx, id, sqlite.UpdateIssueInput{
-		Title:      req.Title,
-		Body:       req.Body,
-		Status:     req.Status,
-		Priority:   req.Priority,
-		Assignee:   req.Assignee,
-		Due:        req.Due,
-		NextAction: req.NextAction,
+	if req.Status != nil {
+		current, err := store.GetIssue(ctx, id)
+		if err != nil {
+			writeMutationError(w, err)
+			return
+		}
+		if !checkStatusChange(r, current.Status, *req.Status) {
+			writeError(w, http.StatusForbidden, "contributor tokens cannot move issues from "+current.Status+" to "+*req.Status)
+			return
+		}
+	}
+
+	updated, err := service.UpdateIssue(ctx, store, id, service.Update{
+		UpdateIssueInput: sqlite.UpdateIssueInput{
+			Title:       req.Title,
+			Body:        req.Body,
+			AppendBody:  req.AppendBody,
+			PrependBody: req.PrependBody,
+			Status:      req.Status,
+			Priority:    req.Priority,
+			Assignee:    req.Assignee,
+			Due:         req.Due,
+			NextAction:  req.NextAction,
+		},
+		Labels:  req.Labels,
+		Project: req.Project,
 	})
 	if err != nil {
-		switch {
-		case isNotFoundErr(err):
+		writeMutationError(w, err)
+		return
+	}
+	writeIssueWithProject(ctx, w, store, updated)
+}
+
+func reorderIssueHandler(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var req reorderIssueRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req.Before = strings.TrimSpace(req.Before)
+	req.After = strings.TrimSpace(req.After)
+	if (req.Before == "") == (req.After == "") {
+		writeError(w, http.StatusBadRequest, "specify either before or after")
+		return
+	}
+	if req.Before != "" {
+		req.Before = normalizeIssueIDArg(req.Before)
+	}
+	if req.After != "" {
+		req.After = normalizeIssueIDArg(req.After)
+	}
+
+	ctx := apiContext(r)
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	defer store.Close()
+
+	if err := service.Reorder(ctx, store, id, req.Before, req.After); err != nil {
+		writeMutationError(w, err)
+		return
+	}
+	item, err := store.GetIssue(ctx, id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	writeJSON(w, http.StatusOK, toIssueResponse(item))
+}
+
+type commentRequest struct {
+	Body string `json:"body"`
+}
+
+type commentResponse struct {
+	ID        int    `json:"id"`
+	IssueID   string `json:"issue_id"`
+	Author    string `json:"author"`
+	Body      string `json:"body"`
+	CreatedAt string `json:"created_at"`
+}
+
+func toCommentResponse(c sqlite.Comment) commentResponse {
+	return commentResponse{ID: c.ID, IssueID: c.IssueID, Author: c.Author, Body: c.Body, CreatedAt: c.CreatedAt}
+}
+
+// issueCommentsHandler lists an issue's comments, oldest first, or adds one
+// attributed to the request's actor.
+func issueCommentsHandler(w http.ResponseWriter, r *http.Request, id string) {
+	var req commentRequest
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		dec := json.NewDecoder(r.Body)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		if strings.TrimSpace(req.Body) == "" {
+			writeError(w, http.StatusBadRequest, "body is required")
+			return
+		}
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	ctx := apiContext(r)
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	defer store.Close()
+
+	if r.Method == http.MethodPost {
+		c, err := store.AddComment(ctx, id, req.Body)
+		if err != nil {
+			if isNotFoundErr(err) {
+				writeError(w, http.StatusNotFound, "issue not found")
+				return
+			}
+			writeError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+		writeJSON(w, http.StatusCreated, toCommentResponse(c))
+		return
+	}
+
+	if _, err := store.GetIssue(ctx, id); err != nil {
+		if isNotFoundErr(err) {
+			writeError(w, http.StatusNotFound, "issue not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	comments, err := store.ListComments(ctx, id, 0)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	resp := make([]commentResponse, 0, len(comments))
+	for _, c := range comments {
+		resp = append(resp, toCommentResponse(c))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func nextHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	ctx := apiContext(r)
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	defer store.Close()
+
+	next, ok, err := store.NextIssue(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if !ok {
+		writeJSON(w, http.StatusOK, map[string]any{"item": nil})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]issueResponse{"item": toIssueResponse(next)})
+}
+
+func issueSectionHandler(w http.ResponseWriter, r *http.Request, id, name string) {
+	title, err := issue.ResolveSectionName(name)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	var req patchSectionRequest
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPatch:
+		dec := json.NewDecoder(r.Body)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		if err := dec.Decode(&struct{}{}); err != io.EOF {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		if req.Content == nil {
+			writeError(w, http.StatusBadRequest, "content is required")
+			return
+		}
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	ctx := apiContext(r)
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	defer store.Close()
+
+	if r.Method == http.MethodPatch {
+		if _, err := service.SetSection(ctx, store, id, title, *req.Content, req.Append); err != nil {
+			var hookErr *service.HookError
+			switch {
+			case isNotFoundErr(err):
+				writeError(w, http.StatusNotFound, "issue not found")
+			case errors.As(err, &hookErr):
+				writeError(w, http.StatusInternalServerError, err.Error())
+			default:
+				writeError(w, http.StatusInternalServerError, "internal error")
+			}
+			return
+		}
+	}
+
+	content, present, err := store.GetIssueSection(ctx, id, title)
+	if err != nil {
+		if isNotFoundErr(err) {
 			writeError(w, http.StatusNotFound, "issue not found")
-		default:
-			writeError(w, http.StatusBadRequest, err.Error())
+			return
 		}
+		writeError(w, http.StatusInternalServerError, "internal error")
 		return
 	}
-	writeJSON(w, http.StatusOK, toIssueResponse(updated))
+	writeJSON(w, http.StatusOK, sectionResponse{Name: name, Title: title, Content: content, Present: present})
 }
 
 func normalizeIssueIDArg(raw string) string {
@@ -240,6 +775,23 @@ func toIssueResponse(it issue.Item) issueResponse {
 	}
 }
 
+// writeMutationError maps service errors: unknown issues are 404, failed
+// hooks (the change itself was saved) are 500, and anything else, including
+// an unknown reorder reference, is treated as a validation error.
+func writeMutationError(w http.ResponseWriter, err error) {
+	var hookErr *service.HookError
+	switch {
+	case errors.As(err, &hookErr):
+		writeError(w, http.StatusInternalServerError, err.Error())
+	case errors.Is(err, service.ErrIdempotencyKeyReused):
+		writeError(w, http.StatusConflict, err.Error())
+	case isNotFoundErr(err) && !strings.HasPrefix(err.Error(), "reference issue"):
+		writeError(w, http.StatusNotFound, "issue not found")
+	default:
+		writeError(w, http.StatusBadRequest, err.Error())
+	}
+}
+
 func isNotFoundErr(err error) bool {
 	if err == nil {
 		return false
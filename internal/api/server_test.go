@@ -3,13 +3,20 @@ package api
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/share"
 	"github.com/myuon/track/internal/store/sqlite"
 )
 
@@ -25,11 +32,68 @@ func TestHealthz(t *testing.T) {
 		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
 	}
 	assertJSONContentType(t, rr)
-	if strings.TrimSpace(rr.Body.String()) != `{"ok":true}` {
+	if want := fmt.Sprintf(`{"ok":true,"schema_version":%d}`, sqlite.SchemaVersion); strings.TrimSpace(rr.Body.String()) != want {
 		t.Fatalf("unexpected body: %q", rr.Body.String())
 	}
 }
 
+func TestMetricsReportsDatabaseSizes(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	h := NewHandler()
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body=%s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{"\ntrack_db_size_bytes ", "\ntrack_db_wal_size_bytes ", `track_wal_checkpoints_total{result="busy"} `} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("metrics missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "track_db_size_bytes 0\n") {
+		t.Fatalf("database size should not be zero:\n%s", body)
+	}
+}
+
+func TestReadyzGatesOnSchemaVersion(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	h := NewHandler()
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body=%s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	want := fmt.Sprintf(`{"ready":true,"schema_version":%d,"want_schema_version":%[1]d}`, sqlite.SchemaVersion)
+	if got := strings.TrimSpace(rr.Body.String()); got != want {
+		t.Fatalf("unexpected body: %q", got)
+	}
+
+	path, err := sqlite.DBPath()
+	if err != nil {
+		t.Fatalf("db path: %v", err)
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE meta SET value = '99' WHERE key = 'schema_version'`); err != nil {
+		t.Fatalf("bump schema version: %v", err)
+	}
+	_ = db.Close()
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status with a newer schema = %d, want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+}
+
 func TestIssuesListDefaultExcludesDoneAndArchived(t *testing.T) {
 	t.Setenv("TRACK_HOME", t.TempDir())
 	ctx := context.Background()
@@ -67,6 +131,110 @@ func TestIssuesListDefaultExcludesDoneAndArchived(t *testing.T) {
 	}
 }
 
+func TestIssuesListLabelExpressionAndQuery(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	for _, labels := range [][]string{{"bug"}, {"bug", "wontfix"}} {
+		if _, err := store.CreateIssue(ctx, issue.Item{Title: "t", Status: issue.StatusTodo, Priority: "p2", Labels: labels}); err != nil {
+			t.Fatalf("create issue: %v", err)
+		}
+	}
+
+	h := NewHandler()
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/issues?label="+url.QueryEscape("bug AND NOT wontfix"), nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body=%s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	var resp struct {
+		Items []issueResponse `json:"items"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].ID != "TRK-1" {
+		t.Fatalf("unexpected items: %+v", resp.Items)
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/issues?q="+url.QueryEscape("label:bug -label:wontfix"), nil))
+	resp.Items = nil
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("q status = %d, err %v", rr.Code, err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].ID != "TRK-1" {
+		t.Fatalf("unexpected q items: %+v", resp.Items)
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/issues?q="+url.QueryEscape("colour:red"), nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("invalid q status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/issues?sort=bogus", nil))
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "invalid sort key") {
+		t.Fatalf("invalid sort status = %d, body=%s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/issues?label="+url.QueryEscape("(bug"), nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("invalid expression status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestCountIssues(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	for _, labels := range [][]string{{"bug"}, {"bug"}, {"ui"}} {
+		if _, err := store.CreateIssue(ctx, issue.Item{Title: "t", Status: issue.StatusTodo, Priority: "p2", Labels: labels}); err != nil {
+			t.Fatalf("create issue: %v", err)
+		}
+	}
+
+	h := NewHandler()
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/issues:count?label=bug", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body=%s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	var resp struct {
+		Count int `json:"count"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Count != 2 {
+		t.Fatalf("count = %d, want 2", resp.Count)
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/issues:count?q="+url.QueryEscape("colour:red"), nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("invalid q status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/issues:count", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST status = %d, want %d", rr.Code, http.StatusMethodNotAllowed)
+	}
+}
+
 func TestGetIssueAcceptsTRKAndNumericID(t *testing.T) {
 	t.Setenv("TRACK_HOME", t.TempDir())
 	ctx := context.Background()
@@ -134,6 +302,57 @@ func TestPatchIssueSuccess(t *testing.T) {
 	}
 }
 
+func TestCreateIssueWithIdempotencyKey(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	h := NewHandler()
+
+	post := func(key, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/issues", bytes.NewBufferString(body))
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	body := `{"title":"From webhook","priority":"p1","labels":["gh"]}`
+	rr := post("delivery-7", body)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d; body=%s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+	assertJSONContentType(t, rr)
+	var created issueResponse
+	if err := json.NewDecoder(rr.Body).Decode(&created); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if created.Status != issue.StatusTodo || created.Priority != "p1" || len(created.Labels) != 1 {
+		t.Fatalf("unexpected created issue: %+v", created)
+	}
+
+	rr = post("delivery-7", body)
+	if rr.Code != http.StatusOK || rr.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("replay status = %d, replayed = %q; body=%s", rr.Code, rr.Header().Get("Idempotent-Replayed"), rr.Body.String())
+	}
+	var replayed issueResponse
+	if err := json.NewDecoder(rr.Body).Decode(&replayed); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if replayed.ID != created.ID {
+		t.Fatalf("replay returned %s, want %s", replayed.ID, created.ID)
+	}
+
+	if rr := post("delivery-7", `{"title":"Something else"}`); rr.Code != http.StatusConflict {
+		t.Fatalf("reused key status = %d, want %d", rr.Code, http.StatusConflict)
+	}
+	if rr := post("", `{"title":""}`); rr.Code != http.StatusBadRequest {
+		t.Fatalf("empty title status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+	if rr := post("", body); rr.Code != http.StatusCreated {
+		t.Fatalf("keyless status = %d, want %d", rr.Code, http.StatusCreated)
+	}
+}
+
 func TestPatchIssueValidationError(t *testing.T) {
 	t.Setenv("TRACK_HOME", t.TempDir())
 	ctx := context.Background()
@@ -159,6 +378,289 @@ func TestPatchIssueValidationError(t *testing.T) {
 	}
 }
 
+func TestPatchIssueLabelsAndProject(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	if _, err := store.CreateProject(ctx, "web", "Web", ""); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	it := mustCreateIssue(t, ctx, store, "labels", issue.StatusTodo, "p2")
+	h := NewHandler()
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPatch, "/issues/"+it.ID, bytes.NewBufferString(`{"labels":["bug","api","bug"],"project":"web"}`))
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body=%s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	var got issueResponse
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if strings.Join(got.Labels, ",") != "bug,api" || got.Project != "web" {
+		t.Fatalf("unexpected labels/project: %+v", got)
+	}
+
+	rr = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPatch, "/issues/"+it.ID, bytes.NewBufferString(`{"title":"renamed","project":"missing"}`))
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "project not found") {
+		t.Fatalf("unknown project: status = %d, body=%s", rr.Code, rr.Body.String())
+	}
+	unchanged, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if unchanged.Title != "labels" {
+		t.Fatalf("title should be unchanged after rejected patch, got %q", unchanged.Title)
+	}
+
+	rr = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPatch, "/issues/"+it.ID, bytes.NewBufferString(`{"labels":[],"project":"none"}`))
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body=%s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	got = issueResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(got.Labels) != 0 || got.Project != "" {
+		t.Fatalf("labels/project should be cleared: %+v", got)
+	}
+
+	rr = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPatch, "/issues/"+it.ID, bytes.NewBufferString(`{"labels":[" "]}`))
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("empty label: status = %d, body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestReorderAndNext(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	h := NewHandler()
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/next", nil))
+	if rr.Code != http.StatusOK || strings.TrimSpace(rr.Body.String()) != `{"item":null}` {
+		t.Fatalf("empty next: status = %d, body=%s", rr.Code, rr.Body.String())
+	}
+
+	a := mustCreateIssue(t, ctx, store, "a", issue.StatusTodo, "p2")
+	b := mustCreateIssue(t, ctx, store, "b", issue.StatusReady, "p2")
+	mustCreateIssue(t, ctx, store, "done", issue.StatusDone, "p0")
+
+	rr = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/issues/"+b.ID+"/reorder", bytes.NewBufferString(`{"before":"`+a.ID+`"}`))
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("reorder status = %d, body=%s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/next", nil))
+	var resp struct {
+		Item issueResponse `json:"item"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Item.ID != b.ID {
+		t.Fatalf("next = %s, want %s", resp.Item.ID, b.ID)
+	}
+
+	cases := []struct {
+		path string
+		body string
+		want int
+	}{
+		{"/issues/" + a.ID + "/reorder", `{}`, http.StatusBadRequest},
+		{"/issues/" + a.ID + "/reorder", `{"before":"TRK-999"}`, http.StatusBadRequest},
+		{"/issues/TRK-999/reorder", `{"after":"` + a.ID + `"}`, http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		rr = httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, tc.path, bytes.NewBufferString(tc.body)))
+		if rr.Code != tc.want {
+			t.Fatalf("%s %s: status = %d, want %d; body=%s", tc.path, tc.body, rr.Code, tc.want, rr.Body.String())
+		}
+	}
+}
+
+func TestIssueComments(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it := mustCreateIssue(t, ctx, store, "discuss", issue.StatusTodo, "p2")
+	h := NewHandler()
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/issues/"+it.ID+"/comments", bytes.NewBufferString(`{"body":"looks good"}`)))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("post status = %d, body=%s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/issues/"+it.ID+"/comments", nil))
+	var comments []commentResponse
+	if err := json.NewDecoder(rr.Body).Decode(&comments); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(comments) != 1 || comments[0].Body != "looks good" || comments[0].Author != "api" || comments[0].IssueID != it.ID {
+		t.Fatalf("comments = %+v", comments)
+	}
+
+	cases := []struct {
+		method, path, body string
+		want               int
+	}{
+		{http.MethodPost, "/issues/" + it.ID + "/comments", `{"body":" "}`, http.StatusBadRequest},
+		{http.MethodPost, "/issues/" + it.ID + "/comments", `{"text":"x"}`, http.StatusBadRequest},
+		{http.MethodPost, "/issues/TRK-999/comments", `{"body":"x"}`, http.StatusNotFound},
+		{http.MethodGet, "/issues/TRK-999/comments", ``, http.StatusNotFound},
+		{http.MethodDelete, "/issues/" + it.ID + "/comments", ``, http.StatusMethodNotAllowed},
+	}
+	for _, tc := range cases {
+		rr = httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.body)))
+		if rr.Code != tc.want {
+			t.Fatalf("%s %s %s: status = %d, want %d; body=%s", tc.method, tc.path, tc.body, rr.Code, tc.want, rr.Body.String())
+		}
+	}
+}
+
+func TestPatchIssueAppendBody(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it := mustCreateIssue(t, ctx, store, "append", issue.StatusTodo, "p2")
+	h := NewHandler()
+	for _, payload := range []string{`{"append_body":"- ran nightly"}`, `{"prepend_body":"# Log"}`} {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodPatch, "/issues/"+it.ID, bytes.NewBufferString(payload)))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body=%s", payload, rr.Code, rr.Body.String())
+		}
+	}
+	got, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if got.Body != "# Log\n- ran nightly" {
+		t.Fatalf("body = %q", got.Body)
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodPatch, "/issues/"+it.ID, bytes.NewBufferString(`{"body":"x","append_body":"y"}`)))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("body with append_body: status = %d, body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestPatchIssueFiresStatusHooks(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	outFile := filepath.Join(tmp, "hook.out")
+	if err := store.AddHook(ctx, "issue.status_changed", "/bin/sh -c 'echo $TRACK_EVENT:$TRACK_ISSUE_ID >> "+outFile+"'", ""); err != nil {
+		t.Fatalf("add hook: %v", err)
+	}
+	it := mustCreateIssue(t, ctx, store, "hooked", issue.StatusTodo, "p2")
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPatch, "/issues/"+it.ID, bytes.NewBufferString(`{"status":"ready"}`))
+	NewHandler().ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, body=%s", rr.Code, rr.Body.String())
+	}
+	raw, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("read hook output: %v", err)
+	}
+	if strings.TrimSpace(string(raw)) != "issue.status_changed:"+it.ID {
+		t.Fatalf("unexpected hook output: %q", raw)
+	}
+}
+
+func TestOpenAPIDocumentsAllRoutes(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	rr := httptest.NewRecorder()
+	NewHandler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	assertJSONContentType(t, rr)
+
+	var doc struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&doc); err != nil {
+		t.Fatalf("decode openapi: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Fatalf("openapi version = %q", doc.OpenAPI)
+	}
+	want := map[string][]string{
+		"/healthz":                     {"get"},
+		"/readyz":                      {"get"},
+		"/metrics":                     {"get"},
+		"/openapi.json":                {"get"},
+		"/issues":                      {"get", "post"},
+		"/issues:count":                {"get"},
+		"/issues/{id}":                 {"get", "patch"},
+		"/issues/{id}/reorder":         {"post"},
+		"/issues/{id}/comments":        {"get", "post"},
+		"/issues/{id}/sections/{name}": {"get", "patch"},
+		"/next":                        {"get"},
+		"/share/{token}":               {"get"},
+		"/webhooks/github":             {"post"},
+	}
+	for path, methods := range want {
+		ops, ok := doc.Paths[path]
+		if !ok {
+			t.Fatalf("openapi is missing path %s", path)
+		}
+		for _, m := range methods {
+			if _, ok := ops[m]; !ok {
+				t.Fatalf("openapi is missing %s %s", m, path)
+			}
+		}
+	}
+	if len(doc.Paths) != len(want) {
+		t.Fatalf("openapi documents %d paths, want %d", len(doc.Paths), len(want))
+	}
+}
+
 func TestNotFoundAndMethodNotAllowed(t *testing.T) {
 	t.Setenv("TRACK_HOME", t.TempDir())
 	h := NewHandler()
@@ -180,6 +682,91 @@ func TestNotFoundAndMethodNotAllowed(t *testing.T) {
 	assertJSONContentType(t, rr)
 }
 
+func TestPatchIssueSection(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it := mustCreateIssue(t, ctx, store, "sections", issue.StatusTodo, "p2")
+	h := NewHandler()
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPatch, "/issues/"+it.ID+"/sections/spec", bytes.NewBufferString(`{"content":"build it"}`))
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body=%s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	assertJSONContentType(t, rr)
+
+	rr = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPatch, "/issues/"+it.ID+"/sections/notes", bytes.NewBufferString(`{"content":"- first"}`))
+	h.ServeHTTP(rr, req)
+	rr = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPatch, "/issues/"+it.ID+"/sections/notes", bytes.NewBufferString(`{"content":"- second","append":true}`))
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("append status = %d; body=%s", rr.Code, rr.Body.String())
+	}
+	var got sectionResponse
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got.Title != issue.SectionNotes || got.Content != "- first\n- second" || !got.Present {
+		t.Fatalf("unexpected section response: %+v", got)
+	}
+
+	updated, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if updated.Body != "## Spec\nbuild it\n\n## Notes\n- first\n- second\n" {
+		t.Fatalf("unexpected body: %q", updated.Body)
+	}
+
+	rr = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/issues/"+it.ID+"/sections/spec", nil)
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"content":"build it"`) {
+		t.Fatalf("get section = %d %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestPatchIssueSectionErrors(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it := mustCreateIssue(t, ctx, store, "sections", issue.StatusTodo, "p2")
+	h := NewHandler()
+
+	cases := []struct {
+		path string
+		body string
+		want int
+	}{
+		{path: "/issues/" + it.ID + "/sections/unknown", body: `{"content":"x"}`, want: http.StatusNotFound},
+		{path: "/issues/" + it.ID + "/sections/spec", body: `{}`, want: http.StatusBadRequest},
+		{path: "/issues/TRK-9999/sections/spec", body: `{"content":"x"}`, want: http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPatch, tc.path, bytes.NewBufferString(tc.body))
+		h.ServeHTTP(rr, req)
+		if rr.Code != tc.want {
+			t.Fatalf("%s status = %d, want %d; body=%s", tc.path, rr.Code, tc.want, rr.Body.String())
+		}
+		assertJSONContentType(t, rr)
+	}
+}
+
 func mustCreateIssue(t *testing.T, ctx context.Context, store *sqlite.Store, title, status, priority string) issue.Item {
 	t.Helper()
 	it, err := store.CreateIssue(ctx, issue.Item{Title: title, Status: status, Priority: priority})
@@ -196,3 +783,54 @@ func assertJSONContentType(t *testing.T, rr *httptest.ResponseRecorder) {
 		t.Fatalf("content-type = %q, want %q", got, "application/json; charset=utf-8")
 	}
 }
+
+func TestShareLinkServesReadOnlyIssue(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "Shared <plan>", Status: issue.StatusTodo, Priority: "p1", Body: "## Spec\nship it"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	if _, err := store.CreateAPIToken(ctx, "ci", sqlite.RoleRead); err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	secret, err := store.ShareSecret(ctx)
+	if err != nil {
+		t.Fatalf("share secret: %v", err)
+	}
+
+	h := NewHandler()
+	get := func(token string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/share/"+token, nil))
+		return rr
+	}
+
+	rr := get(share.Sign(secret, it.ID, time.Now().Add(time.Hour)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("share status = %d: %s", rr.Code, rr.Body.String())
+	}
+	if body := rr.Body.String(); !strings.Contains(body, "Shared &lt;plan&gt;") || !strings.Contains(body, "ship it") {
+		t.Fatalf("share page should render the issue: %s", body)
+	}
+	if rr := get(share.Sign(secret, it.ID, time.Now().Add(-time.Minute))); rr.Code != http.StatusGone {
+		t.Fatalf("expired link status = %d", rr.Code)
+	}
+	if rr := get(share.Sign([]byte("forged"), it.ID, time.Now().Add(time.Hour))); rr.Code != http.StatusNotFound {
+		t.Fatalf("forged link status = %d", rr.Code)
+	}
+
+	if err := store.RotateShareSecret(ctx); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if rr := get(share.Sign(secret, it.ID, time.Now().Add(time.Hour))); rr.Code != http.StatusNotFound {
+		t.Fatalf("link signed with the old secret status = %d", rr.Code)
+	}
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/myuon/track/internal/share"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+var shareTpl = template.Must(template.New("share").Parse(`<!doctype html><html><head><meta charset="utf-8"><title>{{.ID}} {{.Title}}</title></head><body><h1>{{.ID}} {{.Title}}</h1><p>Status: {{.Status}} &middot; Priority: {{.Priority}}{{if .Due}} &middot; Due: {{.Due}}{{end}}</p>{{if .Labels}}<p>Labels: {{range $i, $l := .Labels}}{{if $i}}, {{end}}{{$l}}{{end}}</p>{{end}}{{if .Body}}<pre style="white-space: pre-wrap">{{.Body}}</pre>{{end}}<p><small>Read-only view shared from track. Updated {{.UpdatedAt}}.</small></p></body></html>`))
+
+// newShareHandler serves the read-only page behind a `track share` link. It
+// needs no API token; the signed link is the credential. Anyone holding a
+// link can load it as often as they like, so the handler keeps one store
+// for the server's lifetime, whose read cache holds the share secret until
+// the database changes, rather than opening one per request.
+func newShareHandler() http.HandlerFunc {
+	var mu sync.Mutex
+	var shared *sqlite.Store
+	openStore := func(ctx context.Context) (*sqlite.Store, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if shared == nil {
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return nil, err
+			}
+			shared = store
+		}
+		return shared, nil
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		token := strings.TrimPrefix(r.URL.Path, "/share/")
+		ctx := context.Background()
+		store, err := openStore(ctx)
+		if err != nil {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		serveShare(ctx, w, r, store, token)
+	}
+}
+
+func serveShare(ctx context.Context, w http.ResponseWriter, r *http.Request, store *sqlite.Store, token string) {
+	secret, err := store.ShareSecret(ctx)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	id, err := share.Verify(secret, token, time.Now())
+	if errors.Is(err, share.ErrExpired) {
+		http.Error(w, "this link has expired", http.StatusGone)
+		return
+	}
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	it, err := store.GetIssue(ctx, id)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = shareTpl.Execute(w, it)
+}
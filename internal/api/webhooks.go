@@ -0,0 +1,230 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/secret"
+	"github.com/myuon/track/internal/service"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+// maxWebhookBody caps the payload read from GitHub, which documents 25 MB
+// as its own limit.
+const maxWebhookBody = 25 << 20
+
+type githubWebhookPayload struct {
+	Action     string `json:"action"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	PullRequest struct {
+		Number int  `json:"number"`
+		Merged bool `json:"merged"`
+	} `json:"pull_request"`
+	Issue struct {
+		Number int `json:"number"`
+	} `json:"issue"`
+	CheckRun struct {
+		Name         string `json:"name"`
+		Conclusion   string `json:"conclusion"`
+		HTMLURL      string `json:"html_url"`
+		PullRequests []struct {
+			Number int `json:"number"`
+		} `json:"pull_requests"`
+	} `json:"check_run"`
+}
+
+// githubWebhookHandler receives GitHub webhooks, so linked issues follow
+// their PRs without polling `track gh watch`. Deliveries must be signed with
+// gh_webhook_secret; the signature replaces the API token. A merged PR marks
+// its linked issues done, closing or reopening a GitHub issue moves the
+// track issue created from it, and a failed check run is recorded in the
+// activity feed of the issues linked to its PRs.
+func githubWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	cfg, err := appconfig.Load()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if cfg.GHWebhookSecret == "" {
+		writeError(w, http.StatusNotFound, "github webhooks are disabled (set gh_webhook_secret)")
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "read body: "+err.Error())
+		return
+	}
+	webhookSecret, err := secret.Expand(cfg.GHWebhookSecret)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if !validGitHubSignature(webhookSecret, body, r.Header.Get("X-Hub-Signature-256")) {
+		writeError(w, http.StatusUnauthorized, "invalid signature")
+		return
+	}
+
+	event := r.Header.Get("X-GitHub-Event")
+	var payload githubWebhookPayload
+	if event != "ping" {
+		if err := json.Unmarshal(body, &payload); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid payload: "+err.Error())
+			return
+		}
+	}
+
+	ctx := sqlite.WithActor(context.Background(), "github")
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	defer store.Close()
+
+	// 503 marks the delivery failed, so it can be redelivered after thawing.
+	if f, frozen, err := store.AutomationFrozen(ctx); err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	} else if frozen {
+		writeError(w, http.StatusServiceUnavailable, f.Error())
+		return
+	}
+	var updated []string
+	switch event {
+	case "pull_request":
+		updated, err = handlePullRequestEvent(ctx, store, payload)
+	case "issues":
+		updated, err = handleIssuesEvent(ctx, store, payload)
+	case "check_run":
+		updated, err = handleCheckRunEvent(ctx, store, payload)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if updated == nil {
+		updated = []string{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"event": event, "updated": updated})
+}
+
+// validGitHubSignature checks the "sha256=<hex>" HMAC GitHub sends in
+// X-Hub-Signature-256.
+func validGitHubSignature(secret string, body []byte, header string) bool {
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+func handlePullRequestEvent(ctx context.Context, store *sqlite.Store, p githubWebhookPayload) ([]string, error) {
+	if p.Action != "closed" || !p.PullRequest.Merged {
+		return nil, nil
+	}
+	links, err := store.GitHubLinksForPR(ctx, p.Repository.FullName, strconv.Itoa(p.PullRequest.Number))
+	if err != nil {
+		return nil, err
+	}
+	var updated []string
+	for _, link := range links {
+		current, err := store.GetIssue(ctx, link.IssueID)
+		if err != nil {
+			return nil, err
+		}
+		if current.Status == issue.StatusDone {
+			continue
+		}
+		if err := store.RecordActivity(ctx, sqlite.ActivityPRMerged, link.IssueID, link.PRRef); err != nil {
+			return nil, err
+		}
+		if _, err := service.SetStatus(ctx, store, link.IssueID, issue.StatusDone); err != nil {
+			return nil, err
+		}
+		updated = append(updated, link.IssueID)
+	}
+	return updated, nil
+}
+
+func handleIssuesEvent(ctx context.Context, store *sqlite.Store, p githubWebhookPayload) ([]string, error) {
+	var status string
+	switch p.Action {
+	case "closed":
+		status = issue.StatusDone
+	case "reopened":
+		status = issue.StatusTodo
+	default:
+		return nil, nil
+	}
+	links, err := store.GitHubIssueLinksFor(ctx, p.Repository.FullName, strconv.Itoa(p.Issue.Number))
+	if err != nil {
+		return nil, err
+	}
+	var updated []string
+	for _, link := range links {
+		current, err := store.GetIssue(ctx, link.IssueID)
+		if err != nil {
+			return nil, err
+		}
+		if current.Status == status || (status == issue.StatusTodo && current.Status != issue.StatusDone) {
+			continue
+		}
+		if _, err := service.SetStatus(ctx, store, link.IssueID, status); err != nil {
+			return nil, err
+		}
+		updated = append(updated, link.IssueID)
+	}
+	return updated, nil
+}
+
+func handleCheckRunEvent(ctx context.Context, store *sqlite.Store, p githubWebhookPayload) ([]string, error) {
+	run := p.CheckRun
+	if p.Action != "completed" || !isFailedConclusion(run.Conclusion) {
+		return nil, nil
+	}
+	var updated []string
+	for _, pr := range run.PullRequests {
+		links, err := store.GitHubLinksForPR(ctx, p.Repository.FullName, strconv.Itoa(pr.Number))
+		if err != nil {
+			return nil, err
+		}
+		for _, link := range links {
+			detail := fmt.Sprintf("%s (%s) %s", run.Name, run.Conclusion, run.HTMLURL)
+			if err := store.RecordActivity(ctx, sqlite.ActivityCheckFailed, link.IssueID, strings.TrimSpace(detail)); err != nil {
+				return nil, err
+			}
+			updated = append(updated, link.IssueID)
+		}
+	}
+	return updated, nil
+}
+
+func isFailedConclusion(c string) bool {
+	switch c {
+	case "failure", "timed_out", "cancelled", "action_required", "startup_failure":
+		return true
+	}
+	return false
+}
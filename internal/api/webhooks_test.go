@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/secret"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestGitHubWebhook(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	cfg, err := appconfig.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	cfg.GHWebhookSecret = "s3cret"
+	if err := appconfig.Save(cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	pr, err := store.CreateIssue(ctx, issue.Item{Title: "Ship it", Status: issue.StatusInProgress, Priority: "none"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	if err := store.UpsertGitHubLink(ctx, pr.ID, "7", "owner/repo"); err != nil {
+		t.Fatalf("link pr: %v", err)
+	}
+	gh, err := store.CreateIssue(ctx, issue.Item{Title: "Reported", Status: issue.StatusTodo, Priority: "none"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	if err := store.UpsertGitHubIssueLink(ctx, gh.ID, "42", "https://github.com/owner/repo/issues/42", "owner/repo"); err != nil {
+		t.Fatalf("link issue: %v", err)
+	}
+	// Tokens do not apply: the signature is the credential.
+	if _, err := store.CreateAPIToken(ctx, "ci", sqlite.RoleRead); err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+
+	h := NewHandler()
+	deliver := func(event, body, secret string) *httptest.ResponseRecorder {
+		t.Helper()
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(body))
+		req.Header.Set("X-GitHub-Event", event)
+		req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+	status := func(id string) string {
+		t.Helper()
+		it, err := store.GetIssue(ctx, id)
+		if err != nil {
+			t.Fatalf("get issue: %v", err)
+		}
+		return it.Status
+	}
+
+	if rr := deliver("pull_request", `{"action":"closed","repository":{"full_name":"owner/repo"},"pull_request":{"number":7,"merged":true}}`, "forged"); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("forged delivery status = %d", rr.Code)
+	}
+	if rr := deliver("ping", `{"zen":"hi"}`, "s3cret"); rr.Code != http.StatusOK {
+		t.Fatalf("ping status = %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr := deliver("check_run", `{"action":"completed","repository":{"full_name":"owner/repo"},"check_run":{"name":"test","conclusion":"failure","html_url":"https://github.com/owner/repo/runs/1","pull_requests":[{"number":7}]}}`, "s3cret")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("check_run status = %d: %s", rr.Code, rr.Body.String())
+	}
+	acts, err := store.ListIssueActivity(ctx, pr.ID)
+	if err != nil {
+		t.Fatalf("list activity: %v", err)
+	}
+	found := false
+	for _, a := range acts {
+		found = found || (a.Kind == sqlite.ActivityCheckFailed && strings.HasPrefix(a.Detail, "test (failure)"))
+	}
+	if !found {
+		t.Fatalf("failed check should be recorded: %+v", acts)
+	}
+
+	rr = deliver("pull_request", `{"action":"closed","repository":{"full_name":"owner/repo"},"pull_request":{"number":7,"merged":true}}`, "s3cret")
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), pr.ID) {
+		t.Fatalf("pull_request = %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := status(pr.ID); got != issue.StatusDone {
+		t.Fatalf("merged pr status = %s, want done", got)
+	}
+
+	deliver("issues", `{"action":"closed","repository":{"full_name":"owner/repo"},"issue":{"number":42}}`, "s3cret")
+	if got := status(gh.ID); got != issue.StatusDone {
+		t.Fatalf("closed issue status = %s, want done", got)
+	}
+	deliver("issues", `{"action":"reopened","repository":{"full_name":"other/repo"},"issue":{"number":42}}`, "s3cret")
+	if got := status(gh.ID); got != issue.StatusDone {
+		t.Fatalf("another repo's issue should not match, status = %s", got)
+	}
+	deliver("issues", `{"action":"reopened","repository":{"full_name":"owner/repo"},"issue":{"number":42}}`, "s3cret")
+	if got := status(gh.ID); got != issue.StatusTodo {
+		t.Fatalf("reopened issue status = %s, want todo", got)
+	}
+
+	if _, err := store.FreezeAutomation(ctx, ""); err != nil {
+		t.Fatalf("freeze: %v", err)
+	}
+	rr = deliver("issues", `{"action":"closed","repository":{"full_name":"owner/repo"},"issue":{"number":42}}`, "s3cret")
+	if rr.Code != http.StatusServiceUnavailable || status(gh.ID) != issue.StatusTodo {
+		t.Fatalf("frozen delivery = %d, status %s; want 503 and no change", rr.Code, status(gh.ID))
+	}
+}
+
+func TestGitHubWebhookSecretFromSecretStore(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	cfg := appconfig.Default()
+	cfg.SecretStore = secret.StoreFile
+	cfg.GHWebhookSecret = "{{secret:gh_webhook}}"
+	if err := appconfig.Save(cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	if _, err := secret.Set("gh_webhook", "s3cret"); err != nil {
+		t.Fatalf("set secret: %v", err)
+	}
+
+	h := NewHandler()
+	deliver := func(key string) int {
+		t.Helper()
+		body := `{"zen":"hi"}`
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(body))
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(body))
+		req.Header.Set("X-GitHub-Event", "ping")
+		req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr.Code
+	}
+	if code := deliver("s3cret"); code != http.StatusOK {
+		t.Fatalf("delivery signed with the secret's value = %d, want 200", code)
+	}
+	if code := deliver(cfg.GHWebhookSecret); code != http.StatusUnauthorized {
+		t.Fatalf("delivery signed with the reference text = %d, want 401", code)
+	}
+}
@@ -0,0 +1,113 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+const AutoOrganize = "auto-organize"
+
+const defaultAutoOrganizeNextAction = "planning: refine spec and decide ready/user"
+
+// AutoOrganizeSettings fills in assignee and next_action on newly created
+// todo issues that do not have them yet.
+type AutoOrganizeSettings struct {
+	Assignee   string `json:"assignee"`
+	NextAction string `json:"next_action"`
+}
+
+func DefaultAutoOrganizeSettings() AutoOrganizeSettings {
+	return AutoOrganizeSettings{Assignee: "agent", NextAction: defaultAutoOrganizeNextAction}
+}
+
+// Names lists the built-in automations.
+func Names() []string {
+	return []string{AutoOrganize}
+}
+
+func Validate(name string) error {
+	for _, n := range Names() {
+		if n == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown automation: %s", name)
+}
+
+// LoadAutoOrganize returns the stored auto-organize state, falling back to
+// the defaults for settings that were never configured.
+func LoadAutoOrganize(ctx context.Context, store *sqlite.Store) (bool, AutoOrganizeSettings, error) {
+	settings := DefaultAutoOrganizeSettings()
+	a, ok, err := store.GetAutomation(ctx, AutoOrganize)
+	if err != nil || !ok {
+		return false, settings, err
+	}
+	if err := json.Unmarshal([]byte(a.Settings), &settings); err != nil {
+		return false, settings, fmt.Errorf("decode %s settings: %w", AutoOrganize, err)
+	}
+	return a.Enabled, settings, nil
+}
+
+func SaveAutoOrganize(ctx context.Context, store *sqlite.Store, enabled bool, settings AutoOrganizeSettings) error {
+	raw, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("encode %s settings: %w", AutoOrganize, err)
+	}
+	_, err = store.SaveAutomation(ctx, sqlite.Automation{Name: AutoOrganize, Enabled: enabled, Settings: string(raw)})
+	return err
+}
+
+// Apply runs the enabled built-in automations and rules for event and
+// reports whether the issue was changed.
+func Apply(ctx context.Context, store *sqlite.Store, event, issueID string) (bool, error) {
+	if issueID == "" || (event != "issue.created" && event != "issue.updated") {
+		return false, nil
+	}
+	organized := false
+	if event == "issue.created" {
+		var err error
+		if organized, err = applyAutoOrganize(sqlite.WithActor(ctx, "automation:"+AutoOrganize), store, issueID); err != nil {
+			return false, err
+		}
+	}
+	ruled, err := applyRules(sqlite.WithActor(ctx, "automation:rules"), store, issueID)
+	if err != nil {
+		return organized, err
+	}
+	return organized || ruled, nil
+}
+
+func applyAutoOrganize(ctx context.Context, store *sqlite.Store, issueID string) (bool, error) {
+	enabled, settings, err := LoadAutoOrganize(ctx, store)
+	if err != nil || !enabled {
+		return false, err
+	}
+
+	it, err := store.GetIssue(ctx, issueID)
+	if err != nil {
+		return false, err
+	}
+	if it.Status != issue.StatusTodo {
+		return false, nil
+	}
+
+	var in sqlite.UpdateIssueInput
+	if it.Assignee == "" && strings.TrimSpace(settings.Assignee) != "" {
+		in.Assignee = &settings.Assignee
+	}
+	if it.NextAction == "" && strings.TrimSpace(settings.NextAction) != "" {
+		in.NextAction = &settings.NextAction
+	}
+	if in.Assignee == nil && in.NextAction == nil {
+		return false, nil
+	}
+	if _, err := store.UpdateIssue(ctx, issueID, in); err != nil {
+		return false, err
+	}
+	return true, nil
+}
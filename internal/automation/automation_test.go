@@ -0,0 +1,92 @@
+package automation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestApplyAutoOrganizeFillsMissingFields(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	bare, err := store.CreateIssue(ctx, issue.Item{Title: "bare", Status: issue.StatusTodo, Priority: "none"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+
+	changed, err := Apply(ctx, store, "issue.created", bare.ID)
+	if err != nil || changed {
+		t.Fatalf("disabled automation should not change issue: changed=%v err=%v", changed, err)
+	}
+
+	if err := SaveAutoOrganize(ctx, store, true, AutoOrganizeSettings{Assignee: "agent", NextAction: "plan it"}); err != nil {
+		t.Fatalf("save automation: %v", err)
+	}
+
+	withAssignee, err := store.CreateIssue(ctx, issue.Item{Title: "owned", Status: issue.StatusTodo, Priority: "none", Assignee: "user"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	ready, err := store.CreateIssue(ctx, issue.Item{Title: "ready", Status: issue.StatusReady, Priority: "none"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+
+	cases := []struct {
+		id             string
+		event          string
+		wantChanged    bool
+		wantAssignee   string
+		wantNextAction string
+	}{
+		{id: bare.ID, event: "issue.updated", wantChanged: false, wantAssignee: "", wantNextAction: ""},
+		{id: bare.ID, event: "issue.created", wantChanged: true, wantAssignee: "agent", wantNextAction: "plan it"},
+		{id: withAssignee.ID, event: "issue.created", wantChanged: true, wantAssignee: "user", wantNextAction: "plan it"},
+		{id: ready.ID, event: "issue.created", wantChanged: false, wantAssignee: "", wantNextAction: ""},
+	}
+	for _, tc := range cases {
+		changed, err := Apply(ctx, store, tc.event, tc.id)
+		if err != nil {
+			t.Fatalf("Apply(%s, %s) error: %v", tc.event, tc.id, err)
+		}
+		if changed != tc.wantChanged {
+			t.Fatalf("Apply(%s, %s) changed = %v, want %v", tc.event, tc.id, changed, tc.wantChanged)
+		}
+		got, err := store.GetIssue(ctx, tc.id)
+		if err != nil {
+			t.Fatalf("get issue: %v", err)
+		}
+		if got.Assignee != tc.wantAssignee || got.NextAction != tc.wantNextAction {
+			t.Fatalf("Apply(%s, %s) issue = %+v", tc.event, tc.id, got)
+		}
+	}
+}
+
+func TestLoadAutoOrganizeDefaults(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	enabled, settings, err := LoadAutoOrganize(ctx, store)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if enabled || settings != DefaultAutoOrganizeSettings() {
+		t.Fatalf("unexpected defaults: enabled=%v settings=%+v", enabled, settings)
+	}
+	if err := Validate("nope"); err == nil {
+		t.Fatalf("expected unknown automation error")
+	}
+}
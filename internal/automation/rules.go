@@ -0,0 +1,368 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+// maxRulePasses bounds how often rules are re-evaluated after they change an
+// issue, so conflicting rules cannot loop forever.
+const maxRulePasses = 5
+
+var (
+	ruleConditionFields = []string{"status", "priority", "assignee", "label", "due"}
+	ruleActionFields    = []string{"status", "priority", "assignee", "label", "due", "next_action"}
+)
+
+type RuleCondition struct {
+	Field  string
+	Negate bool
+	Value  string
+}
+
+// Assignment strategies for "assignee=<strategy>(a,b,...)" actions.
+const (
+	AssignRoundRobin = "round-robin"
+	AssignLeastBusy  = "least-busy"
+)
+
+// RuleAction sets Field to Value. An assignee value of
+// "round-robin(a,b,...)" or "least-busy(a,b,...)" picks from the pool
+// instead: round-robin takes turns per rule, and least-busy picks whoever has
+// the fewest in_progress issues (ties go to the earlier name).
+type RuleAction struct {
+	Field string
+	Value string
+}
+
+// AssignPool returns the strategy and pool of a pooled assignee action; ok
+// is false for plain actions.
+func (a RuleAction) AssignPool() (strategy string, pool []string, ok bool) {
+	if a.Field != "assignee" {
+		return "", nil, false
+	}
+	for _, strategy := range []string{AssignRoundRobin, AssignLeastBusy} {
+		rest, found := strings.CutPrefix(a.Value, strategy+"(")
+		if !found {
+			continue
+		}
+		rest, found = strings.CutSuffix(rest, ")")
+		if !found {
+			return strategy, nil, true
+		}
+		for _, name := range strings.Split(rest, ",") {
+			if name = issue.NormalizeAssignee(strings.TrimSpace(name)); name != "" {
+				pool = append(pool, name)
+			}
+		}
+		return strategy, pool, true
+	}
+	return "", nil, false
+}
+
+// Rule is a parsed "when <conditions> then set <actions>" automation.
+// Conditions are joined by "and"; actions are comma separated.
+type Rule struct {
+	Conditions []RuleCondition
+	Actions    []RuleAction
+}
+
+// ParseRule parses expressions such as
+// "when label=bug and priority=none then set priority=p1, assignee=agent"
+// or "when label=ci and assignee= then set assignee=round-robin(codex,claude)".
+func ParseRule(expr string) (Rule, error) {
+	text := strings.TrimSpace(expr)
+	lower := strings.ToLower(text)
+	if !strings.HasPrefix(lower, "when ") {
+		return Rule{}, fmt.Errorf("rule must start with \"when\": %s", expr)
+	}
+	idx := strings.Index(lower, " then ")
+	if idx < 0 {
+		return Rule{}, fmt.Errorf("rule must contain \"then\": %s", expr)
+	}
+	condText := strings.TrimSpace(text[len("when "):idx])
+	actionText := strings.TrimSpace(text[idx+len(" then "):])
+	if strings.HasPrefix(strings.ToLower(actionText), "set ") {
+		actionText = strings.TrimSpace(actionText[len("set "):])
+	}
+
+	var rule Rule
+	for _, part := range splitFold(condText, " and ") {
+		cond, err := parseRuleCondition(part)
+		if err != nil {
+			return Rule{}, err
+		}
+		rule.Conditions = append(rule.Conditions, cond)
+	}
+	for _, part := range splitActions(actionText) {
+		action, err := parseRuleAction(part)
+		if err != nil {
+			return Rule{}, err
+		}
+		rule.Actions = append(rule.Actions, action)
+	}
+	return rule, nil
+}
+
+func parseRuleCondition(raw string) (RuleCondition, error) {
+	raw = strings.TrimSpace(raw)
+	negate := false
+	field, value, ok := strings.Cut(raw, "!=")
+	if ok {
+		negate = true
+	} else if field, value, ok = strings.Cut(raw, "="); !ok {
+		return RuleCondition{}, fmt.Errorf("invalid rule condition: %s", raw)
+	}
+	field = strings.ToLower(strings.TrimSpace(field))
+	if !slices.Contains(ruleConditionFields, field) {
+		return RuleCondition{}, fmt.Errorf("unsupported rule condition field: %s", field)
+	}
+	return RuleCondition{Field: field, Negate: negate, Value: trimRuleValue(value)}, nil
+}
+
+func parseRuleAction(raw string) (RuleAction, error) {
+	raw = strings.TrimSpace(raw)
+	field, value, ok := strings.Cut(raw, "=")
+	if !ok {
+		return RuleAction{}, fmt.Errorf("invalid rule action: %s", raw)
+	}
+	field = strings.ToLower(strings.TrimSpace(field))
+	if !slices.Contains(ruleActionFields, field) {
+		return RuleAction{}, fmt.Errorf("unsupported rule action field: %s", field)
+	}
+	value = trimRuleValue(value)
+	switch field {
+	case "priority":
+		if err := issue.ValidatePriority(value); err != nil {
+			return RuleAction{}, err
+		}
+	case "due":
+		if err := issue.ValidateDue(value); err != nil {
+			return RuleAction{}, err
+		}
+	case "label":
+		if value == "" {
+			return RuleAction{}, fmt.Errorf("rule label action needs a value")
+		}
+	}
+	action := RuleAction{Field: field, Value: value}
+	if strategy, pool, ok := action.AssignPool(); ok && len(pool) == 0 {
+		return RuleAction{}, fmt.Errorf("%s needs a list of assignees, e.g. %s(alice,bob)", strategy, strategy)
+	}
+	return action, nil
+}
+
+// splitActions splits on commas outside parentheses, so assignee pools stay
+// in one action.
+func splitActions(s string) []string {
+	var (
+		out   []string
+		depth int
+		start int
+	)
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				out = append(out, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(out, s[start:])
+}
+
+func trimRuleValue(v string) string {
+	return strings.Trim(strings.TrimSpace(v), `"'`)
+}
+
+func splitFold(s, sep string) []string {
+	out := make([]string, 0)
+	lower := strings.ToLower(s)
+	for {
+		idx := strings.Index(lower, sep)
+		if idx < 0 {
+			return append(out, s)
+		}
+		out = append(out, s[:idx])
+		s, lower = s[idx+len(sep):], lower[idx+len(sep):]
+	}
+}
+
+// Matches reports whether every condition holds for it.
+func (r Rule) Matches(it issue.Item) bool {
+	for _, c := range r.Conditions {
+		var ok bool
+		switch c.Field {
+		case "label":
+			ok = slices.Contains(it.Labels, c.Value)
+		default:
+			ok = ruleFieldValue(it, c.Field) == c.Value
+		}
+		if ok == c.Negate {
+			return false
+		}
+	}
+	return true
+}
+
+// Pending returns the actions that would change it.
+func (r Rule) Pending(it issue.Item) []RuleAction {
+	out := make([]RuleAction, 0, len(r.Actions))
+	for _, a := range r.Actions {
+		if a.Field == "label" {
+			if !slices.Contains(it.Labels, a.Value) {
+				out = append(out, a)
+			}
+			continue
+		}
+		if _, pool, ok := a.AssignPool(); ok {
+			if !slices.Contains(pool, it.Assignee) {
+				out = append(out, a)
+			}
+			continue
+		}
+		if ruleFieldValue(it, a.Field) != a.Value {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func ruleFieldValue(it issue.Item, field string) string {
+	switch field {
+	case "status":
+		return it.Status
+	case "priority":
+		return it.Priority
+	case "assignee":
+		return it.Assignee
+	case "due":
+		return it.Due
+	case "next_action":
+		return it.NextAction
+	}
+	return ""
+}
+
+func FormatActions(actions []RuleAction) string {
+	parts := make([]string, 0, len(actions))
+	for _, a := range actions {
+		parts = append(parts, a.Field+"="+a.Value)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// applyRules evaluates enabled rules against the issue until nothing changes
+// (or maxRulePasses is reached) and reports whether anything was updated.
+func applyRules(ctx context.Context, store *sqlite.Store, issueID string) (bool, error) {
+	stored, err := store.ListRules(ctx)
+	if err != nil {
+		return false, err
+	}
+	rules := make([]Rule, 0, len(stored))
+	ids := make([]int, 0, len(stored))
+	for _, sr := range stored {
+		if !sr.Enabled {
+			continue
+		}
+		rule, err := ParseRule(sr.Expr)
+		if err != nil {
+			return false, fmt.Errorf("rule(%d): %w", sr.ID, err)
+		}
+		rules = append(rules, rule)
+		ids = append(ids, sr.ID)
+	}
+	if len(rules) == 0 {
+		return false, nil
+	}
+
+	changed := false
+	for pass := 0; pass < maxRulePasses; pass++ {
+		passChanged := false
+		for i, rule := range rules {
+			it, err := store.GetIssue(ctx, issueID)
+			if err != nil {
+				return changed, err
+			}
+			if !rule.Matches(it) {
+				continue
+			}
+			pending := rule.Pending(it)
+			if len(pending) == 0 {
+				continue
+			}
+			if err := applyRuleActions(ctx, store, ids[i], issueID, pending); err != nil {
+				return changed, err
+			}
+			changed, passChanged = true, true
+		}
+		if !passChanged {
+			break
+		}
+	}
+	return changed, nil
+}
+
+func applyRuleActions(ctx context.Context, store *sqlite.Store, ruleID int, issueID string, actions []RuleAction) error {
+	var in sqlite.UpdateIssueInput
+	hasUpdate := false
+	for _, a := range actions {
+		v := a.Value
+		if strategy, pool, ok := a.AssignPool(); ok {
+			var err error
+			if v, err = pickAssignee(ctx, store, ruleID, strategy, pool); err != nil {
+				return err
+			}
+		}
+		switch a.Field {
+		case "label":
+			if _, err := store.AddLabel(ctx, issueID, v); err != nil {
+				return err
+			}
+			continue
+		case "status":
+			in.Status = &v
+		case "priority":
+			in.Priority = &v
+		case "assignee":
+			in.Assignee = &v
+		case "due":
+			in.Due = &v
+		case "next_action":
+			in.NextAction = &v
+		}
+		hasUpdate = true
+	}
+	if !hasUpdate {
+		return nil
+	}
+	_, err := store.UpdateIssue(ctx, issueID, in)
+	return err
+}
+
+func pickAssignee(ctx context.Context, store *sqlite.Store, ruleID int, strategy string, pool []string) (string, error) {
+	if strategy == AssignRoundRobin {
+		return store.NextRuleAssignee(ctx, ruleID, pool)
+	}
+	counts, err := store.CountIssuesByAssignee(ctx, issue.StatusInProgress)
+	if err != nil {
+		return "", err
+	}
+	best := pool[0]
+	for _, name := range pool[1:] {
+		if counts[name] < counts[best] {
+			best = name
+		}
+	}
+	return best, nil
+}
@@ -0,0 +1,143 @@
+package automation
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestParseRule(t *testing.T) {
+	rule, err := ParseRule("when label=bug and priority!=p0 then set priority=p1, assignee=agent")
+	if err != nil {
+		t.Fatalf("ParseRule() error: %v", err)
+	}
+	wantConds := []RuleCondition{{Field: "label", Value: "bug"}, {Field: "priority", Negate: true, Value: "p0"}}
+	if !slices.Equal(rule.Conditions, wantConds) {
+		t.Fatalf("conditions = %+v", rule.Conditions)
+	}
+	wantActions := []RuleAction{{Field: "priority", Value: "p1"}, {Field: "assignee", Value: "agent"}}
+	if !slices.Equal(rule.Actions, wantActions) {
+		t.Fatalf("actions = %+v", rule.Actions)
+	}
+
+	invalid := []string{
+		"label=bug then set priority=p1",
+		"when label=bug set priority=p1",
+		"when title=x then set priority=p1",
+		"when label=bug then set priority=p9",
+		"when label=bug then set body=x",
+	}
+	for _, expr := range invalid {
+		if _, err := ParseRule(expr); err == nil {
+			t.Fatalf("ParseRule(%q) should fail", expr)
+		}
+	}
+}
+
+func TestRuleMatchesAndPending(t *testing.T) {
+	rule, err := ParseRule("when label=bug and priority=none then set priority=p1, label=triaged")
+	if err != nil {
+		t.Fatalf("ParseRule() error: %v", err)
+	}
+	it := issue.Item{Priority: "none", Labels: []string{"bug"}}
+	if !rule.Matches(it) {
+		t.Fatalf("rule should match %+v", it)
+	}
+	if got := FormatActions(rule.Pending(it)); got != "priority=p1, label=triaged" {
+		t.Fatalf("pending = %q", got)
+	}
+	if rule.Matches(issue.Item{Priority: "p2", Labels: []string{"bug"}}) {
+		t.Fatalf("rule should not match other priority")
+	}
+}
+
+func TestApplyRulesOnUpdate(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	if _, err := store.AddRule(ctx, "when label=bug and priority=none then set priority=p1"); err != nil {
+		t.Fatalf("add rule: %v", err)
+	}
+	if _, err := store.AddRule(ctx, "when priority=p1 then set next_action=fix first"); err != nil {
+		t.Fatalf("add rule: %v", err)
+	}
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "crash", Status: issue.StatusTodo, Priority: "none", Labels: []string{"bug"}})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+
+	changed, err := Apply(ctx, store, "issue.updated", it.ID)
+	if err != nil || !changed {
+		t.Fatalf("Apply() changed=%v err=%v", changed, err)
+	}
+	got, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if got.Priority != "p1" || got.NextAction != "fix first" {
+		t.Fatalf("rules should chain: %+v", got)
+	}
+
+	changed, err = Apply(ctx, store, "issue.updated", it.ID)
+	if err != nil || changed {
+		t.Fatalf("second Apply() changed=%v err=%v", changed, err)
+	}
+}
+
+func TestApplyRulesAssignsFromPool(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	if _, err := ParseRule("when label=ci then set assignee=round-robin()"); err == nil {
+		t.Fatalf("empty pool should be rejected")
+	}
+	if _, err := store.AddRule(ctx, "when label=ci and assignee= then set assignee=round-robin(codex, claude), label=triaged"); err != nil {
+		t.Fatalf("add rule: %v", err)
+	}
+	if _, err := store.AddRule(ctx, "when label=ops and assignee= then set assignee=least-busy(alice,bob)"); err != nil {
+		t.Fatalf("add rule: %v", err)
+	}
+
+	assign := func(label string) string {
+		t.Helper()
+		it, err := store.CreateIssue(ctx, issue.Item{Title: label, Status: issue.StatusTodo, Priority: "none", Labels: []string{label}})
+		if err != nil {
+			t.Fatalf("create issue: %v", err)
+		}
+		if _, err := Apply(ctx, store, "issue.created", it.ID); err != nil {
+			t.Fatalf("Apply() error: %v", err)
+		}
+		got, err := store.GetIssue(ctx, it.ID)
+		if err != nil {
+			t.Fatalf("get issue: %v", err)
+		}
+		return got.Assignee
+	}
+	var turns []string
+	for range 3 {
+		turns = append(turns, assign("ci"))
+	}
+	if !slices.Equal(turns, []string{"codex", "claude", "codex"}) {
+		t.Fatalf("round-robin assignees = %v", turns)
+	}
+
+	if _, err := store.CreateIssue(ctx, issue.Item{Title: "busy", Status: issue.StatusInProgress, Priority: "none", Assignee: "alice"}); err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	if got := assign("ops"); got != "bob" {
+		t.Fatalf("least-busy assignee = %q, want bob", got)
+	}
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/myuon/track/internal/automation"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func newAutomationCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "automation",
+		Short: "Manage built-in automations",
+	}
+	cmd.AddCommand(newAutomationListCmd())
+	cmd.AddCommand(newAutomationEnableCmd())
+	cmd.AddCommand(newAutomationDisableCmd())
+	return cmd
+}
+
+func newAutomationListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List built-in automations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			enabled, settings, err := automation.LoadAutoOrganize(ctx, store)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\tassignee=%s\tnext_action=%s\n", automation.AutoOrganize, enabledLabel(enabled), settings.Assignee, settings.NextAction)
+			return nil
+		},
+	}
+}
+
+func newAutomationEnableCmd() *cobra.Command {
+	var assignee string
+	var nextAction string
+
+	cmd := &cobra.Command{
+		Use:   "enable <name>",
+		Short: "Enable a built-in automation",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := automation.Validate(args[0]); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			_, settings, err := automation.LoadAutoOrganize(ctx, store)
+			if err != nil {
+				return err
+			}
+			if cmd.Flags().Changed("assignee") {
+				settings.Assignee = assignee
+			}
+			if cmd.Flags().Changed("next-action") {
+				settings.NextAction = nextAction
+			}
+			if err := automation.SaveAutoOrganize(ctx, store, true, settings); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&assignee, "assignee", "", "Assignee to set when missing (auto-organize)")
+	cmd.Flags().StringVar(&nextAction, "next-action", "", "Next action to set when missing (auto-organize)")
+	return cmd
+}
+
+func newAutomationDisableCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "disable <name>",
+		Short: "Disable a built-in automation",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := automation.Validate(args[0]); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			_, settings, err := automation.LoadAutoOrganize(ctx, store)
+			if err != nil {
+				return err
+			}
+			if err := automation.SaveAutoOrganize(ctx, store, false, settings); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+}
+
+func enabledLabel(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestAutomationEnableAutoOrganizesNewIssues(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	enable := newAutomationCmd()
+	enable.SetOut(&bytes.Buffer{})
+	enable.SetArgs([]string{"enable", "auto-organize", "--next-action", "triage"})
+	if err := enable.Execute(); err != nil {
+		t.Fatalf("automation enable error: %v", err)
+	}
+
+	create := newNewCmd()
+	var createOut bytes.Buffer
+	create.SetOut(&createOut)
+	create.SetArgs([]string{"organized"})
+	if err := create.Execute(); err != nil {
+		t.Fatalf("new error: %v", err)
+	}
+	id := strings.TrimSpace(createOut.String())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	got, err := store.GetIssue(ctx, id)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if got.Assignee != "agent" || got.NextAction != "triage" {
+		t.Fatalf("issue should be organized: %+v", got)
+	}
+
+	list := newAutomationCmd()
+	var listOut bytes.Buffer
+	list.SetOut(&listOut)
+	list.SetArgs([]string{"list"})
+	if err := list.Execute(); err != nil {
+		t.Fatalf("automation list error: %v", err)
+	}
+	if listOut.String() != "auto-organize\tenabled\tassignee=agent\tnext_action=triage\n" {
+		t.Fatalf("unexpected list output: %q", listOut.String())
+	}
+
+	disable := newAutomationCmd()
+	disable.SetOut(&bytes.Buffer{})
+	disable.SetArgs([]string{"disable", "auto-organize"})
+	if err := disable.Execute(); err != nil {
+		t.Fatalf("automation disable error: %v", err)
+	}
+
+	create = newNewCmd()
+	createOut.Reset()
+	create.SetOut(&createOut)
+	create.SetArgs([]string{"untouched"})
+	if err := create.Execute(); err != nil {
+		t.Fatalf("new error: %v", err)
+	}
+	untouched, err := store.GetIssue(ctx, strings.TrimSpace(createOut.String()))
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if untouched.Assignee != "" || untouched.NextAction != "" {
+		t.Fatalf("disabled automation should not organize: %+v", untouched)
+	}
+}
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+// autopilotStopFile is created in the track home to stop a running
+// autopilot after its current issue.
+const autopilotStopFile = "autopilot.stop"
+
+// autopilotLimits bound an autopilot run. Zero max or budget means no limit.
+type autopilotLimits struct {
+	max      int
+	budget   time.Duration
+	stopFile string
+}
+
+func newAutopilotCmd() *cobra.Command {
+	var (
+		opts     dispatchOptions
+		limits   autopilotLimits
+		stopFile string
+	)
+	cmd := &cobra.Command{
+		Use:   "autopilot",
+		Short: "Dispatch ready issues one after another",
+		Long: `Repeatedly pick the next ready issue whose blockers are merged, dispatch it
+(worktree, runner, PR, CI, merge, done), and move on. The run stops after
+--max issues, once --budget has passed, when a dispatch fails, when no
+unblocked ready issue is left, when track freeze pauses automation, or when
+the stop file exists; limits are checked between issues, so the issue in
+flight always finishes:
+
+  track autopilot --max 3 --budget 2h
+  touch ~/.track/autopilot.stop   # stop after the current issue`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateDispatchOptions(opts); err != nil {
+				return err
+			}
+			if limits.max < 0 || limits.budget < 0 {
+				return fmt.Errorf("--max and --budget must not be negative")
+			}
+			limits.stopFile = stopFile
+			if limits.stopFile == "" {
+				home, err := appconfig.HomeDir()
+				if err != nil {
+					return err
+				}
+				limits.stopFile = filepath.Join(home, autopilotStopFile)
+			}
+			if _, err := os.Stat(limits.stopFile); err == nil {
+				return fmt.Errorf("stop file exists: %s (remove it to start autopilot)", limits.stopFile)
+			}
+
+			ctx := cmd.Context()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if f, frozen, err := store.AutomationFrozen(ctx); err != nil {
+				return err
+			} else if frozen {
+				return f
+			}
+
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			return runAutopilot(ctx, store, cmd.OutOrStdout(), limits, time.Now, func(issueID string) error {
+				return dispatchIssue(cmd, store, cwd, issueID, opts)
+			})
+		},
+	}
+	cmd.Flags().IntVar(&limits.max, "max", 3, "Most issues to dispatch (0 for no limit)")
+	cmd.Flags().DurationVar(&limits.budget, "budget", 0, "Start no new issue after this long (e.g. 2h; 0 for no limit)")
+	cmd.Flags().StringVar(&stopFile, "stop-file", "", "Stop after the current issue once this file exists (default: autopilot.stop in the track home)")
+	cmd.Flags().StringVar(&opts.Runner, "runner", "codex", "Runner (codex|claude); defaults to the assignee's agent profile")
+	cmd.Flags().StringVar(&opts.Mode, "mode", "execution", "Mode (execution|plan)")
+	cmd.Flags().StringVar(&opts.Base, "base", "main", "Base branch")
+	cmd.Flags().StringVar(&opts.MergeMethod, "merge-method", "merge", "Merge method (merge|squash|rebase)")
+	cmd.Flags().BoolVar(&opts.NoMerge, "no-merge", false, "Skip merge after CI success (dependent issues then stay blocked)")
+	return cmd
+}
+
+// runAutopilot dispatches issues until a limit is reached, printing a status
+// line before each issue and after the run.
+func runAutopilot(ctx context.Context, store *sqlite.Store, out io.Writer, limits autopilotLimits, now func() time.Time, dispatch func(issueID string) error) error {
+	start := now()
+	attempted := map[string]bool{}
+	dispatched := 0
+	status := func(format string, args ...any) {
+		count := fmt.Sprint(dispatched)
+		if limits.max > 0 {
+			count += fmt.Sprintf("/%d", limits.max)
+		}
+		elapsed := now().Sub(start).Round(time.Second).String()
+		if limits.budget > 0 {
+			elapsed += " of " + limits.budget.String()
+		}
+		fmt.Fprintf(out, "autopilot [%s done, %s] %s\n", count, elapsed, fmt.Sprintf(format, args...))
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			status("interrupted")
+			return err
+		}
+		if _, err := os.Stat(limits.stopFile); err == nil {
+			status("stop file %s found; stopping", limits.stopFile)
+			return nil
+		}
+		if _, frozen, err := store.AutomationFrozen(ctx); err != nil {
+			return err
+		} else if frozen {
+			status("automation frozen; stopping")
+			return nil
+		}
+		if limits.max > 0 && dispatched >= limits.max {
+			status("reached --max; stopping")
+			return nil
+		}
+		if limits.budget > 0 && now().Sub(start) >= limits.budget {
+			status("budget spent; stopping")
+			return nil
+		}
+		next, ok, err := nextDispatchable(ctx, store, attempted)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			status("no unblocked ready issues; stopping")
+			return nil
+		}
+		attempted[next.ID] = true
+		status("dispatching %s: %s", next.ID, next.Title)
+		if err := dispatch(next.ID); err != nil {
+			status("%s failed; stopping", next.ID)
+			return err
+		}
+		dispatched++
+		after, err := store.GetIssue(ctx, next.ID)
+		if err != nil {
+			return err
+		}
+		status("%s -> %s", next.ID, after.Status)
+	}
+}
+
+// nextDispatchable returns the first ready issue, in dependency order, whose
+// blockers are merged and that this run has not tried yet.
+func nextDispatchable(ctx context.Context, store *sqlite.Store, skip map[string]bool) (issue.Item, bool, error) {
+	ready, err := store.ListIssues(ctx, sqlite.ListFilter{Statuses: []string{issue.StatusReady}, Sort: "priority_manual"})
+	if err != nil {
+		return issue.Item{}, false, err
+	}
+	deps, err := store.ListIssueDependencies(ctx)
+	if err != nil {
+		return issue.Item{}, false, err
+	}
+	for _, it := range orderByDependencies(ready, deps) {
+		if skip[it.ID] {
+			continue
+		}
+		blockers, err := store.UnmergedBlockers(ctx, it.ID)
+		if err != nil {
+			return issue.Item{}, false, err
+		}
+		if len(blockers) == 0 {
+			return it, true, nil
+		}
+	}
+	return issue.Item{}, false, nil
+}
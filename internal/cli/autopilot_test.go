@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestRunAutopilotStopsAtLimits(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("TRACK_HOME", home)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	var ids []string
+	for _, title := range []string{"first", "second", "third", "fourth"} {
+		it, err := store.CreateIssue(ctx, issue.Item{Title: title, Status: issue.StatusReady, Priority: "none"})
+		if err != nil {
+			t.Fatalf("CreateIssue() error: %v", err)
+		}
+		ids = append(ids, it.ID)
+	}
+	// second waits for fourth, so fourth goes first.
+	if err := store.AddIssueDependency(ctx, ids[1], ids[3]); err != nil {
+		t.Fatalf("AddIssueDependency() error: %v", err)
+	}
+
+	clock := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	now := func() time.Time { return clock }
+	stopFile := filepath.Join(home, autopilotStopFile)
+	var order []string
+	dispatch := func(id string) error {
+		order = append(order, id)
+		clock = clock.Add(40 * time.Minute)
+		return updateIssueStatus(ctx, store, id, issue.StatusDone)
+	}
+
+	var out bytes.Buffer
+	if err := runAutopilot(ctx, store, &out, autopilotLimits{max: 2, stopFile: stopFile}, now, dispatch); err != nil {
+		t.Fatalf("runAutopilot() error: %v", err)
+	}
+	if want := []string{ids[0], ids[3]}; !slices.Equal(order, want) {
+		t.Fatalf("order = %v, want %v\n%s", order, want, out.String())
+	}
+	if !strings.Contains(out.String(), "autopilot [0/2 done, 0s] dispatching "+ids[0]+": first") ||
+		!strings.Contains(out.String(), "autopilot [2/2 done, 1h20m0s] reached --max; stopping") {
+		t.Fatalf("status lines:\n%s", out.String())
+	}
+
+	out.Reset()
+	order = nil
+	if err := runAutopilot(ctx, store, &out, autopilotLimits{budget: 30 * time.Minute, stopFile: stopFile}, now, dispatch); err != nil {
+		t.Fatalf("runAutopilot() error: %v", err)
+	}
+	if len(order) != 1 || !strings.Contains(out.String(), "budget spent; stopping") {
+		t.Fatalf("budget run dispatched %v:\n%s", order, out.String())
+	}
+
+	if err := os.WriteFile(stopFile, nil, 0o644); err != nil {
+		t.Fatalf("write stop file: %v", err)
+	}
+	out.Reset()
+	order = nil
+	if err := runAutopilot(ctx, store, &out, autopilotLimits{stopFile: stopFile}, now, dispatch); err != nil {
+		t.Fatalf("runAutopilot() error: %v", err)
+	}
+	if len(order) != 0 || !strings.Contains(out.String(), "stop file "+stopFile+" found; stopping") {
+		t.Fatalf("stop file run dispatched %v:\n%s", order, out.String())
+	}
+}
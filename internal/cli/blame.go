@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/myuon/track/internal/timefmt"
+	"github.com/spf13/cobra"
+)
+
+func newBlameCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "blame <id>",
+		Short: "Show who last changed each body section",
+		Long:  "Print each section of an issue body with the actor who last changed it and when, from the activity log. Sections untouched since creation are attributed to whoever created the issue; text before the first heading is shown as " + issue.BodyTop + ".",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			times, err := timefmt.Load()
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			it, err := store.GetIssue(ctx, normalizeIssueIDArg(args[0]))
+			if err != nil {
+				return err
+			}
+			entries, err := store.ListIssueActivity(ctx, it.ID)
+			if err != nil {
+				return err
+			}
+			for _, sec := range issue.BodySections(it.Body) {
+				a, ok := sectionAuthor(entries, sec.Title)
+				if !ok {
+					fmt.Fprintf(cmd.OutOrStdout(), "%s\t-\t-\n", sec.Title)
+					continue
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\n", sec.Title, a.Actor, times.Time(a.CreatedAt))
+			}
+			return nil
+		},
+	}
+}
+
+// sectionAuthor picks the latest entry that changed the titled section,
+// falling back to the issue's creation.
+func sectionAuthor(entries []sqlite.Activity, title string) (sqlite.Activity, bool) {
+	var created sqlite.Activity
+	found := false
+	for i := len(entries) - 1; i >= 0; i-- {
+		a := entries[i]
+		if a.Kind == sqlite.ActivitySection && strings.EqualFold(a.Detail, title) {
+			return a, true
+		}
+		if a.Kind == sqlite.ActivityCreated {
+			created, found = a, true
+		}
+	}
+	return created, found
+}
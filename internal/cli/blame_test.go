@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestBlameAttributesSections(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	t.Setenv(sqlite.ActorEnv, "")
+	cfg := appconfig.Default()
+	cfg.UserName = "jane"
+	if err := appconfig.Save(cfg); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	created, err := store.CreateIssue(ctx, issue.Item{Title: "Add login", Status: issue.StatusTodo, Priority: "p2", Body: "intro\n\n## Spec\ndraft\n\n## Notes\nmine"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	body := "intro\n\n## Spec\nagent spec\n\n## Notes\nmine"
+	if _, err := store.UpdateIssue(sqlite.WithActor(ctx, "agent:codex"), created.ID, sqlite.UpdateIssueInput{Body: &body}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+
+	cmd := newBlameCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{created.ID})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("blame error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := []string{issue.BodyTop + "\tjane\t", "Spec\tagent:codex\t", "Notes\tjane\t"}
+	if len(lines) != len(want) {
+		t.Fatalf("blame should print %d sections, got:\n%s", len(want), out.String())
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(lines[i], w) {
+			t.Fatalf("line %d = %q, want prefix %q", i, lines[i], w)
+		}
+	}
+}
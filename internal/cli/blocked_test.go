@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSetBlockedReason(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := newRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%v: %v\n%s", args, err, out.String())
+		}
+		return out.String()
+	}
+
+	run("status", "add", "blocked")
+	id := strings.TrimSpace(run("new", "deploy"))
+	run("set", id, "--status", "blocked", "--reason", "waiting on infra")
+
+	list := run("list", "--status", "blocked")
+	if !strings.Contains(list, "BLOCKED REASON") {
+		t.Fatalf("list header = %q", list)
+	}
+	if !strings.Contains(list, "0m      waiting on infra") {
+		t.Fatalf("list = %q", list)
+	}
+	if show := run("show", id); !strings.Contains(show, "blocked: 0m (since ") || !strings.Contains(show, "): waiting on infra") {
+		t.Fatalf("show = %q", show)
+	}
+
+	run("set", id, "--status", "in_progress")
+	if show := run("show", id); strings.Contains(show, "blocked:") {
+		t.Fatalf("show after unblocking = %q", show)
+	}
+	report := run("stats", "blocked")
+	if !strings.Contains(report, id) || !strings.Contains(report, "no    waiting on infra") {
+		t.Fatalf("stats blocked = %q", report)
+	}
+}
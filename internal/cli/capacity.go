@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+// focusLabelPrefix starts the label `track capacity --tag` puts on a day's
+// plan, followed by the date: focus:2026-03-01.
+const focusLabelPrefix = "focus:"
+
+func newCapacityCmd() *cobra.Command {
+	var (
+		hours float64
+		tag   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "capacity",
+		Short: "Plan a day's worth of ready issues by estimate",
+		Long:  "Select ready issues in priority order (pinned first, then manual order) whose estimates fit in --hours, and print the plan. Issues without an estimate are skipped; set one with track set --estimate. With --tag, the planned issues get today's focus label (" + focusLabelPrefix + "YYYY-MM-DD in the configured timezone).",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if hours <= 0 {
+				return fmt.Errorf("--hours must be positive")
+			}
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			ready, err := store.ListIssues(ctx, sqlite.ListFilter{Statuses: []string{issue.StatusReady}, Sort: "priority_manual"})
+			if err != nil {
+				return err
+			}
+			budget := time.Duration(hours * float64(time.Hour)).Round(time.Minute)
+			plan, unestimated := planCapacity(ready, budget)
+
+			out := cmd.OutOrStdout()
+			var total time.Duration
+			for _, it := range plan {
+				total += issue.EstimateDuration(it.Estimate)
+				fmt.Fprintf(out, "%s\t%s\t%s\t%s\n", it.ID, it.Priority, it.Estimate, it.Title)
+			}
+			fmt.Fprintf(out, "total: %s of %s\n", issue.FormatEstimate(total), issue.FormatEstimate(budget))
+			if unestimated > 0 {
+				fmt.Fprintf(cmd.ErrOrStderr(), "%d ready issues have no estimate (see track set --estimate)\n", unestimated)
+			}
+
+			if !tag || len(plan) == 0 {
+				return nil
+			}
+			loc, err := appconfig.LoadLocation()
+			if err != nil {
+				return err
+			}
+			label := focusLabelPrefix + time.Now().In(loc).Format(issue.DueDateLayout)
+			batchCtx, flush := sqlite.BatchEvents(ctx)
+			for _, it := range plan {
+				if _, err := store.AddLabel(batchCtx, it.ID, label); err != nil {
+					return err
+				}
+			}
+			if err := flush(); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "tagged %d issues %s\n", len(plan), label)
+			return nil
+		},
+	}
+
+	cmd.Flags().Float64Var(&hours, "hours", 6, "Hours of work to plan")
+	cmd.Flags().BoolVar(&tag, "tag", false, "Label the planned issues with today's focus label")
+	return cmd
+}
+
+// planCapacity takes issues in order while their estimates fit in budget,
+// skipping any that would overflow it so smaller ones later can still fit.
+// It also returns how many issues had no estimate.
+func planCapacity(items []issue.Item, budget time.Duration) ([]issue.Item, int) {
+	var plan []issue.Item
+	unestimated := 0
+	for _, it := range items {
+		d := issue.EstimateDuration(it.Estimate)
+		if d == 0 {
+			unestimated++
+			continue
+		}
+		if d <= budget {
+			plan = append(plan, it)
+			budget -= d
+		}
+	}
+	return plan, unestimated
+}
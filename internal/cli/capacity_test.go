@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestCapacityPlansReadyIssuesByEstimate(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	for _, tc := range []struct{ title, priority, estimate string }{
+		{"big", "p0", "4h"},
+		{"too big", "p1", "3h"},
+		{"small", "p2", "90m"},
+		{"unknown", "p0", ""},
+	} {
+		it, err := store.CreateIssue(ctx, issue.Item{Title: tc.title, Status: issue.StatusReady, Priority: tc.priority})
+		if err != nil {
+			t.Fatalf("CreateIssue() error: %v", err)
+		}
+		estimate := tc.estimate
+		if _, err := store.UpdateIssue(ctx, it.ID, sqlite.UpdateIssueInput{Estimate: &estimate}); err != nil {
+			t.Fatalf("UpdateIssue() error: %v", err)
+		}
+	}
+
+	cmd := newCapacityCmd()
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"--hours", "6", "--tag"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("capacity error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "TRK-1\tp0\t4h\tbig") || !strings.HasPrefix(lines[1], "TRK-3\tp2\t1h30m\tsmall") || lines[2] != "total: 5h30m of 6h" {
+		t.Fatalf("capacity output:\n%s", out.String())
+	}
+	if !strings.Contains(errOut.String(), "1 ready issues have no estimate") {
+		t.Fatalf("capacity should report unestimated issues, got %q", errOut.String())
+	}
+
+	tagged, err := store.GetIssue(ctx, "TRK-3")
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if !slices.ContainsFunc(tagged.Labels, func(l string) bool { return strings.HasPrefix(l, focusLabelPrefix) }) {
+		t.Fatalf("planned issue should get the focus label: %v", tagged.Labels)
+	}
+	skipped, _ := store.GetIssue(ctx, "TRK-2")
+	if len(skipped.Labels) != 0 {
+		t.Fatalf("unplanned issue should not be tagged: %v", skipped.Labels)
+	}
+}
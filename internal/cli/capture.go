@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/pkg/track"
+	"github.com/spf13/cobra"
+)
+
+func newCaptureCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "capture <text>...",
+		Short: "Create an issue from one line with inline #label !priority @assignee due:",
+		Long: `Create an issue from one line of text. Inline tokens set fields and the
+remaining words are the title:
+
+  track capture "fix flaky TestX #ci !p1 @agent due:fri"
+
+#label adds a label (repeatable), !p0-!p3 sets the priority, @name sets the
+assignee, and due: takes today, tomorrow, a weekday (the next one, counting
+today), or a date as --due accepts.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			loc, err := appconfig.LoadLocation()
+			if err != nil {
+				return err
+			}
+			c, err := issue.ParseCapture(strings.Join(args, " "), time.Now().In(loc))
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			tracker, err := track.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer tracker.Close()
+
+			item, err := tracker.CreateIssue(ctx, track.NewIssue{
+				Title:    c.Title,
+				Priority: c.Priority,
+				Assignee: c.Assignee,
+				Due:      c.Due,
+				Labels:   c.Labels,
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), item.ID)
+			return nil
+		},
+	}
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"strings"
@@ -8,20 +9,51 @@ import (
 	"github.com/mattn/go-isatty"
 )
 
+// Values of the --color flag.
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+// colorMode is the --color flag. always and never win over NO_COLOR,
+// CLICOLOR, and terminal detection, so colors survive `| less -R` and CI
+// logs.
+var colorMode = colorAuto
+
+func validateColorMode(v string) error {
+	switch v {
+	case colorAuto, colorAlways, colorNever:
+		return nil
+	default:
+		return fmt.Errorf("invalid --color (auto|always|never): %s", v)
+	}
+}
+
 type cliColor struct {
 	enabled bool
 }
 
 func newCLIColor(w io.Writer) cliColor {
-	f, ok := w.(*os.File)
-	if !ok {
+	switch colorMode {
+	case colorAlways:
+		return cliColor{enabled: true}
+	case colorNever:
 		return cliColor{enabled: false}
 	}
 	if os.Getenv("NO_COLOR") != "" || strings.TrimSpace(os.Getenv("CLICOLOR")) == "0" {
 		return cliColor{enabled: false}
 	}
+	return cliColor{enabled: isTerminal(w)}
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
 	fd := f.Fd()
-	return cliColor{enabled: isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)}
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
 }
 
 func (c cliColor) status(v string) string {
@@ -60,6 +92,20 @@ func (c cliColor) priority(v string) string {
 	}
 }
 
+// heat shades a heatmap cell by how close n is to the busiest cell.
+func (c cliColor) heat(v string, n, hottest int) string {
+	switch {
+	case n == 0:
+		return c.wrap("90", v)
+	case n*3 > hottest*2:
+		return c.wrap("1;31", v)
+	case n*3 > hottest:
+		return c.wrap("33", v)
+	default:
+		return c.wrap("36", v)
+	}
+}
+
 func (c cliColor) wrap(code, v string) string {
 	if !c.enabled {
 		return v
@@ -1,6 +1,10 @@
 package cli
 
-import "testing"
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
 
 func TestCLIColorDisabled(t *testing.T) {
 	c := cliColor{enabled: false}
@@ -21,3 +25,34 @@ func TestCLIColorEnabled(t *testing.T) {
 		t.Fatalf("priority(p0) = %q", got)
 	}
 }
+
+func TestColorFlagOverridesDetection(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	t.Setenv("NO_COLOR", "1")
+	t.Cleanup(func() { colorMode = colorAuto })
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+	if out, err := run("new", "Colorful"); err != nil {
+		t.Fatalf("new error: %v\n%s", err, out)
+	}
+	if out, err := run("list"); err != nil || strings.Contains(out, "\x1b[") {
+		t.Fatalf("auto list into a buffer = %q, %v", out, err)
+	}
+	if out, err := run("list", "--color", "always"); err != nil || !strings.Contains(out, "\x1b[36mtodo\x1b[0m") {
+		t.Fatalf("list --color always = %q, %v", out, err)
+	}
+	if out, err := run("list", "--color", "never"); err != nil || strings.Contains(out, "\x1b[") {
+		t.Fatalf("list --color never = %q, %v", out, err)
+	}
+	if _, err := run("list", "--color", "rainbow"); err == nil {
+		t.Fatalf("invalid --color should fail")
+	}
+}
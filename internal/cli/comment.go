@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/myuon/track/internal/timefmt"
+	"github.com/spf13/cobra"
+)
+
+// showComments is how many of the latest comments `track show` prints.
+const showComments = 3
+
+func newCommentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "comment",
+		Short: "Discuss an issue without editing its body",
+		Long:  "Add and read an issue's comments. Comments keep discussion history out of the body; `track show` prints the latest ones, and @mentions in them notify like mentions in the body.",
+	}
+	cmd.AddCommand(newCommentAddCmd())
+	cmd.AddCommand(newCommentListCmd())
+	return cmd
+}
+
+func newCommentAddCmd() *cobra.Command {
+	var message string
+	cmd := &cobra.Command{
+		Use:   "add <id>",
+		Short: "Add a comment",
+		Long:  "Add a comment to an issue. Without -m, the comment is read from a comment> prompt.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			text := strings.TrimSpace(message)
+			if text == "" {
+				fmt.Fprint(cmd.OutOrStdout(), "comment> ")
+				line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+				if err != nil && err != io.EOF {
+					return err
+				}
+				text = strings.TrimSpace(line)
+			}
+			if text == "" {
+				return fmt.Errorf("comment message is required")
+			}
+			if _, err := store.AddComment(ctx, normalizeIssueIDArg(args[0]), text); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&message, "message", "m", "", "Comment text")
+	return cmd
+}
+
+func newCommentListCmd() *cobra.Command {
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "list <id>",
+		Short: "List an issue's comments, oldest first",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			issueID := normalizeIssueIDArg(args[0])
+			if _, err := store.GetIssue(ctx, issueID); err != nil {
+				return err
+			}
+			comments, err := store.ListComments(ctx, issueID, limit)
+			if err != nil {
+				return err
+			}
+			times, err := timefmt.Load()
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			for i, c := range comments {
+				if i > 0 {
+					fmt.Fprintln(out)
+				}
+				fmt.Fprintf(out, "#%d %s %s\n%s\n", c.ID, c.Author, times.Time(c.CreatedAt), c.Body)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 0, "Only the most recent n comments (0 for all)")
+	return cmd
+}
+
+// writeRecentComments prints the latest comments for `track show`, one per
+// line with continuation lines indented.
+func writeRecentComments(ctx context.Context, out io.Writer, store *sqlite.Store, issueID string, times timefmt.Formatter) error {
+	total, err := store.CountComments(ctx, issueID)
+	if err != nil || total == 0 {
+		return err
+	}
+	comments, err := store.ListComments(ctx, issueID, showComments)
+	if err != nil {
+		return err
+	}
+	if total > len(comments) {
+		fmt.Fprintf(out, "comments: %d (latest %d; track comment list %s for all)\n", total, len(comments), issueID)
+	} else {
+		fmt.Fprintf(out, "comments: %d\n", total)
+	}
+	for _, c := range comments {
+		body := strings.ReplaceAll(c.Body, "\n", "\n  ")
+		fmt.Fprintf(out, "comment: %s %s: %s\n", times.Time(c.CreatedAt), c.Author, body)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCommentAddListAndShow(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	t.Setenv("TRACK_ACTOR", "bob")
+	run := func(in string, args ...string) string {
+		t.Helper()
+		cmd := newRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetIn(strings.NewReader(in))
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%v: %v\n%s", args, err, out.String())
+		}
+		return out.String()
+	}
+
+	id := strings.TrimSpace(run("", "new", "Fix login"))
+	run("", "comment", "add", id, "-m", "Repro on Safari")
+	if out := run("From the prompt\n", "comment", "add", id); !strings.HasSuffix(out, "comment> ok\n") {
+		t.Fatalf("comment add prompt output = %q", out)
+	}
+
+	out := run("", "comment", "list", id)
+	if !strings.Contains(out, "#1 bob ") || !strings.Contains(out, "\nRepro on Safari\n\n#2 bob ") || !strings.HasSuffix(out, "\nFrom the prompt\n") {
+		t.Fatalf("comment list output:\n%s", out)
+	}
+
+	out = run("", "show", id)
+	if !strings.Contains(out, "comments: 2\n") || !strings.Contains(out, " bob: Repro on Safari\n") {
+		t.Fatalf("show should print comments:\n%s", out)
+	}
+	run("", "comment", "add", id, "-m", "third")
+	run("", "comment", "add", id, "-m", "fourth")
+	out = run("", "show", id)
+	if !strings.Contains(out, "comments: 4 (latest 3; track comment list "+id+" for all)\n") || strings.Contains(out, "Repro on Safari") {
+		t.Fatalf("show should print only the latest comments:\n%s", out)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/store/sqlite"
 	"github.com/spf13/cobra"
 )
 
@@ -53,6 +54,11 @@ func newConfigSetCmd() *cobra.Command {
 			if err := appconfig.Set(&cfg, args[0], args[1]); err != nil {
 				return err
 			}
+			if args[0] == "list.default_sort" {
+				if err := sqlite.ValidateSort(cfg.List.DefaultSort); err != nil {
+					return err
+				}
+			}
 			if err := appconfig.Save(cfg); err != nil {
 				return err
 			}
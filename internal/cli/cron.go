@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/cron"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+const cronJobGHWatch = "gh-watch"
+
+var ghWatchSeenFailures = map[string]struct{}{}
+
+// cronJobs returns the built-in jobs plus those that live in the cli package.
+func cronJobs() []cron.Job {
+	return append(cron.Builtin(), cron.Job{
+		Name:            cronJobGHWatch,
+		Description:     "poll linked PRs and update issues (gh watch)",
+		DefaultInterval: time.Minute,
+		Run: func(ctx context.Context, store *sqlite.Store, log io.Writer) error {
+			if _, err := exec.LookPath("gh"); err != nil {
+				return fmt.Errorf("gh command is required")
+			}
+			cfg, err := appconfig.Load()
+			if err != nil {
+				return err
+			}
+			return runGHWatchOnce(ctx, cfg.GHRepo, log, ghWatchSeenFailures)
+		},
+	})
+}
+
+func newCronCmd() *cobra.Command {
+	var once bool
+	var tick string
+
+	cmd := &cobra.Command{
+		Use:   "cron",
+		Short: "Run scheduled background jobs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			sched := cron.NewScheduler(cronJobs()...)
+			if once {
+				store, err := sqlite.Open(ctx)
+				if err != nil {
+					return err
+				}
+				defer store.Close()
+
+				ran, err := sched.RunDue(ctx, store)
+				if err != nil {
+					return err
+				}
+				for _, name := range ran {
+					fmt.Fprintf(cmd.OutOrStdout(), "ran %s\n", name)
+				}
+				return nil
+			}
+
+			dur, err := time.ParseDuration(tick)
+			if err != nil || dur <= 0 {
+				return fmt.Errorf("invalid tick: %s", tick)
+			}
+			return sched.Loop(ctx, dur, cmd.ErrOrStderr())
+		},
+	}
+	cmd.Flags().BoolVar(&once, "once", false, "Run due jobs once and exit")
+	cmd.Flags().StringVar(&tick, "tick", "1m", "How often to check for due jobs")
+
+	cmd.AddCommand(newCronListCmd())
+	cmd.AddCommand(newCronToggleCmd("enable", "Enable a scheduled job", true))
+	cmd.AddCommand(newCronToggleCmd("disable", "Disable a scheduled job", false))
+	cmd.AddCommand(newCronRunCmd())
+	cmd.AddCommand(newCronLogsCmd())
+	return cmd
+}
+
+func newCronListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List scheduled jobs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			for _, job := range cronJobs() {
+				st, err := cron.State(ctx, store, job)
+				if err != nil {
+					return err
+				}
+				lastRun := st.LastRunAt
+				if lastRun == "" {
+					lastRun = "never"
+				}
+				line := fmt.Sprintf("%s\t%s\tevery=%s\tlast=%s", job.Name, enabledLabel(st.Enabled), st.Interval, lastRun)
+				if st.LastError != "" {
+					line += "\terror=" + st.LastError
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), line)
+			}
+			return nil
+		},
+	}
+}
+
+func newCronToggleCmd(use, short string, enabled bool) *cobra.Command {
+	var interval string
+
+	cmd := &cobra.Command{
+		Use:   use + " <job>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			job, err := cron.NewScheduler(cronJobs()...).Find(args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			st, err := cron.State(ctx, store, job)
+			if err != nil {
+				return err
+			}
+			st.Enabled = enabled
+			if cmd.Flags().Changed("interval") {
+				dur, err := time.ParseDuration(interval)
+				if err != nil || dur <= 0 {
+					return fmt.Errorf("invalid interval: %s", interval)
+				}
+				st.Interval = dur
+			}
+			if err := store.SaveJobState(ctx, st); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&interval, "interval", "", "Run interval (e.g. 30m, 24h)")
+	return cmd
+}
+
+func newCronRunCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run <job>",
+		Short: "Run a job now, regardless of schedule",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sched := cron.NewScheduler(cronJobs()...)
+			job, err := sched.Find(args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if err := sched.RunJob(ctx, store, job); err != nil {
+				return fmt.Errorf("job %s failed: %w", job.Name, err)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+}
+
+func newCronLogsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "logs <job>",
+		Short: "Print a job's log",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			job, err := cron.NewScheduler(cronJobs()...).Find(args[0])
+			if err != nil {
+				return err
+			}
+			path, err := cron.LogPath(job.Name)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("read job log: %w", err)
+			}
+			_, err = cmd.OutOrStdout().Write(data)
+			return err
+		},
+	}
+}
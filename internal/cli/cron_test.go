@@ -0,0 +1,252 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestRecurAddAndCronRunCreatesIssue(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	add := newRecurCmd()
+	var out bytes.Buffer
+	add.SetOut(&out)
+	add.SetArgs([]string{"add", "Weekly review", "--every", "weekly", "--label", "chore"})
+	if err := add.Execute(); err != nil {
+		t.Fatalf("recur add error: %v", err)
+	}
+	if strings.TrimSpace(out.String()) != "1" {
+		t.Fatalf("recur add output = %q", out.String())
+	}
+
+	run := newCronCmd()
+	out.Reset()
+	run.SetOut(&out)
+	run.SetArgs([]string{"run", "recurring"})
+	if err := run.Execute(); err != nil {
+		t.Fatalf("cron run error: %v", err)
+	}
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	defer store.Close()
+	items, err := store.ListIssues(ctx, sqlite.ListFilter{})
+	if err != nil {
+		t.Fatalf("ListIssues() error: %v", err)
+	}
+	if len(items) != 1 || items[0].Title != "Weekly review" {
+		t.Fatalf("unexpected issues: %+v", items)
+	}
+
+	logs := newCronCmd()
+	out.Reset()
+	logs.SetOut(&out)
+	logs.SetArgs([]string{"logs", "recurring"})
+	if err := logs.Execute(); err != nil {
+		t.Fatalf("cron logs error: %v", err)
+	}
+	if !strings.Contains(out.String(), "created "+items[0].ID) {
+		t.Fatalf("cron log missing created issue:\n%s", out.String())
+	}
+}
+
+func TestCronDisableShowsInList(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	disable := newCronCmd()
+	var out bytes.Buffer
+	disable.SetOut(&out)
+	disable.SetArgs([]string{"disable", "due-soon", "--interval", "2h"})
+	if err := disable.Execute(); err != nil {
+		t.Fatalf("cron disable error: %v", err)
+	}
+
+	list := newCronCmd()
+	out.Reset()
+	list.SetOut(&out)
+	list.SetArgs([]string{"list"})
+	if err := list.Execute(); err != nil {
+		t.Fatalf("cron list error: %v", err)
+	}
+	if !strings.Contains(out.String(), "due-soon\tdisabled\tevery=2h0m0s\tlast=never\n") {
+		t.Fatalf("unexpected cron list:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "recurring\tenabled\t") {
+		t.Fatalf("recurring should default to enabled:\n%s", out.String())
+	}
+
+	unknown := newCronCmd()
+	unknown.SetOut(&out)
+	unknown.SetErr(&out)
+	unknown.SetArgs([]string{"enable", "nope"})
+	if err := unknown.Execute(); err == nil || !strings.Contains(err.Error(), "unknown job: nope") {
+		t.Fatalf("expected unknown job error, got %v", err)
+	}
+}
+
+func TestServeCmdStartsAPIWithoutCron(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	orig := apiListenAndServe
+	t.Cleanup(func() {
+		apiListenAndServe = orig
+	})
+
+	var gotAddr string
+	apiListenAndServe = func(addr string, handler http.Handler) error {
+		gotAddr = addr
+		return nil
+	}
+
+	cmd := newServeCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--port", "18899", "--grpc-port", "0", "--no-cron"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if gotAddr != "127.0.0.1:18899" {
+		t.Fatalf("listen addr = %q", gotAddr)
+	}
+}
+
+func TestServeCmdHeadless(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	t.Setenv(appconfig.ConfigEnv, "")
+	t.Setenv("TRACK_RATE_LIMIT", "60")
+	t.Setenv(adminTokenEnv, "trk_bootstrap-secret-0123456789")
+	origLogger := slog.Default()
+	orig := apiServeUntil
+	t.Cleanup(func() {
+		apiServeUntil = orig
+		slog.SetDefault(origLogger)
+	})
+
+	var gotAddr string
+	var gotCfg appconfig.Config
+	apiServeUntil = func(ctx context.Context, addr string, handler http.Handler) error {
+		gotAddr = addr
+		cfg, err := appconfig.Load()
+		gotCfg = cfg
+		return err
+	}
+
+	data := t.TempDir()
+	cmd := newServeCmd()
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"--headless", "--data", data, "--port", "18899", "--grpc-port", "0", "--no-cron"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if gotAddr != "0.0.0.0:18899" {
+		t.Fatalf("listen addr = %q", gotAddr)
+	}
+	if gotCfg.RateLimit != 60 {
+		t.Fatalf("rate_limit from env = %d, want 60", gotCfg.RateLimit)
+	}
+	if _, err := os.Stat(filepath.Join(data, "config.toml")); !os.IsNotExist(err) {
+		t.Fatalf("headless mode should not write config.toml: %v", err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("headless mode should only log JSON to stderr, stdout = %q", out.String())
+	}
+	for _, line := range strings.Split(strings.TrimSpace(errOut.String()), "\n") {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("log line is not JSON: %q", line)
+		}
+	}
+	if !strings.Contains(errOut.String(), `"msg":"api listening"`) || !strings.Contains(errOut.String(), data) {
+		t.Fatalf("missing startup log: %q", errOut.String())
+	}
+}
+
+func TestServeCmdHeadlessRequiresAToken(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	t.Setenv(appconfig.ConfigEnv, "")
+	t.Setenv(adminTokenEnv, "")
+	origLogger := slog.Default()
+	orig := apiServeUntil
+	t.Cleanup(func() {
+		apiServeUntil = orig
+		slog.SetDefault(origLogger)
+	})
+	served := false
+	apiServeUntil = func(ctx context.Context, addr string, handler http.Handler) error {
+		served = true
+		return nil
+	}
+	serve := func(data string, extra ...string) error {
+		cmd := newServeCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(append([]string{"--headless", "--data", data, "--port", "18899", "--grpc-port", "0", "--no-cron"}, extra...))
+		return cmd.Execute()
+	}
+
+	data := t.TempDir()
+	if err := serve(data); err == nil || !strings.Contains(err.Error(), adminTokenEnv) || served {
+		t.Fatalf("headless serve without tokens = %v (served %v); want a refusal naming %s", err, served, adminTokenEnv)
+	}
+	if err := serve(data, "--insecure-no-auth"); err != nil || !served {
+		t.Fatalf("headless serve --insecure-no-auth = %v (served %v)", err, served)
+	}
+
+	// The bootstrap token is stored and then required.
+	served = false
+	t.Setenv(adminTokenEnv, "trk_bootstrap-secret-0123456789")
+	if err := serve(data); err != nil || !served {
+		t.Fatalf("headless serve with %s = %v (served %v)", adminTokenEnv, err, served)
+	}
+	store, err := sqlite.Open(context.Background())
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	tok, ok, err := store.AuthorizeAPIToken(context.Background(), "trk_bootstrap-secret-0123456789", sqlite.RoleAdmin)
+	if err != nil || !ok || tok.Name != "bootstrap" {
+		t.Fatalf("AuthorizeAPIToken(bootstrap) = %+v, %v, %v", tok, ok, err)
+	}
+	if _, _, err := store.AuthorizeAPIToken(context.Background(), "", sqlite.RoleRead); !errors.Is(err, sqlite.ErrTokenRequired) {
+		t.Fatalf("AuthorizeAPIToken(no token) error = %v, want ErrTokenRequired", err)
+	}
+
+	// Too short a secret is refused rather than trusted.
+	t.Setenv(adminTokenEnv, "short")
+	if err := serve(t.TempDir()); err == nil || !strings.Contains(err.Error(), "16 characters") {
+		t.Fatalf("headless serve with a short token = %v", err)
+	}
+}
+
+func TestAPIServeUntilStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- apiServeUntil(ctx, "127.0.0.1:0", http.NotFoundHandler()) }()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-errc:
+		if err != nil {
+			t.Fatalf("apiServeUntil() error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("server did not stop after cancel")
+	}
+}
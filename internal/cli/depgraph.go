@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func newDepCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dep",
+		Short: "Record and inspect issue dependencies",
+		Long:  "Record which issues block which, as `track depends-on` does, and inspect the dependency graph. `next` skips issues whose blockers are neither done nor archived, and `list` flags them.",
+	}
+	cmd.AddCommand(newDepAddCmd(), newDepRmCmd(), newDepGraphCmd())
+	return cmd
+}
+
+func newDepGraphCmd() *cobra.Command {
+	var (
+		project string
+		format  string
+		all     bool
+	)
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Print issue dependencies as a DOT or Mermaid graph",
+		Long: `Print the issues (of --project, if given) that depend on or block another
+issue, with an arrow from each blocker to the issue waiting on it, colored
+by status. Blockers and dependents in other projects are drawn too so no
+arrow dangles; --all also includes issues without dependencies. Mermaid
+output renders inside a ` + "```mermaid" + ` block in markdown:
+
+  track dep graph --project cli --format mermaid`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "dot" && format != "mermaid" {
+				return fmt.Errorf("unsupported format: %s", format)
+			}
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if project != "" {
+				if _, err := store.GetProject(ctx, project); err != nil {
+					return err
+				}
+			}
+			g, err := dependencyGraph(ctx, store, project, all)
+			if err != nil {
+				return err
+			}
+			return writeGraph(cmd.OutOrStdout(), g, format)
+		},
+	}
+	cmd.Flags().StringVar(&project, "project", "", "Only graph this project's issues")
+	cmd.Flags().StringVar(&format, "format", "mermaid", "Output format: dot|mermaid")
+	cmd.Flags().BoolVar(&all, "all", false, "Include issues without dependencies")
+	return cmd
+}
+
+// dependencyGraph draws an edge from each blocker to the issue depending on
+// it, for every dependency that touches an issue of project.
+func dependencyGraph(ctx context.Context, store *sqlite.Store, project string, all bool) (graph, error) {
+	items, err := store.ListIssues(ctx, sqlite.ListFilter{Project: project, Sort: "id"})
+	if err != nil {
+		return graph{}, err
+	}
+	deps, err := store.ListIssueDependencies(ctx)
+	if err != nil {
+		return graph{}, err
+	}
+	byID := make(map[string]issue.Item, len(items))
+	for _, it := range items {
+		byID[it.ID] = it
+	}
+
+	var g graph
+	inGraph := map[string]bool{}
+	var order []string
+	add := func(id string) {
+		if !inGraph[id] {
+			inGraph[id] = true
+			order = append(order, id)
+		}
+	}
+	if all {
+		for _, it := range items {
+			add(it.ID)
+		}
+	}
+	for _, it := range items {
+		for _, blocker := range deps[it.ID] {
+			add(blocker)
+			add(it.ID)
+			g.edges = append(g.edges, graphEdge{from: blocker, to: it.ID})
+		}
+	}
+	// Issues outside project that wait on one of its issues.
+	dependents := make([]string, 0, len(deps))
+	for id := range deps {
+		if _, ok := byID[id]; !ok {
+			dependents = append(dependents, id)
+		}
+	}
+	sort.Strings(dependents)
+	for _, id := range dependents {
+		for _, blocker := range deps[id] {
+			if _, ok := byID[blocker]; ok {
+				add(blocker)
+				add(id)
+				g.edges = append(g.edges, graphEdge{from: blocker, to: id})
+			}
+		}
+	}
+
+	for _, id := range order {
+		it, ok := byID[id]
+		if !ok {
+			if it, err = store.GetIssue(ctx, id); err != nil {
+				return graph{}, err
+			}
+		}
+		g.nodes = append(g.nodes, graphNode{id: it.ID, label: fmt.Sprintf("%s %s (%s)", it.ID, it.Title, it.Status), status: it.Status})
+	}
+	return g, nil
+}
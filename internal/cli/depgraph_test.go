@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDepGraph(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+	for _, args := range [][]string{
+		{"project", "add", "cli", "--name", "CLI"},
+		{"new", "Schema", "--project", "cli"},
+		{"new", "Command", "--project", "cli"},
+		{"new", "Docs"},
+		{"new", "Unrelated", "--project", "cli"},
+		{"depends-on", "TRK-2", "TRK-1"},
+		{"depends-on", "TRK-3", "TRK-2"},
+		{"done", "TRK-1"},
+	} {
+		if out, err := run(args...); err != nil {
+			t.Fatalf("%v error: %v\n%s", args, err, out)
+		}
+	}
+
+	out, err := run("dep", "graph", "--project", "cli")
+	if err != nil {
+		t.Fatalf("dep graph error: %v\n%s", err, out)
+	}
+	want := `flowchart LR
+  TRK_1["TRK-1 Schema (done)"]
+  TRK_2["TRK-2 Command (todo)"]
+  TRK_3["TRK-3 Docs (todo)"]
+  TRK_1 --> TRK_2
+  TRK_2 --> TRK_3
+  classDef status_done fill:#bbf7d0
+  classDef status_todo fill:#e5e7eb
+  class TRK_1 status_done
+  class TRK_2 status_todo
+  class TRK_3 status_todo
+`
+	if out != want {
+		t.Fatalf("mermaid output =\n%s\nwant\n%s", out, want)
+	}
+
+	out, err = run("dep", "graph", "--project", "cli", "--all", "--format", "dot")
+	if err != nil {
+		t.Fatalf("dep graph error: %v\n%s", err, out)
+	}
+	for _, want := range []string{`"TRK-4" [label="TRK-4 Unrelated (todo)"`, `"TRK-1" -> "TRK-2";`, `fillcolor="#bbf7d0"`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("dot output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func newDependsOnCmd() *cobra.Command {
+	var remove bool
+	cmd := &cobra.Command{
+		Use:   "depends-on <id> <blocker_id>",
+		Short: "Record that an issue builds on another",
+		Long:  "Record that an issue can only be dispatched once another is merged (done). `dispatch` refuses blocked issues and `dispatch --all-ready` works through ready issues in dependency order; dependencies that would form a cycle are rejected.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			id, blocker := normalizeIssueIDArg(args[0]), normalizeIssueIDArg(args[1])
+			if remove {
+				err = store.RemoveIssueDependency(ctx, id, blocker)
+			} else {
+				err = store.AddIssueDependency(ctx, id, blocker)
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&remove, "rm", false, "Remove the dependency instead")
+	return cmd
+}
+
+func newDepAddCmd() *cobra.Command {
+	return newDepEdgeCmd("add", "Record that an issue blocks or is blocked by others", false)
+}
+
+func newDepRmCmd() *cobra.Command {
+	return newDepEdgeCmd("rm", "Remove dependencies recorded with dep add", true)
+}
+
+// newDepEdgeCmd builds dep add and dep rm, which take the other end of each
+// dependency from --blocks and --blocked-by.
+func newDepEdgeCmd(use, short string, remove bool) *cobra.Command {
+	var blocks, blockedBy []string
+	cmd := &cobra.Command{
+		Use:   use + " <id> (--blocks <id> | --blocked-by <id>)...",
+		Short: short,
+		Long:  "--blocks TRK-2 records that TRK-2 waits on <id>; --blocked-by TRK-1 records that <id> waits on TRK-1. Both can be repeated or comma separated. Dependencies that would form a cycle are rejected.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			id := normalizeIssueIDArg(args[0])
+			edit := store.AddIssueDependency
+			if remove {
+				edit = store.RemoveIssueDependency
+			}
+			for _, other := range blocks {
+				if err := edit(ctx, normalizeIssueIDArg(other), id); err != nil {
+					return err
+				}
+			}
+			for _, other := range blockedBy {
+				if err := edit(ctx, id, normalizeIssueIDArg(other)); err != nil {
+					return err
+				}
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVar(&blocks, "blocks", nil, "Issues that wait on <id>")
+	cmd.Flags().StringSliceVar(&blockedBy, "blocked-by", nil, "Issues <id> waits on")
+	cmd.MarkFlagsOneRequired("blocks", "blocked-by")
+	return cmd
+}
+
+// blockedError explains why an issue cannot be dispatched yet, or returns
+// nil when all of its blockers are merged.
+func blockedError(ctx context.Context, store *sqlite.Store, id string) error {
+	blockers, err := store.UnmergedBlockers(ctx, id)
+	if err != nil || len(blockers) == 0 {
+		return err
+	}
+	parts := make([]string, 0, len(blockers))
+	for _, b := range blockers {
+		parts = append(parts, fmt.Sprintf("%s (%s)", b.ID, b.Status))
+	}
+	return fmt.Errorf("%s is blocked by unmerged %s", id, strings.Join(parts, ", "))
+}
+
+// blockedPrefix flags an open issue still waiting on blockers that are not
+// done, for the title column of list.
+func blockedPrefix(it issue.Item, blockedBy map[string][]string) string {
+	blockers := blockedBy[it.ID]
+	if len(blockers) == 0 || it.Status == issue.StatusDone || it.Status == issue.StatusArchived {
+		return ""
+	}
+	return "[blocked by " + strings.Join(blockers, ",") + "] "
+}
+
+// dependentsOf lists the issues that depend on id, in ID order.
+func dependentsOf(deps map[string][]string, id string) []string {
+	var out []string
+	for dependent, blockers := range deps {
+		if slices.Contains(blockers, id) {
+			out = append(out, dependent)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// dependencyEdges lists ids with their statuses, as "TRK-1 (done), TRK-2
+// (todo)", for show.
+func dependencyEdges(ctx context.Context, store *sqlite.Store, ids []string) (string, error) {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		it, err := store.GetIssue(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s)", it.ID, it.Status))
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// orderByDependencies sorts items so every issue comes after the issues it
+// depends on, keeping the given order otherwise. Dependencies outside items
+// do not affect the order.
+func orderByDependencies(items []issue.Item, deps map[string][]string) []issue.Item {
+	index := make(map[string]int, len(items))
+	for i, it := range items {
+		index[it.ID] = i
+	}
+	placed := make([]bool, len(items))
+	out := make([]issue.Item, 0, len(items))
+	var visit func(i int)
+	visit = func(i int) {
+		if placed[i] {
+			return
+		}
+		placed[i] = true
+		for _, dep := range deps[items[i].ID] {
+			if j, ok := index[dep]; ok {
+				visit(j)
+			}
+		}
+		out = append(out, items[i])
+	}
+	for i := range items {
+		visit(i)
+	}
+	return out
+}
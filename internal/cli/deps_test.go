@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDepAddBlocksNextAndList(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+	for _, args := range [][]string{
+		{"new", "Ship it", "--priority", "p0"},
+		{"new", "Schema", "--priority", "p2"},
+		{"new", "Docs", "--priority", "p3"},
+		{"dep", "add", "TRK-1", "--blocked-by", "TRK-2"},
+		{"dep", "add", "TRK-3", "--blocks", "TRK-1"},
+	} {
+		if out, err := run(args...); err != nil {
+			t.Fatalf("%v error: %v\n%s", args, err, out)
+		}
+	}
+	if out, err := run("dep", "add", "TRK-2", "--blocked-by", "TRK-1"); err == nil || !strings.Contains(err.Error(), "dependency cycle") {
+		t.Fatalf("dep add cycle = %v\n%s", err, out)
+	}
+	if _, err := run("dep", "add", "TRK-1"); err == nil {
+		t.Fatal("dep add without --blocks or --blocked-by should fail")
+	}
+
+	out, err := run("next")
+	if err != nil || !strings.HasPrefix(out, "TRK-2\t") {
+		t.Fatalf("next = %q, %v; want TRK-2 while TRK-1 is blocked", out, err)
+	}
+	out, err = run("list")
+	if err != nil {
+		t.Fatalf("list error: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "[blocked by TRK-2,TRK-3] Ship it") {
+		t.Fatalf("list should flag TRK-1 with its blockers:\n%s", out)
+	}
+	out, err = run("list", "--unblocked")
+	if err != nil || strings.Contains(out, "Ship it") || !strings.Contains(out, "Schema") {
+		t.Fatalf("list --unblocked = %v\n%s", err, out)
+	}
+
+	out, err = run("show", "TRK-1")
+	if err != nil || !strings.Contains(out, "depends_on: TRK-2 (todo), TRK-3 (todo)\n") {
+		t.Fatalf("show TRK-1 = %v\n%s", err, out)
+	}
+	out, err = run("show", "TRK-2")
+	if err != nil || !strings.Contains(out, "blocks: TRK-1 (todo)\n") {
+		t.Fatalf("show TRK-2 = %v\n%s", err, out)
+	}
+
+	for _, args := range [][]string{
+		{"done", "TRK-2"},
+		{"dep", "rm", "TRK-1", "--blocked-by", "TRK-3"},
+	} {
+		if out, err := run(args...); err != nil {
+			t.Fatalf("%v error: %v\n%s", args, err, out)
+		}
+	}
+	out, err = run("next")
+	if err != nil || !strings.HasPrefix(out, "TRK-1\t") {
+		t.Fatalf("next = %q, %v; want TRK-1 once its blockers are gone", out, err)
+	}
+	if out, _ := run("list"); strings.Contains(out, "[blocked by") {
+		t.Fatalf("list should no longer flag TRK-1:\n%s", out)
+	}
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/myuon/track/internal/textdiff"
+	"github.com/myuon/track/internal/timefmt"
+	"github.com/spf13/cobra"
+)
+
+func newDiffCmd() *cobra.Command {
+	var list bool
+
+	cmd := &cobra.Command{
+		Use:   "diff <id> [revA] [revB]",
+		Short: "Show a unified diff between body revisions",
+		Long:  "Print a unified diff of an issue body between two revisions. With no revisions it diffs the latest edit; with one it diffs that revision against the latest. Revisions are numbered from 1; --list prints them.",
+		Args:  cobra.RangeArgs(1, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			id := normalizeIssueIDArg(args[0])
+			if _, err := store.GetIssue(ctx, id); err != nil {
+				return err
+			}
+			revs, err := store.ListRevisions(ctx, id)
+			if err != nil {
+				return err
+			}
+			if list {
+				times, err := timefmt.Load()
+				if err != nil {
+					return err
+				}
+				for _, r := range revs {
+					actor := r.Actor
+					if actor == "" {
+						actor = "-"
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "%d\t%s\t%s\n", r.Rev, actor, times.Time(r.CreatedAt))
+				}
+				return nil
+			}
+			if len(revs) == 0 {
+				return fmt.Errorf("no revisions recorded: %s", id)
+			}
+
+			latest := revs[len(revs)-1].Rev
+			from, to := max(1, latest-1), latest
+			if len(args) > 1 {
+				if from, err = parseRev(args[1]); err != nil {
+					return err
+				}
+			}
+			if len(args) > 2 {
+				if to, err = parseRev(args[2]); err != nil {
+					return err
+				}
+			}
+			out, err := revisionDiff(ctx, store, id, from, to)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), out)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&list, "list", false, "List the issue's revisions instead")
+	return cmd
+}
+
+func parseRev(v string) (int, error) {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid revision: %s", v)
+	}
+	return n, nil
+}
+
+// revisionDiff renders the unified diff of an issue body from one revision
+// to another.
+func revisionDiff(ctx context.Context, store *sqlite.Store, id string, from, to int) (string, error) {
+	a, err := store.GetRevision(ctx, id, from)
+	if err != nil {
+		return "", err
+	}
+	b, err := store.GetRevision(ctx, id, to)
+	if err != nil {
+		return "", err
+	}
+	return textdiff.Unified(fmt.Sprintf("%s@%d", id, from), fmt.Sprintf("%s@%d", id, to), a.Body, b.Body), nil
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestDiffPrintsRevisions(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	created, err := store.CreateIssue(ctx, issue.Item{Title: "Add login", Status: issue.StatusTodo, Priority: "p2", Body: "## Spec\ndraft"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	body := "## Spec\nfinal"
+	if _, err := store.UpdateIssue(sqlite.WithActor(ctx, "agent:codex"), created.ID, sqlite.UpdateIssueInput{Body: &body}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+	if _, err := store.AppendIssueSection(ctx, created.ID, "Notes", "- later"); err != nil {
+		t.Fatalf("AppendIssueSection() error: %v", err)
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := newDiffCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("diff %v error: %v", args, err)
+		}
+		return out.String()
+	}
+
+	want := "--- TRK-1@1\n+++ TRK-1@2\n@@ -1,2 +1,2 @@\n ## Spec\n-draft\n+final\n"
+	if got := run(created.ID, "1", "2"); got != want {
+		t.Fatalf("diff 1 2 = %q, want %q", got, want)
+	}
+	if got := run(created.ID); !strings.HasPrefix(got, "--- TRK-1@2\n+++ TRK-1@3\n") || !strings.Contains(got, "+- later") {
+		t.Fatalf("diff should default to the latest edit, got:\n%s", got)
+	}
+	if got := run(created.ID, "1"); !strings.HasPrefix(got, "--- TRK-1@1\n+++ TRK-1@3\n") {
+		t.Fatalf("diff 1 should compare against the latest revision, got:\n%s", got)
+	}
+	list := strings.Split(strings.TrimSpace(run(created.ID, "--list")), "\n")
+	if len(list) != 3 || !strings.HasPrefix(list[1], "2\tagent:codex\t") {
+		t.Fatalf("diff --list = %q", list)
+	}
+}
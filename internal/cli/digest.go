@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/digest"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func newDigestCmd() *cobra.Command {
+	var (
+		email string
+		since string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "digest",
+		Short: "Mail an HTML summary of completed, overdue, and waiting issues",
+		Long:  "Build an HTML digest of issues completed in the period, overdue issues, and questions waiting for an answer, and mail it through the smtp_* settings to --email (default digest_email). Without an address the HTML is printed instead. To send it weekly, enable the digest cron job: track cron enable digest.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := appconfig.Load()
+			if err != nil {
+				return err
+			}
+			loc, err := cfg.Location()
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			from := now.Add(-digest.Period)
+			if since != "" {
+				if from, err = parseSince(since, now, loc); err != nil {
+					return err
+				}
+			}
+
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			d, err := digest.Build(ctx, store, from, now, loc)
+			if err != nil {
+				return err
+			}
+			if email == "" {
+				email = cfg.DigestEmail
+			}
+			if email == "" {
+				html, err := d.HTML()
+				if err != nil {
+					return err
+				}
+				fmt.Fprint(cmd.OutOrStdout(), html)
+				return nil
+			}
+			if err := digest.Send(cfg, email, d); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "sent to %s\n", email)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&email, "email", "", "Recipient address (default digest_email)")
+	cmd.Flags().StringVar(&since, "since", "", "Start of the period as a duration ago (7d, 12h) or a date (default 7d)")
+	return cmd
+}
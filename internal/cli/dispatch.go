@@ -11,8 +11,9 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/myuon/track/internal/hooks"
 	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/platform"
+	"github.com/myuon/track/internal/service"
 	"github.com/myuon/track/internal/store/sqlite"
 	"github.com/spf13/cobra"
 )
@@ -21,6 +22,7 @@ const dispatchFinishedStatus = "finished"
 
 type dispatchOptions struct {
 	Runner      string
+	Sandbox     string
 	Mode        string
 	Base        string
 	MergeMethod string
@@ -32,12 +34,25 @@ type dispatchCommandRunner interface {
 	RunInteractive(ctx context.Context, dir string, stdin io.Reader, stdout, stderr io.Writer, name string, args ...string) error
 }
 
-type realDispatchCommandRunner struct{}
+// realDispatchCommandRunner runs commands for real. actor attributes the
+// implementation runner's track commands; it defaults to agent:<runner>.
+type realDispatchCommandRunner struct {
+	actor string
+}
 
+// Run runs a command and returns its trimmed output. gh commands that only
+// read are retried on transient failures (see gh_retries).
 func (r realDispatchCommandRunner) Run(ctx context.Context, dir string, name string, args ...string) (string, error) {
-	cmd := exec.CommandContext(ctx, name, args...)
-	cmd.Dir = dir
-	raw, err := cmd.CombinedOutput()
+	run := func() ([]byte, []byte, error) {
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Dir = dir
+		return captureOutput(cmd, true)
+	}
+	policy := ghRetryPolicy{}
+	if name == "gh" {
+		policy = ghRetryPolicyFor(args)
+	}
+	raw, err := retryGH(ctx, policy, run)
 	out := strings.TrimSpace(string(raw))
 	if err != nil {
 		if out == "" {
@@ -51,6 +66,11 @@ func (r realDispatchCommandRunner) Run(ctx context.Context, dir string, name str
 func (r realDispatchCommandRunner) RunInteractive(ctx context.Context, dir string, stdin io.Reader, stdout, stderr io.Writer, name string, args ...string) error {
 	cmd := exec.CommandContext(ctx, name, args...)
 	cmd.Dir = dir
+	actor := r.actor
+	if actor == "" {
+		actor = "agent:" + strings.TrimPrefix(filepath.Base(name), "exec_")
+	}
+	cmd.Env = append(os.Environ(), sqlite.ActorEnv+"="+actor)
 	cmd.Stdin = stdin
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
@@ -58,21 +78,27 @@ func (r realDispatchCommandRunner) RunInteractive(ctx context.Context, dir strin
 }
 
 func newDispatchCmd() *cobra.Command {
-	var opts dispatchOptions
+	var (
+		opts     dispatchOptions
+		allReady bool
+	)
 
 	cmd := &cobra.Command{
-		Use:   "dispatch <issue_id>",
+		Use:   "dispatch [issue_id]",
 		Short: "Run issue implementation cycle from worktree to PR merge",
-		Args:  cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if opts.Runner != "codex" && opts.Runner != "claude" {
-				return fmt.Errorf("invalid --runner: %s", opts.Runner)
+		Long: `Run an issue's implementation cycle: worktree, runner, commit, PR, CI, and
+merge. Issues whose blockers (see track depends-on) are not merged yet are
+refused. --all-ready dispatches every ready issue instead, blockers first,
+skipping issues still waiting on an unmerged blocker.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if allReady {
+				return cobra.NoArgs(cmd, args)
 			}
-			if opts.Mode != "execution" && opts.Mode != "plan" {
-				return fmt.Errorf("invalid --mode: %s", opts.Mode)
-			}
-			if opts.MergeMethod != "merge" && opts.MergeMethod != "squash" && opts.MergeMethod != "rebase" {
-				return fmt.Errorf("invalid --merge-method: %s", opts.MergeMethod)
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateDispatchOptions(opts); err != nil {
+				return err
 			}
 
 			ctx := cmd.Context()
@@ -87,20 +113,95 @@ func newDispatchCmd() *cobra.Command {
 				return err
 			}
 
-			issueID := normalizeIssueIDArg(args[0])
-			return runDispatch(ctx, store, cmd.OutOrStdout(), cwd, issueID, opts, realDispatchCommandRunner{}, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+			dispatch := func(issueID string) error {
+				return dispatchIssue(cmd, store, cwd, issueID, opts)
+			}
+			if !allReady {
+				return dispatch(normalizeIssueIDArg(args[0]))
+			}
+			return dispatchAllReady(ctx, store, cmd.OutOrStdout(), dispatch)
 		},
 	}
 
-	cmd.Flags().StringVar(&opts.Runner, "runner", "codex", "Runner (codex|claude)")
+	cmd.Flags().StringVar(&opts.Runner, "runner", "codex", "Runner (codex|claude); defaults to the assignee's agent profile")
 	cmd.Flags().StringVar(&opts.Mode, "mode", "execution", "Mode (execution|plan)")
 	cmd.Flags().StringVar(&opts.Base, "base", "main", "Base branch")
 	cmd.Flags().StringVar(&opts.MergeMethod, "merge-method", "merge", "Merge method (merge|squash|rebase)")
 	cmd.Flags().BoolVar(&opts.NoMerge, "no-merge", false, "Skip merge after CI success")
+	cmd.Flags().BoolVar(&allReady, "all-ready", false, "Dispatch every ready issue in dependency order")
 
 	return cmd
 }
 
+func validateDispatchOptions(opts dispatchOptions) error {
+	if opts.Runner != "codex" && opts.Runner != "claude" {
+		return fmt.Errorf("invalid --runner: %s", opts.Runner)
+	}
+	if opts.Mode != "execution" && opts.Mode != "plan" {
+		return fmt.Errorf("invalid --mode: %s", opts.Mode)
+	}
+	if opts.MergeMethod != "merge" && opts.MergeMethod != "squash" && opts.MergeMethod != "rebase" {
+		return fmt.Errorf("invalid --merge-method: %s", opts.MergeMethod)
+	}
+	return nil
+}
+
+// dispatchIssue runs one dispatch for cmd, refusing blocked issues and
+// running as the agent assigned to the issue unless --runner was given.
+func dispatchIssue(cmd *cobra.Command, store *sqlite.Store, cwd, issueID string, opts dispatchOptions) error {
+	ctx := cmd.Context()
+	it, err := store.GetIssue(ctx, issueID)
+	if err != nil {
+		return err
+	}
+	if err := blockedError(ctx, store, issueID); err != nil {
+		return err
+	}
+	agent, release, err := resolveAgent(ctx, store, it, opts.Runner, cmd.Flags().Changed("runner"))
+	if err != nil {
+		return err
+	}
+	defer release()
+	opts.Runner, opts.Sandbox = agent.Runner, agent.Sandbox
+	runner := realDispatchCommandRunner{actor: "agent:" + agent.Name}
+	return runDispatch(ctx, store, cmd.OutOrStdout(), cwd, issueID, opts, runner, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+}
+
+// dispatchAllReady dispatches the ready issues one at a time, each after the
+// issues it depends on. An issue whose blockers are still unmerged, including
+// blockers dispatched earlier with --no-merge, is skipped; the first failed
+// dispatch stops the run.
+func dispatchAllReady(ctx context.Context, store *sqlite.Store, out io.Writer, dispatch func(issueID string) error) error {
+	ready, err := store.ListIssues(ctx, sqlite.ListFilter{Statuses: []string{issue.StatusReady}, Sort: "priority_manual"})
+	if err != nil {
+		return err
+	}
+	deps, err := store.ListIssueDependencies(ctx)
+	if err != nil {
+		return err
+	}
+	ordered := orderByDependencies(ready, deps)
+	if len(ordered) == 0 {
+		fmt.Fprintln(out, "no ready issues")
+		return nil
+	}
+	dispatched, skipped := 0, 0
+	for _, it := range ordered {
+		if err := blockedError(ctx, store, it.ID); err != nil {
+			fmt.Fprintf(out, "skip: %v\n", err)
+			skipped++
+			continue
+		}
+		fmt.Fprintf(out, "dispatch: %s %s\n", it.ID, it.Title)
+		if err := dispatch(it.ID); err != nil {
+			return err
+		}
+		dispatched++
+	}
+	fmt.Fprintf(out, "dispatched %d issues, skipped %d blocked\n", dispatched, skipped)
+	return nil
+}
+
 func runDispatch(
 	ctx context.Context,
 	store *sqlite.Store,
@@ -156,14 +257,18 @@ func runDispatch(
 		if err != nil {
 			return err
 		}
-		repoRoot = strings.TrimSpace(root)
+		repoRoot = platform.NormalizePath(strings.TrimSpace(root))
 		if repoRoot == "" {
 			return fmt.Errorf("resolve repository root")
 		}
 
 		slug := issueSlug(issueID)
-		branch = "codex/" + slug
-		worktreeDir = filepath.Join(repoRoot, ".worktree", slug)
+		prefix, err := dispatchBranchPrefix(ctx, store, issueID, cwd)
+		if err != nil {
+			return err
+		}
+		branch = prefix + slug
+		worktreeDir = filepath.Join(repoRoot, ".worktree", platform.SafeName(slug))
 		if err := os.MkdirAll(filepath.Join(repoRoot, ".worktree"), 0o755); err != nil {
 			return err
 		}
@@ -191,22 +296,27 @@ func runDispatch(
 	}
 
 	if err := runStep("run implementation runner", func() error {
-		runnerCmd := filepath.Join(worktreeDir, "exec_"+opts.Runner)
-		if _, err := os.Stat(runnerCmd); err != nil {
-			runnerCmd = "exec_" + opts.Runner
+		runnerCmd, runnerArgs := dispatchRunnerCommand(worktreeDir, issueID, opts)
+		transcript, err := openSessionTranscript(issueID)
+		if err != nil {
+			return err
 		}
-		return runner.RunInteractive(
+		sessionStdout, sessionStderr := transcript.tee(stdout, stderr)
+		runErr := runner.RunInteractive(
 			ctx,
 			worktreeDir,
 			stdin,
-			stdout,
-			stderr,
+			sessionStdout,
+			sessionStderr,
 			runnerCmd,
-			"--sandbox",
-			"danger-full-access",
-			opts.Mode,
-			issueID,
+			runnerArgs...,
 		)
+		sess, err := transcript.finish(ctx, store, issueID, sessionKindDispatch, opts.Runner, runErr)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "transcript: %s\n", sess.LogPath)
+		return runErr
 	}); err != nil {
 		return err
 	}
@@ -317,28 +427,36 @@ func ensureFinishedStatus(ctx context.Context, store *sqlite.Store) error {
 }
 
 func updateIssueStatus(ctx context.Context, store *sqlite.Store, issueID, status string) error {
-	updated, err := store.UpdateIssue(ctx, issueID, sqlite.UpdateIssueInput{Status: &status})
-	if err != nil {
-		return err
-	}
-	if err := hooks.RunEvent(ctx, store, hooks.IssueUpdated, updated.ID); err != nil {
-		return err
-	}
-	if err := hooks.RunEvent(ctx, store, hooks.IssueStatusChange, updated.ID); err != nil {
-		return err
-	}
-	if status == issue.StatusDone {
-		if err := hooks.RunEvent(ctx, store, hooks.IssueCompleted, updated.ID); err != nil {
-			return err
-		}
-	}
-	return nil
+	_, err := service.SetStatus(ctx, store, issueID, status)
+	return err
 }
 
 type ghPRListItem struct {
 	Number int `json:"number"`
 }
 
+// dispatchRunnerCommand returns the implementation runner's command line.
+// codex and claude run exec_<runner>, preferring a copy in the worktree;
+// custom runners from agent profiles run their own command line. Both get
+// the sandbox (danger-full-access unless the profile sets one), the mode,
+// and the issue ID.
+func dispatchRunnerCommand(worktreeDir, issueID string, opts dispatchOptions) (string, []string) {
+	sandbox := opts.Sandbox
+	if sandbox == "" {
+		sandbox = "danger-full-access"
+	}
+	args := []string{"--sandbox", sandbox, opts.Mode, issueID}
+	if opts.Runner != "codex" && opts.Runner != "claude" {
+		fields := strings.Fields(opts.Runner)
+		return fields[0], append(fields[1:], args...)
+	}
+	runnerCmd, ok := platform.FindExecutable(filepath.Join(worktreeDir, "exec_"+opts.Runner))
+	if !ok {
+		runnerCmd = "exec_" + opts.Runner
+	}
+	return runnerCmd, args
+}
+
 func ensureDispatchPR(ctx context.Context, runner dispatchCommandRunner, worktreeDir, branch, base, issueID, issueTitle string) (string, error) {
 	listOut, err := runner.Run(ctx, worktreeDir, "gh", "pr", "list", "--head", branch, "--state", "open", "--json", "number")
 	if err != nil {
@@ -380,6 +498,36 @@ func ensureDispatchPR(ctx context.Context, runner dispatchCommandRunner, worktre
 	return prRef, nil
 }
 
+// defaultDispatchBranchPrefix names dispatch branches of issues whose
+// project sets no prefix.
+const defaultDispatchBranchPrefix = "codex/"
+
+// dispatchBranchPrefix returns the branch prefix of the issue's project, or
+// of the project that cwd maps to (see project_paths) when the issue has
+// none.
+func dispatchBranchPrefix(ctx context.Context, store *sqlite.Store, issueID, cwd string) (string, error) {
+	key, err := store.GetIssueProject(ctx, issueID)
+	if err != nil {
+		return "", err
+	}
+	if key == "" {
+		if key, err = projectForDir(ctx, cwd); err != nil {
+			return "", err
+		}
+	}
+	if key == "" {
+		return defaultDispatchBranchPrefix, nil
+	}
+	p, err := store.GetProject(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	if p.BranchPrefix == "" {
+		return defaultDispatchBranchPrefix, nil
+	}
+	return p.BranchPrefix, nil
+}
+
 func issueSlug(issueID string) string {
 	raw := strings.ToLower(strings.TrimSpace(issueID))
 	var b strings.Builder
@@ -358,6 +358,17 @@ func TestDispatchNoChangesSkipsPRAndKeepsInProgress(t *testing.T) {
 	if !strings.Contains(out.String(), "no changes detected; skipping commit/push/pr") {
 		t.Fatalf("output should mention no-change summary, got: %q", out.String())
 	}
+
+	sessions, err := store.ListSessions(ctx, issueID)
+	if err != nil {
+		t.Fatalf("ListSessions() error: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Kind != sessionKindDispatch || sessions[0].Runner != "codex" {
+		t.Fatalf("dispatch should record one session, got: %+v", sessions)
+	}
+	if !strings.Contains(out.String(), "transcript: "+sessions[0].LogPath+"\n") {
+		t.Fatalf("output should include transcript path, got: %q", out.String())
+	}
 }
 
 func setupDispatchTest(t *testing.T) (context.Context, *sqlite.Store, string, string, string) {
@@ -386,3 +397,55 @@ func setupDispatchTest(t *testing.T) (context.Context, *sqlite.Store, string, st
 	repoRoot := t.TempDir()
 	return ctx, store, repoRoot, created.ID, created.Title
 }
+
+func TestDispatchAllReadyFollowsDependencies(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	create := func(title, status string) string {
+		t.Helper()
+		it, err := store.CreateIssue(ctx, issue.Item{Title: title, Status: status, Priority: "none"})
+		if err != nil {
+			t.Fatalf("CreateIssue() error: %v", err)
+		}
+		return it.ID
+	}
+	ui := create("UI on top of the API", issue.StatusReady)
+	api := create("API", issue.StatusReady)
+	docs := create("Docs", issue.StatusReady)
+	external := create("Schema migration", issue.StatusInProgress)
+	for _, d := range [][2]string{{ui, api}, {docs, external}} {
+		if err := store.AddIssueDependency(ctx, d[0], d[1]); err != nil {
+			t.Fatalf("AddIssueDependency(%s, %s) error: %v", d[0], d[1], err)
+		}
+	}
+	if err := store.AddIssueDependency(ctx, api, ui); err == nil || !strings.Contains(err.Error(), "dependency cycle") {
+		t.Fatalf("cycle error = %v", err)
+	}
+	if err := blockedError(ctx, store, ui); err == nil || err.Error() != ui+" is blocked by unmerged "+api+" (ready)" {
+		t.Fatalf("blockedError() = %v", err)
+	}
+
+	var order []string
+	var out bytes.Buffer
+	err = dispatchAllReady(ctx, store, &out, func(id string) error {
+		order = append(order, id)
+		return updateIssueStatus(ctx, store, id, issue.StatusDone)
+	})
+	if err != nil {
+		t.Fatalf("dispatchAllReady() error: %v", err)
+	}
+	if want := []string{api, ui}; !slices.Equal(order, want) {
+		t.Fatalf("dispatch order = %v, want %v\n%s", order, want, out.String())
+	}
+	if !strings.Contains(out.String(), "skip: "+docs+" is blocked by unmerged "+external+" (in_progress)") ||
+		!strings.Contains(out.String(), "dispatched 2 issues, skipped 1 blocked") {
+		t.Fatalf("output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+// walWarnBytes is the WAL size above which doctor suggests a checkpoint.
+const walWarnBytes = 64 << 20
+
+func newDoctorCmd() *cobra.Command {
+	var checkpoint bool
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the database and report its size",
+		Long:  "Check that the database opens, answers, and is migrated to the schema this build expects, and report the size of the database file and its write-ahead log. With --checkpoint, the WAL is first copied into the database and truncated.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+			out := cmd.OutOrStdout()
+
+			path, err := sqlite.DBPath()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "database: %s\n", path)
+			if err := store.Ping(ctx); err != nil {
+				return fmt.Errorf("connection check failed: %w", err)
+			}
+			fmt.Fprintln(out, "connection: ok")
+			version, err := store.SchemaVersion(ctx)
+			if err != nil {
+				return err
+			}
+			if version == sqlite.SchemaVersion {
+				fmt.Fprintf(out, "schema: %d\n", version)
+			} else {
+				fmt.Fprintf(out, "schema: %d (this build expects %d)\n", version, sqlite.SchemaVersion)
+			}
+
+			if checkpoint {
+				res, err := store.Checkpoint(ctx)
+				if err != nil {
+					return err
+				}
+				if res.Busy {
+					fmt.Fprintf(out, "checkpoint: blocked by readers after %d of %d frames\n", res.Checkpointed, res.LogFrames)
+				} else {
+					fmt.Fprintf(out, "checkpoint: ok (%d frames)\n", res.Checkpointed)
+				}
+			}
+			dbSize, walSize, err := sqlite.FileSizes()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "db size: %s\n", formatBytes(dbSize))
+			fmt.Fprintf(out, "wal size: %s\n", formatBytes(walSize))
+			if walSize > walWarnBytes && !checkpoint {
+				fmt.Fprintln(out, "hint: the WAL is large; run `track doctor --checkpoint` or keep `track serve` running to truncate it")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&checkpoint, "checkpoint", false, "Checkpoint and truncate the WAL first")
+	return cmd
+}
+
+// formatBytes prints n in the largest binary unit that keeps it at least 1.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestDoctor(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := newRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%v: %v\n%s", args, err, out.String())
+		}
+		return out.String()
+	}
+
+	run("new", "Fix login")
+	out := run("doctor")
+	for _, want := range []string{"connection: ok\n", fmt.Sprintf("schema: %d\n", sqlite.SchemaVersion), "db size: ", "wal size: "} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("doctor output missing %q:\n%s", want, out)
+		}
+	}
+
+	out = run("doctor", "--checkpoint")
+	if !strings.Contains(out, "checkpoint: ok") || !strings.Contains(out, "wal size: 0 B\n") {
+		t.Fatalf("doctor --checkpoint should truncate the WAL:\n%s", out)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[int64]string{0: "0 B", 1023: "1023 B", 1024: "1.0 KiB", 5 << 20: "5.0 MiB", 3 << 30: "3.0 GiB"} {
+		if got := formatBytes(n); got != want {
+			t.Fatalf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/service"
+	"github.com/myuon/track/internal/stats"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func newEstimateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "estimate",
+		Short: "Calibrate estimates against logged time",
+	}
+	cmd.AddCommand(newEstimateReportCmd())
+	cmd.AddCommand(newEstimateSuggestCmd())
+	return cmd
+}
+
+func newEstimateReportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "report",
+		Short: "Show how far logged time strays from estimates",
+		Long:  "Compare the time logged on done and archived issues with their estimates, overall and per label and assignee. The factor is logged over estimated time, so x1.5 means work took half again as long as estimated. Issues without an estimate or logged time are left out.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			c, err := stats.Calibrate(ctx, store)
+			if err != nil {
+				return err
+			}
+			if c.Overall.Issues == 0 {
+				return fmt.Errorf("no closed issues with both an estimate and logged time")
+			}
+			out := cmd.OutOrStdout()
+			writeBias(out, "overall", c.Overall)
+			for _, b := range c.Labels {
+				writeBias(out, "label", b)
+			}
+			for _, b := range c.Assignees {
+				writeBias(out, "assignee", b)
+			}
+			return nil
+		},
+	}
+}
+
+func writeBias(out io.Writer, kind string, b stats.Bias) {
+	fmt.Fprintf(out, "%s\t%s\t%s\t%d issues\testimated %s\tlogged %s\n", kind, b.Key, stats.FormatFactor(b.Factor()), b.Issues,
+		issue.FormatEstimate(b.Estimated.Round(time.Minute)), issue.FormatEstimate(b.Logged.Round(time.Minute)))
+}
+
+func newEstimateSuggestCmd() *cobra.Command {
+	var apply bool
+	cmd := &cobra.Command{
+		Use:   "suggest <id>",
+		Short: "Suggest an estimate adjusted for past bias",
+		Long:  fmt.Sprintf("Scale an issue's estimate by the average bias of its assignee and labels (see `track estimate report`), counting only those with at least %d closed issues and falling back to the overall bias. With --apply the suggestion becomes the issue's estimate.", stats.MinSamples),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			it, err := store.GetIssue(ctx, normalizeIssueIDArg(args[0]))
+			if err != nil {
+				return err
+			}
+			c, err := stats.Calibrate(ctx, store)
+			if err != nil {
+				return err
+			}
+			s, err := c.Suggest(it)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if len(s.Basis) == 0 {
+				fmt.Fprintf(out, "%s: keep %s (fewer than %d closed issues with logged time to calibrate against)\n", it.ID, issue.FormatEstimate(s.Estimate), stats.MinSamples)
+				return nil
+			}
+			fmt.Fprintf(out, "%s: %s -> %s (%s from %s)\n", it.ID, issue.FormatEstimate(s.Estimate), issue.FormatEstimate(s.Suggested), stats.FormatFactor(s.Factor), strings.Join(s.Basis, ", "))
+			if !apply || s.Suggested == s.Estimate {
+				return nil
+			}
+			v := issue.FormatEstimate(s.Suggested)
+			_, err = service.UpdateIssue(ctx, store, it.ID, service.Update{UpdateIssueInput: sqlite.UpdateIssueInput{Estimate: &v}})
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&apply, "apply", false, "Set the suggested estimate on the issue")
+	return cmd
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestEstimateSuggestApply(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	for range 3 {
+		it, err := store.CreateIssue(ctx, issue.Item{Title: "Bug", Status: issue.StatusTodo, Priority: "none", Labels: []string{"bug"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		done, estimate := issue.StatusDone, "1h"
+		if _, err := store.UpdateIssue(ctx, it.ID, sqlite.UpdateIssueInput{Status: &done, Estimate: &estimate}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.LogTime(ctx, it.ID, start, start.Add(90*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	open, err := store.CreateIssue(ctx, issue.Item{Title: "New bug", Status: issue.StatusTodo, Priority: "none", Labels: []string{"bug"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	estimate := "2h"
+	if _, err := store.UpdateIssue(ctx, open.ID, sqlite.UpdateIssueInput{Estimate: &estimate}); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+	out, err := run("estimate", "report")
+	if err != nil || !strings.Contains(out, "label\tbug\tx1.5\t3 issues\testimated 3h\tlogged 4h30m\n") {
+		t.Fatalf("estimate report = %q, %v", out, err)
+	}
+	out, err = run("estimate", "suggest", open.ID, "--apply")
+	if err != nil || out != open.ID+": 2h -> 3h (x1.5 from label bug (3 issues))\n" {
+		t.Fatalf("estimate suggest = %q, %v", out, err)
+	}
+	if out, err := run("show", open.ID); err != nil || !strings.Contains(out, "estimate: 3h\n") {
+		t.Fatalf("show after --apply = %q, %v", out, err)
+	}
+}
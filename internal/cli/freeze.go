@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func newFreezeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "freeze [reason]",
+		Short: "Pause automation until track thaw",
+		Long:  "Pause everything that changes issues on its own: hooks, rules and auto-organize, autopilot, gh watch, and GitHub webhooks (answered with 503 so they can be redelivered). Commands you run yourself, and all reads, keep working.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			reason := ""
+			if len(args) > 0 {
+				reason = strings.TrimSpace(args[0])
+			}
+			f, err := store.FreezeAutomation(ctx, reason)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "frozen by %s since %s\n", f.By, f.At)
+			if f.Reason != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "reason: %s\n", f.Reason)
+			}
+			return nil
+		},
+	}
+}
+
+func newThawCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "thaw",
+		Short: "Resume automation paused by track freeze",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			thawed, err := store.ThawAutomation(ctx)
+			if err != nil {
+				return err
+			}
+			if !thawed {
+				fmt.Fprintln(cmd.OutOrStdout(), "not frozen")
+				return nil
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+}
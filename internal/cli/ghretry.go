@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	appconfig "github.com/myuon/track/internal/config"
+)
+
+// maxGHBackoff caps the delay between two attempts of a gh command.
+const maxGHBackoff = time.Minute
+
+// ghSleep waits between attempts; tests replace it.
+var ghSleep = func(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// ghRetryPolicy is how many times a failed gh command is retried and how long
+// to wait before the first retry; the wait doubles after each attempt.
+type ghRetryPolicy struct {
+	retries int
+	backoff time.Duration
+}
+
+// loadGHRetryPolicy reads gh_retries and gh_retry_backoff. An unreadable
+// config falls back to the defaults.
+func loadGHRetryPolicy() ghRetryPolicy {
+	cfg, err := appconfig.Load()
+	if err != nil {
+		cfg = appconfig.Default()
+	}
+	backoff, err := time.ParseDuration(cfg.GHRetryBackoff)
+	if err != nil || backoff <= 0 {
+		backoff = time.Second
+	}
+	return ghRetryPolicy{retries: cfg.GHRetries, backoff: backoff}
+}
+
+// ghOutput runs gh and returns its stdout, retrying transient failures of
+// commands that only read.
+func ghOutput(ctx context.Context, args ...string) ([]byte, error) {
+	return retryGH(ctx, ghRetryPolicyFor(args), func() ([]byte, []byte, error) {
+		return captureOutput(exec.CommandContext(ctx, "gh", args...), false)
+	})
+}
+
+// ghCombinedOutput runs gh and returns stdout and stderr together, retrying
+// transient failures of commands that only read.
+func ghCombinedOutput(ctx context.Context, args ...string) ([]byte, error) {
+	return retryGH(ctx, ghRetryPolicyFor(args), func() ([]byte, []byte, error) {
+		return captureOutput(exec.CommandContext(ctx, "gh", args...), true)
+	})
+}
+
+// ghRetryPolicyFor is the configured policy for gh commands that only read
+// from GitHub, and no retries for the rest: a command that creates an issue,
+// a release, or a PR may time out after GitHub has acted on it, and running
+// it again would do it twice.
+func ghRetryPolicyFor(args []string) ghRetryPolicy {
+	policy := loadGHRetryPolicy()
+	if !ghReadOnly(args) {
+		policy.retries = 0
+	}
+	return policy
+}
+
+// ghReadOnlyCommands are the gh subcommands that never change anything.
+var ghReadOnlyCommands = map[string]bool{
+	"pr view": true, "pr checks": true, "pr list": true, "pr status": true, "pr diff": true,
+	"issue view": true, "issue list": true, "issue status": true,
+	"run view": true, "run list": true,
+	"release view": true, "release list": true,
+	"repo view": true, "auth status": true,
+}
+
+// ghReadOnly reports whether gh args only read. gh api counts when it makes
+// a GET: it switches to POST as soon as fields or a body are given.
+func ghReadOnly(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	if args[0] != "api" {
+		return len(args) > 1 && ghReadOnlyCommands[args[0]+" "+args[1]]
+	}
+	for i, a := range args[1:] {
+		name, value, hasValue := strings.Cut(a, "=")
+		switch name {
+		case "-f", "-F", "--field", "--raw-field", "--input":
+			return false
+		case "-X", "--method":
+			if !hasValue {
+				if i+2 >= len(args) {
+					return false
+				}
+				value = args[i+2]
+			}
+			if !strings.EqualFold(value, "GET") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// captureOutput runs cmd and returns its stdout, with stderr interleaved when
+// combined, and its stderr alone, which is all isTransientGHError looks at.
+func captureOutput(cmd *exec.Cmd, combined bool) ([]byte, []byte, error) {
+	var out, stderr lockedBuffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if combined {
+		cmd.Stderr = io.MultiWriter(&out, &stderr)
+	}
+	err := cmd.Run()
+	return out.Bytes(), stderr.Bytes(), err
+}
+
+// lockedBuffer is a bytes.Buffer that several goroutines may write to, such
+// as a command's stdout and stderr copiers.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Bytes()
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// retryGH calls run until it succeeds, fails permanently, or runs out of
+// retries. run returns the output callers see and, separately, stderr:
+// only stderr decides whether a failure is transient, since stdout carries
+// things like check names and logs. The last attempt's output and error are
+// returned as they are, so callers report failures the same way with or
+// without retries.
+func retryGH(ctx context.Context, policy ghRetryPolicy, run func() (out, stderr []byte, err error)) ([]byte, error) {
+	delay := policy.backoff
+	for attempt := 0; ; attempt++ {
+		out, stderr, err := run()
+		if err == nil || attempt >= policy.retries || !isTransientGHError(stderr, err) {
+			return out, err
+		}
+		if err := ghSleep(ctx, delay); err != nil {
+			return out, fmt.Errorf("%w (retry cancelled: %v)", errorWithOutput(out, err), err)
+		}
+		delay = min(delay*2, maxGHBackoff)
+	}
+}
+
+// errorWithOutput keeps gh's explanation when retrying is cut short.
+func errorWithOutput(out []byte, err error) error {
+	if msg := strings.TrimSpace(string(out)); msg != "" {
+		return fmt.Errorf("%s", msg)
+	}
+	return err
+}
+
+// ghPermanentMarkers identify failures a retry cannot fix: bad credentials,
+// missing permissions, and unknown repositories or PRs.
+var ghPermanentMarkers = []string{
+	"http 401", "bad credentials", "gh auth login", "authentication required",
+	"http 403", "resource not accessible", "must have admin rights",
+	"http 404", "not found", "could not resolve to a", "no pull requests found",
+	"http 422", "validation failed",
+}
+
+// ghTransientMarkers identify network trouble, GitHub outages, and rate
+// limits.
+var ghTransientMarkers = []string{
+	"rate limit", "abuse detection", "http 429",
+	"http 500", "http 502", "http 503", "http 504", "bad gateway", "service unavailable", "gateway timeout",
+	"timeout", "timed out", "connection reset", "connection refused", "broken pipe", "unexpected eof",
+	"no such host", "could not resolve host", "tls handshake", "temporarily unavailable", "network is unreachable",
+}
+
+// isTransientGHError reports whether a failed gh command is worth retrying,
+// judging by its stderr. Rate limits count as transient even though GitHub
+// reports them as 403s; anything unrecognized, such as failed checks, is
+// treated as permanent.
+func isTransientGHError(stderr []byte, err error) bool {
+	text := string(stderr)
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) && err != nil {
+		text += "\n" + err.Error()
+	}
+	text = strings.ToLower(text)
+	if strings.Contains(text, "rate limit") {
+		return true
+	}
+	for _, m := range ghPermanentMarkers {
+		if strings.Contains(text, m) {
+			return false
+		}
+	}
+	for _, m := range ghTransientMarkers {
+		if strings.Contains(text, m) {
+			return true
+		}
+	}
+	return false
+}
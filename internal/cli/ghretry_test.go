@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryGHBacksOffOnTransientFailures(t *testing.T) {
+	var waits []time.Duration
+	orig := ghSleep
+	ghSleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	t.Cleanup(func() { ghSleep = orig })
+
+	policy := ghRetryPolicy{retries: 3, backoff: time.Second}
+	failing := errors.New("exit status 1")
+
+	calls := 0
+	out, err := retryGH(context.Background(), policy, func() ([]byte, []byte, error) {
+		calls++
+		if calls < 3 {
+			msg := []byte("HTTP 502: Bad Gateway (https://api.github.com/graphql)")
+			return msg, msg, failing
+		}
+		return []byte("ok"), nil, nil
+	})
+	if err != nil || string(out) != "ok" || calls != 3 {
+		t.Fatalf("retryGH() = %q, %v after %d calls; want ok after 3", out, err, calls)
+	}
+	if want := []time.Duration{time.Second, 2 * time.Second}; !slices.Equal(waits, want) {
+		t.Fatalf("waits = %v, want %v", waits, want)
+	}
+
+	waits, calls = nil, 0
+	_, err = retryGH(context.Background(), policy, func() ([]byte, []byte, error) {
+		calls++
+		return nil, []byte("API rate limit exceeded"), failing
+	})
+	if err == nil || calls != 4 {
+		t.Fatalf("rate limited command: err=%v calls=%d, want an error after 4 calls", err, calls)
+	}
+
+	calls = 0
+	_, err = retryGH(context.Background(), policy, func() ([]byte, []byte, error) {
+		calls++
+		return nil, []byte("HTTP 401: Bad credentials"), failing
+	})
+	if err == nil || calls != 1 {
+		t.Fatalf("auth failure: err=%v calls=%d, want no retries", err, calls)
+	}
+
+	// Output on stdout, such as a check named timeout-test failing, says
+	// nothing about the connection.
+	calls = 0
+	_, err = retryGH(context.Background(), policy, func() ([]byte, []byte, error) {
+		calls++
+		return []byte("timeout-test\tfail\t1m\thttps://github.com/o/r/actions/runs/1"), nil, failing
+	})
+	if err == nil || calls != 1 {
+		t.Fatalf("failed check named timeout-test: err=%v calls=%d, want no retries", err, calls)
+	}
+}
+
+func TestGHRetryPolicyRetriesOnlyReads(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	cases := map[string]bool{
+		"pr view 12 --json state":                       true,
+		"pr checks 12 --watch":                          true,
+		"run view 99 --log-failed":                      true,
+		"api repos/o/r/branches/main/protection":        true,
+		"api -X GET repos/o/r/issues":                   true,
+		"api --method=get repos/o/r/issues":             true,
+		"issue create --title t --body b":               false,
+		"release create v1 --notes n":                   false,
+		"pr create --fill":                              false,
+		"pr merge 12 --auto --squash":                   false,
+		"api -X POST repos/o/r/issues":                  false,
+		"api repos/o/r/issues -f title=t":               false,
+		"api graphql --raw-field query=mutation{x}":     false,
+		"api --input body.json repos/o/r/issues/1/pins": false,
+	}
+	for cmd, want := range cases {
+		if got := ghRetryPolicyFor(strings.Fields(cmd)).retries > 0; got != want {
+			t.Fatalf("ghRetryPolicyFor(%q) retries = %v, want %v", cmd, got, want)
+		}
+	}
+}
+
+func TestIsTransientGHError(t *testing.T) {
+	failing := errors.New("exit status 1")
+	cases := map[string]bool{
+		"API rate limit exceeded for user":                           true,
+		"HTTP 403: You have exceeded a secondary rate limit":         true,
+		"dial tcp: lookup api.github.com: no such host":              true,
+		"Post \"https://api.github.com/graphql\": net/http: timeout": true,
+		"HTTP 404: Not Found (https://api.github.com/repos/o/r)":     false,
+		"To get started with GitHub CLI, please run:  gh auth login": false,
+		"some checks were not successful":                            false,
+	}
+	for out, want := range cases {
+		if got := isTransientGHError([]byte(out), failing); got != want {
+			t.Fatalf("isTransientGHError(%q) = %v, want %v", out, got, want)
+		}
+	}
+}
@@ -131,3 +131,44 @@ func hasGitRef(ctx context.Context, ref string) bool {
 	cmd := exec.CommandContext(ctx, "git", "show-ref", "--verify", "--quiet", ref)
 	return cmd.Run() == nil
 }
+
+// gitOriginURL returns the URL of the current repository's origin remote;
+// tests replace it.
+var gitOriginURL = func(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, "git", "remote", "get-url", "origin").Output()
+	if err != nil {
+		return "", fmt.Errorf("read git remote origin: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// repoFromRemoteURL turns a remote URL such as git@github.com:owner/name.git
+// or https://github.com/owner/name into owner/name, the form gh --repo takes.
+// Other hosts keep their host prefix (host/owner/name).
+func repoFromRemoteURL(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	var host, path string
+	if scheme, rest, ok := strings.Cut(raw, "://"); ok && scheme != "" {
+		hostPart, p, _ := strings.Cut(rest, "/")
+		host, path = hostPart, p
+	} else if h, p, ok := strings.Cut(raw, ":"); ok {
+		host, path = h, p
+	} else {
+		return "", false
+	}
+	if _, h, ok := strings.Cut(host, "@"); ok {
+		host = h
+	}
+	if h, _, ok := strings.Cut(host, ":"); ok {
+		host = h
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	parts := strings.Split(path, "/")
+	if host == "" || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+	if host == "github.com" {
+		return path, true
+	}
+	return host + "/" + path, true
+}
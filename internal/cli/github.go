@@ -8,13 +8,14 @@ import (
 	"io"
 	"os/exec"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	appconfig "github.com/myuon/track/internal/config"
-	"github.com/myuon/track/internal/hooks"
 	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/service"
 	"github.com/myuon/track/internal/store/sqlite"
 	"github.com/spf13/cobra"
 )
@@ -110,7 +111,7 @@ func newGHIssueCreateCmd() *cobra.Command {
 			}
 			argsCreate = append(argsCreate, "--json", "number,url")
 
-			raw, err := exec.CommandContext(ctx, "gh", argsCreate...).CombinedOutput()
+			raw, err := ghCombinedOutput(ctx, argsCreate...)
 			if err != nil {
 				msg := strings.TrimSpace(string(raw))
 				if msg == "" {
@@ -138,11 +139,14 @@ func newGHIssueCreateCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&repoOverride, "repo", "", "owner/name")
+	cmd.Flags().StringVar(&repoOverride, "repo", "", "owner/name (default: gh_repo, then the origin remote)")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show payload without creating GitHub issue")
 	return cmd
 }
 
+// resolveGitHubRepo picks the repository for gh: --repo, then gh_repo, then
+// the current repository's origin remote. It is empty when none of them
+// names one, leaving gh to decide.
 func resolveGitHubRepo(repoOverride string) (string, error) {
 	if strings.TrimSpace(repoOverride) != "" {
 		return strings.TrimSpace(repoOverride), nil
@@ -151,7 +155,15 @@ func resolveGitHubRepo(repoOverride string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return strings.TrimSpace(cfg.GHRepo), nil
+	if repo := strings.TrimSpace(cfg.GHRepo); repo != "" {
+		return repo, nil
+	}
+	url, err := gitOriginURL(context.Background())
+	if err != nil {
+		return "", nil
+	}
+	repo, _ := repoFromRemoteURL(url)
+	return repo, nil
 }
 
 func formatGitHubIssueBody(it issue.Item) string {
@@ -193,7 +205,11 @@ func newGHLinkCmd() *cobra.Command {
 			if _, err := store.GetIssue(ctx, issueID); err != nil {
 				return err
 			}
-			if err := store.UpsertGitHubLink(ctx, issueID, normalizePRRef(prRef), repo); err != nil {
+			resolvedRepo, err := resolveGitHubRepo(repo)
+			if err != nil {
+				return err
+			}
+			if err := store.UpsertGitHubLink(ctx, issueID, normalizePRRef(prRef), resolvedRepo); err != nil {
 				return err
 			}
 			fmt.Fprintln(cmd.OutOrStdout(), "ok")
@@ -201,7 +217,7 @@ func newGHLinkCmd() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVar(&prRef, "pr", "", "PR number or URL")
-	cmd.Flags().StringVar(&repo, "repo", "", "owner/name")
+	cmd.Flags().StringVar(&repo, "repo", "", "owner/name (default: gh_repo, then the origin remote)")
 	return cmd
 }
 
@@ -226,6 +242,11 @@ func newGHStatusCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if link.Repo == "" {
+				if link.Repo, err = resolveGitHubRepo(""); err != nil {
+					return err
+				}
+			}
 			checks, err := fetchPRChecks(ctx, link, "")
 			if err != nil {
 				return err
@@ -253,6 +274,7 @@ func newGHStatusCmd() *cobra.Command {
 func newGHWatchCmd() *cobra.Command {
 	var repo string
 	var interval string
+	var checkpointEvery time.Duration
 
 	cmd := &cobra.Command{
 		Use:   "watch",
@@ -268,6 +290,7 @@ func newGHWatchCmd() *cobra.Command {
 			}
 
 			ctx := cmd.Context()
+			startDBMaintenance(ctx, checkpointEvery, cmd.ErrOrStderr())
 			seenFailures := map[string]struct{}{}
 			if err := runGHWatchOnce(ctx, repo, cmd.OutOrStdout(), seenFailures); err != nil {
 				return err
@@ -289,15 +312,19 @@ func newGHWatchCmd() *cobra.Command {
 	}
 	cmd.Flags().StringVar(&repo, "repo", "", "owner/name")
 	cmd.Flags().StringVar(&interval, "interval", "30s", "Polling interval")
+	addCheckpointFlag(cmd, &checkpointEvery)
 	return cmd
 }
 
 func newGHAutoMergeCmd() *cobra.Command {
 	var method string
 	var repoOverride string
+	var wait bool
+	var interval string
 	cmd := &cobra.Command{
 		Use:   "auto-merge <issue_id>",
 		Short: "Enable auto-merge for linked PR",
+		Long:  "Enable auto-merge for the issue's linked PR. The base branch's required checks and review rules are checked first, and each condition still blocking the merge is listed. With --wait, blocks until the PR merges and then marks the issue done.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if _, err := exec.LookPath("gh"); err != nil {
@@ -306,6 +333,10 @@ func newGHAutoMergeCmd() *cobra.Command {
 			if method != "squash" && method != "merge" && method != "rebase" {
 				return fmt.Errorf("invalid --method: %s", method)
 			}
+			dur, err := time.ParseDuration(interval)
+			if err != nil || dur <= 0 {
+				return fmt.Errorf("invalid interval: %s", interval)
+			}
 
 			ctx := context.Background()
 			store, err := sqlite.Open(ctx)
@@ -325,6 +356,10 @@ func newGHAutoMergeCmd() *cobra.Command {
 			if repoOverride != "" {
 				repo = repoOverride
 			}
+			out := cmd.OutOrStdout()
+			if err := reportMergeConditions(ctx, out, link, repo); err != nil {
+				return err
+			}
 			argsMerge := []string{"pr", "merge", pr, "--auto"}
 			switch method {
 			case "squash":
@@ -338,19 +373,215 @@ func newGHAutoMergeCmd() *cobra.Command {
 				argsMerge = append(argsMerge, "--repo", repo)
 			}
 
-			raw, err := exec.CommandContext(ctx, "gh", argsMerge...).CombinedOutput()
+			raw, err := ghCombinedOutput(ctx, argsMerge...)
 			if err != nil {
 				return fmt.Errorf("enable auto-merge failed: %s", strings.TrimSpace(string(raw)))
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "auto-merge enabled for pr %s (method=%s)\n", pr, method)
-			return nil
+			fmt.Fprintf(out, "auto-merge enabled for pr %s (method=%s)\n", pr, method)
+			if !wait {
+				return nil
+			}
+			return waitForPRMerge(ctx, out, store, link, repo, dur)
 		},
 	}
 	cmd.Flags().StringVar(&method, "method", "squash", "Merge method: squash|merge|rebase")
 	cmd.Flags().StringVar(&repoOverride, "repo", "", "owner/name")
+	cmd.Flags().BoolVar(&wait, "wait", false, "Block until the PR merges, then mark the issue done")
+	cmd.Flags().StringVar(&interval, "interval", "30s", "Polling interval for --wait")
 	return cmd
 }
 
+type ghPRMergeInfo struct {
+	BaseRefName    string `json:"baseRefName"`
+	ReviewDecision string `json:"reviewDecision"`
+	IsDraft        bool   `json:"isDraft"`
+	Mergeable      string `json:"mergeable"`
+}
+
+// ghBranchProtection is the part of GitHub's branch protection response that
+// decides whether a PR may merge.
+type ghBranchProtection struct {
+	RequiredStatusChecks *struct {
+		Contexts []string `json:"contexts"`
+		Checks   []struct {
+			Context string `json:"context"`
+		} `json:"checks"`
+	} `json:"required_status_checks"`
+	RequiredPullRequestReviews *struct {
+		RequiredApprovingReviewCount int  `json:"required_approving_review_count"`
+		RequireCodeOwnerReviews      bool `json:"require_code_owner_reviews"`
+	} `json:"required_pull_request_reviews"`
+}
+
+// requiredChecks lists the status checks the branch requires, each once.
+func (p ghBranchProtection) requiredChecks() []string {
+	if p.RequiredStatusChecks == nil {
+		return nil
+	}
+	names := append([]string{}, p.RequiredStatusChecks.Contexts...)
+	for _, c := range p.RequiredStatusChecks.Checks {
+		names = append(names, c.Context)
+	}
+	sort.Strings(names)
+	return slices.Compact(names)
+}
+
+// reportMergeConditions prints the base branch and every condition that
+// still blocks the PR from merging. Branch protection needs admin access to
+// read; without it only the PR's own review decision and checks are used.
+func reportMergeConditions(ctx context.Context, out io.Writer, link sqlite.GitHubLink, repo string) error {
+	pr := normalizePRRef(link.PRRef)
+	args := []string{"pr", "view", pr, "--json", "baseRefName,reviewDecision,isDraft,mergeable"}
+	if repo != "" {
+		args = append(args, "--repo", repo)
+	}
+	raw, err := ghOutput(ctx, args...)
+	if err != nil {
+		return fmt.Errorf("gh pr view failed for %s: %w", pr, err)
+	}
+	var info ghPRMergeInfo
+	if err := json.Unmarshal(raw, &info); err != nil {
+		return fmt.Errorf("decode gh response: %w", err)
+	}
+	protection, err := fetchBranchProtection(ctx, repo, info.BaseRefName)
+	if err != nil {
+		fmt.Fprintf(out, "note: %v\n", err)
+	}
+	checks, err := fetchPRChecks(ctx, link, repo)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "base: %s\n", info.BaseRefName)
+	unmet := unmetMergeConditions(info, protection, checks)
+	if len(unmet) == 0 {
+		fmt.Fprintln(out, "all required conditions are met")
+	}
+	for _, u := range unmet {
+		fmt.Fprintf(out, "unmet: %s\n", u)
+	}
+	return nil
+}
+
+// fetchBranchProtection reads the protection rules of branch. An unprotected
+// branch has no rules and is not an error.
+func fetchBranchProtection(ctx context.Context, repo, branch string) (ghBranchProtection, error) {
+	if repo == "" {
+		repo = "{owner}/{repo}"
+	}
+	raw, err := ghCombinedOutput(ctx, "api", fmt.Sprintf("repos/%s/branches/%s/protection", repo, branch))
+	if err != nil {
+		msg := strings.TrimSpace(string(raw))
+		if strings.Contains(msg, "Branch not protected") {
+			return ghBranchProtection{}, nil
+		}
+		return ghBranchProtection{}, fmt.Errorf("cannot read branch protection for %s: %s", branch, msg)
+	}
+	var p ghBranchProtection
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return ghBranchProtection{}, fmt.Errorf("decode branch protection: %w", err)
+	}
+	return p, nil
+}
+
+// unmetMergeConditions lists what still blocks the merge: draft state,
+// conflicts, reviews, and required checks that are missing, pending, or
+// failed. Without branch protection, any failing check is reported.
+func unmetMergeConditions(info ghPRMergeInfo, p ghBranchProtection, checks []ghCheck) []string {
+	var unmet []string
+	if info.IsDraft {
+		unmet = append(unmet, "pr is a draft")
+	}
+	if strings.EqualFold(info.Mergeable, "CONFLICTING") {
+		unmet = append(unmet, "pr has merge conflicts")
+	}
+	switch strings.ToUpper(info.ReviewDecision) {
+	case "CHANGES_REQUESTED":
+		unmet = append(unmet, "changes were requested in review")
+	case "REVIEW_REQUIRED":
+		reason := "review required"
+		if r := p.RequiredPullRequestReviews; r != nil && r.RequiredApprovingReviewCount > 0 {
+			reason = fmt.Sprintf("needs %d approving review(s)", r.RequiredApprovingReviewCount)
+			if r.RequireCodeOwnerReviews {
+				reason += " including a code owner"
+			}
+		}
+		unmet = append(unmet, reason)
+	}
+
+	states := map[string]string{}
+	for _, c := range checks {
+		states[c.Name] = c.State
+	}
+	required := p.requiredChecks()
+	if required == nil {
+		for _, c := range checks {
+			required = append(required, c.Name)
+		}
+		sort.Strings(required)
+		required = slices.Compact(required)
+	}
+	for _, name := range required {
+		state, ok := states[name]
+		switch {
+		case !ok:
+			if p.RequiredStatusChecks != nil {
+				unmet = append(unmet, fmt.Sprintf("required check %s has not reported", name))
+			}
+		case isFailureState(state):
+			unmet = append(unmet, fmt.Sprintf("check %s failed", name))
+		case isPendingState(state):
+			if p.RequiredStatusChecks != nil {
+				unmet = append(unmet, fmt.Sprintf("required check %s is %s", name, strings.ToLower(state)))
+			}
+		}
+	}
+	return unmet
+}
+
+// waitForPRMerge polls until the PR merges and marks the issue done. A PR
+// closed without merging ends the wait with an error.
+func waitForPRMerge(ctx context.Context, out io.Writer, store *sqlite.Store, link sqlite.GitHubLink, repo string, interval time.Duration) error {
+	fmt.Fprintf(out, "waiting for pr %s to merge\n", normalizePRRef(link.PRRef))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		state, err := fetchPRState(ctx, link, repo)
+		if err != nil {
+			return err
+		}
+		if state.MergedAt != nil || strings.EqualFold(state.State, "MERGED") {
+			return markPRMerged(ctx, out, store, link)
+		}
+		if strings.EqualFold(state.State, "CLOSED") {
+			return fmt.Errorf("pr %s was closed without merging", normalizePRRef(link.PRRef))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// markPRMerged records the merge on the linked issue and marks it done.
+func markPRMerged(ctx context.Context, out io.Writer, store *sqlite.Store, link sqlite.GitHubLink) error {
+	current, err := store.GetIssue(ctx, link.IssueID)
+	if err != nil {
+		return err
+	}
+	if current.Status != issue.StatusDone {
+		if err := store.RecordActivity(ctx, sqlite.ActivityPRMerged, link.IssueID, link.PRRef); err != nil {
+			return err
+		}
+	}
+	if _, err := service.SetStatus(ctx, store, link.IssueID, issue.StatusDone); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "updated %s -> done (pr %s)\n", link.IssueID, link.PRRef)
+	return nil
+}
+
 func runGHWatchOnce(ctx context.Context, repo string, out io.Writer, seenFailures map[string]struct{}) error {
 	store, err := sqlite.Open(ctx)
 	if err != nil {
@@ -358,6 +589,12 @@ func runGHWatchOnce(ctx context.Context, repo string, out io.Writer, seenFailure
 	}
 	defer store.Close()
 
+	if f, frozen, err := store.AutomationFrozen(ctx); err != nil {
+		return err
+	} else if frozen {
+		fmt.Fprintf(out, "skipped: %v\n", f)
+		return nil
+	}
 	links, err := store.ListGitHubLinks(ctx, "")
 	if err != nil {
 		return err
@@ -394,46 +631,41 @@ func runGHWatchOnce(ctx context.Context, repo string, out io.Writer, seenFailure
 		if !merged {
 			continue
 		}
-		status := issue.StatusDone
-		updated, err := store.UpdateIssue(ctx, link.IssueID, sqlite.UpdateIssueInput{Status: &status})
-		if err != nil {
-			return err
-		}
-		if err := hooks.RunEvent(ctx, store, hooks.IssueUpdated, updated.ID); err != nil {
-			return err
-		}
-		if err := hooks.RunEvent(ctx, store, hooks.IssueStatusChange, updated.ID); err != nil {
-			return err
-		}
-		if err := hooks.RunEvent(ctx, store, hooks.IssueCompleted, updated.ID); err != nil {
+		if err := markPRMerged(ctx, out, store, link); err != nil {
 			return err
 		}
-		fmt.Fprintf(out, "updated %s -> done (pr %s)\n", link.IssueID, link.PRRef)
 	}
 	return nil
 }
 
 func fetchPRMerged(ctx context.Context, link sqlite.GitHubLink, repoOverride string) (bool, error) {
+	state, err := fetchPRState(ctx, link, repoOverride)
+	if err != nil {
+		return false, err
+	}
+	if state.MergedAt != nil {
+		return true, nil
+	}
+	return strings.EqualFold(state.State, "MERGED"), nil
+}
+
+func fetchPRState(ctx context.Context, link sqlite.GitHubLink, repoOverride string) (ghPRState, error) {
 	pr := normalizePRRef(link.PRRef)
 	args := []string{"pr", "view", pr, "--json", "state,mergedAt"}
 	repo := repoForLink(link, repoOverride)
 	if repo != "" {
 		args = append(args, "--repo", repo)
 	}
-	cmd := exec.CommandContext(ctx, "gh", args...)
-	raw, err := cmd.Output()
+	raw, err := ghOutput(ctx, args...)
 	if err != nil {
-		return false, fmt.Errorf("gh pr view failed for %s: %w", pr, err)
+		return ghPRState{}, fmt.Errorf("gh pr view failed for %s: %w", pr, err)
 	}
 
 	var state ghPRState
 	if err := json.Unmarshal(raw, &state); err != nil {
-		return false, fmt.Errorf("decode gh response: %w", err)
-	}
-	if state.MergedAt != nil {
-		return true, nil
+		return ghPRState{}, fmt.Errorf("decode gh response: %w", err)
 	}
-	return strings.EqualFold(state.State, "MERGED"), nil
+	return state, nil
 }
 
 func fetchPRChecks(ctx context.Context, link sqlite.GitHubLink, repoOverride string) ([]ghCheck, error) {
@@ -443,7 +675,7 @@ func fetchPRChecks(ctx context.Context, link sqlite.GitHubLink, repoOverride str
 	if repo != "" {
 		args = append(args, "--repo", repo)
 	}
-	raw, err := exec.CommandContext(ctx, "gh", args...).Output()
+	raw, err := ghOutput(ctx, args...)
 	if err != nil {
 		return nil, fmt.Errorf("gh pr checks failed for %s: %w", pr, err)
 	}
@@ -466,7 +698,7 @@ func fetchFailureCheckLog(ctx context.Context, check ghCheck, repo string) (stri
 	if repo != "" {
 		args = append(args, "--repo", repo)
 	}
-	raw, err := exec.CommandContext(ctx, "gh", args...).CombinedOutput()
+	raw, err := ghCombinedOutput(ctx, args...)
 	if err != nil {
 		return "", fmt.Errorf("gh run view failed: %s", strings.TrimSpace(string(raw)))
 	}
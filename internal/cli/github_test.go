@@ -3,6 +3,7 @@ package cli
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"slices"
@@ -54,6 +55,25 @@ func TestSummarizeChecks(t *testing.T) {
 	}
 }
 
+func TestUnmetMergeConditions(t *testing.T) {
+	var protection ghBranchProtection
+	raw := `{"required_status_checks":{"contexts":["build"],"checks":[{"context":"test"},{"context":"lint"}]},"required_pull_request_reviews":{"required_approving_review_count":2}}`
+	if err := json.Unmarshal([]byte(raw), &protection); err != nil {
+		t.Fatalf("decode protection: %v", err)
+	}
+	checks := []ghCheck{{Name: "build", State: "SUCCESS"}, {Name: "test", State: "IN_PROGRESS"}, {Name: "extra", State: "FAILURE"}}
+	got := unmetMergeConditions(ghPRMergeInfo{ReviewDecision: "REVIEW_REQUIRED"}, protection, checks)
+	want := []string{"needs 2 approving review(s)", "required check lint has not reported", "required check test is in_progress"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("unmet = %q, want %q", got, want)
+	}
+
+	got = unmetMergeConditions(ghPRMergeInfo{ReviewDecision: "APPROVED"}, ghBranchProtection{}, checks)
+	if want := []string{"check extra failed"}; !slices.Equal(got, want) {
+		t.Fatalf("unprotected unmet = %q, want %q", got, want)
+	}
+}
+
 func TestTailLines(t *testing.T) {
 	raw := "1\n2\n3\n4\n5\n"
 	got := tailLines(raw, 2)
@@ -334,3 +354,54 @@ func readArgsFile(t *testing.T, path string) []string {
 	}
 	return lines
 }
+
+func TestRepoFromRemoteURL(t *testing.T) {
+	cases := map[string]string{
+		"git@github.com:owner/repo.git":           "owner/repo",
+		"https://github.com/owner/repo":           "owner/repo",
+		"https://github.com/owner/repo.git/":      "owner/repo",
+		"ssh://git@github.com:22/owner/repo.git":  "owner/repo",
+		"https://token@github.com/owner/repo.git": "owner/repo",
+		"git@ghe.example.com:team/tool.git":       "ghe.example.com/team/tool",
+		"/srv/git/repo.git":                       "",
+		"https://github.com/owner":                "",
+	}
+	for raw, want := range cases {
+		got, ok := repoFromRemoteURL(raw)
+		if got != want || ok != (want != "") {
+			t.Fatalf("repoFromRemoteURL(%q) = %q, %v; want %q", raw, got, ok, want)
+		}
+	}
+}
+
+func TestGHIssueCreateFallsBackToOriginRemote(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+	ghArgsFile := filepath.Join(tmp, "gh-args.txt")
+	setupFakeGHForTest(t, tmp, ghArgsFile)
+	orig := gitOriginURL
+	gitOriginURL = func(context.Context) (string, error) { return "git@github.com:origin/repo.git", nil }
+	t.Cleanup(func() { gitOriginURL = orig })
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "From origin", Status: issue.StatusTodo, Priority: "none"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	cmd := newGHIssueCreateCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{it.ID, "--dry-run"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("cmd.Execute() error: %v", err)
+	}
+	if !strings.Contains(out.String(), "repo: origin/repo\n") {
+		t.Fatalf("dry-run should use the origin remote:\n%s", out.String())
+	}
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+// graph is a small node/edge list that `status graph` and `dep graph` render
+// as Graphviz DOT or a Mermaid flowchart.
+type graph struct {
+	nodes []graphNode
+	edges []graphEdge
+}
+
+type graphNode struct {
+	id    string
+	label string
+	// status picks the node's fill color; empty leaves it uncolored.
+	status string
+}
+
+type graphEdge struct {
+	from, to string
+	label    string
+	dashed   bool
+}
+
+// statusColors are the fills for the built-in statuses; custom statuses get
+// statusColorDefault.
+var statusColors = map[string]string{
+	issue.StatusTodo:       "#e5e7eb",
+	issue.StatusReady:      "#bfdbfe",
+	issue.StatusInProgress: "#fde68a",
+	issue.StatusDone:       "#bbf7d0",
+	issue.StatusArchived:   "#d1d5db",
+}
+
+const statusColorDefault = "#f5d0fe"
+
+func statusColor(status string) string {
+	if c, ok := statusColors[status]; ok {
+		return c
+	}
+	return statusColorDefault
+}
+
+func writeGraph(out io.Writer, g graph, format string) error {
+	switch format {
+	case "dot":
+		writeGraphDOT(out, g)
+	case "mermaid":
+		writeGraphMermaid(out, g)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+	return nil
+}
+
+func writeGraphDOT(out io.Writer, g graph) {
+	fmt.Fprintln(out, "digraph track {")
+	fmt.Fprintln(out, "  rankdir=LR;")
+	fmt.Fprintln(out, `  node [shape=box, style="rounded,filled", fillcolor="#ffffff"];`)
+	for _, n := range g.nodes {
+		attrs := fmt.Sprintf("label=%s", dotQuote(n.label))
+		if n.status != "" {
+			attrs += fmt.Sprintf(", fillcolor=%s", dotQuote(statusColor(n.status)))
+		}
+		fmt.Fprintf(out, "  %s [%s];\n", dotQuote(n.id), attrs)
+	}
+	for _, e := range g.edges {
+		var attrs []string
+		if e.label != "" {
+			attrs = append(attrs, "label="+dotQuote(e.label))
+		}
+		if e.dashed {
+			attrs = append(attrs, "style=dashed")
+		}
+		line := fmt.Sprintf("  %s -> %s", dotQuote(e.from), dotQuote(e.to))
+		if len(attrs) > 0 {
+			line += " [" + strings.Join(attrs, ", ") + "]"
+		}
+		fmt.Fprintln(out, line+";")
+	}
+	fmt.Fprintln(out, "}")
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// writeGraphMermaid writes a flowchart that renders inside a ```mermaid
+// block on GitHub.
+func writeGraphMermaid(out io.Writer, g graph) {
+	fmt.Fprintln(out, "flowchart LR")
+	var classes []string
+	seen := map[string]bool{}
+	for _, n := range g.nodes {
+		fmt.Fprintf(out, "  %s[\"%s\"]\n", mermaidID(n.id), mermaidText(n.label))
+		if n.status != "" && !seen[n.status] {
+			seen[n.status] = true
+			classes = append(classes, n.status)
+		}
+	}
+	for _, e := range g.edges {
+		arrow := "-->"
+		if e.dashed {
+			arrow = "-.->"
+		}
+		if e.label != "" {
+			arrow += "|\"" + mermaidText(e.label) + "\"|"
+		}
+		fmt.Fprintf(out, "  %s %s %s\n", mermaidID(e.from), arrow, mermaidID(e.to))
+	}
+	for _, st := range classes {
+		fmt.Fprintf(out, "  classDef %s fill:%s\n", mermaidClass(st), statusColor(st))
+	}
+	for _, n := range g.nodes {
+		if n.status != "" {
+			fmt.Fprintf(out, "  class %s %s\n", mermaidID(n.id), mermaidClass(n.status))
+		}
+	}
+}
+
+// mermaidID keeps letters, digits, and underscores so IDs like TRK-1 or a
+// status named "on-hold" stay valid node names.
+func mermaidID(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// mermaidClass prefixes the status so it cannot clash with Mermaid keywords
+// such as "end".
+func mermaidClass(status string) string {
+	return "status_" + mermaidID(status)
+}
+
+func mermaidText(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s)
+}
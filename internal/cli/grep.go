@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func newGrepCmd() *cobra.Command {
+	var (
+		flags        listFilterFlags
+		contextLines int
+		before       int
+		after        int
+		ignoreCase   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "grep <regexp>",
+		Short: "Regex-search issue titles, bodies, and comments",
+		Long: `Regex-search issue titles, bodies, and comments and print the matching lines
+with their issue ID and where they came from, like grep:
+
+  TRK-3:body:12:panic in sqlite.Store
+  TRK-3-body-13-  at issues.go:42
+  --
+  TRK-7:comment:panic in Store again
+
+Context lines use "-" and groups are separated by "--". Done and archived
+issues are skipped unless --status is given; --status open is the default set.
+The other filters work as in track list.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pattern := args[0]
+			if ignoreCase {
+				pattern = "(?i)" + pattern
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("invalid regexp: %w", err)
+			}
+			if cmd.Flags().Changed("context") {
+				if !cmd.Flags().Changed("before-context") {
+					before = contextLines
+				}
+				if !cmd.Flags().Changed("after-context") {
+					after = contextLines
+				}
+			}
+			if flags.status == "open" {
+				flags.status = ""
+			}
+
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			filter, err := flags.filter(ctx, store, "")
+			if err != nil {
+				return err
+			}
+			items, err := store.ListIssues(ctx, filter)
+			if err != nil {
+				return err
+			}
+
+			g := &grepPrinter{out: cmd.OutOrStdout()}
+			for _, it := range items {
+				g.search(it.ID, "title", []string{it.Title}, re, 0, 0, false)
+				g.search(it.ID, "body", strings.Split(it.Body, "\n"), re, before, after, true)
+				activity, err := store.ListIssueActivity(ctx, it.ID)
+				if err != nil {
+					return err
+				}
+				for _, a := range activity {
+					if a.Kind == sqlite.ActivityComment {
+						g.search(it.ID, "comment", strings.Split(a.Detail, "\n"), re, before, after, false)
+					}
+				}
+			}
+			if !g.matched {
+				return fmt.Errorf("no matches")
+			}
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+	cmd.Flags().IntVarP(&contextLines, "context", "C", 0, "Lines of context around each match")
+	cmd.Flags().IntVarP(&before, "before-context", "B", 0, "Lines of context before each match")
+	cmd.Flags().IntVarP(&after, "after-context", "A", 0, "Lines of context after each match")
+	cmd.Flags().BoolVarP(&ignoreCase, "ignore-case", "i", false, "Match case-insensitively")
+	return cmd
+}
+
+type grepPrinter struct {
+	out     io.Writer
+	matched bool
+	groups  int
+}
+
+// search prints the matching lines of one field with their context. Body
+// lines carry line numbers; overlapping context is printed once.
+func (g *grepPrinter) search(id, field string, lines []string, re *regexp.Regexp, before, after int, numbered bool) {
+	last := -1
+	for i, line := range lines {
+		if !re.MatchString(line) {
+			continue
+		}
+		from, to := max(0, i-before), min(len(lines)-1, i+after)
+		if last >= 0 && from <= last+1 {
+			from = last + 1
+		} else if g.groups > 0 && (before > 0 || after > 0) {
+			fmt.Fprintln(g.out, "--")
+		}
+		for j := from; j <= to; j++ {
+			sep := "-"
+			if re.MatchString(lines[j]) {
+				sep = ":"
+			}
+			if numbered {
+				fmt.Fprintf(g.out, "%s%s%s%s%d%s%s\n", id, sep, field, sep, j+1, sep, lines[j])
+			} else {
+				fmt.Fprintf(g.out, "%s%s%s%s%s\n", id, sep, field, sep, lines[j])
+			}
+		}
+		if to > last {
+			last = to
+		}
+		g.matched = true
+		g.groups++
+	}
+}
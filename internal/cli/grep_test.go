@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestGrepPrintsMatchesWithContext(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	crash, err := store.CreateIssue(ctx, issue.Item{Title: "crash on save", Status: issue.StatusTodo, Priority: "none", Body: "steps:\nrun save\npanic in sqlite.Store\nat issues.go:42\nend"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	if err := store.RecordActivity(ctx, sqlite.ActivityComment, crash.ID, "saw a panic in Store again"); err != nil {
+		t.Fatalf("RecordActivity() error: %v", err)
+	}
+	done, err := store.CreateIssue(ctx, issue.Item{Title: "old panic in Store", Status: issue.StatusDone, Priority: "none"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	run := func(args ...string) (string, error) {
+		cmd := newGrepCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+	out, err := run("panic in .*Store", "--status", "open", "-C", "1")
+	if err != nil {
+		t.Fatalf("grep error: %v\n%s", err, out)
+	}
+	want := crash.ID + "-body-2-run save\n" +
+		crash.ID + ":body:3:panic in sqlite.Store\n" +
+		crash.ID + "-body-4-at issues.go:42\n" +
+		"--\n" +
+		crash.ID + ":comment:saw a panic in Store again\n"
+	if out != want {
+		t.Fatalf("grep output =\n%s\nwant\n%s", out, want)
+	}
+
+	out, err = run("(?i)OLD PANIC", "--status", "done")
+	if err != nil || out != done.ID+":title:old panic in Store\n" {
+		t.Fatalf("grep --status done = %q, %v", out, err)
+	}
+	if _, err := run("nothing like this"); err == nil {
+		t.Fatalf("grep without matches should fail")
+	}
+}
@@ -19,6 +19,11 @@ func newHookCmd() *cobra.Command {
 	cmd.AddCommand(newHookAddCmd())
 	cmd.AddCommand(newHookRemoveCmd())
 	cmd.AddCommand(newHookTestCmd())
+	cmd.AddCommand(newHookFailuresCmd())
+	cmd.AddCommand(newHookToggleCmd("enable", "Enable hook", true))
+	cmd.AddCommand(newHookToggleCmd("disable", "Disable hook without removing it", false))
+	cmd.AddCommand(newHookOrderCmd())
+	cmd.AddCommand(newHookApproveCmd())
 	return cmd
 }
 
@@ -39,7 +44,7 @@ func newHookListCmd() *cobra.Command {
 				return err
 			}
 			for _, h := range hooksList {
-				fmt.Fprintf(cmd.OutOrStdout(), "%d\t%s\t%s\t%s\n", h.ID, h.Event, h.RunCmd, h.CWD)
+				fmt.Fprintf(cmd.OutOrStdout(), "%d\t%s\t%s\t%s\t%d\t%s\t%s\n", h.ID, h.Event, h.RunCmd, h.CWD, h.Order, enabledLabel(h.Enabled), approvedLabel(h.Approved))
 			}
 			return nil
 		},
@@ -108,6 +113,105 @@ func newHookRemoveCmd() *cobra.Command {
 	}
 }
 
+func newHookToggleCmd(use, short string, enabled bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " <hook_id>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hookID, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid hook_id: %s", args[0])
+			}
+
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if err := store.SetHookEnabled(ctx, hookID, enabled); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+}
+
+func newHookOrderCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "order <hook_id> <n>",
+		Short: "Set hook execution order (lower runs first)",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hookID, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid hook_id: %s", args[0])
+			}
+			order, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid order: %s", args[1])
+			}
+
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if err := store.SetHookOrder(ctx, hookID, order); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+}
+
+func newHookApproveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "approve <hook_id>...",
+		Short: "Allow a hook's command to run",
+		Long:  "Hooks added with track hook add are approved. Hooks that arrive with a database synced, restored, or imported from elsewhere, or whose command was changed there, are skipped until approved here; check them with track hook list first. Approvals are kept in $TRACK_HOME/hooks.allow, outside the database. When it is first created for a database from before approvals existed, the hooks already in it are approved; a newer database, copied from another machine or restored from a backup, starts with none approved and a warning naming each of its hooks.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids := make([]int, 0, len(args))
+			for _, arg := range args {
+				hookID, err := strconv.Atoi(arg)
+				if err != nil {
+					return fmt.Errorf("invalid hook_id: %s", arg)
+				}
+				ids = append(ids, hookID)
+			}
+
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			for _, hookID := range ids {
+				if _, err := store.ApproveHook(ctx, hookID); err != nil {
+					return err
+				}
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+}
+
+func approvedLabel(approved bool) string {
+	if approved {
+		return "approved"
+	}
+	return "unapproved"
+}
+
 func newHookTestCmd() *cobra.Command {
 	var issueID string
 	cmd := &cobra.Command{
@@ -125,6 +229,11 @@ func newHookTestCmd() *cobra.Command {
 			}
 			defer store.Close()
 
+			if f, frozen, err := store.AutomationFrozen(ctx); err != nil {
+				return err
+			} else if frozen {
+				return f
+			}
 			if err := hooks.RunEvent(ctx, store, args[0], issueID); err != nil {
 				return err
 			}
@@ -135,3 +244,34 @@ func newHookTestCmd() *cobra.Command {
 	cmd.Flags().StringVar(&issueID, "issue", "", "Issue ID for context")
 	return cmd
 }
+
+func newHookFailuresCmd() *cobra.Command {
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "failures",
+		Short: "List recent hook failures",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if limit < 0 {
+				return fmt.Errorf("limit must be >= 0")
+			}
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			failures, err := store.ListHookFailures(ctx, limit)
+			if err != nil {
+				return err
+			}
+			for _, f := range failures {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s\t%d\t%s\t%s\t%s\n", f.RanAt, f.HookID, f.Event, f.IssueID, f.Error)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum number of failures to show (0 for all)")
+	return cmd
+}
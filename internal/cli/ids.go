@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+// maxIDRange caps how many issues a single A..B argument may expand to.
+const maxIDRange = 500
+
+var issueIDPattern = regexp.MustCompile(`^([A-Za-z]+)-(\d+)$`)
+
+// expandIssueIDArgs normalizes issue ID arguments and expands ranges such as
+// TRK-3..TRK-9 or 3..9. Duplicates are dropped, keeping the first position.
+func expandIssueIDArgs(args []string) ([]string, error) {
+	var ids []string
+	seen := map[string]bool{}
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, arg := range args {
+		from, to, isRange := strings.Cut(arg, "..")
+		if !isRange {
+			add(normalizeIssueIDArg(arg))
+			continue
+		}
+		prefix, start, err := splitIssueID(normalizeIssueIDArg(from))
+		if err != nil {
+			return nil, fmt.Errorf("invalid id range %q: %w", arg, err)
+		}
+		toPrefix, end, err := splitIssueID(normalizeIssueIDArg(to))
+		if err != nil {
+			return nil, fmt.Errorf("invalid id range %q: %w", arg, err)
+		}
+		if toPrefix != prefix {
+			return nil, fmt.Errorf("invalid id range %q: prefixes differ", arg)
+		}
+		if end < start {
+			return nil, fmt.Errorf("invalid id range %q: end is before start", arg)
+		}
+		if end-start+1 > maxIDRange {
+			return nil, fmt.Errorf("invalid id range %q: more than %d issues", arg, maxIDRange)
+		}
+		for n := start; n <= end; n++ {
+			add(fmt.Sprintf("%s-%d", prefix, n))
+		}
+	}
+	return ids, nil
+}
+
+// externalIDPrefix marks an argument such as ext:JIRA-123 that names an issue
+// by its ID in the system it was imported from.
+const externalIDPrefix = "ext:"
+
+// resolveExternalIDs replaces ext:<id> arguments with the local issue IDs.
+func resolveExternalIDs(ctx context.Context, store *sqlite.Store, ids []string) ([]string, error) {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		ext, ok := strings.CutPrefix(id, externalIDPrefix)
+		if !ok {
+			out[i] = id
+			continue
+		}
+		it, err := store.GetIssueByExternalID(ctx, ext)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = it.ID
+	}
+	return out, nil
+}
+
+func splitIssueID(id string) (string, int, error) {
+	m := issueIDPattern.FindStringSubmatch(id)
+	if m == nil {
+		return "", 0, fmt.Errorf("%q is not an issue id", id)
+	}
+	n, err := strconv.Atoi(m[2])
+	if err != nil {
+		return "", 0, err
+	}
+	return m[1], n, nil
+}
+
+// runForIssues applies fn to each issue. A single issue keeps the plain
+// single-issue output ("ok" or the error itself); several issues print one
+// "<id>: ok" or "<id>: <error>" line each and fail when any of them did.
+func runForIssues(cmd *cobra.Command, ids []string, fn func(id string) error) error {
+	out := cmd.OutOrStdout()
+	if len(ids) == 1 {
+		if err := fn(ids[0]); err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "ok")
+		return nil
+	}
+
+	failed := 0
+	for _, id := range ids {
+		if err := fn(id); err != nil {
+			failed++
+			fmt.Fprintf(out, "%s: %v\n", id, err)
+			continue
+		}
+		fmt.Fprintf(out, "%s: ok\n", id)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d issues failed", failed, len(ids))
+	}
+	return nil
+}
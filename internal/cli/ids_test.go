@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandIssueIDArgs(t *testing.T) {
+	got, err := expandIssueIDArgs([]string{"3", "TRK-5..TRK-7", "6..8", "TRK-3"})
+	if err != nil {
+		t.Fatalf("expandIssueIDArgs() error: %v", err)
+	}
+	want := []string{"TRK-3", "TRK-5", "TRK-6", "TRK-7", "TRK-8"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expandIssueIDArgs() = %v, want %v", got, want)
+	}
+
+	for _, bad := range []string{"TRK-9..TRK-3", "TRK-1..ABC-2", "TRK-1..x", "1..100000"} {
+		if _, err := expandIssueIDArgs([]string{bad}); err == nil || !strings.Contains(err.Error(), "invalid id range") {
+			t.Fatalf("expandIssueIDArgs(%q) error = %v, want invalid id range", bad, err)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/myuon/track/internal/issue"
 	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/myuon/track/internal/timefmt"
 	"github.com/spf13/cobra"
 )
 
@@ -23,6 +24,7 @@ func newExportCmd() *cobra.Command {
 	var format string
 	var status string
 	var label string
+	var outDir string
 
 	cmd := &cobra.Command{
 		Use:   "export",
@@ -52,45 +54,64 @@ func newExportCmd() *cobra.Command {
 				return writeTextExport(cmd.OutOrStdout(), items)
 			case "csv":
 				return writeCSVExport(cmd.OutOrStdout(), items)
-			case "json":
-				return writeJSONExport(cmd.OutOrStdout(), items)
-			case "jsonl":
+			case "json", "jsonl":
+				for i := range items {
+					if items[i].Links, err = store.ListLinks(ctx, items[i].ID); err != nil {
+						return err
+					}
+				}
+				if format == "json" {
+					return writeJSONExport(cmd.OutOrStdout(), items)
+				}
 				return writeJSONLExport(cmd.OutOrStdout(), items)
+			case "site":
+				if err := writeSiteExport(ctx, store, items, outDir); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "exported: %d issues to %s\n", len(items), outDir)
+				return nil
 			default:
 				return fmt.Errorf("unsupported format: %s", format)
 			}
 		},
 	}
 
-	cmd.Flags().StringVar(&format, "format", "text", "Export format: text|csv|json|jsonl")
+	cmd.Flags().StringVar(&format, "format", "text", "Export format: text|csv|json|jsonl|site")
 	cmd.Flags().StringVar(&status, "status", "", "Status filter")
-	cmd.Flags().StringVar(&label, "label", "", "Label filter")
+	cmd.Flags().StringVar(&outDir, "out", "", "Output directory for --format site")
+	cmd.Flags().StringVar(&label, "label", "", `Label filter expression (e.g. "bug AND NOT wontfix", "bug,ui")`)
 	return cmd
 }
 
 func newImportCmd() *cobra.Command {
 	var format string
 	var dryRun bool
+	var skipDuplicates bool
+	var mergeDuplicates bool
+	var externalIDColumn string
 
 	cmd := &cobra.Command{
 		Use:   "import --format text|csv|json|jsonl <path>",
 		Short: "Import issues",
+		Long:  "Import issues. Each row's external_id (or the CSV column named by --external-id) is kept so the issue can be found again with track show ext:<id>. Rows whose external ID or title matches an existing issue (ignoring case) are reported as likely duplicates; --skip-duplicates leaves them out, and --merge-duplicates copies their non-empty fields, labels, and links onto the existing issue instead of creating a new one. New issues and their links are created in one transaction, so an invalid row creates none of them. Merges run afterwards, one existing issue at a time: if a merge fails, the new issues and the merges before it are kept.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if skipDuplicates && mergeDuplicates {
+				return fmt.Errorf("--skip-duplicates and --merge-duplicates cannot be combined")
+			}
 			f, err := os.Open(args[0])
 			if err != nil {
 				return err
 			}
 			defer f.Close()
 
-			items, err := readImport(format, f)
+			if cmd.Flags().Changed("external-id") && format != "csv" {
+				return fmt.Errorf("--external-id names a CSV column; json and jsonl imports read the ExternalID field")
+			}
+			items, err := readImport(format, f, externalIDColumn)
 			if err != nil {
 				return err
 			}
-			if dryRun {
-				fmt.Fprintf(cmd.OutOrStdout(), "dry-run: %d issues\n", len(items))
-				return nil
-			}
 
 			ctx := context.Background()
 			store, err := sqlite.Open(ctx)
@@ -99,27 +120,153 @@ func newImportCmd() *cobra.Command {
 			}
 			defer store.Close()
 
-			for _, it := range items {
+			dups, err := findImportDuplicates(ctx, store, items)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if dryRun {
+				fmt.Fprintf(out, "dry-run: %d issues\n", len(items))
+				printImportDuplicates(out, items, dups)
+				return nil
+			}
+
+			skipped := 0
+			var toCreate, toMerge, mergeInto []issue.Item
+			for i, it := range items {
+				if dup, ok := dups[i]; ok && (skipDuplicates || mergeDuplicates) {
+					if skipDuplicates {
+						skipped++
+						continue
+					}
+					toMerge = append(toMerge, it)
+					mergeInto = append(mergeInto, dup.Item)
+					continue
+				}
 				if it.Status == "" {
 					it.Status = issue.StatusTodo
 				}
 				if it.Priority == "" {
 					it.Priority = "none"
 				}
-				if _, err := store.CreateIssue(ctx, it); err != nil {
-					return err
+				toCreate = append(toCreate, it)
+			}
+			created, err := store.BulkCreate(ctx, toCreate)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "imported: %d issues\n", len(created))
+			merged := 0
+			for i, it := range toMerge {
+				if err := mergeImportedIssue(ctx, store, mergeInto[i], it); err != nil {
+					return fmt.Errorf("merge into %s (%d of %d merged): %w", mergeInto[i].ID, merged, len(toMerge), err)
 				}
+				merged++
+			}
+			switch {
+			case skipped > 0:
+				fmt.Fprintf(out, "skipped: %d duplicates\n", skipped)
+			case merged > 0:
+				fmt.Fprintf(out, "merged: %d duplicates\n", merged)
+			case len(dups) > 0:
+				printImportDuplicates(out, items, dups)
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "imported: %d issues\n", len(items))
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&format, "format", "text", "Import format: text|csv|json|jsonl")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate input without writing")
+	cmd.Flags().BoolVar(&skipDuplicates, "skip-duplicates", false, "Leave out rows that duplicate an existing issue")
+	cmd.Flags().BoolVar(&mergeDuplicates, "merge-duplicates", false, "Merge rows into the existing issues they duplicate")
+	cmd.Flags().StringVar(&externalIDColumn, "external-id", "external_id", "CSV column holding each row's ID in its source system (e.g. \"Issue key\")")
 	return cmd
 }
 
+// importDuplicate is an existing issue that an imported item likely
+// duplicates, and why.
+type importDuplicate struct {
+	issue.Item
+	reason string
+}
+
+// findImportDuplicates maps the index of each imported item that likely
+// duplicates an existing issue to that issue. A matching external ID is
+// checked first; otherwise titles are compared ignoring case and surrounding
+// space, and the oldest match wins.
+func findImportDuplicates(ctx context.Context, store *sqlite.Store, items []issue.Item) (map[int]importDuplicate, error) {
+	existing, err := store.ListIssues(ctx, sqlite.ListFilter{Sort: "manual"})
+	if err != nil {
+		return nil, err
+	}
+	byTitle := map[string]issue.Item{}
+	byExternalID := map[string]issue.Item{}
+	for _, it := range existing {
+		key := strings.ToLower(strings.TrimSpace(it.Title))
+		if prev, ok := byTitle[key]; !ok || it.CreatedAt < prev.CreatedAt {
+			byTitle[key] = it
+		}
+		if it.ExternalID != "" {
+			byExternalID[it.ExternalID] = it
+		}
+	}
+	dups := map[int]importDuplicate{}
+	for i, it := range items {
+		if match, ok := byExternalID[strings.TrimSpace(it.ExternalID)]; ok && it.ExternalID != "" {
+			dups[i] = importDuplicate{Item: match, reason: "same external id"}
+		} else if match, ok := byTitle[strings.ToLower(strings.TrimSpace(it.Title))]; ok {
+			dups[i] = importDuplicate{Item: match, reason: "same title"}
+		}
+	}
+	return dups, nil
+}
+
+func printImportDuplicates(out io.Writer, items []issue.Item, dups map[int]importDuplicate) {
+	if len(dups) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "duplicates: %d (use --skip-duplicates or --merge-duplicates)\n", len(dups))
+	for i := range items {
+		if match, ok := dups[i]; ok {
+			fmt.Fprintf(out, "  row %d %q matches %s (%s)\n", i+1, items[i].Title, match.ID, match.reason)
+		}
+	}
+}
+
+// mergeImportedIssue copies the imported item's non-empty fields onto the
+// existing issue and adds its labels and links.
+func mergeImportedIssue(ctx context.Context, store *sqlite.Store, existing, it issue.Item) error {
+	var in sqlite.UpdateIssueInput
+	set := func(dst **string, v string) {
+		if v != "" {
+			*dst = &v
+		}
+	}
+	set(&in.Status, it.Status)
+	if it.Priority != "none" {
+		set(&in.Priority, it.Priority)
+	}
+	set(&in.Assignee, it.Assignee)
+	set(&in.Due, it.Due)
+	set(&in.NextAction, it.NextAction)
+	set(&in.Body, it.Body)
+	set(&in.ExternalID, it.ExternalID)
+	if _, err := store.UpdateIssue(ctx, existing.ID, in); err != nil {
+		return err
+	}
+	for _, label := range it.Labels {
+		if _, err := store.AddLabel(ctx, existing.ID, label); err != nil {
+			return err
+		}
+	}
+	for _, l := range it.Links {
+		if err := store.AddLink(ctx, existing.ID, l.URL, l.Title); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func writeTextExport(out io.Writer, items []issue.Item) error {
 	for _, it := range items {
 		_, err := fmt.Fprintf(out, "%s\t%s\t%s\t%s\n", it.ID, it.Status, it.Priority, strings.ReplaceAll(it.Title, "\t", " "))
@@ -132,11 +279,16 @@ func writeTextExport(out io.Writer, items []issue.Item) error {
 
 func writeCSVExport(out io.Writer, items []issue.Item) error {
 	w := csv.NewWriter(out)
-	if err := w.Write([]string{"id", "title", "status", "priority", "assignee", "due", "labels", "next_action", "body"}); err != nil {
+	times, err := timefmt.Load()
+	if err != nil {
+		return err
+	}
+	times = times.Static()
+	if err := w.Write([]string{"id", "title", "status", "priority", "assignee", "due", "labels", "next_action", "body", "created_at", "updated_at", "external_id"}); err != nil {
 		return err
 	}
 	for _, it := range items {
-		if err := w.Write([]string{it.ID, it.Title, it.Status, it.Priority, it.Assignee, it.Due, strings.Join(it.Labels, ","), it.NextAction, it.Body}); err != nil {
+		if err := w.Write([]string{it.ID, it.Title, it.Status, it.Priority, it.Assignee, it.Due, strings.Join(it.Labels, ","), it.NextAction, it.Body, times.Time(it.CreatedAt), times.Time(it.UpdatedAt), it.ExternalID}); err != nil {
 			return err
 		}
 	}
@@ -159,12 +311,14 @@ func writeJSONExport(out io.Writer, items []issue.Item) error {
 	return enc.Encode(items)
 }
 
-func readImport(format string, in io.Reader) ([]issue.Item, error) {
+// readImport parses issues in format. externalIDColumn names the CSV column
+// that holds each row's external ID.
+func readImport(format string, in io.Reader, externalIDColumn string) ([]issue.Item, error) {
 	switch format {
 	case "text":
 		return readTextImport(in)
 	case "csv":
-		return readCSVImport(in)
+		return readCSVImport(in, externalIDColumn)
 	case "json":
 		return readJSONImport(in)
 	case "jsonl":
@@ -198,7 +352,7 @@ func readTextImport(in io.Reader) ([]issue.Item, error) {
 	return items, nil
 }
 
-func readCSVImport(in io.Reader) ([]issue.Item, error) {
+func readCSVImport(in io.Reader, externalIDColumn string) ([]issue.Item, error) {
 	r := csv.NewReader(in)
 	recs, err := r.ReadAll()
 	if err != nil {
@@ -229,6 +383,7 @@ func readCSVImport(in io.Reader) ([]issue.Item, error) {
 			Due:        get("due"),
 			NextAction: get("next_action"),
 			Body:       get("body"),
+			ExternalID: get(externalIDColumn),
 		}
 		if raw := get("labels"); raw != "" {
 			it.Labels = strings.Split(raw, ",")
@@ -11,6 +11,7 @@ import (
 
 	"github.com/myuon/track/internal/issue"
 	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
 )
 
 func TestCSVRoundTrip(t *testing.T) {
@@ -31,7 +32,7 @@ func TestCSVRoundTrip(t *testing.T) {
 		t.Fatalf("writeCSVExport() error: %v", err)
 	}
 
-	got, err := readCSVImport(strings.NewReader(buf.String()))
+	got, err := readCSVImport(strings.NewReader(buf.String()), "external_id")
 	if err != nil {
 		t.Fatalf("readCSVImport() error: %v", err)
 	}
@@ -68,7 +69,7 @@ func TestCSVRoundTripWithNonePriority(t *testing.T) {
 	if err := writeCSVExport(&buf, items); err != nil {
 		t.Fatalf("writeCSVExport() error: %v", err)
 	}
-	got, err := readCSVImport(strings.NewReader(buf.String()))
+	got, err := readCSVImport(strings.NewReader(buf.String()), "external_id")
 	if err != nil {
 		t.Fatalf("readCSVImport() error: %v", err)
 	}
@@ -215,3 +216,208 @@ func TestImportCmdJSONDefaultsStatusAndPriority(t *testing.T) {
 		t.Fatalf("labels mismatch: %+v", items[0].Labels)
 	}
 }
+
+func TestExportSiteWritesIndexAndIssuePages(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("sqlite.Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	if _, err := store.CreateProject(ctx, "web", "Website", "Public site"); err != nil {
+		t.Fatalf("CreateProject() error: %v", err)
+	}
+	inProject, err := store.CreateIssue(ctx, issue.Item{Title: "Launch <beta>", Status: issue.StatusInProgress, Priority: "p1", Body: "## Spec\nship"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	if err := store.SetIssueProject(ctx, inProject.ID, "web"); err != nil {
+		t.Fatalf("SetIssueProject() error: %v", err)
+	}
+	if _, err := store.CreateIssue(ctx, issue.Item{Title: "Loose end", Status: issue.StatusTodo, Priority: "p2"}); err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	outDir := filepath.Join(tmp, "public")
+	cmd := newExportCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--format", "site", "--out", outDir})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("export error: %v", err)
+	}
+
+	index, err := os.ReadFile(filepath.Join(outDir, "index.html"))
+	if err != nil {
+		t.Fatalf("read index: %v", err)
+	}
+	s := string(index)
+	web, loose := strings.Index(s, "<h2>Website</h2>"), strings.Index(s, "<h2>No project</h2>")
+	if web == -1 || loose == -1 || web > loose || !strings.Contains(s, `<h3>in_progress (1)</h3><ul><li><a href="issues/TRK-1.html">TRK-1</a> Launch &lt;beta&gt;</li>`) {
+		t.Fatalf("index should group issues by project and status:\n%s", s)
+	}
+	page, err := os.ReadFile(filepath.Join(outDir, "issues", "TRK-2.html"))
+	if err != nil {
+		t.Fatalf("read issue page: %v", err)
+	}
+	if !strings.Contains(string(page), "TRK-2 Loose end") {
+		t.Fatalf("issue page mismatch:\n%s", page)
+	}
+}
+
+func TestJSONLExportImportKeepsLinks(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", filepath.Join(tmp, "src"))
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("sqlite.Open() error: %v", err)
+	}
+	created, err := store.CreateIssue(ctx, issue.Item{Title: "Linked", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	if err := store.AddLink(ctx, created.ID, "https://example.com/design", "Design doc"); err != nil {
+		t.Fatalf("AddLink() error: %v", err)
+	}
+	if err := store.AddLink(ctx, created.ID, "not a url", ""); err == nil {
+		t.Fatalf("AddLink() should reject a url without scheme and host")
+	}
+	_ = store.Close()
+
+	cmd := newExportCmd()
+	var exported bytes.Buffer
+	cmd.SetOut(&exported)
+	cmd.SetArgs([]string{"--format", "jsonl"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("export error: %v", err)
+	}
+	inPath := filepath.Join(tmp, "issues.jsonl")
+	if err := os.WriteFile(inPath, exported.Bytes(), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error: %v", err)
+	}
+
+	t.Setenv("TRACK_HOME", filepath.Join(tmp, "dst"))
+	cmd = newImportCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--format", "jsonl", inPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("import error: %v", err)
+	}
+
+	store, err = sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("sqlite.Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	links, err := store.ListLinks(ctx, "TRK-1")
+	if err != nil {
+		t.Fatalf("ListLinks() error: %v", err)
+	}
+	if len(links) != 1 || links[0] != (issue.Link{URL: "https://example.com/design", Title: "Design doc"}) {
+		t.Fatalf("links = %+v", links)
+	}
+}
+
+func TestImportReportsSkipsAndMergesDuplicates(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("sqlite.Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	existing, err := store.CreateIssue(ctx, issue.Item{Title: "Fix login", Status: issue.StatusTodo, Priority: "none"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	inPath := filepath.Join(tmp, "issues.csv")
+	raw := "title,status,priority,labels\n fix LOGIN ,,p1,auth\nBrand new,,p2,\n"
+	if err := os.WriteFile(inPath, []byte(raw), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error: %v", err)
+	}
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := newImportCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(append([]string{"--format", "csv"}, append(args, inPath)...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("import %v error: %v\n%s", args, err, out.String())
+		}
+		return out.String()
+	}
+
+	out := run("--dry-run")
+	if !strings.Contains(out, "duplicates: 1") || !strings.Contains(out, `row 1 " fix LOGIN " matches `+existing.ID) {
+		t.Fatalf("dry-run should report the duplicate:\n%s", out)
+	}
+
+	if out := run("--skip-duplicates"); !strings.Contains(out, "imported: 1 issues") || !strings.Contains(out, "skipped: 1 duplicates") {
+		t.Fatalf("skip output = %q", out)
+	}
+	if out := run("--merge-duplicates"); !strings.Contains(out, "merged: 2 duplicates") {
+		t.Fatalf("merge output = %q", out)
+	}
+	merged, err := store.GetIssue(ctx, existing.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if merged.Priority != "p1" || strings.Join(merged.Labels, ",") != "auth" {
+		t.Fatalf("merged issue = %+v", merged)
+	}
+	items, err := store.ListIssues(ctx, sqlite.ListFilter{Sort: "manual"})
+	if err != nil {
+		t.Fatalf("ListIssues() error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("len(items) = %d, want 2 (no duplicates created)", len(items))
+	}
+}
+
+func TestImportKeepsExternalIDs(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	inPath := filepath.Join(tmp, "jira.csv")
+	raw := "title,Issue key\nFix login,JIRA-1\nAdd export,JIRA-2\n"
+	if err := os.WriteFile(inPath, []byte(raw), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error: %v", err)
+	}
+	run := func(cmd *cobra.Command, args ...string) string {
+		t.Helper()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%v error: %v\n%s", args, err, out.String())
+		}
+		return out.String()
+	}
+
+	run(newImportCmd(), "--format", "csv", "--external-id", "Issue key", inPath)
+	out := run(newShowCmd(), "ext:JIRA-2")
+	if !strings.Contains(out, "title: Add export") || !strings.Contains(out, "external_id: JIRA-2") {
+		t.Fatalf("show ext:JIRA-2 = %q", out)
+	}
+
+	// A re-sync that renamed the issue still finds it by external ID.
+	if err := os.WriteFile(inPath, []byte("title,Issue key\nAdd CSV export,JIRA-2\n"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error: %v", err)
+	}
+	out = run(newImportCmd(), "--format", "csv", "--external-id", "Issue key", "--dry-run", inPath)
+	if !strings.Contains(out, "(same external id)") {
+		t.Fatalf("dry-run should match by external id:\n%s", out)
+	}
+}
@@ -2,20 +2,31 @@ package cli
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"database/sql"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"os"
 	"os/exec"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mattn/go-runewidth"
 	appconfig "github.com/myuon/track/internal/config"
-	"github.com/myuon/track/internal/hooks"
 	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/notify"
+	"github.com/myuon/track/internal/query"
+	"github.com/myuon/track/internal/service"
+	"github.com/myuon/track/internal/stats"
 	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/myuon/track/internal/textdiff"
+	"github.com/myuon/track/internal/timefmt"
+	"github.com/myuon/track/pkg/client"
+	"github.com/myuon/track/pkg/track"
 	"github.com/spf13/cobra"
 )
 
@@ -135,17 +146,28 @@ func newIssueCommands() []*cobra.Command {
 	return []*cobra.Command{
 		newNewCmd(),
 		newListCmd(),
+		newCountCmd(),
+		newSampleCmd(),
 		newShowCmd(),
 		newEditCmd(),
 		newSetCmd(),
 		newStatusCmd(),
 		newLabelCmd(),
+		newLinkCmd(),
+		newDependsOnCmd(),
 		newPlanningCmd(),
 		newReplyCmd(),
+		newNoteCmd(),
+		newCommentCmd(),
 		newNextCmd(),
+		newCheckCmd(),
 		newDoneCmd(),
 		newArchiveCmd(),
 		newReorderCmd(),
+		newRankCmd(),
+		newTriageCmd(),
+		newPinCmd(true),
+		newPinCmd(false),
 	}
 }
 
@@ -214,22 +236,36 @@ func newStatusCmd() *cobra.Command {
 			return nil
 		},
 	})
+	cmd.AddCommand(newStatusGraphCmd())
 	return cmd
 }
 
 func newNewCmd() *cobra.Command {
 	var (
-		body     string
-		labels   []string
-		priority string
-		due      string
-		assignee string
-		tui      bool
+		title         string
+		body          string
+		labels        []string
+		priority      string
+		due           string
+		assignee      string
+		tui           bool
+		idemKey       string
+		remote        string
+		project       string
+		stdin         bool
+		titleFromLine bool
+		externalID    string
+		templateName  string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "new [title]",
 		Short: "Create an issue",
+		Long: `Create an issue. The body can be piped in with --body - (or --stdin), and
+--stdin --title-from-line creates one issue per non-empty input line:
+
+  cat report.md | track new --title "Nightly report" --body -
+  git log --oneline | track new --stdin --title-from-line --label review`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if tui {
 				if len(args) > 1 {
@@ -237,23 +273,45 @@ func newNewCmd() *cobra.Command {
 				}
 				return nil
 			}
+			if titleFromLine || cmd.Flags().Changed("title") {
+				return cobra.NoArgs(cmd, args)
+			}
 			return cobra.ExactArgs(1)(cmd, args)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
-			store, err := sqlite.Open(ctx)
-			if err != nil {
-				return err
+			if titleFromLine && !stdin {
+				return fmt.Errorf("--title-from-line needs --stdin")
+			}
+			if titleFromLine && cmd.Flags().Changed("title") {
+				return fmt.Errorf("--title cannot be combined with --title-from-line")
+			}
+			if tui && (stdin || body == "-") {
+				return fmt.Errorf("--tui cannot read from stdin")
+			}
+			if stdin && !titleFromLine && cmd.Flags().Changed("body") {
+				return fmt.Errorf("--stdin reads the body; drop --body")
+			}
+			var input string
+			if stdin || body == "-" {
+				data, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return err
+				}
+				input = string(data)
 			}
-			defer store.Close()
 
-			in := newIssueInput{
+			in := track.NewIssue{
+				Title:    title,
 				Body:     body,
 				Priority: priority,
 				Assignee: assignee,
 				Due:      due,
 				Labels:   labels,
 			}
+			if body == "-" || (stdin && !titleFromLine) {
+				in.Body = strings.TrimRight(input, "\n")
+			}
 			if len(args) > 0 {
 				in.Title = args[0]
 			}
@@ -272,62 +330,108 @@ func newNewCmd() *cobra.Command {
 				}
 				in = prompted
 			}
+			in.IdempotencyKey = idemKey
+			in.Project = project
+			if project == "" && remote == "" {
+				detected, err := projectForCwd(ctx)
+				if err != nil {
+					return err
+				}
+				in.Project = detected
+			}
+			in.ExternalID = externalID
 
-			item, err := createIssueFromInput(ctx, store, in)
+			inputs := []track.NewIssue{in}
+			if titleFromLine {
+				if idemKey != "" {
+					return fmt.Errorf("--idempotency-key cannot be combined with --title-from-line")
+				}
+				if externalID != "" {
+					return fmt.Errorf("--external-id cannot be combined with --title-from-line")
+				}
+				inputs = inputs[:0]
+				for _, line := range strings.Split(input, "\n") {
+					if line = strings.TrimSpace(line); line != "" {
+						next := in
+						next.Title = line
+						next.Labels = slices.Clone(in.Labels)
+						inputs = append(inputs, next)
+					}
+				}
+				if len(inputs) == 0 {
+					return fmt.Errorf("no lines on stdin")
+				}
+			}
+			if templateName != "" {
+				tpl, err := loadTemplate(ctx, templateName)
+				if err != nil {
+					return err
+				}
+				for i := range inputs {
+					inputs[i] = applyTemplate(inputs[i], tpl)
+				}
+			}
+
+			if remote != "" {
+				if project != "" {
+					return fmt.Errorf("--project is not supported with --remote")
+				}
+				if externalID != "" {
+					return fmt.Errorf("--external-id is not supported with --remote")
+				}
+				for _, in := range inputs {
+					if err := remoteNew(cmd, remote, client.NewIssue{
+						Title:          in.Title,
+						Body:           in.Body,
+						Priority:       in.Priority,
+						Assignee:       in.Assignee,
+						Due:            in.Due,
+						Labels:         in.Labels,
+						IdempotencyKey: in.IdempotencyKey,
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			tracker, err := track.Open(ctx)
 			if err != nil {
 				return err
 			}
+			defer tracker.Close()
 
-			if err := hooks.RunEvent(ctx, store, hooks.IssueCreated, item.ID); err != nil {
-				return err
+			for _, in := range inputs {
+				item, err := tracker.CreateIssue(ctx, in)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), item.ID)
 			}
-			fmt.Fprintln(cmd.OutOrStdout(), item.ID)
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&body, "body", "", "Issue body")
+	cmd.Flags().StringVar(&title, "title", "", "Issue title (instead of the argument)")
+	cmd.Flags().StringVar(&body, "body", "", "Issue body (- reads it from stdin)")
 	cmd.Flags().StringArrayVar(&labels, "label", nil, "Issue label (repeatable)")
 	cmd.Flags().StringVar(&priority, "priority", "none", "Priority (none|p0|p1|p2|p3)")
-	cmd.Flags().StringVar(&due, "due", "", "Due date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&due, "due", "", "Due date (YYYY-MM-DD, or YYYY-MM-DDTHH:MM in the configured timezone)")
 	cmd.Flags().StringVar(&assignee, "assignee", "", "Assignee")
+	_ = cmd.RegisterFlagCompletionFunc("assignee", completeAssignees)
 	cmd.Flags().BoolVar(&tui, "tui", false, "Create issue with interactive prompts")
+	cmd.Flags().StringVar(&idemKey, "idempotency-key", "", "Return the issue already created with this key (kept 24h) instead of a duplicate")
+	cmd.Flags().StringVar(&remote, "remote", "", remoteFlagUsage)
+	cmd.Flags().StringVar(&project, "project", "", "Create the issue in this project, applying its defaults")
+	cmd.Flags().BoolVar(&stdin, "stdin", false, "Read the body from stdin")
+	cmd.Flags().BoolVar(&titleFromLine, "title-from-line", false, "With --stdin, create one issue per input line, titled by the line")
+	cmd.Flags().StringVar(&templateName, "template", "", "Start from this template (see track template list)")
+	cmd.Flags().StringVar(&externalID, "external-id", "", "ID of the issue in its source system (e.g. JIRA-123); show it with track show ext:<id>")
 
 	return cmd
 }
 
-type newIssueInput struct {
-	Title    string
-	Body     string
-	Priority string
-	Assignee string
-	Due      string
-	Labels   []string
-}
-
-func createIssueFromInput(ctx context.Context, store *sqlite.Store, in newIssueInput) (issue.Item, error) {
-	if err := issue.ValidateTitle(in.Title); err != nil {
-		return issue.Item{}, err
-	}
-	if err := issue.ValidatePriority(in.Priority); err != nil {
-		return issue.Item{}, err
-	}
-	if err := issue.ValidateDue(in.Due); err != nil {
-		return issue.Item{}, err
-	}
-
-	return store.CreateIssue(ctx, issue.Item{
-		Title:    in.Title,
-		Status:   issue.StatusTodo,
-		Priority: in.Priority,
-		Assignee: issue.NormalizeAssignee(in.Assignee),
-		Due:      in.Due,
-		Labels:   in.Labels,
-		Body:     in.Body,
-	})
-}
-
-func promptNewIssueInput(in io.Reader, out io.Writer, initialTitle string) (newIssueInput, bool, error) {
+func promptNewIssueInput(in io.Reader, out io.Writer, initialTitle string) (track.NewIssue, bool, error) {
 	reader := bufio.NewReader(in)
 
 	title := strings.TrimSpace(initialTitle)
@@ -335,7 +439,7 @@ func promptNewIssueInput(in io.Reader, out io.Writer, initialTitle string) (newI
 		if title == "" {
 			v, err := readPromptLine(reader, out, "title: ")
 			if err != nil {
-				return newIssueInput{}, false, err
+				return track.NewIssue{}, false, err
 			}
 			title = strings.TrimSpace(v)
 		}
@@ -349,14 +453,14 @@ func promptNewIssueInput(in io.Reader, out io.Writer, initialTitle string) (newI
 
 	body, err := readPromptLine(reader, out, "body: ")
 	if err != nil {
-		return newIssueInput{}, false, err
+		return track.NewIssue{}, false, err
 	}
 
 	var priority string
 	for {
 		v, err := readPromptLine(reader, out, "priority (none|p0|p1|p2|p3) [none]: ")
 		if err != nil {
-			return newIssueInput{}, false, err
+			return track.NewIssue{}, false, err
 		}
 		v = strings.TrimSpace(v)
 		if v == "" {
@@ -372,18 +476,18 @@ func promptNewIssueInput(in io.Reader, out io.Writer, initialTitle string) (newI
 
 	labelsLine, err := readPromptLine(reader, out, "labels (comma separated): ")
 	if err != nil {
-		return newIssueInput{}, false, err
+		return track.NewIssue{}, false, err
 	}
 	assignee, err := readPromptLine(reader, out, "assignee: ")
 	if err != nil {
-		return newIssueInput{}, false, err
+		return track.NewIssue{}, false, err
 	}
 
 	var due string
 	for {
-		v, err := readPromptLine(reader, out, "due (YYYY-MM-DD): ")
+		v, err := readPromptLine(reader, out, "due (YYYY-MM-DD[THH:MM]): ")
 		if err != nil {
-			return newIssueInput{}, false, err
+			return track.NewIssue{}, false, err
 		}
 		v = strings.TrimSpace(v)
 		if err := issue.ValidateDue(v); err != nil {
@@ -396,14 +500,14 @@ func promptNewIssueInput(in io.Reader, out io.Writer, initialTitle string) (newI
 
 	confirm, err := readPromptLine(reader, out, "confirm create? [y/N]: ")
 	if err != nil {
-		return newIssueInput{}, false, err
+		return track.NewIssue{}, false, err
 	}
 	confirmed := strings.EqualFold(strings.TrimSpace(confirm), "y") || strings.EqualFold(strings.TrimSpace(confirm), "yes")
 	if !confirmed {
-		return newIssueInput{}, true, nil
+		return track.NewIssue{}, true, nil
 	}
 
-	return newIssueInput{
+	return track.NewIssue{
 		Title:    title,
 		Body:     strings.TrimSpace(body),
 		Priority: priority,
@@ -413,6 +517,13 @@ func promptNewIssueInput(in io.Reader, out io.Writer, initialTitle string) (newI
 	}, false, nil
 }
 
+// isTerminalInput reports whether r is an interactive terminal, so a
+// command can ask for what its flags left out.
+func isTerminalInput(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	return ok && isTerminal(f)
+}
+
 func readPromptLine(reader *bufio.Reader, out io.Writer, prompt string) (string, error) {
 	fmt.Fprint(out, prompt)
 	line, err := reader.ReadString('\n')
@@ -441,76 +552,227 @@ func parseCommaSeparatedLabels(v string) []string {
 	return labels
 }
 
+// listFilterFlags are the issue filter flags shared by list, count, and
+// sample.
+type listFilterFlags struct {
+	status   string
+	label    string
+	assignee string
+	search   string
+	project  string
+	query    string
+	// hide are statuses left out when no status filter is given.
+	hide []string
+}
+
+const queryHelp = `-q takes a query such as "status:ready priority<=p1 due<2026-03-01 label:bug -label:blocked".
+Keys: status, priority, label, due, assignee, project, sort; words without a key search
+title and body. status, priority, and label can be negated with a leading "-".`
+
+func (o *listFilterFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.status, "status", "", "Status filter (comma separated)")
+	cmd.Flags().StringVar(&o.label, "label", "", `Label filter expression (e.g. "bug AND NOT wontfix", "bug,ui")`)
+	cmd.Flags().StringVar(&o.assignee, "assignee", "", "Assignee filter")
+	_ = cmd.RegisterFlagCompletionFunc("assignee", completeAssignees)
+	cmd.Flags().StringVar(&o.search, "search", "", "Search text")
+	cmd.Flags().StringVar(&o.project, "project", "", "Project filter")
+	cmd.Flags().StringVarP(&o.query, "query", "q", "", "Query (see track list --help)")
+}
+
+// filter builds the list filter. Without a status filter, done and archived
+// issues are excluded.
+func (o *listFilterFlags) filter(ctx context.Context, store *sqlite.Store, sort string) (sqlite.ListFilter, error) {
+	statuses, err := parseStatusFilter(o.status, func(v string) error {
+		return store.ValidateStatus(ctx, v)
+	})
+	if err != nil {
+		return sqlite.ListFilter{}, err
+	}
+
+	filter := sqlite.ListFilter{
+		Statuses: statuses,
+		Label:    o.label,
+		Assignee: issue.NormalizeAssignee(o.assignee),
+		Search:   o.search,
+		Project:  o.project,
+		Sort:     sort,
+	}
+	if o.query != "" {
+		if err := query.Apply(o.query, &filter); err != nil {
+			return sqlite.ListFilter{}, err
+		}
+		for _, st := range append(slices.Clone(filter.Statuses), filter.ExcludeStatuses...) {
+			if err := store.ValidateStatus(ctx, st); err != nil {
+				return sqlite.ListFilter{}, err
+			}
+		}
+	}
+	filter.ExcludeDone = len(filter.Statuses) == 0
+	filter.ExcludeArchived = len(filter.Statuses) == 0
+	if len(filter.Statuses) == 0 {
+		filter.ExcludeStatuses = append(filter.ExcludeStatuses, o.hide...)
+	}
+	return filter, nil
+}
+
+// snoozedStatus is the custom status list.hide_snoozed hides.
+const snoozedStatus = "snoozed"
+
+// applyListDefaults fills in the list.* config for --status and --sort when
+// they are not given. The default status is skipped when -q filters by
+// status itself.
+func applyListDefaults(cmd *cobra.Command, d appconfig.ListDefaults, flags *listFilterFlags, sort *string) {
+	if d.DefaultSort != "" && !cmd.Flags().Changed("sort") {
+		*sort = d.DefaultSort
+	}
+	if d.DefaultStatus != "" && !cmd.Flags().Changed("status") && !queryFiltersStatus(flags.query) {
+		flags.status = d.DefaultStatus
+	}
+	if d.HideSnoozed {
+		flags.hide = append(flags.hide, snoozedStatus)
+	}
+}
+
+func queryFiltersStatus(q string) bool {
+	for _, word := range strings.Fields(q) {
+		if strings.HasPrefix(strings.TrimPrefix(word, "-"), "status") {
+			return true
+		}
+	}
+	return false
+}
+
 func newListCmd() *cobra.Command {
 	var (
-		status   string
-		label    string
-		assignee string
-		search   string
-		project  string
-		sort     string
+		flags     listFilterFlags
+		sort      string
+		remote    string
+		unblocked bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List issues",
+		Long:  "List issues. --status and --sort default to the list.default_status and list.default_sort config, and list.hide_snoozed leaves out issues in the snoozed status unless a status filter is given. With --status blocked, each issue also shows how long it has been blocked and why. Open issues waiting on dependencies that are neither done nor archived show their blockers before the title; --unblocked leaves them out.\n\n" + queryHelp,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
+			cfg, err := appconfig.Load()
+			if err != nil {
+				return err
+			}
+			applyListDefaults(cmd, cfg.List, &flags, &sort)
+			if remote != "" {
+				return remoteList(cmd, remote, &flags, sort)
+			}
 			store, err := sqlite.Open(ctx)
 			if err != nil {
 				return err
 			}
 			defer store.Close()
 
-			statuses, err := parseStatusFilter(status, func(v string) error {
-				return store.ValidateStatus(ctx, v)
-			})
+			filter, err := flags.filter(ctx, store, sort)
 			if err != nil {
 				return err
 			}
-
-			items, err := store.ListIssues(ctx, sqlite.ListFilter{
-				Statuses:        statuses,
-				ExcludeDone:     len(statuses) == 0,
-				ExcludeArchived: len(statuses) == 0,
-				Label:           label,
-				Assignee:        issue.NormalizeAssignee(assignee),
-				Search:          search,
-				Project:         project,
-				Sort:            sort,
-			})
+			filter.Unblocked = unblocked
+			items, err := store.ListIssues(ctx, filter)
+			if err != nil {
+				return err
+			}
+			inherited, err := service.InheritedPriorities(ctx, store)
+			if err != nil {
+				return err
+			}
+			blockedBy, err := store.BlockedBy(ctx)
 			if err != nil {
 				return err
 			}
 
-			c := newCLIColor(cmd.OutOrStdout())
-			layout := issueListLayoutForItems(items)
-			fmt.Fprintln(cmd.OutOrStdout(), formatIssueListRowWithLayout(layout, "ID", "STATUS", "PRIORITY", "TITLE", "LABELS"))
-			for _, it := range items {
-				fmt.Fprintln(
-					cmd.OutOrStdout(),
-					formatIssueListRowWithLayout(layout, it.ID, c.status(it.Status), c.priority(it.Priority), it.Title, strings.Join(it.Labels, ",")),
-				)
+			if slices.Equal(filter.Statuses, []string{issue.StatusBlocked}) {
+				blocks, err := store.CurrentBlocks(ctx)
+				if err != nil {
+					return err
+				}
+				printBlockedIssueList(cmd.OutOrStdout(), items, inherited, blockedBy, blocks, time.Now())
+				return nil
 			}
+			printIssueListInherited(cmd.OutOrStdout(), items, inherited, blockedBy)
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&status, "status", "", "Status filter (comma separated)")
-	cmd.Flags().StringVar(&label, "label", "", "Label filter")
-	cmd.Flags().StringVar(&assignee, "assignee", "", "Assignee filter")
-	cmd.Flags().StringVar(&search, "search", "", "Search text")
-	cmd.Flags().StringVar(&project, "project", "", "Project filter")
-	cmd.Flags().StringVar(&sort, "sort", "manual", "Sort by priority|due|updated|manual")
+	flags.register(cmd)
+	cmd.Flags().StringVar(&sort, "sort", "manual", "Sort keys, comma separated, - for descending (priority|due|manual|title|id|updated|created, e.g. priority,-updated)")
+	cmd.Flags().StringVar(&remote, "remote", "", remoteFlagUsage)
+	cmd.Flags().BoolVar(&unblocked, "unblocked", false, "Leave out issues waiting on dependencies that are neither done nor archived")
 
 	return cmd
 }
 
-func newShowCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "show <id>",
-		Short: "Show issue detail",
-		Args:  cobra.ExactArgs(1),
+func printIssueList(out io.Writer, items []issue.Item) {
+	printIssueListInherited(out, items, nil, nil)
+}
+
+// printIssueListInherited prints items with the priorities they inherit next
+// to their own, as "p2 (eff p0)", and the blockers from blockedBy they are
+// still waiting on.
+func printIssueListInherited(out io.Writer, items []issue.Item, inherited map[string]service.InheritedPriority, blockedBy map[string][]string) {
+	c := newCLIColor(out)
+	layout := issueListLayoutForItems(items)
+	for _, it := range items {
+		if w := listDisplayWidth(listPriority(cliColor{}, it, inherited)); w > layout.priorityWidth {
+			layout.priorityWidth = w
+		}
+	}
+	fmt.Fprintln(out, formatIssueListRowWithLayout(layout, "ID", "STATUS", "PRIORITY", "TITLE", "LABELS"))
+	for _, it := range items {
+		fmt.Fprintln(
+			out,
+			formatIssueListRowWithLayout(layout, it.ID, c.status(it.Status), listPriority(c, it, inherited), blockedPrefix(it, blockedBy)+listTitleWithProgress(it), strings.Join(it.Labels, ",")),
+		)
+	}
+}
+
+// printBlockedIssueList prints items like printIssueListInherited, followed by
+// how long each has been blocked and why, from blocks.
+func printBlockedIssueList(out io.Writer, items []issue.Item, inherited map[string]service.InheritedPriority, blockedBy map[string][]string, blocks map[string]sqlite.Block, now time.Time) {
+	c := newCLIColor(out)
+	layout := issueListLayoutForItems(items)
+	for _, it := range items {
+		if w := listDisplayWidth(listPriority(cliColor{}, it, inherited)); w > layout.priorityWidth {
+			layout.priorityWidth = w
+		}
+	}
+	fmt.Fprintf(out, "%s %-7s %s\n", formatIssueListRowWithLayout(layout, "ID", "STATUS", "PRIORITY", "TITLE", "LABELS"), "BLOCKED", "REASON")
+	for _, it := range items {
+		row := formatIssueListRowWithLayout(layout, it.ID, c.status(it.Status), listPriority(c, it, inherited), blockedPrefix(it, blockedBy)+listTitleWithProgress(it), strings.Join(it.Labels, ","))
+		age, reason := "-", ""
+		if b, ok := blocks[it.ID]; ok {
+			age, reason = stats.FormatAge(b.Duration(now)), b.Reason
+		}
+		fmt.Fprintln(out, strings.TrimRight(fmt.Sprintf("%s %-7s %s", row, age, reason), " "))
+	}
+}
+
+func listPriority(c cliColor, it issue.Item, inherited map[string]service.InheritedPriority) string {
+	p := c.priority(it.Priority)
+	if in, ok := inherited[it.ID]; ok {
+		p += " (eff " + c.priority(in.Priority) + ")"
+	}
+	return p
+}
+
+func newCountCmd() *cobra.Command {
+	var (
+		flags  listFilterFlags
+		exists bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "count",
+		Short: "Count matching issues",
+		Long:  "Print the number of issues matching the filters. With --exists, print true and succeed when any issue matches, or fail when none does.\n\n" + queryHelp,
+		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 			store, err := sqlite.Open(ctx)
@@ -519,58 +781,345 @@ func newShowCmd() *cobra.Command {
 			}
 			defer store.Close()
 
-			it, err := store.GetIssue(ctx, normalizeIssueIDArg(args[0]))
+			filter, err := flags.filter(ctx, store, "")
 			if err != nil {
 				return err
 			}
+			n, err := store.CountIssues(ctx, filter)
+			if err != nil {
+				return err
+			}
+			if exists {
+				if n == 0 {
+					return fmt.Errorf("no matching issues")
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "true")
+				return nil
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), n)
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+	cmd.Flags().BoolVar(&exists, "exists", false, "Only report whether any issue matches (exit status 1 when none)")
+	return cmd
+}
+
+func newSampleCmd() *cobra.Command {
+	var (
+		flags listFilterFlags
+		n     int
+		seed  int64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sample",
+		Short: "Show a random selection of matching issues",
+		Long:  "Show up to --n randomly chosen issues matching the filters, for spot-check triage.\n\n" + queryHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if n < 1 {
+				return fmt.Errorf("--n must be at least 1")
+			}
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
 
-			c := newCLIColor(cmd.OutOrStdout())
-			fmt.Fprintf(cmd.OutOrStdout(), "id: %s\n", it.ID)
-			fmt.Fprintf(cmd.OutOrStdout(), "title: %s\n", it.Title)
-			fmt.Fprintf(cmd.OutOrStdout(), "status: %s\n", c.status(it.Status))
-			fmt.Fprintf(cmd.OutOrStdout(), "priority: %s\n", c.priority(it.Priority))
-			if it.Assignee != "" {
-				fmt.Fprintf(cmd.OutOrStdout(), "assignee: %s\n", it.Assignee)
+			filter, err := flags.filter(ctx, store, "id")
+			if err != nil {
+				return err
 			}
-			if it.Due != "" {
-				fmt.Fprintf(cmd.OutOrStdout(), "due: %s\n", it.Due)
+			items, err := store.ListIssues(ctx, filter)
+			if err != nil {
+				return err
 			}
-			if len(it.Labels) > 0 {
-				fmt.Fprintf(cmd.OutOrStdout(), "labels: %s\n", strings.Join(it.Labels, ","))
+
+			r := rand.New(rand.NewPCG(uint64(seed), uint64(seed)))
+			if !cmd.Flags().Changed("seed") {
+				r = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
 			}
-			if it.NextAction != "" {
-				fmt.Fprintf(cmd.OutOrStdout(), "next_action: %s\n", it.NextAction)
+			r.Shuffle(len(items), func(i, j int) { items[i], items[j] = items[j], items[i] })
+			if len(items) > n {
+				items = items[:n]
 			}
-			branchLink, err := store.GetGitBranchLink(ctx, it.ID)
-			if err != nil && !isNotFoundErr(err) {
+			printIssueList(cmd.OutOrStdout(), items)
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+	cmd.Flags().IntVar(&n, "n", 5, "Number of issues to pick")
+	cmd.Flags().Int64Var(&seed, "seed", 0, "Random seed, to repeat a selection")
+	return cmd
+}
+
+func newShowCmd() *cobra.Command {
+	var (
+		remote   string
+		follow   bool
+		interval string
+	)
+	cmd := &cobra.Command{
+		Use:   "show <id> [id...]",
+		Short: "Show issue detail",
+		Long:  "Show issue detail. Accepts several IDs, ranges such as TRK-3..TRK-9, and ext:<id> for an issue's external ID (see new --external-id). With --follow, keeps one issue on screen and redraws it whenever it changes.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := expandIssueIDArgs(args)
+			if err != nil {
 				return err
 			}
-			if err == nil {
-				fmt.Fprintf(cmd.OutOrStdout(), "branch: %s\n", branchLink.BranchName)
-				fmt.Fprintf(cmd.OutOrStdout(), "merged: %s\n", branchMergeStatus(ctx, branchLink.BranchName))
+			if follow && (len(ids) != 1 || remote != "") {
+				return fmt.Errorf("--follow takes a single local issue")
+			}
+			if remote != "" {
+				return remoteShow(cmd, remote, ids)
+			}
+			dur, err := time.ParseDuration(interval)
+			if err != nil || dur <= 0 {
+				return fmt.Errorf("invalid interval: %s", interval)
 			}
-			projectKey, err := store.GetIssueProject(ctx, it.ID)
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			store, err := sqlite.Open(ctx)
 			if err != nil {
 				return err
 			}
-			if projectKey != "" {
-				fmt.Fprintf(cmd.OutOrStdout(), "project: %s\n", projectKey)
+			defer store.Close()
+			if ids, err = resolveExternalIDs(ctx, store, ids); err != nil {
+				return err
 			}
-			if it.Body != "" {
-				fmt.Fprintf(cmd.OutOrStdout(), "body: %s\n", it.Body)
+
+			if follow {
+				return followIssueDetail(ctx, cmd.OutOrStdout(), store, ids[0], dur)
+			}
+			if len(ids) == 1 {
+				return printIssueDetail(ctx, cmd, store, ids[0])
+			}
+			failed := 0
+			for i, id := range ids {
+				if i > 0 {
+					fmt.Fprintln(cmd.OutOrStdout())
+				}
+				if err := printIssueDetail(ctx, cmd, store, id); err != nil {
+					failed++
+					fmt.Fprintf(cmd.ErrOrStderr(), "%s: %v\n", id, err)
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d issues failed", failed, len(ids))
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "updated_at: %s\n", it.UpdatedAt)
 			return nil
 		},
 	}
+	cmd.Flags().StringVar(&remote, "remote", "", remoteFlagUsage)
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Redraw the issue whenever it changes (e.g. while an agent works on it)")
+	cmd.Flags().StringVar(&interval, "interval", "1s", "Polling interval for --follow")
+	return cmd
+}
+
+// followIssueDetail polls the issue and redraws it whenever its rendering
+// changes, clearing the screen on a terminal. Changes may come from other
+// processes, so it polls rather than subscribing to the in-process event bus.
+func followIssueDetail(ctx context.Context, out io.Writer, store *sqlite.Store, id string, interval time.Duration) error {
+	c := newCLIColor(out)
+	clear := isTerminal(out)
+	last := ""
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		var buf bytes.Buffer
+		if err := writeIssueDetail(ctx, &buf, c, store, id); err != nil {
+			return err
+		}
+		if rendered := buf.String(); rendered != last {
+			switch {
+			case clear:
+				fmt.Fprint(out, "\033[H\033[2J")
+			case last != "":
+				fmt.Fprintln(out)
+			}
+			fmt.Fprint(out, rendered)
+			last = rendered
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func printIssueDetail(ctx context.Context, cmd *cobra.Command, store *sqlite.Store, id string) error {
+	return writeIssueDetail(ctx, cmd.OutOrStdout(), newCLIColor(cmd.OutOrStdout()), store, id)
+}
+
+func writeIssueDetail(ctx context.Context, out io.Writer, c cliColor, store *sqlite.Store, id string) error {
+	it, err := store.GetIssue(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	vars, err := store.TemplateVars(ctx, it)
+	if err != nil {
+		return err
+	}
+	it.NextAction = issue.ExpandTemplate(it.NextAction, vars)
+	it.Body = issue.ExpandTemplate(it.Body, vars)
+
+	fmt.Fprintf(out, "id: %s\n", it.ID)
+	fmt.Fprintf(out, "title: %s\n", it.Title)
+	fmt.Fprintf(out, "status: %s\n", c.status(it.Status))
+	if it.Status == issue.StatusBlocked {
+		blocks, err := store.ListBlocks(ctx, it.ID)
+		if err != nil {
+			return err
+		}
+		if n := len(blocks); n > 0 && blocks[n-1].Until == "" {
+			b := blocks[n-1]
+			line := fmt.Sprintf("blocked: %s (since %s)", stats.FormatAge(b.Duration(time.Now())), b.Since)
+			if b.Reason != "" {
+				line += ": " + b.Reason
+			}
+			fmt.Fprintln(out, line)
+		}
+	}
+	in, inherits, err := service.InheritedPriorityOf(ctx, store, it.ID)
+	if err != nil {
+		return err
+	}
+	if inherits {
+		fmt.Fprintf(out, "priority: %s (effective %s, inherited from %s)\n", c.priority(it.Priority), c.priority(in.Priority), in.From)
+	} else {
+		fmt.Fprintf(out, "priority: %s\n", c.priority(it.Priority))
+	}
+	if it.Assignee != "" {
+		fmt.Fprintf(out, "assignee: %s\n", it.Assignee)
+	}
+	if it.Due != "" {
+		loc, err := appconfig.LoadLocation()
+		if err != nil {
+			return err
+		}
+		due := it.Due
+		if it.Status != issue.StatusDone && it.Status != issue.StatusArchived && issue.IsOverdue(it.Due, time.Now(), loc) {
+			due += " (overdue)"
+		}
+		fmt.Fprintf(out, "due: %s\n", due)
+	}
+	if len(it.Labels) > 0 {
+		fmt.Fprintf(out, "labels: %s\n", strings.Join(it.Labels, ","))
+	}
+	if it.NextAction != "" {
+		fmt.Fprintf(out, "next_action: %s\n", it.NextAction)
+	}
+	if it.ExternalID != "" {
+		fmt.Fprintf(out, "external_id: %s\n", it.ExternalID)
+	}
+	if it.Estimate != "" {
+		fmt.Fprintf(out, "estimate: %s\n", it.Estimate)
+	}
+	entries, err := store.ListTimeEntries(ctx, it.ID)
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		var spent time.Duration
+		for _, e := range entries {
+			spent += e.Duration()
+		}
+		fmt.Fprintf(out, "time_spent: %s\n", issue.FormatEstimate(spent.Round(time.Minute)))
+	}
+	if it.Pinned {
+		fmt.Fprintln(out, "pinned: yes")
+	}
+	if done, total := issue.ChecklistProgress(it.Body); total > 0 {
+		fmt.Fprintf(out, "acceptance: %d/%d\n", done, total)
+	}
+	branchLink, err := store.GetGitBranchLink(ctx, it.ID)
+	if err != nil && !isNotFoundErr(err) {
+		return err
+	}
+	if err == nil {
+		fmt.Fprintf(out, "branch: %s\n", branchLink.BranchName)
+		fmt.Fprintf(out, "merged: %s\n", branchMergeStatus(ctx, branchLink.BranchName))
+	}
+	projectKey, err := store.GetIssueProject(ctx, it.ID)
+	if err != nil {
+		return err
+	}
+	if projectKey != "" {
+		fmt.Fprintf(out, "project: %s\n", projectKey)
+	}
+	links, err := store.ListLinks(ctx, it.ID)
+	if err != nil {
+		return err
+	}
+	for _, l := range links {
+		if l.Title != "" {
+			fmt.Fprintf(out, "link: %s (%s)\n", l.URL, l.Title)
+		} else {
+			fmt.Fprintf(out, "link: %s\n", l.URL)
+		}
+	}
+	deps, err := store.ListDependencyClosure(ctx, it.ID)
+	if err != nil {
+		return err
+	}
+	if len(deps[it.ID]) > 0 {
+		edges, err := dependencyEdges(ctx, store, deps[it.ID])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "depends_on: %s\n", edges)
+	}
+	if dependents := dependentsOf(deps, it.ID); len(dependents) > 0 {
+		edges, err := dependencyEdges(ctx, store, dependents)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "blocks: %s\n", edges)
+	}
+	referencedBy, err := store.ReferencedBy(ctx, it.ID)
+	if err != nil {
+		return err
+	}
+	if len(referencedBy) > 0 {
+		fmt.Fprintf(out, "referenced_by: %s\n", strings.Join(referencedBy, ", "))
+	}
+	if it.Body != "" {
+		fmt.Fprintf(out, "body: %s\n", it.Body)
+	}
+	times, err := timefmt.Load()
+	if err != nil {
+		return err
+	}
+	if err := writeRecentComments(ctx, out, store, it.ID, times); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "created_at: %s\n", times.Time(it.CreatedAt))
+	fmt.Fprintf(out, "updated_at: %s\n", times.Time(it.UpdatedAt))
+	return nil
 }
 
-func normalizeIssueIDArg(raw string) string {
-	id := strings.TrimSpace(raw)
-	if _, err := strconv.Atoi(id); err == nil {
-		return "TRK-" + id
+// listTitleWithProgress prefixes the title with acceptance criteria progress
+// so it survives title truncation.
+func listTitleWithProgress(it issue.Item) string {
+	done, total := issue.ChecklistProgress(it.Body)
+	if total == 0 {
+		return it.Title
 	}
-	return id
+	return fmt.Sprintf("[%d/%d] %s", done, total, it.Title)
+}
+
+func normalizeIssueIDArg(raw string) string {
+	return track.NormalizeID(raw)
 }
 
 func parseStatusFilter(raw string, validate func(string) error) ([]string, error) {
@@ -602,13 +1151,17 @@ func isNotFoundErr(err error) bool {
 
 func newEditCmd() *cobra.Command {
 	var (
-		title string
-		body  string
+		title       string
+		body        string
+		appendBody  string
+		prependBody string
+		interactive bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "edit <id>",
 		Short: "Edit issue title/body",
+		Long:  "Edit issue title/body. --interactive opens the body in $VISUAL or $EDITOR; if someone else changes the body before you save, it offers to merge both edits, overwrite theirs, or abort.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
@@ -625,15 +1178,39 @@ func newEditCmd() *cobra.Command {
 			if cmd.Flags().Changed("body") {
 				in.Body = &body
 			}
-			if in.Title == nil && in.Body == nil {
+			if cmd.Flags().Changed("append-body") {
+				in.AppendBody = &appendBody
+			}
+			if cmd.Flags().Changed("prepend-body") {
+				in.PrependBody = &prependBody
+			}
+			if in.Body != nil && (in.AppendBody != nil || in.PrependBody != nil) {
+				return fmt.Errorf("--body cannot be combined with --append-body or --prepend-body")
+			}
+			if interactive && (in.AppendBody != nil || in.PrependBody != nil) {
+				return fmt.Errorf("--interactive cannot be combined with --append-body or --prepend-body")
+			}
+			if interactive {
+				if in.Body != nil {
+					return fmt.Errorf("--interactive cannot be combined with --body")
+				}
+				edited, ok, err := editBodyInteractive(ctx, store, cmd, normalizeIssueIDArg(args[0]))
+				if err != nil {
+					return err
+				}
+				if ok {
+					in.Body = &edited
+				}
+				if in.Title == nil && in.Body == nil {
+					fmt.Fprintln(cmd.OutOrStdout(), "no changes")
+					return nil
+				}
+			}
+			if in.Title == nil && in.Body == nil && in.AppendBody == nil && in.PrependBody == nil {
 				return fmt.Errorf("no fields to update")
 			}
 
-			updated, err := store.UpdateIssue(ctx, args[0], in)
-			if err != nil {
-				return err
-			}
-			if err := hooks.RunEvent(ctx, store, hooks.IssueUpdated, updated.ID); err != nil {
+			if _, err := service.UpdateIssue(ctx, store, args[0], service.Update{UpdateIssueInput: in}); err != nil {
 				return err
 			}
 			fmt.Fprintln(cmd.OutOrStdout(), "ok")
@@ -643,10 +1220,115 @@ func newEditCmd() *cobra.Command {
 
 	cmd.Flags().StringVar(&title, "title", "", "New title")
 	cmd.Flags().StringVar(&body, "body", "", "New body")
+	cmd.Flags().StringVar(&appendBody, "append-body", "", "Add a line to the end of the body")
+	cmd.Flags().StringVar(&prependBody, "prepend-body", "", "Add a line to the start of the body")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Edit the body in $EDITOR, merging concurrent changes")
 
 	return cmd
 }
 
+// runEditor opens text in the user's editor and returns what was saved.
+var runEditor = func(cmd *cobra.Command, text string) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	f, err := os.CreateTemp("", "track-edit-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(text); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(editor)
+	c := exec.Command(fields[0], append(fields[1:], f.Name())...)
+	c.Stdin, c.Stdout, c.Stderr = cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr()
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("editor %s: %w", editor, err)
+	}
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+// editBodyInteractive edits an issue body in the editor. Before saving it
+// checks the body's latest revision; if the body changed meanwhile, the user
+// picks between a three-way merge, overwriting, and aborting. ok is false
+// when there is nothing to save.
+func editBodyInteractive(ctx context.Context, store *sqlite.Store, cmd *cobra.Command, issueID string) (string, bool, error) {
+	it, err := store.GetIssue(ctx, issueID)
+	if err != nil {
+		return "", false, err
+	}
+	baseRev, err := latestRevision(ctx, store, issueID)
+	if err != nil {
+		return "", false, err
+	}
+	base := it.Body
+	edited, err := runEditor(cmd, base)
+	if err != nil || edited == base {
+		return "", false, err
+	}
+
+	reader := bufio.NewReader(cmd.InOrStdin())
+	out := cmd.OutOrStdout()
+	for {
+		current, err := store.GetIssue(ctx, issueID)
+		if err != nil {
+			return "", false, err
+		}
+		currentRev, err := latestRevision(ctx, store, issueID)
+		if err != nil {
+			return "", false, err
+		}
+		if currentRev == baseRev && current.Body == base {
+			return edited, edited != current.Body, nil
+		}
+
+		choice, err := readPromptLine(reader, out, fmt.Sprintf("%s changed while you were editing (rev %d -> %d). [m]erge, [o]verwrite, [a]bort: ", issueID, baseRev, currentRev))
+		if err != nil {
+			return "", false, err
+		}
+		switch strings.ToLower(strings.TrimSpace(choice)) {
+		case "m", "merge":
+			merged, clean := textdiff.Merge3(base, edited, current.Body)
+			if !clean {
+				fmt.Fprintln(out, "conflicting changes; resolve the marked sections")
+				if merged, err = runEditor(cmd, merged); err != nil {
+					return "", false, err
+				}
+				if strings.Contains(merged, textdiff.MarkerOurs) || strings.Contains(merged, textdiff.MarkerTheirs) {
+					return "", false, fmt.Errorf("conflict markers left in the body; nothing saved")
+				}
+			}
+			edited, base, baseRev = merged, current.Body, currentRev
+		case "o", "overwrite":
+			return edited, edited != current.Body, nil
+		default:
+			return "", false, fmt.Errorf("edit aborted; nothing saved")
+		}
+	}
+}
+
+func latestRevision(ctx context.Context, store *sqlite.Store, issueID string) (int, error) {
+	revs, err := store.ListRevisions(ctx, issueID)
+	if err != nil || len(revs) == 0 {
+		return 0, err
+	}
+	return revs[len(revs)-1].Rev, nil
+}
+
 func newSetCmd() *cobra.Command {
 	var (
 		title      string
@@ -655,14 +1337,48 @@ func newSetCmd() *cobra.Command {
 		due        string
 		assignee   string
 		nextAction string
+		estimate   string
 		project    string
+		labels     []string
+		remote     string
+		reason     string
 	)
 
 	cmd := &cobra.Command{
-		Use:   "set <id>",
+		Use:   "set <id> [id...]",
 		Short: "Set issue fields",
-		Args:  cobra.ExactArgs(1),
+		Long: `Set issue fields. Accepts several IDs and ranges such as TRK-3..TRK-9; each
+issue is reported separately.
+
+Moving issues to the blocked status records why and since when they are
+blocked; --reason gives the reason, and without it a terminal is asked for
+one. list --status blocked and show print the reason and how long the issue
+has been blocked, and stats blocked sums it up per issue.`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := expandIssueIDArgs(args)
+			if err != nil {
+				return err
+			}
+			if remote != "" {
+				if cmd.Flags().Changed("estimate") {
+					return fmt.Errorf("--estimate is not supported with --remote")
+				}
+				if cmd.Flags().Changed("reason") {
+					return fmt.Errorf("--reason is not supported with --remote")
+				}
+				var patch client.IssuePatch
+				for flag, field := range map[string]**string{
+					"title": &patch.Title, "status": &patch.Status, "priority": &patch.Priority, "due": &patch.Due,
+					"assignee": &patch.Assignee, "next-action": &patch.NextAction, "project": &patch.Project,
+				} {
+					if cmd.Flags().Changed(flag) {
+						v, _ := cmd.Flags().GetString(flag)
+						*field = &v
+					}
+				}
+				return remoteSet(cmd, remote, ids, patch, labels)
+			}
 			ctx := context.Background()
 			store, err := sqlite.Open(ctx)
 			if err != nil {
@@ -702,54 +1418,57 @@ func newSetCmd() *cobra.Command {
 			if cmd.Flags().Changed("next-action") {
 				in.NextAction = &nextAction
 			}
-			hasProjectUpdate := cmd.Flags().Changed("project")
-			if in.Title == nil && in.Status == nil && in.Priority == nil && in.Due == nil && in.Assignee == nil && in.NextAction == nil && !hasProjectUpdate {
-				return fmt.Errorf("no fields to update")
+			if cmd.Flags().Changed("estimate") {
+				if _, err := issue.NormalizeEstimate(estimate); err != nil {
+					return err
+				}
+				in.Estimate = &estimate
 			}
-
-			issueID := normalizeIssueIDArg(args[0])
-			updatedIssueID := issueID
-			if in.Title != nil || in.Status != nil || in.Priority != nil || in.Due != nil || in.Assignee != nil || in.NextAction != nil {
-				updated, err := store.UpdateIssue(ctx, issueID, in)
+			if cmd.Flags().Changed("reason") {
+				in.BlockedReason = &reason
+			} else if in.Status != nil && *in.Status == issue.StatusBlocked && isTerminalInput(cmd.InOrStdin()) {
+				line, err := readPromptLine(bufio.NewReader(cmd.InOrStdin()), cmd.OutOrStdout(), "reason: ")
 				if err != nil {
 					return err
 				}
-				updatedIssueID = updated.ID
-				if in.Status != nil {
-					if err := hooks.RunEvent(ctx, store, hooks.IssueStatusChange, updated.ID); err != nil {
-						return err
-					}
-					if *in.Status == issue.StatusDone {
-						if err := hooks.RunEvent(ctx, store, hooks.IssueCompleted, updated.ID); err != nil {
-							return err
-						}
-					}
+				in.BlockedReason = &line
+			}
+			for _, label := range labels {
+				if strings.TrimSpace(label) == "" {
+					return fmt.Errorf("label must not be empty")
 				}
 			}
-			if hasProjectUpdate {
-				projectValue := strings.TrimSpace(project)
-				if projectValue == "none" {
-					projectValue = ""
+			return runForIssues(cmd, ids, func(id string) error {
+				u := service.Update{UpdateIssueInput: in}
+				if cmd.Flags().Changed("project") {
+					u.Project = &project
 				}
-				if err := store.SetIssueProject(ctx, issueID, projectValue); err != nil {
-					return err
+				if len(labels) > 0 {
+					current, err := store.GetIssue(ctx, id)
+					if err != nil {
+						return err
+					}
+					next := append(slices.Clone(current.Labels), labels...)
+					u.Labels = &next
 				}
-			}
-			if err := hooks.RunEvent(ctx, store, hooks.IssueUpdated, updatedIssueID); err != nil {
+				_, err := service.UpdateIssue(ctx, store, id, u)
 				return err
-			}
-			fmt.Fprintln(cmd.OutOrStdout(), "ok")
-			return nil
+			})
 		},
 	}
 
 	cmd.Flags().StringVar(&title, "title", "", "Title")
 	cmd.Flags().StringVar(&status, "status", "", "Status")
 	cmd.Flags().StringVar(&priority, "priority", "", "Priority")
-	cmd.Flags().StringVar(&due, "due", "", "Due date")
+	cmd.Flags().StringVar(&due, "due", "", "Due date (YYYY-MM-DD, or YYYY-MM-DDTHH:MM in the configured timezone)")
 	cmd.Flags().StringVar(&assignee, "assignee", "", "Assignee")
+	_ = cmd.RegisterFlagCompletionFunc("assignee", completeAssignees)
 	cmd.Flags().StringVar(&nextAction, "next-action", "", "Next action")
+	cmd.Flags().StringVar(&estimate, "estimate", "", "Estimated effort such as 45m or 1h30m (empty clears it)")
 	cmd.Flags().StringVar(&project, "project", "", "Project key or none")
+	cmd.Flags().StringArrayVar(&labels, "label", nil, "Attach label (repeatable)")
+	cmd.Flags().StringVar(&remote, "remote", "", remoteFlagUsage)
+	cmd.Flags().StringVar(&reason, "reason", "", "Why the issue is blocked (with --status blocked, or for an issue that already is)")
 
 	return cmd
 }
@@ -768,17 +1487,16 @@ func newLabelCmd() *cobra.Command {
 		}
 		defer store.Close()
 
-		updated, err := store.GetIssue(ctx, args[0])
-		if err != nil {
+		if _, err := store.GetIssue(ctx, args[0]); err != nil {
 			return err
 		}
+		batchCtx, flush := sqlite.BatchEvents(ctx)
 		for _, label := range args[1:] {
-			updated, err = store.AddLabel(ctx, args[0], label)
-			if err != nil {
+			if _, err := store.AddLabel(batchCtx, args[0], label); err != nil {
 				return err
 			}
 		}
-		if err := hooks.RunEvent(ctx, store, hooks.IssueUpdated, updated.ID); err != nil {
+		if err := flush(); err != nil {
 			return err
 		}
 		fmt.Fprintln(cmd.OutOrStdout(), "ok")
@@ -793,17 +1511,16 @@ func newLabelCmd() *cobra.Command {
 		}
 		defer store.Close()
 
-		updated, err := store.GetIssue(ctx, args[0])
-		if err != nil {
+		if _, err := store.GetIssue(ctx, args[0]); err != nil {
 			return err
 		}
+		batchCtx, flush := sqlite.BatchEvents(ctx)
 		for _, label := range args[1:] {
-			updated, err = store.RemoveLabel(ctx, args[0], label)
-			if err != nil {
+			if _, err := store.RemoveLabel(batchCtx, args[0], label); err != nil {
 				return err
 			}
 		}
-		if err := hooks.RunEvent(ctx, store, hooks.IssueUpdated, updated.ID); err != nil {
+		if err := flush(); err != nil {
 			return err
 		}
 		fmt.Fprintln(cmd.OutOrStdout(), "ok")
@@ -840,9 +1557,11 @@ func newLabelCmd() *cobra.Command {
 }
 
 func newNextCmd() *cobra.Command {
-	return &cobra.Command{
+	var remote string
+	cmd := &cobra.Command{
 		Use:   "next",
 		Short: "Show next actionable issue",
+		Long:  "Show the most urgent todo or ready issue, skipping issues that wait on dependencies that are neither done nor archived.",
 		Args:  cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
@@ -850,34 +1569,52 @@ func newNextCmd() *cobra.Command {
 				return fmt.Errorf("unexpected args")
 			}
 			ctx := context.Background()
-			store, err := sqlite.Open(ctx)
+			if remote != "" {
+				c, err := remoteClient(ctx, remote)
+				if err != nil {
+					return err
+				}
+				next, err := c.Next(ctx)
+				if err != nil {
+					return err
+				}
+				if next == nil {
+					fmt.Fprintln(cmd.OutOrStdout(), "no actionable issues")
+					return nil
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\n", next.ID, next.Priority, next.Title)
+				return nil
+			}
+			tracker, err := track.Open(ctx)
 			if err != nil {
 				return err
 			}
-			defer store.Close()
+			defer tracker.Close()
 
-			items, err := store.ListIssues(ctx, sqlite.ListFilter{
-				Statuses: []string{issue.StatusTodo, issue.StatusReady},
-				Sort:     "priority_manual",
-			})
+			next, ok, err := tracker.Next(ctx)
 			if err != nil {
 				return err
 			}
-			if len(items) == 0 {
+			if !ok {
 				fmt.Fprintln(cmd.OutOrStdout(), "no actionable issues")
 				return nil
 			}
-			next := items[0]
 			fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\n", next.ID, next.Priority, next.Title)
 			return nil
 		},
 	}
+	cmd.Flags().StringVar(&remote, "remote", "", remoteFlagUsage)
+	return cmd
 }
 
 func newPlanningCmd() *cobra.Command {
 	var limit int
 	var yes bool
 	var dryRun bool
+	var priority string
+	var project string
+	var label string
+	var sort string
 
 	cmd := &cobra.Command{
 		Use:   "planning [id]",
@@ -903,10 +1640,17 @@ func newPlanningCmd() *cobra.Command {
 				}
 				items = []issue.Item{it}
 			} else {
+				priorities, err := parsePriorityFilter(priority)
+				if err != nil {
+					return err
+				}
 				items, err = store.ListIssues(ctx, sqlite.ListFilter{
-					Statuses: []string{issue.StatusTodo},
-					Assignee: "agent",
-					Sort:     "manual",
+					Statuses:   []string{issue.StatusTodo},
+					Priorities: priorities,
+					Assignee:   "agent",
+					Label:      strings.TrimSpace(label),
+					Project:    strings.TrimSpace(project),
+					Sort:       sort,
 				})
 				if err != nil {
 					return err
@@ -964,6 +1708,10 @@ func newPlanningCmd() *cobra.Command {
 				if err != nil {
 					return err
 				}
+				updated, err = applyPlanningOutcome(ctx, store, cmd, it, updated)
+				if err != nil {
+					return err
+				}
 				if updated.Status == issue.StatusReady {
 					updatedCount++
 					fmt.Fprintf(out, "%s updated to ready\n", it.ID)
@@ -981,29 +1729,116 @@ func newPlanningCmd() *cobra.Command {
 	cmd.Flags().IntVar(&limit, "limit", 0, "Limit number of issues to process")
 	cmd.Flags().BoolVar(&yes, "yes", false, "Skip confirmation prompts and run planning for each target issue")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview planning targets without running sessions")
+	cmd.Flags().StringVar(&priority, "priority", "", "Priority filter (comma separated)")
+	cmd.Flags().StringVar(&project, "project", "", "Project filter")
+	cmd.Flags().StringVar(&label, "label", "", "Label filter")
+	cmd.Flags().StringVar(&sort, "sort", "manual", "Sort keys, comma separated, - for descending (priority|due|manual|title|id|updated|created, e.g. priority,-updated)")
 	return cmd
 }
 
+// applyPlanningOutcome inspects the body written by a planning session. New
+// questions hand the issue to the user, and a ready issue without a spec and
+// acceptance criteria is sent back to todo.
+func applyPlanningOutcome(ctx context.Context, store *sqlite.Store, cmd *cobra.Command, before, after issue.Item) (issue.Item, error) {
+	out := cmd.OutOrStdout()
+
+	questions := sectionContent(after.Body, issue.SectionQuestions)
+	if questions != "" && questions != sectionContent(before.Body, issue.SectionQuestions) {
+		if after.Assignee != "user" {
+			assignee := "user"
+			updated, err := store.UpdateIssue(ctx, after.ID, sqlite.UpdateIssueInput{Assignee: &assignee})
+			if err != nil {
+				return issue.Item{}, err
+			}
+			after = updated
+		}
+		fmt.Fprintf(out, "%s assigned to user (questions added)\n", after.ID)
+		if err := notify.Send(ctx, notify.Message{
+			IssueID: after.ID,
+			Title:   fmt.Sprintf("%s has questions for you", after.ID),
+			Body:    questions,
+		}); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", err)
+		}
+	}
+
+	if after.Status != issue.StatusReady {
+		return after, nil
+	}
+	gaps := issue.ReadinessGaps(after.Body)
+	if len(gaps) == 0 {
+		return after, nil
+	}
+	if err := updateIssueStatus(ctx, store, after.ID, issue.StatusTodo); err != nil {
+		return issue.Item{}, err
+	}
+	fmt.Fprintf(out, "%s reverted to todo (missing: %s)\n", after.ID, strings.Join(gaps, ", "))
+	return store.GetIssue(ctx, after.ID)
+}
+
+func sectionContent(body, title string) string {
+	sec, ok := issue.FindSection(body, title)
+	if !ok {
+		return ""
+	}
+	return sec.Content
+}
+
+func parsePriorityFilter(raw string) ([]string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	parts := strings.Split(raw, ",")
+	out := make([]string, 0, len(parts))
+	for _, part := range parts {
+		p := strings.TrimSpace(part)
+		if p == "" {
+			continue
+		}
+		if err := issue.ValidatePriority(p); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("priority filter is empty")
+	}
+	return out, nil
+}
+
 var planningSessionRunner = func(ctx context.Context, cmd *cobra.Command, issueID string) error {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return err
 	}
-	prompt := buildPlanningPrompt(issueID)
-	args := []string{"exec", "-C", cwd}
-	trackHome, err := appconfig.HomeDir()
-	if err == nil && strings.TrimSpace(trackHome) != "" {
-		args = append(args, "--add-dir", trackHome)
-	}
-	args = append(args, prompt)
-	c := exec.CommandContext(ctx, "codex", args...)
-	if err == nil && strings.TrimSpace(trackHome) != "" {
-		c.Env = append(os.Environ(), "TRACK_HOME="+trackHome)
-	}
-	c.Stdin = cmd.InOrStdin()
-	c.Stdout = cmd.OutOrStdout()
-	c.Stderr = cmd.ErrOrStderr()
-	return c.Run()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	it, err := store.GetIssue(ctx, issueID)
+	if err != nil {
+		return err
+	}
+	agent, release, err := resolveAgent(ctx, store, it, "codex", false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	_, err = runAgentSession(ctx, store, issueID, sessionKindPlanning, agentSessionRequest{
+		Runner:  agent.Runner,
+		Agent:   agent.Name,
+		Sandbox: agent.Sandbox,
+		Dir:     cwd,
+		Prompt:  buildPlanningPrompt(issueID),
+		Stdin:   cmd.InOrStdin(),
+		Stdout:  cmd.OutOrStdout(),
+		Stderr:  cmd.ErrOrStderr(),
+	})
+	return err
 }
 
 func buildPlanningPrompt(issueID string) string {
@@ -1015,12 +1850,17 @@ Plan mode requirements: (1) update issue body with an implementation-ready, test
 
 func newReplyCmd() *cobra.Command {
 	var message string
+	var question int
 
 	cmd := &cobra.Command{
 		Use:   "reply <id>",
-		Short: "Append user reply to issue body and assign back to agent",
+		Short: "Record user reply in issue body and assign back to agent",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if question < 0 {
+				return fmt.Errorf("question must be >= 1")
+			}
+
 			ctx := context.Background()
 			store, err := sqlite.Open(ctx)
 			if err != nil {
@@ -1034,115 +1874,228 @@ func newReplyCmd() *cobra.Command {
 				return err
 			}
 
+			out := cmd.OutOrStdout()
 			replyText := strings.TrimSpace(message)
 			if replyText == "" {
-				fmt.Fprint(cmd.OutOrStdout(), "reply> ")
-				s := bufio.NewScanner(cmd.InOrStdin())
-				if !s.Scan() {
-					if err := s.Err(); err != nil {
+				reader := bufio.NewReader(cmd.InOrStdin())
+				if question == 0 {
+					question, err = promptQuestionSelection(reader, out, current.Body)
+					if err != nil {
 						return err
 					}
-					return fmt.Errorf("reply message is required")
 				}
-				replyText = strings.TrimSpace(s.Text())
+				fmt.Fprint(out, "reply> ")
+				line, err := reader.ReadString('\n')
+				if err != nil && err != io.EOF {
+					return err
+				}
+				replyText = strings.TrimSpace(line)
 			}
 			if replyText == "" {
 				return fmt.Errorf("reply message is required")
 			}
 
-			body := appendReplyToBody(current.Body, replyText)
+			batchCtx, flush := sqlite.BatchEvents(ctx)
+			if question > 0 {
+				if _, _, err := store.AnswerIssueQuestion(batchCtx, issueID, question, replyText); err != nil {
+					return err
+				}
+			} else if _, err := store.AppendIssueSection(batchCtx, issueID, issue.SectionUserReplies, "- "+replyText); err != nil {
+				return err
+			}
 			assignee := "agent"
-			updated, err := store.UpdateIssue(ctx, issueID, sqlite.UpdateIssueInput{
-				Body:     &body,
-				Assignee: &assignee,
-			})
-			if err != nil {
+			if _, err := store.UpdateIssue(batchCtx, issueID, sqlite.UpdateIssueInput{Assignee: &assignee}); err != nil {
+				return err
+			}
+			if err := store.RecordActivity(ctx, sqlite.ActivityComment, issueID, replyText); err != nil {
 				return err
 			}
-			if err := hooks.RunEvent(ctx, store, hooks.IssueUpdated, updated.ID); err != nil {
+			if err := flush(); err != nil {
 				return err
 			}
 
-			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			fmt.Fprintln(out, "ok")
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&message, "message", "m", "", "Reply message")
+	cmd.Flags().IntVar(&question, "question", 0, "Answer the n-th question under Questions for user")
 	return cmd
 }
 
-func appendReplyToBody(body, reply string) string {
-	const header = "## User replies"
-	entry := "- " + strings.TrimSpace(reply)
+// promptQuestionSelection lists unanswered questions and asks which one the
+// reply addresses. It returns 0 (a general reply) when there is nothing to
+// pick or the user leaves the prompt blank.
+func promptQuestionSelection(reader *bufio.Reader, out io.Writer, body string) (int, error) {
+	unanswered := make([]issue.Question, 0)
+	for _, q := range issue.Questions(body) {
+		if !q.Answered() {
+			unanswered = append(unanswered, q)
+		}
+	}
+	if len(unanswered) == 0 {
+		return 0, nil
+	}
 
-	body = strings.TrimRight(body, "\n")
-	if body == "" {
-		return header + "\n" + entry + "\n"
+	fmt.Fprintln(out, "unanswered questions:")
+	for _, q := range unanswered {
+		fmt.Fprintf(out, "  %d. %s\n", q.Index, q.Text)
 	}
-	if strings.Contains(body, header) {
-		return body + "\n" + entry + "\n"
+	for {
+		line, err := readPromptLine(reader, out, "question number (blank for general reply): ")
+		if err != nil {
+			return 0, err
+		}
+		v := strings.TrimSpace(line)
+		if v == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(v)
+		if err == nil {
+			for _, q := range unanswered {
+				if q.Index == n {
+					return n, nil
+				}
+			}
+		}
+		fmt.Fprintf(out, "invalid question: %s\n", v)
 	}
-	return body + "\n\n" + header + "\n" + entry + "\n"
 }
 
-func newDoneCmd() *cobra.Command {
+func newCheckCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "done <id>",
-		Short: "Mark issue as done",
-		Args:  cobra.ExactArgs(1),
+		Use:   "check <id> <n>",
+		Short: "Toggle the n-th acceptance criterion checkbox",
+		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			n, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid criterion number: %s", args[1])
+			}
+
 			ctx := context.Background()
 			store, err := sqlite.Open(ctx)
 			if err != nil {
 				return err
 			}
 			defer store.Close()
-			status := issue.StatusDone
-			updated, err := store.UpdateIssue(ctx, args[0], sqlite.UpdateIssueInput{Status: &status})
+
+			issueID := normalizeIssueIDArg(args[0])
+			current, err := store.GetIssue(ctx, issueID)
 			if err != nil {
 				return err
 			}
-			if err := hooks.RunEvent(ctx, store, hooks.IssueUpdated, updated.ID); err != nil {
+			body, item, err := issue.ToggleAcceptanceCriterion(current.Body, n)
+			if err != nil {
 				return err
 			}
-			if err := hooks.RunEvent(ctx, store, hooks.IssueStatusChange, updated.ID); err != nil {
+			updated, err := store.UpdateIssue(ctx, issueID, sqlite.UpdateIssueInput{Body: &body})
+			if err != nil {
 				return err
 			}
-			if err := hooks.RunEvent(ctx, store, hooks.IssueCompleted, updated.ID); err != nil {
-				return err
+
+			mark := " "
+			if item.Checked {
+				mark = "x"
 			}
-			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			done, total := issue.ChecklistProgress(updated.Body)
+			fmt.Fprintf(cmd.OutOrStdout(), "[%s] %d. %s (%d/%d)\n", mark, item.Index, item.Text, done, total)
 			return nil
 		},
 	}
 }
 
+func newDoneCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "done <id> [id...]",
+		Short: "Mark issue as done",
+		Long:  "Mark issues as done. Accepts several IDs and ranges such as TRK-3..TRK-9; each issue is reported separately.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := expandIssueIDArgs(args)
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
+			tracker, err := track.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer tracker.Close()
+			return runForIssues(cmd, ids, func(id string) error {
+				if !force {
+					current, err := tracker.Get(ctx, id)
+					if err != nil {
+						return err
+					}
+					if done, total := issue.ChecklistProgress(current.Body); done < total {
+						return fmt.Errorf("%s has unchecked acceptance criteria (%d/%d); use --force to override", current.ID, done, total)
+					}
+				}
+				_, err := tracker.Transition(ctx, id, track.StatusDone)
+				return err
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Mark done even when acceptance criteria remain unchecked")
+	return cmd
+}
+
 func newArchiveCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "archive <id>",
+		Use:   "archive <id> [id...]",
 		Short: "Archive issue",
-		Args:  cobra.ExactArgs(1),
+		Long:  "Archive issues. Accepts several IDs and ranges such as TRK-3..TRK-9; each issue is reported separately.",
+		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := context.Background()
-			store, err := sqlite.Open(ctx)
+			ids, err := expandIssueIDArgs(args)
 			if err != nil {
 				return err
 			}
-			defer store.Close()
-			status := issue.StatusArchived
-			updated, err := store.UpdateIssue(ctx, args[0], sqlite.UpdateIssueInput{Status: &status})
+			ctx := context.Background()
+			tracker, err := track.Open(ctx)
 			if err != nil {
 				return err
 			}
-			if err := hooks.RunEvent(ctx, store, hooks.IssueUpdated, updated.ID); err != nil {
+			defer tracker.Close()
+			return runForIssues(cmd, ids, func(id string) error {
+				_, err := tracker.Transition(ctx, id, track.StatusArchived)
+				return err
+			})
+		},
+	}
+}
+
+// newPinCmd builds `track pin`, or `track unpin` when pin is false.
+func newPinCmd(pin bool) *cobra.Command {
+	use, short := "pin", "Pin issues above all others in list, next, and the web UI"
+	if !pin {
+		use, short = "unpin", "Unpin issues"
+	}
+	return &cobra.Command{
+		Use:   use + " <id> [id...]",
+		Short: short,
+		Long:  short + ". Pinned issues sort first whatever the sort order, and stay pinned through reorder. Accepts several IDs and ranges such as TRK-3..TRK-9.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := expandIssueIDArgs(args)
+			if err != nil {
 				return err
 			}
-			if err := hooks.RunEvent(ctx, store, hooks.IssueStatusChange, updated.ID); err != nil {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
 				return err
 			}
-			fmt.Fprintln(cmd.OutOrStdout(), "ok")
-			return nil
+			defer store.Close()
+			return runForIssues(cmd, ids, func(id string) error {
+				_, err := store.SetPinned(ctx, id, pin)
+				return err
+			})
 		},
 	}
 }
@@ -1162,10 +2115,7 @@ func newReorderCmd() *cobra.Command {
 				return err
 			}
 			defer store.Close()
-			if err := store.Reorder(ctx, args[0], beforeID, afterID); err != nil {
-				return err
-			}
-			if err := hooks.RunEvent(ctx, store, hooks.IssueUpdated, args[0]); err != nil {
+			if err := service.Reorder(ctx, store, args[0], beforeID, afterID); err != nil {
 				return err
 			}
 			fmt.Fprintln(cmd.OutOrStdout(), "ok")
@@ -3,12 +3,17 @@ package cli
 import (
 	"bytes"
 	"context"
+	"os"
+	"path/filepath"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 	"unicode/utf8"
 
+	appconfig "github.com/myuon/track/internal/config"
 	"github.com/myuon/track/internal/issue"
 	"github.com/myuon/track/internal/store/sqlite"
 	"github.com/spf13/cobra"
@@ -338,6 +343,142 @@ func TestListDefaultSortMatchesManual(t *testing.T) {
 	}
 }
 
+func TestListQueryFiltersIssues(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	for _, it := range []issue.Item{
+		{Title: "match", Status: issue.StatusReady, Priority: "p1", Due: "2026-02-20", Labels: []string{"bug"}},
+		{Title: "blocked", Status: issue.StatusReady, Priority: "p0", Due: "2026-02-20", Labels: []string{"bug", "blocked"}},
+		{Title: "late", Status: issue.StatusReady, Priority: "p1", Due: "2026-03-01", Labels: []string{"bug"}},
+		{Title: "low", Status: issue.StatusReady, Priority: "p2", Due: "2026-02-20", Labels: []string{"bug"}},
+		{Title: "todo", Status: issue.StatusTodo, Priority: "p1", Due: "2026-02-20", Labels: []string{"bug"}},
+	} {
+		if _, err := store.CreateIssue(ctx, it); err != nil {
+			t.Fatalf("CreateIssue() error: %v", err)
+		}
+	}
+
+	cmd := newListCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"-q", "status:ready priority<=p1 due<2026-03-01 label:bug -label:blocked"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("list -q error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], "TRK-1") {
+		t.Fatalf("list -q should return only TRK-1, got:\n%s", out.String())
+	}
+
+	cmd = newListCmd()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"-q", "status:nope"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "invalid status") {
+		t.Fatalf("list -q with unknown status error = %v", err)
+	}
+}
+
+func TestCountAndExists(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	for _, it := range []issue.Item{
+		{Title: "a", Status: issue.StatusReady, Priority: "p1", Labels: []string{"bug"}},
+		{Title: "b", Status: issue.StatusReady, Priority: "p2", Labels: []string{"bug"}},
+		{Title: "c", Status: issue.StatusTodo, Priority: "p1", Labels: []string{"bug"}},
+	} {
+		if _, err := store.CreateIssue(ctx, it); err != nil {
+			t.Fatalf("CreateIssue() error: %v", err)
+		}
+	}
+
+	run := func(args ...string) (string, error) {
+		cmd := newCountCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return strings.TrimSpace(out.String()), err
+	}
+
+	if out, err := run("--status", "ready", "--label", "bug"); err != nil || out != "2" {
+		t.Fatalf("count = %q, %v; want 2", out, err)
+	}
+	if out, err := run("-q", "priority:p1"); err != nil || out != "2" {
+		t.Fatalf("count -q = %q, %v; want 2", out, err)
+	}
+	if out, err := run("--exists", "--status", "todo"); err != nil || out != "true" {
+		t.Fatalf("count --exists = %q, %v; want true", out, err)
+	}
+	if _, err := run("--exists", "--status", "done"); err == nil || !strings.Contains(err.Error(), "no matching issues") {
+		t.Fatalf("count --exists with no matches error = %v", err)
+	}
+}
+
+func TestSamplePicksRandomMatchingIssues(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	for i := 0; i < 6; i++ {
+		status := issue.StatusTodo
+		if i == 5 {
+			status = issue.StatusReady
+		}
+		if _, err := store.CreateIssue(ctx, issue.Item{Title: "t" + strconv.Itoa(i), Status: status, Priority: "p2"}); err != nil {
+			t.Fatalf("CreateIssue() error: %v", err)
+		}
+	}
+
+	run := func(args ...string) string {
+		cmd := newSampleCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("sample %v error: %v", args, err)
+		}
+		return out.String()
+	}
+
+	first := run("--n", "3", "--status", "todo", "--seed", "7")
+	lines := strings.Split(strings.TrimSpace(first), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("sample should print a header and 3 rows, got:\n%s", first)
+	}
+	if strings.Contains(first, "TRK-6") {
+		t.Fatalf("sample should only pick todo issues, got:\n%s", first)
+	}
+	if again := run("--n", "3", "--status", "todo", "--seed", "7"); again != first {
+		t.Fatalf("same seed should repeat the selection:\n%s\nvs\n%s", first, again)
+	}
+	if all := run("--n", "10", "--status", "todo"); len(strings.Split(strings.TrimSpace(all), "\n")) != 6 {
+		t.Fatalf("sample larger than the match set should print every match, got:\n%s", all)
+	}
+}
+
 func TestListHelpShowsManualAsDefaultSort(t *testing.T) {
 	cmd := newListCmd()
 	var out bytes.Buffer
@@ -353,6 +494,89 @@ func TestListHelpShowsManualAsDefaultSort(t *testing.T) {
 	}
 }
 
+func TestShowUsesConfiguredTimeFormat(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	cfg := appconfig.Default()
+	cfg.TimeFormat = "relative"
+	if err := appconfig.Save(cfg); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	if _, err := store.CreateIssue(ctx, issue.Item{Title: "t", Status: issue.StatusTodo, Priority: "p2"}); err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	cmd := newShowCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"TRK-1"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("show error: %v", err)
+	}
+	if !strings.Contains(out.String(), "created_at: just now\n") || !strings.Contains(out.String(), "updated_at: just now\n") {
+		t.Fatalf("show should print relative timestamps, got: %q", out.String())
+	}
+}
+
+func TestShowFollowRedrawsOnChange(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	if _, err := store.CreateIssue(ctx, issue.Item{Title: "t", Status: issue.StatusTodo, Priority: "p2"}); err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	followCtx, cancel := context.WithCancel(ctx)
+	out := &lockedBuffer{}
+	done := make(chan error, 1)
+	go func() { done <- followIssueDetail(followCtx, out, store, "TRK-1", 10*time.Millisecond) }()
+
+	waitFor := func(want string) {
+		t.Helper()
+		deadline := time.Now().Add(3 * time.Second)
+		for !strings.Contains(out.String(), want) {
+			if time.Now().After(deadline) {
+				t.Fatalf("output never contained %q: %q", want, out.String())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	waitFor("status: todo")
+	time.Sleep(50 * time.Millisecond)
+	if n := strings.Count(out.String(), "id: TRK-1"); n != 1 {
+		t.Fatalf("unchanged issue was drawn %d times", n)
+	}
+
+	ready := issue.StatusReady
+	if _, err := store.UpdateIssue(ctx, "TRK-1", sqlite.UpdateIssueInput{Status: &ready}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+	waitFor("status: ready")
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("followIssueDetail() error: %v", err)
+	}
+
+	cmd := newShowCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"1..2", "--follow"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected --follow to reject several issues")
+	}
+}
+
 func TestShowIncludesLinkedBranch(t *testing.T) {
 	tmp := t.TempDir()
 	t.Setenv("TRACK_HOME", tmp)
@@ -392,6 +616,60 @@ func TestShowIncludesLinkedBranch(t *testing.T) {
 	}
 }
 
+func TestShowExpandsTemplateVariables(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{
+		Title:      "templated",
+		Status:     issue.StatusTodo,
+		Priority:   "p2",
+		Due:        "2026-03-01",
+		NextAction: "Push {{branch}} and review {{pr_url}}",
+		Body:       "Ship by {{due}}",
+	})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	if err := store.UpsertGitBranchLink(ctx, it.ID, "feature/x"); err != nil {
+		t.Fatalf("UpsertGitBranchLink() error: %v", err)
+	}
+
+	show := func() string {
+		cmd := newShowCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs([]string{it.ID})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("show error: %v", err)
+		}
+		return out.String()
+	}
+	got := show()
+	if !strings.Contains(got, "next_action: Push feature/x and review {{pr_url}}\n") || !strings.Contains(got, "body: Ship by 2026-03-01\n") {
+		t.Fatalf("show should expand known placeholders only:\n%s", got)
+	}
+
+	if err := store.UpsertGitHubLink(ctx, it.ID, "42", "myuon/track"); err != nil {
+		t.Fatalf("UpsertGitHubLink() error: %v", err)
+	}
+	if got := show(); !strings.Contains(got, "review https://github.com/myuon/track/pull/42\n") {
+		t.Fatalf("show should expand pr_url:\n%s", got)
+	}
+	stored, err := store.GetIssue(ctx, it.ID)
+	if err != nil || stored.NextAction != "Push {{branch}} and review {{pr_url}}" {
+		t.Fatalf("stored next action should keep placeholders: %q, %v", stored.NextAction, err)
+	}
+}
+
 func TestNewDefaultsPriorityToNone(t *testing.T) {
 	tmp := t.TempDir()
 	t.Setenv("TRACK_HOME", tmp)
@@ -421,6 +699,42 @@ func TestNewDefaultsPriorityToNone(t *testing.T) {
 	}
 }
 
+func TestNewIdempotencyKeyReturnsExistingIssue(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	run := func(args ...string) (string, error) {
+		cmd := newNewCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return strings.TrimSpace(out.String()), err
+	}
+
+	first, err := run("webhook issue", "--idempotency-key", "delivery-1")
+	if err != nil {
+		t.Fatalf("new command error: %v", err)
+	}
+	second, err := run("webhook issue", "--idempotency-key", "delivery-1")
+	if err != nil {
+		t.Fatalf("retried new command error: %v", err)
+	}
+	if second != first {
+		t.Fatalf("retry created %q, want existing %q", second, first)
+	}
+	if _, err := run("other issue", "--idempotency-key", "delivery-1"); err == nil || !strings.Contains(err.Error(), "already used") {
+		t.Fatalf("reused key error = %v, want already used", err)
+	}
+	third, err := run("webhook issue")
+	if err != nil {
+		t.Fatalf("new command without key error: %v", err)
+	}
+	if third == first {
+		t.Fatalf("new without a key should create a new issue, got %q", third)
+	}
+}
+
 func TestNewTUICreatesIssue(t *testing.T) {
 	tmp := t.TempDir()
 	t.Setenv("TRACK_HOME", tmp)
@@ -475,6 +789,60 @@ func TestNewTUICreatesIssue(t *testing.T) {
 	}
 }
 
+func TestNewReadsStdin(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	run := func(stdin string, args ...string) string {
+		t.Helper()
+		cmd := newNewCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetIn(strings.NewReader(stdin))
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("new %v error: %v\n%s", args, err, out.String())
+		}
+		return out.String()
+	}
+	reportID := strings.TrimSpace(run("# Report\n\nall green\n", "--title", "Nightly report", "--body", "-"))
+	ids := strings.Fields(run("abc123 fix parser\n\ndef456 add tests\n", "--stdin", "--title-from-line", "--label", "review"))
+	if len(ids) != 2 {
+		t.Fatalf("title-from-line ids = %v, want 2", ids)
+	}
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	report, err := store.GetIssue(ctx, reportID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if report.Title != "Nightly report" || report.Body != "# Report\n\nall green" {
+		t.Fatalf("report = %+v", report)
+	}
+	second, err := store.GetIssue(ctx, ids[1])
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if second.Title != "def456 add tests" || !slices.Equal(second.Labels, []string{"review"}) {
+		t.Fatalf("line issue = %+v", second)
+	}
+
+	cmd := newNewCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--title-from-line"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("--title-from-line without --stdin should fail")
+	}
+}
+
 func TestNewTUICancelDoesNotCreate(t *testing.T) {
 	tmp := t.TempDir()
 	t.Setenv("TRACK_HOME", tmp)
@@ -547,7 +915,7 @@ func TestNewTUIRePromptsOnInvalidPriorityAndDue(t *testing.T) {
 	if !strings.Contains(out.String(), "invalid priority: p9") {
 		t.Fatalf("output should contain invalid priority message, got: %q", out.String())
 	}
-	if !strings.Contains(out.String(), "invalid due date (YYYY-MM-DD): 2026/02/20") {
+	if !strings.Contains(out.String(), "invalid due date (YYYY-MM-DD or YYYY-MM-DDTHH:MM): 2026/02/20") {
 		t.Fatalf("output should contain invalid due message, got: %q", out.String())
 	}
 
@@ -773,6 +1141,65 @@ func TestSetAcceptsNumericIssueID(t *testing.T) {
 	}
 }
 
+func TestBulkSetDoneAndShowReportPerIssue(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	for _, title := range []string{"a", "b", "c"} {
+		if _, err := store.CreateIssue(ctx, issue.Item{Title: title, Status: issue.StatusTodo, Priority: "none", Labels: []string{"old"}}); err != nil {
+			t.Fatalf("CreateIssue() error: %v", err)
+		}
+	}
+
+	run := func(cmd *cobra.Command, args ...string) (string, error) {
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run(newSetCmd(), "TRK-1..TRK-4", "--label", "sprint")
+	if err == nil || err.Error() != "1 of 4 issues failed" {
+		t.Fatalf("set error = %v, want 1 of 4 issues failed", err)
+	}
+	for _, want := range []string{"TRK-1: ok\n", "TRK-3: ok\n", "TRK-4: issue not found"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("set output missing %q:\n%s", want, out)
+		}
+	}
+	for _, id := range []string{"TRK-1", "TRK-2", "TRK-3"} {
+		got, err := store.GetIssue(ctx, id)
+		if err != nil {
+			t.Fatalf("GetIssue() error: %v", err)
+		}
+		if strings.Join(got.Labels, ",") != "old,sprint" {
+			t.Fatalf("%s labels = %v, want old,sprint", id, got.Labels)
+		}
+	}
+
+	if out, err := run(newDoneCmd(), "1", "3"); err != nil || out != "TRK-1: ok\nTRK-3: ok\n" {
+		t.Fatalf("done output = %q, err %v", out, err)
+	}
+	if got, _ := store.GetIssue(ctx, "TRK-3"); got.Status != issue.StatusDone {
+		t.Fatalf("TRK-3 status = %q, want done", got.Status)
+	}
+
+	out, err = run(newShowCmd(), "TRK-1", "TRK-2")
+	if err != nil {
+		t.Fatalf("show error: %v", err)
+	}
+	if !strings.Contains(out, "id: TRK-1\n") || !strings.Contains(out, "\n\nid: TRK-2\n") {
+		t.Fatalf("unexpected show output:\n%s", out)
+	}
+}
+
 func TestSetSupportsProjectAndShowDisplaysProject(t *testing.T) {
 	tmp := t.TempDir()
 	t.Setenv("TRACK_HOME", tmp)
@@ -1113,6 +1540,8 @@ func TestListShowsFullStatusAndPriorityWithAlignedColumns(t *testing.T) {
 	}
 }
 
+const testPlannedBody = "## Spec\nDo the thing.\n\n### Acceptance Criteria\n- [ ] it works\n"
+
 func TestPlanningInteractiveUpdatesAndSkipsWithLimit(t *testing.T) {
 	tmp := t.TempDir()
 	t.Setenv("TRACK_HOME", tmp)
@@ -1143,7 +1572,8 @@ func TestPlanningInteractiveUpdatesAndSkipsWithLimit(t *testing.T) {
 		called = append(called, issueID)
 		if plannedReady[issueID] {
 			status := issue.StatusReady
-			if _, err := store.UpdateIssue(ctx, issueID, sqlite.UpdateIssueInput{Status: &status}); err != nil {
+			body := testPlannedBody
+			if _, err := store.UpdateIssue(ctx, issueID, sqlite.UpdateIssueInput{Status: &status, Body: &body}); err != nil {
 				return err
 			}
 		}
@@ -1209,7 +1639,8 @@ func TestPlanningByIDTargetsOnlySpecifiedIssue(t *testing.T) {
 	planningSessionRunner = func(_ context.Context, _ *cobra.Command, issueID string) error {
 		called = append(called, issueID)
 		status := issue.StatusReady
-		_, err := store.UpdateIssue(ctx, issueID, sqlite.UpdateIssueInput{Status: &status})
+		body := testPlannedBody
+		_, err := store.UpdateIssue(ctx, issueID, sqlite.UpdateIssueInput{Status: &status, Body: &body})
 		return err
 	}
 
@@ -1242,6 +1673,118 @@ func TestPlanningByIDTargetsOnlySpecifiedIssue(t *testing.T) {
 	}
 }
 
+func TestPlanningRevertsReadyWithoutSpecAndAcceptanceCriteria(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	created, err := store.CreateIssue(ctx, issue.Item{Title: "thin plan", Status: issue.StatusTodo, Priority: "none", Assignee: "agent"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	origRunner := planningSessionRunner
+	t.Cleanup(func() { planningSessionRunner = origRunner })
+	planningSessionRunner = func(_ context.Context, _ *cobra.Command, issueID string) error {
+		status := issue.StatusReady
+		body := "## Spec\nDo the thing.\n"
+		_, err := store.UpdateIssue(ctx, issueID, sqlite.UpdateIssueInput{Status: &status, Body: &body})
+		return err
+	}
+
+	cmd := newPlanningCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"--yes"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("planning command error: %v", err)
+	}
+
+	got, err := store.GetIssue(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if got.Status != issue.StatusTodo {
+		t.Fatalf("status = %q, want %q", got.Status, issue.StatusTodo)
+	}
+	if !strings.Contains(out.String(), created.ID+" reverted to todo (missing: acceptance criteria)\n") {
+		t.Fatalf("revert message missing: %q", out.String())
+	}
+	if !strings.Contains(out.String(), "updated: 0, skipped: 1\n") {
+		t.Fatalf("summary mismatch: %q", out.String())
+	}
+}
+
+func TestPlanningAssignsUserAndNotifiesWhenQuestionsAdded(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	notified := filepath.Join(tmp, "notified")
+	script := filepath.Join(tmp, "notify.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nprintf '%s' \"$TRACK_ISSUE_ID\" > "+notified+"\n"), 0o755); err != nil {
+		t.Fatalf("write notify script: %v", err)
+	}
+	cfg := appconfig.Default()
+	cfg.NotifyCmd = script
+	if err := appconfig.Save(cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	created, err := store.CreateIssue(ctx, issue.Item{Title: "unclear", Status: issue.StatusTodo, Priority: "none", Assignee: "agent"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	origRunner := planningSessionRunner
+	t.Cleanup(func() { planningSessionRunner = origRunner })
+	planningSessionRunner = func(_ context.Context, _ *cobra.Command, issueID string) error {
+		body := "## Questions for user\n- which API?\n"
+		_, err := store.UpdateIssue(ctx, issueID, sqlite.UpdateIssueInput{Body: &body})
+		return err
+	}
+
+	cmd := newPlanningCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"--yes"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("planning command error: %v", err)
+	}
+
+	got, err := store.GetIssue(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if got.Assignee != "user" {
+		t.Fatalf("assignee = %q, want user", got.Assignee)
+	}
+	if !strings.Contains(out.String(), created.ID+" assigned to user (questions added)\n") {
+		t.Fatalf("assign message missing: %q", out.String())
+	}
+	data, err := os.ReadFile(notified)
+	if err != nil {
+		t.Fatalf("notify command should run: %v", err)
+	}
+	if string(data) != created.ID {
+		t.Fatalf("notified issue = %q, want %q", string(data), created.ID)
+	}
+}
+
 func TestPlanningDefaultTargetsOnlyTodoAssignedToAgent(t *testing.T) {
 	tmp := t.TempDir()
 	t.Setenv("TRACK_HOME", tmp)
@@ -1656,3 +2199,374 @@ func TestReplyReadsMessageFromStdin(t *testing.T) {
 		t.Fatalf("body missing reply entry: %q", got.Body)
 	}
 }
+
+func TestReplyQuestionFlagRecordsAnswerUnderQuestion(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{
+		Title:    "questioned",
+		Status:   issue.StatusTodo,
+		Priority: "none",
+		Assignee: "user",
+		Body:     "## Questions for user\n- Q1?\n- Q2?\n",
+	})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	cmd := newReplyCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{it.ID, "--question", "2", "-m", "use sqlite"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("reply command error: %v", err)
+	}
+
+	got, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if got.Body != "## Questions for user\n- Q1?\n- Q2?\n  - A: use sqlite\n" {
+		t.Fatalf("unexpected body: %q", got.Body)
+	}
+	if got.Assignee != "agent" {
+		t.Fatalf("assignee = %q, want agent", got.Assignee)
+	}
+}
+
+func TestReplyInteractiveSelectsUnansweredQuestion(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{
+		Title:    "questioned",
+		Status:   issue.StatusTodo,
+		Priority: "none",
+		Assignee: "user",
+		Body:     "## Questions for user\n- Q1?\n  - A: done\n- Q2?\n",
+	})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	cmd := newReplyCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetIn(strings.NewReader("1\n2\nport 8788\n"))
+	cmd.SetArgs([]string{it.ID})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("reply command error: %v", err)
+	}
+	if strings.Contains(out.String(), "1. Q1?") || !strings.Contains(out.String(), "  2. Q2?\n") {
+		t.Fatalf("only unanswered questions should be listed: %q", out.String())
+	}
+	if !strings.Contains(out.String(), "invalid question: 1\n") {
+		t.Fatalf("answered question should be rejected: %q", out.String())
+	}
+
+	got, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if !strings.Contains(got.Body, "- Q2?\n  - A: port 8788\n") {
+		t.Fatalf("answer should follow Q2: %q", got.Body)
+	}
+	if strings.Contains(got.Body, issue.SectionUserReplies) {
+		t.Fatalf("targeted answer should not be added to flat replies: %q", got.Body)
+	}
+}
+
+func TestPlanningDryRunAppliesQueueFiltersAndSort(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	if _, err := store.CreateProject(ctx, "cli", "CLI", ""); err != nil {
+		t.Fatalf("CreateProject() error: %v", err)
+	}
+	low, err := store.CreateIssue(ctx, issue.Item{Title: "low", Status: issue.StatusTodo, Priority: "p1", Assignee: "agent", Labels: []string{"bug"}})
+	if err != nil {
+		t.Fatalf("CreateIssue(low) error: %v", err)
+	}
+	high, err := store.CreateIssue(ctx, issue.Item{Title: "high", Status: issue.StatusTodo, Priority: "p0", Assignee: "agent", Labels: []string{"bug"}})
+	if err != nil {
+		t.Fatalf("CreateIssue(high) error: %v", err)
+	}
+	other, err := store.CreateIssue(ctx, issue.Item{Title: "other", Status: issue.StatusTodo, Priority: "p2", Assignee: "agent", Labels: []string{"bug"}})
+	if err != nil {
+		t.Fatalf("CreateIssue(other) error: %v", err)
+	}
+	unlinked, err := store.CreateIssue(ctx, issue.Item{Title: "unlinked", Status: issue.StatusTodo, Priority: "p0", Assignee: "agent", Labels: []string{"bug"}})
+	if err != nil {
+		t.Fatalf("CreateIssue(unlinked) error: %v", err)
+	}
+	for _, id := range []string{low.ID, high.ID, other.ID} {
+		if err := store.SetIssueProject(ctx, id, "cli"); err != nil {
+			t.Fatalf("SetIssueProject(%s) error: %v", id, err)
+		}
+	}
+
+	cmd := newPlanningCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"--dry-run", "--priority", "p0,p1", "--project", "cli", "--label", "bug", "--sort", "priority"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("planning dry-run error: %v", err)
+	}
+
+	want := high.ID + "\t" + issue.StatusTodo + "\t" + high.Title + "\n" +
+		low.ID + "\t" + issue.StatusTodo + "\t" + low.Title + "\n" +
+		"dry-run: 2 issues\n"
+	if out.String() != want {
+		t.Fatalf("dry-run output mismatch:\n got=%q\nwant=%q (excluded %s, %s)", out.String(), want, other.ID, unlinked.ID)
+	}
+}
+
+func TestPlanningRejectsInvalidPriorityFilter(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	cmd := newPlanningCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--dry-run", "--priority", "p9"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "invalid priority: p9") {
+		t.Fatalf("expected invalid priority error, got: %v", err)
+	}
+}
+
+func TestCheckTogglesCriterionAndDoneRequiresAllChecked(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{
+		Title:    "criteria",
+		Status:   issue.StatusInProgress,
+		Priority: "none",
+		Body:     "### Acceptance Criteria\n- [ ] first\n- [x] second\n",
+	})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	list := newListCmd()
+	var listOut bytes.Buffer
+	list.SetOut(&listOut)
+	list.SetArgs([]string{})
+	if err := list.Execute(); err != nil {
+		t.Fatalf("list error: %v", err)
+	}
+	if !strings.Contains(listOut.String(), "[1/2] criteria") {
+		t.Fatalf("list should show progress: %q", listOut.String())
+	}
+
+	done := newDoneCmd()
+	done.SetOut(&bytes.Buffer{})
+	done.SetErr(&bytes.Buffer{})
+	done.SetArgs([]string{it.ID})
+	err = done.Execute()
+	if err == nil || !strings.Contains(err.Error(), "unchecked acceptance criteria (1/2)") {
+		t.Fatalf("done should be blocked, got %v", err)
+	}
+
+	check := newCheckCmd()
+	var checkOut bytes.Buffer
+	check.SetOut(&checkOut)
+	check.SetArgs([]string{it.ID, "1"})
+	if err := check.Execute(); err != nil {
+		t.Fatalf("check error: %v", err)
+	}
+	if checkOut.String() != "[x] 1. first (2/2)\n" {
+		t.Fatalf("unexpected check output: %q", checkOut.String())
+	}
+
+	show := newShowCmd()
+	var showOut bytes.Buffer
+	show.SetOut(&showOut)
+	show.SetArgs([]string{it.ID})
+	if err := show.Execute(); err != nil {
+		t.Fatalf("show error: %v", err)
+	}
+	if !strings.Contains(showOut.String(), "acceptance: 2/2\n") {
+		t.Fatalf("show should include progress: %q", showOut.String())
+	}
+
+	done = newDoneCmd()
+	done.SetOut(&bytes.Buffer{})
+	done.SetArgs([]string{it.ID})
+	if err := done.Execute(); err != nil {
+		t.Fatalf("done error: %v", err)
+	}
+}
+
+func TestDoneForceIgnoresUncheckedCriteria(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{
+		Title:    "criteria",
+		Status:   issue.StatusInProgress,
+		Priority: "none",
+		Body:     "## Acceptance Criteria\n- [ ] open\n",
+	})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	done := newDoneCmd()
+	done.SetOut(&bytes.Buffer{})
+	done.SetArgs([]string{it.ID, "--force"})
+	if err := done.Execute(); err != nil {
+		t.Fatalf("done --force error: %v", err)
+	}
+	got, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if got.Status != issue.StatusDone {
+		t.Fatalf("status = %q, want done", got.Status)
+	}
+}
+
+func TestEditInteractiveMergesConcurrentChange(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "merge me", Status: issue.StatusTodo, Priority: "none", Body: "one\ntwo\nthree\nfour\nfive"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	origEditor := runEditor
+	t.Cleanup(func() { runEditor = origEditor })
+	runEditor = func(_ *cobra.Command, text string) (string, error) {
+		// Someone else edits the end of the body while the editor is open.
+		theirs := "one\ntwo\nthree\nfour\nFIVE"
+		if _, err := store.UpdateIssue(ctx, it.ID, sqlite.UpdateIssueInput{Body: &theirs}); err != nil {
+			t.Fatalf("UpdateIssue() error: %v", err)
+		}
+		return strings.Replace(text, "one", "ONE", 1) + "\n", nil
+	}
+
+	cmd := newEditCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetIn(strings.NewReader("m\n"))
+	cmd.SetArgs([]string{it.ID, "--interactive"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("edit --interactive error: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "changed while you were editing") {
+		t.Fatalf("edit should report the concurrent change:\n%s", out.String())
+	}
+	got, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if got.Body != "ONE\ntwo\nthree\nfour\nFIVE" {
+		t.Fatalf("body = %q, want both edits merged", got.Body)
+	}
+}
+
+func TestListDefaultsFromConfig(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+	for _, args := range [][]string{
+		{"new", "Low", "--priority", "p3"},
+		{"new", "High", "--priority", "p0"},
+		{"new", "Later"},
+		{"new", "Someday"},
+		{"status", "add", "snoozed"},
+		{"set", "TRK-1", "TRK-2", "--status", "ready"},
+		{"set", "TRK-4", "--status", "snoozed"},
+		{"config", "set", "list.hide_snoozed", "true"},
+	} {
+		if out, err := run(args...); err != nil {
+			t.Fatalf("%v error: %v\n%s", args, err, out)
+		}
+	}
+	ids := func(out string) []string {
+		var got []string
+		for _, line := range strings.Split(strings.TrimSpace(out), "\n")[1:] {
+			got = append(got, strings.Fields(line)[0])
+		}
+		return got
+	}
+
+	out, err := run("list", "--sort", "id")
+	if err != nil || !slices.Equal(ids(out), []string{"TRK-1", "TRK-2", "TRK-3"}) {
+		t.Fatalf("list hiding snoozed = %q, %v", out, err)
+	}
+	if out, err := run("list", "--status", "snoozed"); err != nil || !slices.Equal(ids(out), []string{"TRK-4"}) {
+		t.Fatalf("list --status snoozed = %q, %v", out, err)
+	}
+
+	if _, err := run("config", "set", "list.default_sort", "bogus"); err == nil {
+		t.Fatalf("invalid list.default_sort should be rejected")
+	}
+	for _, kv := range [][2]string{{"list.default_status", "ready"}, {"list.default_sort", "priority"}} {
+		if out, err := run("config", "set", kv[0], kv[1]); err != nil {
+			t.Fatalf("config set %s error: %v\n%s", kv[0], err, out)
+		}
+	}
+	if out, err := run("list"); err != nil || !slices.Equal(ids(out), []string{"TRK-2", "TRK-1"}) {
+		t.Fatalf("list with defaults = %q, %v", out, err)
+	}
+	if out, err := run("list", "--status", "todo"); err != nil || !slices.Equal(ids(out), []string{"TRK-3"}) {
+		t.Fatalf("explicit --status should override = %q, %v", out, err)
+	}
+	if out, err := run("list", "-q", "status:todo", "--sort", "id"); err != nil || !slices.Equal(ids(out), []string{"TRK-3"}) {
+		t.Fatalf("query status should override = %q, %v", out, err)
+	}
+}
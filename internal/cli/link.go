@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func newLinkCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "link",
+		Short: "Manage reference links on issues",
+		Long:  "Attach external URLs, such as design docs or chat threads, to issues. Links are shown by show and the web UI, and carried by JSON and JSONL export and import.",
+	}
+	cmd.AddCommand(newLinkAddCmd())
+	cmd.AddCommand(newLinkListCmd())
+	cmd.AddCommand(newLinkRemoveCmd())
+	return cmd
+}
+
+func newLinkAddCmd() *cobra.Command {
+	var title string
+	cmd := &cobra.Command{
+		Use:   "add <id> <url>",
+		Short: "Link an issue to a URL",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if err := store.AddLink(ctx, normalizeIssueIDArg(args[0]), args[1], title); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&title, "title", "", "Link title")
+	return cmd
+}
+
+func newLinkListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list <id>",
+		Short: "List an issue's links",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			id := normalizeIssueIDArg(args[0])
+			if _, err := store.GetIssue(ctx, id); err != nil {
+				return err
+			}
+			links, err := store.ListLinks(ctx, id)
+			if err != nil {
+				return err
+			}
+			for _, l := range links {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\n", l.URL, l.Title)
+			}
+			return nil
+		},
+	}
+}
+
+func newLinkRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <id> <url>",
+		Short: "Remove a link from an issue",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if err := store.RemoveLink(ctx, normalizeIssueIDArg(args[0]), args[1]); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+}
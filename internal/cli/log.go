@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/myuon/track/internal/timefmt"
+	"github.com/spf13/cobra"
+)
+
+func newLogCmd() *cobra.Command {
+	var (
+		since    string
+		follow   bool
+		interval string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "log",
+		Short: "Show recent activity across all issues",
+		Long:  "Print a chronological feed of issue activity (creations, updates, status changes, replies, and merged PRs) with who made each change.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			times, err := timefmt.Load()
+			if err != nil {
+				return err
+			}
+			from := ""
+			if since != "" {
+				t, err := parseSince(since, time.Now(), times.Loc)
+				if err != nil {
+					return err
+				}
+				from = t.UTC().Format(time.RFC3339)
+			}
+			dur, err := time.ParseDuration(interval)
+			if err != nil {
+				return fmt.Errorf("invalid interval: %w", err)
+			}
+
+			ctx := cmd.Context()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			last, err := printActivity(ctx, cmd.OutOrStdout(), store, times, from, 0)
+			if err != nil || !follow {
+				return err
+			}
+
+			ticker := time.NewTicker(dur)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+					if last, err = printActivity(ctx, cmd.OutOrStdout(), store, times, from, last); err != nil {
+						return err
+					}
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "Only show activity since a duration ago (30m, 12h, 2d) or a date (YYYY-MM-DD or RFC3339)")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep printing new activity as it happens")
+	cmd.Flags().StringVar(&interval, "interval", "1s", "Polling interval for --follow")
+	return cmd
+}
+
+// printActivity prints entries after afterID and returns the last ID printed.
+func printActivity(ctx context.Context, out io.Writer, store *sqlite.Store, times timefmt.Formatter, since string, afterID int) (int, error) {
+	entries, err := store.ListActivity(ctx, since, afterID)
+	if err != nil {
+		return afterID, err
+	}
+	for _, a := range entries {
+		fmt.Fprintf(out, "%s\t%s\t%s\t%s\t%s\n", times.Time(a.CreatedAt), a.IssueID, a.Actor, a.Kind, a.Detail)
+		afterID = a.ID
+	}
+	return afterID, nil
+}
+
+// parseSince accepts a duration before now, with "d" for days, or an
+// absolute date in loc or RFC3339 time.
+func parseSince(v string, now time.Time, loc *time.Location) (time.Time, error) {
+	v = strings.TrimSpace(v)
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", v, loc); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid since: %s", v)
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestLogPrintsActivityFeed(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	t.Setenv(sqlite.ActorEnv, "")
+	cfg := appconfig.Default()
+	cfg.UserName = "jane"
+	if err := appconfig.Save(cfg); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	created, err := store.CreateIssue(ctx, issue.Item{Title: "Add login", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	ready := issue.StatusReady
+	if _, err := store.UpdateIssue(sqlite.WithActor(ctx, "api"), created.ID, sqlite.UpdateIssueInput{Status: &ready}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+
+	reply := newReplyCmd()
+	reply.SetOut(&bytes.Buffer{})
+	reply.SetArgs([]string{created.ID, "-m", "looks good"})
+	if err := reply.Execute(); err != nil {
+		t.Fatalf("reply error: %v", err)
+	}
+
+	cmd := newLogCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"--since", "2d"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("log error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := []string{
+		"TRK-1\tjane\tcreated\tAdd login",
+		"TRK-1\tapi\tstatus\ttodo -> ready",
+		"TRK-1\tjane\tupdated\tbody",
+		"TRK-1\tjane\tsection\tUser replies",
+		"TRK-1\tjane\tupdated\tassignee",
+		"TRK-1\tjane\tcomment\tlooks good",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("log should print %d entries, got:\n%s", len(want), out.String())
+	}
+	for i, w := range want {
+		if !strings.HasSuffix(lines[i], w) {
+			t.Fatalf("line %d = %q, want suffix %q", i, lines[i], w)
+		}
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	cases := map[string]time.Time{
+		"2d":                   time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC),
+		"90m":                  time.Date(2026, 3, 10, 10, 30, 0, 0, time.UTC),
+		"2026-03-01":           time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		"2026-03-01T09:00:00Z": time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+	for in, want := range cases {
+		got, err := parseSince(in, now, time.UTC)
+		if err != nil || !got.Equal(want) {
+			t.Fatalf("parseSince(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := parseSince("yesterday", now, time.UTC); err == nil {
+		t.Fatalf("expected invalid since error")
+	}
+}
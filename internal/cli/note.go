@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+const noteTimeLayout = "2006-01-02 15:04"
+
+func newNoteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "note <id> [text...]",
+		Short: "Append a timestamped note, or print an issue's notes",
+		Long:  "Append a timestamped line to the issue's Notes section, kept apart from the Spec so scratch thoughts don't mix with what agents implement. Without text, print the Notes section.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			issueID := normalizeIssueIDArg(args[0])
+			text := strings.TrimSpace(strings.Join(args[1:], " "))
+			if text == "" {
+				it, err := store.GetIssue(ctx, issueID)
+				if err != nil {
+					return err
+				}
+				if sec, ok := issue.FindSection(it.Body, issue.SectionNotes); ok && sec.Content != "" {
+					fmt.Fprintln(cmd.OutOrStdout(), sec.Content)
+				}
+				return nil
+			}
+
+			loc, err := appconfig.LoadLocation()
+			if err != nil {
+				return err
+			}
+			entry := fmt.Sprintf("- %s %s", time.Now().In(loc).Format(noteTimeLayout), text)
+			if _, err := store.AppendIssueSection(ctx, issueID, issue.SectionNotes, entry); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+}
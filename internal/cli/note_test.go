@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestNoteAppendsToNotesSection(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "noted", Status: issue.StatusTodo, Priority: "none", Body: "## Spec\nkeep me clean"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := newNoteCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("note %v error: %v\n%s", args, err, out.String())
+		}
+		return out.String()
+	}
+	run(it.ID, "try", "the", "cache")
+	run(it.ID, "cache didn't help")
+
+	notes := run(it.ID)
+	if !regexp.MustCompile(`^- \d{4}-\d{2}-\d{2} \d{2}:\d{2} try the cache\n- \d{4}-\d{2}-\d{2} \d{2}:\d{2} cache didn't help\n$`).MatchString(notes) {
+		t.Fatalf("notes = %q", notes)
+	}
+	got, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if spec, _ := issue.FindSection(got.Body, issue.SectionSpec); spec.Content != "keep me clean" {
+		t.Fatalf("spec = %q, want it untouched", spec.Content)
+	}
+}
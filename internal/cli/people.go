@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func newPeopleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "people",
+		Short: "Manage the assignee directory and agent profiles",
+		Long:  "Manage the people issues can be assigned to. Directory names complete --assignee in shells with track completion installed, and with `track config set strict_assignees true` assignees outside the directory are rejected.",
+	}
+	cmd.AddCommand(newPeopleAddCmd())
+	cmd.AddCommand(newPeopleListCmd())
+	cmd.AddCommand(newPeopleRemoveCmd())
+	return cmd
+}
+
+func newPeopleAddCmd() *cobra.Command {
+	var p sqlite.Person
+	var isAgent bool
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Add a person or agent",
+		Long:  "Add a person or, with --agent, an agent profile. run, plan, and dispatch use the profile of the issue's assignee to pick the runner (codex, claude, or a custom command line that gets the prompt as its last argument), pass it the sandbox, and cap how many sessions run at once.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			p.Name = args[0]
+			if isAgent {
+				p.Kind = sqlite.KindAgent
+			}
+			if err := store.AddPerson(ctx, p); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&p.Email, "email", "", "Email address")
+	cmd.Flags().BoolVar(&isAgent, "agent", false, "Add an agent profile instead of a person")
+	cmd.Flags().StringVar(&p.Runner, "runner", "", "Agent runner: codex, claude, or a command line")
+	cmd.Flags().StringVar(&p.Sandbox, "sandbox", "", "Sandbox mode passed to the agent runner")
+	cmd.Flags().IntVar(&p.MaxConcurrency, "max-concurrent", 0, "Most sessions the agent runs at once (0 for no limit)")
+	return cmd
+}
+
+func newPeopleListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List people",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			people, err := store.ListPeople(ctx)
+			if err != nil {
+				return err
+			}
+			for _, p := range people {
+				if p.Kind == sqlite.KindAgent {
+					fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\tagent\trunner=%s\tsandbox=%s\tmax=%d\n", p.Name, p.Email, p.Runner, p.Sandbox, p.MaxConcurrency)
+					continue
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\n", p.Name, p.Email)
+			}
+			return nil
+		},
+	}
+}
+
+func newPeopleRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <name>",
+		Short: "Remove a person",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if err := store.RemovePerson(ctx, args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+}
+
+// completeAssignees completes --assignee from the people directory. An
+// @-prefixed partial name completes too, so "@al" offers "alice".
+func completeAssignees(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	defer store.Close()
+
+	people, err := store.ListPeople(ctx)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	prefix := strings.TrimPrefix(toComplete, "@")
+	var out []string
+	for _, p := range people {
+		if strings.HasPrefix(p.Name, prefix) {
+			out = append(out, p.Name)
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
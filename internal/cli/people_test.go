@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	appconfig "github.com/myuon/track/internal/config"
+)
+
+func TestStrictAssigneesRequirePeopleEntry(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+	if out, err := run("people", "add", "alice", "--email", "alice@example.com"); err != nil {
+		t.Fatalf("people add error: %v\n%s", err, out)
+	}
+	if _, err := run("people", "add", "alice"); err == nil {
+		t.Fatalf("adding a person twice should fail")
+	}
+	if out, err := run("people", "list"); err != nil || out != "alice\talice@example.com\n" {
+		t.Fatalf("people list = %q, %v", out, err)
+	}
+
+	// Free-text assignees are allowed until strict mode is on.
+	if out, err := run("new", "loose", "--assignee", "bob"); err != nil {
+		t.Fatalf("new without strict mode error: %v\n%s", err, out)
+	}
+	cfg := appconfig.Default()
+	cfg.StrictAssignees = true
+	if err := appconfig.Save(cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	if _, err := run("new", "strict", "--assignee", "carol"); err == nil || !strings.Contains(err.Error(), "unknown assignee: carol") {
+		t.Fatalf("new with unknown assignee should fail, got %v", err)
+	}
+	if _, err := run("set", "TRK-1", "--assignee", "carol"); err == nil {
+		t.Fatalf("set with unknown assignee should fail")
+	}
+	if out, err := run("set", "TRK-1", "--assignee", "alice"); err != nil {
+		t.Fatalf("set with known assignee error: %v\n%s", err, out)
+	}
+
+	got, _ := completeAssignees(newSetCmd(), nil, "@al")
+	if !slices.Equal(got, []string{"alice"}) {
+		t.Fatalf("completeAssignees(@al) = %v", got)
+	}
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/notify"
+	"github.com/myuon/track/internal/service"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func newPomoCmd() *cobra.Command {
+	var work, rest time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "pomo <id>",
+		Short: "Run a pomodoro timer on an issue",
+		Long:  "Move the issue to in_progress, run a work timer, and log the interval as time spent on the issue (shown by track show). A notification is sent through notify_cmd when the work and break timers end. Interrupting the timer logs the time worked so far.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if work <= 0 || rest < 0 {
+				return fmt.Errorf("--work must be positive and --break must not be negative")
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			it, err := store.GetIssue(ctx, normalizeIssueIDArg(args[0]))
+			if err != nil {
+				return err
+			}
+			if it.Status != issue.StatusInProgress {
+				if it, err = service.SetStatus(ctx, store, it.ID, issue.StatusInProgress); err != nil {
+					return err
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			start := time.Now()
+			fmt.Fprintf(out, "%s: working for %s: %s\n", it.ID, work, it.Title)
+			finished := sleepContext(ctx, work)
+			// Log with a fresh context so an interrupted timer still records
+			// the time worked.
+			entry, err := store.LogTime(context.WithoutCancel(ctx), it.ID, start, time.Now())
+			if err != nil {
+				return err
+			}
+			spent := issue.FormatEstimate(entry.Duration().Round(time.Minute))
+			if !finished {
+				fmt.Fprintf(out, "%s: interrupted; logged %s\n", it.ID, spent)
+				return nil
+			}
+			fmt.Fprintf(out, "%s: logged %s\n", it.ID, spent)
+			if err := notify.Send(ctx, notify.Message{IssueID: it.ID, Title: "pomodoro done", Body: fmt.Sprintf("%s on %s: %s. Break for %s.", work, it.ID, it.Title, rest)}); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "notify: %v\n", err)
+			}
+
+			if rest == 0 {
+				return nil
+			}
+			fmt.Fprintf(out, "break for %s\n", rest)
+			if !sleepContext(ctx, rest) {
+				return nil
+			}
+			fmt.Fprintln(out, "break over")
+			if err := notify.Send(ctx, notify.Message{IssueID: it.ID, Title: "break over", Body: fmt.Sprintf("Back to %s: %s", it.ID, it.Title)}); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "notify: %v\n", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&work, "work", 25*time.Minute, "Length of the work interval")
+	cmd.Flags().DurationVar(&rest, "break", 5*time.Minute, "Length of the break after it (0 skips it)")
+	return cmd
+}
+
+// sleepContext waits for d and reports whether it finished before ctx was
+// cancelled.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
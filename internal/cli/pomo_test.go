@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestPomoStartsIssueLogsTimeAndNotifies(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	notified := filepath.Join(tmp, "notified")
+	script := filepath.Join(tmp, "notify.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nprintf '%s\\n' \"$TRACK_NOTIFY_TITLE\" >> "+notified+"\n"), 0o755); err != nil {
+		t.Fatalf("write notify script: %v", err)
+	}
+	cfg := appconfig.Default()
+	cfg.NotifyCmd = script
+	if err := appconfig.Save(cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	created, err := store.CreateIssue(ctx, issue.Item{Title: "focus", Status: issue.StatusReady, Priority: "p1"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	cmd := newPomoCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{created.ID, "--work", "20ms", "--break", "10ms"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("pomo error: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "break over") {
+		t.Fatalf("pomo should run the break, got:\n%s", out.String())
+	}
+
+	got, err := store.GetIssue(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if got.Status != issue.StatusInProgress {
+		t.Fatalf("status = %q, want in_progress", got.Status)
+	}
+	entries, err := store.ListTimeEntries(ctx, created.ID)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("ListTimeEntries() = %+v, %v; want one entry", entries, err)
+	}
+	titles, err := os.ReadFile(notified)
+	if err != nil {
+		t.Fatalf("read notifications: %v", err)
+	}
+	if string(titles) != "pomodoro done\nbreak over\n" {
+		t.Fatalf("notifications = %q", titles)
+	}
+}
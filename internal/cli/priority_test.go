@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestListAndShowInheritedPriority(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := newRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%v: %v\n%s", args, err, out.String())
+		}
+		return out.String()
+	}
+
+	epic := strings.TrimSpace(run("new", "epic", "--priority", "p0"))
+	task := strings.TrimSpace(run("new", "task", "--priority", "p2"))
+	run("depends-on", epic, task)
+
+	list := run("list")
+	var row string
+	for _, line := range strings.Split(list, "\n") {
+		if strings.HasPrefix(line, task+" ") {
+			row = line
+		}
+	}
+	if !strings.Contains(row, "p2 (eff p0)") {
+		t.Fatalf("list row for %s = %q\n%s", task, row, list)
+	}
+	if show := run("show", task); !strings.Contains(show, "priority: p2 (effective p0, inherited from "+epic+")") {
+		t.Fatalf("show output:\n%s", show)
+	}
+	if show := run("show", epic); !strings.Contains(show, "priority: p0\n") {
+		t.Fatalf("show output for the epic:\n%s", show)
+	}
+
+	run("config", "set", "priority_inheritance", "off")
+	if show := run("show", task); !strings.Contains(show, "priority: p2\n") {
+		t.Fatalf("show output with inheritance off:\n%s", show)
+	}
+}
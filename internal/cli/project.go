@@ -3,8 +3,14 @@ package cli
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 
+	appconfig "github.com/myuon/track/internal/config"
 	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/myuon/track/internal/timefmt"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +22,9 @@ func newProjectCmd() *cobra.Command {
 	cmd.AddCommand(newProjectAddCmd())
 	cmd.AddCommand(newProjectListCmd())
 	cmd.AddCommand(newProjectShowCmd())
+	cmd.AddCommand(newProjectSetCmd())
+	cmd.AddCommand(newProjectDependsOnCmd())
+	cmd.AddCommand(newProjectReorderCmd())
 	cmd.AddCommand(newProjectRemoveCmd())
 	return cmd
 }
@@ -100,11 +109,141 @@ func newProjectShowCmd() *cobra.Command {
 			if p.Description != "" {
 				fmt.Fprintf(cmd.OutOrStdout(), "description: %s\n", p.Description)
 			}
+			if len(p.Defaults.Labels) > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "default_labels: %s\n", strings.Join(p.Defaults.Labels, ","))
+			}
+			if p.Defaults.Priority != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "default_priority: %s\n", p.Defaults.Priority)
+			}
+			if p.Defaults.Assignee != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "default_assignee: %s\n", p.Defaults.Assignee)
+			}
+			if p.BranchPrefix != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "branch_prefix: %s\n", p.BranchPrefix)
+			}
 			fmt.Fprintf(cmd.OutOrStdout(), "issue_count: %d\n", p.IssueCount)
-			fmt.Fprintf(cmd.OutOrStdout(), "updated_at: %s\n", p.UpdatedAt)
+			times, err := timefmt.Load()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "updated_at: %s\n", times.Time(p.UpdatedAt))
+			return nil
+		},
+	}
+}
+
+func newProjectSetCmd() *cobra.Command {
+	var (
+		labels       []string
+		priority     string
+		assignee     string
+		branchPrefix string
+	)
+	cmd := &cobra.Command{
+		Use:   "set <key>",
+		Short: "Set a project's defaults",
+		Long:  "Set the labels, priority, and assignee filled in on issues created with new --project or linked with set --project. Defaults only fill gaps: an issue's own priority (other than none) and assignee are kept, and default labels are added to its labels. --branch-prefix names the branches dispatch creates for the project's issues. Pass an empty value to clear a default.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("default-label") && !cmd.Flags().Changed("default-priority") && !cmd.Flags().Changed("default-assignee") && !cmd.Flags().Changed("branch-prefix") {
+				return fmt.Errorf("no defaults to set")
+			}
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			p, err := store.GetProject(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			d := p.Defaults
+			if cmd.Flags().Changed("default-label") {
+				d.Labels = labels
+			}
+			if cmd.Flags().Changed("default-priority") {
+				d.Priority = priority
+			}
+			if cmd.Flags().Changed("default-assignee") {
+				d.Assignee = assignee
+			}
+			if _, err := store.SetProjectDefaults(ctx, p.Key, d); err != nil {
+				return err
+			}
+			if cmd.Flags().Changed("branch-prefix") {
+				if _, err := store.SetProjectBranchPrefix(ctx, p.Key, branchPrefix); err != nil {
+					return err
+				}
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
 			return nil
 		},
 	}
+	cmd.Flags().StringArrayVar(&labels, "default-label", nil, "Default label (repeatable; replaces the current set)")
+	cmd.Flags().StringVar(&priority, "default-priority", "", "Default priority (p0|p1|p2|p3|none)")
+	cmd.Flags().StringVar(&assignee, "default-assignee", "", "Default assignee")
+	cmd.Flags().StringVar(&branchPrefix, "branch-prefix", "", `Prefix of dispatch branches for the project's issues (e.g. "auth/")`)
+	return cmd
+}
+
+func newProjectDependsOnCmd() *cobra.Command {
+	var remove bool
+	cmd := &cobra.Command{
+		Use:   "depends-on <key> <dependency>",
+		Short: "Record that a project depends on another",
+		Long:  "Record that a project can only start once another is finished. Roadmap lists it after its dependencies; dependencies that would form a cycle are rejected.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if remove {
+				err = store.RemoveProjectDependency(ctx, args[0], args[1])
+			} else {
+				err = store.AddProjectDependency(ctx, args[0], args[1])
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&remove, "rm", false, "Remove the dependency instead")
+	return cmd
+}
+
+func newProjectReorderCmd() *cobra.Command {
+	var beforeKey string
+	var afterKey string
+	cmd := &cobra.Command{
+		Use:   "reorder <key>",
+		Short: "Move a project in the roadmap order",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if err := store.ReorderProject(ctx, args[0], beforeKey, afterKey); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&beforeKey, "before", "", "Move before this project")
+	cmd.Flags().StringVar(&afterKey, "after", "", "Move after this project")
+	return cmd
 }
 
 func newProjectRemoveCmd() *cobra.Command {
@@ -131,3 +270,43 @@ func newProjectRemoveCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&force, "force", false, "Delete linked issue relations too")
 	return cmd
 }
+
+// projectForDir returns the project that the project_paths config rules map
+// dir to, matched relative to the root of dir's git repository. Outside a
+// repository, or with no matching rule, it is empty.
+func projectForDir(ctx context.Context, dir string) (string, error) {
+	cfg, err := appconfig.Load()
+	if err != nil {
+		return "", err
+	}
+	if len(cfg.ProjectPaths) == 0 {
+		return "", nil
+	}
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--show-toplevel")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", nil
+	}
+	root, err := filepath.EvalSymlinks(strings.TrimSpace(string(out)))
+	if err != nil {
+		return "", nil
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", nil
+	}
+	return cfg.ProjectForPath(filepath.ToSlash(rel)), nil
+}
+
+// projectForCwd is projectForDir for the working directory.
+func projectForCwd(ctx context.Context) (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return projectForDir(ctx, wd)
+}
@@ -3,11 +3,15 @@ package cli
 import (
 	"bytes"
 	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/myuon/track/internal/issue"
 	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
 )
 
 func TestProjectAddListShowRemove(t *testing.T) {
@@ -97,3 +101,156 @@ func TestProjectRemoveRequiresForceWhenLinked(t *testing.T) {
 		t.Fatalf("project rm --force error: %v", err)
 	}
 }
+
+func TestProjectDefaultsApplyOnCreateAndLink(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	run := func(cmd *cobra.Command, args ...string) string {
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%v error: %v\n%s", args, err, out.String())
+		}
+		return out.String()
+	}
+	run(newProjectCmd(), "add", "cli-refresh", "--name", "CLI Refresh")
+	run(newProjectCmd(), "set", "cli-refresh", "--default-label", "cli", "--default-assignee", "agent", "--default-priority", "p2")
+	if out := run(newProjectCmd(), "show", "cli-refresh"); !strings.Contains(out, "default_labels: cli\n") || !strings.Contains(out, "default_assignee: agent\n") {
+		t.Fatalf("project show should list defaults:\n%s", out)
+	}
+
+	createdID := strings.TrimSpace(run(newNewCmd(), "created", "--project", "cli-refresh", "--label", "ui"))
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	created, err := store.GetIssue(ctx, createdID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if created.Priority != "p2" || created.Assignee != "agent" || strings.Join(created.Labels, ",") != "ui,cli" {
+		t.Fatalf("created issue should get project defaults: %+v", created)
+	}
+	if project, err := store.GetIssueProject(ctx, createdID); err != nil || project != "cli-refresh" {
+		t.Fatalf("GetIssueProject() = %q, %v", project, err)
+	}
+
+	// Linking keeps the issue's own priority and assignee.
+	linked, err := store.CreateIssue(ctx, issue.Item{Title: "linked", Status: issue.StatusTodo, Priority: "p0", Assignee: "user"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	run(newSetCmd(), linked.ID, "--project", "cli-refresh")
+	got, err := store.GetIssue(ctx, linked.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if got.Priority != "p0" || got.Assignee != "user" || strings.Join(got.Labels, ",") != "cli" {
+		t.Fatalf("linked issue should only fill gaps: %+v", got)
+	}
+}
+
+func TestRoadmapOrdersProjectsByDependencies(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	for _, key := range []string{"web", "api", "docs"} {
+		if _, err := store.CreateProject(ctx, key, strings.ToUpper(key), ""); err != nil {
+			t.Fatalf("CreateProject(%s) error: %v", key, err)
+		}
+	}
+	for _, st := range []string{issue.StatusDone, issue.StatusTodo, issue.StatusArchived} {
+		it, err := store.CreateIssue(ctx, issue.Item{Title: "api work", Status: st, Priority: "p2"})
+		if err != nil {
+			t.Fatalf("CreateIssue() error: %v", err)
+		}
+		if err := store.SetIssueProject(ctx, it.ID, "api"); err != nil {
+			t.Fatalf("SetIssueProject() error: %v", err)
+		}
+	}
+	if err := store.AddProjectDependency(ctx, "web", "api"); err != nil {
+		t.Fatalf("AddProjectDependency() error: %v", err)
+	}
+	if err := store.ReorderProject(ctx, "docs", "web", ""); err != nil {
+		t.Fatalf("ReorderProject() error: %v", err)
+	}
+
+	cmd := newRoadmapCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("roadmap error: %v", err)
+	}
+	want := "1\tdocs\tDOCS\t0% (0/0)\n2\tapi\tAPI\t50% (1/2)\n3\tweb\tWEB\t0% (0/0)\tafter api\n"
+	if out.String() != want {
+		t.Fatalf("roadmap output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestProjectPathsRouteNewAndDispatchBranches(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", filepath.Join(tmp, "home"))
+	repo := filepath.Join(tmp, "repo")
+	authDir := filepath.Join(repo, "services", "auth", "internal")
+	if err := os.MkdirAll(authDir, 0o755); err != nil {
+		t.Fatalf("MkdirAll() error: %v", err)
+	}
+	if out, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Skipf("git init failed: %v %s", err, out)
+	}
+
+	run := func(cmd *cobra.Command, args ...string) string {
+		t.Helper()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%v error: %v\n%s", args, err, out.String())
+		}
+		return out.String()
+	}
+	run(newProjectCmd(), "add", "auth", "--name", "Auth")
+	run(newProjectCmd(), "set", "auth", "--branch-prefix", "auth/")
+	run(newConfigCmd(), "set", "project_paths.services/auth", "auth")
+
+	t.Chdir(authDir)
+	createdID := strings.TrimSpace(run(newNewCmd(), "Rotate keys"))
+	t.Chdir(repo)
+	otherID := strings.TrimSpace(run(newNewCmd(), "Top level"))
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	if project, err := store.GetIssueProject(ctx, createdID); err != nil || project != "auth" {
+		t.Fatalf("issue created under services/auth: project = %q, %v", project, err)
+	}
+	if project, err := store.GetIssueProject(ctx, otherID); err != nil || project != "" {
+		t.Fatalf("issue created at the root: project = %q, %v", project, err)
+	}
+
+	if prefix, err := dispatchBranchPrefix(ctx, store, createdID, repo); err != nil || prefix != "auth/" {
+		t.Fatalf("dispatchBranchPrefix() = %q, %v; want auth/", prefix, err)
+	}
+	if prefix, err := dispatchBranchPrefix(ctx, store, otherID, repo); err != nil || prefix != defaultDispatchBranchPrefix {
+		t.Fatalf("dispatchBranchPrefix() = %q, %v; want the default", prefix, err)
+	}
+}
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+const (
+	rankStartRating = 1500
+	rankK           = 32
+)
+
+// rankPair picks the two issues compared in a round; tests replace it.
+var rankPair = func(n int) (int, int) {
+	i := rand.IntN(n)
+	j := rand.IntN(n - 1)
+	if j >= i {
+		j++
+	}
+	return i, j
+}
+
+func newRankCmd() *cobra.Command {
+	var (
+		rounds int
+		status string
+		apply  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "rank",
+		Short: "Stack-rank the backlog by comparing pairs of issues",
+		Long:  "Show random pairs of backlog issues and ask which comes first. Each answer updates an Elo-style rating, and the resulting ranking can be applied to the manual order, where the ranked issues take the positions they held between them. Answer 1 or 2, s to skip a pair, or q to stop early.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			statuses, err := parseStatusFilter(status, func(v string) error {
+				return store.ValidateStatus(ctx, v)
+			})
+			if err != nil {
+				return err
+			}
+			items, err := store.ListIssues(ctx, sqlite.ListFilter{Statuses: statuses, Sort: "manual"})
+			if err != nil {
+				return err
+			}
+			if len(items) < 2 {
+				return fmt.Errorf("need at least two issues to rank")
+			}
+			if rounds <= 0 {
+				rounds = 2 * len(items)
+			}
+
+			out := cmd.OutOrStdout()
+			reader := bufio.NewReader(cmd.InOrStdin())
+			ratings, err := runRankRounds(reader, out, items, rounds)
+			if err != nil {
+				return err
+			}
+			ranked := rankByRating(items, ratings)
+			for i, it := range ranked {
+				fmt.Fprintf(out, "%d\t%s\t%.0f\t%s\n", i+1, it.ID, ratings[it.ID], it.Title)
+			}
+
+			if !apply {
+				line, err := readPromptLine(reader, out, "apply to manual order? [y/N]: ")
+				if err != nil {
+					return err
+				}
+				if !strings.EqualFold(strings.TrimSpace(line), "y") {
+					return nil
+				}
+			}
+			ids := make([]string, 0, len(ranked))
+			for _, it := range ranked {
+				ids = append(ids, it.ID)
+			}
+			batchCtx, flush := sqlite.BatchEvents(ctx)
+			if err := store.RankIssues(batchCtx, ids); err != nil {
+				return err
+			}
+			if err := flush(); err != nil {
+				return err
+			}
+			fmt.Fprintln(out, "ok")
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&rounds, "rounds", 0, "Number of comparisons (default twice the number of issues)")
+	cmd.Flags().StringVar(&status, "status", "todo,ready", "Statuses to rank")
+	cmd.Flags().BoolVar(&apply, "apply", false, "Apply the ranking to the manual order without asking")
+	return cmd
+}
+
+// runRankRounds asks up to rounds comparisons and returns each issue's
+// rating.
+func runRankRounds(reader *bufio.Reader, out io.Writer, items []issue.Item, rounds int) (map[string]float64, error) {
+	ratings := make(map[string]float64, len(items))
+	for _, it := range items {
+		ratings[it.ID] = rankStartRating
+	}
+	for round := 1; round <= rounds; round++ {
+		i, j := rankPair(len(items))
+		a, b := items[i], items[j]
+		fmt.Fprintf(out, "\n[%d/%d] which first?\n  1) %s %s\n  2) %s %s\n", round, rounds, a.ID, a.Title, b.ID, b.Title)
+		line, err := readPromptLine(reader, out, "> ")
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "1":
+			updateElo(ratings, a.ID, b.ID)
+		case "2":
+			updateElo(ratings, b.ID, a.ID)
+		case "s":
+		case "q":
+			return ratings, nil
+		default:
+			fmt.Fprintln(out, "answer 1, 2, s, or q")
+			round--
+		}
+	}
+	return ratings, nil
+}
+
+// updateElo moves rating from loser to winner by how unexpected the win was.
+func updateElo(ratings map[string]float64, winner, loser string) {
+	expected := 1 / (1 + math.Pow(10, (ratings[loser]-ratings[winner])/400))
+	delta := rankK * (1 - expected)
+	ratings[winner] += delta
+	ratings[loser] -= delta
+}
+
+// rankByRating orders items by rating, highest first, keeping the current
+// order for ties.
+func rankByRating(items []issue.Item, ratings map[string]float64) []issue.Item {
+	ranked := slices.Clone(items)
+	slices.SortStableFunc(ranked, func(a, b issue.Item) int {
+		switch {
+		case ratings[a.ID] > ratings[b.ID]:
+			return -1
+		case ratings[a.ID] < ratings[b.ID]:
+			return 1
+		}
+		return 0
+	})
+	return ranked
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestRankAppliesPairwiseOrder(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	var ids []string
+	for _, title := range []string{"A", "B", "C"} {
+		created, err := store.CreateIssue(ctx, issue.Item{Title: title, Status: issue.StatusTodo, Priority: "p2"})
+		if err != nil {
+			t.Fatalf("CreateIssue(%s) error: %v", title, err)
+		}
+		ids = append(ids, created.ID)
+	}
+
+	pairs := [][2]int{{0, 2}, {1, 2}, {0, 1}}
+	round := 0
+	orig := rankPair
+	rankPair = func(int) (int, int) {
+		p := pairs[round%len(pairs)]
+		round++
+		return p[0], p[1]
+	}
+	t.Cleanup(func() { rankPair = orig })
+
+	// C beats A, C beats B, B beats A; an invalid answer is asked again.
+	cmd := newRankCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetIn(strings.NewReader("2\nx\n2\n2\ny\n"))
+	cmd.SetArgs([]string{"--rounds", "3"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("rank error: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "answer 1, 2, s, or q") {
+		t.Fatalf("rank should reject invalid answers, got:\n%s", out.String())
+	}
+
+	items, err := store.ListIssues(ctx, sqlite.ListFilter{Sort: "manual"})
+	if err != nil {
+		t.Fatalf("ListIssues() error: %v", err)
+	}
+	var got []string
+	for _, it := range items {
+		got = append(got, it.ID)
+	}
+	want := []string{ids[2], ids[1], ids[0]}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("manual order = %v, want %v\n%s", got, want, out.String())
+	}
+}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/myuon/track/internal/cron"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func newRecurCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recur",
+		Short: "Manage recurring issues created by the scheduler",
+	}
+	cmd.AddCommand(newRecurAddCmd())
+	cmd.AddCommand(newRecurListCmd())
+	cmd.AddCommand(newRecurRemoveCmd())
+	return cmd
+}
+
+func newRecurAddCmd() *cobra.Command {
+	var (
+		every    string
+		start    string
+		body     string
+		priority string
+		labels   []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add <title>",
+		Short: "Add a recurring issue",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := cron.ParseEvery(every); err != nil {
+				return err
+			}
+			next := time.Now().UTC()
+			if strings.TrimSpace(start) != "" {
+				t, err := parseRecurStart(start)
+				if err != nil {
+					return err
+				}
+				next = t
+			}
+
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			r, err := store.AddRecurringIssue(ctx, sqlite.RecurringIssue{
+				Title:     args[0],
+				Body:      body,
+				Priority:  priority,
+				Labels:    labels,
+				Every:     every,
+				NextRunAt: next.Format(time.RFC3339),
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), r.ID)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&every, "every", "", "Interval (hourly|daily|weekly|duration such as 36h)")
+	cmd.Flags().StringVar(&start, "start", "", "First run (YYYY-MM-DD or RFC3339, default now)")
+	cmd.Flags().StringVar(&body, "body", "", "Issue body")
+	cmd.Flags().StringVar(&priority, "priority", "none", "Priority (none|p0|p1|p2|p3)")
+	cmd.Flags().StringArrayVar(&labels, "label", nil, "Issue label (repeatable)")
+	_ = cmd.MarkFlagRequired("every")
+	return cmd
+}
+
+func parseRecurStart(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.UTC(), nil
+	}
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid start: %s", v)
+}
+
+func newRecurListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List recurring issues",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			recs, err := store.ListRecurringIssues(ctx)
+			if err != nil {
+				return err
+			}
+			for _, r := range recs {
+				fmt.Fprintf(cmd.OutOrStdout(), "%d\tevery=%s\tnext=%s\t%s\n", r.ID, r.Every, r.NextRunAt, r.Title)
+			}
+			return nil
+		},
+	}
+}
+
+func newRecurRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <recur_id>",
+		Short: "Remove recurring issue",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid recur_id: %s", args[0])
+			}
+
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if err := store.RemoveRecurringIssue(ctx, id); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+}
@@ -0,0 +1,277 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+// releaseLabelPrefix starts the label `track release create` puts on the
+// issues of a release: release:v1.3.0, or release:cli/v1.3.0 for a project.
+const releaseLabelPrefix = "release:"
+
+func newReleaseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "release",
+		Short: "Cut releases from finished issues",
+	}
+	cmd.AddCommand(newReleaseCreateCmd())
+	cmd.AddCommand(newReleaseListCmd())
+	cmd.AddCommand(newReleaseShowCmd())
+	return cmd
+}
+
+func newReleaseCreateCmd() *cobra.Command {
+	var (
+		project string
+		withGH  bool
+		repo    string
+		draft   bool
+		dryRun  bool
+	)
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Release the issues done since the last release",
+		Long: `Snapshot the done issues (of --project, if given) that are in no release yet,
+write release notes grouping them into features, fixes, and other changes,
+and label each issue with the release so ` + "`track list --label release:<name>`" + `
+finds them later (release:<project>/<name> with --project). With --gh the
+notes also become a GitHub release, created with gh in the detected repo:
+
+  track release create v1.3.0 --project cli --gh`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			name := strings.TrimSpace(args[0])
+			if project != "" {
+				if _, err := store.GetProject(ctx, project); err != nil {
+					return err
+				}
+			}
+			if _, err := store.GetRelease(ctx, project, name); err == nil {
+				return fmt.Errorf("release already exists: %s", name)
+			}
+			items, err := unreleasedIssues(ctx, store, project)
+			if err != nil {
+				return err
+			}
+			if len(items) == 0 {
+				return fmt.Errorf("no done issues since the last release")
+			}
+			notes := releaseNotes(items)
+			rel := sqlite.Release{Name: name, Project: project, Label: releaseLabel(project, name), Notes: notes}
+
+			out := cmd.OutOrStdout()
+			if dryRun {
+				fmt.Fprintf(out, "release: %s (%d issues)\n", name, len(items))
+				fmt.Fprintf(out, "label: %s\n", rel.Label)
+				fmt.Fprint(out, notes)
+				return nil
+			}
+
+			if withGH {
+				url, err := createGitHubRelease(ctx, repo, name, draft, notes)
+				if err != nil {
+					return err
+				}
+				rel.URL = url
+			}
+			if _, err := store.CreateRelease(ctx, rel); err != nil {
+				return err
+			}
+			batchCtx, flush := sqlite.BatchEvents(ctx)
+			for _, it := range items {
+				if _, err := store.AddLabel(batchCtx, it.ID, rel.Label); err != nil {
+					return err
+				}
+			}
+			if err := flush(); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "released %s: %d issues labeled %s\n", name, len(items), rel.Label)
+			if rel.URL != "" {
+				fmt.Fprintln(out, rel.URL)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&project, "project", "", "Release only this project's issues")
+	cmd.Flags().BoolVar(&withGH, "gh", false, "Also create a GitHub release with the notes")
+	cmd.Flags().StringVar(&repo, "repo", "", "GitHub repo for --gh (default: gh_repo, then the origin remote)")
+	cmd.Flags().BoolVar(&draft, "draft", false, "Create the GitHub release as a draft")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the notes without recording the release")
+	return cmd
+}
+
+func newReleaseListCmd() *cobra.Command {
+	var project string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List releases, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			releases, err := store.ListReleases(ctx, project)
+			if err != nil {
+				return err
+			}
+			for _, r := range releases {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\t%s\n", r.Name, r.Project, r.CreatedAt, r.URL)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&project, "project", "", "Only list this project's releases")
+	return cmd
+}
+
+func newReleaseShowCmd() *cobra.Command {
+	var project string
+	cmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a release's notes and issues",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			rel, err := store.GetRelease(ctx, project, args[0])
+			if err != nil {
+				return err
+			}
+			items, err := store.ListIssues(ctx, sqlite.ListFilter{Label: rel.Label, Sort: "id"})
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "name: %s\n", rel.Name)
+			if rel.Project != "" {
+				fmt.Fprintf(out, "project: %s\n", rel.Project)
+			}
+			fmt.Fprintf(out, "label: %s\n", rel.Label)
+			fmt.Fprintf(out, "created_at: %s\n", rel.CreatedAt)
+			if rel.URL != "" {
+				fmt.Fprintf(out, "url: %s\n", rel.URL)
+			}
+			fmt.Fprintf(out, "issues: %d\n", len(items))
+			fmt.Fprintf(out, "\n%s", rel.Notes)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&project, "project", "", "Project the release belongs to")
+	return cmd
+}
+
+func releaseLabel(project, name string) string {
+	if project != "" {
+		return releaseLabelPrefix + project + "/" + name
+	}
+	return releaseLabelPrefix + name
+}
+
+// unreleasedIssues returns the done issues, of project if given, that carry
+// no release label yet, oldest first.
+func unreleasedIssues(ctx context.Context, store *sqlite.Store, project string) ([]issue.Item, error) {
+	done, err := store.ListIssues(ctx, sqlite.ListFilter{Statuses: []string{issue.StatusDone}, Project: project, Sort: "id"})
+	if err != nil {
+		return nil, err
+	}
+	var out []issue.Item
+	for _, it := range done {
+		released := slices.ContainsFunc(it.Labels, func(l string) bool { return strings.HasPrefix(l, releaseLabelPrefix) })
+		if !released {
+			out = append(out, it)
+		}
+	}
+	return out, nil
+}
+
+// releaseNotes lists items as markdown, grouped by their labels: bug and
+// fix labels count as fixes, feature and enhancement labels as features.
+func releaseNotes(items []issue.Item) string {
+	groups := []struct {
+		heading string
+		labels  []string
+		items   []issue.Item
+	}{
+		{heading: "Features", labels: []string{"feature", "enhancement"}},
+		{heading: "Fixes", labels: []string{"bug", "fix"}},
+		{heading: "Other changes"},
+	}
+	for _, it := range items {
+		i := len(groups) - 1
+		for g := range groups[:i] {
+			if slices.ContainsFunc(it.Labels, func(l string) bool { return slices.Contains(groups[g].labels, strings.ToLower(l)) }) {
+				i = g
+				break
+			}
+		}
+		groups[i].items = append(groups[i].items, it)
+	}
+
+	var b strings.Builder
+	for _, g := range groups {
+		if len(g.items) == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "## %s\n\n", g.heading)
+		for _, it := range g.items {
+			fmt.Fprintf(&b, "- %s (%s)\n", it.Title, it.ID)
+		}
+	}
+	return b.String()
+}
+
+// createGitHubRelease tags name on GitHub with notes and returns the
+// release URL gh prints.
+func createGitHubRelease(ctx context.Context, repoOverride, name string, draft bool, notes string) (string, error) {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return "", fmt.Errorf("gh command is required")
+	}
+	repo, err := resolveGitHubRepo(repoOverride)
+	if err != nil {
+		return "", err
+	}
+	args := []string{"release", "create", name, "--title", name, "--notes", notes}
+	if repo != "" {
+		args = append(args, "--repo", repo)
+	}
+	if draft {
+		args = append(args, "--draft")
+	}
+	raw, err := ghCombinedOutput(ctx, args...)
+	if err != nil {
+		msg := strings.TrimSpace(string(raw))
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("gh release create failed: %s", msg)
+	}
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestReleaseCreateLabelsDoneIssuesOnce(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+	ghArgsFile := filepath.Join(tmp, "gh-args.txt")
+	setupFakeGHForTest(t, tmp, ghArgsFile)
+	t.Setenv("GH_STDOUT", "https://github.com/owner/repo/releases/tag/v1.3.0")
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	if _, err := store.CreateProject(ctx, "cli", "CLI", ""); err != nil {
+		t.Fatalf("CreateProject() error: %v", err)
+	}
+	create := func(title, status, project string, labels ...string) issue.Item {
+		t.Helper()
+		it, err := store.CreateIssue(ctx, issue.Item{Title: title, Status: status, Priority: "none", Labels: labels})
+		if err != nil {
+			t.Fatalf("CreateIssue() error: %v", err)
+		}
+		if project != "" {
+			if err := store.SetIssueProject(ctx, it.ID, project); err != nil {
+				t.Fatalf("SetIssueProject() error: %v", err)
+			}
+		}
+		return it
+	}
+	feature := create("Add export", issue.StatusDone, "cli", "feature")
+	fix := create("Fix crash", issue.StatusDone, "cli", "bug")
+	chore := create("Bump deps", issue.StatusDone, "cli")
+	create("Still open", issue.StatusTodo, "cli")
+	create("Other project", issue.StatusDone, "")
+
+	run := func(args ...string) (string, error) {
+		cmd := newReleaseCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	got, err := run("create", "v1.3.0", "--project", "cli", "--gh", "--repo", "owner/repo")
+	if err != nil {
+		t.Fatalf("release create error: %v\n%s", err, got)
+	}
+	if !strings.Contains(got, "released v1.3.0: 3 issues labeled release:cli/v1.3.0") {
+		t.Fatalf("release create output:\n%s", got)
+	}
+	notes := "## Features\n\n- Add export (" + feature.ID + ")\n\n## Fixes\n\n- Fix crash (" + fix.ID + ")\n\n## Other changes\n\n- Bump deps (" + chore.ID + ")\n"
+	ghArgs := readArgsFile(t, ghArgsFile)
+	if !slices.Equal(ghArgs[:5], []string{"release", "create", "v1.3.0", "--title", "v1.3.0"}) || !slices.Contains(ghArgs, "owner/repo") {
+		t.Fatalf("gh args = %q", ghArgs)
+	}
+	if rel, err := store.GetRelease(ctx, "cli", "v1.3.0"); err != nil || rel.Notes != notes || rel.URL != "https://github.com/owner/repo/releases/tag/v1.3.0" {
+		t.Fatalf("stored release = %+v, %v", rel, err)
+	}
+	labeled, err := store.ListIssues(ctx, sqlite.ListFilter{Label: "release:cli/v1.3.0"})
+	if err != nil || len(labeled) != 3 {
+		t.Fatalf("labeled issues = %d, %v", len(labeled), err)
+	}
+
+	if _, err := run("create", "v1.3.0", "--project", "cli"); err == nil || !strings.Contains(err.Error(), "release already exists") {
+		t.Fatalf("duplicate release error = %v", err)
+	}
+	if _, err := run("create", "v1.3.1", "--project", "cli"); err == nil || !strings.Contains(err.Error(), "no done issues since the last release") {
+		t.Fatalf("empty release error = %v", err)
+	}
+
+	later := create("Add import", issue.StatusDone, "cli", "enhancement")
+	got, err = run("create", "v1.4.0", "--project", "cli", "--dry-run")
+	if err != nil || !strings.Contains(got, "- Add import ("+later.ID+")") || strings.Contains(got, "Add export") {
+		t.Fatalf("dry run = %v\n%s", err, got)
+	}
+	if _, err := store.GetRelease(ctx, "cli", "v1.4.0"); err == nil {
+		t.Fatalf("dry run should not record the release")
+	}
+}
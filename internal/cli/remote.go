@@ -0,0 +1,423 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/secret"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/myuon/track/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+func newRemoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remote",
+		Short: "Manage remote track servers",
+		Long:  "Manage remote track servers. list, show, new, set, and next take --remote <name> to work on a remote server's issues over its REST API instead of the local database.",
+	}
+	cmd.AddCommand(newRemoteAddCmd())
+	cmd.AddCommand(newRemoteListCmd())
+	cmd.AddCommand(newRemoteRemoveCmd())
+	return cmd
+}
+
+func newRemoteAddCmd() *cobra.Command {
+	var token string
+	cmd := &cobra.Command{
+		Use:   "add <name> <url>",
+		Short: "Add a remote server",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			// The token goes to the secret store; the row keeps a reference.
+			ref := token
+			if token != "" && !secret.HasRef(token) {
+				ref = secret.Ref(remoteTokenSecret(args[0]))
+			}
+			if err := store.AddRemote(ctx, sqlite.Remote{Name: args[0], URL: args[1], Token: ref}); err != nil {
+				return err
+			}
+			if ref != token {
+				if _, err := secret.Set(remoteTokenSecret(args[0]), token); err != nil {
+					_ = store.RemoveRemote(ctx, args[0])
+					return err
+				}
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&token, "token", "", "API token for the remote (see track token add); kept in the secret store")
+	return cmd
+}
+
+func newRemoteListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List remotes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			remotes, err := store.ListRemotes(ctx)
+			if err != nil {
+				return err
+			}
+			for _, r := range remotes {
+				auth := "no token"
+				if r.Token != "" {
+					auth = "token"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\n", r.Name, r.URL, auth)
+			}
+			return nil
+		},
+	}
+}
+
+func newRemoteRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <name>",
+		Short: "Remove a remote",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			r, err := store.GetRemote(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if err := store.RemoveRemote(ctx, args[0]); err != nil {
+				return err
+			}
+			if r.Token == secret.Ref(remoteTokenSecret(r.Name)) {
+				if err := secret.Delete(remoteTokenSecret(r.Name)); err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", err)
+				}
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+}
+
+const remoteFlagUsage = "Work on this remote server (see track remote add) instead of the local database"
+
+// remoteClient returns an API client for the named remote.
+func remoteClient(ctx context.Context, name string) (*client.Client, error) {
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	r, err := store.GetRemote(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	// Remotes added before tokens went to the secret store keep the token in
+	// the row; move it there on first use.
+	if r.Token != "" && !secret.HasRef(r.Token) {
+		if _, err := secret.Set(remoteTokenSecret(name), r.Token); err != nil {
+			return nil, err
+		}
+		if err := store.SetRemoteToken(ctx, name, secret.Ref(remoteTokenSecret(name))); err != nil {
+			return nil, err
+		}
+	}
+	token, err := secret.Expand(r.Token)
+	if err != nil {
+		return nil, fmt.Errorf("remote %s: %w", name, err)
+	}
+	c := client.New(r.URL)
+	c.Token = token
+	return c, nil
+}
+
+// remoteTokenSecret names the secret holding a remote's API token.
+func remoteTokenSecret(remote string) string {
+	return "remote." + remote + ".token"
+}
+
+func itemFromRemote(it client.Issue) issue.Item {
+	return issue.Item{
+		ID:         it.ID,
+		Title:      it.Title,
+		Status:     it.Status,
+		Priority:   it.Priority,
+		Assignee:   it.Assignee,
+		Due:        it.Due,
+		Labels:     it.Labels,
+		NextAction: it.NextAction,
+		Body:       it.Body,
+		CreatedAt:  it.CreatedAt,
+		UpdatedAt:  it.UpdatedAt,
+	}
+}
+
+// listOptions maps the list filter flags onto the API's list parameters.
+// The server applies the same defaults, so done and archived issues are
+// excluded unless a status is given.
+func (o *listFilterFlags) listOptions(sort string) client.ListOptions {
+	opts := client.ListOptions{
+		Label:    o.label,
+		Assignee: o.assignee,
+		Search:   o.search,
+		Sort:     sort,
+		Query:    o.query,
+	}
+	if o.status != "" {
+		opts.Statuses = strings.Split(o.status, ",")
+	}
+	if o.project != "" {
+		opts.Query = strings.TrimSpace(opts.Query + " project:" + o.project)
+	}
+	return opts
+}
+
+func printRemoteIssueDetail(cmd *cobra.Command, it client.Issue) {
+	out := cmd.OutOrStdout()
+	c := newCLIColor(out)
+	fmt.Fprintf(out, "id: %s\n", it.ID)
+	fmt.Fprintf(out, "title: %s\n", it.Title)
+	fmt.Fprintf(out, "status: %s\n", c.status(it.Status))
+	fmt.Fprintf(out, "priority: %s\n", c.priority(it.Priority))
+	if it.Assignee != "" {
+		fmt.Fprintf(out, "assignee: %s\n", it.Assignee)
+	}
+	if it.Due != "" {
+		fmt.Fprintf(out, "due: %s\n", it.Due)
+	}
+	if len(it.Labels) > 0 {
+		fmt.Fprintf(out, "labels: %s\n", strings.Join(it.Labels, ","))
+	}
+	if it.NextAction != "" {
+		fmt.Fprintf(out, "next_action: %s\n", it.NextAction)
+	}
+	if done, total := issue.ChecklistProgress(it.Body); total > 0 {
+		fmt.Fprintf(out, "acceptance: %d/%d\n", done, total)
+	}
+	if it.Project != "" {
+		fmt.Fprintf(out, "project: %s\n", it.Project)
+	}
+	if it.Body != "" {
+		fmt.Fprintf(out, "body: %s\n", it.Body)
+	}
+	fmt.Fprintf(out, "created_at: %s\n", it.CreatedAt)
+	fmt.Fprintf(out, "updated_at: %s\n", it.UpdatedAt)
+}
+
+// remoteList lists a remote's issues, caching them for offline use. When the
+// remote is unreachable it lists the cached copies instead, which only
+// supports the status filter.
+func remoteList(cmd *cobra.Command, remote string, flags *listFilterFlags, sort string) error {
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	c, err := remoteClient(ctx, remote)
+	if err != nil {
+		return err
+	}
+
+	remoteItems, err := c.ListIssues(ctx, flags.listOptions(sort))
+	if isOffline(err) {
+		if flags.label != "" || flags.assignee != "" || flags.search != "" || flags.project != "" || flags.query != "" {
+			return fmt.Errorf("remote %s is unreachable (%w); offline lists only support --status", remote, err)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "remote %s is unreachable; showing cached issues (see track pull)\n", remote)
+		remoteItems, err = cachedRemoteList(ctx, store, remote, flags.status)
+	}
+	if err != nil {
+		return err
+	}
+	items := make([]issue.Item, 0, len(remoteItems))
+	for _, it := range remoteItems {
+		if err := cacheRemoteIssue(ctx, store, remote, it); err != nil {
+			return err
+		}
+		items = append(items, itemFromRemote(it))
+	}
+	printIssueList(cmd.OutOrStdout(), items)
+	return nil
+}
+
+func cachedRemoteList(ctx context.Context, store *sqlite.Store, remote, status string) ([]client.Issue, error) {
+	cached, err := store.ListRemoteIssues(ctx, remote)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []string
+	if status != "" {
+		statuses = strings.Split(status, ",")
+	}
+	out := make([]client.Issue, 0, len(cached))
+	for _, r := range cached {
+		it, _, err := cachedRemoteIssue(ctx, store, remote, r.IssueID)
+		if err != nil {
+			return nil, err
+		}
+		visible := it.Status != issue.StatusDone && it.Status != issue.StatusArchived
+		if statuses != nil {
+			visible = slices.Contains(statuses, it.Status)
+		}
+		if visible {
+			out = append(out, it)
+		}
+	}
+	return out, nil
+}
+
+// remoteShow prints each issue like show does, minus the details only the
+// local database knows (git branches). Unreachable remotes fall back to the
+// cached copy.
+func remoteShow(cmd *cobra.Command, remote string, ids []string) error {
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	c, err := remoteClient(ctx, remote)
+	if err != nil {
+		return err
+	}
+	get := func(id string) (client.Issue, error) {
+		it, err := c.GetIssue(ctx, id)
+		if isOffline(err) {
+			cached, ok, cacheErr := cachedRemoteIssue(ctx, store, remote, id)
+			if cacheErr != nil {
+				return client.Issue{}, cacheErr
+			}
+			if !ok {
+				return client.Issue{}, fmt.Errorf("remote %s is unreachable and %s is not cached: %w", remote, id, err)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "remote %s is unreachable; showing cached %s\n", remote, id)
+			return cached, nil
+		}
+		if err != nil {
+			return client.Issue{}, err
+		}
+		return it, cacheRemoteIssue(ctx, store, remote, it)
+	}
+
+	failed := 0
+	for i, id := range ids {
+		it, err := get(id)
+		if err != nil {
+			if len(ids) == 1 {
+				return err
+			}
+			failed++
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s: %v\n", id, err)
+			continue
+		}
+		if i > 0 {
+			fmt.Fprintln(cmd.OutOrStdout())
+		}
+		printRemoteIssueDetail(cmd, it)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d issues failed", failed, len(ids))
+	}
+	return nil
+}
+
+// remoteNew creates an issue on the remote, or queues it for push when the
+// remote is unreachable.
+func remoteNew(cmd *cobra.Command, remote string, in client.NewIssue) error {
+	ctx := context.Background()
+	c, err := remoteClient(ctx, remote)
+	if err != nil {
+		return err
+	}
+	created, err := c.CreateIssue(ctx, in)
+	if isOffline(err) {
+		store, err := sqlite.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		if err := queueRemoteCreate(ctx, store, remote, in); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "queued: remote %s is unreachable; run track push\n", remote)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), created.ID)
+	return nil
+}
+
+// remoteSet applies set's flags through the remote API. --label adds to the
+// issue's current labels, as it does locally. Changes to an unreachable
+// remote are queued for push.
+func remoteSet(cmd *cobra.Command, remote string, ids []string, patch client.IssuePatch, addLabels []string) error {
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	c, err := remoteClient(ctx, remote)
+	if err != nil {
+		return err
+	}
+	return runForIssues(cmd, ids, func(id string) error {
+		p := patch
+		if len(addLabels) > 0 {
+			current, err := c.GetIssue(ctx, id)
+			if isOffline(err) {
+				cached, ok, cacheErr := cachedRemoteIssue(ctx, store, remote, id)
+				if cacheErr != nil {
+					return cacheErr
+				}
+				if !ok {
+					return fmt.Errorf("remote %s is unreachable and %s is not cached: %w", remote, id, err)
+				}
+				current, err = cached, nil
+			}
+			if err != nil {
+				return err
+			}
+			next := append(slices.Clone(current.Labels), addLabels...)
+			p.Labels = &next
+		}
+		updated, err := c.UpdateIssue(ctx, id, p)
+		if isOffline(err) {
+			if err := queueRemoteUpdate(ctx, store, remote, id, p); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s: remote %s is unreachable; queued for track push\n", id, remote)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return cacheRemoteIssue(ctx, store, remote, updated)
+	})
+}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/myuon/track/internal/api"
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/secret"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func TestRemoteProxiesThroughAPI(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	secret, err := store.CreateAPIToken(ctx, "teammate", sqlite.RoleContributor)
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+
+	srv := httptest.NewServer(api.NewHandler())
+	t.Cleanup(srv.Close)
+
+	run := func(cmd *cobra.Command, args ...string) (string, error) {
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	if _, err := run(newRemoteCmd(), "add", "work", srv.URL, "--token", secret); err != nil {
+		t.Fatalf("remote add: %v", err)
+	}
+	if _, err := run(newRemoteCmd(), "add", "work", srv.URL); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("duplicate remote error = %v", err)
+	}
+	if _, err := run(newRemoteCmd(), "add", "bad", "tracker.example"); err == nil {
+		t.Fatalf("expected invalid url error")
+	}
+	if out, err := run(newRemoteCmd(), "list"); err != nil || out != "work\t"+srv.URL+"\ttoken\n" {
+		t.Fatalf("remote list = %q, %v", out, err)
+	}
+
+	out, err := run(newNewCmd(), "Remote task", "--remote", "work", "--priority", "p1")
+	if err != nil || strings.TrimSpace(out) != "TRK-1" {
+		t.Fatalf("new --remote = %q, %v", out, err)
+	}
+	if _, err := run(newSetCmd(), "TRK-1", "--remote", "work", "--status", "ready", "--label", "team"); err != nil {
+		t.Fatalf("set --remote: %v", err)
+	}
+	out, err = run(newListCmd(), "--remote", "work")
+	if err != nil || !strings.Contains(out, "Remote task") {
+		t.Fatalf("list --remote = %q, %v", out, err)
+	}
+	out, err = run(newShowCmd(), "1", "--remote", "work")
+	if err != nil || !strings.Contains(out, "status: ready") || !strings.Contains(out, "labels: team") {
+		t.Fatalf("show --remote = %q, %v", out, err)
+	}
+	out, err = run(newNextCmd(), "--remote", "work")
+	if err != nil || !strings.HasPrefix(out, "TRK-1\tp1\tRemote task") {
+		t.Fatalf("next --remote = %q, %v", out, err)
+	}
+
+	entries, err := store.ListActivity(ctx, "", 0)
+	if err != nil {
+		t.Fatalf("ListActivity() error: %v", err)
+	}
+	for _, e := range entries {
+		if e.Actor != "api:teammate" {
+			t.Fatalf("activity %+v was not made through the remote token", e)
+		}
+	}
+
+	if _, err := run(newListCmd(), "--remote", "nope"); err == nil || !strings.Contains(err.Error(), "remote not found") {
+		t.Fatalf("unknown remote error = %v", err)
+	}
+	if _, err := run(newRemoteCmd(), "rm", "work"); err != nil {
+		t.Fatalf("remote rm: %v", err)
+	}
+}
+
+func TestRemoteTokensLiveInTheSecretStore(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	cfg := appconfig.Default()
+	cfg.SecretStore = secret.StoreFile
+	if err := appconfig.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	cmd := newRemoteCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"add", "work", "https://tracker.example", "--token", "remote-token-0123456789"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("remote add: %v", err)
+	}
+	r, err := store.GetRemote(ctx, "work")
+	if err != nil || r.Token != "{{secret:remote.work.token}}" {
+		t.Fatalf("stored token = %q, %v; want only a secret reference", r.Token, err)
+	}
+	if c, err := remoteClient(ctx, "work"); err != nil || c.Token != "remote-token-0123456789" {
+		t.Fatalf("remoteClient token = %v", err)
+	}
+
+	// A remote added before tokens moved to the secret store.
+	if err := store.AddRemote(ctx, sqlite.Remote{Name: "old", URL: "https://old.example", Token: "old-token-0123456789"}); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := remoteClient(ctx, "old"); err != nil || c.Token != "old-token-0123456789" {
+		t.Fatalf("remoteClient token for old remote = %v", err)
+	}
+	if r, err := store.GetRemote(ctx, "old"); err != nil || r.Token != "{{secret:remote.old.token}}" {
+		t.Fatalf("old remote token after use = %q, %v; want it moved to the secret store", r.Token, err)
+	}
+
+	for _, name := range []string{"work", "old"} {
+		cmd := newRemoteCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetArgs([]string{"rm", name})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("remote rm %s: %v", name, err)
+		}
+	}
+	if secrets, err := secret.List(); err != nil || len(secrets) != 0 {
+		t.Fatalf("secrets after remote rm = %+v, %v", secrets, err)
+	}
+}
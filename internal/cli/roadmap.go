@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func newRoadmapCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "roadmap",
+		Short: "Print projects in execution order with completion",
+		Long:  "Print projects in the order they can be worked on: each project comes after the projects it depends on (see track project depends-on), and otherwise in project order (see track project reorder). Completion counts done issues out of each project's issues, leaving archived ones out.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			projects, err := store.ListProjects(ctx)
+			if err != nil {
+				return err
+			}
+			deps, err := store.ListProjectDependencies(ctx)
+			if err != nil {
+				return err
+			}
+			progress, err := store.ListProjectProgress(ctx)
+			if err != nil {
+				return err
+			}
+			for i, p := range roadmapOrder(projects, deps) {
+				pr := progress[p.Key]
+				pct := 0
+				if pr.Total > 0 {
+					pct = pr.Done * 100 / pr.Total
+				}
+				line := fmt.Sprintf("%d\t%s\t%s\t%d%% (%d/%d)", i+1, p.Key, p.Name, pct, pr.Done, pr.Total)
+				if len(deps[p.Key]) > 0 {
+					line += "\tafter " + strings.Join(deps[p.Key], ",")
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), line)
+			}
+			return nil
+		},
+	}
+}
+
+// roadmapOrder sorts projects so each follows its dependencies, keeping the
+// given order wherever the dependencies allow.
+func roadmapOrder(projects []sqlite.Project, deps map[string][]string) []sqlite.Project {
+	placed := map[string]bool{}
+	pending := slices.Clone(projects)
+	out := make([]sqlite.Project, 0, len(projects))
+	for len(pending) > 0 {
+		next := slices.IndexFunc(pending, func(p sqlite.Project) bool {
+			return !slices.ContainsFunc(deps[p.Key], func(d string) bool { return !placed[d] })
+		})
+		if next == -1 {
+			// The store rejects cycles, so this only guards against
+			// dependencies written by hand.
+			next = 0
+		}
+		placed[pending[next].Key] = true
+		out = append(out, pending[next])
+		pending = slices.Delete(pending, next, next+1)
+	}
+	return out
+}
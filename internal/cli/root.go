@@ -21,24 +21,75 @@ func newRootCmd() *cobra.Command {
 		SilenceErrors: true,
 	}
 
+	// --sandbox runs any command against a copy of the database. people add
+	// keeps its own --sandbox flag, which shadows this one there.
+	sb := &sandboxRun{}
+	cmd.PersistentFlags().BoolVar(&sb.enabled, "sandbox", false, "Run against a throwaway copy of the database and print what would change")
+	cmd.PersistentFlags().StringVar(&colorMode, "color", colorAuto, "Color output: auto (terminal without NO_COLOR), always, or never")
+	cmd.PersistentPreRunE = func(c *cobra.Command, args []string) error {
+		if err := validateColorMode(colorMode); err != nil {
+			return err
+		}
+		return sb.start(c)
+	}
+	cmd.PersistentPostRunE = func(c *cobra.Command, args []string) error {
+		return sb.report(c.Context(), c.OutOrStdout())
+	}
+
 	cmd.SetOut(os.Stdout)
 	cmd.SetErr(os.Stderr)
 
 	cmd.AddCommand(newVersionCmd())
+	cmd.AddCommand(newDoctorCmd())
 	cmd.AddCommand(newConfigCmd())
+	cmd.AddCommand(newSecretCmd())
 	for _, c := range newIssueCommands() {
 		cmd.AddCommand(c)
 	}
 	for _, c := range newIOCommands() {
 		cmd.AddCommand(c)
 	}
+	cmd.AddCommand(newLogCmd())
+	cmd.AddCommand(newBlameCmd())
+	cmd.AddCommand(newDiffCmd())
+	cmd.AddCommand(newCapacityCmd())
+	cmd.AddCommand(newStatsCmd())
+	cmd.AddCommand(newEstimateCmd())
+	cmd.AddCommand(newPomoCmd())
+	cmd.AddCommand(newDigestCmd())
+	cmd.AddCommand(newShareCmd())
+	cmd.AddCommand(newRoadmapCmd())
+	cmd.AddCommand(newTimelineCmd())
+	cmd.AddCommand(newPeopleCmd())
+	cmd.AddCommand(newCaptureCmd())
+	cmd.AddCommand(newGrepCmd())
+	cmd.AddCommand(newTemplateCmd())
+	cmd.AddCommand(newReleaseCmd())
 	cmd.AddCommand(newHookCmd())
+	cmd.AddCommand(newAutomationCmd())
+	cmd.AddCommand(newRuleCmd())
+	cmd.AddCommand(newScriptCmd())
+	cmd.AddCommand(newCronCmd())
+	cmd.AddCommand(newRecurCmd())
 	cmd.AddCommand(newGitCmd())
 	cmd.AddCommand(newGitHubCmd())
+	cmd.AddCommand(newDepCmd())
 	cmd.AddCommand(newDispatchCmd())
+	cmd.AddCommand(newAutopilotCmd())
+	cmd.AddCommand(newFreezeCmd())
+	cmd.AddCommand(newThawCmd())
+	cmd.AddCommand(newRunCmd())
+	cmd.AddCommand(newSessionCmd())
+	cmd.AddCommand(newSpecCmd())
 	cmd.AddCommand(newUICmd())
 	cmd.AddCommand(newProjectCmd())
 	cmd.AddCommand(newAPICmd())
+	cmd.AddCommand(newTokenCmd())
+	cmd.AddCommand(newRemoteCmd())
+	cmd.AddCommand(newPushCmd())
+	cmd.AddCommand(newPullCmd())
+	cmd.AddCommand(newServeCmd())
+	cmd.AddCommand(newRPCCmd())
 
 	return cmd
 }
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"context"
+	"strings"
+
+	"github.com/myuon/track/internal/rpc"
+	"github.com/spf13/cobra"
+)
+
+func newRPCCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rpc",
+		Short: "Serve JSON-RPC over stdio for editor integrations",
+		Long:  "Serve JSON-RPC 2.0 over stdin/stdout with LSP-style Content-Length framing.\nMethods: " + strings.Join(rpc.Methods, ", "),
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			return rpc.Serve(ctx, cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	}
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/myuon/track/internal/automation"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func newRuleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rule",
+		Short: "Manage if/then field automations",
+	}
+	cmd.AddCommand(newRuleAddCmd())
+	cmd.AddCommand(newRuleListCmd())
+	cmd.AddCommand(newRuleRemoveCmd())
+	cmd.AddCommand(newRuleTestCmd())
+	return cmd
+}
+
+func newRuleAddCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "add <expr>",
+		Short: `Add a rule, e.g. "when label=bug and priority=none then set priority=p1"`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := automation.ParseRule(args[0]); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			r, err := store.AddRule(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), r.ID)
+			return nil
+		},
+	}
+}
+
+func newRuleListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List rules",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			rules, err := store.ListRules(ctx)
+			if err != nil {
+				return err
+			}
+			for _, r := range rules {
+				fmt.Fprintf(cmd.OutOrStdout(), "%d\t%s\t%s\n", r.ID, enabledLabel(r.Enabled), r.Expr)
+			}
+			return nil
+		},
+	}
+}
+
+func newRuleRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <rule_id>",
+		Short: "Remove rule",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ruleID, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid rule_id: %s", args[0])
+			}
+
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if err := store.RemoveRule(ctx, ruleID); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+}
+
+func newRuleTestCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "test <rule_id|expr> <issue_id>",
+		Short: "Show whether a rule matches an issue and what it would change (no writes)",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			expr := args[0]
+			if ruleID, err := strconv.Atoi(args[0]); err == nil {
+				stored, err := store.GetRule(ctx, ruleID)
+				if err != nil {
+					return err
+				}
+				expr = stored.Expr
+			}
+			rule, err := automation.ParseRule(expr)
+			if err != nil {
+				return err
+			}
+			it, err := store.GetIssue(ctx, normalizeIssueIDArg(args[1]))
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if !rule.Matches(it) {
+				fmt.Fprintf(out, "%s: no match\n", it.ID)
+				return nil
+			}
+			pending := rule.Pending(it)
+			if len(pending) == 0 {
+				fmt.Fprintf(out, "%s: match (no changes)\n", it.ID)
+				return nil
+			}
+			fmt.Fprintf(out, "%s: match -> set %s\n", it.ID, automation.FormatActions(pending))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestRuleAddAppliesOnCreateAndTestReportsChanges(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	add := newRuleCmd()
+	var addOut bytes.Buffer
+	add.SetOut(&addOut)
+	add.SetArgs([]string{"add", "when label=bug and priority=none then set priority=p1"})
+	if err := add.Execute(); err != nil {
+		t.Fatalf("rule add error: %v", err)
+	}
+	if strings.TrimSpace(addOut.String()) != "1" {
+		t.Fatalf("unexpected rule add output: %q", addOut.String())
+	}
+
+	create := newNewCmd()
+	var createOut bytes.Buffer
+	create.SetOut(&createOut)
+	create.SetArgs([]string{"crash", "--label", "bug"})
+	if err := create.Execute(); err != nil {
+		t.Fatalf("new error: %v", err)
+	}
+	id := strings.TrimSpace(createOut.String())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	got, err := store.GetIssue(ctx, id)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if got.Priority != "p1" {
+		t.Fatalf("priority = %q, want p1", got.Priority)
+	}
+
+	test := newRuleCmd()
+	var testOut bytes.Buffer
+	test.SetOut(&testOut)
+	test.SetArgs([]string{"test", "when label=bug then set assignee=agent", id})
+	if err := test.Execute(); err != nil {
+		t.Fatalf("rule test error: %v", err)
+	}
+	if testOut.String() != id+": match -> set assignee=agent\n" {
+		t.Fatalf("unexpected rule test output: %q", testOut.String())
+	}
+	unchanged, err := store.GetIssue(ctx, id)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if unchanged.Assignee != "" {
+		t.Fatalf("rule test must not write: %+v", unchanged)
+	}
+
+	list := newRuleCmd()
+	var listOut bytes.Buffer
+	list.SetOut(&listOut)
+	list.SetArgs([]string{"list"})
+	if err := list.Execute(); err != nil {
+		t.Fatalf("rule list error: %v", err)
+	}
+	if listOut.String() != "1\tenabled\twhen label=bug and priority=none then set priority=p1\n" {
+		t.Fatalf("unexpected rule list output: %q", listOut.String())
+	}
+}
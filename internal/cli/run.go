@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+const sessionKindRun = "run"
+
+var runPromptTemplates = map[string]func(issueID string) string{
+	"impl": buildImplPrompt,
+	"plan": buildPlanningPrompt,
+}
+
+type agentSessionRequest struct {
+	Runner  string
+	Agent   string
+	Sandbox string
+	Dir     string
+	Prompt  string
+	Stdin   io.Reader
+	Stdout  io.Writer
+	Stderr  io.Writer
+}
+
+var agentSessionRunner = func(ctx context.Context, req agentSessionRequest) error {
+	name, args := agentCommand(req.Runner, req.Sandbox, req.Dir, req.Prompt)
+	c := exec.CommandContext(ctx, name, args...)
+	c.Dir = req.Dir
+	agent := req.Agent
+	if agent == "" {
+		agent = req.Runner
+	}
+	c.Env = append(os.Environ(), sqlite.ActorEnv+"=agent:"+agent)
+	if req.Sandbox != "" {
+		c.Env = append(c.Env, "TRACK_SANDBOX="+req.Sandbox)
+	}
+	if trackHome, err := appconfig.HomeDir(); err == nil && strings.TrimSpace(trackHome) != "" {
+		c.Env = append(c.Env, "TRACK_HOME="+trackHome)
+	}
+	c.Stdin = req.Stdin
+	c.Stdout = req.Stdout
+	c.Stderr = req.Stderr
+	return c.Run()
+}
+
+func newRunCmd() *cobra.Command {
+	var runner string
+	var promptTemplate string
+
+	cmd := &cobra.Command{
+		Use:   "run <id>",
+		Short: "Run a one-off agent session on an issue in the current directory",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if runner != "codex" && runner != "claude" {
+				return fmt.Errorf("invalid --runner: %s", runner)
+			}
+			buildPrompt, ok := runPromptTemplates[promptTemplate]
+			if !ok {
+				return fmt.Errorf("invalid --prompt-template: %s", promptTemplate)
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			issueID := normalizeIssueIDArg(args[0])
+			it, err := store.GetIssue(ctx, issueID)
+			if err != nil {
+				return err
+			}
+			agent, release, err := resolveAgent(ctx, store, it, runner, cmd.Flags().Changed("runner"))
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			sess, runErr := runAgentSession(ctx, store, issueID, sessionKindRun, agentSessionRequest{
+				Runner:  agent.Runner,
+				Agent:   agent.Name,
+				Sandbox: agent.Sandbox,
+				Dir:     cwd,
+				Prompt:  buildPrompt(issueID),
+				Stdin:   cmd.InOrStdin(),
+				Stdout:  cmd.OutOrStdout(),
+				Stderr:  cmd.ErrOrStderr(),
+			})
+			if sess.ID == 0 {
+				return runErr
+			}
+
+			nextAction := fmt.Sprintf("review run result: %s", sess.LogPath)
+			if runErr != nil {
+				nextAction = fmt.Sprintf("run failed (exit %d): inspect %s", sess.ExitCode, sess.LogPath)
+			}
+			if _, err := store.SetNextAction(ctx, issueID, nextAction); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "transcript: %s\n", sess.LogPath)
+			if runErr != nil {
+				return fmt.Errorf("run %s failed: %w", issueID, runErr)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&runner, "runner", "codex", "Runner (codex|claude); defaults to the assignee's agent profile")
+	cmd.Flags().StringVar(&promptTemplate, "prompt-template", "impl", "Prompt template (impl|plan)")
+	return cmd
+}
+
+// agent is the runner a session starts, as resolved by resolveAgent.
+type agent struct {
+	Name    string
+	Runner  string
+	Sandbox string
+}
+
+// resolveAgent picks the runner for an issue. An explicit --runner wins;
+// otherwise an assignee with an agent profile (see track people add --agent)
+// supplies the runner and sandbox, and takes one of the agent's concurrency
+// slots until release is called. Anything else runs codex.
+func resolveAgent(ctx context.Context, store *sqlite.Store, it issue.Item, runnerFlag string, runnerSet bool) (agent, func() error, error) {
+	noop := func() error { return nil }
+	if !runnerSet {
+		p, ok, err := store.AgentProfile(ctx, it.Assignee)
+		if err != nil {
+			return agent{}, nil, err
+		}
+		if ok {
+			release, err := store.AcquireAgentLease(ctx, p.Name, it.ID, p.MaxConcurrency)
+			if err != nil {
+				return agent{}, nil, err
+			}
+			return agent{Name: p.Name, Runner: p.Runner, Sandbox: p.Sandbox}, release, nil
+		}
+	}
+	return agent{Name: runnerFlag, Runner: runnerFlag}, noop, nil
+}
+
+// agentCommand builds the command line for a runner. codex and claude are
+// built in; any other runner is a command line that gets the prompt as its
+// last argument and the sandbox in $TRACK_SANDBOX.
+func agentCommand(runner, sandbox, dir, prompt string) (string, []string) {
+	trackHome, err := appconfig.HomeDir()
+	hasHome := err == nil && strings.TrimSpace(trackHome) != ""
+	switch runner {
+	case "claude":
+		args := []string{"-p"}
+		if hasHome {
+			args = append(args, "--add-dir", trackHome)
+		}
+		return "claude", append(args, prompt)
+	case "codex", "":
+		args := []string{"exec", "-C", dir}
+		if sandbox != "" {
+			args = append(args, "--sandbox", sandbox)
+		}
+		if hasHome {
+			args = append(args, "--add-dir", trackHome)
+		}
+		return "codex", append(args, prompt)
+	default:
+		fields := strings.Fields(runner)
+		return fields[0], append(fields[1:], prompt)
+	}
+}
+
+func buildImplPrompt(issueID string) string {
+	return fmt.Sprintf("$track-dev-cycle execution %s", issueID)
+}
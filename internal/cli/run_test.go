@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestRunRecordsTranscriptAndUpdatesNextAction(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "run me", Status: issue.StatusReady, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	origRunner := agentSessionRunner
+	t.Cleanup(func() { agentSessionRunner = origRunner })
+	var gotReq agentSessionRequest
+	agentSessionRunner = func(_ context.Context, req agentSessionRequest) error {
+		gotReq = req
+		fmt.Fprintln(req.Stdout, "agent output")
+		fmt.Fprintln(req.Stderr, "agent warning")
+		return nil
+	}
+
+	cmd := newRunCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"1", "--runner", "claude"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("run command error: %v", err)
+	}
+
+	if gotReq.Runner != "claude" {
+		t.Fatalf("runner = %q, want claude", gotReq.Runner)
+	}
+	if gotReq.Prompt != "$track-dev-cycle execution "+it.ID {
+		t.Fatalf("prompt = %q", gotReq.Prompt)
+	}
+
+	sessions, err := store.ListSessions(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("ListSessions() error: %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("len(sessions) = %d, want 1", len(sessions))
+	}
+	sess := sessions[0]
+	if sess.Kind != sessionKindRun || sess.ExitCode != 0 {
+		t.Fatalf("unexpected session: %+v", sess)
+	}
+	raw, err := os.ReadFile(sess.LogPath)
+	if err != nil {
+		t.Fatalf("ReadFile(transcript) error: %v", err)
+	}
+	if !strings.Contains(string(raw), "agent output\n") || !strings.Contains(string(raw), "agent warning\n") {
+		t.Fatalf("transcript should capture stdout and stderr: %q", string(raw))
+	}
+	if !strings.Contains(out.String(), "transcript: "+sess.LogPath+"\n") {
+		t.Fatalf("output should print transcript path: %q", out.String())
+	}
+
+	got, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if got.NextAction != "review run result: "+sess.LogPath {
+		t.Fatalf("next_action = %q", got.NextAction)
+	}
+	if got.Status != issue.StatusReady {
+		t.Fatalf("status = %q, want unchanged %q", got.Status, issue.StatusReady)
+	}
+}
+
+func TestRunFailureRecordsSessionAndReturnsError(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "run me", Status: issue.StatusReady, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	origRunner := agentSessionRunner
+	t.Cleanup(func() { agentSessionRunner = origRunner })
+	agentSessionRunner = func(_ context.Context, _ agentSessionRequest) error {
+		return errors.New("runner crashed")
+	}
+
+	cmd := newRunCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{it.ID})
+	err = cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "runner crashed") {
+		t.Fatalf("expected runner error, got: %v", err)
+	}
+
+	sessions, err := store.ListSessions(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("ListSessions() error: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ExitCode != -1 {
+		t.Fatalf("unexpected sessions: %+v", sessions)
+	}
+	got, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if !strings.HasPrefix(got.NextAction, "run failed (exit -1): inspect ") {
+		t.Fatalf("next_action = %q", got.NextAction)
+	}
+}
+
+func TestRunRejectsUnknownPromptTemplate(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	cmd := newRunCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"TRK-1", "--prompt-template", "nope"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "invalid --prompt-template") {
+		t.Fatalf("expected invalid template error, got: %v", err)
+	}
+}
+
+func TestRunResolvesRunnerFromAssigneeAgentProfile(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	if err := store.AddPerson(ctx, sqlite.Person{Name: "reviewer", Kind: sqlite.KindAgent, Runner: "claude", Sandbox: "read-only", MaxConcurrency: 1}); err != nil {
+		t.Fatalf("AddPerson() error: %v", err)
+	}
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "review me", Status: issue.StatusReady, Priority: "p2", Assignee: "reviewer"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	origRunner := agentSessionRunner
+	t.Cleanup(func() { agentSessionRunner = origRunner })
+	var gotReq agentSessionRequest
+	agentSessionRunner = func(_ context.Context, req agentSessionRequest) error {
+		gotReq = req
+		// The only slot is taken while this session runs.
+		if _, err := store.AcquireAgentLease(ctx, "reviewer", it.ID, 1); err == nil {
+			t.Errorf("second lease should be refused while the session runs")
+		}
+		return nil
+	}
+
+	cmd := newRunCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{it.ID})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("run command error: %v\n%s", err, out.String())
+	}
+	if gotReq.Runner != "claude" || gotReq.Agent != "reviewer" || gotReq.Sandbox != "read-only" {
+		t.Fatalf("request = %+v, want the reviewer profile", gotReq)
+	}
+
+	release, err := store.AcquireAgentLease(ctx, "reviewer", it.ID, 1)
+	if err != nil {
+		t.Fatalf("lease should be released after the session: %v", err)
+	}
+	_ = release()
+
+	if name, args := agentCommand("my-agent --fast", "read-only", tmp, "do it"); name != "my-agent" || strings.Join(args, " ") != "--fast do it" {
+		t.Fatalf("agentCommand(custom) = %s %v", name, args)
+	}
+}
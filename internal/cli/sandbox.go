@@ -0,0 +1,254 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/myuon/track/internal/textdiff"
+	"github.com/spf13/cobra"
+)
+
+// sandboxRun points TRACK_HOME at a throwaway copy of the database and
+// config for the length of one command, then reports how the copy's issues
+// differ from the real ones.
+type sandboxRun struct {
+	enabled bool
+	dir     string
+	hadHome bool
+	oldHome string
+}
+
+// sandboxRefused lists the commands that act outside the machine: the copy
+// keeps the remotes, their tokens, and the GitHub links, so running them
+// would push, open PRs, or merge for real.
+var sandboxRefused = map[string]string{
+	"track push":            "it pushes to a remote",
+	"track dispatch":        "it runs an agent, pushes a branch, and opens a PR",
+	"track gh issue create": "it creates a GitHub issue",
+	"track gh auto-merge":   "it merges a GitHub PR",
+}
+
+// sandboxRefusal reports why cmd cannot run in the sandbox, or "" if it can.
+func sandboxRefusal(cmd *cobra.Command) string {
+	if why, ok := sandboxRefused[cmd.CommandPath()]; ok {
+		return why
+	}
+	if f := cmd.Flags().Lookup("remote"); f != nil && f.Changed {
+		return "--remote acts on a remote track server"
+	}
+	if f := cmd.Flags().Lookup("email"); f != nil && f.Changed && cmd.CommandPath() == "track digest" {
+		return "--email sends mail"
+	}
+	if f := cmd.Flags().Lookup("gh"); f != nil && f.Changed && cmd.CommandPath() == "track release create" {
+		return "--gh creates a GitHub release"
+	}
+	return ""
+}
+
+func (s *sandboxRun) start(cmd *cobra.Command) error {
+	if !s.enabled || s.dir != "" {
+		return nil
+	}
+	if why := sandboxRefusal(cmd); why != "" {
+		return fmt.Errorf("%s cannot run with --sandbox: %s", cmd.CommandPath(), why)
+	}
+	ctx := cmd.Context()
+	dir, err := os.MkdirTemp("", "track-sandbox-")
+	if err != nil {
+		return fmt.Errorf("create sandbox: %w", err)
+	}
+	s.dir = dir
+	cobra.OnFinalize(s.cleanup)
+
+	cfgPath, err := appconfig.ConfigPath()
+	if err != nil {
+		return err
+	}
+	if cfg, err := os.ReadFile(cfgPath); err == nil {
+		if err := os.WriteFile(filepath.Join(dir, "config.toml"), cfg, 0o600); err != nil {
+			return fmt.Errorf("copy config: %w", err)
+		}
+	}
+	// No hook is approved in the copy, so hooks cannot run shell commands
+	// during a preview.
+	allowPath, err := sqlite.HookApprovalsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, filepath.Base(allowPath)), nil, 0o600); err != nil {
+		return fmt.Errorf("create hook approvals: %w", err)
+	}
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return err
+	}
+	err = store.BackupTo(ctx, filepath.Join(dir, "track.db"))
+	store.Close()
+	if err != nil {
+		return err
+	}
+	s.oldHome, s.hadHome = os.LookupEnv("TRACK_HOME")
+	if err := os.Setenv("TRACK_HOME", dir); err != nil {
+		return err
+	}
+	return disarmSandbox(ctx)
+}
+
+// disarmSandbox keeps the copy from reaching outside it: automation is
+// frozen, and notify_cmd and digest_email are cleared.
+func disarmSandbox(ctx context.Context) error {
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	if _, err := store.FreezeAutomation(sqlite.WithActor(ctx, "sandbox"), "sandbox preview"); err != nil {
+		return err
+	}
+	cfg, err := appconfig.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.NotifyCmd == "" && cfg.DigestEmail == "" {
+		return nil
+	}
+	cfg.NotifyCmd = ""
+	cfg.DigestEmail = ""
+	return appconfig.Save(cfg)
+}
+
+// report prints what the command changed in the copy.
+func (s *sandboxRun) report(ctx context.Context, out io.Writer) error {
+	if s.dir == "" {
+		return nil
+	}
+	after, err := sandboxSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	s.restoreHome()
+	before, err := sandboxSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	writeSandboxDiff(out, before, after)
+	return nil
+}
+
+func (s *sandboxRun) restoreHome() {
+	if s.hadHome {
+		_ = os.Setenv("TRACK_HOME", s.oldHome)
+	} else {
+		_ = os.Unsetenv("TRACK_HOME")
+	}
+}
+
+// cleanup runs after the command even when it fails.
+func (s *sandboxRun) cleanup() {
+	if s.dir == "" {
+		return
+	}
+	s.restoreHome()
+	_ = os.RemoveAll(s.dir)
+	s.dir = ""
+}
+
+func sandboxSnapshot(ctx context.Context) ([]issue.Item, error) {
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	return store.ListIssues(ctx, sqlite.ListFilter{Sort: "id"})
+}
+
+// writeSandboxDiff lists created (+), changed (~), and removed (-) issues,
+// with the changed fields and a unified diff of changed bodies.
+func writeSandboxDiff(out io.Writer, beforeItems, afterItems []issue.Item) {
+	before := make(map[string]issue.Item, len(beforeItems))
+	for _, it := range beforeItems {
+		before[it.ID] = it
+	}
+	after := make(map[string]issue.Item, len(afterItems))
+	ids := make([]string, 0, len(afterItems))
+	for _, it := range afterItems {
+		after[it.ID] = it
+		ids = append(ids, it.ID)
+	}
+	for _, it := range beforeItems {
+		if _, ok := after[it.ID]; !ok {
+			ids = append(ids, it.ID)
+		}
+	}
+
+	var b strings.Builder
+	changed := 0
+	for _, id := range ids {
+		old, hadOld := before[id]
+		cur, hasCur := after[id]
+		switch {
+		case !hadOld:
+			fmt.Fprintf(&b, "+ %s %s\n", id, cur.Title)
+			writeSandboxFields(&b, issue.Item{}, cur)
+		case !hasCur:
+			fmt.Fprintf(&b, "- %s %s\n", id, old.Title)
+		default:
+			var fields strings.Builder
+			writeSandboxFields(&fields, old, cur)
+			if fields.Len() == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, "~ %s %s\n", id, old.Title)
+			b.WriteString(fields.String())
+		}
+		changed++
+	}
+	if changed == 0 {
+		fmt.Fprintln(out, "sandbox: no issues would change")
+		return
+	}
+	fmt.Fprintf(out, "sandbox: %d issues would change (nothing was saved)\n", changed)
+	fmt.Fprint(out, b.String())
+}
+
+func writeSandboxFields(w io.Writer, old, cur issue.Item) {
+	fields := []struct{ name, old, cur string }{
+		{"title", old.Title, cur.Title},
+		{"status", old.Status, cur.Status},
+		{"priority", old.Priority, cur.Priority},
+		{"assignee", old.Assignee, cur.Assignee},
+		{"due", old.Due, cur.Due},
+		{"labels", strings.Join(old.Labels, ","), strings.Join(cur.Labels, ",")},
+		{"estimate", old.Estimate, cur.Estimate},
+		{"next_action", old.NextAction, cur.NextAction},
+		{"external_id", old.ExternalID, cur.ExternalID},
+	}
+	for _, f := range fields {
+		if f.old != f.cur {
+			fmt.Fprintf(w, "    %s: %s -> %s\n", f.name, orDash(f.old), orDash(f.cur))
+		}
+	}
+	if old.Pinned != cur.Pinned {
+		fmt.Fprintf(w, "    pinned: %t -> %t\n", old.Pinned, cur.Pinned)
+	}
+	if old.Body != cur.Body {
+		fmt.Fprintln(w, "    body:")
+		for _, line := range strings.Split(strings.TrimRight(textdiff.Unified("before", "after", old.Body, cur.Body), "\n"), "\n") {
+			fmt.Fprintf(w, "      %s\n", line)
+		}
+	}
+}
+
+func orDash(v string) string {
+	if v == "" {
+		return "-"
+	}
+	return v
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/myuon/track/internal/hooks"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestSandboxLeavesDatabaseUntouched(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("TRACK_HOME", home)
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+	if out, err := run("new", "Fix login"); err != nil {
+		t.Fatalf("new error: %v\n%s", err, out)
+	}
+
+	out, err := run("--sandbox", "set", "TRK-1", "--status", "in_progress")
+	if err != nil {
+		t.Fatalf("sandbox set error: %v\n%s", err, out)
+	}
+	for _, want := range []string{"sandbox: 1 issues would change", "~ TRK-1 Fix login", "status: todo -> in_progress"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output missing %q:\n%s", want, out)
+		}
+	}
+	if got := os.Getenv("TRACK_HOME"); got != home {
+		t.Fatalf("TRACK_HOME = %q, want it restored to %q", got, home)
+	}
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	it, err := store.GetIssue(ctx, "TRK-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if it.Status != "todo" {
+		t.Fatalf("status = %q, sandbox run must not touch the database", it.Status)
+	}
+
+	if out, err := run("--sandbox", "list"); err != nil || !strings.Contains(out, "sandbox: no issues would change") {
+		t.Fatalf("read-only sandbox run = %q, %v", out, err)
+	}
+}
+
+func TestSandboxRunsNoHooks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("TRACK_HOME", home)
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+	marker := filepath.Join(t.TempDir(), "ran")
+	for _, args := range [][]string{
+		{"new", "Fix login"},
+		{"hook", "add", hooks.IssueUpdated, "--run", "touch " + marker},
+		{"set", "TRK-1", "--priority", "p1"},
+	} {
+		if out, err := run(args...); err != nil {
+			t.Fatalf("%v error: %v\n%s", args, err, out)
+		}
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Fatalf("the approved hook should run outside the sandbox: %v", err)
+	}
+	if err := os.Remove(marker); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := run("--sandbox", "set", "TRK-1", "--priority", "p0")
+	if err != nil || !strings.Contains(out, "priority: p1 -> p0") {
+		t.Fatalf("sandbox set = %v\n%s", err, out)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Fatalf("hook ran inside the sandbox (stat err %v)", err)
+	}
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if _, frozen, err := store.AutomationFrozen(ctx); err != nil || frozen {
+		t.Fatalf("the sandbox must not freeze the real database: frozen=%v, %v", frozen, err)
+	}
+}
+
+func TestSandboxRefusesCommandsThatLeaveTheMachine(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		http.Error(w, "unexpected request", http.StatusTeapot)
+	}))
+	t.Cleanup(srv.Close)
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+	if out, err := run("remote", "add", "work", srv.URL, "--token", "remote-token-0123456789"); err != nil {
+		t.Fatalf("remote add error: %v\n%s", err, out)
+	}
+
+	for _, args := range [][]string{
+		{"--sandbox", "new", "Remote task", "--remote", "work"},
+		{"--sandbox", "set", "TRK-1", "--remote", "work", "--status", "done"},
+		{"--sandbox", "push", "work"},
+		{"--sandbox", "dispatch", "TRK-1"},
+		{"--sandbox", "gh", "issue", "create", "TRK-1"},
+		{"--sandbox", "digest", "--email", "me@example.com"},
+	} {
+		if _, err := run(args...); err == nil || !strings.Contains(err.Error(), "cannot run with --sandbox") {
+			t.Fatalf("%v error = %v, want it refused", args, err)
+		}
+	}
+	if n := hits.Load(); n != 0 {
+		t.Fatalf("the remote got %d requests from the sandbox", n)
+	}
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/myuon/track/internal/hooks"
+	"github.com/myuon/track/internal/script"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+// scriptEvents are the issue events scripts can handle.
+var scriptEvents = []string{hooks.IssueCreated, hooks.IssueUpdated, hooks.IssueStatusChange, hooks.IssueCompleted}
+
+func newScriptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "script",
+		Short: "Manage Starlark scripts that run on issue events",
+		Long: `Scripts are Starlark files in $TRACK_HOME/scripts (see track script dir). A
+script handles an event by defining on_<event>(issue), e.g. on_created for
+issue.created or on_status_changed for issue.status_changed, and runs after
+the rules for that event and before the hooks. Scripts are re-read when they
+change, so a running track serve picks up edits.
+
+The issue argument has the fields id, title, status, priority, assignee,
+due, labels, next_action, estimate, body, and pinned, and the methods
+set(field=value, ...), add_label(l), remove_label(l), and comment(text),
+which appends a timestamped line to the Notes section. track.get(id) returns
+another issue the same way. Changes are attributed to script:<name>.
+
+  def on_created(issue):
+      if "bug" in issue.labels and not issue.assignee:
+          issue.set(assignee="triage", priority="p1")
+          issue.comment("routed to triage")`,
+	}
+	cmd.AddCommand(newScriptDirCmd())
+	cmd.AddCommand(newScriptListCmd())
+	cmd.AddCommand(newScriptRunCmd())
+	return cmd
+}
+
+func newScriptDirCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "dir",
+		Short: "Print the scripts directory, creating it if needed",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := script.Dir()
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), dir)
+			return nil
+		},
+	}
+}
+
+func newScriptListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List scripts and the events they handle",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scripts, err := script.List(scriptEvents)
+			if err != nil {
+				return err
+			}
+			for _, s := range scripts {
+				handles := strings.Join(s.Handlers, ",")
+				if s.Err != nil {
+					handles = "error: " + s.Err.Error()
+				} else if handles == "" {
+					handles = "-"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\n", s.Name, handles)
+			}
+			return nil
+		},
+	}
+}
+
+func newScriptRunCmd() *cobra.Command {
+	var event string
+	cmd := &cobra.Command{
+		Use:   "run <script> <id>",
+		Short: "Run a script's handler for an issue now",
+		Long:  "Call the handler for --event in one script, by name in the scripts directory or by path, with the given issue. Its changes are saved and publish events like any other change.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !slices.Contains(scriptEvents, event) {
+				return fmt.Errorf("unknown script event: %s", event)
+			}
+			path := args[0]
+			if !strings.ContainsRune(path, filepath.Separator) && !strings.ContainsRune(path, '/') {
+				dir, err := script.Dir()
+				if err != nil {
+					return err
+				}
+				path = filepath.Join(dir, strings.TrimSuffix(path, script.Ext)+script.Ext)
+			}
+
+			s := script.Load(path, scriptEvents)
+			if s.Err != nil {
+				return fmt.Errorf("script(%s): %w", s.Name, s.Err)
+			}
+			if !slices.Contains(s.Handlers, event) {
+				return fmt.Errorf("script %s has no %s handler", s.Name, script.Handler(event))
+			}
+
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if f, frozen, err := store.AutomationFrozen(ctx); err != nil {
+				return err
+			} else if frozen {
+				return f
+			}
+			if _, err := script.RunScript(ctx, store, path, event, normalizeIssueIDArg(args[1])); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&event, "event", hooks.IssueUpdated, "Event whose handler to call: "+strings.Join(scriptEvents, "|"))
+	return cmd
+}
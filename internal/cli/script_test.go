@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestScriptRunsOnCreateAndByHand(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	dir, err := run("script", "dir")
+	if err != nil {
+		t.Fatalf("script dir: %v", err)
+	}
+	src := `
+def on_created(issue):
+    if "bug" in issue.labels:
+        issue.set(priority="p1")
+
+def on_updated(issue):
+    issue.set(next_action="reproduce " + issue.title)
+`
+	if err := os.WriteFile(filepath.Join(strings.TrimSpace(dir), "bugs.star"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := run("script", "list")
+	if err != nil {
+		t.Fatalf("script list: %v", err)
+	}
+	if strings.TrimSpace(out) != "bugs\tissue.created,issue.updated" {
+		t.Fatalf("script list = %q", out)
+	}
+
+	out, err = run("new", "crash", "--label", "bug")
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	id := strings.TrimSpace(out)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	got, err := store.GetIssue(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Priority != "p1" {
+		t.Fatalf("priority = %q, on_created did not run", got.Priority)
+	}
+
+	if _, err := run("script", "run", "bugs", id, "--event", "issue.status_changed"); err == nil || !strings.Contains(err.Error(), "no on_status_changed handler") {
+		t.Fatalf("expected missing handler error, got %v", err)
+	}
+	if _, err := run("script", "run", "bugs", id); err != nil {
+		t.Fatalf("script run: %v", err)
+	}
+	got, err = store.GetIssue(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.NextAction != "reproduce crash" {
+		t.Fatalf("next_action = %q", got.NextAction)
+	}
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/myuon/track/internal/secret"
+	"github.com/spf13/cobra"
+)
+
+func newSecretCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secret",
+		Short: "Manage secrets referenced from hooks and notifications",
+		Long: `Keep tokens out of config.toml and the hooks table. A secret is stored in the
+OS keychain when one is available (macOS security, Linux secret-tool) and
+otherwise encrypted in $TRACK_HOME/secrets.json with a key in
+$TRACK_HOME/secret.key; set secret_store to file or keychain to choose.
+Hook commands, notify_cmd, and smtp_password refer to it as
+{{secret:<name>}}:
+
+  track secret set slack_webhook https://hooks.slack.com/services/...
+  track hook add issue.completed --run "curl -d text=done {{secret:slack_webhook}}"`,
+	}
+	cmd.AddCommand(newSecretSetCmd())
+	cmd.AddCommand(newSecretListCmd())
+	cmd.AddCommand(newSecretRemoveCmd())
+	return cmd
+}
+
+func newSecretSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <name> [value]",
+		Short: "Store a secret, reading the value from stdin when it is not given",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			value := ""
+			if len(args) == 2 {
+				value = args[1]
+			} else {
+				raw, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return err
+				}
+				value = strings.TrimRight(string(raw), "\r\n")
+			}
+			if value == "" {
+				return fmt.Errorf("secret value must not be empty")
+			}
+			store, err := secret.Set(args[0], value)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "ok (%s)\n", store)
+			return nil
+		},
+	}
+}
+
+func newSecretListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List secret names and where they are stored",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			secrets, err := secret.List()
+			if err != nil {
+				return err
+			}
+			for _, s := range secrets {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\n", s.Name, s.Store)
+			}
+			return nil
+		},
+	}
+}
+
+func newSecretRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <name>",
+		Short: "Remove a secret",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := secret.Delete(args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+}
@@ -0,0 +1,249 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/myuon/track/internal/api"
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/cron"
+	"github.com/myuon/track/internal/grpcapi"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+var grpcServe = func(addr string, srv *grpc.Server) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return srv.Serve(lis)
+}
+
+// shutdownTimeout bounds how long a headless server waits for in-flight
+// requests after SIGTERM.
+const shutdownTimeout = 10 * time.Second
+
+// apiServeUntil serves handler on addr until ctx is done, then shuts down
+// gracefully.
+var apiServeUntil = func(ctx context.Context, addr string, handler http.Handler) error {
+	srv := &http.Server{Addr: addr, Handler: handler}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func newServeCmd() *cobra.Command {
+	var port int
+	var grpcPort int
+	var noCron bool
+	var headless bool
+	var dataDir string
+	var insecureNoAuth bool
+	var checkpointEvery time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Start the API server together with the job scheduler",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			if dataDir != "" {
+				if err := os.Setenv("TRACK_HOME", dataDir); err != nil {
+					return err
+				}
+			}
+			if headless {
+				return runHeadless(ctx, cmd, port, grpcPort, noCron, insecureNoAuth, checkpointEvery)
+			}
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			startDBMaintenance(ctx, checkpointEvery, cmd.ErrOrStderr())
+
+			if !noCron {
+				sched := cron.NewScheduler(cronJobs()...)
+				go func() {
+					_ = sched.Loop(ctx, time.Minute, cmd.ErrOrStderr())
+				}()
+			}
+
+			if grpcPort > 0 {
+				srv := grpcapi.NewServer()
+				defer srv.Stop()
+				grpcAddr := fmt.Sprintf("127.0.0.1:%d", grpcPort)
+				fmt.Fprintf(cmd.OutOrStdout(), "gRPC running at %s\n", grpcAddr)
+				go func() {
+					if err := grpcServe(grpcAddr, srv); err != nil {
+						fmt.Fprintf(cmd.ErrOrStderr(), "grpc error: %v\n", err)
+					}
+				}()
+			}
+
+			addr := fmt.Sprintf("127.0.0.1:%d", port)
+			fmt.Fprintf(cmd.OutOrStdout(), "API running at http://%s\n", addr)
+			return apiListenAndServe(addr, api.NewHandler())
+		},
+	}
+	cmd.Flags().IntVar(&port, "port", 8788, "Port")
+	cmd.Flags().IntVar(&grpcPort, "grpc-port", 8789, "gRPC port (0 disables gRPC)")
+	cmd.Flags().BoolVar(&noCron, "no-cron", false, "Do not run scheduled jobs")
+	cmd.Flags().BoolVar(&headless, "headless", false, "Container mode: listen on all interfaces, read config from TRACK_* env only, log JSON, and shut down cleanly on SIGTERM")
+	cmd.Flags().StringVar(&dataDir, "data", "", "Data directory (overrides TRACK_HOME)")
+	cmd.Flags().BoolVar(&insecureNoAuth, "insecure-no-auth", false, "With --headless, start even though no API token exists, leaving the API open to anyone who can reach it")
+	addCheckpointFlag(cmd, &checkpointEvery)
+	return cmd
+}
+
+// addCheckpointFlag adds --checkpoint-interval to a long-running command.
+func addCheckpointFlag(cmd *cobra.Command, every *time.Duration) {
+	cmd.Flags().DurationVar(every, "checkpoint-interval", 5*time.Minute, "How often to check the database connection and truncate the WAL (0 disables)")
+}
+
+// startDBMaintenance runs sqlite.MaintainLoop in the background until ctx is
+// done, unless every is 0.
+func startDBMaintenance(ctx context.Context, every time.Duration, errOut io.Writer) {
+	if every <= 0 {
+		return
+	}
+	go func() {
+		_ = sqlite.MaintainLoop(ctx, every, errOut)
+	}()
+}
+
+// adminTokenEnv names the variable that hands a headless server its admin
+// token, stored as the "bootstrap" token on every start.
+const adminTokenEnv = "TRACK_ADMIN_TOKEN"
+
+// runHeadless runs serve for containers. Settings come only from the
+// environment, every log line is JSON on stderr, and SIGINT or SIGTERM
+// drains in-flight requests before exiting. It listens on all interfaces,
+// so it refuses to start while the API would accept requests without a
+// token, unless insecureNoAuth says to.
+func runHeadless(ctx context.Context, cmd *cobra.Command, port, grpcPort int, noCron, insecureNoAuth bool, checkpointEvery time.Duration) error {
+	if err := os.Setenv(appconfig.ConfigEnv, "env"); err != nil {
+		return err
+	}
+	logger := slog.New(slog.NewJSONHandler(cmd.ErrOrStderr(), nil))
+	slog.SetDefault(logger)
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if _, err := appconfig.Load(); err != nil {
+		return err
+	}
+	home, err := appconfig.HomeDir()
+	if err != nil {
+		return err
+	}
+	if err := requireHeadlessTokens(ctx, insecureNoAuth, logger); err != nil {
+		return err
+	}
+
+	if !noCron {
+		sched := cron.NewScheduler(cronJobs()...)
+		go func() {
+			_ = sched.Loop(ctx, time.Minute, logWriter{logger: logger})
+		}()
+	}
+	startDBMaintenance(ctx, checkpointEvery, logWriter{logger: logger})
+
+	if grpcPort > 0 {
+		srv := grpcapi.NewServer()
+		grpcAddr := fmt.Sprintf("0.0.0.0:%d", grpcPort)
+		logger.Info("grpc listening", "addr", grpcAddr)
+		go func() {
+			if err := grpcServe(grpcAddr, srv); err != nil {
+				logger.Error("grpc error", "error", err)
+			}
+		}()
+		defer stopGRPC(srv)
+	}
+
+	addr := fmt.Sprintf("0.0.0.0:%d", port)
+	logger.Info("api listening", "addr", addr, "data", home)
+	err = apiServeUntil(ctx, addr, api.NewHandler())
+	logger.Info("shutting down")
+	return err
+}
+
+// requireHeadlessTokens stores the token from adminTokenEnv, if set, and
+// fails when the API would still accept requests without a token.
+func requireHeadlessTokens(ctx context.Context, insecureNoAuth bool, logger *slog.Logger) error {
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	if secret := os.Getenv(adminTokenEnv); secret != "" {
+		if err := store.PutAPIToken(ctx, "bootstrap", sqlite.RoleAdmin, secret); err != nil {
+			return fmt.Errorf("%s: %w", adminTokenEnv, err)
+		}
+	}
+	tokens, err := store.ListAPITokens(ctx)
+	if err != nil || len(tokens) > 0 {
+		return err
+	}
+	if !insecureNoAuth {
+		return fmt.Errorf("headless serve would accept requests without a token: set %s, create one with track token add, or pass --insecure-no-auth", adminTokenEnv)
+	}
+	logger.Warn("no API tokens exist; the API accepts requests from anyone who can reach it")
+	return nil
+}
+
+// stopGRPC lets in-flight calls finish, cutting off streams that outlive
+// shutdownTimeout.
+func stopGRPC(srv *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(shutdownTimeout):
+		srv.Stop()
+	}
+}
+
+// logWriter turns plain error lines, such as the scheduler's, into log
+// records.
+type logWriter struct {
+	logger *slog.Logger
+}
+
+func (w logWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimSpace(string(p)), "\n") {
+		if line != "" {
+			w.logger.Error(line)
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+const (
+	sessionKindPlanning = "planning"
+	sessionKindDispatch = "dispatch"
+)
+
+type sessionTranscript struct {
+	file      *os.File
+	path      string
+	startedAt string
+}
+
+// openSessionTranscript creates $TRACK_HOME/sessions/<issue>/<timestamp>.log.
+// Call finish to close it and register the session in the store.
+func openSessionTranscript(issueID string) (*sessionTranscript, error) {
+	now := time.Now()
+	path, err := newSessionLogPath(issueID, now)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create transcript: %w", err)
+	}
+	return &sessionTranscript{file: f, path: path, startedAt: now.UTC().Format(time.RFC3339)}, nil
+}
+
+func (t *sessionTranscript) tee(stdout, stderr io.Writer) (io.Writer, io.Writer) {
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+	return io.MultiWriter(stdout, t.file), io.MultiWriter(stderr, t.file)
+}
+
+func (t *sessionTranscript) finish(ctx context.Context, store *sqlite.Store, issueID, kind, runner string, runErr error) (sqlite.Session, error) {
+	if err := t.file.Close(); err != nil {
+		return sqlite.Session{}, fmt.Errorf("close transcript: %w", err)
+	}
+	return store.AddSession(ctx, sqlite.Session{
+		IssueID:    issueID,
+		Kind:       kind,
+		Runner:     runner,
+		LogPath:    t.path,
+		ExitCode:   sessionExitCode(runErr),
+		StartedAt:  t.startedAt,
+		FinishedAt: time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// runAgentSession runs one agent session while teeing its output into a
+// transcript and records it in the store. The returned session is
+// zero-valued when the transcript could not be set up or recorded.
+func runAgentSession(ctx context.Context, store *sqlite.Store, issueID, kind string, req agentSessionRequest) (sqlite.Session, error) {
+	transcript, err := openSessionTranscript(issueID)
+	if err != nil {
+		return sqlite.Session{}, err
+	}
+	req.Stdout, req.Stderr = transcript.tee(req.Stdout, req.Stderr)
+	runErr := agentSessionRunner(ctx, req)
+
+	sess, err := transcript.finish(ctx, store, issueID, kind, req.Runner, runErr)
+	if err != nil {
+		return sqlite.Session{}, err
+	}
+	return sess, runErr
+}
+
+func newSessionLogPath(issueID string, now time.Time) (string, error) {
+	home, err := appconfig.HomeDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(home, "sessions", issueID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create sessions dir: %w", err)
+	}
+	return filepath.Join(dir, now.UTC().Format("20060102T150405.000000000Z")+".log"), nil
+}
+
+func sessionExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+func newSessionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "session",
+		Short: "Review recorded agent sessions",
+	}
+	cmd.AddCommand(newSessionListCmd())
+	cmd.AddCommand(newSessionShowCmd())
+	return cmd
+}
+
+func newSessionListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list [issue_id]",
+		Short: "List agent sessions",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			issueID := ""
+			if len(args) == 1 {
+				issueID = normalizeIssueIDArg(args[0])
+			}
+			sessions, err := store.ListSessions(ctx, issueID)
+			if err != nil {
+				return err
+			}
+			for _, sess := range sessions {
+				fmt.Fprintf(cmd.OutOrStdout(), "%d\t%s\t%s\t%s\t%d\t%s\t%s\n", sess.ID, sess.IssueID, sess.Kind, sess.Runner, sess.ExitCode, sess.StartedAt, sess.LogPath)
+			}
+			return nil
+		},
+	}
+}
+
+func newSessionShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <session_id>",
+		Short: "Show session metadata and transcript",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sessionID, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid session_id: %s", args[0])
+			}
+
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			sess, err := store.GetSession(ctx, sessionID)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "id: %d\n", sess.ID)
+			fmt.Fprintf(out, "issue: %s\n", sess.IssueID)
+			fmt.Fprintf(out, "kind: %s\n", sess.Kind)
+			fmt.Fprintf(out, "runner: %s\n", sess.Runner)
+			fmt.Fprintf(out, "exit_code: %d\n", sess.ExitCode)
+			fmt.Fprintf(out, "started_at: %s\n", sess.StartedAt)
+			fmt.Fprintf(out, "finished_at: %s\n", sess.FinishedAt)
+			fmt.Fprintf(out, "log: %s\n", sess.LogPath)
+
+			raw, err := os.ReadFile(sess.LogPath)
+			if err != nil {
+				if os.IsNotExist(err) {
+					fmt.Fprintln(out, "transcript missing")
+					return nil
+				}
+				return fmt.Errorf("read transcript: %w", err)
+			}
+			fmt.Fprintln(out, "---")
+			_, err = out.Write(raw)
+			return err
+		},
+	}
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func TestPlanningSessionRunnerRecordsTranscript(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "plan me", Status: issue.StatusTodo, Priority: "none"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	origRunner := agentSessionRunner
+	t.Cleanup(func() { agentSessionRunner = origRunner })
+	agentSessionRunner = func(_ context.Context, req agentSessionRequest) error {
+		if !strings.Contains(req.Prompt, "$track-dev-cycle plan "+it.ID) {
+			t.Fatalf("unexpected planning prompt: %q", req.Prompt)
+		}
+		fmt.Fprintln(req.Stdout, "planned")
+		return nil
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := planningSessionRunner(ctx, cmd, it.ID); err != nil {
+		t.Fatalf("planningSessionRunner() error: %v", err)
+	}
+
+	sessions, err := store.ListSessions(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("ListSessions() error: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Kind != sessionKindPlanning || sessions[0].Runner != "codex" {
+		t.Fatalf("unexpected sessions: %+v", sessions)
+	}
+}
+
+func TestSessionListAndShow(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "sessions", Status: issue.StatusReady, Priority: "none"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	origRunner := agentSessionRunner
+	t.Cleanup(func() { agentSessionRunner = origRunner })
+	agentSessionRunner = func(_ context.Context, req agentSessionRequest) error {
+		fmt.Fprintln(req.Stdout, "hello from agent")
+		return nil
+	}
+	sess, err := runAgentSession(ctx, store, it.ID, sessionKindRun, agentSessionRequest{Runner: "codex"})
+	if err != nil {
+		t.Fatalf("runAgentSession() error: %v", err)
+	}
+
+	listCmd := newSessionCmd()
+	var out bytes.Buffer
+	listCmd.SetOut(&out)
+	listCmd.SetErr(&out)
+	listCmd.SetArgs([]string{"list", "1"})
+	if err := listCmd.Execute(); err != nil {
+		t.Fatalf("session list error: %v", err)
+	}
+	want := fmt.Sprintf("%d\t%s\trun\tcodex\t0\t", sess.ID, it.ID)
+	if !strings.HasPrefix(out.String(), want) || !strings.Contains(out.String(), sess.LogPath) {
+		t.Fatalf("unexpected session list output: %q", out.String())
+	}
+
+	showCmd := newSessionCmd()
+	out.Reset()
+	showCmd.SetOut(&out)
+	showCmd.SetErr(&out)
+	showCmd.SetArgs([]string{"show", strconv.Itoa(sess.ID)})
+	if err := showCmd.Execute(); err != nil {
+		t.Fatalf("session show error: %v", err)
+	}
+	if !strings.Contains(out.String(), "issue: "+it.ID+"\n") || !strings.Contains(out.String(), "---\nhello from agent\n") {
+		t.Fatalf("unexpected session show output: %q", out.String())
+	}
+}
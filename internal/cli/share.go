@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/myuon/track/internal/share"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func newShareCmd() *cobra.Command {
+	var (
+		expires   time.Duration
+		baseURL   string
+		revokeAll bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "share <id>",
+		Short: "Print a read-only link to an issue",
+		Long:  "Print a signed, expiring link to a read-only page for one issue, served by track serve without an API token. --url is the address track serve is reachable at. --revoke-all invalidates every link shared so far.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if revokeAll {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if revokeAll {
+				if err := store.RotateShareSecret(ctx); err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "ok")
+				return nil
+			}
+			if expires <= 0 {
+				return fmt.Errorf("--expires must be positive")
+			}
+			it, err := store.GetIssue(ctx, normalizeIssueIDArg(args[0]))
+			if err != nil {
+				return err
+			}
+			secret, err := store.ShareSecret(ctx)
+			if err != nil {
+				return err
+			}
+			token := share.Sign(secret, it.ID, time.Now().Add(expires))
+			fmt.Fprintf(cmd.OutOrStdout(), "%s/share/%s\n", strings.TrimRight(baseURL, "/"), token)
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&expires, "expires", 72*time.Hour, "How long the link stays valid")
+	cmd.Flags().StringVar(&baseURL, "url", "http://127.0.0.1:8788", "Base URL of track serve")
+	cmd.Flags().BoolVar(&revokeAll, "revoke-all", false, "Invalidate every share link")
+	return cmd
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+const siteStyle = `body{font-family:system-ui,sans-serif;max-width:52rem;margin:2rem auto;padding:0 1rem;line-height:1.5}pre{white-space:pre-wrap;background:#f6f8fa;padding:1rem}li{margin:.2rem 0}`
+
+var siteIndexTpl = template.Must(template.New("index").Parse(`<!doctype html><html><head><meta charset="utf-8"><title>Roadmap</title><style>` + siteStyle + `</style></head><body><h1>Roadmap</h1>{{range .}}<h2>{{.Name}}</h2>{{if .Description}}<p>{{.Description}}</p>{{end}}{{range .Statuses}}<h3>{{.Status}} ({{len .Items}})</h3><ul>{{range .Items}}<li><a href="issues/{{.ID}}.html">{{.ID}}</a> {{.Title}}</li>{{end}}</ul>{{end}}{{else}}<p>No issues</p>{{end}}</body></html>
+`))
+
+var siteIssueTpl = template.Must(template.New("issue").Parse(`<!doctype html><html><head><meta charset="utf-8"><title>{{.ID}} {{.Title}}</title><style>` + siteStyle + `</style></head><body><p><a href="../index.html">Roadmap</a></p><h1>{{.ID}} {{.Title}}</h1><p>Status: {{.Status}} &middot; Priority: {{.Priority}}{{if .Due}} &middot; Due: {{.Due}}{{end}}</p>{{if .Labels}}<p>Labels: {{range $i, $l := .Labels}}{{if $i}}, {{end}}{{$l}}{{end}}</p>{{end}}{{if .Body}}<pre>{{.Body}}</pre>{{end}}</body></html>
+`))
+
+type siteGroup struct {
+	Name        string
+	Description string
+	Statuses    []siteStatus
+}
+
+type siteStatus struct {
+	Status string
+	Items  []issue.Item
+}
+
+// writeSiteExport writes a static site into dir: index.html groups the
+// issues by project and then status, and issues/<id>.html shows each one.
+func writeSiteExport(ctx context.Context, store *sqlite.Store, items []issue.Item, dir string) error {
+	if dir == "" {
+		return fmt.Errorf("--out is required for --format site")
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "issues"), 0o755); err != nil {
+		return fmt.Errorf("create site dir: %w", err)
+	}
+
+	projects, err := store.ListProjects(ctx)
+	if err != nil {
+		return err
+	}
+	statuses, err := store.ListStatuses(ctx)
+	if err != nil {
+		return err
+	}
+	byProject := map[string][]issue.Item{}
+	for _, it := range items {
+		key, err := store.GetIssueProject(ctx, it.ID)
+		if err != nil {
+			return err
+		}
+		byProject[key] = append(byProject[key], it)
+
+		if err := writeSitePage(filepath.Join(dir, "issues", it.ID+".html"), siteIssueTpl, it); err != nil {
+			return err
+		}
+	}
+
+	var groups []siteGroup
+	addGroup := func(name, description string, items []issue.Item) {
+		if len(items) == 0 {
+			return
+		}
+		g := siteGroup{Name: name, Description: description}
+		for _, st := range statuses {
+			var matched []issue.Item
+			for _, it := range items {
+				if it.Status == st {
+					matched = append(matched, it)
+				}
+			}
+			if len(matched) > 0 {
+				g.Statuses = append(g.Statuses, siteStatus{Status: st, Items: matched})
+			}
+		}
+		groups = append(groups, g)
+	}
+	for _, p := range projects {
+		addGroup(p.Name, p.Description, byProject[p.Key])
+	}
+	addGroup("No project", "", byProject[""])
+	return writeSitePage(filepath.Join(dir, "index.html"), siteIndexTpl, groups)
+}
+
+func writeSitePage(path string, tpl *template.Template, data any) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	if err := tpl.Execute(f, data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("render %s: %w", path, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/service"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func newSpecCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "spec",
+		Short: "Read, write, and validate the Spec section of an issue body",
+	}
+	cmd.AddCommand(newSpecShowCmd())
+	cmd.AddCommand(newSpecEditCmd())
+	cmd.AddCommand(newSpecCheckCmd())
+	return cmd
+}
+
+func newSpecShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <id>",
+		Short: "Print the Spec section",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			it, err := store.GetIssue(ctx, normalizeIssueIDArg(args[0]))
+			if err != nil {
+				return err
+			}
+			sec, ok := issue.FindSection(it.Body, issue.SectionSpec)
+			if !ok {
+				return fmt.Errorf("spec section not found: %s", it.ID)
+			}
+			if sec.Content != "" {
+				fmt.Fprintln(cmd.OutOrStdout(), sec.Content)
+			}
+			return nil
+		},
+	}
+}
+
+func newSpecEditCmd() *cobra.Command {
+	var body string
+
+	cmd := &cobra.Command{
+		Use:   "edit <id>",
+		Short: "Replace the Spec section (from --body or stdin)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			content := body
+			if !cmd.Flags().Changed("body") {
+				data, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return err
+				}
+				content = string(data)
+			}
+			if strings.TrimSpace(content) == "" {
+				return fmt.Errorf("spec content is required")
+			}
+
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if _, err := service.SetSection(ctx, store, normalizeIssueIDArg(args[0]), issue.SectionSpec, content, false); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&body, "body", "", "New Spec content (reads stdin when omitted)")
+	return cmd
+}
+
+func newSpecCheckCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check <id>",
+		Short: "Validate the Spec section against the spec_sections template",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := appconfig.Load()
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			it, err := store.GetIssue(ctx, normalizeIssueIDArg(args[0]))
+			if err != nil {
+				return err
+			}
+			sec, ok := issue.FindSection(it.Body, issue.SectionSpec)
+			if !ok {
+				return fmt.Errorf("spec section not found: %s", it.ID)
+			}
+			missing := issue.MissingSubsections(sec.Content, appconfig.SplitList(cfg.SpecSections))
+			if len(missing) > 0 {
+				for _, name := range missing {
+					fmt.Fprintf(cmd.OutOrStdout(), "missing: %s\n", name)
+				}
+				return fmt.Errorf("spec check failed for %s", it.ID)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestSpecEditShowAndCheck(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "spec me", Status: issue.StatusTodo, Priority: "none", Body: "context\n\n## Notes\nkeep me\n"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	edit := newSpecCmd()
+	edit.SetOut(&bytes.Buffer{})
+	edit.SetIn(strings.NewReader("### Goal\nship it\n\n### Approach\nsmall steps\n"))
+	edit.SetArgs([]string{"edit", it.ID})
+	if err := edit.Execute(); err != nil {
+		t.Fatalf("spec edit error: %v", err)
+	}
+
+	got, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if !strings.HasPrefix(got.Body, "context\n\n## Notes\nkeep me\n\n## Spec\n### Goal\nship it") {
+		t.Fatalf("unexpected body after edit: %q", got.Body)
+	}
+
+	show := newSpecCmd()
+	var showOut bytes.Buffer
+	show.SetOut(&showOut)
+	show.SetArgs([]string{"show", it.ID})
+	if err := show.Execute(); err != nil {
+		t.Fatalf("spec show error: %v", err)
+	}
+	if showOut.String() != "### Goal\nship it\n\n### Approach\nsmall steps\n" {
+		t.Fatalf("unexpected spec show output: %q", showOut.String())
+	}
+
+	check := newSpecCmd()
+	var checkOut bytes.Buffer
+	check.SetOut(&checkOut)
+	check.SetErr(&bytes.Buffer{})
+	check.SilenceUsage = true
+	check.SetArgs([]string{"check", it.ID})
+	if err := check.Execute(); err == nil {
+		t.Fatalf("spec check should fail without a test plan")
+	}
+	if checkOut.String() != "missing: Test plan\n" {
+		t.Fatalf("unexpected spec check output: %q", checkOut.String())
+	}
+
+	edit = newSpecCmd()
+	edit.SetOut(&bytes.Buffer{})
+	edit.SetArgs([]string{"edit", it.ID, "--body", "### Goal\nship it\n### Approach\nsmall steps\n### Test plan\ngo test ./...\n"})
+	if err := edit.Execute(); err != nil {
+		t.Fatalf("spec edit --body error: %v", err)
+	}
+
+	check = newSpecCmd()
+	checkOut.Reset()
+	check.SetOut(&checkOut)
+	check.SetArgs([]string{"check", it.ID})
+	if err := check.Execute(); err != nil {
+		t.Fatalf("spec check error: %v", err)
+	}
+	if checkOut.String() != "ok\n" {
+		t.Fatalf("unexpected spec check output: %q", checkOut.String())
+	}
+}
+
+func TestSpecShowMissingSection(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "no spec", Status: issue.StatusTodo, Priority: "none"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	cmd := newSpecCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"show", it.ID})
+	err = cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "spec section not found") {
+		t.Fatalf("expected missing spec error, got %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/myuon/track/internal/stats"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func newStatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Report on how work flows through track",
+	}
+	cmd.AddCommand(newStatsAgingCmd(), newStatsBlockedCmd())
+	return cmd
+}
+
+func newStatsAgingCmd() *cobra.Command {
+	var stall time.Duration
+	cmd := &cobra.Command{
+		Use:   "aging",
+		Short: "Show how long issues sit in each status",
+		Long: `Replay the activity log into every stay of an issue in a status and print a
+heatmap of how long those stays last, one row per status (done and archived
+are left out), counting stays still going on up to now. Statuses whose
+median stay is at least --stall are marked as stalling. A second table
+gives the median stay per label, so slow kinds of work stand out:
+
+  track stats aging --stall 72h`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if stall <= 0 {
+				return fmt.Errorf("--stall must be positive")
+			}
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			stints, err := stats.Stints(ctx, store, time.Now())
+			if err != nil {
+				return err
+			}
+			statuses, err := store.ListStatuses(ctx)
+			if err != nil {
+				return err
+			}
+			writeAging(cmd.OutOrStdout(), stats.BuildAging(stints, statuses, stall))
+			return nil
+		},
+	}
+	cmd.Flags().DurationVar(&stall, "stall", stats.DefaultStall, "Median stay from which a status counts as stalling")
+	return cmd
+}
+
+func writeAging(out io.Writer, ag stats.Aging) {
+	c := newCLIColor(out)
+	width := len("STATUS")
+	hottest := 0
+	for _, row := range ag.Statuses {
+		width = max(width, len(row.Status))
+		for _, n := range row.Buckets {
+			hottest = max(hottest, n)
+		}
+	}
+
+	header := []string{fmt.Sprintf("%-*s", width, "STATUS")}
+	for _, b := range stats.AgeBuckets {
+		header = append(header, fmt.Sprintf("%5s", b.Name))
+	}
+	header = append(header, fmt.Sprintf("%7s", "MEDIAN"), fmt.Sprintf("%5s", "OPEN"))
+	fmt.Fprintln(out, strings.Join(header, "  "))
+	for _, row := range ag.Statuses {
+		cells := []string{c.status(fmt.Sprintf("%-*s", width, row.Status))}
+		for _, n := range row.Buckets {
+			cells = append(cells, c.heat(fmt.Sprintf("%5d", n), n, hottest))
+		}
+		med := "-"
+		if row.Count > 0 {
+			med = stats.FormatAge(row.Median)
+		}
+		cells = append(cells, fmt.Sprintf("%7s", med), fmt.Sprintf("%5d", row.Open))
+		line := strings.Join(cells, "  ")
+		if row.Stalled {
+			line += "  " + c.wrap("1;31", "stalls")
+		}
+		fmt.Fprintln(out, line)
+	}
+
+	if len(ag.Labels) == 0 {
+		return
+	}
+	lwidth := len("LABEL")
+	for _, la := range ag.Labels {
+		lwidth = max(lwidth, len(la.Label))
+	}
+	header = []string{fmt.Sprintf("%-*s", lwidth, "LABEL")}
+	for _, row := range ag.Statuses {
+		header = append(header, fmt.Sprintf("%-12s", row.Status))
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, strings.TrimRight(strings.Join(header, "  "), " "))
+	for _, la := range ag.Labels {
+		cells := []string{fmt.Sprintf("%-*s", lwidth, la.Label)}
+		for _, cell := range la.Cells {
+			v := "-"
+			if cell.Count > 0 {
+				v = fmt.Sprintf("%s (%d)", stats.FormatAge(cell.Median), cell.Count)
+			}
+			cells = append(cells, fmt.Sprintf("%-12s", v))
+		}
+		fmt.Fprintln(out, strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+}
+
+func newStatsBlockedCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "blocked",
+		Short: "Show how long issues have been blocked",
+		Long: `Sum up, per issue, the time spent in the blocked status over every time it
+was blocked (see track set --status blocked --reason), counting a block
+still going on up to now. Issues that were blocked the longest come first;
+the reason is that of the latest block.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			times, err := stats.BlockedTimes(ctx, store, time.Now())
+			if err != nil {
+				return err
+			}
+			writeBlockedTimes(cmd.OutOrStdout(), times)
+			return nil
+		},
+	}
+}
+
+func writeBlockedTimes(out io.Writer, times []stats.BlockedTime) {
+	idWidth := len("ID")
+	for _, bt := range times {
+		idWidth = max(idWidth, len(bt.IssueID))
+	}
+	fmt.Fprintf(out, "%-*s  %7s  %6s  %-4s  %s\n", idWidth, "ID", "BLOCKED", "BLOCKS", "NOW", "REASON")
+	for _, bt := range times {
+		now := "no"
+		if bt.Open {
+			now = "yes"
+		}
+		line := fmt.Sprintf("%-*s  %7s  %6d  %-4s  %s", idWidth, bt.IssueID, stats.FormatAge(bt.Total), bt.Blocks, now, bt.Reason)
+		fmt.Fprintln(out, strings.TrimRight(line, " "))
+	}
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStatsAging(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+	for _, args := range [][]string{
+		{"new", "Fix login", "--label", "bug"},
+		{"new", "Write docs"},
+		{"set", "TRK-1", "--status", "in_progress"},
+	} {
+		if out, err := run(args...); err != nil {
+			t.Fatalf("%v error: %v\n%s", args, err, out)
+		}
+	}
+
+	out, err := run("stats", "aging")
+	if err != nil {
+		t.Fatalf("stats aging error: %v\n%s", err, out)
+	}
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	want := []string{
+		"STATUS         <1d   1-3d   3-7d   1-4w    >4w   MEDIAN   OPEN",
+		"todo             2      0      0      0      0       0m      1",
+		"ready            0      0      0      0      0        -      0",
+		"in_progress      1      0      0      0      0       0m      1",
+		"",
+		"LABEL  todo          ready         in_progress",
+		"bug    0m (1)        -             0m (1)",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("stats aging =\n%s\nwant\n%s", out, strings.Join(want, "\n"))
+	}
+
+	if _, err := run("stats", "aging", "--stall", "0s"); err == nil {
+		t.Fatalf("non-positive --stall should fail")
+	}
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/myuon/track/internal/automation"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+// statusGraphAny is the source node for rules that change the status
+// without a status condition.
+const statusGraphAny = "any status"
+
+// builtinTransitions are the moves track's own commands make.
+var builtinTransitions = []graphEdge{
+	{from: issue.StatusTodo, to: issue.StatusReady, label: "planning"},
+	{from: issue.StatusReady, to: issue.StatusInProgress, label: "dispatch, pomo"},
+	{from: issue.StatusInProgress, to: issue.StatusDone, label: "done, PR merged"},
+	{from: issue.StatusDone, to: issue.StatusArchived, label: "archive"},
+}
+
+func newStatusGraphCmd() *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Print the workflow as a DOT or Mermaid graph",
+		Long: `Print every status with its issue count, the transitions track's commands
+make (solid), and the status changes enabled rules make (dashed, labeled
+with the rule ID), for documenting or debugging a workflow:
+
+  track status graph --format mermaid
+  track status graph --format dot | dot -Tsvg > workflow.svg`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "dot" && format != "mermaid" {
+				return fmt.Errorf("unsupported format: %s", format)
+			}
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			g, err := statusGraph(ctx, store)
+			if err != nil {
+				return err
+			}
+			return writeGraph(cmd.OutOrStdout(), g, format)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "mermaid", "Output format: dot|mermaid")
+	return cmd
+}
+
+func statusGraph(ctx context.Context, store *sqlite.Store) (graph, error) {
+	statuses, err := store.ListStatuses(ctx)
+	if err != nil {
+		return graph{}, err
+	}
+	var g graph
+	for _, st := range statuses {
+		n, err := store.CountIssues(ctx, sqlite.ListFilter{Statuses: []string{st}})
+		if err != nil {
+			return graph{}, err
+		}
+		g.nodes = append(g.nodes, graphNode{id: st, label: fmt.Sprintf("%s (%d)", st, n), status: st})
+	}
+	g.edges = append(g.edges, builtinTransitions...)
+
+	rules, err := store.ListRules(ctx)
+	if err != nil {
+		return graph{}, err
+	}
+	anyUsed := false
+	for _, sr := range rules {
+		if !sr.Enabled {
+			continue
+		}
+		edges, err := ruleTransitions(sr)
+		if err != nil {
+			return graph{}, err
+		}
+		for _, e := range edges {
+			if e.from == statusGraphAny {
+				anyUsed = true
+			}
+			g.edges = append(g.edges, e)
+		}
+	}
+	if anyUsed {
+		g.nodes = append(g.nodes, graphNode{id: statusGraphAny, label: statusGraphAny})
+	}
+	return g, nil
+}
+
+// ruleTransitions returns the status changes a rule makes: one edge from
+// each status its conditions require (or from statusGraphAny) to the status
+// its actions set.
+func ruleTransitions(sr sqlite.Rule) ([]graphEdge, error) {
+	rule, err := automation.ParseRule(sr.Expr)
+	if err != nil {
+		return nil, fmt.Errorf("rule %d: %w", sr.ID, err)
+	}
+	var to string
+	for _, a := range rule.Actions {
+		if a.Field == "status" {
+			to = a.Value
+		}
+	}
+	if to == "" {
+		return nil, nil
+	}
+	var froms []string
+	for _, c := range rule.Conditions {
+		if c.Field == "status" && !c.Negate && !slices.Contains(froms, c.Value) {
+			froms = append(froms, c.Value)
+		}
+	}
+	if len(froms) == 0 {
+		froms = []string{statusGraphAny}
+	}
+	edges := make([]graphEdge, 0, len(froms))
+	for _, from := range froms {
+		edges = append(edges, graphEdge{from: from, to: to, label: fmt.Sprintf("rule %d", sr.ID), dashed: true})
+	}
+	return edges, nil
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStatusGraph(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+	for _, args := range [][]string{
+		{"new", "Fix login"},
+		{"new", "Write docs"},
+		{"status", "add", "on-hold"},
+		{"rule", "add", "when status=on-hold and label=unblocked then set status=ready"},
+		{"rule", "add", "when label=wontfix then set status=archived"},
+	} {
+		if out, err := run(args...); err != nil {
+			t.Fatalf("%v error: %v\n%s", args, err, out)
+		}
+	}
+
+	out, err := run("status", "graph", "--format", "mermaid")
+	if err != nil {
+		t.Fatalf("status graph error: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"flowchart LR\n",
+		`  todo["todo (2)"]`,
+		`  on_hold["on-hold (0)"]`,
+		`  todo -->|"planning"| ready`,
+		`  on_hold -.->|"rule 1"| ready`,
+		`  any_status -.->|"rule 2"| archived`,
+		"  classDef status_todo fill:#e5e7eb",
+		"  class on_hold status_on_hold",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("mermaid output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = run("status", "graph", "--format", "dot")
+	if err != nil {
+		t.Fatalf("status graph error: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"digraph track {",
+		`  "todo" [label="todo (2)", fillcolor="#e5e7eb"];`,
+		`  "on-hold" -> "ready" [label="rule 1", style=dashed];`,
+		`  "any status" [label="any status"];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("dot output missing %q:\n%s", want, out)
+		}
+	}
+
+	if _, err := run("status", "graph", "--format", "svg"); err == nil {
+		t.Fatalf("unknown format should fail")
+	}
+}
@@ -0,0 +1,424 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/myuon/track/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// Conflict strategies for push and pull.
+const (
+	strategyAsk    = "ask"
+	strategyLocal  = "local"
+	strategyRemote = "remote"
+)
+
+// queuedCreate is the payload of a create queued offline. The idempotency
+// key is fixed when queuing, so a push that is retried never creates the
+// issue twice.
+type queuedCreate struct {
+	Issue          client.NewIssue `json:"issue"`
+	IdempotencyKey string          `json:"idempotency_key"`
+}
+
+// fields maps JSON field names to their encoded values.
+type fields map[string]json.RawMessage
+
+func newPushCmd() *cobra.Command {
+	var strategy string
+	cmd := &cobra.Command{
+		Use:   "push [remote]",
+		Short: "Send changes queued while a remote was unreachable",
+		Long: "Send changes made with --remote while the remote was unreachable, oldest first. A field the remote changed " +
+			"since it was last seen is a conflict: --strategy ask (default) prompts per field, local keeps the queued " +
+			"value, and remote keeps the remote's. The remote may be omitted when only one is configured.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateStrategy(strategy); err != nil {
+				return err
+			}
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			remote, c, err := syncTarget(ctx, store, args)
+			if err != nil {
+				return err
+			}
+			ops, err := store.ListRemoteOps(ctx, remote)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			in := bufio.NewReader(cmd.InOrStdin())
+			failed := 0
+			for i, op := range ops {
+				id, err := pushOp(ctx, store, c, op, strategy, in, out)
+				if isOffline(err) {
+					return fmt.Errorf("remote %s is unreachable: %w; %d changes still queued", remote, err, len(ops)-i)
+				}
+				if err != nil {
+					failed++
+					fmt.Fprintf(out, "%s: %v\n", opLabel(op), err)
+					continue
+				}
+				if id != "" {
+					fmt.Fprintf(out, "%s: pushed\n", id)
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d changes failed and are still queued", failed, len(ops))
+			}
+			if len(ops) == 0 {
+				fmt.Fprintln(out, "nothing to push")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&strategy, "strategy", strategyAsk, "Conflict resolution (ask|local|remote)")
+	return cmd
+}
+
+func newPullCmd() *cobra.Command {
+	var strategy string
+	cmd := &cobra.Command{
+		Use:   "pull [remote]",
+		Short: "Refresh the offline copy of a remote's issues",
+		Long: "Fetch the remote's open issues, and any other issue already cached, so --remote reads work offline. " +
+			"Queued changes that now conflict with the remote are resolved as in push. The remote may be omitted when " +
+			"only one is configured.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateStrategy(strategy); err != nil {
+				return err
+			}
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			remote, c, err := syncTarget(ctx, store, args)
+			if err != nil {
+				return err
+			}
+			items, err := c.ListIssues(ctx, client.ListOptions{})
+			if err != nil {
+				return err
+			}
+			cached, err := store.ListRemoteIssues(ctx, remote)
+			if err != nil {
+				return err
+			}
+			for _, r := range cached {
+				if slices.ContainsFunc(items, func(it client.Issue) bool { return it.ID == r.IssueID }) {
+					continue
+				}
+				it, err := c.GetIssue(ctx, r.IssueID)
+				if client.IsNotFound(err) {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				items = append(items, it)
+			}
+
+			ops, err := store.ListRemoteOps(ctx, remote)
+			if err != nil {
+				return err
+			}
+			in := bufio.NewReader(cmd.InOrStdin())
+			for _, it := range items {
+				if err := cacheRemoteIssue(ctx, store, remote, it); err != nil {
+					return err
+				}
+				for _, op := range ops {
+					if op.Kind != sqlite.RemoteOpUpdate || op.IssueID != it.ID {
+						continue
+					}
+					if _, err := rebaseOp(ctx, store, op, it, strategy, in, cmd.OutOrStdout()); err != nil {
+						return err
+					}
+				}
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "pulled %d issues from %s\n", len(items), remote)
+			if ops, err := store.ListRemoteOps(ctx, remote); err == nil && len(ops) > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "%d changes queued; run track push\n", len(ops))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&strategy, "strategy", strategyAsk, "Conflict resolution (ask|local|remote)")
+	return cmd
+}
+
+func validateStrategy(v string) error {
+	switch v {
+	case strategyAsk, strategyLocal, strategyRemote:
+		return nil
+	default:
+		return fmt.Errorf("invalid strategy: %s (want ask|local|remote)", v)
+	}
+}
+
+// syncTarget picks the remote named in args, or the only configured one.
+func syncTarget(ctx context.Context, store *sqlite.Store, args []string) (string, *client.Client, error) {
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	} else {
+		remotes, err := store.ListRemotes(ctx)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(remotes) != 1 {
+			return "", nil, fmt.Errorf("specify a remote (%d configured)", len(remotes))
+		}
+		name = remotes[0].Name
+	}
+	c, err := remoteClient(ctx, name)
+	return name, c, err
+}
+
+// isOffline reports whether err means the remote could not be reached, as
+// opposed to the remote answering with an error.
+func isOffline(err error) bool {
+	var apiErr *client.APIError
+	return err != nil && !errors.As(err, &apiErr) && !errors.Is(err, context.Canceled)
+}
+
+func opLabel(op sqlite.RemoteOp) string {
+	if op.IssueID != "" {
+		return op.IssueID
+	}
+	return fmt.Sprintf("queued create #%d", op.ID)
+}
+
+// pushOp sends one queued operation and returns the issue it wrote, or ""
+// when conflict resolution left nothing to send.
+func pushOp(ctx context.Context, store *sqlite.Store, c *client.Client, op sqlite.RemoteOp, strategy string, in *bufio.Reader, out io.Writer) (string, error) {
+	if op.Kind == sqlite.RemoteOpCreate {
+		var q queuedCreate
+		if err := json.Unmarshal([]byte(op.Payload), &q); err != nil {
+			return "", fmt.Errorf("decode queued create: %w", err)
+		}
+		q.Issue.IdempotencyKey = q.IdempotencyKey
+		created, err := c.CreateIssue(ctx, q.Issue)
+		if err != nil {
+			return "", err
+		}
+		if err := cacheRemoteIssue(ctx, store, op.Remote, created); err != nil {
+			return "", err
+		}
+		return created.ID, store.DeleteRemoteOp(ctx, op.ID)
+	}
+
+	current, err := c.GetIssue(ctx, op.IssueID)
+	if err != nil {
+		return "", err
+	}
+	op, err = rebaseOp(ctx, store, op, current, strategy, in, out)
+	if err != nil || op.ID == 0 {
+		return "", err
+	}
+	var patch client.IssuePatch
+	if err := json.Unmarshal([]byte(op.Payload), &patch); err != nil {
+		return "", fmt.Errorf("decode queued update: %w", err)
+	}
+	updated, err := c.UpdateIssue(ctx, op.IssueID, patch)
+	if err != nil {
+		return "", err
+	}
+	if err := cacheRemoteIssue(ctx, store, op.Remote, updated); err != nil {
+		return "", err
+	}
+	return op.IssueID, store.DeleteRemoteOp(ctx, op.ID)
+}
+
+// rebaseOp resolves the conflicts between a queued update and the remote's
+// current copy. A field is in conflict when the remote changed it since the
+// update was queued, to something other than the queued value. Fields won
+// by the remote are dropped; fields kept locally get the remote value as
+// their new base, so they are not asked about again. An update left empty is
+// deleted, reported by a zero ID.
+func rebaseOp(ctx context.Context, store *sqlite.Store, op sqlite.RemoteOp, theirs client.Issue, strategy string, in *bufio.Reader, out io.Writer) (sqlite.RemoteOp, error) {
+	var patch, base fields
+	if err := json.Unmarshal([]byte(op.Payload), &patch); err != nil {
+		return op, fmt.Errorf("decode queued update: %w", err)
+	}
+	if op.Base != "" {
+		if err := json.Unmarshal([]byte(op.Base), &base); err != nil {
+			return op, fmt.Errorf("decode queued base: %w", err)
+		}
+	}
+	remote := issueFields(theirs)
+
+	keys := make([]string, 0, len(patch))
+	for k := range patch {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	changed := false
+	for _, key := range keys {
+		was, ok := base[key]
+		now := remote.value(key)
+		if !ok || string(now) == string(was) || string(now) == string(patch[key]) {
+			continue
+		}
+		keepLocal, err := chooseLocal(op.IssueID, key, patch[key], now, strategy, in, out)
+		if err != nil {
+			return op, err
+		}
+		if keepLocal {
+			base[key] = now
+		} else {
+			delete(patch, key)
+			delete(base, key)
+		}
+		changed = true
+	}
+	if !changed {
+		return op, nil
+	}
+	if len(patch) == 0 {
+		fmt.Fprintf(out, "%s: remote values kept; nothing left to push\n", op.IssueID)
+		if err := store.DeleteRemoteOp(ctx, op.ID); err != nil {
+			return op, err
+		}
+		op.ID = 0
+		return op, nil
+	}
+	payload, _ := json.Marshal(patch)
+	baseJSON, _ := json.Marshal(base)
+	op.Payload, op.Base = string(payload), string(baseJSON)
+	return op, store.UpdateRemoteOp(ctx, op)
+}
+
+func chooseLocal(id, key string, local, remote json.RawMessage, strategy string, in *bufio.Reader, out io.Writer) (bool, error) {
+	switch strategy {
+	case strategyLocal:
+		return true, nil
+	case strategyRemote:
+		return false, nil
+	}
+	for {
+		answer, err := readPromptLine(in, out, fmt.Sprintf("%s %s: local %s, remote %s. Keep [l]ocal or [r]emote? ", id, key, local, remote))
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "l", "local":
+			return true, nil
+		case "r", "remote":
+			return false, nil
+		}
+	}
+}
+
+func toFields(v any) fields {
+	data, _ := json.Marshal(v)
+	out := fields{}
+	_ = json.Unmarshal(data, &out)
+	return out
+}
+
+func issueFields(it client.Issue) fields {
+	return toFields(it)
+}
+
+// value returns a field, treating a missing or null field as empty so that
+// issues encoded with and without omitted fields compare equal.
+func (f fields) value(key string) json.RawMessage {
+	v, ok := f[key]
+	if !ok || string(v) == "null" {
+		if key == "labels" {
+			return json.RawMessage(`[]`)
+		}
+		return json.RawMessage(`""`)
+	}
+	return v
+}
+
+func cacheRemoteIssue(ctx context.Context, store *sqlite.Store, remote string, it client.Issue) error {
+	data, err := json.Marshal(it)
+	if err != nil {
+		return err
+	}
+	return store.PutRemoteIssue(ctx, remote, it.ID, string(data))
+}
+
+func cachedRemoteIssue(ctx context.Context, store *sqlite.Store, remote, id string) (client.Issue, bool, error) {
+	r, ok, err := store.GetRemoteIssue(ctx, remote, id)
+	if err != nil || !ok {
+		return client.Issue{}, false, err
+	}
+	var it client.Issue
+	if err := json.Unmarshal([]byte(r.Data), &it); err != nil {
+		return client.Issue{}, false, fmt.Errorf("decode cached %s: %w", id, err)
+	}
+	return it, true, nil
+}
+
+// queueRemoteUpdate records an update for push, noting the cached remote
+// values it replaces, and applies it to the cached copy so offline reads
+// show it.
+func queueRemoteUpdate(ctx context.Context, store *sqlite.Store, remote, id string, patch client.IssuePatch) error {
+	changes := toFields(patch)
+	var base fields
+	if cached, ok, err := cachedRemoteIssue(ctx, store, remote, id); err != nil {
+		return err
+	} else if ok {
+		current := issueFields(cached)
+		base = fields{}
+		for k, v := range changes {
+			base[k] = current.value(k)
+			current[k] = v
+		}
+		data, _ := json.Marshal(current)
+		if err := store.PutRemoteIssue(ctx, remote, id, string(data)); err != nil {
+			return err
+		}
+	}
+	op := sqlite.RemoteOp{Remote: remote, Kind: sqlite.RemoteOpUpdate, IssueID: id}
+	payload, _ := json.Marshal(changes)
+	op.Payload = string(payload)
+	if base != nil {
+		baseJSON, _ := json.Marshal(base)
+		op.Base = string(baseJSON)
+	}
+	_, err := store.QueueRemoteOp(ctx, op)
+	return err
+}
+
+func queueRemoteCreate(ctx context.Context, store *sqlite.Store, remote string, in client.NewIssue) error {
+	key := in.IdempotencyKey
+	if key == "" {
+		var b [16]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return err
+		}
+		key = hex.EncodeToString(b[:])
+	}
+	payload, err := json.Marshal(queuedCreate{Issue: in, IdempotencyKey: key})
+	if err != nil {
+		return err
+	}
+	_, err = store.QueueRemoteOp(ctx, sqlite.RemoteOp{Remote: remote, Kind: sqlite.RemoteOpCreate, Payload: string(payload)})
+	return err
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/myuon/track/internal/api"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func TestOfflineChangesArePushedWithConflictResolution(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	var offline atomic.Bool
+	handler := api.NewHandler()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if offline.Load() {
+			panic(http.ErrAbortHandler)
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	run := func(cmd *cobra.Command, stdin string, args ...string) (string, error) {
+		var out bytes.Buffer
+		cmd.SetIn(strings.NewReader(stdin))
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	if _, err := run(newRemoteCmd(), "", "add", "work", srv.URL); err != nil {
+		t.Fatalf("remote add: %v", err)
+	}
+	if out, err := run(newNewCmd(), "", "Shared task", "--remote", "work", "--priority", "p2"); err != nil || strings.TrimSpace(out) != "TRK-1" {
+		t.Fatalf("new --remote = %q, %v", out, err)
+	}
+	if _, err := run(newPullCmd(), ""); err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+
+	offline.Store(true)
+	if out, err := run(newSetCmd(), "", "TRK-1", "--remote", "work", "--status", "ready", "--priority", "p1"); err != nil || !strings.Contains(out, "queued for track push") {
+		t.Fatalf("offline set = %q, %v", out, err)
+	}
+	if out, err := run(newNewCmd(), "", "Offline task", "--remote", "work"); err != nil || !strings.HasPrefix(out, "queued") {
+		t.Fatalf("offline new = %q, %v", out, err)
+	}
+	if out, err := run(newListCmd(), "", "--remote", "work", "--status", "ready"); err != nil || !strings.Contains(out, "Shared task") {
+		t.Fatalf("offline list should show the cached, locally changed issue: %q, %v", out, err)
+	}
+
+	// A teammate changes the priority on the server in the meantime.
+	p3 := "p3"
+	if _, err := store.UpdateIssue(ctx, "TRK-1", sqlite.UpdateIssueInput{Priority: &p3}); err != nil {
+		t.Fatalf("remote update: %v", err)
+	}
+
+	offline.Store(false)
+	out, err := run(newPushCmd(), "r\n")
+	if err != nil {
+		t.Fatalf("push: %v; output=%s", err, out)
+	}
+	if !strings.Contains(out, `TRK-1 priority: local "p1", remote "p3"`) || !strings.Contains(out, "TRK-1: pushed") || !strings.Contains(out, "TRK-2: pushed") {
+		t.Fatalf("push output = %q", out)
+	}
+	it, err := store.GetIssue(ctx, "TRK-1")
+	if err != nil {
+		t.Fatalf("get TRK-1: %v", err)
+	}
+	if it.Status != "ready" || it.Priority != "p3" {
+		t.Fatalf("TRK-1 = %s/%s, want ready with the remote's p3", it.Status, it.Priority)
+	}
+	if it, err := store.GetIssue(ctx, "TRK-2"); err != nil || it.Title != "Offline task" {
+		t.Fatalf("TRK-2 = %+v, %v", it, err)
+	}
+	if out, err := run(newPushCmd(), ""); err != nil || strings.TrimSpace(out) != "nothing to push" {
+		t.Fatalf("second push = %q, %v", out, err)
+	}
+	if _, err := run(newPushCmd(), "", "--strategy", "mine"); err == nil {
+		t.Fatalf("expected invalid strategy error")
+	}
+}
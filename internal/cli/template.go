@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/myuon/track/internal/ghtemplate"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/myuon/track/pkg/track"
+	"github.com/spf13/cobra"
+)
+
+// ghTemplateDir is where GitHub looks for issue templates in a repository.
+const ghTemplateDir = ".github/ISSUE_TEMPLATE"
+
+func newTemplateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Manage issue templates",
+		Long:  "Manage the templates `track new --template NAME` starts issues from. A template prefixes the title and supplies labels, an assignee, and a body; `track template import-gh` keeps them in step with the repository's GitHub issue templates.",
+	}
+	cmd.AddCommand(newTemplateAddCmd())
+	cmd.AddCommand(newTemplateListCmd())
+	cmd.AddCommand(newTemplateShowCmd())
+	cmd.AddCommand(newTemplateRemoveCmd())
+	cmd.AddCommand(newTemplateImportGHCmd())
+	return cmd
+}
+
+func newTemplateAddCmd() *cobra.Command {
+	var t sqlite.Template
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Create or replace a template",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			t.Name = args[0]
+			if t.Body == "-" {
+				data, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return err
+				}
+				t.Body = strings.TrimRight(string(data), "\n")
+			}
+			if err := store.SaveTemplate(ctx, t); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&t.Description, "description", "", "What the template is for")
+	cmd.Flags().StringVar(&t.Title, "title", "", "Prefix for the titles of new issues (e.g. \"[Bug]: \")")
+	cmd.Flags().StringArrayVar(&t.Labels, "label", nil, "Label for new issues (repeatable)")
+	cmd.Flags().StringVar(&t.Assignee, "assignee", "", "Assignee for new issues")
+	_ = cmd.RegisterFlagCompletionFunc("assignee", completeAssignees)
+	cmd.Flags().StringVar(&t.Body, "body", "", "Body of new issues (- reads it from stdin)")
+	return cmd
+}
+
+func newTemplateListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List templates",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			templates, err := store.ListTemplates(ctx)
+			if err != nil {
+				return err
+			}
+			for _, t := range templates {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\n", t.Name, strings.Join(t.Labels, ","), t.Description)
+			}
+			return nil
+		},
+	}
+}
+
+func newTemplateShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a template",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			t, err := store.GetTemplate(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "name: %s\n", t.Name)
+			fmt.Fprintf(out, "description: %s\n", t.Description)
+			fmt.Fprintf(out, "title: %q\n", t.Title)
+			fmt.Fprintf(out, "labels: %s\n", strings.Join(t.Labels, ","))
+			fmt.Fprintf(out, "assignee: %s\n", t.Assignee)
+			if t.Source != "" {
+				fmt.Fprintf(out, "source: %s\n", t.Source)
+			}
+			fmt.Fprintf(out, "updated_at: %s\n", t.UpdatedAt)
+			if t.Body != "" {
+				fmt.Fprintf(out, "\n%s\n", t.Body)
+			}
+			return nil
+		},
+	}
+}
+
+func newTemplateRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <name>",
+		Short: "Remove a template",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if err := store.RemoveTemplate(ctx, args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+}
+
+func newTemplateImportGHCmd() *cobra.Command {
+	var (
+		dir    string
+		dryRun bool
+	)
+	cmd := &cobra.Command{
+		Use:   "import-gh",
+		Short: "Import the repository's GitHub issue templates",
+		Long: `Import the GitHub issue templates in .github/ISSUE_TEMPLATE, found from the
+root of the current git repository, as track templates named after their
+files. Markdown templates keep their front matter's title, labels, and
+assignees and use the rest as the body; issue forms become one "## Label"
+section per field, with dropdown and checkbox options as a checklist.
+Re-running the import replaces templates with the same name, so bug and
+feature forms stay the same in both systems.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			if dir == "" {
+				dir = defaultGHTemplateDir(ctx)
+			}
+			templates, err := readGHTemplates(dir)
+			if err != nil {
+				return err
+			}
+			if len(templates) == 0 {
+				return fmt.Errorf("no issue templates in %s", dir)
+			}
+			if dryRun {
+				for _, t := range templates {
+					fmt.Fprintf(cmd.OutOrStdout(), "would import %s\t%s\n", t.Name, t.Source)
+				}
+				return nil
+			}
+
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			for _, t := range templates {
+				if err := store.SaveTemplate(ctx, t); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "imported %s\t%s\n", t.Name, t.Source)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&dir, "dir", "", "Directory holding the templates (default: .github/ISSUE_TEMPLATE at the repository root)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the templates that would be imported without saving them")
+	return cmd
+}
+
+// defaultGHTemplateDir is .github/ISSUE_TEMPLATE at the root of the current
+// repository, or under the working directory outside one.
+func defaultGHTemplateDir(ctx context.Context) string {
+	out, err := exec.CommandContext(ctx, "git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return ghTemplateDir
+	}
+	return filepath.Join(strings.TrimSpace(string(out)), filepath.FromSlash(ghTemplateDir))
+}
+
+// readGHTemplates parses every issue template in dir into a track template
+// named after its file, in file name order.
+func readGHTemplates(dir string) ([]sqlite.Template, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read issue templates: %w", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() && ghtemplate.IsTemplateFile(e.Name()) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	out := make([]sqlite.Template, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		gt, err := ghtemplate.Parse(name, data)
+		if err != nil {
+			return nil, err
+		}
+		t := sqlite.Template{
+			Name:        strings.TrimSuffix(name, filepath.Ext(name)),
+			Description: gt.Description,
+			Title:       gt.Title,
+			Labels:      gt.Labels,
+			Body:        gt.Body,
+			Source:      filepath.ToSlash(filepath.Join(ghTemplateDir, name)),
+		}
+		if t.Description == "" {
+			t.Description = gt.Name
+		}
+		if len(gt.Assignees) > 0 {
+			t.Assignee = gt.Assignees[0]
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+func loadTemplate(ctx context.Context, name string) (sqlite.Template, error) {
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return sqlite.Template{}, err
+	}
+	defer store.Close()
+	return store.GetTemplate(ctx, name)
+}
+
+// applyTemplate starts a new issue from t: the title gets t's prefix unless
+// it already has it, t's labels come before the issue's own, and the
+// assignee and body are t's unless the issue sets them.
+func applyTemplate(in track.NewIssue, t sqlite.Template) track.NewIssue {
+	if !strings.HasPrefix(in.Title, t.Title) {
+		in.Title = t.Title + in.Title
+	}
+	labels := slices.Clone(t.Labels)
+	for _, l := range in.Labels {
+		if !slices.Contains(labels, l) {
+			labels = append(labels, l)
+		}
+	}
+	in.Labels = labels
+	if in.Assignee == "" {
+		in.Assignee = t.Assignee
+	}
+	if in.Body == "" {
+		in.Body = t.Body
+	}
+	return in
+}
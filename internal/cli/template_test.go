@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func TestTemplateImportGHAndNewFromTemplate(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"bug_report.yml": `name: Bug report
+description: File a bug report
+title: "[Bug]: "
+labels: ["bug"]
+body:
+  - type: textarea
+    attributes:
+      label: Steps to reproduce
+  - type: input
+    attributes:
+      label: Version
+`,
+		"feature.md": "---\nname: Feature request\nabout: Suggest an idea\nlabels: enhancement\n---\n## Problem\n",
+		"config.yml": "blank_issues_enabled: false\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	run := func(cmd *cobra.Command, args ...string) string {
+		t.Helper()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%v: %v\n%s", args, err, out.String())
+		}
+		return out.String()
+	}
+
+	if got := run(newTemplateCmd(), "import-gh", "--dir", dir, "--dry-run"); !strings.Contains(got, "would import bug_report") || strings.Contains(got, "config") {
+		t.Fatalf("dry run output:\n%s", got)
+	}
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	if list, err := store.ListTemplates(ctx); err != nil || len(list) != 0 {
+		t.Fatalf("dry run should not save templates: %v, %v", list, err)
+	}
+
+	got := run(newTemplateCmd(), "import-gh", "--dir", dir)
+	if !strings.Contains(got, "imported bug_report\t.github/ISSUE_TEMPLATE/bug_report.yml") || !strings.Contains(got, "imported feature") {
+		t.Fatalf("import output:\n%s", got)
+	}
+	// Re-importing replaces templates instead of failing.
+	run(newTemplateCmd(), "import-gh", "--dir", dir)
+	if got := run(newTemplateCmd(), "list"); got != "bug_report\tbug\tFile a bug report\nfeature\tenhancement\tSuggest an idea\n" {
+		t.Fatalf("template list:\n%s", got)
+	}
+
+	id := strings.TrimSpace(run(newNewCmd(), "Crash on start", "--template", "bug_report", "--label", "p1"))
+	it, err := store.GetIssue(ctx, id)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if it.Title != "[Bug]: Crash on start" || !slices.Equal(it.Labels, []string{"bug", "p1"}) {
+		t.Fatalf("issue from template: title=%q labels=%v", it.Title, it.Labels)
+	}
+	if it.Body != "## Steps to reproduce\n\n## Version" {
+		t.Fatalf("issue body = %q", it.Body)
+	}
+
+	cmd := newNewCmd()
+	cmd.SetArgs([]string{"x", "--template", "missing"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "template not found: missing") {
+		t.Fatalf("unknown template error = %v", err)
+	}
+}
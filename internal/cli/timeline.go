@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/myuon/track/internal/timeline"
+	"github.com/spf13/cobra"
+)
+
+func newTimelineCmd() *cobra.Command {
+	var (
+		project string
+		format  string
+	)
+	cmd := &cobra.Command{
+		Use:   "timeline",
+		Short: "Print a schedule of issues as a Mermaid Gantt chart or JSON",
+		Long: `Print the non-archived issues as a schedule, one section per project. An issue
+starts on the day it first moved to in_progress, or, before that, as many
+8-hour workdays before its due date as its estimate needs; it ends on the
+day it was done, on its due date, or when its estimate runs out. Issues that
+have neither started nor been given a due date are left out. The web UI
+shows the same schedule at /timeline.
+
+  track timeline --project cli --format mermaid-gantt`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "mermaid-gantt" && format != "json" {
+				return fmt.Errorf("unsupported format: %s", format)
+			}
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if project != "" {
+				if _, err := store.GetProject(ctx, project); err != nil {
+					return err
+				}
+			}
+			loc, err := appconfig.LoadLocation()
+			if err != nil {
+				return err
+			}
+			tl, err := timeline.Build(ctx, store, project, time.Now(), loc)
+			if err != nil {
+				return err
+			}
+			if format == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(tl)
+			}
+			writeMermaidGantt(cmd.OutOrStdout(), tl)
+			if tl.Unscheduled > 0 {
+				fmt.Fprintf(cmd.ErrOrStderr(), "%d issues have no start or due date (see track set --due)\n", tl.Unscheduled)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&project, "project", "", "Only schedule this project's issues")
+	cmd.Flags().StringVar(&format, "format", "mermaid-gantt", "Output format: mermaid-gantt|json")
+	return cmd
+}
+
+// writeMermaidGantt writes tl as a gantt chart. Done issues are marked done,
+// in_progress ones active, and overdue ones crit.
+func writeMermaidGantt(out io.Writer, tl timeline.Timeline) {
+	fmt.Fprintln(out, "gantt")
+	fmt.Fprintln(out, "  dateFormat YYYY-MM-DD")
+	for _, sec := range tl.Sections {
+		name := sec.Project
+		if name == "" {
+			name = "No project"
+		}
+		fmt.Fprintf(out, "  section %s\n", ganttText(name))
+		for _, e := range sec.Entries {
+			var tags []string
+			if e.Overdue {
+				tags = append(tags, "crit")
+			}
+			switch e.Status {
+			case issue.StatusDone:
+				tags = append(tags, "done")
+			case issue.StatusInProgress:
+				tags = append(tags, "active")
+			}
+			tags = append(tags, mermaidID(e.ID), e.Start, ganttEnd(e.End))
+			fmt.Fprintf(out, "  %s %s :%s\n", e.ID, ganttText(e.Title), strings.Join(tags, ", "))
+		}
+	}
+}
+
+// ganttEnd turns the last day of work into the exclusive end date Mermaid
+// expects.
+func ganttEnd(last string) string {
+	day, err := time.Parse(issue.DueDateLayout, last)
+	if err != nil {
+		return last
+	}
+	return day.AddDate(0, 0, 1).Format(issue.DueDateLayout)
+}
+
+// ganttText escapes what would end a gantt task's name early.
+func ganttText(s string) string {
+	return strings.NewReplacer(":", "#58;", ";", "#59;", "#", "#35;", "\n", " ").Replace(s)
+}
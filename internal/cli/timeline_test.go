@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/myuon/track/internal/timeline"
+)
+
+func TestWriteMermaidGantt(t *testing.T) {
+	tl := timeline.Timeline{Sections: []timeline.Section{
+		{Project: "cli", Entries: []timeline.Entry{
+			{ID: "TRK-1", Title: "Schema: v2", Status: "done", Start: "2030-01-01", End: "2030-01-02"},
+			{ID: "TRK-2", Title: "Command", Status: "in_progress", Start: "2030-01-03", End: "2030-01-03", Overdue: true},
+		}},
+		{Entries: []timeline.Entry{{ID: "TRK-3", Title: "Docs", Status: "todo", Start: "2030-01-04", End: "2030-01-05"}}},
+	}}
+	var out bytes.Buffer
+	writeMermaidGantt(&out, tl)
+	want := strings.Join([]string{
+		"gantt",
+		"  dateFormat YYYY-MM-DD",
+		"  section cli",
+		"  TRK-1 Schema#58; v2 :done, TRK_1, 2030-01-01, 2030-01-03",
+		"  TRK-2 Command :crit, active, TRK_2, 2030-01-03, 2030-01-04",
+		"  section No project",
+		"  TRK-3 Docs :TRK_3, 2030-01-04, 2030-01-06",
+		"",
+	}, "\n")
+	if out.String() != want {
+		t.Fatalf("gantt =\n%s\nwant\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func newTokenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Manage API tokens",
+		Long:  "Manage API tokens for `track api` and `track serve`. Once any token exists, every API request except /healthz and /openapi.json needs \"Authorization: Bearer <token>\".",
+	}
+	cmd.AddCommand(newTokenAddCmd())
+	cmd.AddCommand(newTokenListCmd())
+	cmd.AddCommand(newTokenRemoveCmd())
+	return cmd
+}
+
+func newTokenAddCmd() *cobra.Command {
+	var role string
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Create a token and print its secret",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			secret, err := store.CreateAPIToken(ctx, args[0], role)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), secret)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&role, "role", sqlite.RoleContributor, "Role ("+strings.Join(sqlite.Roles, "|")+")")
+	return cmd
+}
+
+func newTokenListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List tokens",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			tokens, err := store.ListAPITokens(ctx)
+			if err != nil {
+				return err
+			}
+			for _, t := range tokens {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\n", t.Name, t.Role, t.CreatedAt)
+			}
+			return nil
+		},
+	}
+}
+
+func newTokenRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <name>",
+		Short: "Revoke a token",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if err := store.RemoveAPIToken(ctx, args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ok")
+			return nil
+		},
+	}
+}
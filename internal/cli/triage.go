@@ -0,0 +1,355 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/service"
+	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// triageChoices caps how many labels, projects, and people a triage prompt
+// offers, one per digit key.
+const triageChoices = 9
+
+// errTriageQuit stops triage after the issues handled so far.
+var errTriageQuit = errors.New("triage stopped")
+
+func newTriageCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "triage",
+		Short: "Organize new issues one keystroke at a time",
+		Long: `Walk through the inbox, the open issues with priority none and no project,
+oldest first, and ask for each one's priority, labels, project, and
+assignee. Every answer is a single key: 0-3 pick a priority, digits pick
+one of the offered labels (toggled until enter), projects, or people, enter
+leaves a field as it is, s skips the issue, and q stops. What was picked is
+saved before moving on to the next issue.
+
+  track triage`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			store, err := sqlite.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			inbox, err := triageInbox(ctx, store)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if len(inbox) == 0 {
+				fmt.Fprintln(out, "inbox zero")
+				return nil
+			}
+			choices, err := loadTriageChoices(ctx, store)
+			if err != nil {
+				return err
+			}
+
+			keys := newKeyReader(cmd.InOrStdin())
+			triaged, skipped := 0, 0
+			for i, it := range inbox {
+				fmt.Fprintf(out, "\n[%d/%d] %s %s\n", i+1, len(inbox), it.ID, it.Title)
+				u, err := askTriage(keys, out, it, choices)
+				if err == errTriageQuit {
+					break
+				}
+				if err != nil {
+					return err
+				}
+				if u == nil {
+					fmt.Fprintf(out, "%s skipped\n", it.ID)
+					skipped++
+					continue
+				}
+				if _, err := service.UpdateIssue(ctx, store, it.ID, *u); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "%s %s\n", it.ID, formatTriage(*u))
+				triaged++
+			}
+
+			left, err := triageInbox(ctx, store)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "\ntriaged %d, skipped %d, %d left in the inbox\n", triaged, skipped, len(left))
+			if len(left) == 0 {
+				fmt.Fprintln(out, "inbox zero")
+			}
+			return nil
+		},
+	}
+}
+
+// triageInbox lists the open issues that have neither a priority nor a
+// project, oldest first.
+func triageInbox(ctx context.Context, store *sqlite.Store) ([]issue.Item, error) {
+	return store.ListIssues(ctx, sqlite.ListFilter{
+		Priorities:      []string{"none"},
+		NoProject:       true,
+		ExcludeDone:     true,
+		ExcludeArchived: true,
+		Sort:            "+created,id",
+	})
+}
+
+// triageOptions are the values a triage prompt offers for each field.
+type triageOptions struct {
+	labels, projects, people []string
+}
+
+// loadTriageChoices offers the most used labels, and the projects and
+// people in the order they are listed elsewhere.
+func loadTriageChoices(ctx context.Context, store *sqlite.Store) (triageOptions, error) {
+	var o triageOptions
+	items, err := store.ListIssues(ctx, sqlite.ListFilter{Sort: "id"})
+	if err != nil {
+		return o, err
+	}
+	counts := map[string]int{}
+	for _, it := range items {
+		for _, l := range it.Labels {
+			counts[l]++
+		}
+	}
+	for l := range counts {
+		o.labels = append(o.labels, l)
+	}
+	sort.Slice(o.labels, func(i, j int) bool {
+		if counts[o.labels[i]] != counts[o.labels[j]] {
+			return counts[o.labels[i]] > counts[o.labels[j]]
+		}
+		return o.labels[i] < o.labels[j]
+	})
+	projects, err := store.ListProjects(ctx)
+	if err != nil {
+		return o, err
+	}
+	for _, p := range projects {
+		o.projects = append(o.projects, p.Key)
+	}
+	people, err := store.ListPeople(ctx)
+	if err != nil {
+		return o, err
+	}
+	for _, p := range people {
+		o.people = append(o.people, p.Name)
+	}
+	o.labels = o.labels[:min(len(o.labels), triageChoices)]
+	o.projects = o.projects[:min(len(o.projects), triageChoices)]
+	o.people = o.people[:min(len(o.people), triageChoices)]
+	return o, nil
+}
+
+// askTriage asks for each field of it in turn. Labels it already has start
+// out picked. It returns nil when the issue is skipped or nothing changed,
+// and errTriageQuit when triage should stop.
+func askTriage(keys *keyReader, out io.Writer, it issue.Item, o triageOptions) (*service.Update, error) {
+	var u service.Update
+	for {
+		k, err := keys.key(out, "priority 0-3, enter keep none, s skip, q quit: ")
+		if err != nil {
+			return nil, err
+		}
+		if k >= '0' && k <= '3' {
+			p := "p" + string(k)
+			u.Priority = &p
+			break
+		}
+		if done, err := triageControl(k); done || err != nil {
+			return nil, err
+		}
+		if k == '\n' {
+			break
+		}
+	}
+
+	if len(o.labels) > 0 {
+		picked := slices.Clone(it.Labels)
+		for {
+			k, err := keys.key(out, fmt.Sprintf("labels %s%s, enter done: ", numbered(o.labels), pickedSuffix(picked)))
+			if err != nil {
+				return nil, err
+			}
+			if k == '\n' {
+				break
+			}
+			if done, err := triageControl(k); done || err != nil {
+				return nil, err
+			}
+			if l, ok := choice(o.labels, k); ok {
+				if i := slices.Index(picked, l); i >= 0 {
+					picked = slices.Delete(picked, i, i+1)
+				} else {
+					picked = append(picked, l)
+				}
+			}
+		}
+		if !slices.Equal(picked, it.Labels) {
+			u.Labels = &picked
+		}
+	}
+
+	for _, field := range []struct {
+		name    string
+		options []string
+		set     func(string)
+	}{
+		{"project", o.projects, func(v string) { u.Project = &v }},
+		{"assignee", o.people, func(v string) { u.Assignee = &v }},
+	} {
+		if len(field.options) == 0 {
+			continue
+		}
+		for {
+			k, err := keys.key(out, fmt.Sprintf("%s %s, enter none: ", field.name, numbered(field.options)))
+			if err != nil {
+				return nil, err
+			}
+			if k == '\n' {
+				break
+			}
+			if done, err := triageControl(k); done || err != nil {
+				return nil, err
+			}
+			if v, ok := choice(field.options, k); ok {
+				field.set(v)
+				break
+			}
+		}
+	}
+
+	if u.Priority == nil && u.Labels == nil && u.Project == nil && u.Assignee == nil {
+		return nil, nil
+	}
+	return &u, nil
+}
+
+// triageControl handles the keys every prompt accepts: s skips the issue and
+// q stops triage.
+func triageControl(k rune) (bool, error) {
+	switch k {
+	case 's':
+		return true, nil
+	case 'q':
+		return true, errTriageQuit
+	}
+	return false, nil
+}
+
+func numbered(options []string) string {
+	parts := make([]string, len(options))
+	for i, o := range options {
+		parts[i] = fmt.Sprintf("%d) %s", i+1, o)
+	}
+	return strings.Join(parts, " ")
+}
+
+func pickedSuffix(picked []string) string {
+	if len(picked) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(picked, ",") + "]"
+}
+
+func choice(options []string, k rune) (string, bool) {
+	i := int(k - '1')
+	if k < '1' || i >= len(options) {
+		return "", false
+	}
+	return options[i], true
+}
+
+func formatTriage(u service.Update) string {
+	var parts []string
+	if u.Priority != nil {
+		parts = append(parts, "priority="+*u.Priority)
+	}
+	if u.Labels != nil {
+		parts = append(parts, "labels="+strings.Join(*u.Labels, ","))
+	}
+	if u.Project != nil {
+		parts = append(parts, "project="+*u.Project)
+	}
+	if u.Assignee != nil {
+		parts = append(parts, "assignee="+*u.Assignee)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// keyReader reads single keystrokes from a terminal, switching it to raw
+// mode only while it waits for one. Other input is read a line at a time,
+// each line standing for the key it starts with, so answers can be piped in.
+type keyReader struct {
+	file  *os.File
+	lines *bufio.Reader
+}
+
+func newKeyReader(in io.Reader) *keyReader {
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return &keyReader{file: f}
+	}
+	return &keyReader{lines: bufio.NewReader(in)}
+}
+
+// key prints prompt and returns the key pressed, lowercased, with enter as
+// '\n'. Ctrl-C and Ctrl-D read as q.
+func (k *keyReader) key(out io.Writer, prompt string) (rune, error) {
+	if k.lines != nil {
+		line, err := readPromptLine(k.lines, out, prompt)
+		if err != nil {
+			return 0, err
+		}
+		r, _ := utf8.DecodeRuneInString(strings.TrimSpace(line))
+		if r == utf8.RuneError {
+			return '\n', nil
+		}
+		return normalizeKey(r), nil
+	}
+
+	fmt.Fprint(out, prompt)
+	state, err := term.MakeRaw(int(k.file.Fd()))
+	if err != nil {
+		return 0, err
+	}
+	buf := make([]byte, utf8.UTFMax)
+	n, err := k.file.Read(buf)
+	_ = term.Restore(int(k.file.Fd()), state)
+	if err != nil {
+		return 0, err
+	}
+	r, _ := utf8.DecodeRune(buf[:n])
+	r = normalizeKey(r)
+	if r != '\n' {
+		fmt.Fprint(out, string(r))
+	}
+	fmt.Fprintln(out)
+	return r, nil
+}
+
+func normalizeKey(r rune) rune {
+	switch r {
+	case '\r':
+		return '\n'
+	case 3, 4:
+		return 'q'
+	}
+	return unicode.ToLower(r)
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestTriage(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	run := func(in string, args ...string) string {
+		t.Helper()
+		cmd := newRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetIn(strings.NewReader(in))
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%v: %v\n%s", args, err, out.String())
+		}
+		return out.String()
+	}
+
+	run("", "project", "add", "cli", "--name", "CLI")
+	run("", "people", "add", "alice")
+	run("", "new", "prioritized", "--priority", "p2", "--label", "bug")
+	run("", "new", "filed", "--project", "cli")
+	first := strings.TrimSpace(run("", "new", "crash on start"))
+	second := strings.TrimSpace(run("", "new", "typo"))
+	third := strings.TrimSpace(run("", "new", "later"))
+
+	// first: p1, label bug, project cli, assignee alice; second: skipped;
+	// then triage stops before third.
+	out := run("1\n1\n\n1\n1\ns\nq\n", "triage")
+	if !strings.Contains(out, "[1/3] "+first+" crash on start") || !strings.Contains(out, "labels 1) bug, enter done: ") {
+		t.Fatalf("triage prompts:\n%s", out)
+	}
+	if !strings.Contains(out, first+" priority=p1, labels=bug, project=cli, assignee=alice") {
+		t.Fatalf("triage result:\n%s", out)
+	}
+	if !strings.Contains(out, second+" skipped") || strings.Contains(out, third+" priority=") {
+		t.Fatalf("triage skip/quit:\n%s", out)
+	}
+	if !strings.Contains(out, "triaged 1, skipped 1, 2 left in the inbox") {
+		t.Fatalf("triage summary:\n%s", out)
+	}
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	defer store.Close()
+	it, err := store.GetIssue(ctx, first)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	project, err := store.GetIssueProject(ctx, first)
+	if err != nil {
+		t.Fatalf("GetIssueProject() error: %v", err)
+	}
+	if it.Priority != "p1" || !slices.Equal(it.Labels, []string{"bug"}) || it.Assignee != "alice" || project != "cli" {
+		t.Fatalf("triaged issue = %+v, project %q", it, project)
+	}
+
+	if out := run("2\n\n\n\n3\n\n\n\n", "triage"); !strings.HasSuffix(out, "triaged 2, skipped 0, 0 left in the inbox\ninbox zero\n") {
+		t.Fatalf("second triage:\n%s", out)
+	}
+	if out := run("", "triage"); out != "inbox zero\n" {
+		t.Fatalf("empty inbox = %q", out)
+	}
+}
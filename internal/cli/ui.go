@@ -2,22 +2,29 @@ package cli
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"os/exec"
 	"runtime"
+	"strconv"
 
 	appconfig "github.com/myuon/track/internal/config"
 	"github.com/myuon/track/internal/ui"
 	"github.com/spf13/cobra"
 )
 
+var uiListenAndServe = http.ListenAndServe
+
 func newUICmd() *cobra.Command {
+	var host string
 	var port int
 	var open bool
+	var allowHosts []string
 
 	cmd := &cobra.Command{
 		Use:   "ui",
 		Short: "Start local web UI",
+		Long:  "Start the web UI. It has no login, so it listens on 127.0.0.1 unless --host names another address, and it refuses changes posted from pages on other origins. Requests must address it by an IP address, localhost, the --host name, or an --allow-host name.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg, err := appconfig.Load()
 			if err != nil {
@@ -27,16 +34,19 @@ func newUICmd() *cobra.Command {
 				port = cfg.UIPort
 			}
 
-			url := fmt.Sprintf("http://127.0.0.1:%d", port)
+			addr := net.JoinHostPort(host, strconv.Itoa(port))
+			url := "http://" + addr
 			if open || cfg.OpenBrowser {
 				_ = openBrowser(url)
 			}
 			fmt.Fprintf(cmd.OutOrStdout(), "UI running at %s\n", url)
-			return http.ListenAndServe(fmt.Sprintf(":%d", port), ui.NewHandler())
+			return uiListenAndServe(addr, ui.NewHandler(append(allowHosts, host)...))
 		},
 	}
+	cmd.Flags().StringVar(&host, "host", "127.0.0.1", "Address to listen on")
 	cmd.Flags().IntVar(&port, "port", 0, "Port (default from config or 8787)")
 	cmd.Flags().BoolVar(&open, "open", false, "Open browser")
+	cmd.Flags().StringSliceVar(&allowHosts, "allow-host", nil, "Another host name the UI may be reached by (repeatable)")
 	return cmd
 }
 
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+)
+
+func TestUICmdListensOnLoopbackByDefault(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	orig := uiListenAndServe
+	t.Cleanup(func() {
+		uiListenAndServe = orig
+	})
+
+	var gotAddr string
+	uiListenAndServe = func(addr string, handler http.Handler) error {
+		gotAddr = addr
+		return nil
+	}
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--port", "18887"}, "127.0.0.1:18887"},
+		{[]string{"--port", "18887", "--host", "0.0.0.0"}, "0.0.0.0:18887"},
+		{[]string{"--port", "18887", "--host", "::1"}, "[::1]:18887"},
+	} {
+		cmd := newUICmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(tc.args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute(%v) error: %v", tc.args, err)
+		}
+		if gotAddr != tc.want {
+			t.Fatalf("listen addr for %v = %q, want %q", tc.args, gotAddr, tc.want)
+		}
+		if want := "UI running at http://" + tc.want + "\n"; out.String() != want {
+			t.Fatalf("output = %q, want %q", out.String(), want)
+		}
+	}
+}
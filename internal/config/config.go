@@ -4,28 +4,118 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
 
 const (
-	defaultUIPort = 8787
+	defaultUIPort       = 8787
+	defaultSpecSections = "Goal,Approach,Test plan"
+	defaultTimeFormat   = "iso"
+	defaultSMTPPort     = 587
+	defaultGHRetries    = 3
+	defaultGHBackoff    = "1s"
 )
 
 type Config struct {
-	UIPort      int    `toml:"ui_port"`
-	OpenBrowser bool   `toml:"open_browser"`
-	GHRepo      string `toml:"gh_repo"`
-	SyncAuto    bool   `toml:"sync_auto"`
+	UIPort             int    `toml:"ui_port"`
+	OpenBrowser        bool   `toml:"open_browser"`
+	GHRepo             string `toml:"gh_repo"`
+	SyncAuto           bool   `toml:"sync_auto"`
+	NotifyCmd          string `toml:"notify_cmd"`
+	SpecSections       string `toml:"spec_sections"`
+	NotifyHookFailures bool   `toml:"notify_hook_failures"`
+	Timezone           string `toml:"timezone"`
+	TimeFormat         string `toml:"time_format"`
+	UserName           string `toml:"user_name"`
+	UserEmail          string `toml:"user_email"`
+	RateLimit          int    `toml:"rate_limit"`
+	SMTPHost           string `toml:"smtp_host"`
+	SMTPPort           int    `toml:"smtp_port"`
+	SMTPUsername       string `toml:"smtp_username"`
+	SMTPPassword       string `toml:"smtp_password"`
+	SMTPFrom           string `toml:"smtp_from"`
+	DigestEmail        string `toml:"digest_email"`
+	StrictAssignees    bool   `toml:"strict_assignees"`
+	GHWebhookSecret    string `toml:"gh_webhook_secret"`
+	GHRetries          int    `toml:"gh_retries"`
+	GHRetryBackoff     string `toml:"gh_retry_backoff"`
+	// SecretStore is where `track secret set` keeps values: "keychain",
+	// "file", or "auto" (the OS keychain when one is available).
+	SecretStore string `toml:"secret_store"`
+	// PriorityInheritance is how open issues take on the priority of the
+	// open issues that depend on them: "show" (the default) displays the
+	// effective priority, "raise" also raises their own priority when a
+	// dependent's is raised, and "off" does neither.
+	PriorityInheritance string `toml:"priority_inheritance"`
+	// ProjectPaths maps repository subdirectories, relative to the repository
+	// root, to project keys. It is the [project_paths] table of config.toml
+	// and is set with `track config set project_paths.<dir> <project>`.
+	ProjectPaths map[string]string `toml:"project_paths"`
+	// List holds the defaults of `track list`, the [list] table of
+	// config.toml, set with `track config set list.<key> <value>`.
+	List ListDefaults `toml:"list"`
+	// Board holds the web UI board's preferences, the [board] table of
+	// config.toml. The board saves them when they are changed there, and
+	// `track config set board.<key> <value>` sets them too.
+	Board BoardPrefs `toml:"board"`
+}
+
+// Values of board.swimlanes.
+const (
+	SwimlanesNone     = "none"
+	SwimlanesProject  = "project"
+	SwimlanesAssignee = "assignee"
+	SwimlanesPriority = "priority"
+)
+
+// BoardPrefs lay out the web UI board.
+type BoardPrefs struct {
+	// Swimlanes groups the board's rows: "none" (the default), "project",
+	// "assignee", or "priority".
+	Swimlanes string `toml:"swimlanes"`
+	// WIPLimit flags lanes with more in_progress issues than this; 0 means
+	// no limit.
+	WIPLimit int `toml:"wip_limit"`
+}
+
+// ListDefaults fill in `track list` flags that are not given. Statuses are
+// not checked here, since custom statuses live in the database.
+type ListDefaults struct {
+	// DefaultStatus is a comma-separated status filter such as "ready".
+	DefaultStatus string `toml:"default_status"`
+	// DefaultSort is a sort such as "priority,-updated".
+	DefaultSort string `toml:"default_sort"`
+	// HideSnoozed leaves issues in the custom "snoozed" status out of lists
+	// that do not filter by status.
+	HideSnoozed bool `toml:"hide_snoozed"`
 }
 
 func Default() Config {
 	return Config{
-		UIPort: defaultUIPort,
+		UIPort:         defaultUIPort,
+		SpecSections:   defaultSpecSections,
+		TimeFormat:     defaultTimeFormat,
+		SMTPPort:       defaultSMTPPort,
+		GHRetries:      defaultGHRetries,
+		GHRetryBackoff: defaultGHBackoff,
 	}
 }
 
+// ConfigEnv selects where settings come from. When it is "env", Load reads
+// only TRACK_<KEY> variables (TRACK_GH_REPO, TRACK_RATE_LIMIT, ...) on top of
+// the defaults and never reads or writes config.toml.
+const ConfigEnv = "TRACK_CONFIG"
+
+// EnvOnly reports whether settings come from the environment alone.
+func EnvOnly() bool {
+	return os.Getenv(ConfigEnv) == "env"
+}
+
 func HomeDir() (string, error) {
 	if v := os.Getenv("TRACK_HOME"); v != "" {
 		return v, nil
@@ -67,6 +157,10 @@ func Load() (Config, error) {
 		return Config{}, err
 	}
 
+	if EnvOnly() {
+		return loadEnv()
+	}
+
 	cfg := Default()
 	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
 		if err := Save(cfg); err != nil {
@@ -83,11 +177,40 @@ func Load() (Config, error) {
 	if cfg.UIPort == 0 {
 		cfg.UIPort = defaultUIPort
 	}
+	if strings.TrimSpace(cfg.SpecSections) == "" {
+		cfg.SpecSections = defaultSpecSections
+	}
+	if cfg.TimeFormat == "" {
+		cfg.TimeFormat = defaultTimeFormat
+	}
+	if cfg.SMTPPort == 0 {
+		cfg.SMTPPort = defaultSMTPPort
+	}
+	if cfg.GHRetryBackoff == "" {
+		cfg.GHRetryBackoff = defaultGHBackoff
+	}
 
 	return cfg, nil
 }
 
+// loadEnv builds the config from the defaults and TRACK_<KEY> variables.
+func loadEnv() (Config, error) {
+	cfg := Default()
+	for _, key := range ValidKeys() {
+		name := "TRACK_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+		if v, ok := os.LookupEnv(name); ok {
+			if err := Set(&cfg, key, v); err != nil {
+				return Config{}, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	return cfg, nil
+}
+
 func Save(cfg Config) error {
+	if EnvOnly() {
+		return fmt.Errorf("config is read from the environment (%s=env); set TRACK_<KEY> variables instead", ConfigEnv)
+	}
 	if err := EnsureDir(); err != nil {
 		return err
 	}
@@ -109,7 +232,13 @@ func Save(cfg Config) error {
 	return nil
 }
 
+// projectPathsPrefix starts the config keys of path-to-project rules.
+const projectPathsPrefix = "project_paths."
+
 func Get(cfg Config, key string) (string, error) {
+	if dir, ok := strings.CutPrefix(key, projectPathsPrefix); ok {
+		return cfg.ProjectPaths[cleanProjectPath(dir)], nil
+	}
 	switch key {
 	case "ui_port":
 		return fmt.Sprintf("%d", cfg.UIPort), nil
@@ -125,12 +254,96 @@ func Get(cfg Config, key string) (string, error) {
 			return "true", nil
 		}
 		return "false", nil
+	case "notify_cmd":
+		return cfg.NotifyCmd, nil
+	case "spec_sections":
+		return cfg.SpecSections, nil
+	case "notify_hook_failures":
+		if cfg.NotifyHookFailures {
+			return "true", nil
+		}
+		return "false", nil
+	case "timezone":
+		return cfg.Timezone, nil
+	case "time_format":
+		return cfg.TimeFormat, nil
+	case "user_name":
+		return cfg.UserName, nil
+	case "user_email":
+		return cfg.UserEmail, nil
+	case "rate_limit":
+		return fmt.Sprintf("%d", cfg.RateLimit), nil
+	case "smtp_host":
+		return cfg.SMTPHost, nil
+	case "smtp_port":
+		return fmt.Sprintf("%d", cfg.SMTPPort), nil
+	case "smtp_username":
+		return cfg.SMTPUsername, nil
+	case "smtp_password":
+		return cfg.SMTPPassword, nil
+	case "smtp_from":
+		return cfg.SMTPFrom, nil
+	case "digest_email":
+		return cfg.DigestEmail, nil
+	case "strict_assignees":
+		if cfg.StrictAssignees {
+			return "true", nil
+		}
+		return "false", nil
+	case "gh_webhook_secret":
+		return cfg.GHWebhookSecret, nil
+	case "gh_retries":
+		return fmt.Sprintf("%d", cfg.GHRetries), nil
+	case "gh_retry_backoff":
+		return cfg.GHRetryBackoff, nil
+	case "secret_store":
+		if cfg.SecretStore == "" {
+			return "auto", nil
+		}
+		return cfg.SecretStore, nil
+	case "priority_inheritance":
+		if cfg.PriorityInheritance == "" {
+			return "show", nil
+		}
+		return cfg.PriorityInheritance, nil
+	case "list.default_status":
+		return cfg.List.DefaultStatus, nil
+	case "list.default_sort":
+		return cfg.List.DefaultSort, nil
+	case "list.hide_snoozed":
+		if cfg.List.HideSnoozed {
+			return "true", nil
+		}
+		return "false", nil
+	case "board.swimlanes":
+		if cfg.Board.Swimlanes == "" {
+			return SwimlanesNone, nil
+		}
+		return cfg.Board.Swimlanes, nil
+	case "board.wip_limit":
+		return fmt.Sprintf("%d", cfg.Board.WIPLimit), nil
 	default:
 		return "", fmt.Errorf("unknown config key: %s", key)
 	}
 }
 
 func Set(cfg *Config, key, value string) error {
+	if dir, ok := strings.CutPrefix(key, projectPathsPrefix); ok {
+		dir = cleanProjectPath(dir)
+		if dir == "" {
+			return fmt.Errorf("invalid project path: %s", key)
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			delete(cfg.ProjectPaths, dir)
+			return nil
+		}
+		if cfg.ProjectPaths == nil {
+			cfg.ProjectPaths = map[string]string{}
+		}
+		cfg.ProjectPaths[dir] = value
+		return nil
+	}
 	switch key {
 	case "ui_port":
 		var v int
@@ -162,11 +375,227 @@ func Set(cfg *Config, key, value string) error {
 			return fmt.Errorf("invalid sync_auto: %s", value)
 		}
 		return nil
+	case "notify_cmd":
+		cfg.NotifyCmd = value
+		return nil
+	case "spec_sections":
+		if len(SplitList(value)) == 0 {
+			return fmt.Errorf("invalid spec_sections: %s", value)
+		}
+		cfg.SpecSections = value
+		return nil
+	case "notify_hook_failures":
+		switch value {
+		case "true":
+			cfg.NotifyHookFailures = true
+		case "false":
+			cfg.NotifyHookFailures = false
+		default:
+			return fmt.Errorf("invalid notify_hook_failures: %s", value)
+		}
+		return nil
+	case "timezone":
+		if _, err := parseLocation(value); err != nil {
+			return err
+		}
+		cfg.Timezone = value
+		return nil
+	case "time_format":
+		switch value {
+		case "iso", "local", "relative":
+			cfg.TimeFormat = value
+		default:
+			return fmt.Errorf("invalid time_format: %s", value)
+		}
+		return nil
+	case "user_name":
+		cfg.UserName = strings.TrimSpace(value)
+		return nil
+	case "user_email":
+		cfg.UserEmail = strings.TrimSpace(value)
+		return nil
+	case "rate_limit":
+		var v int
+		if _, err := fmt.Sscanf(value, "%d", &v); err != nil || v < 0 {
+			return fmt.Errorf("invalid rate_limit: %s", value)
+		}
+		cfg.RateLimit = v
+		return nil
+	case "smtp_host":
+		cfg.SMTPHost = strings.TrimSpace(value)
+		return nil
+	case "smtp_port":
+		var v int
+		if _, err := fmt.Sscanf(value, "%d", &v); err != nil || v <= 0 || v > 65535 {
+			return fmt.Errorf("invalid smtp_port: %s", value)
+		}
+		cfg.SMTPPort = v
+		return nil
+	case "smtp_username":
+		cfg.SMTPUsername = value
+		return nil
+	case "smtp_password":
+		cfg.SMTPPassword = value
+		return nil
+	case "smtp_from":
+		cfg.SMTPFrom = strings.TrimSpace(value)
+		return nil
+	case "digest_email":
+		cfg.DigestEmail = strings.TrimSpace(value)
+		return nil
+	case "strict_assignees":
+		switch value {
+		case "true":
+			cfg.StrictAssignees = true
+		case "false":
+			cfg.StrictAssignees = false
+		default:
+			return fmt.Errorf("invalid strict_assignees: %s", value)
+		}
+		return nil
+	case "gh_webhook_secret":
+		cfg.GHWebhookSecret = strings.TrimSpace(value)
+		return nil
+	case "gh_retries":
+		var v int
+		if _, err := fmt.Sscanf(value, "%d", &v); err != nil || v < 0 {
+			return fmt.Errorf("invalid gh_retries: %s", value)
+		}
+		cfg.GHRetries = v
+		return nil
+	case "gh_retry_backoff":
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return fmt.Errorf("invalid gh_retry_backoff: %s", value)
+		}
+		cfg.GHRetryBackoff = value
+		return nil
+	case "secret_store":
+		switch value {
+		case "auto", "file", "keychain":
+			cfg.SecretStore = value
+		default:
+			return fmt.Errorf("invalid secret_store: %s (want auto|file|keychain)", value)
+		}
+		return nil
+	case "priority_inheritance":
+		switch value {
+		case "show", "raise", "off":
+			cfg.PriorityInheritance = value
+		default:
+			return fmt.Errorf("invalid priority_inheritance: %s (want show|raise|off)", value)
+		}
+		return nil
+	case "list.default_status":
+		cfg.List.DefaultStatus = strings.Join(SplitList(value), ",")
+		return nil
+	case "list.default_sort":
+		cfg.List.DefaultSort = strings.TrimSpace(value)
+		return nil
+	case "list.hide_snoozed":
+		switch value {
+		case "true":
+			cfg.List.HideSnoozed = true
+		case "false":
+			cfg.List.HideSnoozed = false
+		default:
+			return fmt.Errorf("invalid list.hide_snoozed: %s", value)
+		}
+		return nil
+	case "board.swimlanes":
+		switch value {
+		case SwimlanesNone, SwimlanesProject, SwimlanesAssignee, SwimlanesPriority:
+			cfg.Board.Swimlanes = value
+		default:
+			return fmt.Errorf("invalid board.swimlanes: %s (want none|project|assignee|priority)", value)
+		}
+		return nil
+	case "board.wip_limit":
+		var v int
+		if _, err := fmt.Sscanf(value, "%d", &v); err != nil || v < 0 {
+			return fmt.Errorf("invalid board.wip_limit: %s", value)
+		}
+		cfg.Board.WIPLimit = v
+		return nil
 	default:
 		return fmt.Errorf("unknown config key: %s", key)
 	}
 }
 
 func ValidKeys() []string {
-	return []string{"ui_port", "open_browser", "gh_repo", "sync_auto"}
+	return []string{"ui_port", "open_browser", "gh_repo", "sync_auto", "notify_cmd", "spec_sections", "notify_hook_failures", "timezone", "time_format", "user_name", "user_email", "rate_limit", "smtp_host", "smtp_port", "smtp_username", "smtp_password", "smtp_from", "digest_email", "strict_assignees", "gh_webhook_secret", "gh_retries", "gh_retry_backoff", "secret_store", "priority_inheritance", "list.default_status", "list.default_sort", "list.hide_snoozed", "board.swimlanes", "board.wip_limit"}
+}
+
+// ProjectForPath returns the project of the longest project_paths rule that
+// contains dir, a slash-separated path relative to the repository root. It is
+// empty when no rule matches.
+func (c Config) ProjectForPath(dir string) string {
+	dir = cleanProjectPath(dir)
+	best, project := -1, ""
+	for prefix, key := range c.ProjectPaths {
+		prefix = cleanProjectPath(prefix)
+		if (dir == prefix || strings.HasPrefix(dir, prefix+"/")) && len(prefix) > best {
+			best, project = len(prefix), key
+		}
+	}
+	return project
+}
+
+func cleanProjectPath(dir string) string {
+	dir = path.Clean(filepath.ToSlash(strings.TrimSpace(dir)))
+	dir = strings.Trim(dir, "/")
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+// UserIdentity returns the configured user as "name <email>", or whichever
+// of the two is set. It is empty when neither is.
+func (c Config) UserIdentity() string {
+	name, email := strings.TrimSpace(c.UserName), strings.TrimSpace(c.UserEmail)
+	switch {
+	case name != "" && email != "":
+		return name + " <" + email + ">"
+	case name != "":
+		return name
+	default:
+		return email
+	}
+}
+
+// Location returns the configured timezone, or the system's local zone when
+// none is set.
+func (c Config) Location() (*time.Location, error) {
+	return parseLocation(c.Timezone)
+}
+
+// LoadLocation loads the config and returns its timezone.
+func LoadLocation() (*time.Location, error) {
+	cfg, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Location()
+}
+
+func parseLocation(name string) (*time.Location, error) {
+	if strings.TrimSpace(name) == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone: %s", name)
+	}
+	return loc, nil
+}
+
+// SplitList splits a comma separated config value, dropping empty entries.
+func SplitList(value string) []string {
+	out := make([]string, 0)
+	for _, part := range strings.Split(value, ",") {
+		if p := strings.TrimSpace(part); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -17,6 +18,9 @@ func TestLoadCreatesDefaultConfig(t *testing.T) {
 	if cfg.UIPort != 8787 {
 		t.Fatalf("unexpected default ui_port: %d", cfg.UIPort)
 	}
+	if cfg.SpecSections != "Goal,Approach,Test plan" {
+		t.Fatalf("unexpected default spec_sections: %q", cfg.SpecSections)
+	}
 
 	path := filepath.Join(tmp, "config.toml")
 	if _, err := os.Stat(path); err != nil {
@@ -39,12 +43,75 @@ func TestSetAndGetRoundtrip(t *testing.T) {
 	if err := Set(&cfg, "sync_auto", "true"); err != nil {
 		t.Fatalf("set sync_auto: %v", err)
 	}
+	if err := Set(&cfg, "notify_cmd", "notify-send track"); err != nil {
+		t.Fatalf("set notify_cmd: %v", err)
+	}
+	if err := Set(&cfg, "spec_sections", "Goal,Risks"); err != nil {
+		t.Fatalf("set spec_sections: %v", err)
+	}
+	if err := Set(&cfg, "notify_hook_failures", "true"); err != nil {
+		t.Fatalf("set notify_hook_failures: %v", err)
+	}
+	if err := Set(&cfg, "timezone", "Asia/Tokyo"); err != nil {
+		t.Fatalf("set timezone: %v", err)
+	}
+	if err := Set(&cfg, "timezone", "Mars/Olympus"); err == nil {
+		t.Fatalf("expected invalid timezone error")
+	}
+	if err := Set(&cfg, "time_format", "relative"); err != nil {
+		t.Fatalf("set time_format: %v", err)
+	}
+	if err := Set(&cfg, "time_format", "julian"); err == nil {
+		t.Fatalf("expected invalid time_format error")
+	}
+	if err := Set(&cfg, "user_name", "Jane"); err != nil {
+		t.Fatalf("set user_name: %v", err)
+	}
+	if err := Set(&cfg, "user_email", "jane@example.com"); err != nil {
+		t.Fatalf("set user_email: %v", err)
+	}
+	if got := cfg.UserIdentity(); got != "Jane <jane@example.com>" {
+		t.Fatalf("UserIdentity() = %q", got)
+	}
+	if err := Set(&cfg, "rate_limit", "120"); err != nil {
+		t.Fatalf("set rate_limit: %v", err)
+	}
+	if err := Set(&cfg, "rate_limit", "-1"); err == nil {
+		t.Fatalf("expected invalid rate_limit error")
+	}
+	if err := Set(&cfg, "smtp_port", "465"); err != nil {
+		t.Fatalf("set smtp_port: %v", err)
+	}
+	if err := Set(&cfg, "smtp_port", "0"); err == nil {
+		t.Fatalf("expected invalid smtp_port error")
+	}
+	if err := Set(&cfg, "strict_assignees", "yes"); err == nil {
+		t.Fatalf("expected invalid strict_assignees error")
+	}
+	for key, value := range map[string]string{"smtp_host": "smtp.example.com", "smtp_from": "track@example.com", "digest_email": "jane@example.com", "strict_assignees": "true"} {
+		if err := Set(&cfg, key, value); err != nil {
+			t.Fatalf("set %s: %v", key, err)
+		}
+	}
 
 	cases := map[string]string{
-		"ui_port":      "9999",
-		"open_browser": "true",
-		"gh_repo":      "owner/repo",
-		"sync_auto":    "true",
+		"ui_port":              "9999",
+		"open_browser":         "true",
+		"gh_repo":              "owner/repo",
+		"sync_auto":            "true",
+		"notify_cmd":           "notify-send track",
+		"spec_sections":        "Goal,Risks",
+		"notify_hook_failures": "true",
+		"timezone":             "Asia/Tokyo",
+		"time_format":          "relative",
+		"user_name":            "Jane",
+		"user_email":           "jane@example.com",
+		"rate_limit":           "120",
+		"smtp_host":            "smtp.example.com",
+		"smtp_port":            "465",
+		"smtp_from":            "track@example.com",
+		"digest_email":         "jane@example.com",
+		"strict_assignees":     "true",
 	}
 
 	for key, want := range cases {
@@ -57,3 +124,70 @@ func TestSetAndGetRoundtrip(t *testing.T) {
 		}
 	}
 }
+
+func TestSetRejectsEmptySpecSections(t *testing.T) {
+	cfg := Default()
+	if err := Set(&cfg, "spec_sections", " , "); err == nil {
+		t.Fatalf("expected error for empty spec_sections")
+	}
+}
+
+func TestLoadFromEnvOnly(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("TRACK_HOME", home)
+	t.Setenv(ConfigEnv, "env")
+	t.Setenv("TRACK_GH_REPO", "owner/repo")
+	t.Setenv("TRACK_SYNC_AUTO", "true")
+	t.Setenv("TRACK_LIST_DEFAULT_STATUS", "ready, in_progress")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.GHRepo != "owner/repo" || !cfg.SyncAuto || cfg.UIPort != defaultUIPort || cfg.List.DefaultStatus != "ready,in_progress" {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+	if _, err := os.Stat(filepath.Join(home, "config.toml")); !os.IsNotExist(err) {
+		t.Fatalf("env-only Load should not write config.toml: %v", err)
+	}
+	if err := Save(cfg); err == nil {
+		t.Fatalf("expected Save to fail in env-only mode")
+	}
+
+	t.Setenv("TRACK_RATE_LIMIT", "lots")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "TRACK_RATE_LIMIT") {
+		t.Fatalf("expected invalid TRACK_RATE_LIMIT error, got %v", err)
+	}
+}
+
+func TestProjectPaths(t *testing.T) {
+	cfg := Default()
+	for key, value := range map[string]string{"project_paths.services/auth": "auth", "project_paths./services/": "services", "project_paths.services/auth/legacy": "legacy"} {
+		if err := Set(&cfg, key, value); err != nil {
+			t.Fatalf("Set(%s) error: %v", key, err)
+		}
+	}
+	cases := map[string]string{
+		"services/auth":            "auth",
+		"services/auth/internal":   "auth",
+		"services/auth/legacy/api": "legacy",
+		"services/authz":           "services",
+		"services":                 "services",
+		"docs":                     "",
+		"":                         "",
+	}
+	for dir, want := range cases {
+		if got := cfg.ProjectForPath(dir); got != want {
+			t.Fatalf("ProjectForPath(%q) = %q, want %q", dir, got, want)
+		}
+	}
+	if got, _ := Get(cfg, "project_paths.services/auth/"); got != "auth" {
+		t.Fatalf("Get(project_paths.services/auth/) = %q", got)
+	}
+	if err := Set(&cfg, "project_paths.services/auth", ""); err != nil {
+		t.Fatalf("Set() to clear error: %v", err)
+	}
+	if got := cfg.ProjectForPath("services/auth"); got != "services" {
+		t.Fatalf("cleared rule still matches: %q", got)
+	}
+}
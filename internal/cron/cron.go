@@ -0,0 +1,176 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+// Job is a periodic background task. Run writes progress to log, which is
+// appended to the job's log file under $TRACK_HOME/logs/cron.
+type Job struct {
+	Name            string
+	Description     string
+	DefaultInterval time.Duration
+	DefaultEnabled  bool
+	Run             func(ctx context.Context, store *sqlite.Store, log io.Writer) error
+}
+
+type Scheduler struct {
+	Jobs []Job
+	Now  func() time.Time
+}
+
+func NewScheduler(jobs ...Job) *Scheduler {
+	return &Scheduler{Jobs: jobs, Now: time.Now}
+}
+
+func (s *Scheduler) Find(name string) (Job, error) {
+	for _, j := range s.Jobs {
+		if j.Name == name {
+			return j, nil
+		}
+	}
+	return Job{}, fmt.Errorf("unknown job: %s", name)
+}
+
+// State returns the stored state of job, filling in its defaults when the job
+// has never been configured.
+func State(ctx context.Context, store *sqlite.Store, job Job) (sqlite.JobState, error) {
+	st, ok, err := store.GetJobState(ctx, job.Name)
+	if err != nil {
+		return sqlite.JobState{}, err
+	}
+	if !ok {
+		st = sqlite.JobState{Name: job.Name, Enabled: job.DefaultEnabled}
+	}
+	if st.Interval <= 0 {
+		st.Interval = job.DefaultInterval
+	}
+	return st, nil
+}
+
+// RunDue runs every enabled job whose interval has elapsed since its last run
+// and returns the names of the jobs that ran. Job failures are recorded in the
+// job state and log rather than returned.
+func (s *Scheduler) RunDue(ctx context.Context, store *sqlite.Store) ([]string, error) {
+	now := s.Now().UTC()
+	ran := make([]string, 0)
+	for _, job := range s.Jobs {
+		st, err := State(ctx, store, job)
+		if err != nil {
+			return ran, err
+		}
+		if !st.Enabled {
+			continue
+		}
+		if st.LastRunAt != "" {
+			last, err := time.Parse(time.RFC3339, st.LastRunAt)
+			if err == nil && now.Sub(last) < st.Interval {
+				continue
+			}
+		}
+		_ = s.RunJob(ctx, store, job)
+		ran = append(ran, job.Name)
+	}
+	return ran, nil
+}
+
+// RunJob runs job immediately, appending its output to the job log and
+// recording the outcome in the job state.
+func (s *Scheduler) RunJob(ctx context.Context, store *sqlite.Store, job Job) error {
+	st, err := State(ctx, store, job)
+	if err != nil {
+		return err
+	}
+
+	logPath, err := LogPath(job.Name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o755); err != nil {
+		return fmt.Errorf("create cron log dir: %w", err)
+	}
+	f, err := os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open cron log: %w", err)
+	}
+	defer f.Close()
+
+	started := s.Now().UTC()
+	fmt.Fprintf(f, "=== %s %s\n", job.Name, started.Format(time.RFC3339))
+	runErr := job.Run(ctx, store, f)
+	if runErr != nil {
+		fmt.Fprintf(f, "error: %v\n", runErr)
+	}
+
+	st.LastRunAt = started.Format(time.RFC3339)
+	st.LastError = ""
+	if runErr != nil {
+		st.LastError = runErr.Error()
+	}
+	if err := store.SaveJobState(ctx, st); err != nil {
+		return err
+	}
+	return runErr
+}
+
+// Loop runs due jobs every tick until ctx is cancelled.
+func (s *Scheduler) Loop(ctx context.Context, tick time.Duration, errOut io.Writer) error {
+	runOnce := func() {
+		store, err := sqlite.Open(ctx)
+		if err != nil {
+			fmt.Fprintf(errOut, "cron error: %v\n", err)
+			return
+		}
+		defer store.Close()
+		if _, err := s.RunDue(ctx, store); err != nil {
+			fmt.Fprintf(errOut, "cron error: %v\n", err)
+		}
+	}
+
+	runOnce()
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			runOnce()
+		}
+	}
+}
+
+func LogPath(job string) (string, error) {
+	home, err := appconfig.HomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "logs", "cron", job+".log"), nil
+}
+
+// ParseEvery parses recurrence intervals: hourly, daily, weekly, or a Go
+// duration such as 36h.
+func ParseEvery(v string) (time.Duration, error) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "hourly":
+		return time.Hour, nil
+	case "daily":
+		return 24 * time.Hour, nil
+	case "weekly":
+		return 7 * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(v))
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid interval: %s", v)
+	}
+	return d, nil
+}
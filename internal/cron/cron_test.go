@@ -0,0 +1,201 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func openStore(t *testing.T) *sqlite.Store {
+	t.Helper()
+	t.Setenv("TRACK_HOME", t.TempDir())
+	store, err := sqlite.Open(context.Background())
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestRunDueRespectsEnabledAndInterval(t *testing.T) {
+	store := openStore(t)
+	ctx := context.Background()
+
+	calls := map[string]int{}
+	job := func(name string, enabled bool, err error) Job {
+		return Job{
+			Name:            name,
+			DefaultInterval: time.Hour,
+			DefaultEnabled:  enabled,
+			Run: func(ctx context.Context, store *sqlite.Store, log io.Writer) error {
+				calls[name]++
+				fmt.Fprintf(log, "%s ran\n", name)
+				return err
+			},
+		}
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sched := NewScheduler(job("a", true, nil), job("b", false, nil), job("c", true, errors.New("boom")))
+	sched.Now = func() time.Time { return now }
+
+	ran, err := sched.RunDue(ctx, store)
+	if err != nil {
+		t.Fatalf("RunDue() error: %v", err)
+	}
+	if strings.Join(ran, ",") != "a,c" {
+		t.Fatalf("ran = %v, want [a c]", ran)
+	}
+
+	now = now.Add(30 * time.Minute)
+	if ran, _ := sched.RunDue(ctx, store); len(ran) != 0 {
+		t.Fatalf("jobs should not rerun before interval, ran %v", ran)
+	}
+	now = now.Add(time.Hour)
+	if ran, _ := sched.RunDue(ctx, store); len(ran) != 2 {
+		t.Fatalf("jobs should rerun after interval, ran %v", ran)
+	}
+	if calls["a"] != 2 || calls["b"] != 0 || calls["c"] != 2 {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+
+	st, err := State(ctx, store, sched.Jobs[2])
+	if err != nil {
+		t.Fatalf("State() error: %v", err)
+	}
+	if st.LastError != "boom" {
+		t.Fatalf("LastError = %q, want boom", st.LastError)
+	}
+	path, _ := LogPath("c")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read job log: %v", err)
+	}
+	if strings.Count(string(data), "c ran") != 2 || !strings.Contains(string(data), "error: boom") {
+		t.Fatalf("unexpected log:\n%s", data)
+	}
+}
+
+func TestRecurringJobCreatesIssueAndAdvances(t *testing.T) {
+	store := openStore(t)
+	ctx := context.Background()
+
+	start := time.Now().UTC().Add(-50 * time.Hour)
+	r, err := store.AddRecurringIssue(ctx, sqlite.RecurringIssue{
+		Title:     "Rotate logs",
+		Priority:  "p2",
+		Labels:    []string{"ops"},
+		Every:     "daily",
+		NextRunAt: start.Format(time.RFC3339),
+	})
+	if err != nil {
+		t.Fatalf("AddRecurringIssue() error: %v", err)
+	}
+
+	if err := runRecurring(ctx, store, io.Discard); err != nil {
+		t.Fatalf("runRecurring() error: %v", err)
+	}
+	items, err := store.ListIssues(ctx, sqlite.ListFilter{})
+	if err != nil {
+		t.Fatalf("ListIssues() error: %v", err)
+	}
+	if len(items) != 1 || items[0].Title != "Rotate logs" || items[0].Priority != "p2" || items[0].Status != issue.StatusTodo {
+		t.Fatalf("unexpected issues: %+v", items)
+	}
+
+	recs, err := store.ListRecurringIssues(ctx)
+	if err != nil {
+		t.Fatalf("ListRecurringIssues() error: %v", err)
+	}
+	want := start.Add(72 * time.Hour).Format(time.RFC3339)
+	if recs[0].ID != r.ID || recs[0].NextRunAt != want {
+		t.Fatalf("next run = %s, want %s", recs[0].NextRunAt, want)
+	}
+
+	if err := runRecurring(ctx, store, io.Discard); err != nil {
+		t.Fatalf("runRecurring() second error: %v", err)
+	}
+	items, _ = store.ListIssues(ctx, sqlite.ListFilter{})
+	if len(items) != 1 {
+		t.Fatalf("recurring issue should not be created again before next run, got %d", len(items))
+	}
+}
+
+func TestPriorityAgingSkipsFreshIssues(t *testing.T) {
+	store := openStore(t)
+	ctx := context.Background()
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "fresh", Status: issue.StatusTodo, Priority: "p3"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	if err := runPriorityAging(ctx, store, io.Discard); err != nil {
+		t.Fatalf("runPriorityAging() error: %v", err)
+	}
+	got, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if got.Priority != "p3" {
+		t.Fatalf("fresh issue priority = %s, want p3", got.Priority)
+	}
+}
+
+func TestBackupJobPrunesOldBackups(t *testing.T) {
+	store := openStore(t)
+	ctx := context.Background()
+
+	dir, err := BackupDir()
+	if err != nil {
+		t.Fatalf("BackupDir() error: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for i := 0; i < BackupKeep; i++ {
+		name := fmt.Sprintf("%s/track-2000010%dT000000Z.db", dir, i)
+		if err := os.WriteFile(name, nil, 0o644); err != nil {
+			t.Fatalf("write old backup: %v", err)
+		}
+	}
+
+	if err := runBackup(ctx, store, io.Discard); err != nil {
+		t.Fatalf("runBackup() error: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read backup dir: %v", err)
+	}
+	if len(entries) != BackupKeep {
+		t.Fatalf("backups = %d, want %d", len(entries), BackupKeep)
+	}
+	if entries[0].Name() != "track-20000101T000000Z.db" {
+		t.Fatalf("oldest backup should be pruned, first = %s", entries[0].Name())
+	}
+}
+
+func TestParseEvery(t *testing.T) {
+	cases := map[string]time.Duration{
+		"daily":  24 * time.Hour,
+		"Weekly": 7 * 24 * time.Hour,
+		"36h":    36 * time.Hour,
+	}
+	for in, want := range cases {
+		got, err := ParseEvery(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseEvery(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "monthly", "-1h"} {
+		if _, err := ParseEvery(in); err == nil {
+			t.Fatalf("ParseEvery(%q) should fail", in)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/digest"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/notify"
+	"github.com/myuon/track/internal/service"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+const (
+	JobDueSoon       = "due-soon"
+	JobRecurring     = "recurring"
+	JobPriorityAging = "priority-aging"
+	JobBackup        = "backup"
+	JobDigest        = "digest"
+
+	// DueSoonWindow is how far ahead due-soon looks for upcoming due dates.
+	DueSoonWindow = 24 * time.Hour
+	// AgingAfter is how long a todo/ready issue may sit untouched before
+	// priority-aging bumps it.
+	AgingAfter = 14 * 24 * time.Hour
+	// BackupKeep is the number of database backups retained.
+	BackupKeep = 7
+)
+
+// Builtin returns the jobs that ship with track.
+func Builtin() []Job {
+	return []Job{
+		{
+			Name:            JobDueSoon,
+			Description:     "notify about issues due within a day",
+			DefaultInterval: time.Hour,
+			DefaultEnabled:  true,
+			Run:             runDueSoon,
+		},
+		{
+			Name:            JobRecurring,
+			Description:     "create issues from recurring templates",
+			DefaultInterval: 15 * time.Minute,
+			DefaultEnabled:  true,
+			Run:             runRecurring,
+		},
+		{
+			Name:            JobPriorityAging,
+			Description:     "raise the priority of stale todo/ready issues",
+			DefaultInterval: 24 * time.Hour,
+			Run:             runPriorityAging,
+		},
+		{
+			Name:            JobBackup,
+			Description:     "snapshot the database into $TRACK_HOME/backups",
+			DefaultInterval: 24 * time.Hour,
+			Run:             runBackup,
+		},
+		{
+			Name:            JobDigest,
+			Description:     "mail the activity digest to digest_email",
+			DefaultInterval: digest.Period,
+			Run:             runDigest,
+		},
+	}
+}
+
+func runDueSoon(ctx context.Context, store *sqlite.Store, log io.Writer) error {
+	items, err := store.ListIssues(ctx, sqlite.ListFilter{ExcludeDone: true, ExcludeArchived: true})
+	if err != nil {
+		return err
+	}
+	loc, err := appconfig.LoadLocation()
+	if err != nil {
+		return err
+	}
+	by := time.Now().Add(DueSoonWindow)
+	for _, it := range items {
+		if !dueBy(it.Due, by, loc) {
+			continue
+		}
+		fresh, err := store.MarkJobKey(ctx, JobDueSoon, it.ID+":"+it.Due)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			continue
+		}
+		if err := notify.Send(ctx, notify.Message{
+			IssueID: it.ID,
+			Title:   fmt.Sprintf("%s is due %s", it.ID, it.Due),
+			Body:    it.Title,
+		}); err != nil {
+			fmt.Fprintf(log, "%s: notify failed: %v\n", it.ID, err)
+			continue
+		}
+		fmt.Fprintf(log, "%s: due %s\n", it.ID, it.Due)
+	}
+	return nil
+}
+
+// dueBy reports whether due falls on or before by. Date-only values count
+// when their day in loc has started by then.
+func dueBy(due string, by time.Time, loc *time.Location) bool {
+	if due == "" {
+		return false
+	}
+	if _, err := time.Parse(issue.DueDateLayout, due); err == nil {
+		return due <= by.In(loc).Format(issue.DueDateLayout)
+	}
+	t, ok := issue.DueTime(due, loc)
+	return ok && !t.After(by)
+}
+
+func runRecurring(ctx context.Context, store *sqlite.Store, log io.Writer) error {
+	recs, err := store.ListRecurringIssues(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, r := range recs {
+		next, err := time.Parse(time.RFC3339, r.NextRunAt)
+		if err != nil {
+			return fmt.Errorf("recurring %d: invalid next run: %w", r.ID, err)
+		}
+		if next.After(now) {
+			continue
+		}
+		every, err := ParseEvery(r.Every)
+		if err != nil {
+			return fmt.Errorf("recurring %d: %w", r.ID, err)
+		}
+
+		item, err := service.CreateIssue(ctx, store, issue.Item{
+			Title:    r.Title,
+			Status:   issue.StatusTodo,
+			Priority: r.Priority,
+			Labels:   r.Labels,
+			Body:     r.Body,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(log, "recurring %d: created %s\n", r.ID, item.ID)
+
+		// Skip occurrences missed while the scheduler was not running.
+		for !next.After(now) {
+			next = next.Add(every)
+		}
+		if err := store.SetRecurringNextRun(ctx, r.ID, next.Format(time.RFC3339)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var agedPriority = map[string]string{
+	"p3": "p2",
+	"p2": "p1",
+}
+
+func runPriorityAging(ctx context.Context, store *sqlite.Store, log io.Writer) error {
+	items, err := store.ListIssues(ctx, sqlite.ListFilter{Statuses: []string{issue.StatusTodo, issue.StatusReady}})
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().UTC().Add(-AgingAfter)
+	for _, it := range items {
+		bumped, ok := agedPriority[it.Priority]
+		if !ok {
+			continue
+		}
+		updated, err := time.Parse(time.RFC3339, it.UpdatedAt)
+		if err != nil || updated.After(cutoff) {
+			continue
+		}
+		if _, err := service.UpdateIssue(ctx, store, it.ID, service.Update{UpdateIssueInput: sqlite.UpdateIssueInput{Priority: &bumped}}); err != nil {
+			return err
+		}
+		fmt.Fprintf(log, "%s: %s -> %s\n", it.ID, it.Priority, bumped)
+	}
+	return nil
+}
+
+func runBackup(ctx context.Context, store *sqlite.Store, log io.Writer) error {
+	dir, err := BackupDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create backup dir: %w", err)
+	}
+	path := filepath.Join(dir, "track-"+time.Now().UTC().Format("20060102T150405Z")+".db")
+	if err := store.BackupTo(ctx, path); err != nil {
+		return err
+	}
+	fmt.Fprintf(log, "backup: %s\n", path)
+
+	matches, err := filepath.Glob(filepath.Join(dir, "track-*.db"))
+	if err != nil {
+		return fmt.Errorf("list backups: %w", err)
+	}
+	sort.Strings(matches)
+	for len(matches) > BackupKeep {
+		if err := os.Remove(matches[0]); err != nil {
+			return fmt.Errorf("remove old backup: %w", err)
+		}
+		fmt.Fprintf(log, "pruned: %s\n", matches[0])
+		matches = matches[1:]
+	}
+	return nil
+}
+
+// runDigest mails the digest covering the job's interval.
+func runDigest(ctx context.Context, store *sqlite.Store, log io.Writer) error {
+	cfg, err := appconfig.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.DigestEmail == "" {
+		return fmt.Errorf("digest_email is not set (see track config set digest_email)")
+	}
+	loc, err := cfg.Location()
+	if err != nil {
+		return err
+	}
+	period := digest.Period
+	if st, ok, err := store.GetJobState(ctx, JobDigest); err != nil {
+		return err
+	} else if ok && st.Interval > 0 {
+		period = st.Interval
+	}
+	now := time.Now()
+	d, err := digest.Build(ctx, store, now.Add(-period), now, loc)
+	if err != nil {
+		return err
+	}
+	if err := digest.Send(cfg, cfg.DigestEmail, d); err != nil {
+		return err
+	}
+	fmt.Fprintf(log, "digest: sent to %s\n", cfg.DigestEmail)
+	return nil
+}
+
+func BackupDir() (string, error) {
+	home, err := appconfig.HomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "backups"), nil
+}
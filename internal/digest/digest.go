@@ -0,0 +1,153 @@
+// Package digest builds the summary mailed by `track digest` and the digest
+// cron job: work completed in a period, overdue issues, and questions still
+// waiting for an answer.
+package digest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"net"
+	"net/smtp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/secret"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+// Period is the span a digest covers by default.
+const Period = 7 * 24 * time.Hour
+
+type Digest struct {
+	Since     time.Time
+	Until     time.Time
+	Completed []issue.Item
+	Overdue   []issue.Item
+	Waiting   []Waiting
+}
+
+// Waiting is an open issue with unanswered questions for the user.
+type Waiting struct {
+	issue.Item
+	Questions []string
+}
+
+// Build collects the digest for since..now. Completed issues are those moved
+// to done in the period, per the activity log.
+func Build(ctx context.Context, store *sqlite.Store, since, now time.Time, loc *time.Location) (Digest, error) {
+	d := Digest{Since: since, Until: now}
+
+	entries, err := store.ListActivity(ctx, since.UTC().Format(time.RFC3339), 0)
+	if err != nil {
+		return Digest{}, err
+	}
+	var done []string
+	for _, a := range entries {
+		if a.Kind == sqlite.ActivityStatus && strings.HasSuffix(a.Detail, "-> "+issue.StatusDone) && !slices.Contains(done, a.IssueID) {
+			done = append(done, a.IssueID)
+		}
+	}
+	for _, id := range done {
+		it, err := store.GetIssue(ctx, id)
+		if err != nil {
+			return Digest{}, err
+		}
+		if it.Status == issue.StatusDone {
+			d.Completed = append(d.Completed, it)
+		}
+	}
+
+	open, err := store.ListIssues(ctx, sqlite.ListFilter{ExcludeDone: true, ExcludeArchived: true, Sort: "due"})
+	if err != nil {
+		return Digest{}, err
+	}
+	for _, it := range open {
+		if it.Due != "" && issue.IsOverdue(it.Due, now, loc) {
+			d.Overdue = append(d.Overdue, it)
+		}
+		var questions []string
+		for _, q := range issue.Questions(it.Body) {
+			if !q.Answered() {
+				questions = append(questions, q.Text)
+			}
+		}
+		if len(questions) > 0 {
+			d.Waiting = append(d.Waiting, Waiting{Item: it, Questions: questions})
+		}
+	}
+	return d, nil
+}
+
+// Subject is the mail subject line for d.
+func (d Digest) Subject() string {
+	return fmt.Sprintf("Track digest: %d completed, %d overdue, %d waiting", len(d.Completed), len(d.Overdue), len(d.Waiting))
+}
+
+var htmlTpl = template.Must(template.New("digest").Parse(`<!doctype html><html><body>
+<h1>Track digest</h1>
+<p>{{.Since.Format "2006-01-02"}} to {{.Until.Format "2006-01-02"}}</p>
+<h2>Completed ({{len .Completed}})</h2>
+<ul>{{range .Completed}}<li>{{.ID}} {{.Title}}</li>{{else}}<li>Nothing completed</li>{{end}}</ul>
+<h2>Overdue ({{len .Overdue}})</h2>
+<ul>{{range .Overdue}}<li>{{.ID}} {{.Title}} (due {{.Due}})</li>{{else}}<li>Nothing overdue</li>{{end}}</ul>
+<h2>Waiting for your answer ({{len .Waiting}})</h2>
+<ul>{{range .Waiting}}<li>{{.ID}} {{.Title}}<ul>{{range .Questions}}<li>{{.}}</li>{{end}}</ul></li>{{else}}<li>No open questions</li>{{end}}</ul>
+</body></html>
+`))
+
+// HTML renders d as an HTML document.
+func (d Digest) HTML() (string, error) {
+	var buf bytes.Buffer
+	if err := htmlTpl.Execute(&buf, d); err != nil {
+		return "", fmt.Errorf("render digest: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// sendMail is replaced in tests.
+var sendMail = smtp.SendMail
+
+// Send mails d to the given address through the configured SMTP server.
+// The sender is smtp_from, falling back to user_email; smtp_username and
+// smtp_password are used for PLAIN auth when set.
+func Send(cfg appconfig.Config, to string, d Digest) error {
+	if strings.TrimSpace(cfg.SMTPHost) == "" {
+		return fmt.Errorf("smtp_host is not set (see track config set smtp_host)")
+	}
+	from := cfg.SMTPFrom
+	if from == "" {
+		from = cfg.UserEmail
+	}
+	if from == "" {
+		return fmt.Errorf("smtp_from is not set (see track config set smtp_from)")
+	}
+	body, err := d.HTML()
+	if err != nil {
+		return err
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\nTo: %s\r\nSubject: %s\r\n", from, to, d.Subject())
+	msg.WriteString("MIME-Version: 1.0\r\nContent-Type: text/html; charset=UTF-8\r\n\r\n")
+	msg.WriteString(body)
+
+	var auth smtp.Auth
+	if cfg.SMTPUsername != "" {
+		password, err := secret.Expand(cfg.SMTPPassword)
+		if err != nil {
+			return fmt.Errorf("smtp_password: %w", err)
+		}
+		auth = smtp.PlainAuth("", cfg.SMTPUsername, password, cfg.SMTPHost)
+	}
+	addr := net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort))
+	if err := sendMail(addr, auth, from, []string{to}, []byte(msg.String())); err != nil {
+		return fmt.Errorf("send digest: %w", err)
+	}
+	return nil
+}
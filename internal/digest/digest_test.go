@@ -0,0 +1,78 @@
+package digest
+
+import (
+	"context"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestBuildAndSend(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	shipped, err := store.CreateIssue(ctx, issue.Item{Title: "Ship login", Status: issue.StatusInProgress, Priority: "p1"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	done := issue.StatusDone
+	if _, err := store.UpdateIssue(ctx, shipped.ID, sqlite.UpdateIssueInput{Status: &done}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+	if _, err := store.CreateIssue(ctx, issue.Item{Title: "Late report", Status: issue.StatusTodo, Priority: "p2", Due: "2020-01-01"}); err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	if _, err := store.CreateIssue(ctx, issue.Item{Title: "Pick a DB", Status: issue.StatusTodo, Priority: "p2", Body: "## Questions for user\n- sqlite or postgres?\n- hosted?\n  - A: yes\n"}); err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	now := time.Now()
+	d, err := Build(ctx, store, now.Add(-Period), now, time.UTC)
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	if len(d.Completed) != 1 || len(d.Overdue) != 1 || len(d.Waiting) != 1 || len(d.Waiting[0].Questions) != 1 {
+		t.Fatalf("unexpected digest: %+v", d)
+	}
+
+	var gotAddr, gotMsg string
+	var gotTo []string
+	orig := sendMail
+	t.Cleanup(func() { sendMail = orig })
+	sendMail = func(addr string, _ smtp.Auth, _ string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, string(msg)
+		return nil
+	}
+
+	cfg := appconfig.Default()
+	if err := Send(cfg, "jane@example.com", d); err == nil {
+		t.Fatalf("Send() without smtp_host should fail")
+	}
+	cfg.SMTPHost = "smtp.example.com"
+	cfg.SMTPFrom = "track@example.com"
+	if err := Send(cfg, "jane@example.com", d); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+	if gotAddr != "smtp.example.com:587" || len(gotTo) != 1 || gotTo[0] != "jane@example.com" {
+		t.Fatalf("sent to %s %v", gotAddr, gotTo)
+	}
+	for _, want := range []string{"Subject: Track digest: 1 completed, 1 overdue, 1 waiting", "Content-Type: text/html", "Ship login", "Late report (due 2020-01-01)", "<li>sqlite or postgres?</li>"} {
+		if !strings.Contains(gotMsg, want) {
+			t.Fatalf("message should contain %q:\n%s", want, gotMsg)
+		}
+	}
+	if strings.Contains(gotMsg, "hosted?") {
+		t.Fatalf("answered questions should be left out:\n%s", gotMsg)
+	}
+}
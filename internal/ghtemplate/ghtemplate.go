@@ -0,0 +1,189 @@
+// Package ghtemplate reads GitHub issue templates, both markdown templates
+// with front matter and YAML issue forms, so they can be stored as track
+// templates.
+package ghtemplate
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Template is a GitHub issue template reduced to what a track issue can
+// carry. Title is the prefix GitHub puts in front of new issue titles, kept
+// with its trailing space, and Body is markdown: forms become one
+// "## Label" section per field.
+type Template struct {
+	Name        string
+	Description string
+	Title       string
+	Labels      []string
+	Assignees   []string
+	Body        string
+}
+
+// IsTemplateFile reports whether a file in .github/ISSUE_TEMPLATE is an
+// issue template. config.yml only configures the template chooser.
+func IsTemplateFile(name string) bool {
+	base := strings.ToLower(filepath.Base(name))
+	if base == "config.yml" || base == "config.yaml" {
+		return false
+	}
+	switch filepath.Ext(base) {
+	case ".md", ".yml", ".yaml":
+		return true
+	}
+	return false
+}
+
+// Parse reads one template file. The file name picks the format and, when
+// the template has no name, becomes its name.
+func Parse(filename string, data []byte) (Template, error) {
+	var (
+		t   Template
+		err error
+	)
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".md":
+		t, err = parseMarkdown(string(data))
+	case ".yml", ".yaml":
+		t, err = parseForm(string(data))
+	default:
+		return Template{}, fmt.Errorf("unsupported template file: %s", filename)
+	}
+	if err != nil {
+		return Template{}, fmt.Errorf("%s: %w", filepath.Base(filename), err)
+	}
+	if t.Name == "" {
+		t.Name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+	return t, nil
+}
+
+func parseMarkdown(src string) (Template, error) {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	front, body := "", src
+	if rest, ok := strings.CutPrefix(src, "---\n"); ok {
+		end := strings.Index(rest, "\n---")
+		if end < 0 {
+			return Template{}, fmt.Errorf("unterminated front matter")
+		}
+		front = rest[:end]
+		body = strings.TrimPrefix(rest[end+len("\n---"):], "\n")
+	}
+	t := Template{Body: strings.TrimSpace(body)}
+	if front == "" {
+		return t, nil
+	}
+	v, err := parseYAML(front)
+	if err != nil {
+		return Template{}, err
+	}
+	meta, ok := v.(map[string]any)
+	if !ok {
+		return Template{}, fmt.Errorf("front matter is not a mapping")
+	}
+	t.Name = stringField(meta, "name")
+	t.Description = stringField(meta, "about")
+	t.Title, _ = meta["title"].(string)
+	t.Labels = listField(meta, "labels")
+	t.Assignees = listField(meta, "assignees")
+	return t, nil
+}
+
+func parseForm(src string) (Template, error) {
+	v, err := parseYAML(src)
+	if err != nil {
+		return Template{}, err
+	}
+	form, ok := v.(map[string]any)
+	if !ok {
+		return Template{}, fmt.Errorf("issue form is not a mapping")
+	}
+	t := Template{
+		Name:        stringField(form, "name"),
+		Description: stringField(form, "description"),
+		Labels:      listField(form, "labels"),
+		Assignees:   listField(form, "assignees"),
+	}
+	t.Title, _ = form["title"].(string)
+	elements, _ := form["body"].([]any)
+	var sections []string
+	for _, e := range elements {
+		el, _ := e.(map[string]any)
+		if s := formSection(el); s != "" {
+			sections = append(sections, s)
+		}
+	}
+	t.Body = strings.Join(sections, "\n\n")
+	return t, nil
+}
+
+// formSection renders one form element. Markdown elements are instructions
+// for the reporter and are dropped; fields become sections with their
+// default value, or their choices as a checklist.
+func formSection(el map[string]any) string {
+	attrs, _ := el["attributes"].(map[string]any)
+	label := stringField(attrs, "label")
+	if label == "" {
+		return ""
+	}
+	var content string
+	switch stringField(el, "type") {
+	case "textarea", "input":
+		content = stringField(attrs, "value")
+	case "dropdown":
+		var b strings.Builder
+		for _, opt := range listField(attrs, "options") {
+			fmt.Fprintf(&b, "- [ ] %s\n", opt)
+		}
+		content = b.String()
+	case "checkboxes":
+		var b strings.Builder
+		opts, _ := attrs["options"].([]any)
+		for _, o := range opts {
+			switch o := o.(type) {
+			case map[string]any:
+				fmt.Fprintf(&b, "- [ ] %s\n", stringField(o, "label"))
+			case string:
+				fmt.Fprintf(&b, "- [ ] %s\n", o)
+			}
+		}
+		content = b.String()
+	default:
+		return ""
+	}
+	if content = strings.TrimSpace(content); content == "" {
+		return "## " + label
+	}
+	return "## " + label + "\n\n" + content
+}
+
+func stringField(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return strings.TrimSpace(s)
+}
+
+// listField reads a list of strings written either as a YAML sequence or,
+// as GitHub also accepts, a comma-separated string whose items may be
+// quoted.
+func listField(m map[string]any, key string) []string {
+	var raw []string
+	switch v := m[key].(type) {
+	case string:
+		raw = strings.Split(v, ",")
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				raw = append(raw, s)
+			}
+		}
+	}
+	var out []string
+	for _, s := range raw {
+		if s = strings.Trim(strings.TrimSpace(s), `"'`); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
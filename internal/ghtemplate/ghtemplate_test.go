@@ -0,0 +1,94 @@
+package ghtemplate
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseIssueForm(t *testing.T) {
+	src := `name: Bug report
+description: "Report something that's broken"
+title: "[Bug]: "
+labels: ["bug", triage]
+assignees:
+  - octocat
+body:
+  - type: markdown
+    attributes:
+      value: |
+        Thanks for taking the time to fill out this bug report!
+  - type: textarea
+    id: what-happened
+    attributes:
+      label: What happened?
+      description: Also tell us, what did you expect to happen?
+      value: |
+        Steps:
+        1.
+    validations:
+      required: true
+  - type: dropdown
+    id: version
+    attributes:
+      label: Version # which release
+      options:
+        - 1.0.2 (Default)
+        - 1.0.3 (Edge)
+  - type: checkboxes
+    attributes:
+      label: Code of Conduct
+      options:
+        - label: I agree to follow this project's Code of Conduct
+          required: true
+  - type: input
+    attributes:
+      label: Contact
+`
+	got, err := Parse("bug_report.yml", []byte(src))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if got.Name != "Bug report" || got.Description != "Report something that's broken" || got.Title != "[Bug]: " {
+		t.Fatalf("unexpected metadata: %+v", got)
+	}
+	if !slices.Equal(got.Labels, []string{"bug", "triage"}) || !slices.Equal(got.Assignees, []string{"octocat"}) {
+		t.Fatalf("labels=%v assignees=%v", got.Labels, got.Assignees)
+	}
+	want := "## What happened?\n\nSteps:\n1.\n\n" +
+		"## Version\n\n- [ ] 1.0.2 (Default)\n- [ ] 1.0.3 (Edge)\n\n" +
+		"## Code of Conduct\n\n- [ ] I agree to follow this project's Code of Conduct\n\n" +
+		"## Contact"
+	if got.Body != want {
+		t.Fatalf("body =\n%q\nwant\n%q", got.Body, want)
+	}
+}
+
+func TestParseMarkdownTemplate(t *testing.T) {
+	src := "---\nname: Feature request\nabout: Suggest an idea\ntitle: ''\nlabels: enhancement, 'needs triage'\nassignees: ''\n---\n\n## Problem\n\n## Proposal\n"
+	got, err := Parse("feature_request.md", []byte(src))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if got.Name != "Feature request" || got.Description != "Suggest an idea" || got.Title != "" || len(got.Assignees) != 0 {
+		t.Fatalf("unexpected metadata: %+v", got)
+	}
+	if !slices.Equal(got.Labels, []string{"enhancement", "needs triage"}) {
+		t.Fatalf("labels = %q", got.Labels)
+	}
+	if got.Body != "## Problem\n\n## Proposal" {
+		t.Fatalf("body = %q", got.Body)
+	}
+
+	plain, err := Parse("docs.md", []byte("Describe the docs change."))
+	if err != nil || plain.Name != "docs" || plain.Body != "Describe the docs change." {
+		t.Fatalf("template without front matter = %+v, %v", plain, err)
+	}
+}
+
+func TestIsTemplateFile(t *testing.T) {
+	for name, want := range map[string]bool{"bug.yml": true, "feature.md": true, "form.yaml": true, "config.yml": false, "README.txt": false} {
+		if got := IsTemplateFile(name); got != want {
+			t.Fatalf("IsTemplateFile(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,414 @@
+package ghtemplate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseYAML decodes the subset of YAML that GitHub issue templates use:
+// block mappings and sequences, flow sequences and mappings of scalars,
+// quoted and plain scalars, and literal (|) and folded (>) block scalars.
+// Mappings decode to map[string]any, sequences to []any, and scalars to
+// string; anchors, tags, and multi-document streams are not supported.
+func parseYAML(src string) (any, error) {
+	p := &yamlParser{lines: strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")}
+	p.skipBlank()
+	if p.pos < len(p.lines) && strings.TrimSpace(p.lines[p.pos]) == "---" {
+		p.pos++
+	}
+	p.skipBlank()
+	if p.pos >= len(p.lines) {
+		return map[string]any{}, nil
+	}
+	v, err := p.parseBlock(0)
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+	if p.pos < len(p.lines) && strings.TrimSpace(p.lines[p.pos]) != "---" && strings.TrimSpace(p.lines[p.pos]) != "..." {
+		return nil, p.errorf("unexpected content %q", strings.TrimSpace(p.lines[p.pos]))
+	}
+	return v, nil
+}
+
+type yamlParser struct {
+	lines []string
+	pos   int
+}
+
+func (p *yamlParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("yaml line %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+// skipBlank moves past empty and comment-only lines.
+func (p *yamlParser) skipBlank() {
+	for p.pos < len(p.lines) {
+		t := strings.TrimSpace(p.lines[p.pos])
+		if t != "" && !strings.HasPrefix(t, "#") {
+			return
+		}
+		p.pos++
+	}
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func isSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// parseBlock parses the node starting at the next line, which must be
+// indented at least indent.
+func (p *yamlParser) parseBlock(indent int) (any, error) {
+	p.skipBlank()
+	if p.pos >= len(p.lines) {
+		return "", nil
+	}
+	line := p.lines[p.pos]
+	n := indentOf(line)
+	if n < indent {
+		return "", nil
+	}
+	text := strings.TrimSpace(line)
+	if isSeqItem(text) {
+		return p.parseSeq(n)
+	}
+	if _, _, ok := splitMapKey(text); ok {
+		return p.parseMap(n)
+	}
+	p.pos++
+	return parseInline(text)
+}
+
+func (p *yamlParser) parseSeq(indent int) ([]any, error) {
+	out := []any{}
+	for {
+		p.skipBlank()
+		if p.pos >= len(p.lines) {
+			return out, nil
+		}
+		line := p.lines[p.pos]
+		text := strings.TrimSpace(line)
+		if indentOf(line) != indent || !isSeqItem(text) {
+			return out, nil
+		}
+		item := strings.TrimSpace(strings.TrimPrefix(text, "-"))
+		switch {
+		case item == "":
+			p.pos++
+			v, err := p.parseBlock(indent + 1)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		case isSeqItem(item):
+			// A nested sequence starting on the item's line.
+			p.lines[p.pos] = strings.Repeat(" ", indent+2) + item
+			v, err := p.parseSeq(indent + 2)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		default:
+			if _, _, ok := splitMapKey(item); ok && !strings.HasPrefix(item, "[") && !strings.HasPrefix(item, "{") {
+				// "- key: value" starts a mapping indented to the key.
+				p.lines[p.pos] = strings.Repeat(" ", indent+2) + item
+				v, err := p.parseMap(indent + 2)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, v)
+				continue
+			}
+			v, err := p.parseValue(item, indent)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+	}
+}
+
+func (p *yamlParser) parseMap(indent int) (map[string]any, error) {
+	out := map[string]any{}
+	for {
+		p.skipBlank()
+		if p.pos >= len(p.lines) {
+			return out, nil
+		}
+		line := p.lines[p.pos]
+		text := strings.TrimSpace(line)
+		if indentOf(line) != indent || isSeqItem(text) {
+			return out, nil
+		}
+		key, rest, ok := splitMapKey(text)
+		if !ok {
+			return nil, p.errorf("expected key: value, got %q", text)
+		}
+		if rest == "" {
+			p.pos++
+			p.skipBlank()
+			var v any = ""
+			if p.pos < len(p.lines) {
+				next := p.lines[p.pos]
+				n := indentOf(next)
+				if n > indent || (n == indent && isSeqItem(strings.TrimSpace(next))) {
+					var err error
+					if v, err = p.parseBlock(n); err != nil {
+						return nil, err
+					}
+				}
+			}
+			out[key] = v
+			continue
+		}
+		v, err := p.parseValue(rest, indent)
+		if err != nil {
+			return nil, err
+		}
+		out[key] = v
+	}
+}
+
+// parseValue parses the value after "key:" or "- " on the current line,
+// consuming the line and, for block scalars, the lines that belong to it.
+func (p *yamlParser) parseValue(text string, indent int) (any, error) {
+	if strings.HasPrefix(text, "|") || strings.HasPrefix(text, ">") {
+		header := strings.TrimSpace(stripComment(text))
+		p.pos++
+		return p.parseBlockScalar(header, indent), nil
+	}
+	p.pos++
+	return parseInline(text)
+}
+
+// parseBlockScalar reads the lines of a | or > scalar: every following line
+// indented deeper than the parent, and the blank lines between them.
+func (p *yamlParser) parseBlockScalar(header string, parent int) string {
+	folded := header[0] == '>'
+	chomp := byte(0)
+	if strings.Contains(header, "-") {
+		chomp = '-'
+	} else if strings.Contains(header, "+") {
+		chomp = '+'
+	}
+	var lines []string
+	contentIndent := -1
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if strings.TrimSpace(line) == "" {
+			lines = append(lines, "")
+			p.pos++
+			continue
+		}
+		n := indentOf(line)
+		if n <= parent {
+			break
+		}
+		if contentIndent < 0 {
+			contentIndent = n
+		}
+		if n < contentIndent {
+			break
+		}
+		lines = append(lines, line[contentIndent:])
+		p.pos++
+	}
+	// Trailing blank lines belong to the scalar only for chomping.
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+	var body string
+	if folded {
+		var b strings.Builder
+		for i, l := range lines {
+			switch {
+			case i == 0:
+			case l == "" || lines[i-1] == "":
+				b.WriteByte('\n')
+			case strings.HasPrefix(l, " "):
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(' ')
+			}
+			b.WriteString(l)
+		}
+		body = b.String()
+	} else {
+		body = strings.Join(lines, "\n")
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	switch chomp {
+	case '-':
+		return body
+	case '+':
+		return body + strings.Repeat("\n", trailing+1)
+	default:
+		return body + "\n"
+	}
+}
+
+// splitMapKey splits "key: value" or "key:" outside quotes.
+func splitMapKey(text string) (string, string, bool) {
+	inSingle, inDouble := false, false
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '\'' && !inDouble:
+			inSingle = !inSingle
+		case c == '"' && !inSingle:
+			inDouble = !inDouble
+		case c == '\\' && inDouble:
+			i++
+		case c == ':' && !inSingle && !inDouble && (i == len(text)-1 || text[i+1] == ' '):
+			key := strings.TrimSpace(text[:i])
+			if key == "" || strings.HasPrefix(key, "#") {
+				return "", "", false
+			}
+			if unq, err := parseInline(key); err == nil {
+				if s, ok := unq.(string); ok {
+					key = s
+				}
+			}
+			return key, strings.TrimSpace(text[i+1:]), true
+		case c == '#' && !inSingle && !inDouble && i > 0 && text[i-1] == ' ':
+			return "", "", false
+		}
+	}
+	return "", "", false
+}
+
+// stripComment drops a trailing " # comment" outside quotes.
+func stripComment(text string) string {
+	inSingle, inDouble := false, false
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '\'' && !inDouble:
+			inSingle = !inSingle
+		case c == '"' && !inSingle:
+			inDouble = !inDouble
+		case c == '\\' && inDouble:
+			i++
+		case c == '#' && !inSingle && !inDouble && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return strings.TrimSpace(text[:i])
+		}
+	}
+	return strings.TrimSpace(text)
+}
+
+// parseInline parses a scalar or flow collection written on one line.
+func parseInline(text string) (any, error) {
+	text = stripComment(text)
+	switch {
+	case strings.HasPrefix(text, "["):
+		if !strings.HasSuffix(text, "]") {
+			return nil, fmt.Errorf("unterminated flow sequence %q", text)
+		}
+		items, err := splitFlow(text[1 : len(text)-1])
+		if err != nil {
+			return nil, err
+		}
+		out := []any{}
+		for _, item := range items {
+			v, err := parseInline(item)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case strings.HasPrefix(text, "{"):
+		if !strings.HasSuffix(text, "}") {
+			return nil, fmt.Errorf("unterminated flow mapping %q", text)
+		}
+		items, err := splitFlow(text[1 : len(text)-1])
+		if err != nil {
+			return nil, err
+		}
+		out := map[string]any{}
+		for _, item := range items {
+			key, rest, ok := splitMapKey(item)
+			if !ok {
+				return nil, fmt.Errorf("invalid flow mapping entry %q", item)
+			}
+			v, err := parseInline(rest)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = v
+		}
+		return out, nil
+	case strings.HasPrefix(text, `"`):
+		return unquoteDouble(text)
+	case strings.HasPrefix(text, "'"):
+		if len(text) < 2 || !strings.HasSuffix(text, "'") {
+			return nil, fmt.Errorf("unterminated string %q", text)
+		}
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	}
+	return text, nil
+}
+
+// splitFlow splits the inside of a flow collection on top-level commas.
+func splitFlow(s string) ([]string, error) {
+	var out []string
+	depth, start := 0, 0
+	inSingle, inDouble := false, false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\'' && !inDouble:
+			inSingle = !inSingle
+		case c == '"' && !inSingle:
+			inDouble = !inDouble
+		case c == '\\' && inDouble:
+			i++
+		case inSingle || inDouble:
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			out = append(out, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if inSingle || inDouble || depth != 0 {
+		return nil, fmt.Errorf("unbalanced flow collection %q", s)
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		out = append(out, last)
+	}
+	return out, nil
+}
+
+func unquoteDouble(text string) (string, error) {
+	var b strings.Builder
+	for i := 1; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '"':
+			if strings.TrimSpace(text[i+1:]) != "" {
+				return "", fmt.Errorf("unexpected text after string %q", text)
+			}
+			return b.String(), nil
+		case c == '\\' && i+1 < len(text):
+			i++
+			switch text[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(text[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated string %q", text)
+}
@@ -0,0 +1,92 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/myuon/track/internal/store/sqlite"
+	trackv1 "github.com/myuon/track/proto/trackv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// methodRoles is the role each RPC needs once API tokens exist, as the HTTP
+// API requires them. Methods missing here need an admin token.
+var methodRoles = map[string]string{
+	trackv1.TrackService_ListIssues_FullMethodName:   sqlite.RoleRead,
+	trackv1.TrackService_GetIssue_FullMethodName:     sqlite.RoleRead,
+	trackv1.TrackService_ListProjects_FullMethodName: sqlite.RoleRead,
+	trackv1.TrackService_WatchEvents_FullMethodName:  sqlite.RoleRead,
+	trackv1.TrackService_UpdateIssue_FullMethodName:  sqlite.RoleContributor,
+}
+
+type tokenCtxKey struct{}
+
+func unaryAuth(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func streamAuth(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authedStream carries the caller's token on the stream's context.
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authedStream) Context() context.Context { return s.ctx }
+
+// authorize checks the "authorization: Bearer <token>" metadata of a call to
+// method with the same tokens and roles as the HTTP API, and returns ctx
+// with the caller's token on it.
+func authorize(ctx context.Context, method string) (context.Context, error) {
+	required, ok := methodRoles[method]
+	if !ok {
+		required = sqlite.RoleAdmin
+	}
+	var secret string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) > 0 {
+			secret, _ = strings.CutPrefix(v[0], "Bearer ")
+		}
+	}
+
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	defer store.Close()
+
+	tok, ok, err := store.AuthorizeAPIToken(ctx, secret, required)
+	var forbidden *sqlite.ForbiddenError
+	switch {
+	case errors.Is(err, sqlite.ErrTokenRequired) || errors.Is(err, sqlite.ErrTokenInvalid):
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	case errors.As(err, &forbidden):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	if ok {
+		ctx = context.WithValue(ctx, tokenCtxKey{}, tok)
+	}
+	return ctx, nil
+}
+
+func callerToken(ctx context.Context) (sqlite.APIToken, bool) {
+	tok, ok := ctx.Value(tokenCtxKey{}).(sqlite.APIToken)
+	return tok, ok
+}
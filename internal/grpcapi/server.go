@@ -0,0 +1,273 @@
+// Package grpcapi serves the TrackService defined in proto/trackv1.
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/service"
+	"github.com/myuon/track/internal/store/sqlite"
+	trackv1 "github.com/myuon/track/proto/trackv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const defaultPollInterval = time.Second
+
+type Server struct {
+	trackv1.UnimplementedTrackServiceServer
+
+	// PollInterval controls how often WatchEvents checks the store for
+	// changes made by other processes.
+	PollInterval time.Duration
+}
+
+func NewServer() *grpc.Server {
+	return newGRPCServer(&Server{PollInterval: defaultPollInterval})
+}
+
+// newGRPCServer serves impl behind the API token checks.
+func newGRPCServer(impl *Server) *grpc.Server {
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(unaryAuth), grpc.ChainStreamInterceptor(streamAuth))
+	trackv1.RegisterTrackServiceServer(s, impl)
+	return s
+}
+
+func (s *Server) ListIssues(ctx context.Context, req *trackv1.ListIssuesRequest) (*trackv1.ListIssuesResponse, error) {
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	defer store.Close()
+
+	for _, st := range req.GetStatuses() {
+		if err := store.ValidateStatus(ctx, st); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	items, err := store.ListIssues(ctx, sqlite.ListFilter{
+		Statuses:        req.GetStatuses(),
+		ExcludeDone:     len(req.GetStatuses()) == 0,
+		ExcludeArchived: len(req.GetStatuses()) == 0,
+		Label:           strings.TrimSpace(req.GetLabel()),
+		Assignee:        issue.NormalizeAssignee(req.GetAssignee()),
+		Search:          strings.TrimSpace(req.GetSearch()),
+		Project:         strings.TrimSpace(req.GetProject()),
+		Sort:            strings.TrimSpace(req.GetSort()),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	resp := &trackv1.ListIssuesResponse{Items: make([]*trackv1.Issue, 0, len(items))}
+	for _, it := range items {
+		resp.Items = append(resp.Items, toIssue(it, ""))
+	}
+	return resp, nil
+}
+
+func (s *Server) GetIssue(ctx context.Context, req *trackv1.GetIssueRequest) (*trackv1.Issue, error) {
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	defer store.Close()
+
+	it, err := store.GetIssue(ctx, normalizeIssueID(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return withProject(ctx, store, it)
+}
+
+func (s *Server) UpdateIssue(ctx context.Context, req *trackv1.UpdateIssueRequest) (*trackv1.Issue, error) {
+	actor := "grpc"
+	tok, authed := callerToken(ctx)
+	if authed {
+		actor += ":" + tok.Name
+	}
+	ctx = sqlite.WithActor(ctx, actor)
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	defer store.Close()
+
+	id := normalizeIssueID(req.GetId())
+	if authed && req.Status != nil {
+		current, err := store.GetIssue(ctx, id)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		if !tok.MayChangeStatus(current.Status, *req.Status) {
+			return nil, status.Error(codes.PermissionDenied, "contributor tokens cannot move issues from "+current.Status+" to "+*req.Status)
+		}
+	}
+
+	u := service.Update{
+		UpdateIssueInput: sqlite.UpdateIssueInput{
+			Title:      req.Title,
+			Body:       req.Body,
+			Status:     req.Status,
+			Priority:   req.Priority,
+			Assignee:   req.Assignee,
+			Due:        req.Due,
+			NextAction: req.NextAction,
+		},
+		Project: req.Project,
+	}
+	if req.Labels != nil {
+		labels := append([]string{}, req.Labels.GetValues()...)
+		u.Labels = &labels
+	}
+	updated, err := service.UpdateIssue(ctx, store, id, u)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return withProject(ctx, store, updated)
+}
+
+func (s *Server) ListProjects(ctx context.Context, _ *trackv1.ListProjectsRequest) (*trackv1.ListProjectsResponse, error) {
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	defer store.Close()
+
+	projects, err := store.ListProjects(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	resp := &trackv1.ListProjectsResponse{Items: make([]*trackv1.Project, 0, len(projects))}
+	for _, p := range projects {
+		resp.Items = append(resp.Items, &trackv1.Project{
+			Key:         p.Key,
+			Name:        p.Name,
+			Description: p.Description,
+			IssueCount:  int32(p.IssueCount),
+			CreatedAt:   p.CreatedAt,
+			UpdatedAt:   p.UpdatedAt,
+		})
+	}
+	return resp, nil
+}
+
+// WatchEvents polls updated_at so that changes from every process sharing the
+// database are streamed, not just those made through this server.
+func (s *Server) WatchEvents(_ *trackv1.WatchEventsRequest, stream trackv1.TrackService_WatchEventsServer) error {
+	ctx := stream.Context()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return status.Error(codes.Internal, "internal error")
+	}
+	defer store.Close()
+
+	seen, err := snapshot(ctx, store)
+	if err != nil {
+		return status.Error(codes.Internal, "internal error")
+	}
+	interval := s.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		items, err := store.ListIssues(ctx, sqlite.ListFilter{})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return status.Error(codes.Internal, "internal error")
+		}
+		for _, it := range items {
+			prev, known := seen[it.ID]
+			if known && prev == it.UpdatedAt {
+				continue
+			}
+			seen[it.ID] = it.UpdatedAt
+			eventType := "issue.updated"
+			if !known {
+				eventType = "issue.created"
+			}
+			msg, err := withProject(ctx, store, it)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(&trackv1.Event{Type: eventType, Issue: msg}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func snapshot(ctx context.Context, store *sqlite.Store) (map[string]string, error) {
+	items, err := store.ListIssues(ctx, sqlite.ListFilter{})
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]string, len(items))
+	for _, it := range items {
+		seen[it.ID] = it.UpdatedAt
+	}
+	return seen, nil
+}
+
+func withProject(ctx context.Context, store *sqlite.Store, it issue.Item) (*trackv1.Issue, error) {
+	project, err := store.GetIssueProject(ctx, it.ID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	return toIssue(it, project), nil
+}
+
+func toIssue(it issue.Item, project string) *trackv1.Issue {
+	return &trackv1.Issue{
+		Id:         it.ID,
+		Title:      it.Title,
+		Status:     it.Status,
+		Priority:   it.Priority,
+		Assignee:   it.Assignee,
+		Due:        it.Due,
+		Labels:     it.Labels,
+		NextAction: it.NextAction,
+		Body:       it.Body,
+		Project:    project,
+		CreatedAt:  it.CreatedAt,
+		UpdatedAt:  it.UpdatedAt,
+	}
+}
+
+// toStatus mirrors the REST error mapping: unknown issues are NotFound,
+// failed hooks are Internal (the change was saved), and the rest are
+// validation errors.
+func toStatus(err error) error {
+	var hookErr *service.HookError
+	switch {
+	case errors.As(err, &hookErr):
+		return status.Error(codes.Internal, err.Error())
+	case errors.Is(err, sql.ErrNoRows) || strings.Contains(strings.ToLower(err.Error()), "issue not found"):
+		return status.Error(codes.NotFound, "issue not found")
+	default:
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+}
+
+func normalizeIssueID(raw string) string {
+	id := strings.TrimSpace(raw)
+	if _, err := strconv.Atoi(id); err == nil {
+		return "TRK-" + id
+	}
+	return id
+}
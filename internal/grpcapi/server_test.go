@@ -0,0 +1,205 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+	trackv1 "github.com/myuon/track/proto/trackv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T) (trackv1.TrackServiceClient, *sqlite.Store) {
+	t.Helper()
+	t.Setenv("TRACK_HOME", t.TempDir())
+	store, err := sqlite.Open(context.Background())
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	lis := bufconn.Listen(1 << 20)
+	srv := newGRPCServer(&Server{PollInterval: 10 * time.Millisecond})
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return trackv1.NewTrackServiceClient(conn), store
+}
+
+func TestIssueRPCs(t *testing.T) {
+	client, store := newTestClient(t)
+	ctx := context.Background()
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "grpc", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	if _, err := store.CreateIssue(ctx, issue.Item{Title: "done", Status: issue.StatusDone, Priority: "p2"}); err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	if _, err := store.CreateProject(ctx, "web", "Web", ""); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+
+	list, err := client.ListIssues(ctx, &trackv1.ListIssuesRequest{})
+	if err != nil {
+		t.Fatalf("ListIssues() error: %v", err)
+	}
+	if len(list.GetItems()) != 1 || list.GetItems()[0].GetId() != it.ID {
+		t.Fatalf("unexpected list: %v", list.GetItems())
+	}
+
+	statusReady := issue.StatusReady
+	project := "web"
+	updated, err := client.UpdateIssue(ctx, &trackv1.UpdateIssueRequest{
+		Id:      "1",
+		Status:  &statusReady,
+		Labels:  &trackv1.LabelSet{Values: []string{"editor"}},
+		Project: &project,
+	})
+	if err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+	if updated.GetStatus() != issue.StatusReady || updated.GetProject() != "web" || len(updated.GetLabels()) != 1 {
+		t.Fatalf("unexpected update result: %v", updated)
+	}
+
+	projects, err := client.ListProjects(ctx, &trackv1.ListProjectsRequest{})
+	if err != nil || len(projects.GetItems()) != 1 || projects.GetItems()[0].GetIssueCount() != 1 {
+		t.Fatalf("ListProjects() = %v, err %v", projects.GetItems(), err)
+	}
+
+	_, err = client.GetIssue(ctx, &trackv1.GetIssueRequest{Id: "TRK-999"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("GetIssue(missing) code = %v, want NotFound", status.Code(err))
+	}
+	bad := "p9"
+	_, err = client.UpdateIssue(ctx, &trackv1.UpdateIssueRequest{Id: it.ID, Priority: &bad})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("UpdateIssue(bad priority) code = %v, want InvalidArgument", status.Code(err))
+	}
+}
+
+func TestWatchEventsStreamsChanges(t *testing.T) {
+	client, store := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchEvents(ctx, &trackv1.WatchEventsRequest{})
+	if err != nil {
+		t.Fatalf("WatchEvents() error: %v", err)
+	}
+	// Give the server a moment to take its initial snapshot.
+	time.Sleep(50 * time.Millisecond)
+
+	it, err := store.CreateIssue(context.Background(), issue.Item{Title: "streamed", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	ev, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv() error: %v", err)
+	}
+	if ev.GetType() != "issue.created" || ev.GetIssue().GetId() != it.ID {
+		t.Fatalf("unexpected event: %v", ev)
+	}
+}
+
+func TestRPCsRequireAPITokens(t *testing.T) {
+	client, store := newTestClient(t)
+	ctx := context.Background()
+
+	for _, st := range []string{issue.StatusTodo, issue.StatusDone} {
+		if _, err := store.CreateIssue(ctx, issue.Item{Title: "t", Status: st, Priority: "p2"}); err != nil {
+			t.Fatalf("create issue: %v", err)
+		}
+	}
+	tokens := map[string]string{}
+	for _, role := range sqlite.Roles {
+		secret, err := store.CreateAPIToken(ctx, role+"-bot", role)
+		if err != nil {
+			t.Fatalf("create token: %v", err)
+		}
+		tokens[role] = secret
+	}
+	as := func(token string) context.Context {
+		if token == "" {
+			return ctx
+		}
+		return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	}
+	ready, todo := issue.StatusReady, issue.StatusTodo
+
+	cases := []struct {
+		name  string
+		token string
+		call  func(ctx context.Context) error
+		want  codes.Code
+	}{
+		{"missing token", "", func(ctx context.Context) error {
+			_, err := client.ListIssues(ctx, &trackv1.ListIssuesRequest{})
+			return err
+		}, codes.Unauthenticated},
+		{"unknown token", "trk_nope", func(ctx context.Context) error {
+			_, err := client.GetIssue(ctx, &trackv1.GetIssueRequest{Id: "1"})
+			return err
+		}, codes.Unauthenticated},
+		{"read can list", tokens[sqlite.RoleRead], func(ctx context.Context) error {
+			_, err := client.ListIssues(ctx, &trackv1.ListIssuesRequest{})
+			return err
+		}, codes.OK},
+		{"read cannot update", tokens[sqlite.RoleRead], func(ctx context.Context) error {
+			_, err := client.UpdateIssue(ctx, &trackv1.UpdateIssueRequest{Id: "1", Status: &ready})
+			return err
+		}, codes.PermissionDenied},
+		{"contributor can update", tokens[sqlite.RoleContributor], func(ctx context.Context) error {
+			_, err := client.UpdateIssue(ctx, &trackv1.UpdateIssueRequest{Id: "1", Status: &ready})
+			return err
+		}, codes.OK},
+		{"contributor cannot reopen", tokens[sqlite.RoleContributor], func(ctx context.Context) error {
+			_, err := client.UpdateIssue(ctx, &trackv1.UpdateIssueRequest{Id: "2", Status: &todo})
+			return err
+		}, codes.PermissionDenied},
+		{"admin can reopen", tokens[sqlite.RoleAdmin], func(ctx context.Context) error {
+			_, err := client.UpdateIssue(ctx, &trackv1.UpdateIssueRequest{Id: "2", Status: &todo})
+			return err
+		}, codes.OK},
+		{"watch needs a token", "", func(ctx context.Context) error {
+			stream, err := client.WatchEvents(ctx, &trackv1.WatchEventsRequest{})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		}, codes.Unauthenticated},
+	}
+	for _, tc := range cases {
+		if got := status.Code(tc.call(as(tc.token))); got != tc.want {
+			t.Fatalf("%s: code = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	entries, err := store.ListActivity(ctx, "", 0)
+	if err != nil {
+		t.Fatalf("ListActivity() error: %v", err)
+	}
+	if last := entries[len(entries)-1]; last.Actor != "grpc:admin-bot" {
+		t.Fatalf("last activity actor = %q, want grpc:admin-bot", last.Actor)
+	}
+}
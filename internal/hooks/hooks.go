@@ -4,17 +4,22 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
+	"strings"
 
-	"github.com/google/shlex"
+	"github.com/myuon/track/internal/automation"
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/notify"
+	"github.com/myuon/track/internal/platform"
+	"github.com/myuon/track/internal/script"
+	"github.com/myuon/track/internal/secret"
 	"github.com/myuon/track/internal/store/sqlite"
 )
 
 const (
-	IssueCreated      = "issue.created"
-	IssueUpdated      = "issue.updated"
-	IssueStatusChange = "issue.status_changed"
-	IssueCompleted    = "issue.completed"
+	IssueCreated      = sqlite.EventIssueCreated
+	IssueUpdated      = sqlite.EventIssueUpdated
+	IssueStatusChange = sqlite.EventIssueStatusChange
+	IssueCompleted    = sqlite.EventIssueCompleted
 	SyncCompleted     = "sync.completed"
 )
 
@@ -33,37 +38,115 @@ func ValidateEvent(event string) error {
 	return nil
 }
 
+// Hooks subscribe to the store's event bus, so every issue mutation in a
+// process that links this package runs automations and hooks.
+func init() {
+	sqlite.Subscribe(func(ctx context.Context, ev sqlite.Event) error {
+		return RunEvent(ctx, ev.Store, ev.Type, ev.IssueID)
+	})
+}
+
 func RunEvent(ctx context.Context, store *sqlite.Store, event, issueID string) error {
+	return runEvent(ctx, store, event, issueID, true)
+}
+
+// runEvent runs built-in automations and scripts (when automate is set)
+// followed by the registered hooks. Automations and scripts write without
+// publishing; their changes are announced with a single issue.updated event
+// that does not re-run them.
+// While automation is frozen, neither runs.
+func runEvent(ctx context.Context, store *sqlite.Store, event, issueID string, automate bool) error {
 	if err := ValidateEvent(event); err != nil {
 		return err
 	}
+	if _, frozen, err := store.AutomationFrozen(ctx); err != nil || frozen {
+		return err
+	}
+	automated := false
+	if automate {
+		var err error
+		if automated, err = automation.Apply(sqlite.WithoutEvents(ctx), store, event, issueID); err != nil {
+			return err
+		}
+		scripted, err := script.Run(sqlite.WithoutEvents(ctx), store, event, issueID)
+		if err != nil {
+			return err
+		}
+		automated = automated || scripted
+	}
 	hooks, err := store.ListHooks(ctx, event)
 	if err != nil {
 		return err
 	}
 	for _, h := range hooks {
-		if err := runOne(ctx, h, event, issueID); err != nil {
+		if !h.Enabled {
+			continue
+		}
+		if !h.Approved {
+			fmt.Fprintf(os.Stderr, "warning: hook(%d) skipped: its command is not approved (see track hook approve %d)\n", h.ID, h.ID)
+			continue
+		}
+		runErr := runOne(ctx, h, event, issueID)
+		if err := recordRun(ctx, store, h, event, issueID, runErr); err != nil {
 			return err
 		}
+		if runErr != nil {
+			return runErr
+		}
+	}
+	if automated {
+		return runEvent(ctx, store, IssueUpdated, issueID, false)
 	}
 	return nil
 }
 
-func runOne(ctx context.Context, h sqlite.Hook, event, issueID string) error {
-	parts, err := shlex.Split(h.RunCmd)
+// recordRun stores the outcome of a hook run in hook_runs and, when
+// notify_hook_failures is enabled, sends a notification for failures.
+func recordRun(ctx context.Context, store *sqlite.Store, h sqlite.Hook, event, issueID string, runErr error) error {
+	run := sqlite.HookRun{HookID: h.ID, Event: event, IssueID: issueID, Success: runErr == nil}
+	if runErr != nil {
+		run.Error = runErr.Error()
+	}
+	if _, err := store.AddHookRun(ctx, run); err != nil {
+		return err
+	}
+	if runErr == nil {
+		return nil
+	}
+
+	cfg, err := appconfig.Load()
 	if err != nil {
-		return fmt.Errorf("parse hook command: %w", err)
+		return err
+	}
+	if !cfg.NotifyHookFailures {
+		return nil
+	}
+	if err := notify.Send(ctx, notify.Message{
+		IssueID: issueID,
+		Title:   fmt.Sprintf("hook(%d) failed on %s", h.ID, event),
+		Body:    run.Error,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
 	}
-	if len(parts) == 0 {
+	return nil
+}
+
+func runOne(ctx context.Context, h sqlite.Hook, event, issueID string) error {
+	if strings.TrimSpace(h.RunCmd) == "" {
 		return fmt.Errorf("empty hook command")
 	}
-	cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
-	if h.CWD != "" {
-		cmd.Dir = h.CWD
+	runCmd, err := secret.Expand(h.RunCmd)
+	if err != nil {
+		return fmt.Errorf("hook(%d): %w", h.ID, err)
+	}
+	cmd, err := platform.Command(ctx, runCmd)
+	if err != nil {
+		return fmt.Errorf("parse hook command: %w", err)
 	}
+	cmd.Dir = platform.NormalizePath(h.CWD)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	cmd.Env = append(os.Environ(), "TRACK_EVENT="+event, "TRACK_ISSUE_ID="+issueID)
+	cmd.Env = append(os.Environ(), "TRACK_EVENT="+event, "TRACK_ISSUE_ID="+issueID, fmt.Sprintf("%s=hook:%d", sqlite.ActorEnv, h.ID))
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("hook(%d) failed: %w", h.ID, err)
 	}
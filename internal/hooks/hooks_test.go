@@ -5,13 +5,17 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/secret"
 	"github.com/myuon/track/internal/store/sqlite"
 )
 
 func TestRunEventExecutesHook(t *testing.T) {
+	requireSh(t)
 	tmp := t.TempDir()
 	t.Setenv("TRACK_HOME", tmp)
 
@@ -41,7 +45,262 @@ func TestRunEventExecutesHook(t *testing.T) {
 	}
 }
 
+func TestRunEventSkipsEverythingWhileFrozen(t *testing.T) {
+	requireSh(t)
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	outFile := filepath.Join(tmp, "hook.out")
+	if err := store.AddHook(ctx, IssueCompleted, "/bin/sh -c 'echo ran >> "+outFile+"'", ""); err != nil {
+		t.Fatalf("add hook: %v", err)
+	}
+	f, err := store.FreezeAutomation(ctx, "migrating")
+	if err != nil {
+		t.Fatalf("freeze: %v", err)
+	}
+	if again, err := store.FreezeAutomation(ctx, "other"); err != nil || again != f {
+		t.Fatalf("second freeze = %+v, %v; want the first %+v kept", again, err, f)
+	}
+
+	if err := RunEvent(ctx, store, IssueCompleted, "TRK-1"); err != nil {
+		t.Fatalf("run event: %v", err)
+	}
+	if _, err := os.Stat(outFile); !os.IsNotExist(err) {
+		t.Fatalf("hook ran while frozen: %v", err)
+	}
+
+	if thawed, err := store.ThawAutomation(ctx); err != nil || !thawed {
+		t.Fatalf("thaw = %v, %v", thawed, err)
+	}
+	if err := RunEvent(ctx, store, IssueCompleted, "TRK-1"); err != nil {
+		t.Fatalf("run event: %v", err)
+	}
+	if _, err := os.Stat(outFile); err != nil {
+		t.Fatalf("hook should run after thaw: %v", err)
+	}
+}
+
+func TestRunEventSkipsDisabledHooksAndFollowsOrder(t *testing.T) {
+	requireSh(t)
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	outFile := filepath.Join(tmp, "hook.out")
+	for _, name := range []string{"a", "b", "c"} {
+		cmd := "/bin/sh -c 'echo " + name + " >> " + outFile + "'"
+		if err := store.AddHook(ctx, IssueCreated, cmd, ""); err != nil {
+			t.Fatalf("add hook: %v", err)
+		}
+	}
+	if err := store.SetHookOrder(ctx, 1, 10); err != nil {
+		t.Fatalf("set order: %v", err)
+	}
+	if err := store.SetHookEnabled(ctx, 2, false); err != nil {
+		t.Fatalf("disable hook: %v", err)
+	}
+
+	if err := RunEvent(ctx, store, IssueCreated, "TRK-1"); err != nil {
+		t.Fatalf("run event: %v", err)
+	}
+
+	raw, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("read hook output: %v", err)
+	}
+	if string(raw) != "c\na\n" {
+		t.Fatalf("unexpected hook output: %q", string(raw))
+	}
+}
+
+func TestRunEventSkipsUnapprovedHooks(t *testing.T) {
+	requireSh(t)
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	outFile := filepath.Join(tmp, "hook.out")
+	if err := store.AddHook(ctx, IssueCreated, "/bin/sh -c 'echo ran >> "+outFile+"'", ""); err != nil {
+		t.Fatalf("add hook: %v", err)
+	}
+	// Without the approvals file the database looks like one copied from
+	// another machine.
+	allow, err := sqlite.HookApprovalsPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(allow); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RunEvent(ctx, store, IssueCreated, "TRK-1"); err != nil {
+		t.Fatalf("run event: %v", err)
+	}
+	if _, err := os.Stat(outFile); !os.IsNotExist(err) {
+		t.Fatalf("unapproved hook ran: %v", err)
+	}
+
+	if _, err := store.ApproveHook(ctx, 1); err != nil {
+		t.Fatalf("approve hook: %v", err)
+	}
+	if err := RunEvent(ctx, store, IssueCreated, "TRK-1"); err != nil {
+		t.Fatalf("run event: %v", err)
+	}
+	if raw, err := os.ReadFile(outFile); err != nil || string(raw) != "ran\n" {
+		t.Fatalf("approved hook output = %q, %v", raw, err)
+	}
+}
+
+func TestRunEventSkipsHooksOfACopiedDatabase(t *testing.T) {
+	requireSh(t)
+	src := t.TempDir()
+	t.Setenv("TRACK_HOME", src)
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	marker := filepath.Join(t.TempDir(), "ran")
+	if err := store.AddHook(ctx, IssueCreated, "/bin/sh -c 'touch "+marker+"'", ""); err != nil {
+		t.Fatalf("add hook: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("close store: %v", err)
+	}
+
+	// The database arrives in a fresh TRACK_HOME, as on a new machine or a
+	// restored backup, without the hooks.allow it was approved in.
+	raw, err := os.ReadFile(filepath.Join(src, "track.db"))
+	if err != nil {
+		t.Fatalf("read database: %v", err)
+	}
+	dst := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dst, "track.db"), raw, 0o600); err != nil {
+		t.Fatalf("copy database: %v", err)
+	}
+	t.Setenv("TRACK_HOME", dst)
+	copied, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open copied store: %v", err)
+	}
+	t.Cleanup(func() { _ = copied.Close() })
+
+	if h, err := copied.GetHook(ctx, 1); err != nil || h.Approved {
+		t.Fatalf("copied hook = %+v, %v; want unapproved", h, err)
+	}
+	if err := RunEvent(ctx, copied, IssueCreated, "TRK-1"); err != nil {
+		t.Fatalf("run event: %v", err)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Fatalf("hook of a copied database ran: %v", err)
+	}
+}
+
+func TestRunEventExpandsSecretReferences(t *testing.T) {
+	requireSh(t)
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+	cfg := appconfig.Default()
+	cfg.SecretStore = secret.StoreFile
+	if err := appconfig.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := secret.Set("token", "s3cret"); err != nil {
+		t.Fatalf("set secret: %v", err)
+	}
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	outFile := filepath.Join(tmp, "hook.out")
+	if err := store.AddHook(ctx, IssueCreated, "/bin/sh -c 'echo {{secret:token}} >> "+outFile+"'", ""); err != nil {
+		t.Fatalf("add hook: %v", err)
+	}
+	if err := RunEvent(ctx, store, IssueCreated, "TRK-1"); err != nil {
+		t.Fatalf("run event: %v", err)
+	}
+	if raw, err := os.ReadFile(outFile); err != nil || string(raw) != "s3cret\n" {
+		t.Fatalf("hook output = %q, %v", raw, err)
+	}
+
+	if err := store.AddHook(ctx, IssueUpdated, "echo {{secret:missing}}", ""); err != nil {
+		t.Fatalf("add hook: %v", err)
+	}
+	if err := RunEvent(ctx, store, IssueUpdated, "TRK-1"); err == nil || !strings.Contains(err.Error(), "unknown secret: missing") {
+		t.Fatalf("expected unknown secret error, got %v", err)
+	}
+}
+
+func TestRunEventRecordsFailureAndNotifies(t *testing.T) {
+	requireSh(t)
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	notified := filepath.Join(tmp, "notified")
+	cfg := appconfig.Default()
+	cfg.NotifyCmd = "/bin/sh -c 'echo \"$TRACK_NOTIFY_TITLE\" > " + notified + "'"
+	cfg.NotifyHookFailures = true
+	if err := appconfig.Save(cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	if err := store.AddHook(ctx, IssueUpdated, "/bin/sh -c 'exit 3'", ""); err != nil {
+		t.Fatalf("add hook: %v", err)
+	}
+	if err := RunEvent(ctx, store, IssueUpdated, "TRK-7"); err == nil {
+		t.Fatalf("expected hook failure")
+	}
+
+	failures, err := store.ListHookFailures(ctx, 0)
+	if err != nil {
+		t.Fatalf("list failures: %v", err)
+	}
+	if len(failures) != 1 || failures[0].IssueID != "TRK-7" || failures[0].Event != IssueUpdated || !strings.Contains(failures[0].Error, "exit status 3") {
+		t.Fatalf("unexpected failures: %+v", failures)
+	}
+
+	raw, err := os.ReadFile(notified)
+	if err != nil {
+		t.Fatalf("notification should be sent: %v", err)
+	}
+	if !strings.Contains(string(raw), "hook(1) failed on issue.updated") {
+		t.Fatalf("unexpected notification: %q", string(raw))
+	}
+}
+
 func TestAutoOrganizeOnCreatedScript(t *testing.T) {
+	requireSh(t)
 	scriptPath := filepath.Join("..", "..", "scripts", "hooks", "auto-organize-on-created.sh")
 	nextActionDefault := "planning: refine spec and decide ready/user"
 
@@ -169,3 +428,12 @@ func assertSetLogNotExists(t *testing.T, stateDir string) {
 		t.Fatalf("stat set.log: %v", err)
 	}
 }
+
+// requireSh skips tests whose hook commands are /bin/sh one-liners; the
+// Windows side of platform.Command is covered in its own package.
+func requireSh(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs /bin/sh")
+	}
+}
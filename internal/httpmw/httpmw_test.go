@@ -0,0 +1,112 @@
+package httpmw
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecoverReturnsJSON500(t *testing.T) {
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { panic("boom") }))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/x", nil))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Fatalf("content type = %q", ct)
+	}
+	if strings.TrimSpace(rr.Body.String()) != `{"error":"internal error"}` {
+		t.Fatalf("body = %q", rr.Body.String())
+	}
+}
+
+func TestLogRecordsStatusAndLatency(t *testing.T) {
+	var buf bytes.Buffer
+	orig := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(orig) })
+
+	h := Log(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/issues", nil))
+
+	line := buf.String()
+	for _, want := range []string{"method=POST", "path=/issues", "status=418", "duration_ms=", "client=192.0.2.1"} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("log line %q does not contain %q", line, want)
+		}
+	}
+}
+
+func TestRateLimiterRefills(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(2)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("a"); !ok {
+			t.Fatalf("request %d should be allowed", i+1)
+		}
+	}
+	ok, wait := l.Allow("a")
+	if ok || wait <= 0 || wait > 30*time.Second {
+		t.Fatalf("third request = %v, wait %v; want rejected with wait up to 30s", ok, wait)
+	}
+	if ok, _ := l.Allow("b"); !ok {
+		t.Fatalf("other keys have their own budget")
+	}
+
+	now = now.Add(30 * time.Second)
+	if ok, _ := l.Allow("a"); !ok {
+		t.Fatalf("budget should refill over time")
+	}
+	if ok, _ := NewRateLimiter(0).Allow("a"); !ok {
+		t.Fatalf("a zero limit disables limiting")
+	}
+}
+
+func TestRateLimiterDropsIdleBuckets(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(1)
+	l.now = func() time.Time { return now }
+
+	for _, key := range []string{"a", "b", "c"} {
+		l.Allow(key)
+	}
+	if ok, _ := l.Allow("a"); ok {
+		t.Fatal("a should be over budget")
+	}
+	l.Refund("a")
+	if ok, _ := l.Allow("a"); !ok {
+		t.Fatal("a refunded request should be allowed again")
+	}
+
+	now = now.Add(time.Minute)
+	l.Allow("d")
+	if len(l.buckets) != 1 {
+		t.Fatalf("buckets = %d after a minute idle, want only d's", len(l.buckets))
+	}
+}
+
+func TestLimitRejectsWith429(t *testing.T) {
+	l := NewRateLimiter(1)
+	h := Limit(l, ClientIP, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("first status = %d", rr.Code)
+	}
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rr.Code != http.StatusTooManyRequests || rr.Header().Get("Retry-After") == "" {
+		t.Fatalf("second status = %d, Retry-After = %q", rr.Code, rr.Header().Get("Retry-After"))
+	}
+}
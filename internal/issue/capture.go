@@ -0,0 +1,81 @@
+package issue
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Capture is a one-line issue as written for track capture.
+type Capture struct {
+	Title    string
+	Labels   []string
+	Priority string
+	Assignee string
+	Due      string
+}
+
+var captureWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// ParseCapture splits inline tokens out of text: #label, !p0-!p3, @assignee,
+// and due:<when>, where <when> is today, tomorrow, a weekday (the next one,
+// counting today), or anything --due accepts. The remaining words, in order,
+// are the title. Relative dates are resolved against now.
+func ParseCapture(text string, now time.Time) (Capture, error) {
+	var (
+		c     Capture
+		title []string
+	)
+	for _, word := range strings.Fields(text) {
+		switch {
+		case len(word) > 1 && word[0] == '#':
+			c.Labels = append(c.Labels, word[1:])
+		case len(word) > 1 && word[0] == '!':
+			if err := ValidatePriority(word[1:]); err != nil {
+				return Capture{}, err
+			}
+			c.Priority = word[1:]
+		case len(word) > 1 && word[0] == '@':
+			c.Assignee = word[1:]
+		case strings.HasPrefix(word, "due:") && len(word) > len("due:"):
+			due, err := captureDue(word[len("due:"):], now)
+			if err != nil {
+				return Capture{}, err
+			}
+			c.Due = due
+		default:
+			title = append(title, word)
+		}
+	}
+	c.Title = strings.Join(title, " ")
+	if err := ValidateTitle(c.Title); err != nil {
+		return Capture{}, err
+	}
+	return c, nil
+}
+
+func captureDue(v string, now time.Time) (string, error) {
+	switch lower := strings.ToLower(v); lower {
+	case "today":
+		return now.Format(DueDateLayout), nil
+	case "tomorrow":
+		return now.AddDate(0, 0, 1).Format(DueDateLayout), nil
+	default:
+		if day, ok := captureWeekdays[lower]; ok {
+			ahead := (int(day) - int(now.Weekday()) + 7) % 7
+			return now.AddDate(0, 0, ahead).Format(DueDateLayout), nil
+		}
+	}
+	if err := ValidateDue(v); err != nil {
+		return "", fmt.Errorf("invalid due (today, tomorrow, a weekday, or YYYY-MM-DD): %s", v)
+	}
+	return v, nil
+}
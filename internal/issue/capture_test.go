@@ -0,0 +1,34 @@
+package issue
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseCapture(t *testing.T) {
+	// 2026-03-04 is a Wednesday.
+	now := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	c, err := ParseCapture("fix flaky TestX #ci !p1 @agent due:fri", now)
+	if err != nil {
+		t.Fatalf("ParseCapture() error: %v", err)
+	}
+	if c.Title != "fix flaky TestX" || c.Priority != "p1" || c.Assignee != "agent" || c.Due != "2026-03-06" || !slices.Equal(c.Labels, []string{"ci"}) {
+		t.Fatalf("capture = %+v", c)
+	}
+
+	dues := map[string]string{"today": "2026-03-04", "tomorrow": "2026-03-05", "wed": "2026-03-04", "Monday": "2026-03-09", "2026-04-01": "2026-04-01"}
+	for in, want := range dues {
+		c, err := ParseCapture("x due:"+in, now)
+		if err != nil || c.Due != want {
+			t.Fatalf("due:%s = %q, %v; want %q", in, c.Due, err, want)
+		}
+	}
+
+	for _, bad := range []string{"#only !p1", "x !p9", "x due:someday"} {
+		if _, err := ParseCapture(bad, now); err == nil {
+			t.Fatalf("ParseCapture(%q) should fail", bad)
+		}
+	}
+}
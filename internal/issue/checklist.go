@@ -0,0 +1,93 @@
+package issue
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var checklistItemPattern = regexp.MustCompile(`^(\s*[-*+]\s+\[)([ xX])(\]\s+)(.*)$`)
+
+type ChecklistItem struct {
+	Index   int
+	Text    string
+	Checked bool
+	line    int
+}
+
+// AcceptanceCriteria returns the `- [ ]` / `- [x]` items found under the
+// first Acceptance Criteria heading, numbered from 1.
+func AcceptanceCriteria(body string) []ChecklistItem {
+	return acceptanceCriteriaItems(splitBodyLines(body))
+}
+
+// ChecklistProgress reports how many acceptance criteria are checked.
+func ChecklistProgress(body string) (done int, total int) {
+	for _, item := range AcceptanceCriteria(body) {
+		total++
+		if item.Checked {
+			done++
+		}
+	}
+	return done, total
+}
+
+// ToggleAcceptanceCriterion flips the checkbox of the n-th acceptance
+// criterion and returns the new body together with the updated item.
+func ToggleAcceptanceCriterion(body string, n int) (string, ChecklistItem, error) {
+	lines := splitBodyLines(body)
+	items := acceptanceCriteriaItems(lines)
+	if len(items) == 0 {
+		return "", ChecklistItem{}, fmt.Errorf("no acceptance criteria checklist")
+	}
+	if n < 1 || n > len(items) {
+		return "", ChecklistItem{}, fmt.Errorf("acceptance criterion out of range: %d (1-%d)", n, len(items))
+	}
+	item := items[n-1]
+	mark := "x"
+	if item.Checked {
+		mark = " "
+	}
+	m := checklistItemPattern.FindStringSubmatch(lines[item.line])
+	lines[item.line] = m[1] + mark + m[3] + m[4]
+	item.Checked = !item.Checked
+	return strings.Join(lines, "\n"), item, nil
+}
+
+func acceptanceCriteriaItems(lines []string) []ChecklistItem {
+	var span *sectionSpan
+	spans := sectionSpans(lines)
+	for i := range spans {
+		if strings.EqualFold(spans[i].Title, SectionAcceptanceCriteria) {
+			span = &spans[i]
+			break
+		}
+	}
+	if span == nil {
+		return nil
+	}
+
+	items := make([]ChecklistItem, 0)
+	inFence := false
+	for i := span.start + 1; i < span.end; i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		m := checklistItemPattern.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		items = append(items, ChecklistItem{
+			Index:   len(items) + 1,
+			Text:    strings.TrimSpace(m[4]),
+			Checked: m[2] != " ",
+			line:    i,
+		})
+	}
+	return items
+}
@@ -0,0 +1,49 @@
+package issue
+
+import (
+	"strings"
+	"testing"
+)
+
+const checklistBody = "## Spec\n- [ ] not a criterion\n\n### Acceptance Criteria\n- [ ] first\n- [x] second\n  * [ ] third\n\n## Notes\n- [ ] ignored\n"
+
+func TestAcceptanceCriteriaParsesOnlyCriteriaSection(t *testing.T) {
+	items := AcceptanceCriteria(checklistBody)
+	if len(items) != 3 {
+		t.Fatalf("items = %+v, want 3", items)
+	}
+	if items[0].Text != "first" || items[0].Checked || items[1].Text != "second" || !items[1].Checked || items[2].Index != 3 {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+	if done, total := ChecklistProgress(checklistBody); done != 1 || total != 3 {
+		t.Fatalf("progress = %d/%d, want 1/3", done, total)
+	}
+}
+
+func TestToggleAcceptanceCriterion(t *testing.T) {
+	body, item, err := ToggleAcceptanceCriterion(checklistBody, 3)
+	if err != nil {
+		t.Fatalf("ToggleAcceptanceCriterion() error: %v", err)
+	}
+	if !item.Checked || item.Text != "third" {
+		t.Fatalf("unexpected toggled item: %+v", item)
+	}
+	if !strings.Contains(body, "  * [x] third\n") {
+		t.Fatalf("body not updated: %q", body)
+	}
+
+	body, item, err = ToggleAcceptanceCriterion(body, 2)
+	if err != nil {
+		t.Fatalf("ToggleAcceptanceCriterion() error: %v", err)
+	}
+	if item.Checked || !strings.Contains(body, "- [ ] second\n") {
+		t.Fatalf("second should be unchecked: %+v %q", item, body)
+	}
+
+	if _, _, err := ToggleAcceptanceCriterion(body, 4); err == nil {
+		t.Fatalf("expected out of range error")
+	}
+	if _, _, err := ToggleAcceptanceCriterion("no criteria", 1); err == nil {
+		t.Fatalf("expected missing checklist error")
+	}
+}
@@ -0,0 +1,59 @@
+package issue
+
+import (
+	"fmt"
+	"time"
+)
+
+// DueDateLayout is the layout of a date-only due value. Such a value means
+// the issue is due by the end of that day.
+const DueDateLayout = "2006-01-02"
+
+// dueLocalLayouts are the accepted due values that carry a time but no
+// offset; they are read in the configured timezone.
+var dueLocalLayouts = []string{"2006-01-02T15:04", "2006-01-02T15:04:05"}
+
+func ValidateDue(v string) error {
+	_, err := NormalizeDue(v, time.UTC)
+	return err
+}
+
+// NormalizeDue converts a due value from user input into its stored form.
+// Date-only values are kept as they are; values with a time become RFC3339 in
+// loc, so their leading date is the local date. Times without an offset are
+// read in loc.
+func NormalizeDue(v string, loc *time.Location) (string, error) {
+	if v == "" {
+		return "", nil
+	}
+	if _, err := time.Parse(DueDateLayout, v); err == nil {
+		return v, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.In(loc).Format(time.RFC3339), nil
+	}
+	for _, layout := range dueLocalLayouts {
+		if t, err := time.ParseInLocation(layout, v, loc); err == nil {
+			return t.Format(time.RFC3339), nil
+		}
+	}
+	return "", fmt.Errorf("invalid due date (YYYY-MM-DD or YYYY-MM-DDTHH:MM): %s", v)
+}
+
+// DueTime returns the deadline a stored due value represents. A date-only
+// value is due at the end of that day in loc.
+func DueTime(due string, loc *time.Location) (time.Time, bool) {
+	if day, err := time.ParseInLocation(DueDateLayout, due, loc); err == nil {
+		return day.AddDate(0, 0, 1), true
+	}
+	if t, err := time.Parse(time.RFC3339, due); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// IsOverdue reports whether due has passed at now.
+func IsOverdue(due string, now time.Time, loc *time.Location) bool {
+	t, ok := DueTime(due, loc)
+	return ok && !now.Before(t)
+}
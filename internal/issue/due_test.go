@@ -0,0 +1,54 @@
+package issue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeDue(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	cases := map[string]string{
+		"":                          "",
+		"2026-03-01":                "2026-03-01",
+		"2026-03-01T17:00":          "2026-03-01T17:00:00+09:00",
+		"2026-03-01T17:00:30":       "2026-03-01T17:00:30+09:00",
+		"2026-03-01T20:00:00Z":      "2026-03-02T05:00:00+09:00",
+		"2026-03-01T17:00:00+09:00": "2026-03-01T17:00:00+09:00",
+	}
+	for in, want := range cases {
+		got, err := NormalizeDue(in, tokyo)
+		if err != nil {
+			t.Fatalf("NormalizeDue(%q) error: %v", in, err)
+		}
+		if got != want {
+			t.Fatalf("NormalizeDue(%q) = %q, want %q", in, got, want)
+		}
+	}
+	for _, bad := range []string{"2026/03/01", "2026-03-01 17:00", "tomorrow"} {
+		if _, err := NormalizeDue(bad, tokyo); err == nil {
+			t.Fatalf("NormalizeDue(%q) should fail", bad)
+		}
+	}
+}
+
+func TestIsOverdueUsesTimezone(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	// 2026-03-01 16:00 UTC is already 2026-03-02 01:00 in Tokyo.
+	now := time.Date(2026, 3, 1, 16, 0, 0, 0, time.UTC)
+
+	if !IsOverdue("2026-03-01", now, tokyo) {
+		t.Fatalf("date-only due should be overdue once the day has ended in the timezone")
+	}
+	if IsOverdue("2026-03-01", now, time.UTC) {
+		t.Fatalf("date-only due should not be overdue before the day ends in UTC")
+	}
+	if IsOverdue("2026-03-02T09:00:00+09:00", now, tokyo) {
+		t.Fatalf("timed due in the future should not be overdue")
+	}
+	if !IsOverdue("2026-03-02T00:30:00+09:00", now, tokyo) {
+		t.Fatalf("timed due in the past should be overdue")
+	}
+	if IsOverdue("", now, tokyo) {
+		t.Fatalf("empty due is never overdue")
+	}
+}
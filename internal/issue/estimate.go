@@ -0,0 +1,45 @@
+package issue
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NormalizeEstimate converts an estimate such as "90m" or "1h30m" into its
+// stored form, whole minutes written as hours and minutes ("1h30m"). An
+// empty value clears the estimate.
+func NormalizeEstimate(v string) (string, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return "", nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 || d%time.Minute != 0 {
+		return "", fmt.Errorf("invalid estimate (a duration in whole minutes such as 45m or 1h30m): %s", v)
+	}
+	return FormatEstimate(d), nil
+}
+
+// FormatEstimate writes d as hours and minutes, dropping zero parts.
+func FormatEstimate(d time.Duration) string {
+	h, m := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh%dm", h, m)
+	}
+}
+
+// EstimateDuration returns a stored estimate as a duration, or zero when the
+// issue has none.
+func EstimateDuration(v string) time.Duration {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0
+	}
+	return d
+}
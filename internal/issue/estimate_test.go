@@ -0,0 +1,18 @@
+package issue
+
+import "testing"
+
+func TestNormalizeEstimate(t *testing.T) {
+	cases := map[string]string{"90m": "1h30m", "2h": "2h", "45m": "45m", " 1h0m ": "1h", "": ""}
+	for in, want := range cases {
+		got, err := NormalizeEstimate(in)
+		if err != nil || got != want {
+			t.Fatalf("NormalizeEstimate(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"soon", "-1h", "90s"} {
+		if _, err := NormalizeEstimate(in); err == nil {
+			t.Fatalf("NormalizeEstimate(%q) should fail", in)
+		}
+	}
+}
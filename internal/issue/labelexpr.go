@@ -0,0 +1,151 @@
+package issue
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// LabelExpr is a boolean expression over an issue's labels, such as
+// "bug AND NOT wontfix" or "bug,ui". A comma means OR; NOT binds tighter
+// than AND, which binds tighter than OR, and parentheses group.
+type LabelExpr interface {
+	Match(labels []string) bool
+}
+
+type LabelTerm struct{ Label string }
+
+type LabelNot struct{ X LabelExpr }
+
+type LabelAnd struct{ X, Y LabelExpr }
+
+type LabelOr struct{ X, Y LabelExpr }
+
+func (e LabelTerm) Match(labels []string) bool { return slices.Contains(labels, e.Label) }
+func (e LabelNot) Match(labels []string) bool  { return !e.X.Match(labels) }
+func (e LabelAnd) Match(labels []string) bool  { return e.X.Match(labels) && e.Y.Match(labels) }
+func (e LabelOr) Match(labels []string) bool   { return e.X.Match(labels) || e.Y.Match(labels) }
+
+// ParseLabelExpr parses a label filter. A plain label is a valid expression
+// on its own; keywords are case-insensitive.
+func ParseLabelExpr(s string) (LabelExpr, error) {
+	p := &labelParser{tokens: tokenizeLabelExpr(s)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty label expression")
+	}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid label expression %q: %w", s, err)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("invalid label expression %q: unexpected %q", s, p.tokens[p.pos])
+	}
+	return e, nil
+}
+
+func tokenizeLabelExpr(s string) []string {
+	var tokens []string
+	var cur strings.Builder
+	flush := func() {
+		if cur.Len() > 0 {
+			tokens = append(tokens, cur.String())
+			cur.Reset()
+		}
+	}
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == ',':
+			flush()
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t' || r == '\n':
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+type labelParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *labelParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *labelParser) keyword(kw string) bool {
+	if strings.EqualFold(p.peek(), kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *labelParser) parseOr() (LabelExpr, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") || p.keyword(",") {
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = LabelOr{X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *labelParser) parseAnd() (LabelExpr, error) {
+	x, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND") {
+		y, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		x = LabelAnd{X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *labelParser) parseNot() (LabelExpr, error) {
+	if p.keyword("NOT") {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return LabelNot{X: x}, nil
+	}
+	return p.parseTerm()
+}
+
+func (p *labelParser) parseTerm() (LabelExpr, error) {
+	tok := p.peek()
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end")
+	case tok == "(":
+		p.pos++
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.keyword(")") {
+			return nil, fmt.Errorf("missing )")
+		}
+		return x, nil
+	case tok == ")" || tok == "," || strings.EqualFold(tok, "AND") || strings.EqualFold(tok, "OR"):
+		return nil, fmt.Errorf("unexpected %q", tok)
+	}
+	p.pos++
+	return LabelTerm{Label: tok}, nil
+}
@@ -0,0 +1,36 @@
+package issue
+
+import "testing"
+
+func TestParseLabelExprMatches(t *testing.T) {
+	cases := []struct {
+		expr   string
+		labels []string
+		want   bool
+	}{
+		{"bug", []string{"bug"}, true},
+		{"bug", []string{"bugfix"}, false},
+		{"bug,ui", []string{"ui"}, true},
+		{"bug, ui", []string{"api"}, false},
+		{"bug AND NOT wontfix", []string{"bug"}, true},
+		{"bug and not wontfix", []string{"bug", "wontfix"}, false},
+		{"bug OR ui AND api", []string{"bug"}, true},
+		{"(bug OR ui) AND api", []string{"bug"}, false},
+		{"NOT NOT bug", []string{"bug"}, true},
+	}
+	for _, tc := range cases {
+		e, err := ParseLabelExpr(tc.expr)
+		if err != nil {
+			t.Fatalf("ParseLabelExpr(%q) error: %v", tc.expr, err)
+		}
+		if got := e.Match(tc.labels); got != tc.want {
+			t.Fatalf("ParseLabelExpr(%q).Match(%v) = %v, want %v", tc.expr, tc.labels, got, tc.want)
+		}
+	}
+
+	for _, bad := range []string{"", "bug AND", "(bug", "bug ui", "AND bug", "bug,)"} {
+		if _, err := ParseLabelExpr(bad); err == nil {
+			t.Fatalf("ParseLabelExpr(%q) expected error", bad)
+		}
+	}
+}
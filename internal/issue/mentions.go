@@ -0,0 +1,20 @@
+package issue
+
+import (
+	"regexp"
+	"slices"
+)
+
+var mentionPattern = regexp.MustCompile(`\bTRK-[0-9]+\b`)
+
+// Mentions returns the issue IDs referenced as TRK-n in text, each once, in
+// the order they first appear.
+func Mentions(text string) []string {
+	var out []string
+	for _, id := range mentionPattern.FindAllString(text, -1) {
+		if !slices.Contains(out, id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
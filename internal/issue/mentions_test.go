@@ -0,0 +1,16 @@
+package issue
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMentions(t *testing.T) {
+	got := Mentions("Blocked by TRK-3 and TRK-12.\nSee TRK-3 again, not XTRK-4 or TRK-5a.")
+	if want := []string{"TRK-3", "TRK-12"}; !slices.Equal(got, want) {
+		t.Fatalf("Mentions() = %v, want %v", got, want)
+	}
+	if got := Mentions("no references"); len(got) != 0 {
+		t.Fatalf("Mentions() = %v, want none", got)
+	}
+}
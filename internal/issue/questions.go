@@ -0,0 +1,88 @@
+package issue
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	questionItemPattern = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+(.*)$`)
+	answerLinePattern   = regexp.MustCompile(`(?i)^\s+[-*+]\s+(?:a|answer):\s*(.*)$`)
+)
+
+type Question struct {
+	Index    int
+	Text     string
+	Answers  []string
+	line     int
+	blockEnd int
+}
+
+func (q Question) Answered() bool {
+	return len(q.Answers) > 0
+}
+
+// Questions returns the top-level list items of the Questions for user
+// section, numbered from 1, with answers recorded as indented `- A:` lines.
+func Questions(body string) []Question {
+	return questionItems(splitBodyLines(body))
+}
+
+// AnswerQuestion records answer directly under the n-th question.
+func AnswerQuestion(body string, n int, answer string) (string, Question, error) {
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return "", Question{}, fmt.Errorf("answer is required")
+	}
+	lines := splitBodyLines(body)
+	questions := questionItems(lines)
+	if len(questions) == 0 {
+		return "", Question{}, fmt.Errorf("no questions for user")
+	}
+	if n < 1 || n > len(questions) {
+		return "", Question{}, fmt.Errorf("question out of range: %d (1-%d)", n, len(questions))
+	}
+	q := questions[n-1]
+	out := make([]string, 0, len(lines)+1)
+	out = append(out, lines[:q.blockEnd]...)
+	out = append(out, "  - A: "+answer)
+	out = append(out, lines[q.blockEnd:]...)
+	q.Answers = append(q.Answers, answer)
+	return strings.Join(out, "\n"), q, nil
+}
+
+func questionItems(lines []string) []Question {
+	var span *sectionSpan
+	spans := sectionSpans(lines)
+	for i := range spans {
+		if strings.EqualFold(spans[i].Title, SectionQuestions) {
+			span = &spans[i]
+			break
+		}
+	}
+	if span == nil {
+		return nil
+	}
+
+	questions := make([]Question, 0)
+	for i := span.start + 1; i < span.end; i++ {
+		m := questionItemPattern.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		q := Question{Index: len(questions) + 1, Text: strings.TrimSpace(m[1]), line: i, blockEnd: i + 1}
+		for j := i + 1; j < span.end; j++ {
+			if strings.TrimSpace(lines[j]) == "" || !strings.HasPrefix(lines[j], " ") && !strings.HasPrefix(lines[j], "\t") {
+				break
+			}
+			if a := answerLinePattern.FindStringSubmatch(lines[j]); a != nil {
+				q.Answers = append(q.Answers, strings.TrimSpace(a[1]))
+			}
+			q.blockEnd = j + 1
+		}
+		questions = append(questions, q)
+		i = q.blockEnd - 1
+	}
+	return questions
+}
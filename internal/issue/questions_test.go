@@ -0,0 +1,40 @@
+package issue
+
+import (
+	"strings"
+	"testing"
+)
+
+const questionsBody = "## Questions for user\n- Which DB?\n  - A: sqlite\n- Which port?\n  context line\n1. Deadline?\n\n## Notes\n- not a question\n"
+
+func TestQuestionsParsesAnswers(t *testing.T) {
+	qs := Questions(questionsBody)
+	if len(qs) != 3 {
+		t.Fatalf("questions = %+v, want 3", qs)
+	}
+	if qs[0].Text != "Which DB?" || !qs[0].Answered() || qs[0].Answers[0] != "sqlite" {
+		t.Fatalf("unexpected first question: %+v", qs[0])
+	}
+	if qs[1].Answered() || qs[2].Text != "Deadline?" || qs[2].Index != 3 {
+		t.Fatalf("unexpected questions: %+v", qs)
+	}
+}
+
+func TestAnswerQuestionInsertsUnderQuestion(t *testing.T) {
+	body, q, err := AnswerQuestion(questionsBody, 2, "8788")
+	if err != nil {
+		t.Fatalf("AnswerQuestion() error: %v", err)
+	}
+	if q.Text != "Which port?" || !q.Answered() {
+		t.Fatalf("unexpected question: %+v", q)
+	}
+	if !strings.Contains(body, "- Which port?\n  context line\n  - A: 8788\n1. Deadline?") {
+		t.Fatalf("answer not placed under question: %q", body)
+	}
+	if _, _, err := AnswerQuestion(body, 4, "x"); err == nil {
+		t.Fatalf("expected out of range error")
+	}
+	if _, _, err := AnswerQuestion("", 1, "x"); err == nil {
+		t.Fatalf("expected missing questions error")
+	}
+}
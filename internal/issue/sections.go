@@ -0,0 +1,257 @@
+package issue
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	SectionSpec               = "Spec"
+	SectionAcceptanceCriteria = "Acceptance Criteria"
+	SectionQuestions          = "Questions for user"
+	SectionUserReplies        = "User replies"
+	SectionNotes              = "Notes"
+)
+
+var namedSections = map[string]string{
+	"spec":               SectionSpec,
+	"questions":          SectionQuestions,
+	"questions-for-user": SectionQuestions,
+	"replies":            SectionUserReplies,
+	"user-replies":       SectionUserReplies,
+	"notes":              SectionNotes,
+}
+
+// ResolveSectionName maps a short section name used by the CLI and API
+// (spec, questions, user-replies, notes) to its heading title.
+func ResolveSectionName(name string) (string, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	key = strings.NewReplacer(" ", "-", "_", "-").Replace(key)
+	if title, ok := namedSections[key]; ok {
+		return title, nil
+	}
+	return "", fmt.Errorf("unknown section: %s", name)
+}
+
+type Section struct {
+	Title   string
+	Level   int
+	Content string
+}
+
+type sectionSpan struct {
+	Section
+	start int
+	end   int
+}
+
+// FindSection returns the first markdown section whose heading matches title
+// (case-insensitive). Content runs until the next heading of the same or a
+// higher level and has surrounding blank lines trimmed.
+func FindSection(body, title string) (Section, bool) {
+	for _, span := range sectionSpans(splitBodyLines(body)) {
+		if strings.EqualFold(span.Title, strings.TrimSpace(title)) {
+			return span.Section, true
+		}
+	}
+	return Section{}, false
+}
+
+// ListSections returns every markdown section of body in document order.
+func ListSections(body string) []Section {
+	spans := sectionSpans(splitBodyLines(body))
+	out := make([]Section, 0, len(spans))
+	for _, span := range spans {
+		out = append(out, span.Section)
+	}
+	return out
+}
+
+// SetSection replaces the content of the first section matching title,
+// keeping its heading. When no such section exists a new level-2 section is
+// appended to the end of body.
+func SetSection(body, title, content string) string {
+	lines := splitBodyLines(body)
+	content = strings.Trim(content, "\n")
+	for _, span := range sectionSpans(lines) {
+		if !strings.EqualFold(span.Title, strings.TrimSpace(title)) {
+			continue
+		}
+		out := make([]string, 0, len(lines)+2)
+		out = append(out, lines[:span.start+1]...)
+		if content != "" {
+			out = append(out, content)
+		}
+		if span.end < len(lines) {
+			out = append(out, "")
+			out = append(out, lines[span.end:]...)
+		}
+		return strings.TrimRight(strings.Join(out, "\n"), "\n") + "\n"
+	}
+
+	section := "## " + strings.TrimSpace(title) + "\n"
+	if content != "" {
+		section += content + "\n"
+	}
+	body = strings.TrimRight(body, "\n")
+	if body == "" {
+		return section
+	}
+	return body + "\n\n" + section
+}
+
+// AppendToSection adds entry as a new line at the end of the section matching
+// title, creating the section when it does not exist yet.
+func AppendToSection(body, title, entry string) string {
+	entry = strings.Trim(entry, "\n")
+	content := entry
+	if sec, ok := FindSection(body, title); ok && sec.Content != "" {
+		content = sec.Content + "\n" + entry
+	}
+	return SetSection(body, title, content)
+}
+
+// MissingSubsections returns the entries of required that have no non-empty
+// heading of their own inside content.
+func MissingSubsections(content string, required []string) []string {
+	missing := make([]string, 0)
+	for _, name := range required {
+		if sec, ok := FindSection(content, name); !ok || sec.Content == "" {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// ReadinessGaps lists what a planned body still lacks before the issue may
+// move to ready: a non-empty Spec section and acceptance criteria.
+func ReadinessGaps(body string) []string {
+	gaps := make([]string, 0, 2)
+	if sec, ok := FindSection(body, SectionSpec); !ok || sec.Content == "" {
+		gaps = append(gaps, "spec")
+	}
+	if sec, ok := FindSection(body, SectionAcceptanceCriteria); !ok || sec.Content == "" {
+		gaps = append(gaps, "acceptance criteria")
+	}
+	return gaps
+}
+
+// BodyTop names the text before a body's first heading in ChangedSections
+// and BodySections.
+const BodyTop = "(top)"
+
+// BodySections returns body's sections in document order, led by the text
+// before the first heading as BodyTop when there is any. Only the first of
+// several same-titled sections is kept, matching FindSection.
+func BodySections(body string) []Section {
+	lines := splitBodyLines(body)
+	spans := sectionSpans(lines)
+	topEnd := len(lines)
+	if len(spans) > 0 {
+		topEnd = spans[0].start
+	}
+	out := make([]Section, 0, len(spans)+1)
+	if top := strings.Trim(strings.Join(lines[:topEnd], "\n"), "\n "); top != "" {
+		out = append(out, Section{Title: BodyTop, Content: top})
+	}
+	seen := map[string]bool{}
+	for _, span := range spans {
+		key := strings.ToLower(span.Title)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, span.Section)
+	}
+	return out
+}
+
+// ChangedSections lists the titles of the sections whose content differs
+// between two bodies, including sections added or removed, in the order they
+// appear in after followed by removed ones.
+func ChangedSections(before, after string) []string {
+	old := map[string]string{}
+	for _, sec := range BodySections(before) {
+		old[strings.ToLower(sec.Title)] = sec.Content
+	}
+	var out []string
+	for _, sec := range BodySections(after) {
+		key := strings.ToLower(sec.Title)
+		if content, ok := old[key]; !ok || content != sec.Content {
+			out = append(out, sec.Title)
+		}
+		delete(old, key)
+	}
+	for _, sec := range BodySections(before) {
+		if _, ok := old[strings.ToLower(sec.Title)]; ok {
+			out = append(out, sec.Title)
+		}
+	}
+	return out
+}
+
+func splitBodyLines(body string) []string {
+	return strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+}
+
+func sectionSpans(lines []string) []sectionSpan {
+	type heading struct {
+		line  int
+		level int
+		title string
+	}
+	headings := make([]heading, 0)
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if level, title, ok := parseHeading(line); ok {
+			headings = append(headings, heading{line: i, level: level, title: title})
+		}
+	}
+
+	spans := make([]sectionSpan, 0, len(headings))
+	for i, h := range headings {
+		end := len(lines)
+		for _, next := range headings[i+1:] {
+			if next.level <= h.level {
+				end = next.line
+				break
+			}
+		}
+		spans = append(spans, sectionSpan{
+			Section: Section{
+				Title:   h.title,
+				Level:   h.level,
+				Content: strings.Trim(strings.Join(lines[h.line+1:end], "\n"), "\n "),
+			},
+			start: h.line,
+			end:   end,
+		})
+	}
+	return spans
+}
+
+func parseHeading(line string) (int, string, bool) {
+	if !strings.HasPrefix(line, "#") {
+		return 0, "", false
+	}
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level > 6 || level >= len(line) || (line[level] != ' ' && line[level] != '\t') {
+		return 0, "", false
+	}
+	title := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(line[level:]), "#"))
+	if title == "" {
+		return 0, "", false
+	}
+	return level, title, true
+}
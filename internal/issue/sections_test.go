@@ -0,0 +1,125 @@
+package issue
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFindSectionStopsAtSameOrHigherLevelHeading(t *testing.T) {
+	body := "intro\n\n## Spec\nbuild it\n\n### Acceptance Criteria\n- [ ] works\n\n## Questions for user\n- why?\n"
+
+	spec, ok := FindSection(body, "spec")
+	if !ok {
+		t.Fatalf("spec section should be found")
+	}
+	if spec.Level != 2 || spec.Content != "build it\n\n### Acceptance Criteria\n- [ ] works" {
+		t.Fatalf("unexpected spec section: %+v", spec)
+	}
+
+	ac, ok := FindSection(body, SectionAcceptanceCriteria)
+	if !ok || ac.Content != "- [ ] works" {
+		t.Fatalf("unexpected acceptance criteria: %+v ok=%v", ac, ok)
+	}
+
+	q, ok := FindSection(body, SectionQuestions)
+	if !ok || q.Content != "- why?" {
+		t.Fatalf("unexpected questions: %+v ok=%v", q, ok)
+	}
+
+	if _, ok := FindSection(body, "Notes"); ok {
+		t.Fatalf("missing section should not be found")
+	}
+}
+
+func TestFindSectionIgnoresHeadingsInCodeFences(t *testing.T) {
+	body := "## Spec\n```\n## Not a heading\n```\ndone\n"
+	spec, ok := FindSection(body, SectionSpec)
+	if !ok || spec.Content != "```\n## Not a heading\n```\ndone" {
+		t.Fatalf("unexpected spec: %+v ok=%v", spec, ok)
+	}
+	if _, ok := FindSection(body, "Not a heading"); ok {
+		t.Fatalf("fenced heading should be ignored")
+	}
+}
+
+func TestReadinessGaps(t *testing.T) {
+	if gaps := ReadinessGaps(""); !slices.Equal(gaps, []string{"spec", "acceptance criteria"}) {
+		t.Fatalf("empty body gaps = %v", gaps)
+	}
+	if gaps := ReadinessGaps("## Spec\ndo it\n"); !slices.Equal(gaps, []string{"acceptance criteria"}) {
+		t.Fatalf("spec-only gaps = %v", gaps)
+	}
+	if gaps := ReadinessGaps("## Spec\ndo it\n### Acceptance criteria\n- [ ] ok\n"); len(gaps) != 0 {
+		t.Fatalf("complete body gaps = %v", gaps)
+	}
+}
+
+func TestSetSectionReplacesExistingContent(t *testing.T) {
+	body := "intro\n\n## Spec\nold\n\n## Notes\nkeep\n"
+	got := SetSection(body, "spec", "new\n### Goal\nship")
+	want := "intro\n\n## Spec\nnew\n### Goal\nship\n\n## Notes\nkeep\n"
+	if got != want {
+		t.Fatalf("SetSection() = %q, want %q", got, want)
+	}
+}
+
+func TestSetSectionAppendsMissingSection(t *testing.T) {
+	if got := SetSection("", SectionSpec, "content"); got != "## Spec\ncontent\n" {
+		t.Fatalf("SetSection(empty) = %q", got)
+	}
+	if got := SetSection("intro\n", SectionSpec, "content"); got != "intro\n\n## Spec\ncontent\n" {
+		t.Fatalf("SetSection(intro) = %q", got)
+	}
+}
+
+func TestMissingSubsections(t *testing.T) {
+	spec := "### Goal\nship it\n\n### Approach\n\n### Test plan\ngo test\n"
+	got := MissingSubsections(spec, []string{"Goal", "Approach", "Test plan"})
+	if !slices.Equal(got, []string{"Approach"}) {
+		t.Fatalf("MissingSubsections() = %v", got)
+	}
+}
+
+func TestAppendToSection(t *testing.T) {
+	body := "## User replies\n- first\n\n## Notes\nkeep\n"
+	got := AppendToSection(body, SectionUserReplies, "- second")
+	want := "## User replies\n- first\n- second\n\n## Notes\nkeep\n"
+	if got != want {
+		t.Fatalf("AppendToSection() = %q, want %q", got, want)
+	}
+	if got := AppendToSection("", SectionUserReplies, "- only"); got != "## User replies\n- only\n" {
+		t.Fatalf("AppendToSection(empty) = %q", got)
+	}
+}
+
+func TestResolveSectionName(t *testing.T) {
+	cases := map[string]string{
+		"spec":         SectionSpec,
+		"Questions":    SectionQuestions,
+		"user-replies": SectionUserReplies,
+		"user_replies": SectionUserReplies,
+		"notes":        SectionNotes,
+	}
+	for name, want := range cases {
+		got, err := ResolveSectionName(name)
+		if err != nil || got != want {
+			t.Fatalf("ResolveSectionName(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ResolveSectionName("random"); err == nil {
+		t.Fatalf("expected error for unknown section")
+	}
+}
+
+func TestChangedSections(t *testing.T) {
+	before := "intro\n\n## Spec\nold\n\n## Notes\nkeep\n\n## Gone\nx"
+	after := "intro\n\n## Notes\nkeep\n\n## Spec\nnew\n\n## Added\ny"
+	got := ChangedSections(before, after)
+	want := []string{"Spec", "Added", "Gone"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ChangedSections() = %v, want %v", got, want)
+	}
+	if got := ChangedSections("intro", "new intro"); !slices.Equal(got, []string{BodyTop}) {
+		t.Fatalf("ChangedSections(top) = %v", got)
+	}
+}
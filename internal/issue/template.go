@@ -0,0 +1,24 @@
+package issue
+
+import (
+	"regexp"
+	"strings"
+)
+
+var templateVarPattern = regexp.MustCompile(`\{\{\s*([a-z_]+)\s*\}\}`)
+
+// ExpandTemplate replaces {{name}} placeholders in text with vars[name].
+// Placeholders without a value are left as written, so missing data stays
+// visible instead of rendering as a gap.
+func ExpandTemplate(text string, vars map[string]string) string {
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+	return templateVarPattern.ReplaceAllStringFunc(text, func(m string) string {
+		name := templateVarPattern.FindStringSubmatch(m)[1]
+		if v := vars[name]; v != "" {
+			return v
+		}
+		return m
+	})
+}
@@ -0,0 +1,14 @@
+package issue
+
+import "testing"
+
+func TestExpandTemplate(t *testing.T) {
+	vars := map[string]string{"due": "2026-03-01", "branch": "feature/login"}
+	got := ExpandTemplate("Merge {{branch}} before {{ due }}, then review {{pr_url}}", vars)
+	if want := "Merge feature/login before 2026-03-01, then review {{pr_url}}"; got != want {
+		t.Fatalf("ExpandTemplate() = %q, want %q", got, want)
+	}
+	if got := ExpandTemplate("plain text", vars); got != "plain text" {
+		t.Fatalf("ExpandTemplate() = %q", got)
+	}
+}
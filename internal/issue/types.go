@@ -3,7 +3,6 @@ package issue
 import (
 	"fmt"
 	"strings"
-	"time"
 )
 
 const (
@@ -14,6 +13,10 @@ const (
 	StatusArchived   = "archived"
 )
 
+// StatusBlocked is not built in; once added with `track status add`, issues
+// moved to it record why and since when they are blocked.
+const StatusBlocked = "blocked"
+
 var validStatuses = map[string]struct{}{
 	StatusTodo:       {},
 	StatusReady:      {},
@@ -40,8 +43,22 @@ type Item struct {
 	Labels     []string
 	NextAction string
 	Body       string
+	Estimate   string
+	Pinned     bool
+	// ExternalID is the issue's ID in the system it was imported from, such
+	// as JIRA-123. It is unique when set.
+	ExternalID string `json:",omitempty"`
 	CreatedAt  string
 	UpdatedAt  string
+	// Links is only filled where the caller asks for it, such as export.
+	Links []Link `json:",omitempty"`
+}
+
+// Link is a reference from an issue to an external URL, such as a design
+// doc or a chat thread.
+type Link struct {
+	URL   string
+	Title string
 }
 
 func ValidateStatus(v string) error {
@@ -65,15 +82,22 @@ func ValidatePriority(v string) error {
 	return nil
 }
 
-func ValidateDue(v string) error {
-	if v == "" {
-		return nil
+// PriorityRank orders priorities from most urgent (p0 is 0) to none; an
+// unknown priority ranks last.
+func PriorityRank(v string) int {
+	switch v {
+	case "p0":
+		return 0
+	case "p1":
+		return 1
+	case "p2":
+		return 2
+	case "p3":
+		return 3
+	case "none":
+		return 4
 	}
-	_, err := time.Parse("2006-01-02", v)
-	if err != nil {
-		return fmt.Errorf("invalid due date (YYYY-MM-DD): %s", v)
-	}
-	return nil
+	return 5
 }
 
 func NormalizeAssignee(v string) string {
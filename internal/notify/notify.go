@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/platform"
+	"github.com/myuon/track/internal/secret"
+)
+
+type Message struct {
+	IssueID string
+	Title   string
+	Body    string
+}
+
+// Send runs the configured notify_cmd, with {{secret:<name>}} references
+// filled in, and the message exposed through the TRACK_NOTIFY_* environment
+// variables. It is a no-op when notify_cmd is unset.
+func Send(ctx context.Context, msg Message) error {
+	cfg, err := appconfig.Load()
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(cfg.NotifyCmd) == "" {
+		return nil
+	}
+	notifyCmd, err := secret.Expand(cfg.NotifyCmd)
+	if err != nil {
+		return fmt.Errorf("notify_cmd: %w", err)
+	}
+	cmd, err := platform.Command(ctx, notifyCmd)
+	if err != nil {
+		return fmt.Errorf("parse notify command: %w", err)
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"TRACK_ISSUE_ID="+msg.IssueID,
+		"TRACK_NOTIFY_TITLE="+msg.Title,
+		"TRACK_NOTIFY_BODY="+msg.Body,
+	)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("notify failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	appconfig "github.com/myuon/track/internal/config"
+)
+
+func TestSendRunsConfiguredCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs /bin/sh")
+	}
+	home := t.TempDir()
+	t.Setenv("TRACK_HOME", home)
+
+	out := filepath.Join(home, "notify.out")
+	script := filepath.Join(home, "notify.sh")
+	body := "#!/bin/sh\nprintf '%s|%s|%s' \"$TRACK_ISSUE_ID\" \"$TRACK_NOTIFY_TITLE\" \"$TRACK_NOTIFY_BODY\" > " + out + "\n"
+	if err := os.WriteFile(script, []byte(body), 0o755); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	cfg := appconfig.Default()
+	cfg.NotifyCmd = script
+	if err := appconfig.Save(cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	if err := Send(context.Background(), Message{IssueID: "TRK-1", Title: "questions", Body: "- why?"}); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if strings.TrimSpace(string(got)) != "TRK-1|questions|- why?" {
+		t.Fatalf("unexpected notify env: %q", string(got))
+	}
+}
+
+func TestSendWithoutCommandIsNoop(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	if err := Send(context.Background(), Message{IssueID: "TRK-1"}); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !windows
+
+package platform
+
+import "os/exec"
+
+func setCmdLine(cmd *exec.Cmd, name, line string) {}
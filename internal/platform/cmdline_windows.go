@@ -0,0 +1,18 @@
+//go:build windows
+
+package platform
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setCmdLine hands line to the interpreter without Go's argument quoting:
+// cmd.exe /S /C strips one pair of quotes around the rest of the line, and
+// powershell gets the line as typed.
+func setCmdLine(cmd *exec.Cmd, name, line string) {
+	if name == "cmd.exe" {
+		line = `cmd.exe /S /C "` + line + `"`
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: line}
+}
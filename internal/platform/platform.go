@@ -0,0 +1,122 @@
+// Package platform hides the differences between operating systems that
+// track runs into: how hook and notify commands are started, how paths from
+// git and the database are spelled, and which file names Windows refuses.
+package platform
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/google/shlex"
+)
+
+// Command builds the process for a configured command line such as a hook's
+// --run or notify_cmd. Elsewhere the line is split like a shell would and run
+// directly, so `/bin/sh -c '...'` keeps working; on Windows it is handed to
+// cmd.exe, unless it starts with powershell or pwsh, which then parse the
+// rest of the line themselves.
+func Command(ctx context.Context, line string) (*exec.Cmd, error) {
+	if strings.TrimSpace(line) == "" {
+		return nil, fmt.Errorf("empty command")
+	}
+	if runtime.GOOS != "windows" {
+		parts, err := shlex.Split(line)
+		if err != nil {
+			return nil, err
+		}
+		if len(parts) == 0 {
+			return nil, fmt.Errorf("empty command")
+		}
+		return exec.CommandContext(ctx, parts[0], parts[1:]...), nil
+	}
+	name, args := windowsCommand(line)
+	cmd := exec.CommandContext(ctx, name, args...)
+	setCmdLine(cmd, name, line)
+	return cmd, nil
+}
+
+// windowsCommand picks the interpreter for line on Windows and the arguments
+// to show for it; setCmdLine passes line on verbatim, since Go's quoting of
+// separate arguments is not what cmd.exe expects.
+func windowsCommand(line string) (string, []string) {
+	line = strings.TrimSpace(line)
+	first, rest, _ := strings.Cut(line, " ")
+	switch strings.TrimSuffix(strings.ToLower(filepath.Base(first)), ".exe") {
+	case "powershell", "pwsh":
+		return first, strings.Fields(rest)
+	}
+	return "cmd.exe", []string{"/S", "/C", line}
+}
+
+// NormalizePath spells p with the separators of this OS and cleans it, so
+// git's C:/Users/... output and paths typed with forward slashes work on
+// Windows. An empty path stays empty.
+func NormalizePath(p string) string {
+	if p == "" {
+		return ""
+	}
+	return filepath.Clean(filepath.FromSlash(p))
+}
+
+// reservedNames are the device names Windows will not create a file or
+// directory under, with or without an extension.
+var reservedNames = map[string]bool{
+	"con": true, "prn": true, "aux": true, "nul": true,
+	"com1": true, "com2": true, "com3": true, "com4": true, "com5": true, "com6": true, "com7": true, "com8": true, "com9": true,
+	"lpt1": true, "lpt2": true, "lpt3": true, "lpt4": true, "lpt5": true, "lpt6": true, "lpt7": true, "lpt8": true, "lpt9": true,
+}
+
+// SafeName turns name into a single path element that every OS accepts:
+// separators, characters Windows reserves, and control characters become
+// "-", trailing dots and spaces are dropped, and device names such as con
+// get a "_" appended. It is applied everywhere, not only on Windows, so a
+// checkout shared between systems keeps the same names.
+func SafeName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if r < 0x20 || strings.ContainsRune(`<>:"/\|?*`, r) {
+			b.WriteByte('-')
+			continue
+		}
+		b.WriteRune(r)
+	}
+	out := strings.TrimRight(b.String(), ". ")
+	if out == "" {
+		return "_"
+	}
+	base, _, _ := strings.Cut(out, ".")
+	if reservedNames[strings.ToLower(base)] {
+		out += "_"
+	}
+	return out
+}
+
+// FindExecutable reports the file that running path would start. On Windows
+// a path without an extension is tried with each of %PATHEXT%, so a
+// worktree's exec_codex.cmd is found as exec_codex.
+func FindExecutable(path string) (string, bool) {
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		return path, true
+	}
+	if runtime.GOOS != "windows" || filepath.Ext(path) != "" {
+		return "", false
+	}
+	pathext := os.Getenv("PATHEXT")
+	if pathext == "" {
+		pathext = ".COM;.EXE;.BAT;.CMD"
+	}
+	for _, ext := range strings.Split(pathext, ";") {
+		if ext == "" {
+			continue
+		}
+		if info, err := os.Stat(path + strings.ToLower(ext)); err == nil && !info.IsDir() {
+			return path + strings.ToLower(ext), true
+		}
+	}
+	return "", false
+}
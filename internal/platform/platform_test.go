@@ -0,0 +1,94 @@
+package platform
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestCommandSplitsLineOutsideWindows(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("lines go to cmd.exe on Windows")
+	}
+	cmd, err := Command(context.Background(), `/bin/sh -c 'echo "a b"'`)
+	if err != nil {
+		t.Fatalf("Command: %v", err)
+	}
+	if want := []string{"/bin/sh", "-c", `echo "a b"`}; !reflect.DeepEqual(cmd.Args, want) {
+		t.Fatalf("args = %q, want %q", cmd.Args, want)
+	}
+	if _, err := Command(context.Background(), "  "); err == nil {
+		t.Fatal("expected error for empty command")
+	}
+}
+
+func TestWindowsCommand(t *testing.T) {
+	cases := []struct {
+		line, name string
+		args       []string
+	}{
+		{`echo done:%TRACK_ISSUE_ID% >> "C:\tmp\out.txt"`, "cmd.exe", []string{"/S", "/C", `echo done:%TRACK_ISSUE_ID% >> "C:\tmp\out.txt"`}},
+		{`powershell -NoProfile -Command Write-Output $env:TRACK_EVENT`, "powershell", []string{"-NoProfile", "-Command", "Write-Output", "$env:TRACK_EVENT"}},
+		{`pwsh.exe -File hook.ps1`, "pwsh.exe", []string{"-File", "hook.ps1"}},
+	}
+	for _, c := range cases {
+		name, args := windowsCommand(c.line)
+		if name != c.name || !reflect.DeepEqual(args, c.args) {
+			t.Errorf("windowsCommand(%q) = %q %q, want %q %q", c.line, name, args, c.name, c.args)
+		}
+	}
+}
+
+func TestSafeName(t *testing.T) {
+	cases := map[string]string{
+		"trk-12":      "trk-12",
+		"trk-12.":     "trk-12",
+		"a:b|c?d":     "a-b-c-d",
+		"con":         "con_",
+		"Aux.txt":     "Aux.txt_",
+		"com10":       "com10",
+		"feature/x":   "feature-x",
+		"...":         "_",
+		"tab\there  ": "tab-here",
+	}
+	for in, want := range cases {
+		if got := SafeName(in); got != want {
+			t.Errorf("SafeName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	if got := NormalizePath(""); got != "" {
+		t.Fatalf("NormalizePath(\"\") = %q", got)
+	}
+	if got, want := NormalizePath("a/b/../c/"), filepath.Join("a", "c"); got != want {
+		t.Fatalf("NormalizePath = %q, want %q", got, want)
+	}
+}
+
+func TestFindExecutable(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "exec_codex")
+	if _, ok := FindExecutable(path); ok {
+		t.Fatal("found a file that does not exist")
+	}
+	if runtime.GOOS == "windows" {
+		if err := os.WriteFile(path+".cmd", nil, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if got, ok := FindExecutable(path); !ok || got != path+".cmd" {
+			t.Fatalf("FindExecutable = %q, %v", got, ok)
+		}
+		return
+	}
+	if err := os.WriteFile(path, nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := FindExecutable(path); !ok || got != path {
+		t.Fatalf("FindExecutable = %q, %v", got, ok)
+	}
+}
@@ -0,0 +1,256 @@
+// Package query parses the issue query language used by `track list -q` and
+// the API's ?q= parameter, for example:
+//
+//	status:ready priority<=p1 due<2026-03-01 label:bug -label:blocked
+//
+// Terms are ANDed. A term is key:value, key<value, key<=value, key>value,
+// or key>=value; a leading "-" negates it. Words without a key search the
+// title and body. Values may be double-quoted.
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+// priorityOrder ranks priorities from most to least urgent, so priority<=p1
+// means p0 or p1.
+var priorityOrder = []string{"p0", "p1", "p2", "p3", "none"}
+
+var operators = []string{"<=", ">=", ":", "<", ">"}
+
+type term struct {
+	negate bool
+	key    string
+	op     string
+	value  string
+}
+
+// Apply parses q and narrows f with it. Filters already set on f are kept:
+// statuses and priorities are intersected, label expressions are ANDed, and
+// the remaining single-valued fields are overridden by the query.
+func Apply(q string, f *sqlite.ListFilter) error {
+	terms, err := parse(q)
+	if err != nil {
+		return err
+	}
+
+	var labels, search []string
+	if f.Label != "" {
+		labels = append(labels, "("+f.Label+")")
+	}
+	if f.Search != "" {
+		search = append(search, f.Search)
+	}
+	for _, t := range terms {
+		if t.key == "" {
+			search = append(search, t.value)
+			continue
+		}
+		if t.negate && !slices.Contains([]string{"status", "priority", "label"}, t.key) {
+			return fmt.Errorf("query: %s cannot be negated", t.key)
+		}
+		if t.op != ":" && t.key != "priority" && t.key != "due" {
+			return fmt.Errorf("query: %s only supports %s:value", t.key, t.key)
+		}
+
+		switch t.key {
+		case "status":
+			values := splitValues(t.value)
+			if t.negate {
+				f.ExcludeStatuses = append(f.ExcludeStatuses, values...)
+			} else {
+				f.Statuses = intersect(f.Statuses, values)
+				if len(f.Statuses) == 0 {
+					return fmt.Errorf("query: status filters match nothing")
+				}
+			}
+		case "priority":
+			values, err := priorities(t)
+			if err != nil {
+				return err
+			}
+			f.Priorities = intersect(f.Priorities, values)
+			if len(f.Priorities) == 0 {
+				return fmt.Errorf("query: priority filters match nothing")
+			}
+		case "label":
+			expr := strings.Join(splitValues(t.value), ",")
+			if t.negate {
+				labels = append(labels, "NOT ("+expr+")")
+			} else {
+				labels = append(labels, "("+expr+")")
+			}
+		case "due":
+			if err := applyDue(t, f); err != nil {
+				return err
+			}
+		case "assignee":
+			f.Assignee = issue.NormalizeAssignee(t.value)
+		case "project":
+			f.Project = t.value
+		case "sort":
+			f.Sort = t.value
+		default:
+			return fmt.Errorf("query: unknown key %q", t.key)
+		}
+	}
+	f.Label = strings.Join(labels, " AND ")
+	f.Search = strings.Join(search, " ")
+	return nil
+}
+
+func parse(q string) ([]term, error) {
+	words, err := splitWords(q)
+	if err != nil {
+		return nil, err
+	}
+	terms := make([]term, 0, len(words))
+	for _, w := range words {
+		t := term{value: w}
+		body := w
+		if strings.HasPrefix(body, "-") && len(body) > 1 {
+			body = body[1:]
+			t.negate = true
+		}
+		if idx, op := findOperator(body); idx > 0 {
+			t.key = strings.ToLower(body[:idx])
+			t.op = op
+			t.value = body[idx+len(op):]
+			if t.value == "" {
+				return nil, fmt.Errorf("query: %s has no value", t.key)
+			}
+		} else if t.negate {
+			return nil, fmt.Errorf("query: cannot negate search text %q", w)
+		}
+		terms = append(terms, t)
+	}
+	return terms, nil
+}
+
+// findOperator returns the position of the first operator that follows a
+// key made of letters and underscores.
+func findOperator(word string) (int, string) {
+	for i, r := range word {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_' {
+			continue
+		}
+		for _, op := range operators {
+			if strings.HasPrefix(word[i:], op) {
+				return i, op
+			}
+		}
+		return -1, ""
+	}
+	return -1, ""
+}
+
+// splitWords splits on whitespace, keeping double-quoted runs together and
+// dropping the quotes.
+func splitWords(q string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	inQuote, hasWord := false, false
+	for _, r := range q {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			hasWord = true
+		case !inQuote && (r == ' ' || r == '\t' || r == '\n'):
+			if hasWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				hasWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			hasWord = true
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("query: unterminated quote")
+	}
+	if hasWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
+
+func splitValues(v string) []string {
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func priorities(t term) ([]string, error) {
+	if t.op == ":" {
+		values := splitValues(t.value)
+		for _, v := range values {
+			if err := issue.ValidatePriority(v); err != nil {
+				return nil, fmt.Errorf("query: %w", err)
+			}
+		}
+		if t.negate {
+			return slices.DeleteFunc(slices.Clone(priorityOrder), func(p string) bool { return slices.Contains(values, p) }), nil
+		}
+		return values, nil
+	}
+	if t.negate {
+		return nil, fmt.Errorf("query: priority comparisons cannot be negated")
+	}
+	rank := slices.Index(priorityOrder, t.value)
+	if rank == -1 {
+		return nil, fmt.Errorf("query: invalid priority: %s", t.value)
+	}
+	switch t.op {
+	case "<":
+		return slices.Clone(priorityOrder[:rank]), nil
+	case "<=":
+		return slices.Clone(priorityOrder[:rank+1]), nil
+	case ">":
+		return slices.Clone(priorityOrder[rank+1:]), nil
+	default:
+		return slices.Clone(priorityOrder[rank:]), nil
+	}
+}
+
+func applyDue(t term, f *sqlite.ListFilter) error {
+	if t.value == "none" && t.op == ":" {
+		f.NoDue = true
+		return nil
+	}
+	day, err := time.Parse("2006-01-02", t.value)
+	if err != nil {
+		return fmt.Errorf("query: invalid due date (YYYY-MM-DD): %s", t.value)
+	}
+	format := func(d time.Time) string { return d.Format("2006-01-02") }
+	switch t.op {
+	case ":":
+		f.DueFrom, f.DueTo = t.value, t.value
+	case "<":
+		f.DueTo = format(day.AddDate(0, 0, -1))
+	case "<=":
+		f.DueTo = t.value
+	case ">":
+		f.DueFrom = format(day.AddDate(0, 0, 1))
+	case ">=":
+		f.DueFrom = t.value
+	}
+	return nil
+}
+
+func intersect(current, values []string) []string {
+	if len(current) == 0 {
+		return values
+	}
+	return slices.DeleteFunc(slices.Clone(current), func(v string) bool { return !slices.Contains(values, v) })
+}
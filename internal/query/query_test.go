@@ -0,0 +1,63 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestApplyBuildsFilter(t *testing.T) {
+	var f sqlite.ListFilter
+	err := Apply(`status:ready,todo priority<=p1 due<2026-03-01 label:bug -label:blocked -status:todo assignee:agent "login page" sort:due`, &f)
+	if err != nil {
+		t.Fatalf("Apply() error: %v", err)
+	}
+	want := sqlite.ListFilter{
+		Statuses:        []string{"ready", "todo"},
+		ExcludeStatuses: []string{"todo"},
+		Priorities:      []string{"p0", "p1"},
+		Label:           "(bug) AND NOT (blocked)",
+		Assignee:        "agent",
+		Search:          "login page",
+		DueTo:           "2026-02-28",
+		Sort:            "due",
+	}
+	if !reflect.DeepEqual(f, want) {
+		t.Fatalf("Apply() = %+v, want %+v", f, want)
+	}
+}
+
+func TestApplyNarrowsExistingFilter(t *testing.T) {
+	f := sqlite.ListFilter{Priorities: []string{"p1", "p2"}, Label: "ui,api"}
+	if err := Apply("priority>=p2 due>=2026-01-01 due<=2026-01-31 -priority:p3", &f); err != nil {
+		t.Fatalf("Apply() error: %v", err)
+	}
+	if !reflect.DeepEqual(f.Priorities, []string{"p2"}) || f.Label != "(ui,api)" || f.DueFrom != "2026-01-01" || f.DueTo != "2026-01-31" {
+		t.Fatalf("unexpected filter: %+v", f)
+	}
+
+	f = sqlite.ListFilter{}
+	if err := Apply("due:none", &f); err != nil || !f.NoDue {
+		t.Fatalf("due:none = %+v, err %v", f, err)
+	}
+}
+
+func TestApplyRejectsInvalidQueries(t *testing.T) {
+	for _, q := range []string{
+		"colour:red",
+		"priority<=p9",
+		"due<tomorrow",
+		"-assignee:agent",
+		"assignee>x",
+		"status:",
+		`label:"bug`,
+		"-oops",
+		"priority:p0 priority:p1",
+	} {
+		var f sqlite.ListFilter
+		if err := Apply(q, &f); err == nil {
+			t.Fatalf("Apply(%q) expected error", q)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+// Package rpc implements the stdio JSON-RPC 2.0 mode used by editor plugins
+// (`track rpc`). Messages use LSP-style Content-Length framing so existing
+// language-client libraries can talk to it.
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/service"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeServerError    = -32000
+)
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+type Issue struct {
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	Status     string   `json:"status"`
+	Priority   string   `json:"priority"`
+	Assignee   string   `json:"assignee"`
+	Due        string   `json:"due"`
+	Labels     []string `json:"labels"`
+	NextAction string   `json:"next_action"`
+	Body       string   `json:"body"`
+	UpdatedAt  string   `json:"updated_at"`
+}
+
+type searchParams struct {
+	Query    string   `json:"query"`
+	Statuses []string `json:"statuses"`
+	Limit    int      `json:"limit"`
+}
+
+type createParams struct {
+	Title    string   `json:"title"`
+	Body     string   `json:"body"`
+	Priority string   `json:"priority"`
+	Labels   []string `json:"labels"`
+}
+
+type statusParams struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// Methods lists the supported methods in the order reported by initialize.
+var Methods = []string{"initialize", "issues/search", "issues/get", "issues/create", "issues/setStatus", "issues/toggleDone", "shutdown", "exit"}
+
+// Serve reads framed requests from in and writes responses to out until the
+// input ends, an exit notification arrives, or ctx is cancelled.
+func Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	ctx = sqlite.WithActor(ctx, "rpc")
+	r := bufio.NewReader(in)
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+		body, err := readMessage(r)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			if err := writeMessage(out, response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &Error{Code: codeParseError, Message: "parse error"}}); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Method == "exit" {
+			return nil
+		}
+
+		result, rpcErr := dispatch(ctx, req)
+		if len(req.ID) == 0 {
+			// Notifications never get a response.
+			continue
+		}
+		resp := response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr}
+		if rpcErr == nil && result == nil {
+			resp.Result = json.RawMessage("null")
+		}
+		if err := writeMessage(out, resp); err != nil {
+			return err
+		}
+	}
+}
+
+func dispatch(ctx context.Context, req request) (any, *Error) {
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return nil, &Error{Code: codeInvalidRequest, Message: "invalid request"}
+	}
+	switch req.Method {
+	case "initialize":
+		return map[string]any{"name": "track", "methods": Methods}, nil
+	case "shutdown":
+		return nil, nil
+	case "issues/search":
+		var p searchParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		return withStore(ctx, func(store *sqlite.Store) (any, error) { return search(ctx, store, p) })
+	case "issues/get":
+		var p statusParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		return withStore(ctx, func(store *sqlite.Store) (any, error) {
+			it, err := store.GetIssue(ctx, normalizeIssueID(p.ID))
+			return toIssue(it), err
+		})
+	case "issues/create":
+		var p createParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		if p.Priority == "" {
+			p.Priority = "none"
+		}
+		if err := issue.ValidateTitle(p.Title); err != nil {
+			return nil, &Error{Code: codeInvalidParams, Message: err.Error()}
+		}
+		return withStore(ctx, func(store *sqlite.Store) (any, error) {
+			it, err := service.CreateIssue(ctx, store, issue.Item{
+				Title:    p.Title,
+				Body:     p.Body,
+				Status:   issue.StatusTodo,
+				Priority: p.Priority,
+				Labels:   p.Labels,
+			})
+			return toIssue(it), err
+		})
+	case "issues/setStatus":
+		var p statusParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		return withStore(ctx, func(store *sqlite.Store) (any, error) {
+			it, err := service.SetStatus(ctx, store, normalizeIssueID(p.ID), p.Status)
+			return toIssue(it), err
+		})
+	case "issues/toggleDone":
+		var p statusParams
+		if err := decodeParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		return withStore(ctx, func(store *sqlite.Store) (any, error) {
+			id := normalizeIssueID(p.ID)
+			current, err := store.GetIssue(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			next := issue.StatusDone
+			if current.Status == issue.StatusDone {
+				next = issue.StatusTodo
+			}
+			it, err := service.SetStatus(ctx, store, id, next)
+			return toIssue(it), err
+		})
+	default:
+		return nil, &Error{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+}
+
+func search(ctx context.Context, store *sqlite.Store, p searchParams) (any, error) {
+	for _, st := range p.Statuses {
+		if err := store.ValidateStatus(ctx, st); err != nil {
+			return nil, err
+		}
+	}
+	items, err := store.ListIssues(ctx, sqlite.ListFilter{
+		Statuses:        p.Statuses,
+		ExcludeDone:     len(p.Statuses) == 0,
+		ExcludeArchived: len(p.Statuses) == 0,
+		Search:          strings.TrimSpace(p.Query),
+		Sort:            "priority_manual",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if p.Limit > 0 && len(items) > p.Limit {
+		items = items[:p.Limit]
+	}
+	out := make([]Issue, 0, len(items))
+	for _, it := range items {
+		out = append(out, toIssue(it))
+	}
+	return map[string][]Issue{"items": out}, nil
+}
+
+func withStore(ctx context.Context, fn func(store *sqlite.Store) (any, error)) (any, *Error) {
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return nil, &Error{Code: codeServerError, Message: err.Error()}
+	}
+	defer store.Close()
+
+	result, err := fn(store)
+	if err != nil {
+		return nil, &Error{Code: codeServerError, Message: err.Error()}
+	}
+	return result, nil
+}
+
+func decodeParams(raw json.RawMessage, v any) *Error {
+	if len(raw) == 0 {
+		return nil
+	}
+	dec := json.NewDecoder(strings.NewReader(string(raw)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return &Error{Code: codeInvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	return nil
+}
+
+func readMessage(r *bufio.Reader) ([]byte, error) {
+	header, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(header) == 0 {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("read rpc header: %w", err)
+	}
+	n, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid Content-Length: %q", header.Get("Content-Length"))
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("read rpc body: %w", err)
+	}
+	return body, nil
+}
+
+func writeMessage(w io.Writer, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode rpc response: %w", err)
+	}
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		return fmt.Errorf("write rpc response: %w", err)
+	}
+	return nil
+}
+
+func toIssue(it issue.Item) Issue {
+	return Issue{
+		ID:         it.ID,
+		Title:      it.Title,
+		Status:     it.Status,
+		Priority:   it.Priority,
+		Assignee:   it.Assignee,
+		Due:        it.Due,
+		Labels:     it.Labels,
+		NextAction: it.NextAction,
+		Body:       it.Body,
+		UpdatedAt:  it.UpdatedAt,
+	}
+}
+
+func normalizeIssueID(raw string) string {
+	id := strings.TrimSpace(raw)
+	if _, err := strconv.Atoi(id); err == nil {
+		return "TRK-" + id
+	}
+	return id
+}
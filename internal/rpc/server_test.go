@@ -0,0 +1,111 @@
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+func frame(bodies ...string) string {
+	var b strings.Builder
+	for _, body := range bodies {
+		fmt.Fprintf(&b, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	}
+	return b.String()
+}
+
+func readResponses(t *testing.T, raw string) []map[string]json.RawMessage {
+	t.Helper()
+	r := bufio.NewReader(strings.NewReader(raw))
+	var out []map[string]json.RawMessage
+	for {
+		body, err := readMessage(r)
+		if err != nil {
+			break
+		}
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func TestServeCreateSearchAndToggle(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	in := frame(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"issues/create","params":{"title":"Fix editor sync","priority":"p1"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"issues/search","params":{"query":"editor"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"issues/toggleDone","params":{"id":"1"}}`,
+		`{"jsonrpc":"2.0","method":"issues/toggleDone","params":{"id":"1"}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"issues/get","params":{"id":"TRK-1"}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"nope"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+		`{"jsonrpc":"2.0","id":7,"method":"initialize"}`,
+	)
+	var out strings.Builder
+	if err := Serve(context.Background(), strings.NewReader(in), &out); err != nil {
+		t.Fatalf("Serve() error: %v", err)
+	}
+
+	resps := readResponses(t, out.String())
+	if len(resps) != 6 {
+		t.Fatalf("responses = %d, want 6 (notifications and post-exit requests get none):\n%s", len(resps), out.String())
+	}
+
+	var created Issue
+	if err := json.Unmarshal(resps[1]["result"], &created); err != nil || created.ID != "TRK-1" || created.Priority != "p1" {
+		t.Fatalf("unexpected create result: %s", resps[1]["result"])
+	}
+	var found struct {
+		Items []Issue `json:"items"`
+	}
+	if err := json.Unmarshal(resps[2]["result"], &found); err != nil || len(found.Items) != 1 {
+		t.Fatalf("unexpected search result: %s", resps[2]["result"])
+	}
+	var toggled Issue
+	if err := json.Unmarshal(resps[3]["result"], &toggled); err != nil || toggled.Status != issue.StatusDone {
+		t.Fatalf("unexpected toggle result: %s", resps[3]["result"])
+	}
+	var got Issue
+	if err := json.Unmarshal(resps[4]["result"], &got); err != nil || got.Status != issue.StatusTodo {
+		t.Fatalf("notification toggle should have reopened the issue: %s", resps[4]["result"])
+	}
+	var rpcErr Error
+	if err := json.Unmarshal(resps[5]["error"], &rpcErr); err != nil || rpcErr.Code != codeMethodNotFound {
+		t.Fatalf("unexpected error response: %s", resps[5]["error"])
+	}
+}
+
+func TestServeReportsBadParams(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	in := frame(
+		`{"jsonrpc":"2.0","id":1,"method":"issues/create","params":{"title":""}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"issues/setStatus","params":{"id":"TRK-9","status":"done"}}`,
+		`not json`,
+	)
+	var out strings.Builder
+	if err := Serve(context.Background(), strings.NewReader(in), &out); err != nil {
+		t.Fatalf("Serve() error: %v", err)
+	}
+	resps := readResponses(t, out.String())
+	if len(resps) != 3 {
+		t.Fatalf("responses = %d, want 3", len(resps))
+	}
+	wantCodes := []int{codeInvalidParams, codeServerError, codeParseError}
+	for i, want := range wantCodes {
+		var rpcErr Error
+		if err := json.Unmarshal(resps[i]["error"], &rpcErr); err != nil || rpcErr.Code != want {
+			t.Fatalf("response %d error = %s, want code %d", i, resps[i]["error"], want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package script
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/myuon/track/internal/store/sqlite"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// api is what a handler call may do to the store. changed records whether
+// any of its writes went through.
+type api struct {
+	ctx     context.Context
+	store   *sqlite.Store
+	changed bool
+}
+
+// settable maps the keyword arguments of issue.set to the fields they
+// update.
+var settable = map[string]func(in *sqlite.UpdateIssueInput, v *string){
+	"title":       func(in *sqlite.UpdateIssueInput, v *string) { in.Title = v },
+	"status":      func(in *sqlite.UpdateIssueInput, v *string) { in.Status = v },
+	"priority":    func(in *sqlite.UpdateIssueInput, v *string) { in.Priority = v },
+	"assignee":    func(in *sqlite.UpdateIssueInput, v *string) { in.Assignee = v },
+	"due":         func(in *sqlite.UpdateIssueInput, v *string) { in.Due = v },
+	"next_action": func(in *sqlite.UpdateIssueInput, v *string) { in.NextAction = v },
+	"estimate":    func(in *sqlite.UpdateIssueInput, v *string) { in.Estimate = v },
+}
+
+// issue returns the script value for issue id: its fields as read now, and
+// the methods set(**fields), add_label(l), remove_label(l), and
+// comment(text). The fields are a snapshot; call track.get to see the
+// issue after a change.
+func (a *api) issue(id string) (starlark.Value, error) {
+	it, err := a.store.GetIssue(a.ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	labels := make([]starlark.Value, 0, len(it.Labels))
+	for _, l := range it.Labels {
+		labels = append(labels, starlark.String(l))
+	}
+	fields := starlark.StringDict{
+		"id":          starlark.String(it.ID),
+		"title":       starlark.String(it.Title),
+		"status":      starlark.String(it.Status),
+		"priority":    starlark.String(it.Priority),
+		"assignee":    starlark.String(it.Assignee),
+		"due":         starlark.String(it.Due),
+		"labels":      starlark.NewList(labels),
+		"next_action": starlark.String(it.NextAction),
+		"estimate":    starlark.String(it.Estimate),
+		"body":        starlark.String(it.Body),
+		"pinned":      starlark.Bool(it.Pinned),
+		"set": starlark.NewBuiltin("set", func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			if len(args) > 0 {
+				return nil, fmt.Errorf("%s: fields are keyword arguments, e.g. set(status=\"ready\")", b.Name())
+			}
+			return starlark.None, a.set(it.ID, kwargs)
+		}),
+		"add_label": starlark.NewBuiltin("add_label", func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var label string
+			if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &label); err != nil {
+				return nil, err
+			}
+			return starlark.None, a.write(func() error {
+				_, err := a.store.AddLabel(a.ctx, it.ID, label)
+				return err
+			})
+		}),
+		"remove_label": starlark.NewBuiltin("remove_label", func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var label string
+			if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &label); err != nil {
+				return nil, err
+			}
+			return starlark.None, a.write(func() error {
+				_, err := a.store.RemoveLabel(a.ctx, it.ID, label)
+				return err
+			})
+		}),
+		"comment": starlark.NewBuiltin("comment", func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var text string
+			if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &text); err != nil {
+				return nil, err
+			}
+			return starlark.None, a.comment(it.ID, text)
+		}),
+	}
+	return starlarkstruct.FromStringDict(starlark.String("issue"), fields), nil
+}
+
+func (a *api) set(id string, kwargs []starlark.Tuple) error {
+	var in sqlite.UpdateIssueInput
+	for _, kv := range kwargs {
+		name := string(kv[0].(starlark.String))
+		apply, ok := settable[name]
+		if !ok {
+			return fmt.Errorf("set: unknown field %s (want one of %s)", name, strings.Join(settableNames(), ", "))
+		}
+		v, ok := starlark.AsString(kv[1])
+		if !ok {
+			return fmt.Errorf("set: %s must be a string, not %s", name, kv[1].Type())
+		}
+		apply(&in, &v)
+	}
+	if len(kwargs) == 0 {
+		return nil
+	}
+	return a.write(func() error {
+		_, err := a.store.UpdateIssue(a.ctx, id, in)
+		return err
+	})
+}
+
+// comment adds a comment to the issue, like track comment add does.
+func (a *api) comment(id, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("comment: text must not be empty")
+	}
+	return a.write(func() error {
+		_, err := a.store.AddComment(a.ctx, id, text)
+		return err
+	})
+}
+
+func (a *api) write(fn func() error) error {
+	if err := fn(); err != nil {
+		return err
+	}
+	a.changed = true
+	return nil
+}
+
+func settableNames() []string {
+	names := make([]string, 0, len(settable))
+	for name := range settable {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
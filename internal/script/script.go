@@ -0,0 +1,227 @@
+// Package script runs Starlark scripts from $TRACK_HOME/scripts on issue
+// events. A script defines handlers named after the event, such as
+// on_created(issue) for issue.created or on_status_changed(issue) for
+// issue.status_changed, and changes issues through the small API of the
+// issue value it is given; scripts have no file, network, or process access.
+//
+// Scripts are read again whenever they change on disk, so a running
+// `track serve` picks up edits without a restart.
+package script
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/store/sqlite"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+// Ext is the file extension of scripts in Dir.
+const Ext = ".star"
+
+// maxSteps bounds how much work one handler call may do, so a runaway loop
+// fails the event instead of hanging it.
+const maxSteps = 10_000_000
+
+// Script is one file in Dir. Err is set when it does not load; Handlers
+// lists the events it handles otherwise.
+type Script struct {
+	Name     string
+	Path     string
+	Handlers []string
+	Err      error
+}
+
+// Dir is where scripts are kept, $TRACK_HOME/scripts.
+func Dir() (string, error) {
+	home, err := appconfig.HomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "scripts"), nil
+}
+
+// Handler is the name of the function that handles event, e.g. on_created
+// for issue.created.
+func Handler(event string) string {
+	return "on_" + strings.ReplaceAll(strings.TrimPrefix(event, "issue."), ".", "_")
+}
+
+// List loads every script in Dir, in name order. A missing Dir has no
+// scripts.
+func List(events []string) ([]Script, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []Script
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != Ext {
+			continue
+		}
+		out = append(out, Load(filepath.Join(dir, e.Name()), events))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// Load reads the script at path and reports which of events it handles.
+func Load(path string, events []string) Script {
+	s := Script{Name: strings.TrimSuffix(filepath.Base(path), Ext), Path: path}
+	globals, err := load(path)
+	if err != nil {
+		s.Err = err
+		return s
+	}
+	for _, ev := range events {
+		if _, ok := globals[Handler(ev)].(starlark.Callable); ok {
+			s.Handlers = append(s.Handlers, ev)
+		}
+	}
+	return s
+}
+
+// Run calls the handler for event in every script that has one, in name
+// order, and reports whether any of them changed an issue. Writes are
+// attributed to script:<name>. A script that fails to load or whose handler
+// fails stops the run.
+func Run(ctx context.Context, store *sqlite.Store, event, issueID string) (bool, error) {
+	if issueID == "" {
+		return false, nil
+	}
+	scripts, err := List([]string{event})
+	if err != nil {
+		return false, err
+	}
+	changed := false
+	for _, s := range scripts {
+		if s.Err != nil {
+			return changed, fmt.Errorf("script(%s): %w", s.Name, s.Err)
+		}
+		if len(s.Handlers) == 0 {
+			continue
+		}
+		c, err := RunScript(ctx, store, s.Path, event, issueID)
+		changed = changed || c
+		if err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
+// RunScript calls the handler for event in the script at path with the
+// issue issueID. A script without that handler does nothing.
+func RunScript(ctx context.Context, store *sqlite.Store, path, event, issueID string) (bool, error) {
+	name := strings.TrimSuffix(filepath.Base(path), Ext)
+	globals, err := load(path)
+	if err != nil {
+		return false, fmt.Errorf("script(%s): %w", name, err)
+	}
+	fn, ok := globals[Handler(event)].(starlark.Callable)
+	if !ok {
+		return false, nil
+	}
+	api := &api{ctx: sqlite.WithActor(ctx, "script:"+name), store: store}
+	it, err := api.issue(issueID)
+	if err != nil {
+		return false, err
+	}
+
+	thread := &starlark.Thread{
+		Name:  name,
+		Print: func(_ *starlark.Thread, msg string) { fmt.Fprintf(os.Stderr, "script(%s): %s\n", name, msg) },
+	}
+	thread.SetLocal(apiKey, api)
+	thread.SetMaxExecutionSteps(maxSteps)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			thread.Cancel(ctx.Err().Error())
+		case <-done:
+		}
+	}()
+	if _, err := starlark.Call(thread, fn, starlark.Tuple{it}, nil); err != nil {
+		return api.changed, fmt.Errorf("script(%s) %s: %w", name, Handler(event), err)
+	}
+	return api.changed, nil
+}
+
+type loaded struct {
+	modTime time.Time
+	size    int64
+	globals starlark.StringDict
+	err     error
+}
+
+var (
+	cacheMu sync.Mutex
+	cache   = map[string]loaded{}
+)
+
+// load returns the globals of the script at path, executing it again only
+// when the file changed since the last load.
+func load(path string) (starlark.StringDict, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if l, ok := cache[path]; ok && l.modTime.Equal(info.ModTime()) && l.size == info.Size() {
+		return l.globals, l.err
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	thread := &starlark.Thread{Name: filepath.Base(path)}
+	thread.SetMaxExecutionSteps(maxSteps)
+	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, path, src, predeclared)
+	if err == nil {
+		globals.Freeze()
+	}
+	cache[path] = loaded{modTime: info.ModTime(), size: info.Size(), globals: globals, err: err}
+	return globals, err
+}
+
+// predeclared is what every script sees besides the Starlark built-ins.
+// track.get is bound per call, so the module only names it here.
+var predeclared = starlark.StringDict{
+	"track": starlarkstruct.FromStringDict(starlark.String("track"), starlark.StringDict{
+		"get": starlark.NewBuiltin("track.get", trackGet),
+	}),
+}
+
+// apiKey finds the api of the handler call running on a thread.
+const apiKey = "track.api"
+
+func trackGet(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var id string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &id); err != nil {
+		return nil, err
+	}
+	a, ok := thread.Local(apiKey).(*api)
+	if !ok {
+		return nil, fmt.Errorf("%s: only available inside a handler", b.Name())
+	}
+	return a.issue(strings.TrimSpace(id))
+}
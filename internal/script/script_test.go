@@ -0,0 +1,192 @@
+package script
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func openStore(t *testing.T) (context.Context, *sqlite.Store, string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("TRACK_HOME", home)
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	dir := filepath.Join(home, "scripts")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return ctx, store, dir
+}
+
+func writeScript(t *testing.T, dir, name, src string) string {
+	t.Helper()
+	path := filepath.Join(dir, name+Ext)
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunAppliesHandlerChanges(t *testing.T) {
+	ctx, store, dir := openStore(t)
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "crash on start", Status: issue.StatusTodo, Priority: "p2", Labels: []string{"bug"}})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	writeScript(t, dir, "triage", `
+def on_created(issue):
+    if "bug" in issue.labels and not issue.assignee:
+        issue.set(assignee="triage", priority="p1")
+        issue.add_label("needs-repro")
+        issue.comment("routed to triage")
+
+def on_completed(issue):
+    fail("must not run for issue.created")
+`)
+
+	changed, err := Run(ctx, store, sqlite.EventIssueCreated, it.ID)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !changed {
+		t.Fatal("expected the script to report a change")
+	}
+	got, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Assignee != "triage" || got.Priority != "p1" {
+		t.Fatalf("assignee/priority = %q/%q", got.Assignee, got.Priority)
+	}
+	if strings.Join(got.Labels, ",") != "bug,needs-repro" {
+		t.Fatalf("labels = %v", got.Labels)
+	}
+	comments, err := store.ListComments(ctx, it.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 1 || comments[0].Body != "routed to triage" || comments[0].Author != "script:triage" {
+		t.Fatalf("comments = %+v", comments)
+	}
+	if strings.Contains(got.Body, "routed to triage") {
+		t.Fatalf("the comment should not be written into the body: %q", got.Body)
+	}
+
+	entries, err := store.ListActivity(ctx, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attributed := false
+	for _, a := range entries {
+		attributed = attributed || a.Actor == "script:triage"
+	}
+	if !attributed {
+		t.Fatalf("no activity attributed to script:triage: %+v", entries)
+	}
+
+	// Nothing left to do: the handler runs but changes nothing.
+	changed, err = Run(ctx, store, sqlite.EventIssueCreated, it.ID)
+	if err != nil || changed {
+		t.Fatalf("second run = %v, %v", changed, err)
+	}
+}
+
+func TestRunReloadsChangedScripts(t *testing.T) {
+	ctx, store, dir := openStore(t)
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "a", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := writeScript(t, dir, "next", `
+def on_updated(issue):
+    issue.set(next_action="first")
+`)
+	if _, err := Run(ctx, store, sqlite.EventIssueUpdated, it.ID); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	writeScript(t, dir, "next", `
+def on_updated(issue):
+    issue.set(next_action="second version")
+`)
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Run(ctx, store, sqlite.EventIssueUpdated, it.ID); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	got, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.NextAction != "second version" {
+		t.Fatalf("next_action = %q, script was not reloaded", got.NextAction)
+	}
+}
+
+func TestRunReportsScriptErrors(t *testing.T) {
+	ctx, store, dir := openStore(t)
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "a", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeScript(t, dir, "bad", `
+def on_updated(issue):
+    issue.set(colour="red")
+`)
+	if _, err := Run(ctx, store, sqlite.EventIssueUpdated, it.ID); err == nil || !strings.Contains(err.Error(), "unknown field colour") {
+		t.Fatalf("err = %v", err)
+	}
+
+	writeScript(t, dir, "bad", `
+def on_updated(issue):
+    for i in range(1000000000):
+        pass
+`)
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(dir, "bad"+Ext), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Run(ctx, store, sqlite.EventIssueUpdated, it.ID); err == nil || !strings.Contains(err.Error(), "too many steps") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestListReportsHandlers(t *testing.T) {
+	_, _, dir := openStore(t)
+	writeScript(t, dir, "b", "def on_status_changed(issue):\n    pass\n")
+	writeScript(t, dir, "a", "def on_created(issue):\n    pass\ndef on_completed(issue):\n    pass\n")
+	writeScript(t, dir, "broken", "def on_created(issue)\n")
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a script"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	events := []string{sqlite.EventIssueCreated, sqlite.EventIssueUpdated, sqlite.EventIssueStatusChange, sqlite.EventIssueCompleted}
+	scripts, err := List(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scripts) != 3 {
+		t.Fatalf("scripts = %+v", scripts)
+	}
+	if scripts[0].Name != "a" || strings.Join(scripts[0].Handlers, ",") != "issue.created,issue.completed" {
+		t.Fatalf("a = %+v", scripts[0])
+	}
+	if scripts[1].Name != "b" || strings.Join(scripts[1].Handlers, ",") != "issue.status_changed" {
+		t.Fatalf("b = %+v", scripts[1])
+	}
+	if scripts[2].Name != "broken" || scripts[2].Err == nil {
+		t.Fatalf("broken = %+v", scripts[2])
+	}
+}
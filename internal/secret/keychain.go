@@ -0,0 +1,89 @@
+package secret
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keychainService is the service name track's keychain items are filed
+// under.
+const keychainService = "track"
+
+type keychain interface {
+	set(name, value string) error
+	get(name string) (string, error)
+	delete(name string) error
+}
+
+// osKeychain returns the keychain of this OS when its command-line tool is
+// installed: security on macOS and secret-tool (libsecret) on Linux.
+func osKeychain() (keychain, bool) {
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("security"); err == nil {
+			return macKeychain{}, true
+		}
+	case "linux":
+		if _, err := exec.LookPath("secret-tool"); err == nil {
+			return secretTool{}, true
+		}
+	}
+	return nil, false
+}
+
+// macKeychain drives security(1). It can only take the value as an
+// argument, so it is briefly visible to other processes of the same user.
+type macKeychain struct{}
+
+func (macKeychain) set(name, value string) error {
+	return runKeychain(nil, "security", "add-generic-password", "-U", "-s", keychainService, "-a", name, "-w", value)
+}
+
+func (macKeychain) get(name string) (string, error) {
+	out, err := runKeychainOutput("security", "find-generic-password", "-s", keychainService, "-a", name, "-w")
+	return strings.TrimSuffix(out, "\n"), err
+}
+
+func (macKeychain) delete(name string) error {
+	return runKeychain(nil, "security", "delete-generic-password", "-s", keychainService, "-a", name)
+}
+
+type secretTool struct{}
+
+func (secretTool) set(name, value string) error {
+	return runKeychain(strings.NewReader(value), "secret-tool", "store", "--label", keychainService+" "+name, "service", keychainService, "account", name)
+}
+
+func (secretTool) get(name string) (string, error) {
+	return runKeychainOutput("secret-tool", "lookup", "service", keychainService, "account", name)
+}
+
+func (secretTool) delete(name string) error {
+	return runKeychain(nil, "secret-tool", "clear", "service", keychainService, "account", name)
+}
+
+func runKeychain(stdin *strings.Reader, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s %s: %w: %s", name, args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func runKeychainOutput(name string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s %s: %w: %s", name, args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,239 @@
+// Package secret keeps tokens such as webhook URLs out of config.toml. Values
+// live in the OS keychain when one is available and otherwise encrypted in
+// $TRACK_HOME/secrets.json, and hook commands and notification settings
+// refer to them as {{secret:<name>}}.
+package secret
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	appconfig "github.com/myuon/track/internal/config"
+)
+
+// Where a secret's value is kept.
+const (
+	StoreFile     = "file"
+	StoreKeychain = "keychain"
+)
+
+var (
+	namePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+	refPattern  = regexp.MustCompile(`\{\{\s*secret:([A-Za-z0-9_.-]+)\s*\}\}`)
+)
+
+// entry is one secret in the index. Value holds the encrypted value of file
+// secrets and is empty for keychain ones.
+type entry struct {
+	Store string `json:"store"`
+	Value string `json:"value,omitempty"`
+}
+
+// Secret names a stored secret and where its value is kept.
+type Secret struct {
+	Name  string
+	Store string
+}
+
+func indexPath() (string, error) {
+	home, err := appconfig.HomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "secrets.json"), nil
+}
+
+func loadIndex() (map[string]entry, error) {
+	path, err := indexPath()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]entry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read secrets: %w", err)
+	}
+	index := map[string]entry{}
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return nil, fmt.Errorf("decode secrets: %w", err)
+	}
+	return index, nil
+}
+
+func saveIndex(index map[string]entry) error {
+	if err := appconfig.EnsureDir(); err != nil {
+		return err
+	}
+	path, err := indexPath()
+	if err != nil {
+		return err
+	}
+	raw, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode secrets: %w", err)
+	}
+	if err := os.WriteFile(path, append(raw, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write secrets: %w", err)
+	}
+	return nil
+}
+
+// ValidateName accepts letters, digits, and _ . - as used in references.
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name: %q (use letters, digits, _ . -)", name)
+	}
+	return nil
+}
+
+// Set stores value under name, replacing any previous value, and returns
+// where it was stored, which the secret_store setting decides.
+func Set(name, value string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	kc, err := chooseKeychain()
+	if err != nil {
+		return "", err
+	}
+	index, err := loadIndex()
+	if err != nil {
+		return "", err
+	}
+	if old, ok := index[name]; ok && old.Store == StoreKeychain && kc == nil {
+		if err := removeFromKeychain(name); err != nil {
+			return "", err
+		}
+	}
+
+	e := entry{Store: StoreFile}
+	if kc != nil {
+		if err := kc.set(name, value); err != nil {
+			return "", err
+		}
+		e.Store = StoreKeychain
+	} else if e.Value, err = encrypt(value); err != nil {
+		return "", err
+	}
+	index[name] = e
+	return e.Store, saveIndex(index)
+}
+
+// Get returns the value of name.
+func Get(name string) (string, error) {
+	index, err := loadIndex()
+	if err != nil {
+		return "", err
+	}
+	e, ok := index[name]
+	if !ok {
+		return "", fmt.Errorf("unknown secret: %s (see track secret set)", name)
+	}
+	if e.Store == StoreKeychain {
+		kc, ok := osKeychain()
+		if !ok {
+			return "", fmt.Errorf("secret %s is in the OS keychain, which is not available here", name)
+		}
+		return kc.get(name)
+	}
+	return decrypt(e.Value)
+}
+
+// Delete removes name from wherever it is stored.
+func Delete(name string) error {
+	index, err := loadIndex()
+	if err != nil {
+		return err
+	}
+	e, ok := index[name]
+	if !ok {
+		return fmt.Errorf("unknown secret: %s", name)
+	}
+	if e.Store == StoreKeychain {
+		if err := removeFromKeychain(name); err != nil {
+			return err
+		}
+	}
+	delete(index, name)
+	return saveIndex(index)
+}
+
+// List returns the stored secrets by name, without their values.
+func List() ([]Secret, error) {
+	index, err := loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Secret, 0, len(index))
+	for name, e := range index {
+		out = append(out, Secret{Name: name, Store: e.Store})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// Expand replaces every {{secret:<name>}} in s with the secret's value. The
+// value goes in as is, so quote the reference where a command line needs it.
+func Expand(s string) (string, error) {
+	var firstErr error
+	out := refPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := refPattern.FindStringSubmatch(ref)[1]
+		v, err := Get(name)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return v
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return out, nil
+}
+
+// Ref returns the reference that Expand replaces with name's value.
+func Ref(name string) string {
+	return "{{secret:" + name + "}}"
+}
+
+// HasRef reports whether s refers to a secret.
+func HasRef(s string) bool {
+	return refPattern.MatchString(s)
+}
+
+func removeFromKeychain(name string) error {
+	kc, ok := osKeychain()
+	if !ok {
+		return fmt.Errorf("secret %s is in the OS keychain, which is not available here", name)
+	}
+	return kc.delete(name)
+}
+
+// chooseKeychain returns the keychain to store new secrets in, or nil for
+// the encrypted file.
+func chooseKeychain() (keychain, error) {
+	cfg, err := appconfig.Load()
+	if err != nil {
+		return nil, err
+	}
+	kc, ok := osKeychain()
+	switch cfg.SecretStore {
+	case StoreFile:
+		return nil, nil
+	case StoreKeychain:
+		if !ok {
+			return nil, fmt.Errorf("secret_store is keychain, but no OS keychain is available")
+		}
+		return kc, nil
+	default:
+		if !ok {
+			return nil, nil
+		}
+		return kc, nil
+	}
+}
@@ -0,0 +1,100 @@
+package secret
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	appconfig "github.com/myuon/track/internal/config"
+)
+
+func useFileStore(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("TRACK_HOME", home)
+	cfg := appconfig.Default()
+	cfg.SecretStore = StoreFile
+	if err := appconfig.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	return home
+}
+
+func TestFileSecretsRoundTripEncrypted(t *testing.T) {
+	home := useFileStore(t)
+	const url = "https://hooks.slack.com/services/T000/B000/XXXX"
+	store, err := Set("slack_webhook", url)
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if store != StoreFile {
+		t.Fatalf("store = %q", store)
+	}
+	raw, err := os.ReadFile(filepath.Join(home, "secrets.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), "hooks.slack.com") {
+		t.Fatalf("secrets.json holds the plain value: %s", raw)
+	}
+	if got, err := Get("slack_webhook"); err != nil || got != url {
+		t.Fatalf("Get = %q, %v", got, err)
+	}
+
+	got, err := Expand("curl -d text=hi '{{secret:slack_webhook}}' {{ secret:slack_webhook }}")
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	if want := "curl -d text=hi '" + url + "' " + url; got != want {
+		t.Fatalf("Expand = %q, want %q", got, want)
+	}
+	if _, err := Expand("{{secret:missing}}"); err == nil || !strings.Contains(err.Error(), "unknown secret: missing") {
+		t.Fatalf("Expand missing = %v", err)
+	}
+	if got, err := Expand("no references"); err != nil || got != "no references" {
+		t.Fatalf("Expand plain = %q, %v", got, err)
+	}
+
+	if _, err := Set("a", "1"); err != nil {
+		t.Fatal(err)
+	}
+	list, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0] != (Secret{Name: "a", Store: StoreFile}) || list[1].Name != "slack_webhook" {
+		t.Fatalf("List = %+v", list)
+	}
+	if err := Delete("slack_webhook"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := Get("slack_webhook"); err == nil {
+		t.Fatal("expected deleted secret to be gone")
+	}
+	if err := Delete("slack_webhook"); err == nil {
+		t.Fatal("expected error deleting an unknown secret")
+	}
+}
+
+func TestSetRejectsBadNames(t *testing.T) {
+	useFileStore(t)
+	for _, name := range []string{"", "with space", "a}}b"} {
+		if _, err := Set(name, "v"); err == nil {
+			t.Errorf("Set(%q) succeeded", name)
+		}
+	}
+}
+
+func TestDecryptFailsWithAnotherKey(t *testing.T) {
+	home := useFileStore(t)
+	if _, err := Set("token", "s3cret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(home, "secret.key")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get("token"); err == nil {
+		t.Fatal("expected decrypting with a new key to fail")
+	}
+}
@@ -0,0 +1,193 @@
+// Package service applies issue mutations so the CLI, API, and UI behave the
+// same for the same change. The store publishes the resulting events; a
+// change spanning several store calls is published once, as a batch.
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	// Linking hooks subscribes them to the store's events.
+	_ "github.com/myuon/track/internal/hooks"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+// Update describes a change to an issue. Nil fields are left unchanged.
+// Labels replaces the full label set; Project links the issue to a project
+// key, and "" or "none" unlinks it.
+type Update struct {
+	sqlite.UpdateIssueInput
+	Labels  *[]string
+	Project *string
+}
+
+func (u Update) empty() bool {
+	in := u.UpdateIssueInput
+	return in.Title == nil && in.Body == nil && in.AppendBody == nil && in.PrependBody == nil && in.Status == nil &&
+		in.Priority == nil && in.Due == nil && in.Assignee == nil && in.NextAction == nil && in.Estimate == nil && in.ExternalID == nil &&
+		in.BlockedReason == nil && u.Labels == nil && u.Project == nil
+}
+
+func (u Update) hasFields() bool {
+	return !Update{UpdateIssueInput: u.UpdateIssueInput}.empty()
+}
+
+// HookError reports that a mutation was saved but one of the hooks it
+// triggered failed. Its message is the underlying hook error.
+type HookError = sqlite.EventError
+
+func CreateIssue(ctx context.Context, store *sqlite.Store, item issue.Item) (issue.Item, error) {
+	return store.CreateIssue(ctx, item)
+}
+
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different issue")
+
+// CreateIssueIdempotent creates item in project ("" for none) unless key was
+// already used within sqlite.IdempotencyTTL, in which case the issue created
+// then is returned and replayed is true. Reusing a key for a different issue
+// is an error. An empty key always creates.
+func CreateIssueIdempotent(ctx context.Context, store *sqlite.Store, key string, item issue.Item, project string) (created issue.Item, replayed bool, err error) {
+	key = strings.TrimSpace(key)
+	project = strings.TrimSpace(project)
+	if key == "" {
+		if created, err = store.CreateIssue(ctx, item); err != nil {
+			return issue.Item{}, false, err
+		}
+		return created, false, setCreatedProject(ctx, store, created.ID, project)
+	}
+
+	fingerprint, err := issueFingerprint(item, project)
+	if err != nil {
+		return issue.Item{}, false, err
+	}
+	created, rec, ok, err := store.CreateIssueOnce(ctx, item, sqlite.IdempotencyRecord{Key: key, Fingerprint: fingerprint})
+	if err != nil {
+		return issue.Item{}, false, err
+	}
+	if ok {
+		return created, false, setCreatedProject(ctx, store, created.ID, project)
+	}
+	if rec.Fingerprint != fingerprint {
+		return issue.Item{}, false, fmt.Errorf("%w: %q", ErrIdempotencyKeyReused, key)
+	}
+	existing, err := store.GetIssue(ctx, rec.IssueID)
+	if err != nil {
+		return issue.Item{}, false, err
+	}
+	return existing, true, nil
+}
+
+func setCreatedProject(ctx context.Context, store *sqlite.Store, id, project string) error {
+	if project == "" {
+		return nil
+	}
+	return store.SetIssueProject(ctx, id, project)
+}
+
+// issueFingerprint covers everything a create request can set, so a key is
+// only replayed for the same request.
+func issueFingerprint(item issue.Item, project string) (string, error) {
+	raw, err := json.Marshal(struct {
+		Title, Body, Status, Priority, Assignee, Due, NextAction, Estimate string
+		Labels                                                             []string
+		ExternalID, Project                                                string
+		Links                                                              []issue.Link
+	}{item.Title, item.Body, item.Status, item.Priority, item.Assignee, item.Due, item.NextAction, item.Estimate,
+		item.Labels, item.ExternalID, project, item.Links})
+	if err != nil {
+		return "", fmt.Errorf("fingerprint issue: %w", err)
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// UpdateIssue applies u and publishes issue.updated, plus
+// issue.status_changed and issue.completed when the status actually changed.
+// The whole update is validated before anything is written. Under the raise
+// priority_inheritance policy, raising the priority of an open issue also
+// raises the open issues it waits on.
+func UpdateIssue(ctx context.Context, store *sqlite.Store, id string, u Update) (issue.Item, error) {
+	if u.empty() {
+		return issue.Item{}, fmt.Errorf("no fields to update")
+	}
+	before, err := store.GetIssue(ctx, id)
+	if err != nil {
+		return issue.Item{}, err
+	}
+
+	project := ""
+	if u.Project != nil {
+		project = strings.TrimSpace(*u.Project)
+		if project == "none" {
+			project = ""
+		}
+		if project != "" {
+			if err := sqlite.ValidateProjectKey(project); err != nil {
+				return issue.Item{}, err
+			}
+			if _, err := store.GetProject(ctx, project); err != nil {
+				return issue.Item{}, err
+			}
+		}
+	}
+	if u.Labels != nil {
+		for _, label := range *u.Labels {
+			if strings.TrimSpace(label) == "" {
+				return issue.Item{}, fmt.Errorf("label must not be empty")
+			}
+		}
+	}
+
+	batchCtx, flush := sqlite.BatchEvents(ctx)
+	after := before
+	if u.hasFields() {
+		if after, err = store.UpdateIssue(batchCtx, before.ID, u.UpdateIssueInput); err != nil {
+			return issue.Item{}, err
+		}
+	}
+	if u.Labels != nil {
+		if after, err = store.SetLabels(batchCtx, before.ID, *u.Labels); err != nil {
+			return issue.Item{}, err
+		}
+	}
+	if u.Project != nil {
+		if err := store.SetIssueProject(batchCtx, before.ID, project); err != nil {
+			return issue.Item{}, err
+		}
+	}
+	if u.Priority != nil && issue.PriorityRank(after.Priority) < issue.PriorityRank(before.Priority) && isOpen(after) {
+		policy, err := PriorityInheritance()
+		if err != nil {
+			return issue.Item{}, err
+		}
+		if policy == InheritRaise {
+			if err := raiseBlockers(batchCtx, store, after); err != nil {
+				return issue.Item{}, err
+			}
+		}
+	}
+	return after, flush()
+}
+
+func SetStatus(ctx context.Context, store *sqlite.Store, id, status string) (issue.Item, error) {
+	return UpdateIssue(ctx, store, id, Update{UpdateIssueInput: sqlite.UpdateIssueInput{Status: &status}})
+}
+
+func Reorder(ctx context.Context, store *sqlite.Store, id, beforeID, afterID string) error {
+	return store.Reorder(ctx, id, beforeID, afterID)
+}
+
+// SetSection replaces (or appends, when appendContent is set) the content of
+// a body section.
+func SetSection(ctx context.Context, store *sqlite.Store, id, title, content string, appendContent bool) (issue.Item, error) {
+	if appendContent {
+		return store.AppendIssueSection(ctx, id, title, content)
+	}
+	return store.SetIssueSection(ctx, id, title, content)
+}
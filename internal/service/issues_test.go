@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/myuon/track/internal/hooks"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+// recordEvents registers a hook for every issue event that appends
+// "<event>:<issue>" to a file and returns a function reading the log.
+func recordEvents(t *testing.T, ctx context.Context, store *sqlite.Store) func() []string {
+	t.Helper()
+	outFile := filepath.Join(t.TempDir(), "events.out")
+	for _, event := range []string{hooks.IssueCreated, hooks.IssueUpdated, hooks.IssueStatusChange, hooks.IssueCompleted} {
+		cmd := "/bin/sh -c 'echo $TRACK_EVENT:$TRACK_ISSUE_ID >> " + outFile + "'"
+		if err := store.AddHook(ctx, event, cmd, ""); err != nil {
+			t.Fatalf("add hook: %v", err)
+		}
+	}
+	return func() []string {
+		raw, err := os.ReadFile(outFile)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			t.Fatalf("read hook output: %v", err)
+		}
+		_ = os.Remove(outFile)
+		return strings.Fields(string(raw))
+	}
+}
+
+func openStore(t *testing.T) *sqlite.Store {
+	t.Helper()
+	t.Setenv("TRACK_HOME", t.TempDir())
+	store, err := sqlite.Open(context.Background())
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestUpdateIssueFiresEventsForStatusChanges(t *testing.T) {
+	store := openStore(t)
+	ctx := context.Background()
+	events := recordEvents(t, ctx, store)
+
+	it, err := CreateIssue(ctx, store, issue.Item{Title: "a", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	if got := strings.Join(events(), " "); got != "issue.created:"+it.ID {
+		t.Fatalf("create events = %q", got)
+	}
+
+	priority := "p1"
+	if _, err := UpdateIssue(ctx, store, it.ID, Update{UpdateIssueInput: sqlite.UpdateIssueInput{Priority: &priority}}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+	if got := strings.Join(events(), " "); got != "issue.updated:"+it.ID {
+		t.Fatalf("field update events = %q", got)
+	}
+
+	if _, err := SetStatus(ctx, store, it.ID, issue.StatusDone); err != nil {
+		t.Fatalf("SetStatus() error: %v", err)
+	}
+	want := "issue.updated:" + it.ID + " issue.status_changed:" + it.ID + " issue.completed:" + it.ID
+	if got := strings.Join(events(), " "); got != want {
+		t.Fatalf("status events = %q, want %q", got, want)
+	}
+
+	if _, err := SetStatus(ctx, store, it.ID, issue.StatusDone); err != nil {
+		t.Fatalf("SetStatus() repeat error: %v", err)
+	}
+	if got := strings.Join(events(), " "); got != "issue.updated:"+it.ID {
+		t.Fatalf("unchanged status should only fire issue.updated, got %q", got)
+	}
+}
+
+func TestUpdateIssueValidatesBeforeWriting(t *testing.T) {
+	store := openStore(t)
+	ctx := context.Background()
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "before", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	title := "after"
+	project := "missing"
+	if _, err := UpdateIssue(ctx, store, it.ID, Update{UpdateIssueInput: sqlite.UpdateIssueInput{Title: &title}, Project: &project}); err == nil {
+		t.Fatalf("UpdateIssue() with unknown project should fail")
+	}
+	got, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if got.Title != "before" {
+		t.Fatalf("title = %q, want unchanged", got.Title)
+	}
+	if _, err := UpdateIssue(ctx, store, it.ID, Update{}); err == nil || err.Error() != "no fields to update" {
+		t.Fatalf("empty update error = %v", err)
+	}
+}
+
+func TestHookFailureIsReportedAfterSave(t *testing.T) {
+	store := openStore(t)
+	ctx := context.Background()
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "a", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	if err := store.AddHook(ctx, hooks.IssueUpdated, "/bin/sh -c 'exit 3'", ""); err != nil {
+		t.Fatalf("add hook: %v", err)
+	}
+
+	title := "renamed"
+	_, err = UpdateIssue(ctx, store, it.ID, Update{UpdateIssueInput: sqlite.UpdateIssueInput{Title: &title}})
+	var hookErr *HookError
+	if !errors.As(err, &hookErr) || hookErr.Event != hooks.IssueUpdated {
+		t.Fatalf("expected HookError for issue.updated, got %v", err)
+	}
+	got, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if got.Title != "renamed" {
+		t.Fatalf("update should be saved even though the hook failed, title = %q", got.Title)
+	}
+}
+
+func TestCreateIssueIdempotentConcurrentRequests(t *testing.T) {
+	store := openStore(t)
+	ctx := context.Background()
+	item := issue.Item{Title: "retried", Status: issue.StatusTodo, Priority: "p2"}
+
+	const requests = 8
+	type result struct {
+		it       issue.Item
+		replayed bool
+		err      error
+	}
+	results := make(chan result, requests)
+	for range requests {
+		go func() {
+			// Each request opens its own store, as the API does.
+			s, err := sqlite.Open(ctx)
+			if err != nil {
+				results <- result{err: err}
+				return
+			}
+			defer s.Close()
+			it, replayed, err := CreateIssueIdempotent(ctx, s, "key-1", item, "")
+			results <- result{it, replayed, err}
+		}()
+	}
+	created := 0
+	ids := map[string]bool{}
+	for range requests {
+		r := <-results
+		if r.err != nil {
+			t.Fatalf("CreateIssueIdempotent() error: %v", r.err)
+		}
+		if !r.replayed {
+			created++
+		}
+		ids[r.it.ID] = true
+	}
+	if created != 1 || len(ids) != 1 {
+		t.Fatalf("created %d issues with IDs %v, want one", created, ids)
+	}
+	if n, err := store.CountIssues(ctx, sqlite.ListFilter{}); err != nil || n != 1 {
+		t.Fatalf("CountIssues() = %d, %v; want 1", n, err)
+	}
+
+	other := item
+	other.Title = "different"
+	if _, _, err := CreateIssueIdempotent(ctx, store, "key-1", other, ""); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Fatalf("reused key error = %v, want ErrIdempotencyKeyReused", err)
+	}
+	if id, err := store.NextIssueID(ctx); err != nil || id != "TRK-2" {
+		t.Fatalf("NextIssueID() = %s, %v; replays should not use up IDs", id, err)
+	}
+}
+
+func TestCreateIssueIdempotentKeyCoversProject(t *testing.T) {
+	store := openStore(t)
+	ctx := context.Background()
+	for _, key := range []string{"web", "api"} {
+		if _, err := store.CreateProject(ctx, key, key, ""); err != nil {
+			t.Fatalf("CreateProject(%s) error: %v", key, err)
+		}
+	}
+	item := issue.Item{Title: "retried", Status: issue.StatusTodo, Priority: "p2"}
+
+	first, replayed, err := CreateIssueIdempotent(ctx, store, "key-1", item, "web")
+	if err != nil || replayed {
+		t.Fatalf("first create = %v, replayed %v", err, replayed)
+	}
+	if p, err := store.GetIssueProject(ctx, first.ID); err != nil || p != "web" {
+		t.Fatalf("project = %q, %v; want web", p, err)
+	}
+	again, replayed, err := CreateIssueIdempotent(ctx, store, "key-1", item, "web")
+	if err != nil || !replayed || again.ID != first.ID {
+		t.Fatalf("same request = %s, replayed %v, %v; want a replay of %s", again.ID, replayed, err, first.ID)
+	}
+	if _, _, err := CreateIssueIdempotent(ctx, store, "key-1", item, "api"); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Fatalf("key reused for another project: error = %v, want ErrIdempotencyKeyReused", err)
+	}
+	other := item
+	other.ExternalID = "JIRA-1"
+	if _, _, err := CreateIssueIdempotent(ctx, store, "key-1", other, "web"); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Fatalf("key reused with another external_id: error = %v, want ErrIdempotencyKeyReused", err)
+	}
+	if p, err := store.GetIssueProject(ctx, first.ID); err != nil || p != "web" {
+		t.Fatalf("project after refused reuse = %q, %v; want web", p, err)
+	}
+}
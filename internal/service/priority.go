@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+// The priority_inheritance policies.
+const (
+	InheritShow  = "show"
+	InheritRaise = "raise"
+	InheritOff   = "off"
+)
+
+// InheritedPriority is a priority an open issue takes on from From, an open
+// issue that depends on it directly or through other open issues: an epic's
+// p0 makes the work it waits on p0 as well.
+type InheritedPriority struct {
+	Priority string
+	From     string
+}
+
+// PriorityInheritance returns the priority_inheritance setting.
+func PriorityInheritance() (string, error) {
+	cfg, err := appconfig.Load()
+	if err != nil {
+		return "", err
+	}
+	if cfg.PriorityInheritance == "" {
+		return InheritShow, nil
+	}
+	return cfg.PriorityInheritance, nil
+}
+
+// InheritedPriorities maps each open issue whose effective priority is more
+// urgent than its own to the priority it inherits. It is empty when
+// priority_inheritance is off.
+func InheritedPriorities(ctx context.Context, store *sqlite.Store) (map[string]InheritedPriority, error) {
+	policy, err := PriorityInheritance()
+	if err != nil || policy == InheritOff {
+		return map[string]InheritedPriority{}, err
+	}
+	g, err := loadDependencyGraph(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+	return g.inheritedPriorities(), nil
+}
+
+// InheritedPriorityOf returns the priority id inherits, as
+// InheritedPriorities would, reading only the issues that depend on it.
+func InheritedPriorityOf(ctx context.Context, store *sqlite.Store, id string) (InheritedPriority, bool, error) {
+	policy, err := PriorityInheritance()
+	if err != nil || policy == InheritOff {
+		return InheritedPriority{}, false, err
+	}
+	deps, err := store.ListDependencyClosure(ctx, id)
+	if err != nil {
+		return InheritedPriority{}, false, err
+	}
+	g, err := newDependencyGraph(ctx, store, deps)
+	if err != nil {
+		return InheritedPriority{}, false, err
+	}
+	in, ok := g.inheritedPriorities()[id]
+	return in, ok, nil
+}
+
+func (g dependencyGraph) inheritedPriorities() map[string]InheritedPriority {
+	// The most urgent dependents are visited first, so the first priority
+	// an issue inherits is the one it keeps.
+	var dependents []issue.Item
+	for _, it := range g.items {
+		if isOpen(it) && len(g.deps[it.ID]) > 0 {
+			dependents = append(dependents, it)
+		}
+	}
+	sort.SliceStable(dependents, func(i, j int) bool {
+		return issue.PriorityRank(dependents[i].Priority) < issue.PriorityRank(dependents[j].Priority)
+	})
+
+	out := map[string]InheritedPriority{}
+	for _, d := range dependents {
+		rank := issue.PriorityRank(d.Priority)
+		for _, b := range g.openBlockers(d.ID) {
+			if _, done := out[b.ID]; done || issue.PriorityRank(b.Priority) <= rank {
+				continue
+			}
+			out[b.ID] = InheritedPriority{Priority: d.Priority, From: d.ID}
+		}
+	}
+	return out
+}
+
+// raiseBlockers gives the open issues that it waits on its priority where
+// theirs is less urgent, for the raise policy.
+func raiseBlockers(ctx context.Context, store *sqlite.Store, it issue.Item) error {
+	g, err := loadDependencyGraph(ctx, store)
+	if err != nil {
+		return err
+	}
+	ctx = sqlite.WithActor(ctx, "automation:priority-inheritance")
+	for _, b := range g.openBlockers(it.ID) {
+		if issue.PriorityRank(b.Priority) <= issue.PriorityRank(it.Priority) {
+			continue
+		}
+		priority := it.Priority
+		if _, err := store.UpdateIssue(ctx, b.ID, sqlite.UpdateIssueInput{Priority: &priority}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type dependencyGraph struct {
+	items []issue.Item
+	byID  map[string]issue.Item
+	deps  map[string][]string
+}
+
+// loadDependencyGraph skips loading issues when there are no dependencies,
+// so lists without any stay cheap.
+func loadDependencyGraph(ctx context.Context, store *sqlite.Store) (dependencyGraph, error) {
+	deps, err := store.ListIssueDependencies(ctx)
+	if err != nil || len(deps) == 0 {
+		return dependencyGraph{deps: deps}, err
+	}
+	items, err := store.ListIssues(ctx, sqlite.ListFilter{Sort: "id"})
+	if err != nil {
+		return dependencyGraph{}, err
+	}
+	return indexDependencyGraph(items, deps), nil
+}
+
+// newDependencyGraph loads only the issues deps mentions.
+func newDependencyGraph(ctx context.Context, store *sqlite.Store, deps map[string][]string) (dependencyGraph, error) {
+	if len(deps) == 0 {
+		return dependencyGraph{deps: deps}, nil
+	}
+	ids := make([]string, 0, len(deps))
+	for id, blockers := range deps {
+		ids = append(ids, id)
+		ids = append(ids, blockers...)
+	}
+	items, err := store.ListIssues(ctx, sqlite.ListFilter{IDs: ids, Sort: "id"})
+	if err != nil {
+		return dependencyGraph{}, err
+	}
+	return indexDependencyGraph(items, deps), nil
+}
+
+func indexDependencyGraph(items []issue.Item, deps map[string][]string) dependencyGraph {
+	g := dependencyGraph{items: items, byID: map[string]issue.Item{}, deps: deps}
+	for _, it := range items {
+		g.byID[it.ID] = it
+	}
+	return g
+}
+
+// openBlockers returns the open issues id waits on, directly or through
+// other open issues. A done blocker no longer holds anything up, so the walk
+// stops there.
+func (g dependencyGraph) openBlockers(id string) []issue.Item {
+	var out []issue.Item
+	seen := map[string]bool{id: true}
+	queue := []string{id}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, dep := range g.deps[cur] {
+			b, ok := g.byID[dep]
+			if seen[dep] || !ok || !isOpen(b) {
+				continue
+			}
+			seen[dep] = true
+			out = append(out, b)
+			queue = append(queue, dep)
+		}
+	}
+	return out
+}
+
+func isOpen(it issue.Item) bool {
+	return it.Status != issue.StatusDone && it.Status != issue.StatusArchived
+}
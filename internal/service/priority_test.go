@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+// epic creates an epic and two tasks it waits on, one of which waits on a
+// third, and returns their IDs.
+func epic(t *testing.T, ctx context.Context, store *sqlite.Store) (epicID, taskID, subtaskID, doneID string) {
+	t.Helper()
+	create := func(title, priority, status string) string {
+		it, err := CreateIssue(ctx, store, issue.Item{Title: title, Status: status, Priority: priority})
+		if err != nil {
+			t.Fatalf("CreateIssue() error: %v", err)
+		}
+		return it.ID
+	}
+	epicID = create("epic", "p2", issue.StatusTodo)
+	taskID = create("task", "p3", issue.StatusTodo)
+	subtaskID = create("subtask", "none", issue.StatusReady)
+	doneID = create("done task", "p3", issue.StatusDone)
+	for _, d := range [][2]string{{epicID, taskID}, {taskID, subtaskID}, {epicID, doneID}} {
+		if err := store.AddIssueDependency(ctx, d[0], d[1]); err != nil {
+			t.Fatalf("AddIssueDependency() error: %v", err)
+		}
+	}
+	return epicID, taskID, subtaskID, doneID
+}
+
+func TestInheritedPrioritiesFollowOpenDependents(t *testing.T) {
+	store := openStore(t)
+	ctx := context.Background()
+	epicID, taskID, subtaskID, doneID := epic(t, ctx, store)
+
+	p0 := "p0"
+	if _, err := UpdateIssue(ctx, store, epicID, Update{UpdateIssueInput: sqlite.UpdateIssueInput{Priority: &p0}}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+	got, err := InheritedPriorities(ctx, store)
+	if err != nil {
+		t.Fatalf("InheritedPriorities() error: %v", err)
+	}
+	want := map[string]InheritedPriority{
+		taskID:    {Priority: "p0", From: epicID},
+		subtaskID: {Priority: "p0", From: epicID},
+	}
+	if len(got) != len(want) || got[taskID] != want[taskID] || got[subtaskID] != want[subtaskID] {
+		t.Fatalf("InheritedPriorities() = %+v, want %+v", got, want)
+	}
+	if _, ok := got[doneID]; ok {
+		t.Fatalf("done issues should not inherit: %+v", got)
+	}
+
+	// The show policy leaves the issues' own priorities alone.
+	task, err := store.GetIssue(ctx, taskID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Priority != "p3" {
+		t.Fatalf("task priority = %s, want p3", task.Priority)
+	}
+
+	cfg := appconfig.Default()
+	cfg.PriorityInheritance = InheritOff
+	if err := appconfig.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := InheritedPriorities(ctx, store); err != nil || len(got) != 0 {
+		t.Fatalf("InheritedPriorities() with off = %+v, %v", got, err)
+	}
+}
+
+func TestInheritedPriorityOfMatchesInheritedPriorities(t *testing.T) {
+	store := openStore(t)
+	ctx := context.Background()
+	epicID, taskID, subtaskID, doneID := epic(t, ctx, store)
+	ids := []string{epicID, taskID, subtaskID, doneID}
+	for _, p := range []string{"p1", "p0"} {
+		it, err := CreateIssue(ctx, store, issue.Item{Title: "also waits on subtask " + p, Status: issue.StatusTodo, Priority: p})
+		if err != nil {
+			t.Fatalf("CreateIssue() error: %v", err)
+		}
+		if err := store.AddIssueDependency(ctx, it.ID, subtaskID); err != nil {
+			t.Fatalf("AddIssueDependency() error: %v", err)
+		}
+		ids = append(ids, it.ID)
+	}
+
+	all, err := InheritedPriorities(ctx, store)
+	if err != nil {
+		t.Fatalf("InheritedPriorities() error: %v", err)
+	}
+	for _, id := range ids {
+		got, ok, err := InheritedPriorityOf(ctx, store, id)
+		if want, wantOK := all[id]; err != nil || ok != wantOK || got != want {
+			t.Fatalf("InheritedPriorityOf(%s) = %+v, %v, %v; want %+v, %v", id, got, ok, err, want, wantOK)
+		}
+	}
+	if got, ok, err := InheritedPriorityOf(ctx, store, subtaskID); err != nil || !ok || got.Priority != "p0" {
+		t.Fatalf("InheritedPriorityOf(subtask) = %+v, %v, %v; want p0", got, ok, err)
+	}
+}
+
+func TestRaisePolicyRaisesOpenBlockers(t *testing.T) {
+	store := openStore(t)
+	ctx := context.Background()
+	cfg := appconfig.Default()
+	cfg.PriorityInheritance = InheritRaise
+	if err := appconfig.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	epicID, taskID, subtaskID, doneID := epic(t, ctx, store)
+
+	p1 := "p1"
+	if _, err := UpdateIssue(ctx, store, epicID, Update{UpdateIssueInput: sqlite.UpdateIssueInput{Priority: &p1}}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+	want := map[string]string{taskID: "p1", subtaskID: "p1", doneID: "p3"}
+	for id, priority := range want {
+		it, err := store.GetIssue(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if it.Priority != priority {
+			t.Errorf("%s priority = %s, want %s", id, it.Priority, priority)
+		}
+	}
+
+	entries, err := store.ListActivity(ctx, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attributed := false
+	for _, a := range entries {
+		attributed = attributed || (a.IssueID == taskID && a.Actor == "automation:priority-inheritance")
+	}
+	if !attributed {
+		t.Fatalf("raise not attributed to automation:priority-inheritance: %+v", entries)
+	}
+
+	// Lowering the epic again leaves its blockers where they are.
+	p3 := "p3"
+	if _, err := UpdateIssue(ctx, store, epicID, Update{UpdateIssueInput: sqlite.UpdateIssueInput{Priority: &p3}}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+	task, err := store.GetIssue(ctx, taskID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Priority != "p1" {
+		t.Fatalf("task priority after lowering the epic = %s, want p1", task.Priority)
+	}
+}
@@ -0,0 +1,60 @@
+// Package share signs the expiring tokens in `track share` links. A token
+// names one issue and its expiry, signed with the database's share secret,
+// so the server can check it without storing anything per link.
+package share
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalid is returned for tokens that are malformed, tampered with, or
+// signed with another secret.
+var ErrInvalid = errors.New("invalid share token")
+
+// ErrExpired is returned for correctly signed tokens past their expiry.
+var ErrExpired = errors.New("share token expired")
+
+var encoding = base64.RawURLEncoding
+
+// Sign returns a token for issueID that is valid until expires.
+func Sign(secret []byte, issueID string, expires time.Time) string {
+	payload := encoding.EncodeToString([]byte(issueID + "|" + strconv.FormatInt(expires.Unix(), 10)))
+	return payload + "." + encoding.EncodeToString(mac(secret, payload))
+}
+
+// Verify checks token and returns the issue it was signed for.
+func Verify(secret []byte, token string, now time.Time) (string, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrInvalid
+	}
+	got, err := encoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, mac(secret, payload)) {
+		return "", ErrInvalid
+	}
+	raw, err := encoding.DecodeString(payload)
+	if err != nil {
+		return "", ErrInvalid
+	}
+	issueID, exp, ok := strings.Cut(string(raw), "|")
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if !ok || err != nil || issueID == "" {
+		return "", ErrInvalid
+	}
+	if !now.Before(time.Unix(unix, 0)) {
+		return "", ErrExpired
+	}
+	return issueID, nil
+}
+
+func mac(secret []byte, payload string) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(payload))
+	return h.Sum(nil)
+}
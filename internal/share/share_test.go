@@ -0,0 +1,28 @@
+package share
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	token := Sign(secret, "TRK-7", now.Add(time.Hour))
+
+	id, err := Verify(secret, token, now)
+	if err != nil || id != "TRK-7" {
+		t.Fatalf("Verify() = %q, %v; want TRK-7", id, err)
+	}
+	if _, err := Verify(secret, token, now.Add(2*time.Hour)); !errors.Is(err, ErrExpired) {
+		t.Fatalf("Verify(after expiry) error = %v, want ErrExpired", err)
+	}
+	if _, err := Verify([]byte("other"), token, now); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("Verify(other secret) error = %v, want ErrInvalid", err)
+	}
+	forged := Sign([]byte("other"), "TRK-8", now.Add(time.Hour))
+	if _, err := Verify(secret, token[:len(token)-2]+forged[len(forged)-2:], now); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("Verify(tampered) error = %v, want ErrInvalid", err)
+	}
+}
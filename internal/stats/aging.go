@@ -0,0 +1,207 @@
+// Package stats builds the reports shown by `track stats`.
+package stats
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+// AgeBuckets are the columns of the aging heatmap. Each holds the stints up
+// to its Max; the last one, with no Max, holds the rest.
+var AgeBuckets = []struct {
+	Name string
+	Max  time.Duration
+}{
+	{"<1d", 24 * time.Hour},
+	{"1-3d", 3 * 24 * time.Hour},
+	{"3-7d", 7 * 24 * time.Hour},
+	{"1-4w", 28 * 24 * time.Hour},
+	{">4w", 0},
+}
+
+// DefaultStall is the median stint from which a status counts as stalling.
+const DefaultStall = 7 * 24 * time.Hour
+
+// Stint is one uninterrupted stay of an issue in a status. Open stints are
+// still going on and are measured up to now.
+type Stint struct {
+	IssueID  string
+	Status   string
+	Labels   []string
+	Duration time.Duration
+	Open     bool
+}
+
+// Aging is how long issues sit in each status that is not done or archived.
+type Aging struct {
+	Statuses []StatusAge
+	Labels   []LabelAge
+}
+
+// StatusAge is one heatmap row. Buckets counts stints per AgeBuckets entry.
+type StatusAge struct {
+	Status  string
+	Buckets []int
+	Count   int
+	Open    int
+	Median  time.Duration
+	Stalled bool
+}
+
+// LabelAge breaks the rows down for issues carrying Label. Cells line up
+// with Aging.Statuses.
+type LabelAge struct {
+	Label string
+	Cells []AgeCell
+}
+
+type AgeCell struct {
+	Count  int
+	Median time.Duration
+}
+
+// Stints replays the activity log into every issue's stints.
+func Stints(ctx context.Context, store *sqlite.Store, now time.Time) ([]Stint, error) {
+	items, err := store.ListIssues(ctx, sqlite.ListFilter{Sort: "id"})
+	if err != nil {
+		return nil, err
+	}
+	entries, err := store.ListActivity(ctx, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	changes := map[string][]sqlite.Activity{}
+	for _, a := range entries {
+		if a.Kind == sqlite.ActivityStatus {
+			changes[a.IssueID] = append(changes[a.IssueID], a)
+		}
+	}
+
+	var out []Stint
+	for _, it := range items {
+		since, err := time.Parse(time.RFC3339, it.CreatedAt)
+		if err != nil {
+			continue
+		}
+		status := it.Status
+		if cs := changes[it.ID]; len(cs) > 0 {
+			if from, _, ok := strings.Cut(cs[0].Detail, " -> "); ok {
+				status = from
+			}
+		}
+		for _, a := range changes[it.ID] {
+			at, err := time.Parse(time.RFC3339, a.CreatedAt)
+			if err != nil {
+				continue
+			}
+			out = append(out, Stint{IssueID: it.ID, Status: status, Labels: it.Labels, Duration: at.Sub(since)})
+			if _, to, ok := strings.Cut(a.Detail, " -> "); ok {
+				status = to
+			}
+			since = at
+		}
+		out = append(out, Stint{IssueID: it.ID, Status: status, Labels: it.Labels, Duration: now.Sub(since), Open: true})
+	}
+	return out, nil
+}
+
+// BuildAging summarizes the stints in every status but done and archived,
+// in the order of statuses. A status stalls when its median stint is at
+// least stall.
+func BuildAging(stints []Stint, statuses []string, stall time.Duration) Aging {
+	order := slices.DeleteFunc(slices.Clone(statuses), func(st string) bool {
+		return st == issue.StatusDone || st == issue.StatusArchived
+	})
+	byStatus := map[string][]Stint{}
+	for _, s := range stints {
+		if s.Status == issue.StatusDone || s.Status == issue.StatusArchived {
+			continue
+		}
+		if !slices.Contains(order, s.Status) {
+			order = append(order, s.Status)
+		}
+		byStatus[s.Status] = append(byStatus[s.Status], s)
+	}
+
+	var ag Aging
+	for _, st := range order {
+		row := StatusAge{Status: st, Buckets: make([]int, len(AgeBuckets))}
+		durations := make([]time.Duration, 0, len(byStatus[st]))
+		for _, s := range byStatus[st] {
+			row.Buckets[bucket(s.Duration)]++
+			if s.Open {
+				row.Open++
+			}
+			durations = append(durations, s.Duration)
+		}
+		row.Count = len(durations)
+		row.Median = median(durations)
+		row.Stalled = row.Count > 0 && row.Median >= stall
+		ag.Statuses = append(ag.Statuses, row)
+	}
+
+	var labels []string
+	for _, s := range stints {
+		for _, l := range s.Labels {
+			if !slices.Contains(labels, l) {
+				labels = append(labels, l)
+			}
+		}
+	}
+	sort.Strings(labels)
+	for _, l := range labels {
+		la := LabelAge{Label: l}
+		for _, st := range order {
+			var durations []time.Duration
+			for _, s := range byStatus[st] {
+				if slices.Contains(s.Labels, l) {
+					durations = append(durations, s.Duration)
+				}
+			}
+			la.Cells = append(la.Cells, AgeCell{Count: len(durations), Median: median(durations)})
+		}
+		ag.Labels = append(ag.Labels, la)
+	}
+	return ag
+}
+
+func bucket(d time.Duration) int {
+	for i, b := range AgeBuckets {
+		if b.Max > 0 && d < b.Max {
+			return i
+		}
+	}
+	return len(AgeBuckets) - 1
+}
+
+func median(ds []time.Duration) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(ds)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
+
+// FormatAge prints d in days and hours, or hours and minutes under a day.
+func FormatAge(d time.Duration) string {
+	if d < 24*time.Hour {
+		return issue.FormatEstimate(d.Round(time.Minute))
+	}
+	days, hours := int(d/(24*time.Hour)), int(d%(24*time.Hour)/time.Hour)
+	if hours == 0 {
+		return fmt.Sprintf("%dd", days)
+	}
+	return fmt.Sprintf("%dd%dh", days, hours)
+}
@@ -0,0 +1,62 @@
+package stats
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBuildAging(t *testing.T) {
+	day := 24 * time.Hour
+	stints := []Stint{
+		{IssueID: "TRK-1", Status: "todo", Labels: []string{"bug"}, Duration: 2 * time.Hour},
+		{IssueID: "TRK-1", Status: "in_progress", Labels: []string{"bug"}, Duration: 10 * day, Open: true},
+		{IssueID: "TRK-2", Status: "todo", Duration: 2 * day},
+		{IssueID: "TRK-2", Status: "in_progress", Duration: 8 * day},
+		{IssueID: "TRK-2", Status: "done", Duration: 30 * day, Open: true},
+		{IssueID: "TRK-3", Status: "on-hold", Labels: []string{"bug"}, Duration: 40 * day, Open: true},
+	}
+	ag := BuildAging(stints, []string{"todo", "ready", "in_progress", "done", "archived"}, DefaultStall)
+
+	var order []string
+	for _, row := range ag.Statuses {
+		order = append(order, row.Status)
+	}
+	if !slices.Equal(order, []string{"todo", "ready", "in_progress", "on-hold"}) {
+		t.Fatalf("statuses = %v", order)
+	}
+	todo, ready, inProgress, onHold := ag.Statuses[0], ag.Statuses[1], ag.Statuses[2], ag.Statuses[3]
+	if !slices.Equal(todo.Buckets, []int{1, 1, 0, 0, 0}) || todo.Median != 25*time.Hour || todo.Stalled {
+		t.Fatalf("todo = %+v", todo)
+	}
+	if ready.Count != 0 || ready.Stalled {
+		t.Fatalf("ready = %+v", ready)
+	}
+	if inProgress.Median != 9*day || inProgress.Open != 1 || !inProgress.Stalled {
+		t.Fatalf("in_progress = %+v", inProgress)
+	}
+	if !slices.Equal(onHold.Buckets, []int{0, 0, 0, 0, 1}) {
+		t.Fatalf("on-hold = %+v", onHold)
+	}
+
+	if len(ag.Labels) != 1 || ag.Labels[0].Label != "bug" {
+		t.Fatalf("labels = %+v", ag.Labels)
+	}
+	cells := ag.Labels[0].Cells
+	if cells[0] != (AgeCell{Count: 1, Median: 2 * time.Hour}) || cells[1].Count != 0 || cells[3] != (AgeCell{Count: 1, Median: 40 * day}) {
+		t.Fatalf("bug cells = %+v", cells)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		45 * time.Minute: "45m",
+		5 * time.Hour:    "5h",
+		48 * time.Hour:   "2d",
+		53 * time.Hour:   "2d5h",
+	} {
+		if got := FormatAge(d); got != want {
+			t.Fatalf("FormatAge(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
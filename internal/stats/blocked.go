@@ -0,0 +1,51 @@
+package stats
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+// BlockedTime is how long an issue has spent in issue.StatusBlocked over all
+// its blocks, counting an open block up to now.
+type BlockedTime struct {
+	IssueID string
+	Total   time.Duration
+	Blocks  int
+	// Open is set while the issue is still blocked.
+	Open bool
+	// Reason is the reason of the latest block.
+	Reason string
+}
+
+// BlockedTimes sums up the blocks of every issue that has been blocked, most
+// blocked first.
+func BlockedTimes(ctx context.Context, store *sqlite.Store, now time.Time) ([]BlockedTime, error) {
+	blocks, err := store.ListAllBlocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byIssue := map[string]*BlockedTime{}
+	var order []string
+	for _, b := range blocks {
+		bt, ok := byIssue[b.IssueID]
+		if !ok {
+			bt = &BlockedTime{IssueID: b.IssueID}
+			byIssue[b.IssueID] = bt
+			order = append(order, b.IssueID)
+		}
+		bt.Total += b.Duration(now)
+		bt.Blocks++
+		bt.Open = b.Until == ""
+		bt.Reason = b.Reason
+	}
+
+	out := make([]BlockedTime, 0, len(order))
+	for _, id := range order {
+		out = append(out, *byIssue[id])
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Total > out[j].Total })
+	return out, nil
+}
@@ -0,0 +1,160 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+// MinSamples is how many closed issues a label or assignee needs before its
+// bias is trusted for suggestions.
+const MinSamples = 3
+
+// Bias compares logged time with estimates over a set of closed issues.
+// Factor is logged over estimated time: above 1 means estimates run short.
+type Bias struct {
+	Key       string
+	Issues    int
+	Estimated time.Duration
+	Logged    time.Duration
+}
+
+func (b Bias) Factor() float64 {
+	if b.Estimated <= 0 {
+		return 1
+	}
+	return float64(b.Logged) / float64(b.Estimated)
+}
+
+func (b *Bias) add(estimated, logged time.Duration) {
+	b.Issues++
+	b.Estimated += estimated
+	b.Logged += logged
+}
+
+// Calibration is the bias of all closed issues that have both an estimate
+// and logged time, and of each label and assignee among them.
+type Calibration struct {
+	Overall   Bias
+	Labels    []Bias
+	Assignees []Bias
+}
+
+// Calibrate compares estimates with logged time over done and archived
+// issues.
+func Calibrate(ctx context.Context, store *sqlite.Store) (Calibration, error) {
+	closed, err := store.ListIssues(ctx, sqlite.ListFilter{Statuses: []string{issue.StatusDone, issue.StatusArchived}, Sort: "id"})
+	if err != nil {
+		return Calibration{}, err
+	}
+	c := Calibration{Overall: Bias{Key: "all"}}
+	labels, assignees := map[string]*Bias{}, map[string]*Bias{}
+	for _, it := range closed {
+		estimated := issue.EstimateDuration(it.Estimate)
+		if estimated <= 0 {
+			continue
+		}
+		entries, err := store.ListTimeEntries(ctx, it.ID)
+		if err != nil {
+			return Calibration{}, err
+		}
+		var logged time.Duration
+		for _, e := range entries {
+			logged += e.Duration()
+		}
+		if logged <= 0 {
+			continue
+		}
+		c.Overall.add(estimated, logged)
+		for _, l := range it.Labels {
+			biasFor(labels, l).add(estimated, logged)
+		}
+		if it.Assignee != "" {
+			biasFor(assignees, it.Assignee).add(estimated, logged)
+		}
+	}
+	c.Labels, c.Assignees = sortedBiases(labels), sortedBiases(assignees)
+	return c, nil
+}
+
+func biasFor(m map[string]*Bias, key string) *Bias {
+	b, ok := m[key]
+	if !ok {
+		b = &Bias{Key: key}
+		m[key] = b
+	}
+	return b
+}
+
+func sortedBiases(m map[string]*Bias) []Bias {
+	out := make([]Bias, 0, len(m))
+	for _, b := range m {
+		out = append(out, *b)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// Suggestion is an estimate scaled by the biases that apply to an issue.
+type Suggestion struct {
+	Estimate  time.Duration
+	Suggested time.Duration
+	Factor    float64
+	// Basis names the biases the factor averages, such as "label bug (4
+	// issues)"; empty when none has MinSamples and Suggested is unchanged.
+	Basis []string
+}
+
+// Suggest scales the estimate of it by the average factor of the biases of
+// its assignee and labels that have at least MinSamples issues, falling back
+// to the overall bias. The result is rounded to 5 minutes.
+func (c Calibration) Suggest(it issue.Item) (Suggestion, error) {
+	estimate := issue.EstimateDuration(it.Estimate)
+	if estimate <= 0 {
+		return Suggestion{}, fmt.Errorf("%s has no estimate to calibrate (see track set --estimate)", it.ID)
+	}
+	var (
+		factors []float64
+		basis   []string
+	)
+	use := func(name string, b Bias) {
+		if b.Issues >= MinSamples {
+			factors = append(factors, b.Factor())
+			basis = append(basis, fmt.Sprintf("%s (%d issues)", name, b.Issues))
+		}
+	}
+	if i := slices.IndexFunc(c.Assignees, func(b Bias) bool { return b.Key == it.Assignee }); i >= 0 {
+		use("assignee "+it.Assignee, c.Assignees[i])
+	}
+	for _, l := range it.Labels {
+		if i := slices.IndexFunc(c.Labels, func(b Bias) bool { return b.Key == l }); i >= 0 {
+			use("label "+l, c.Labels[i])
+		}
+	}
+	if len(factors) == 0 {
+		use("all closed issues", c.Overall)
+	}
+
+	s := Suggestion{Estimate: estimate, Suggested: estimate, Factor: 1, Basis: basis}
+	if len(factors) == 0 {
+		return s, nil
+	}
+	var sum float64
+	for _, f := range factors {
+		sum += f
+	}
+	s.Factor = sum / float64(len(factors))
+	s.Suggested = max(time.Duration(float64(estimate)*s.Factor).Round(5*time.Minute), 5*time.Minute)
+	return s, nil
+}
+
+// FormatFactor prints a bias factor such as x1.25.
+func FormatFactor(f float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("x%.2f", f), "0"), ".")
+}
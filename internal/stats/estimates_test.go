@@ -0,0 +1,75 @@
+package stats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestCalibrateAndSuggest(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	start := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	closed := func(labels []string, assignee, estimate string, logged time.Duration) {
+		t.Helper()
+		it, err := store.CreateIssue(ctx, issue.Item{Title: "Work", Status: issue.StatusTodo, Priority: "none", Labels: labels, Assignee: assignee})
+		if err != nil {
+			t.Fatal(err)
+		}
+		done := issue.StatusDone
+		if _, err := store.UpdateIssue(ctx, it.ID, sqlite.UpdateIssueInput{Status: &done, Estimate: &estimate}); err != nil {
+			t.Fatal(err)
+		}
+		if logged > 0 {
+			if _, err := store.LogTime(ctx, it.ID, start, start.Add(logged)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for range 3 {
+		closed([]string{"bug"}, "alice", "1h", 2*time.Hour)
+	}
+	closed([]string{"docs"}, "bob", "2h", time.Hour)
+	closed([]string{"docs"}, "bob", "1h", 0)
+
+	c, err := Calibrate(ctx, store)
+	if err != nil {
+		t.Fatalf("Calibrate() error: %v", err)
+	}
+	if c.Overall.Issues != 4 || c.Overall.Estimated != 5*time.Hour || c.Overall.Logged != 7*time.Hour {
+		t.Fatalf("overall = %+v", c.Overall)
+	}
+	if len(c.Labels) != 2 || c.Labels[0].Key != "bug" || c.Labels[0].Factor() != 2 || c.Labels[1].Factor() != 0.5 {
+		t.Fatalf("labels = %+v", c.Labels)
+	}
+
+	s, err := c.Suggest(issue.Item{ID: "TRK-9", Labels: []string{"bug"}, Assignee: "alice", Estimate: "1h30m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Suggested != 3*time.Hour || s.Factor != 2 || len(s.Basis) != 2 {
+		t.Fatalf("bug suggestion = %+v", s)
+	}
+	s, err = c.Suggest(issue.Item{ID: "TRK-9", Labels: []string{"docs"}, Estimate: "1h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Suggested != 85*time.Minute || len(s.Basis) != 1 || s.Basis[0] != "all closed issues (4 issues)" {
+		t.Fatalf("fallback suggestion = %+v", s)
+	}
+	if _, err := c.Suggest(issue.Item{ID: "TRK-9"}); err == nil {
+		t.Fatalf("issue without estimate should fail")
+	}
+	if got := FormatFactor(1.5); got != "x1.5" {
+		t.Fatalf("FormatFactor(1.5) = %q", got)
+	}
+}
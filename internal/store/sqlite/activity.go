@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+// Activity kinds recorded in the global feed.
+const (
+	ActivityCreated  = "created"
+	ActivityUpdated  = "updated"
+	ActivityStatus   = "status"
+	ActivityComment  = "comment"
+	ActivityPRMerged = "pr_merged"
+	// ActivitySection is recorded once per body section an edit changed,
+	// with the section title as detail; `track blame` reads it.
+	ActivitySection = "section"
+	// ActivityTime is recorded for logged work, with its duration as detail.
+	ActivityTime = "time"
+	// ActivityCheckFailed is recorded when a linked PR's check run fails,
+	// with the check name, conclusion, and link as detail.
+	ActivityCheckFailed = "check_failed"
+)
+
+// Activity is one entry of the feed shown by `track log`, which doubles as
+// the audit log. Detail is a short human-readable summary such as
+// "todo -> ready"; Actor is who made the change (see Actor).
+type Activity struct {
+	ID        int
+	Kind      string
+	IssueID   string
+	Detail    string
+	Actor     string
+	CreatedAt string
+}
+
+// RecordActivity appends an entry to the feed, attributed to Actor(ctx).
+// Entries are recorded even when ctx suppresses events, so automated changes
+// still show up.
+func (s *Store) RecordActivity(ctx context.Context, kind, issueID, detail string) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	actor := Actor(ctx)
+	if err := withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `INSERT INTO activity(kind, issue_id, detail, actor, created_at) VALUES(?, ?, ?, ?, ?)`, kind, issueID, detail, actor, now)
+		return err
+	}); err != nil {
+		return fmt.Errorf("record activity: %w", err)
+	}
+	if kind == ActivityComment {
+		return s.addCommentMentions(ctx, issueID, detail)
+	}
+	return nil
+}
+
+// recordSectionChanges records an ActivitySection entry for each section
+// that differs between two versions of a body.
+func (s *Store) recordSectionChanges(ctx context.Context, issueID, before, after string) error {
+	for _, title := range issue.ChangedSections(before, after) {
+		if err := s.RecordActivity(ctx, ActivitySection, issueID, title); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListIssueActivity returns one issue's entries, oldest first.
+func (s *Store) ListIssueActivity(ctx context.Context, issueID string) ([]Activity, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, kind, issue_id, detail, actor, created_at FROM activity
+		WHERE issue_id = ?
+		ORDER BY id
+	`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("list issue activity: %w", err)
+	}
+	defer rows.Close()
+	return scanActivity(rows)
+}
+
+// ListActivity returns entries created at or after since (RFC3339, empty for
+// all) with an ID above afterID, oldest first.
+func (s *Store) ListActivity(ctx context.Context, since string, afterID int) ([]Activity, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, kind, issue_id, detail, actor, created_at FROM activity
+		WHERE created_at >= ? AND id > ?
+		ORDER BY id
+	`, since, afterID)
+	if err != nil {
+		return nil, fmt.Errorf("list activity: %w", err)
+	}
+	defer rows.Close()
+	return scanActivity(rows)
+}
+
+func scanActivity(rows *sql.Rows) ([]Activity, error) {
+	out := make([]Activity, 0)
+	for rows.Next() {
+		var a Activity
+		if err := rows.Scan(&a.ID, &a.Kind, &a.IssueID, &a.Detail, &a.Actor, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan activity: %w", err)
+		}
+		out = append(out, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate activity: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+func TestMutationsRecordActivity(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	t.Setenv(ActorEnv, "hook:7")
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	created, err := store.CreateIssue(WithoutEvents(ctx), issue.Item{Title: "A", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	done := issue.StatusDone
+	for i := 0; i < 2; i++ {
+		if _, err := store.UpdateIssue(WithActor(ctx, "api"), created.ID, UpdateIssueInput{Status: &done}); err != nil {
+			t.Fatalf("UpdateIssue() error: %v", err)
+		}
+	}
+
+	entries, err := store.ListActivity(ctx, "", 0)
+	if err != nil {
+		t.Fatalf("ListActivity() error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %+v, want created and one status change", entries)
+	}
+	if entries[0].Kind != ActivityCreated || entries[0].Detail != "A" || entries[0].Actor != "hook:7" {
+		t.Fatalf("first entry = %+v", entries[0])
+	}
+	if entries[1].Kind != ActivityStatus || entries[1].Detail != "todo -> done" || entries[1].Actor != "api" {
+		t.Fatalf("second entry = %+v", entries[1])
+	}
+
+	after, err := store.ListActivity(ctx, "", entries[0].ID)
+	if err != nil || len(after) != 1 {
+		t.Fatalf("ListActivity(after first) = %+v, %v", after, err)
+	}
+	labeled, err := store.AddLabel(ctx, created.ID, "bug")
+	if err != nil {
+		t.Fatalf("AddLabel() error: %v", err)
+	}
+	if got, err := store.ListActivity(ctx, "", entries[1].ID); err != nil || len(got) != 1 || got[0].Kind != ActivityUpdated || got[0].Detail != "labels" {
+		t.Fatalf("AddLabel(%s) activity = %+v, %v", labeled.ID, got, err)
+	}
+
+	future, err := store.ListActivity(ctx, "2999-01-01T00:00:00Z", 0)
+	if err != nil || len(future) != 0 {
+		t.Fatalf("ListActivity(future) = %+v, %v", future, err)
+	}
+}
@@ -0,0 +1,41 @@
+package sqlite
+
+import (
+	"context"
+	"os"
+
+	appconfig "github.com/myuon/track/internal/config"
+)
+
+// ActorEnv is set on processes track starts, such as hooks and agent
+// runners, so their own track commands are attributed to them.
+const ActorEnv = "TRACK_ACTOR"
+
+type actorCtxKey struct{}
+
+// WithActor returns a context whose mutations are attributed to actor, for
+// example "api" or "automation:auto-organize".
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorCtxKey{}, actor)
+}
+
+// Actor reports who is making changes through ctx: the actor set with
+// WithActor, then $TRACK_ACTOR, then the configured user_name/user_email,
+// then the OS user.
+func Actor(ctx context.Context) string {
+	if v, ok := ctx.Value(actorCtxKey{}).(string); ok && v != "" {
+		return v
+	}
+	if v := os.Getenv(ActorEnv); v != "" {
+		return v
+	}
+	if cfg, err := appconfig.Load(); err == nil {
+		if id := cfg.UserIdentity(); id != "" {
+			return id
+		}
+	}
+	if v := os.Getenv("USER"); v != "" {
+		return v
+	}
+	return "cli"
+}
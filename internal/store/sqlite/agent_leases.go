@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+// AcquireAgentLease claims one of an agent's max concurrent sessions for
+// the calling process. Leases left by processes that are no longer running
+// are dropped first. Call release when the session ends.
+func (s *Store) AcquireAgentLease(ctx context.Context, agent, issueID string, max int) (release func() error, err error) {
+	if err := s.dropStaleAgentLeases(ctx, agent); err != nil {
+		return nil, err
+	}
+	var id int64
+	err = withSQLiteRetry(ctx, func() error {
+		res, err := s.db.ExecContext(ctx, `
+			INSERT INTO agent_leases(agent, issue_id, pid, started_at)
+			SELECT ?, ?, ?, ?
+			WHERE ? <= 0 OR (SELECT COUNT(1) FROM agent_leases WHERE agent = ?) < ?
+		`, agent, issueID, os.Getpid(), time.Now().UTC().Format(time.RFC3339), max, agent, max)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return nil
+		}
+		id, err = res.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("acquire agent lease: %w", err)
+	}
+	if id == 0 {
+		return nil, fmt.Errorf("agent %s is already running %d sessions (its max concurrency)", agent, max)
+	}
+	return func() error {
+		if _, err := s.db.ExecContext(context.WithoutCancel(ctx), `DELETE FROM agent_leases WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("release agent lease: %w", err)
+		}
+		return nil
+	}, nil
+}
+
+func (s *Store) dropStaleAgentLeases(ctx context.Context, agent string) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, pid FROM agent_leases WHERE agent = ?`, agent)
+	if err != nil {
+		return fmt.Errorf("list agent leases: %w", err)
+	}
+	var stale []int64
+	for rows.Next() {
+		var id int64
+		var pid int
+		if err := rows.Scan(&id, &pid); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scan agent lease: %w", err)
+		}
+		if !processRunning(pid) {
+			stale = append(stale, id)
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("list agent leases: %w", err)
+	}
+	for _, id := range stale {
+		if _, err := s.db.ExecContext(ctx, `DELETE FROM agent_leases WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("drop stale agent lease: %w", err)
+		}
+	}
+	return nil
+}
+
+func processRunning(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return p.Signal(syscall.Signal(0)) == nil
+}
@@ -0,0 +1,228 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+// API token roles, from least to most privileged. Read tokens may only make
+// GET requests; contributors may also change issues but not archive them or
+// reopen done/archived ones; admins may do anything.
+const (
+	RoleRead        = "read"
+	RoleContributor = "contributor"
+	RoleAdmin       = "admin"
+)
+
+var Roles = []string{RoleRead, RoleContributor, RoleAdmin}
+
+// AuthorizeAPIToken fails with these when a request is not authenticated.
+var (
+	ErrTokenRequired = errors.New("missing bearer token")
+	ErrTokenInvalid  = errors.New("invalid token")
+)
+
+// ForbiddenError is AuthorizeAPIToken refusing a token whose role does not
+// grant the request.
+type ForbiddenError struct {
+	Token    APIToken
+	Required string
+}
+
+func (e *ForbiddenError) Error() string {
+	if e.Token.Role == RoleRead {
+		return "token " + e.Token.Name + " is read-only"
+	}
+	return "token " + e.Token.Name + " needs the " + e.Required + " role"
+}
+
+// APIToken is a named credential for `track serve`. Only a hash of the
+// secret is stored.
+type APIToken struct {
+	Name      string
+	Role      string
+	CreatedAt string
+}
+
+// HasRole reports whether the token's role grants everything required does.
+func (t APIToken) HasRole(required string) bool {
+	return slices.Index(Roles, t.Role) >= slices.Index(Roles, required)
+}
+
+// MayChangeStatus reports whether the token may move an issue from one
+// status to another. Contributors may not archive issues or reopen done and
+// archived ones.
+func (t APIToken) MayChangeStatus(from, to string) bool {
+	if t.Role != RoleContributor || from == to {
+		return true
+	}
+	if to == issue.StatusArchived {
+		return false
+	}
+	return from != issue.StatusDone && from != issue.StatusArchived
+}
+
+func ValidateRole(role string) error {
+	if !slices.Contains(Roles, role) {
+		return fmt.Errorf("invalid role: %s (want %s)", role, strings.Join(Roles, "|"))
+	}
+	return nil
+}
+
+// CreateAPIToken stores a new token and returns its secret, which cannot be
+// read back later.
+func (s *Store) CreateAPIToken(ctx context.Context, name, role string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("token name must not be empty")
+	}
+	if err := ValidateRole(role); err != nil {
+		return "", err
+	}
+	var b [24]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	secret := "trk_" + hex.EncodeToString(b[:])
+	now := time.Now().UTC().Format(time.RFC3339)
+	err := withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `INSERT INTO api_tokens(name, token_hash, role, created_at) VALUES(?, ?, ?, ?)`, name, hashToken(secret), role, now)
+		return err
+	})
+	if err != nil {
+		msg := strings.ToLower(err.Error())
+		if strings.Contains(msg, "unique") || strings.Contains(msg, "constraint failed") {
+			return "", fmt.Errorf("token already exists: %s", name)
+		}
+		return "", fmt.Errorf("insert api token: %w", err)
+	}
+	return secret, nil
+}
+
+// PutAPIToken stores a token whose secret the caller chose, such as one
+// handed to a container in its environment, replacing any token of the
+// same name.
+func (s *Store) PutAPIToken(ctx context.Context, name, role, secret string) error {
+	name, secret = strings.TrimSpace(name), strings.TrimSpace(secret)
+	if name == "" {
+		return fmt.Errorf("token name must not be empty")
+	}
+	if err := ValidateRole(role); err != nil {
+		return err
+	}
+	if len(secret) < 16 {
+		return fmt.Errorf("token secret must be at least 16 characters")
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	err := withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `INSERT INTO api_tokens(name, token_hash, role, created_at) VALUES(?, ?, ?, ?)
+			ON CONFLICT(name) DO UPDATE SET token_hash = excluded.token_hash, role = excluded.role`, name, hashToken(secret), role, now)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("put api token: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) ListAPITokens(ctx context.Context) ([]APIToken, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT name, role, created_at FROM api_tokens ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("list api tokens: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]APIToken, 0)
+	for rows.Next() {
+		var t APIToken
+		if err := rows.Scan(&t.Name, &t.Role, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan api token: %w", err)
+		}
+		out = append(out, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate api tokens: %w", err)
+	}
+	return out, nil
+}
+
+func (s *Store) RemoveAPIToken(ctx context.Context, name string) error {
+	var res sql.Result
+	err := withSQLiteRetry(ctx, func() error {
+		var err error
+		res, err = s.db.ExecContext(ctx, `DELETE FROM api_tokens WHERE name = ?`, name)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("delete api token: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("token not found: %s", name)
+	}
+	return nil
+}
+
+// HasAPITokens reports whether any token exists. Without tokens the API
+// stays open, as it only listens on localhost.
+func (s *Store) HasAPITokens(ctx context.Context) (bool, error) {
+	var n int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM api_tokens`).Scan(&n); err != nil {
+		return false, fmt.Errorf("count api tokens: %w", err)
+	}
+	return n > 0, nil
+}
+
+// AuthenticateAPIToken returns the token whose secret this is. The second
+// return value is false for unknown secrets.
+func (s *Store) AuthenticateAPIToken(ctx context.Context, secret string) (APIToken, bool, error) {
+	var t APIToken
+	err := s.db.QueryRowContext(ctx, `SELECT name, role, created_at FROM api_tokens WHERE token_hash = ?`, hashToken(secret)).
+		Scan(&t.Name, &t.Role, &t.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return APIToken{}, false, nil
+	}
+	if err != nil {
+		return APIToken{}, false, fmt.Errorf("read api token: %w", err)
+	}
+	return t, true, nil
+}
+
+// AuthorizeAPIToken checks the bearer secret of a request that needs the
+// required role. Until any token exists every request is allowed, and the
+// second return value is false; otherwise it is true with the caller's
+// token, or an ErrToken* or *ForbiddenError says why the request is refused.
+func (s *Store) AuthorizeAPIToken(ctx context.Context, secret, required string) (APIToken, bool, error) {
+	enabled, err := s.HasAPITokens(ctx)
+	if err != nil || !enabled {
+		return APIToken{}, false, err
+	}
+	if secret = strings.TrimSpace(secret); secret == "" {
+		return APIToken{}, false, ErrTokenRequired
+	}
+	tok, ok, err := s.AuthenticateAPIToken(ctx, secret)
+	if err != nil {
+		return APIToken{}, false, err
+	}
+	if !ok {
+		return APIToken{}, false, ErrTokenInvalid
+	}
+	if !tok.HasRole(required) {
+		return APIToken{}, false, &ForbiddenError{Token: tok, Required: required}
+	}
+	return tok, true, nil
+}
+
+func hashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestAPITokenLifecycle(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	if has, err := store.HasAPITokens(ctx); err != nil || has {
+		t.Fatalf("HasAPITokens() = %v, %v; want false", has, err)
+	}
+	secret, err := store.CreateAPIToken(ctx, "ci", RoleRead)
+	if err != nil {
+		t.Fatalf("CreateAPIToken() error: %v", err)
+	}
+	if !strings.HasPrefix(secret, "trk_") {
+		t.Fatalf("secret = %q", secret)
+	}
+	if _, err := store.CreateAPIToken(ctx, "ci", RoleAdmin); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("duplicate token error = %v", err)
+	}
+	if _, err := store.CreateAPIToken(ctx, "x", "owner"); err == nil {
+		t.Fatalf("expected invalid role error")
+	}
+
+	tok, ok, err := store.AuthenticateAPIToken(ctx, secret)
+	if err != nil || !ok || tok.Name != "ci" || tok.Role != RoleRead {
+		t.Fatalf("AuthenticateAPIToken() = %+v, %v, %v", tok, ok, err)
+	}
+	if _, ok, err := store.AuthenticateAPIToken(ctx, "trk_wrong"); err != nil || ok {
+		t.Fatalf("AuthenticateAPIToken(wrong) = %v, %v", ok, err)
+	}
+
+	if err := store.RemoveAPIToken(ctx, "ci"); err != nil {
+		t.Fatalf("RemoveAPIToken() error: %v", err)
+	}
+	if err := store.RemoveAPIToken(ctx, "ci"); err == nil {
+		t.Fatalf("expected not found error")
+	}
+	if tokens, err := store.ListAPITokens(ctx); err != nil || len(tokens) != 0 {
+		t.Fatalf("ListAPITokens() = %+v, %v", tokens, err)
+	}
+}
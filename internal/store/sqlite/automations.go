@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Automation struct {
+	Name      string
+	Enabled   bool
+	Settings  string
+	UpdatedAt string
+}
+
+// SaveAutomation upserts the enabled flag and JSON settings of a built-in
+// automation.
+func (s *Store) SaveAutomation(ctx context.Context, a Automation) (Automation, error) {
+	if a.Settings == "" {
+		a.Settings = "{}"
+	}
+	a.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	enabled := 0
+	if a.Enabled {
+		enabled = 1
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO automations(name, enabled, settings_json, updated_at)
+		VALUES(?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET enabled=excluded.enabled, settings_json=excluded.settings_json, updated_at=excluded.updated_at
+	`, a.Name, enabled, a.Settings, a.UpdatedAt)
+	if err != nil {
+		return Automation{}, fmt.Errorf("save automation: %w", err)
+	}
+	return a, nil
+}
+
+// GetAutomation returns the stored automation. The second return value is
+// false when it has never been configured.
+func (s *Store) GetAutomation(ctx context.Context, name string) (Automation, bool, error) {
+	var (
+		a       Automation
+		enabled int
+	)
+	err := s.db.QueryRowContext(ctx, `SELECT name, enabled, settings_json, updated_at FROM automations WHERE name = ?`, name).
+		Scan(&a.Name, &enabled, &a.Settings, &a.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Automation{}, false, nil
+	}
+	if err != nil {
+		return Automation{}, false, fmt.Errorf("get automation: %w", err)
+	}
+	a.Enabled = enabled != 0
+	return a, true, nil
+}
@@ -0,0 +1,141 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+// benchIssues is the size of the fixture the store benchmarks run against.
+const benchIssues = 100_000
+
+// openBenchStore opens a store in a fresh TRACK_HOME seeded with n issues.
+// The rows are inserted directly in one transaction, so seeding stays fast
+// and is not part of the measurement.
+func openBenchStore(b *testing.B, n int) *Store {
+	b.Helper()
+	b.Setenv("TRACK_HOME", b.TempDir())
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		b.Fatalf("Open() error: %v", err)
+	}
+	b.Cleanup(func() { _ = store.Close() })
+
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		b.Fatalf("begin tx: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO issues(id, title, status, priority, labels_json, order_index, created_at, updated_at) VALUES(?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		b.Fatalf("prepare seed: %v", err)
+	}
+	statuses := []string{issue.StatusTodo, issue.StatusReady, issue.StatusInProgress, issue.StatusDone}
+	priorities := []string{"p0", "p1", "p2", "p3", "none"}
+	now := time.Now().UTC().Format(time.RFC3339)
+	for i := 1; i <= n; i++ {
+		labels := `[]`
+		if i%10 == 0 {
+			labels = `["bug"]`
+		}
+		if _, err := stmt.ExecContext(ctx, fmt.Sprintf("%s-%d", issueIDPrefix, i), fmt.Sprintf("Issue %d", i), statuses[i%len(statuses)], priorities[i%len(priorities)], labels, i, now, now); err != nil {
+			b.Fatalf("seed issue %d: %v", i, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE meta SET value = ? WHERE key = 'next_issue_number'`, n+1); err != nil {
+		b.Fatalf("seed next issue number: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		b.Fatalf("commit seed: %v", err)
+	}
+	return store
+}
+
+func BenchmarkListIssues(b *testing.B) {
+	store := openBenchStore(b, benchIssues)
+	ctx := context.Background()
+	for _, bc := range []struct {
+		name string
+		f    ListFilter
+	}{
+		{"all", ListFilter{}},
+		{"open", ListFilter{ExcludeDone: true, ExcludeArchived: true}},
+		{"status", ListFilter{Statuses: []string{issue.StatusInProgress}}},
+		{"label", ListFilter{Label: "bug"}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := store.ListIssues(ctx, bc.f); err != nil {
+					b.Fatalf("ListIssues() error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkCreateIssue(b *testing.B) {
+	store := openBenchStore(b, benchIssues)
+	ctx := WithoutEvents(context.Background())
+	for b.Loop() {
+		if _, err := store.CreateIssue(ctx, issue.Item{Title: "Benchmark issue", Status: issue.StatusTodo, Priority: "p2", Labels: []string{"bench"}}); err != nil {
+			b.Fatalf("CreateIssue() error: %v", err)
+		}
+	}
+}
+
+// BenchmarkBulkCreate measures a 10k-row import into the seeded store.
+func BenchmarkBulkCreate(b *testing.B) {
+	store := openBenchStore(b, benchIssues)
+	ctx := WithoutEvents(context.Background())
+	items := make([]issue.Item, 10_000)
+	for i := range items {
+		items[i] = issue.Item{Title: fmt.Sprintf("Imported %d", i), Status: issue.StatusTodo, Priority: "none", Labels: []string{"import"}}
+	}
+	for b.Loop() {
+		if _, err := store.BulkCreate(ctx, items); err != nil {
+			b.Fatalf("BulkCreate() error: %v", err)
+		}
+	}
+}
+
+// BenchmarkCreateIssueParallel measures creates from concurrent goroutines
+// sharing one store, as agents served by one process do.
+func BenchmarkCreateIssueParallel(b *testing.B) {
+	store := openBenchStore(b, benchIssues)
+	ctx := WithoutEvents(context.Background())
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := store.CreateIssue(ctx, issue.Item{Title: "Benchmark issue", Status: issue.StatusTodo, Priority: "p2"}); err != nil {
+				b.Errorf("CreateIssue() error: %v", err)
+				return
+			}
+		}
+	})
+}
+
+// BenchmarkCountIssues compares a count served from the read cache with
+// one that has to run the query, as after every write.
+func BenchmarkCountIssues(b *testing.B) {
+	store := openBenchStore(b, benchIssues)
+	ctx := context.Background()
+	f := ListFilter{ExcludeDone: true, ExcludeArchived: true}
+	b.Run("cached", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := store.CountIssues(ctx, f); err != nil {
+				b.Fatalf("CountIssues() error: %v", err)
+			}
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		for b.Loop() {
+			cacheGen.Add(1)
+			if _, err := store.CountIssues(ctx, f); err != nil {
+				b.Fatalf("CountIssues() error: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+// Block is one stay of an issue in issue.StatusBlocked. Until is empty while
+// the issue is still blocked.
+type Block struct {
+	ID      int
+	IssueID string
+	Reason  string
+	Since   string
+	Until   string
+}
+
+// Duration is how long the block lasted, or has lasted up to now while it is
+// still open.
+func (b Block) Duration(now time.Time) time.Duration {
+	since, err := time.Parse(time.RFC3339, b.Since)
+	if err != nil {
+		return 0
+	}
+	end := now
+	if b.Until != "" {
+		if until, err := time.Parse(time.RFC3339, b.Until); err == nil {
+			end = until
+		}
+	}
+	return max(end.Sub(since), 0)
+}
+
+// ListBlocks returns the blocks of an issue, oldest first.
+func (s *Store) ListBlocks(ctx context.Context, issueID string) ([]Block, error) {
+	return s.queryBlocks(ctx, `WHERE issue_id = ? ORDER BY id`, issueID)
+}
+
+// ListAllBlocks returns the blocks of every issue, oldest first.
+func (s *Store) ListAllBlocks(ctx context.Context) ([]Block, error) {
+	return s.queryBlocks(ctx, `ORDER BY id`)
+}
+
+// CurrentBlocks returns the open block of each issue that is blocked now,
+// keyed by issue ID.
+func (s *Store) CurrentBlocks(ctx context.Context) (map[string]Block, error) {
+	blocks, err := s.queryBlocks(ctx, `WHERE until = '' ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]Block, len(blocks))
+	for _, b := range blocks {
+		out[b.IssueID] = b
+	}
+	return out, nil
+}
+
+func (s *Store) queryBlocks(ctx context.Context, clause string, args ...any) ([]Block, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, issue_id, reason, since, until FROM issue_blocks `+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list blocks: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]Block, 0)
+	for rows.Next() {
+		var b Block
+		if err := rows.Scan(&b.ID, &b.IssueID, &b.Reason, &b.Since, &b.Until); err != nil {
+			return nil, fmt.Errorf("scan block: %w", err)
+		}
+		out = append(out, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate blocks: %w", err)
+	}
+	return out, nil
+}
+
+// recordBlock opens a block when an issue moves to blocked, closes it when
+// the issue moves on, and replaces the reason of the open block when one is
+// given for an issue that stays blocked.
+func (s *Store) recordBlock(ctx context.Context, issueID, from, to string, reason *string, at string) error {
+	return withSQLiteRetry(ctx, func() error {
+		r := ""
+		if reason != nil {
+			r = *reason
+		}
+		var err error
+		switch {
+		case from != issue.StatusBlocked && to == issue.StatusBlocked:
+			_, err = s.db.ExecContext(ctx, `INSERT INTO issue_blocks(issue_id, reason, since) VALUES(?, ?, ?)`, issueID, r, at)
+		case from == issue.StatusBlocked && to != issue.StatusBlocked:
+			_, err = s.db.ExecContext(ctx, `UPDATE issue_blocks SET until = ? WHERE issue_id = ? AND until = ''`, at, issueID)
+		case to == issue.StatusBlocked && reason != nil:
+			// Issues blocked before blocks were recorded have no open block
+			// yet; the first reason given for them starts one.
+			_, err = s.db.ExecContext(ctx, `UPDATE issue_blocks SET reason = ? WHERE issue_id = ? AND until = ''`, r, issueID)
+			if err == nil {
+				_, err = s.db.ExecContext(ctx, `INSERT INTO issue_blocks(issue_id, reason, since)
+					SELECT ?, ?, ? WHERE NOT EXISTS (SELECT 1 FROM issue_blocks WHERE issue_id = ? AND until = '')`, issueID, r, at, issueID)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("record block: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+func TestUpdateIssueRecordsBlocks(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	if err := store.AddStatus(ctx, issue.StatusBlocked); err != nil {
+		t.Fatalf("AddStatus() error: %v", err)
+	}
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "deploy", Status: issue.StatusTodo, Priority: "none"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	ptr := func(s string) *string { return &s }
+
+	if _, err := store.UpdateIssue(ctx, it.ID, UpdateIssueInput{BlockedReason: ptr("waiting")}); err == nil {
+		t.Fatalf("reason without blocked status should fail")
+	}
+	if _, err := store.UpdateIssue(ctx, it.ID, UpdateIssueInput{Status: ptr(issue.StatusBlocked), BlockedReason: ptr(" waiting on infra ")}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+	current, err := store.CurrentBlocks(ctx)
+	if err != nil {
+		t.Fatalf("CurrentBlocks() error: %v", err)
+	}
+	if b, ok := current[it.ID]; !ok || b.Reason != "waiting on infra" || b.Since == "" || b.Until != "" {
+		t.Fatalf("current block = %+v, %v", b, ok)
+	}
+
+	if _, err := store.UpdateIssue(ctx, it.ID, UpdateIssueInput{BlockedReason: ptr("waiting on review")}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+	if _, err := store.UpdateIssue(ctx, it.ID, UpdateIssueInput{Status: ptr(issue.StatusInProgress)}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+	blocks, err := store.ListBlocks(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("ListBlocks() error: %v", err)
+	}
+	if len(blocks) != 1 || blocks[0].Reason != "waiting on review" || blocks[0].Until == "" {
+		t.Fatalf("blocks = %+v", blocks)
+	}
+	if current, _ := store.CurrentBlocks(ctx); len(current) != 0 {
+		t.Fatalf("current blocks after unblocking = %+v", current)
+	}
+}
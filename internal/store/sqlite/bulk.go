@@ -0,0 +1,219 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+// bulkBatchRows is how many rows one multi-row INSERT of BulkCreate
+// carries; 500 issue rows stay well under SQLite's bound-parameter limit.
+const bulkBatchRows = 500
+
+// BulkCreate creates items as CreateIssue would, but in one transaction: it
+// reserves a contiguous range of IDs up front and inserts the issues, their
+// activity, first revisions, mentions, and Links in batches. Either every
+// item is created or none is. EventIssueCreated is published for each issue
+// once the transaction commits.
+func (s *Store) BulkCreate(ctx context.Context, items []issue.Item) ([]issue.Item, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	out, err := s.validateBulk(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.insertBulk(ctx, out, nil); err != nil {
+		return nil, err
+	}
+	return out, s.emitCreated(ctx, out)
+}
+
+// CreateIssueOnce creates item as CreateIssue would, recording rec for it in
+// the same transaction, so concurrent requests with one key create a single
+// issue and a crash cannot keep the issue but lose the key. When rec.Key is
+// already recorded within IdempotencyTTL nothing is created: the recorded
+// record is returned and the third return value is false.
+func (s *Store) CreateIssueOnce(ctx context.Context, item issue.Item, rec IdempotencyRecord) (issue.Item, IdempotencyRecord, bool, error) {
+	out, err := s.validateBulk(ctx, []issue.Item{item})
+	if err != nil {
+		return issue.Item{}, IdempotencyRecord{}, false, err
+	}
+	var existing IdempotencyRecord
+	claimed := false
+	err = s.insertBulk(ctx, out, func(tx *sql.Tx) (bool, error) {
+		if rec.CreatedAt == "" {
+			rec.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+		}
+		rec.IssueID = out[0].ID
+		var err error
+		claimed, existing, err = claimIdempotencyKey(ctx, tx, rec)
+		return claimed, err
+	})
+	if err != nil || !claimed {
+		return issue.Item{}, existing, false, err
+	}
+	return out[0], rec, true, s.emitCreated(ctx, out)
+}
+
+// validateBulk checks items and normalizes them for insertBulk.
+func (s *Store) validateBulk(ctx context.Context, items []issue.Item) ([]issue.Item, error) {
+	out := make([]issue.Item, len(items))
+	checkedAssignees := map[string]bool{}
+	externalIDs := map[string]bool{}
+	for i, item := range items {
+		if err := s.ValidateStatus(ctx, item.Status); err != nil {
+			return nil, err
+		}
+		if err := issue.ValidatePriority(item.Priority); err != nil {
+			return nil, err
+		}
+		due, err := normalizeDue(item.Due)
+		if err != nil {
+			return nil, err
+		}
+		item.Due = due
+		if assignee := issue.NormalizeAssignee(item.Assignee); !checkedAssignees[assignee] {
+			if err := s.validateAssignee(ctx, assignee); err != nil {
+				return nil, err
+			}
+			checkedAssignees[assignee] = true
+		}
+		item.ExternalID = strings.TrimSpace(item.ExternalID)
+		if item.ExternalID != "" {
+			if externalIDs[item.ExternalID] {
+				return nil, fmt.Errorf("external id already used: %s", item.ExternalID)
+			}
+			externalIDs[item.ExternalID] = true
+		}
+		links := make([]issue.Link, 0, len(item.Links))
+		seen := map[string]int{}
+		for _, l := range item.Links {
+			u, err := normalizeLinkURL(l.URL)
+			if err != nil {
+				return nil, err
+			}
+			l.URL, l.Title = u, strings.TrimSpace(l.Title)
+			if j, ok := seen[u]; ok {
+				links[j] = l
+				continue
+			}
+			seen[u] = len(links)
+			links = append(links, l)
+		}
+		item.Links = links
+		out[i] = item
+	}
+	return out, nil
+}
+
+// insertBulk assigns IDs to items and inserts them in one transaction. When
+// claim is given it runs in that transaction once the IDs are set, and the
+// transaction is rolled back when it returns false.
+func (s *Store) insertBulk(ctx context.Context, out []issue.Item, claim func(tx *sql.Tx) (bool, error)) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	actor := Actor(ctx)
+	return withSQLiteRetry(ctx, func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("begin tx: %w", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		first, err := reserveIssueNumbers(ctx, tx, len(out))
+		if err != nil {
+			return err
+		}
+		var maxOrder sql.NullInt64
+		if err := tx.QueryRowContext(ctx, `SELECT MAX(order_index) FROM issues`).Scan(&maxOrder); err != nil {
+			return fmt.Errorf("read max order index: %w", err)
+		}
+
+		issueRows := make([][]any, 0, len(out))
+		activityRows := make([][]any, 0, len(out))
+		revisionRows := make([][]any, 0, len(out))
+		var mentionRows, linkRows [][]any
+		for i := range out {
+			item := &out[i]
+			item.ID = fmt.Sprintf("%s-%d", issueIDPrefix, first+i)
+			item.CreatedAt = now
+			item.UpdatedAt = now
+			labelsJSON, err := json.Marshal(item.Labels)
+			if err != nil {
+				return fmt.Errorf("marshal labels: %w", err)
+			}
+			issueRows = append(issueRows, []any{
+				item.ID, item.Title, item.Status, item.Priority, nullable(item.Assignee), nullable(item.Due), string(labelsJSON), nullable(item.NextAction), nullable(item.Body), nullable(item.ExternalID), int(maxOrder.Int64) + 1 + i, now, now,
+			})
+			activityRows = append(activityRows, []any{ActivityCreated, item.ID, item.Title, actor, now})
+			revisionRows = append(revisionRows, []any{item.ID, 1, item.Body, actor, now})
+			for _, target := range issue.Mentions(item.Body) {
+				if target != item.ID {
+					mentionRows = append(mentionRows, []any{item.ID, target, mentionBody, now})
+				}
+			}
+			for _, l := range item.Links {
+				linkRows = append(linkRows, []any{item.ID, l.URL, l.Title, now})
+			}
+		}
+
+		if claim != nil {
+			if ok, err := claim(tx); err != nil || !ok {
+				return err
+			}
+		}
+
+		if err := insertRows(ctx, tx, `INSERT INTO issues(id, title, status, priority, assignee, due, labels_json, next_action, body, external_id, order_index, created_at, updated_at) VALUES`, issueRows); err != nil {
+			return externalIDError(err, "", "insert issues")
+		}
+		if err := insertRows(ctx, tx, `INSERT INTO activity(kind, issue_id, detail, actor, created_at) VALUES`, activityRows); err != nil {
+			return fmt.Errorf("record activity: %w", err)
+		}
+		if err := insertRows(ctx, tx, `INSERT INTO issue_revisions(issue_id, rev, body, actor, created_at) VALUES`, revisionRows); err != nil {
+			return fmt.Errorf("snapshot issue body: %w", err)
+		}
+		if err := insertRows(ctx, tx, `INSERT INTO issue_mentions(source_id, target_id, origin, created_at) VALUES`, mentionRows); err != nil {
+			return fmt.Errorf("record mentions: %w", err)
+		}
+		if err := insertRows(ctx, tx, `INSERT INTO issue_links(issue_id, url, title, created_at) VALUES`, linkRows); err != nil {
+			return fmt.Errorf("add links: %w", err)
+		}
+		return tx.Commit()
+	})
+}
+
+func (s *Store) emitCreated(ctx context.Context, items []issue.Item) error {
+	events := make([]Event, 0, len(items))
+	for _, item := range items {
+		events = append(events, Event{Type: EventIssueCreated, IssueID: item.ID, Store: s})
+	}
+	return emit(ctx, events)
+}
+
+// insertRows runs insert, an INSERT statement ending in VALUES, for rows in
+// batches of bulkBatchRows.
+func insertRows(ctx context.Context, tx *sql.Tx, insert string, rows [][]any) error {
+	for start := 0; start < len(rows); start += bulkBatchRows {
+		batch := rows[start:min(start+bulkBatchRows, len(rows))]
+		placeholder := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(batch[0])), ", ") + ")"
+		var b strings.Builder
+		b.WriteString(insert)
+		args := make([]any, 0, len(batch)*len(batch[0]))
+		for i, row := range batch {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString(" " + placeholder)
+			args = append(args, row...)
+		}
+		if _, err := tx.ExecContext(ctx, b.String(), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+func TestBulkCreate(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	first, err := store.CreateIssue(ctx, issue.Item{Title: "existing", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	var created []string
+	unsubscribe := Subscribe(func(_ context.Context, ev Event) error {
+		if ev.Type == EventIssueCreated {
+			created = append(created, ev.IssueID)
+		}
+		return nil
+	})
+	defer unsubscribe()
+
+	items := make([]issue.Item, 1200)
+	for i := range items {
+		items[i] = issue.Item{Title: fmt.Sprintf("row %d", i), Status: issue.StatusTodo, Priority: "none", Labels: []string{"imported"}}
+	}
+	items[0].Body = "see " + first.ID
+	items[1].ExternalID = " JIRA-1 "
+	items[1].Links = []issue.Link{{URL: "https://example.com/a", Title: "old"}, {URL: " https://example.com/a ", Title: "spec"}, {URL: "https://example.com/b"}}
+	got, err := store.BulkCreate(ctx, items)
+	if err != nil {
+		t.Fatalf("BulkCreate() error: %v", err)
+	}
+	if len(got) != len(items) || got[0].ID != "TRK-2" || got[len(got)-1].ID != "TRK-1201" {
+		t.Fatalf("BulkCreate() IDs = %s..%s (%d)", got[0].ID, got[len(got)-1].ID, len(got))
+	}
+	if len(created) != len(items) || created[0] != "TRK-2" {
+		t.Fatalf("created events = %d, first %v", len(created), created[:min(1, len(created))])
+	}
+
+	row, err := store.GetIssue(ctx, "TRK-3")
+	if err != nil || row.Title != "row 1" || row.ExternalID != "JIRA-1" || len(row.Labels) != 1 {
+		t.Fatalf("GetIssue(TRK-3) = %+v, %v", row, err)
+	}
+	if links, err := store.ListLinks(ctx, "TRK-3"); err != nil || len(links) != 2 || links[0].URL != "https://example.com/a" || links[0].Title != "spec" {
+		t.Fatalf("ListLinks(TRK-3) = %+v, %v", links, err)
+	}
+	if n, err := store.CountIssues(ctx, ListFilter{}); err != nil || n != len(items)+1 {
+		t.Fatalf("CountIssues() = %d, %v", n, err)
+	}
+	if refs, err := store.ReferencedBy(ctx, first.ID); err != nil || len(refs) != 1 || refs[0] != "TRK-2" {
+		t.Fatalf("ReferencedBy() = %v, %v", refs, err)
+	}
+	if revs, err := store.ListRevisions(ctx, "TRK-2"); err != nil || len(revs) != 1 || revs[0].Body != items[0].Body {
+		t.Fatalf("ListRevisions() = %+v, %v", revs, err)
+	}
+	if activity, err := store.ListIssueActivity(ctx, "TRK-1201"); err != nil || len(activity) != 1 || activity[0].Kind != ActivityCreated {
+		t.Fatalf("ListIssueActivity() = %+v, %v", activity, err)
+	}
+	next, err := store.CreateIssue(ctx, issue.Item{Title: "after", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil || next.ID != "TRK-1202" {
+		t.Fatalf("CreateIssue() after bulk = %s, %v", next.ID, err)
+	}
+
+	// A failing row leaves nothing behind, not even the reserved IDs.
+	bad := []issue.Item{
+		{Title: "ok", Status: issue.StatusTodo, Priority: "none"},
+		{Title: "dup", Status: issue.StatusTodo, Priority: "none", ExternalID: "JIRA-1"},
+	}
+	if _, err := store.BulkCreate(ctx, bad); err == nil {
+		t.Fatal("BulkCreate() with a used external ID should fail")
+	}
+	if _, err := store.BulkCreate(ctx, []issue.Item{{Title: "x", Status: issue.StatusTodo, Priority: "none", Links: []issue.Link{{URL: "not a url"}}}}); err == nil {
+		t.Fatal("BulkCreate() with an invalid link should fail")
+	}
+	if _, err := store.BulkCreate(ctx, []issue.Item{{Title: "x", Status: "nope", Priority: "none"}}); err == nil {
+		t.Fatal("BulkCreate() with an unknown status should fail")
+	}
+	if n, err := store.CountIssues(ctx, ListFilter{}); err != nil || n != len(items)+2 {
+		t.Fatalf("CountIssues() after failed bulk = %d, %v", n, err)
+	}
+	if id, err := store.NextIssueID(ctx); err != nil || id != "TRK-1203" {
+		t.Fatalf("NextIssueID() = %s, %v", id, err)
+	}
+}
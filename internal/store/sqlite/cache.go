@@ -0,0 +1,193 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	sqlitedriver "modernc.org/sqlite"
+)
+
+// cacheGen counts the events published in this process. Every event makes
+// the read caches of all stores start over.
+var cacheGen atomic.Uint64
+
+func init() {
+	Subscribe(func(ctx context.Context, ev Event) error {
+		cacheGen.Add(1)
+		return nil
+	})
+}
+
+// readCache keeps the results of hot reads: the statuses, the projects, the
+// share secret, and issue counts per filter. Besides events, it is invalidated whenever the
+// database changes underneath it, since writes to statuses and projects,
+// writes made WithoutEvents, and other processes publish nothing here.
+type readCache struct {
+	mu sync.Mutex
+	// conns remembers, for each pooled connection the cache has checked
+	// on, what it last saw there.
+	conns       map[any]*connVersion
+	gen         uint64
+	statuses    []string
+	projects    []Project
+	shareSecret []byte
+	counts      map[string]int
+}
+
+// connVersion tracks the changes one pooled connection can see. Its
+// data_version moves whenever any other connection commits, be it another
+// of the pool's, another store's, or another process's; commits counts the
+// ones it makes itself, which data_version leaves out.
+type connVersion struct {
+	commits     atomic.Uint64
+	dataVersion int64
+	seen        uint64
+}
+
+// cache returns the store's read cache, emptied first when the database has
+// changed since it was filled. It checks on a connection borrowed from the
+// pool only for that, so the cache holds none between reads. The caller
+// holds its lock until done with it.
+func (s *Store) cache(ctx context.Context) (*readCache, error) {
+	c := &s.reads
+	c.mu.Lock()
+	changed, err := s.dataChanged(ctx, c)
+	if err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	if gen := cacheGen.Load(); changed || c.gen != gen {
+		c.gen = gen
+		c.statuses, c.projects, c.shareSecret, c.counts = nil, nil, nil, nil
+	}
+	return c, nil
+}
+
+// dataChanged reports whether anything may have been committed since the
+// cache last checked on the connection it borrows. A connection it has not
+// seen before counts as a change.
+func (s *Store) dataChanged(ctx context.Context, c *readCache) (bool, error) {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("open cache connection: %w", err)
+	}
+	defer conn.Close()
+
+	var v *connVersion
+	changed := false
+	if err := conn.Raw(func(dc any) error {
+		if v = c.conns[dc]; v != nil {
+			return nil
+		}
+		hooks, ok := dc.(sqlitedriver.HookRegisterer)
+		if !ok {
+			return fmt.Errorf("sqlite driver cannot report commits")
+		}
+		v, changed = &connVersion{}, true
+		hooks.RegisterCommitHook(func() int32 {
+			v.commits.Add(1)
+			return 0
+		})
+		if c.conns == nil {
+			c.conns = map[any]*connVersion{}
+		}
+		c.conns[dc] = v
+		return nil
+	}); err != nil {
+		return false, err
+	}
+	var dataVersion int64
+	if err := conn.QueryRowContext(ctx, `PRAGMA data_version`).Scan(&dataVersion); err != nil {
+		return false, fmt.Errorf("read data version: %w", err)
+	}
+	commits := v.commits.Load()
+	if v.dataVersion != dataVersion || v.seen != commits {
+		changed = true
+	}
+	v.dataVersion, v.seen = dataVersion, commits
+	return changed, nil
+}
+
+func (s *Store) cachedStatuses(ctx context.Context) ([]string, error) {
+	c, err := s.cache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.mu.Unlock()
+	if c.statuses == nil {
+		if c.statuses, err = s.queryStatuses(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return slices.Clone(c.statuses), nil
+}
+
+func (s *Store) cachedProjects(ctx context.Context) ([]Project, error) {
+	c, err := s.cache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.mu.Unlock()
+	if c.projects == nil {
+		if c.projects, err = s.queryProjects(ctx); err != nil {
+			return nil, err
+		}
+	}
+	out := slices.Clone(c.projects)
+	for i := range out {
+		out[i].Defaults.Labels = slices.Clone(out[i].Defaults.Labels)
+	}
+	return out, nil
+}
+
+func (s *Store) cachedShareSecret(ctx context.Context) ([]byte, error) {
+	c, err := s.cache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.mu.Unlock()
+	if c.shareSecret == nil {
+		if c.shareSecret, err = s.queryShareSecret(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return slices.Clone(c.shareSecret), nil
+}
+
+func (s *Store) cachedCount(ctx context.Context, where string, args []any) (int, error) {
+	c, err := s.cache(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer c.mu.Unlock()
+	key := countKey(where, args)
+	if n, ok := c.counts[key]; ok {
+		return n, nil
+	}
+	stmt, err := s.prepared(ctx, `SELECT COUNT(*) FROM issues`+where)
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	if err := stmt.QueryRowContext(ctx, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count issues: %w", err)
+	}
+	if c.counts == nil {
+		c.counts = map[string]int{}
+	}
+	c.counts[key] = n
+	return n, nil
+}
+
+func countKey(where string, args []any) string {
+	var b strings.Builder
+	b.WriteString(where)
+	for _, a := range args {
+		fmt.Fprintf(&b, "\x00%T:%v", a, a)
+	}
+	return b.String()
+}
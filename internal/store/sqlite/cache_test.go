@@ -0,0 +1,195 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+func TestReadCacheInvalidation(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	other, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = other.Close() })
+
+	statuses, err := store.ListStatuses(ctx)
+	if err != nil {
+		t.Fatalf("ListStatuses() error: %v", err)
+	}
+	first := &store.reads.statuses[0]
+	statuses[0] = "mutated"
+	again, err := store.ListStatuses(ctx)
+	if err != nil {
+		t.Fatalf("ListStatuses() error: %v", err)
+	}
+	if again[0] != issue.StatusTodo || &store.reads.statuses[0] != first {
+		t.Fatalf("second read should come from the cache unchanged: %v", again)
+	}
+
+	// A write through another connection moves data_version.
+	if err := other.AddStatus(ctx, "blocked"); err != nil {
+		t.Fatalf("AddStatus() error: %v", err)
+	}
+	if err := store.ValidateStatus(ctx, "blocked"); err != nil {
+		t.Fatalf("status added elsewhere should be seen: %v", err)
+	}
+
+	todo := ListFilter{Statuses: []string{issue.StatusTodo}}
+	if n, err := store.CountIssues(ctx, todo); err != nil || n != 0 {
+		t.Fatalf("CountIssues() = %d, %v", n, err)
+	}
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "A", Status: issue.StatusTodo, Priority: "none"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	if n, err := store.CountIssues(ctx, todo); err != nil || n != 1 {
+		t.Fatalf("CountIssues() after create = %d, %v", n, err)
+	}
+	// Writes that publish no events still invalidate the cache.
+	done := issue.StatusDone
+	if _, err := store.UpdateIssue(WithoutEvents(ctx), it.ID, UpdateIssueInput{Status: &done}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+	if n, err := store.CountIssues(ctx, todo); err != nil || n != 0 {
+		t.Fatalf("CountIssues() after a quiet update = %d, %v", n, err)
+	}
+
+	if _, err := store.CreateProject(ctx, "web", "Web", ""); err != nil {
+		t.Fatalf("CreateProject() error: %v", err)
+	}
+	projects, err := other.ListProjects(ctx)
+	if err != nil || !slices.ContainsFunc(projects, func(p Project) bool { return p.Key == "web" }) {
+		t.Fatalf("ListProjects() = %+v, %v", projects, err)
+	}
+}
+
+func TestReadCacheBorrowsPooledConnections(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	// Open the whole pool so reads land on several connections.
+	var conns []*sql.Conn
+	for range maxOpenConns {
+		conn, err := store.db.Conn(ctx)
+		if err != nil {
+			t.Fatalf("Conn() error: %v", err)
+		}
+		conns = append(conns, conn)
+	}
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+
+	refills := 0
+	var last *string
+	for range 50 {
+		if _, err := store.ListStatuses(ctx); err != nil {
+			t.Fatalf("ListStatuses() error: %v", err)
+		}
+		if inUse := store.db.Stats().InUse; inUse != 0 {
+			t.Fatalf("cache holds %d connections between reads", inUse)
+		}
+		if first := &store.reads.statuses[0]; first != last {
+			refills++
+			last = first
+		}
+	}
+	// Each connection is new to the cache once; after that every read hits.
+	if refills > maxOpenConns {
+		t.Fatalf("statuses were read %d times in 50 cached reads", refills)
+	}
+
+	// Writes that publish nothing are seen whichever connection made them
+	// and whichever one the cache checks on next.
+	for i := range 2 * maxOpenConns {
+		name := fmt.Sprintf("stage-%d", i)
+		if err := store.AddStatus(ctx, name); err != nil {
+			t.Fatalf("AddStatus(%s) error: %v", name, err)
+		}
+		statuses, err := store.ListStatuses(ctx)
+		if err != nil || !slices.Contains(statuses, name) {
+			t.Fatalf("ListStatuses() after AddStatus(%s) = %v, %v", name, statuses, err)
+		}
+	}
+}
+
+func TestShareSecretWritesOnlyWhenMissing(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	other, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = other.Close() })
+
+	// data_version on a connection of the other store moves whenever this
+	// one commits.
+	watch, err := other.db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Conn() error: %v", err)
+	}
+	t.Cleanup(func() { _ = watch.Close() })
+	dataVersion := func() int64 {
+		t.Helper()
+		var v int64
+		if err := watch.QueryRowContext(ctx, `PRAGMA data_version`).Scan(&v); err != nil {
+			t.Fatalf("data_version: %v", err)
+		}
+		return v
+	}
+
+	before := dataVersion()
+	secret, err := store.ShareSecret(ctx)
+	if err != nil || len(secret) == 0 {
+		t.Fatalf("ShareSecret() = %q, %v", secret, err)
+	}
+	created := dataVersion()
+	if created == before {
+		t.Fatal("the first ShareSecret() should store the secret")
+	}
+	for range 5 {
+		again, err := store.ShareSecret(ctx)
+		if err != nil || string(again) != string(secret) {
+			t.Fatalf("ShareSecret() = %q, %v; want %q", again, err, secret)
+		}
+		if got, err := other.ShareSecret(ctx); err != nil || string(got) != string(secret) {
+			t.Fatalf("other ShareSecret() = %q, %v; want %q", got, err, secret)
+		}
+	}
+	if dataVersion() != created {
+		t.Fatal("ShareSecret() wrote to the database although the secret exists")
+	}
+	cached := &store.reads.shareSecret[0]
+	if _, err := store.ShareSecret(ctx); err != nil || &store.reads.shareSecret[0] != cached {
+		t.Fatalf("ShareSecret() should come from the cache: %v", err)
+	}
+
+	if err := other.RotateShareSecret(ctx); err != nil {
+		t.Fatalf("RotateShareSecret() error: %v", err)
+	}
+	if rotated, err := store.ShareSecret(ctx); err != nil || string(rotated) == string(secret) {
+		t.Fatalf("ShareSecret() after rotating elsewhere = %q, %v", rotated, err)
+	}
+}
@@ -0,0 +1,99 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Comment is one message in an issue's discussion, kept apart from the
+// body. Author is Actor(ctx) when it was added.
+type Comment struct {
+	ID        int
+	IssueID   string
+	Author    string
+	Body      string
+	CreatedAt string
+}
+
+// AddComment appends a comment to an issue. It is also recorded in the
+// activity feed, which links the issues it references by ID, and it bumps
+// the issue's updated_at and publishes EventIssueUpdated, so hooks and
+// watchers see the discussion move.
+func (s *Store) AddComment(ctx context.Context, issueID, body string) (Comment, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return Comment{}, fmt.Errorf("comment must not be empty")
+	}
+	if _, err := s.GetIssue(ctx, issueID); err != nil {
+		return Comment{}, err
+	}
+	c := Comment{
+		IssueID:   issueID,
+		Author:    Actor(ctx),
+		Body:      body,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	err := withSQLiteRetry(ctx, func() error {
+		res, err := s.db.ExecContext(ctx, `INSERT INTO comments(issue_id, author, body, created_at) VALUES(?, ?, ?, ?)`,
+			c.IssueID, c.Author, c.Body, c.CreatedAt)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		c.ID = int(id)
+		return err
+	})
+	if err != nil {
+		return Comment{}, fmt.Errorf("add comment: %w", err)
+	}
+	if err := s.RecordActivity(ctx, ActivityComment, issueID, body); err != nil {
+		return c, err
+	}
+	if err := withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `UPDATE issues SET updated_at = ? WHERE id = ?`, c.CreatedAt, issueID)
+		return err
+	}); err != nil {
+		return c, fmt.Errorf("touch issue: %w", err)
+	}
+	return c, s.publish(ctx, issueID, EventIssueUpdated)
+}
+
+// ListComments returns an issue's comments, oldest first. A positive limit
+// keeps only the most recent ones.
+func (s *Store) ListComments(ctx context.Context, issueID string, limit int) ([]Comment, error) {
+	query := `SELECT id, issue_id, author, body, created_at FROM comments WHERE issue_id = ? ORDER BY id`
+	args := []any{issueID}
+	if limit > 0 {
+		query = `SELECT * FROM (SELECT id, issue_id, author, body, created_at FROM comments WHERE issue_id = ? ORDER BY id DESC LIMIT ?) ORDER BY id`
+		args = append(args, limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list comments: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]Comment, 0)
+	for rows.Next() {
+		var c Comment
+		if err := rows.Scan(&c.ID, &c.IssueID, &c.Author, &c.Body, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan comment: %w", err)
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate comments: %w", err)
+	}
+	return out, nil
+}
+
+// CountComments returns how many comments an issue has.
+func (s *Store) CountComments(ctx context.Context, issueID string) (int, error) {
+	var n int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM comments WHERE issue_id = ?`, issueID).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count comments: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,92 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+func TestComments(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := WithActor(context.Background(), "alice")
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "A", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	if _, err := store.AddComment(ctx, it.ID, "  "); err == nil {
+		t.Fatal("empty comment should be rejected")
+	}
+	if _, err := store.AddComment(ctx, "TRK-99", "hi"); err == nil {
+		t.Fatal("comment on a missing issue should fail")
+	}
+	for _, body := range []string{"first", "second", "third"} {
+		if _, err := store.AddComment(ctx, it.ID, body); err != nil {
+			t.Fatalf("AddComment() error: %v", err)
+		}
+	}
+
+	all, err := store.ListComments(ctx, it.ID, 0)
+	if err != nil || len(all) != 3 || all[0].Body != "first" || all[0].Author != "alice" {
+		t.Fatalf("ListComments() = %+v, %v", all, err)
+	}
+	recent, err := store.ListComments(ctx, it.ID, 2)
+	if err != nil || len(recent) != 2 || recent[0].Body != "second" || recent[1].Body != "third" {
+		t.Fatalf("ListComments(limit 2) = %+v, %v", recent, err)
+	}
+	if n, err := store.CountComments(ctx, it.ID); err != nil || n != 3 {
+		t.Fatalf("CountComments() = %d, %v", n, err)
+	}
+
+	activity, err := store.ListIssueActivity(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("ListIssueActivity() error: %v", err)
+	}
+	if last := activity[len(activity)-1]; last.Kind != ActivityComment || last.Detail != "third" {
+		t.Fatalf("last activity = %+v, want the comment", last)
+	}
+}
+
+func TestAddCommentPublishesIssueUpdated(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "A", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	if _, err := store.db.ExecContext(ctx, `UPDATE issues SET updated_at = '2020-01-01T00:00:00Z' WHERE id = ?`, it.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	var updated []string
+	unsubscribe := Subscribe(func(_ context.Context, ev Event) error {
+		if ev.Type == EventIssueUpdated {
+			updated = append(updated, ev.IssueID)
+		}
+		return nil
+	})
+	defer unsubscribe()
+
+	if _, err := store.AddComment(ctx, it.ID, "looks good"); err != nil {
+		t.Fatalf("AddComment() error: %v", err)
+	}
+	if len(updated) != 1 || updated[0] != it.ID {
+		t.Fatalf("issue.updated events = %v, want one for %s", updated, it.ID)
+	}
+	got, err := store.GetIssue(ctx, it.ID)
+	if err != nil || got.UpdatedAt == "2020-01-01T00:00:00Z" {
+		t.Fatalf("updated_at = %q, %v; want it bumped by the comment", got.UpdatedAt, err)
+	}
+}
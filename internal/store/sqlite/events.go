@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+// Event types published by the store. Hooks use the same names.
+const (
+	EventIssueCreated      = "issue.created"
+	EventIssueUpdated      = "issue.updated"
+	EventIssueStatusChange = "issue.status_changed"
+	EventIssueCompleted    = "issue.completed"
+)
+
+// Event describes a committed mutation. Store is the store that made it, so
+// subscribers can read and write through the same connection.
+type Event struct {
+	Type    string
+	IssueID string
+	Store   *Store
+}
+
+// EventHandler reacts to a published event. A returned error is reported to
+// the caller of the mutation; the mutation itself stays committed.
+type EventHandler func(ctx context.Context, ev Event) error
+
+// EventError reports that a mutation was saved but a subscriber failed while
+// handling one of its events. Its message is the subscriber's error.
+type EventError struct {
+	Event string
+	Err   error
+}
+
+func (e *EventError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *EventError) Unwrap() error {
+	return e.Err
+}
+
+type subscription struct {
+	id      int
+	handler EventHandler
+}
+
+var bus struct {
+	mu     sync.RWMutex
+	nextID int
+	subs   []subscription
+}
+
+// Subscribe registers h for every event published by any store in this
+// process and returns a function that removes it. Handlers run synchronously
+// in subscription order.
+func Subscribe(h EventHandler) (unsubscribe func()) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.nextID++
+	id := bus.nextID
+	bus.subs = append(bus.subs, subscription{id: id, handler: h})
+	return func() {
+		bus.mu.Lock()
+		defer bus.mu.Unlock()
+		bus.subs = slices.DeleteFunc(bus.subs, func(s subscription) bool { return s.id == id })
+	}
+}
+
+type eventsCtxKey struct{}
+
+type eventBatch struct {
+	events []Event
+}
+
+// WithoutEvents returns a context whose mutations publish nothing. Built-in
+// automations use it so their own writes do not re-trigger themselves.
+func WithoutEvents(ctx context.Context) context.Context {
+	return context.WithValue(ctx, eventsCtxKey{}, (*eventBatch)(nil))
+}
+
+// BatchEvents returns a context that queues events instead of publishing
+// them, and a flush function that publishes the queue once, dropping
+// duplicates. Use it when one logical change takes several store calls.
+func BatchEvents(ctx context.Context) (context.Context, func() error) {
+	batch := &eventBatch{}
+	flush := func() error {
+		events := batch.events
+		batch.events = nil
+		return emit(ctx, events)
+	}
+	return context.WithValue(ctx, eventsCtxKey{}, batch), flush
+}
+
+// publish announces events for issueID, or queues them when ctx is batching.
+func (s *Store) publish(ctx context.Context, issueID string, types ...string) error {
+	events := make([]Event, 0, len(types))
+	for _, t := range types {
+		events = append(events, Event{Type: t, IssueID: issueID, Store: s})
+	}
+	return emit(ctx, events)
+}
+
+func emit(ctx context.Context, events []Event) error {
+	if v, ok := ctx.Value(eventsCtxKey{}).(*eventBatch); ok {
+		if v == nil {
+			return nil
+		}
+		for _, ev := range events {
+			if !slices.ContainsFunc(v.events, func(q Event) bool { return q.Type == ev.Type && q.IssueID == ev.IssueID }) {
+				v.events = append(v.events, ev)
+			}
+		}
+		return nil
+	}
+	return deliver(ctx, events)
+}
+
+func deliver(ctx context.Context, events []Event) error {
+	bus.mu.RLock()
+	subs := slices.Clone(bus.subs)
+	bus.mu.RUnlock()
+	for _, ev := range events {
+		for _, sub := range subs {
+			if err := sub.handler(ctx, ev); err != nil {
+				return &EventError{Event: ev.Type, Err: err}
+			}
+		}
+	}
+	return nil
+}
+
+// statusEvents lists the events for a change from one status to another.
+func statusEvents(from, to string) []string {
+	types := []string{EventIssueUpdated}
+	if from != to {
+		types = append(types, EventIssueStatusChange)
+		if to == issue.StatusDone {
+			types = append(types, EventIssueCompleted)
+		}
+	}
+	return types
+}
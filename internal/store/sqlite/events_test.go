@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+func TestMutationsPublishEvents(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	var got []string
+	unsubscribe := Subscribe(func(ctx context.Context, ev Event) error {
+		if ev.Store != store {
+			t.Errorf("event store = %p, want %p", ev.Store, store)
+		}
+		got = append(got, ev.Type+" "+ev.IssueID)
+		return nil
+	})
+	t.Cleanup(unsubscribe)
+
+	created, err := store.CreateIssue(ctx, issue.Item{Title: "A", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	done := issue.StatusDone
+	if _, err := store.UpdateIssue(ctx, created.ID, UpdateIssueInput{Status: &done}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+	if _, err := store.UpdateIssue(ctx, created.ID, UpdateIssueInput{Status: &done}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+	if _, err := store.AddLabel(WithoutEvents(ctx), created.ID, "quiet"); err != nil {
+		t.Fatalf("AddLabel() error: %v", err)
+	}
+
+	batchCtx, flush := BatchEvents(ctx)
+	if _, err := store.AddLabel(batchCtx, created.ID, "x"); err != nil {
+		t.Fatalf("AddLabel() error: %v", err)
+	}
+	if _, err := store.SetNextAction(batchCtx, created.ID, "ship"); err != nil {
+		t.Fatalf("SetNextAction() error: %v", err)
+	}
+	before := len(got)
+	if err := flush(); err != nil {
+		t.Fatalf("flush() error: %v", err)
+	}
+	if before != 5 {
+		t.Fatalf("batched events were published before flush: %v", got)
+	}
+
+	want := []string{
+		"issue.created TRK-1",
+		"issue.updated TRK-1",
+		"issue.status_changed TRK-1",
+		"issue.completed TRK-1",
+		"issue.updated TRK-1",
+		"issue.updated TRK-1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+}
+
+func TestSubscriberErrorIsReportedAfterCommit(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	boom := errors.New("boom")
+	unsubscribe := Subscribe(func(ctx context.Context, ev Event) error { return boom })
+	created, err := store.CreateIssue(ctx, issue.Item{Title: "A", Status: issue.StatusTodo, Priority: "p2"})
+	unsubscribe()
+
+	var evErr *EventError
+	if !errors.As(err, &evErr) || evErr.Event != EventIssueCreated || !errors.Is(err, boom) {
+		t.Fatalf("CreateIssue() error = %v, want EventError wrapping boom", err)
+	}
+	if _, err := store.GetIssue(ctx, created.ID); err != nil {
+		t.Fatalf("issue should be saved despite the subscriber error: %v", err)
+	}
+	if _, err := store.SetNextAction(ctx, created.ID, "x"); err != nil {
+		t.Fatalf("SetNextAction() after unsubscribe error: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Freeze records that automation is paused: hooks, rules, autopilot, gh
+// watch, and GitHub webhooks leave issues alone until it is lifted, so a
+// person can step in without racing them.
+type Freeze struct {
+	By     string `json:"by"`
+	Reason string `json:"reason,omitempty"`
+	At     string `json:"at"`
+}
+
+// FreezeAutomation pauses automation. Freezing again keeps the first
+// freeze, which is returned.
+func (s *Store) FreezeAutomation(ctx context.Context, reason string) (Freeze, error) {
+	raw, err := json.Marshal(Freeze{By: Actor(ctx), Reason: reason, At: time.Now().UTC().Format(time.RFC3339)})
+	if err != nil {
+		return Freeze{}, err
+	}
+	err = withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `INSERT INTO meta(key, value) VALUES('freeze', ?) ON CONFLICT(key) DO NOTHING`, string(raw))
+		return err
+	})
+	if err != nil {
+		return Freeze{}, fmt.Errorf("freeze automation: %w", err)
+	}
+	f, _, err := s.AutomationFrozen(ctx)
+	return f, err
+}
+
+// ThawAutomation lifts the freeze. It reports whether automation was frozen.
+func (s *Store) ThawAutomation(ctx context.Context) (bool, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM meta WHERE key = 'freeze'`)
+	if err != nil {
+		return false, fmt.Errorf("thaw automation: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// AutomationFrozen returns the current freeze, if any.
+func (s *Store) AutomationFrozen(ctx context.Context) (Freeze, bool, error) {
+	var raw string
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM meta WHERE key = 'freeze'`).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Freeze{}, false, nil
+	}
+	if err != nil {
+		return Freeze{}, false, fmt.Errorf("read freeze: %w", err)
+	}
+	var f Freeze
+	if err := json.Unmarshal([]byte(raw), &f); err != nil {
+		return Freeze{}, false, fmt.Errorf("decode freeze: %w", err)
+	}
+	return f, true, nil
+}
+
+// Error explains why f blocks an automated change.
+func (f Freeze) Error() string {
+	msg := fmt.Sprintf("automation is frozen by %s since %s", f.By, f.At)
+	if f.Reason != "" {
+		msg += ": " + f.Reason
+	}
+	return msg + " (track thaw resumes it)"
+}
@@ -68,3 +68,30 @@ func (s *Store) ListGitHubLinks(ctx context.Context, repo string) ([]GitHubLink,
 	}
 	return out, nil
 }
+
+// GitHubLinksForPR returns the issues linked to pull request number in repo.
+// Links saved without a repo match any repo.
+func (s *Store) GitHubLinksForPR(ctx context.Context, repo, number string) ([]GitHubLink, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT issue_id, pr_ref, COALESCE(repo, ''), created_at, updated_at FROM github_links
+		WHERE pr_ref = ? AND (repo IS NULL OR repo = '' OR repo = ?)
+		ORDER BY issue_id ASC
+	`, number, repo)
+	if err != nil {
+		return nil, fmt.Errorf("find github links: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]GitHubLink, 0)
+	for rows.Next() {
+		var l GitHubLink
+		if err := rows.Scan(&l.IssueID, &l.PRRef, &l.Repo, &l.CreatedAt, &l.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan github link: %w", err)
+		}
+		out = append(out, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate github links: %w", err)
+	}
+	return out, nil
+}
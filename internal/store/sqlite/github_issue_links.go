@@ -44,3 +44,31 @@ func (s *Store) GetGitHubIssueLink(ctx context.Context, issueID string) (GitHubI
 	}
 	return out, nil
 }
+
+// GitHubIssueLinksFor returns the issues linked to GitHub issue number in
+// repo. Links saved without a repo match any repo.
+func (s *Store) GitHubIssueLinksFor(ctx context.Context, repo, number string) ([]GitHubIssueLink, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT issue_id, gh_issue_number, gh_issue_url, COALESCE(repo, ''), created_at, updated_at
+		FROM github_issue_links
+		WHERE gh_issue_number = ? AND (repo IS NULL OR repo = '' OR repo = ?)
+		ORDER BY issue_id ASC
+	`, number, repo)
+	if err != nil {
+		return nil, fmt.Errorf("find github issue links: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]GitHubIssueLink, 0)
+	for rows.Next() {
+		var l GitHubIssueLink
+		if err := rows.Scan(&l.IssueID, &l.GHIssueNumber, &l.GHIssueURL, &l.Repo, &l.CreatedAt, &l.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan github issue link: %w", err)
+		}
+		out = append(out, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate github issue links: %w", err)
+	}
+	return out, nil
+}
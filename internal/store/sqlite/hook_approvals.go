@@ -0,0 +1,162 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	appconfig "github.com/myuon/track/internal/config"
+)
+
+// HookApprovalsPath is the file that lists the approved hook commands. It is
+// kept next to the database rather than in it, so a database that was
+// synced, restored, or imported from elsewhere cannot approve its own hooks.
+func HookApprovalsPath() (string, error) {
+	home, err := appconfig.HomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "hooks.allow"), nil
+}
+
+// Fingerprint identifies what a hook runs: its event, command, and working
+// directory. Changing any of them needs a new approval.
+func (h Hook) Fingerprint() string {
+	sum := sha256.Sum256([]byte(h.Event + "\x00" + h.RunCmd + "\x00" + h.CWD))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetHook returns the hook with the given id.
+func (s *Store) GetHook(ctx context.Context, hookID int) (Hook, error) {
+	var (
+		h       Hook
+		enabled int
+	)
+	err := s.db.QueryRowContext(ctx, `SELECT id, event, run_cmd, cwd, enabled, order_index, created_at FROM hooks WHERE id = ?`, hookID).
+		Scan(&h.ID, &h.Event, &h.RunCmd, &h.CWD, &enabled, &h.Order, &h.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Hook{}, fmt.Errorf("hook not found: %d", hookID)
+	}
+	if err != nil {
+		return Hook{}, fmt.Errorf("get hook: %w", err)
+	}
+	h.Enabled = enabled != 0
+	approved, err := loadHookApprovals()
+	if err != nil {
+		return Hook{}, err
+	}
+	h.Approved = approved[h.Fingerprint()]
+	return h, nil
+}
+
+// ApproveHook lets the hook's current command run. Hooks added through
+// AddHook are approved already; this is for hooks that arrived with a
+// database from somewhere else.
+func (s *Store) ApproveHook(ctx context.Context, hookID int) (Hook, error) {
+	h, err := s.GetHook(ctx, hookID)
+	if err != nil {
+		return Hook{}, err
+	}
+	if err := approveHookCommand(h); err != nil {
+		return Hook{}, err
+	}
+	h.Approved = true
+	return h, nil
+}
+
+// hookApprovalsSchema is the first schema version written by builds that
+// check hook approvals.
+const hookApprovalsSchema = 20
+
+// initHookApprovals creates HookApprovalsPath the first time a store is
+// opened in this TRACK_HOME. prev is the schema version the database had
+// before Open migrated it. A database older than hookApprovalsSchema has its
+// hooks approved: they were added before approvals existed, and would
+// otherwise all stop running after an upgrade. A newer database may have
+// been copied here from another machine or a backup, so its hooks start out
+// unapproved, each named in a warning.
+func (s *Store) initHookApprovals(ctx context.Context, prev int) error {
+	path, err := HookApprovalsPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		return err
+	}
+	hooks, err := s.ListHooks(ctx, "")
+	if err != nil {
+		return err
+	}
+	grandfather := prev < hookApprovalsSchema
+	var b strings.Builder
+	b.WriteString("# Hook commands allowed to run; see track hook approve.\n")
+	if grandfather {
+		for _, h := range hooks {
+			fmt.Fprintln(&b, h.Fingerprint())
+		}
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if os.IsExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("create hook approvals: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(b.String()); err != nil {
+		return fmt.Errorf("create hook approvals: %w", err)
+	}
+	if !grandfather {
+		for _, h := range hooks {
+			fmt.Fprintf(os.Stderr, "warning: hook(%d) came with the database and will not run until approved: %s (see track hook approve %d)\n", h.ID, h.RunCmd, h.ID)
+		}
+	}
+	return nil
+}
+
+func loadHookApprovals() (map[string]bool, error) {
+	path, err := HookApprovalsPath()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read hook approvals: %w", err)
+	}
+	approved := map[string]bool{}
+	for _, line := range strings.Split(string(raw), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			approved[line] = true
+		}
+	}
+	return approved, nil
+}
+
+func approveHookCommand(h Hook) error {
+	approved, err := loadHookApprovals()
+	if err != nil || approved[h.Fingerprint()] {
+		return err
+	}
+	path, err := HookApprovalsPath()
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("approve hook: %w", err)
+	}
+	defer f.Close()
+	if _, err := fmt.Fprintln(f, h.Fingerprint()); err != nil {
+		return fmt.Errorf("approve hook: %w", err)
+	}
+	return nil
+}
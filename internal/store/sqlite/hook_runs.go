@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type HookRun struct {
+	ID      int
+	HookID  int
+	Event   string
+	IssueID string
+	Success bool
+	Error   string
+	RanAt   string
+}
+
+func (s *Store) AddHookRun(ctx context.Context, in HookRun) (HookRun, error) {
+	if in.RanAt == "" {
+		in.RanAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	success := 0
+	if in.Success {
+		success = 1
+	}
+	var res sql.Result
+	err := withSQLiteRetry(ctx, func() error {
+		var err error
+		res, err = s.db.ExecContext(ctx, `
+			INSERT INTO hook_runs(hook_id, event, issue_id, success, error, ran_at)
+			VALUES(?, ?, ?, ?, ?, ?)
+		`, in.HookID, in.Event, nullable(in.IssueID), success, nullable(in.Error), in.RanAt)
+		return err
+	})
+	if err != nil {
+		return HookRun{}, fmt.Errorf("insert hook run: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return HookRun{}, fmt.Errorf("read hook run id: %w", err)
+	}
+	in.ID = int(id)
+	return in, nil
+}
+
+// ListHookFailures returns failed hook runs, newest first. A limit of 0
+// returns every failure.
+func (s *Store) ListHookFailures(ctx context.Context, limit int) ([]HookRun, error) {
+	query := `SELECT id, hook_id, event, issue_id, success, error, ran_at FROM hook_runs WHERE success = 0 ORDER BY ran_at DESC, id DESC`
+	args := []any{}
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list hook failures: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]HookRun, 0)
+	for rows.Next() {
+		var (
+			run     HookRun
+			issueID sql.NullString
+			errText sql.NullString
+			success int
+		)
+		if err := rows.Scan(&run.ID, &run.HookID, &run.Event, &issueID, &success, &errText, &run.RanAt); err != nil {
+			return nil, fmt.Errorf("scan hook run: %w", err)
+		}
+		run.IssueID = issueID.String
+		run.Error = errText.String
+		run.Success = success != 0
+		out = append(out, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate hook runs: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,45 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHookRunsListFailuresNewestFirst(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	runs := []HookRun{
+		{HookID: 1, Event: "issue.created", IssueID: "TRK-1", Success: false, Error: "exit 1", RanAt: "2026-01-01T00:00:00Z"},
+		{HookID: 1, Event: "issue.created", IssueID: "TRK-2", Success: true, RanAt: "2026-01-02T00:00:00Z"},
+		{HookID: 2, Event: "sync.completed", Success: false, Error: "exit 2", RanAt: "2026-01-03T00:00:00Z"},
+	}
+	for _, r := range runs {
+		if _, err := store.AddHookRun(ctx, r); err != nil {
+			t.Fatalf("AddHookRun() error: %v", err)
+		}
+	}
+
+	failures, err := store.ListHookFailures(ctx, 0)
+	if err != nil {
+		t.Fatalf("ListHookFailures() error: %v", err)
+	}
+	if len(failures) != 2 || failures[0].HookID != 2 || failures[1].IssueID != "TRK-1" || failures[1].Error != "exit 1" {
+		t.Fatalf("unexpected failures: %+v", failures)
+	}
+
+	limited, err := store.ListHookFailures(ctx, 1)
+	if err != nil {
+		t.Fatalf("ListHookFailures(limit) error: %v", err)
+	}
+	if len(limited) != 1 || limited[0].HookID != 2 {
+		t.Fatalf("unexpected limited failures: %+v", limited)
+	}
+}
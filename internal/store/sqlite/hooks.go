@@ -11,17 +11,22 @@ type Hook struct {
 	Event     string
 	RunCmd    string
 	CWD       string
+	Enabled   bool
+	Order     int
 	CreatedAt string
+	// Approved is set when the hook's Fingerprint is in HookApprovalsPath;
+	// only approved hooks run.
+	Approved bool
 }
 
 func (s *Store) ListHooks(ctx context.Context, event string) ([]Hook, error) {
-	query := `SELECT id, event, run_cmd, cwd, created_at FROM hooks`
+	query := `SELECT id, event, run_cmd, cwd, enabled, order_index, created_at FROM hooks`
 	args := []any{}
 	if event != "" {
 		query += ` WHERE event = ?`
 		args = append(args, event)
 	}
-	query += ` ORDER BY id ASC`
+	query += ` ORDER BY order_index ASC, id ASC`
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -29,12 +34,21 @@ func (s *Store) ListHooks(ctx context.Context, event string) ([]Hook, error) {
 	}
 	defer rows.Close()
 
+	approved, err := loadHookApprovals()
+	if err != nil {
+		return nil, err
+	}
 	hooks := make([]Hook, 0)
 	for rows.Next() {
-		var h Hook
-		if err := rows.Scan(&h.ID, &h.Event, &h.RunCmd, &h.CWD, &h.CreatedAt); err != nil {
+		var (
+			h       Hook
+			enabled int
+		)
+		if err := rows.Scan(&h.ID, &h.Event, &h.RunCmd, &h.CWD, &enabled, &h.Order, &h.CreatedAt); err != nil {
 			return nil, fmt.Errorf("scan hook: %w", err)
 		}
+		h.Enabled = enabled != 0
+		h.Approved = approved[h.Fingerprint()]
 		hooks = append(hooks, h)
 	}
 	if err := rows.Err(); err != nil {
@@ -43,6 +57,8 @@ func (s *Store) ListHooks(ctx context.Context, event string) ([]Hook, error) {
 	return hooks, nil
 }
 
+// AddHook registers a hook and approves its command, since it was added on
+// this machine.
 func (s *Store) AddHook(ctx context.Context, event, runCmd, cwd string) error {
 	_, err := s.db.ExecContext(
 		ctx,
@@ -55,7 +71,7 @@ func (s *Store) AddHook(ctx context.Context, event, runCmd, cwd string) error {
 	if err != nil {
 		return fmt.Errorf("insert hook: %w", err)
 	}
-	return nil
+	return approveHookCommand(Hook{Event: event, RunCmd: runCmd, CWD: cwd})
 }
 
 func (s *Store) RemoveHook(ctx context.Context, hookID int) error {
@@ -72,3 +88,32 @@ func (s *Store) RemoveHook(ctx context.Context, hookID int) error {
 	}
 	return nil
 }
+
+func (s *Store) SetHookEnabled(ctx context.Context, hookID int, enabled bool) error {
+	v := 0
+	if enabled {
+		v = 1
+	}
+	return s.updateHook(ctx, hookID, `UPDATE hooks SET enabled = ? WHERE id = ?`, v)
+}
+
+// SetHookOrder sets the execution order of a hook. Hooks for the same event
+// run in ascending order, ties broken by id.
+func (s *Store) SetHookOrder(ctx context.Context, hookID int, order int) error {
+	return s.updateHook(ctx, hookID, `UPDATE hooks SET order_index = ? WHERE id = ?`, order)
+}
+
+func (s *Store) updateHook(ctx context.Context, hookID int, stmt string, value any) error {
+	res, err := s.db.ExecContext(ctx, stmt, value, hookID)
+	if err != nil {
+		return fmt.Errorf("update hook: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("hook rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("hook not found: %d", hookID)
+	}
+	return nil
+}
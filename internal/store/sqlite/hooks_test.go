@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenMigratesLegacyHooksTable(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	legacy, err := sql.Open(driverName, filepath.Join(tmp, "track.db"))
+	if err != nil {
+		t.Fatalf("open legacy db: %v", err)
+	}
+	if _, err := legacy.ExecContext(ctx, `CREATE TABLE hooks (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		event TEXT NOT NULL,
+		run_cmd TEXT NOT NULL,
+		cwd TEXT,
+		created_at TEXT NOT NULL
+	)`); err != nil {
+		t.Fatalf("create legacy hooks: %v", err)
+	}
+	if _, err := legacy.ExecContext(ctx, `INSERT INTO hooks(event, run_cmd, cwd, created_at) VALUES('issue.created', 'true', '', '2026-01-01T00:00:00Z')`); err != nil {
+		t.Fatalf("insert legacy hook: %v", err)
+	}
+	_ = legacy.Close()
+
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	hooks, err := store.ListHooks(ctx, "")
+	if err != nil {
+		t.Fatalf("ListHooks() error: %v", err)
+	}
+	if len(hooks) != 1 || !hooks[0].Enabled || hooks[0].Order != 0 {
+		t.Fatalf("unexpected migrated hooks: %+v", hooks)
+	}
+}
+
+func TestSetHookEnabledAndOrder(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	for _, cmd := range []string{"first", "second"} {
+		if err := store.AddHook(ctx, "issue.created", cmd, ""); err != nil {
+			t.Fatalf("AddHook(%s) error: %v", cmd, err)
+		}
+	}
+	if err := store.SetHookOrder(ctx, 1, 5); err != nil {
+		t.Fatalf("SetHookOrder() error: %v", err)
+	}
+	if err := store.SetHookEnabled(ctx, 2, false); err != nil {
+		t.Fatalf("SetHookEnabled() error: %v", err)
+	}
+
+	hooks, err := store.ListHooks(ctx, "issue.created")
+	if err != nil {
+		t.Fatalf("ListHooks() error: %v", err)
+	}
+	if len(hooks) != 2 || hooks[0].RunCmd != "second" || hooks[0].Enabled || hooks[1].RunCmd != "first" || hooks[1].Order != 5 {
+		t.Fatalf("unexpected hooks: %+v", hooks)
+	}
+
+	if err := store.SetHookEnabled(ctx, 99, true); err == nil {
+		t.Fatalf("expected not found error")
+	}
+}
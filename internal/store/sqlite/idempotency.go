@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// IdempotencyTTL is how long an idempotency key and the issue it created
+// are remembered.
+const IdempotencyTTL = 24 * time.Hour
+
+// IdempotencyRecord links a client-supplied key to the issue created for it.
+// Fingerprint identifies the request so a reused key with different
+// parameters can be rejected.
+type IdempotencyRecord struct {
+	Key         string
+	Fingerprint string
+	IssueID     string
+	CreatedAt   string
+}
+
+// GetIdempotencyKey returns the record for key. Expired records are purged
+// first, so the second return value is false for keys older than
+// IdempotencyTTL.
+func (s *Store) GetIdempotencyKey(ctx context.Context, key string) (IdempotencyRecord, bool, error) {
+	cutoff := time.Now().UTC().Add(-IdempotencyTTL).Format(time.RFC3339)
+	if err := withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE created_at < ?`, cutoff)
+		return err
+	}); err != nil {
+		return IdempotencyRecord{}, false, fmt.Errorf("purge idempotency keys: %w", err)
+	}
+
+	var rec IdempotencyRecord
+	err := s.db.QueryRowContext(ctx, `SELECT key, fingerprint, issue_id, created_at FROM idempotency_keys WHERE key = ?`, key).
+		Scan(&rec.Key, &rec.Fingerprint, &rec.IssueID, &rec.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return IdempotencyRecord{}, false, nil
+	}
+	if err != nil {
+		return IdempotencyRecord{}, false, fmt.Errorf("get idempotency key: %w", err)
+	}
+	return rec, true, nil
+}
+
+// claimIdempotencyKey records rec in tx unless its key is recorded already,
+// in which case that record is returned and the first return value is false.
+// Expired records are purged first.
+func claimIdempotencyKey(ctx context.Context, tx *sql.Tx, rec IdempotencyRecord) (bool, IdempotencyRecord, error) {
+	cutoff := time.Now().UTC().Add(-IdempotencyTTL).Format(time.RFC3339)
+	if _, err := tx.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE created_at < ?`, cutoff); err != nil {
+		return false, IdempotencyRecord{}, fmt.Errorf("purge idempotency keys: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO idempotency_keys(key, fingerprint, issue_id, created_at)
+		VALUES(?, ?, ?, ?)
+		ON CONFLICT(key) DO NOTHING
+	`, rec.Key, rec.Fingerprint, rec.IssueID, rec.CreatedAt)
+	if err != nil {
+		return false, IdempotencyRecord{}, fmt.Errorf("save idempotency key: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return true, rec, nil
+	}
+	var existing IdempotencyRecord
+	if err := tx.QueryRowContext(ctx, `SELECT key, fingerprint, issue_id, created_at FROM idempotency_keys WHERE key = ?`, rec.Key).
+		Scan(&existing.Key, &existing.Fingerprint, &existing.IssueID, &existing.CreatedAt); err != nil {
+		return false, IdempotencyRecord{}, fmt.Errorf("get idempotency key: %w", err)
+	}
+	return false, existing, nil
+}
+
+func (s *Store) SaveIdempotencyKey(ctx context.Context, rec IdempotencyRecord) error {
+	if rec.CreatedAt == "" {
+		rec.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	return withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `
+			INSERT INTO idempotency_keys(key, fingerprint, issue_id, created_at)
+			VALUES(?, ?, ?, ?)
+			ON CONFLICT(key) DO UPDATE SET fingerprint=excluded.fingerprint, issue_id=excluded.issue_id, created_at=excluded.created_at
+		`, rec.Key, rec.Fingerprint, rec.IssueID, rec.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("save idempotency key: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyKeysExpire(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	if _, ok, err := store.GetIdempotencyKey(ctx, "k1"); err != nil || ok {
+		t.Fatalf("GetIdempotencyKey() before save = ok %v, err %v", ok, err)
+	}
+	if err := store.SaveIdempotencyKey(ctx, IdempotencyRecord{Key: "k1", Fingerprint: "f", IssueID: "TRK-1"}); err != nil {
+		t.Fatalf("SaveIdempotencyKey() error: %v", err)
+	}
+	got, ok, err := store.GetIdempotencyKey(ctx, "k1")
+	if err != nil || !ok || got.IssueID != "TRK-1" || got.Fingerprint != "f" {
+		t.Fatalf("GetIdempotencyKey() = %+v, ok %v, err %v", got, ok, err)
+	}
+
+	old := time.Now().UTC().Add(-IdempotencyTTL - time.Minute).Format(time.RFC3339)
+	if err := store.SaveIdempotencyKey(ctx, IdempotencyRecord{Key: "k2", Fingerprint: "f", IssueID: "TRK-2", CreatedAt: old}); err != nil {
+		t.Fatalf("SaveIdempotencyKey() error: %v", err)
+	}
+	if _, ok, err := store.GetIdempotencyKey(ctx, "k2"); err != nil || ok {
+		t.Fatalf("expired key should be gone: ok %v, err %v", ok, err)
+	}
+}
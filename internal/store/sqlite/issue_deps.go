@@ -0,0 +1,176 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+// AddIssueDependency records that id is blocked until dependsOn is merged,
+// which dispatch marks by moving dependsOn to done. Dependencies that would
+// form a cycle are rejected.
+func (s *Store) AddIssueDependency(ctx context.Context, id, dependsOn string) error {
+	id, dependsOn = strings.TrimSpace(id), strings.TrimSpace(dependsOn)
+	if id == dependsOn {
+		return fmt.Errorf("issue cannot depend on itself: %s", id)
+	}
+	for _, i := range []string{id, dependsOn} {
+		if _, err := s.GetIssue(ctx, i); err != nil {
+			return err
+		}
+	}
+	deps, err := s.ListIssueDependencies(ctx)
+	if err != nil {
+		return err
+	}
+	if dependsOnTransitively(deps, dependsOn, id) {
+		return fmt.Errorf("dependency cycle: %s already depends on %s", dependsOn, id)
+	}
+	err = withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `
+			INSERT INTO issue_dependencies(issue_id, depends_on, created_at)
+			VALUES(?, ?, ?)
+			ON CONFLICT(issue_id, depends_on) DO NOTHING
+		`, id, dependsOn, time.Now().UTC().Format(time.RFC3339))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("add issue dependency: %w", err)
+	}
+	return s.RecordActivity(ctx, ActivityUpdated, id, "depends_on")
+}
+
+func (s *Store) RemoveIssueDependency(ctx context.Context, id, dependsOn string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM issue_dependencies WHERE issue_id = ? AND depends_on = ?`, strings.TrimSpace(id), strings.TrimSpace(dependsOn))
+	if err != nil {
+		return fmt.Errorf("remove issue dependency: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("issue dependency not found: %s -> %s", id, dependsOn)
+	}
+	return s.RecordActivity(ctx, ActivityUpdated, id, "depends_on")
+}
+
+// ListIssueDependencies maps each issue ID to the issues it depends on.
+func (s *Store) ListIssueDependencies(ctx context.Context) (map[string][]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT issue_id, depends_on FROM issue_dependencies ORDER BY issue_id, depends_on`)
+	if err != nil {
+		return nil, fmt.Errorf("list issue dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	out := map[string][]string{}
+	for rows.Next() {
+		var id, dep string
+		if err := rows.Scan(&id, &dep); err != nil {
+			return nil, fmt.Errorf("scan issue dependency: %w", err)
+		}
+		out[id] = append(out[id], dep)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate issue dependencies: %w", err)
+	}
+	return out, nil
+}
+
+// ListDependencyClosure is ListIssueDependencies limited to id and the
+// issues that depend on it, directly or through other issues, so looking at
+// one issue reads only its part of the graph.
+func (s *Store) ListDependencyClosure(ctx context.Context, id string) (map[string][]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		WITH RECURSIVE dependents(id) AS (
+			SELECT ?
+			UNION
+			SELECT d.issue_id FROM issue_dependencies d JOIN dependents ON d.depends_on = dependents.id
+		)
+		SELECT issue_id, depends_on FROM issue_dependencies
+		WHERE issue_id IN (SELECT id FROM dependents)
+		ORDER BY issue_id, depends_on
+	`, strings.TrimSpace(id))
+	if err != nil {
+		return nil, fmt.Errorf("list issue dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	out := map[string][]string{}
+	for rows.Next() {
+		var id, dep string
+		if err := rows.Scan(&id, &dep); err != nil {
+			return nil, fmt.Errorf("scan issue dependency: %w", err)
+		}
+		out[id] = append(out[id], dep)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate issue dependencies: %w", err)
+	}
+	return out, nil
+}
+
+// BlockedBy maps each issue that depends on an issue not resolved yet to
+// those blockers. Done and archived blockers count as resolved.
+func (s *Store) BlockedBy(ctx context.Context) (map[string][]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT d.issue_id, d.depends_on
+		FROM issue_dependencies d
+		JOIN issues i ON i.id = d.depends_on
+		WHERE i.status NOT IN (?, ?)
+		ORDER BY d.issue_id, d.depends_on
+	`, issue.StatusDone, issue.StatusArchived)
+	if err != nil {
+		return nil, fmt.Errorf("list blockers: %w", err)
+	}
+	defer rows.Close()
+
+	out := map[string][]string{}
+	for rows.Next() {
+		var id, dep string
+		if err := rows.Scan(&id, &dep); err != nil {
+			return nil, fmt.Errorf("scan blocker: %w", err)
+		}
+		out[id] = append(out[id], dep)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate blockers: %w", err)
+	}
+	return out, nil
+}
+
+// UnmergedBlockers returns the issues id depends on that are neither done
+// nor archived.
+func (s *Store) UnmergedBlockers(ctx context.Context, id string) ([]issue.Item, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT d.depends_on
+		FROM issue_dependencies d
+		JOIN issues i ON i.id = d.depends_on
+		WHERE d.issue_id = ? AND i.status NOT IN (?, ?)
+		ORDER BY d.depends_on
+	`, id, issue.StatusDone, issue.StatusArchived)
+	if err != nil {
+		return nil, fmt.Errorf("list blockers: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var dep string
+		if err := rows.Scan(&dep); err != nil {
+			return nil, fmt.Errorf("scan blocker: %w", err)
+		}
+		ids = append(ids, dep)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate blockers: %w", err)
+	}
+	out := make([]issue.Item, 0, len(ids))
+	for _, dep := range ids {
+		it, err := s.GetIssue(ctx, dep)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, it)
+	}
+	return out, nil
+}
@@ -9,28 +9,75 @@ import (
 	"strings"
 	"time"
 
+	appconfig "github.com/myuon/track/internal/config"
 	"github.com/myuon/track/internal/issue"
 )
 
 type ListFilter struct {
+	// IDs, when set, matches only these issues.
+	IDs             []string
 	Statuses        []string
+	Priorities      []string
 	ExcludeDone     bool
 	ExcludeArchived bool
-	Label           string
-	Assignee        string
-	Search          string
-	Project         string
-	Sort            string
+	ExcludeStatuses []string
+	// Label is a label expression; see issue.ParseLabelExpr.
+	Label    string
+	Assignee string
+	Search   string
+	Project  string
+	// DueFrom and DueTo are inclusive YYYY-MM-DD bounds compared against the
+	// due value's local date; issues without a due date never match them. NoDue matches only those issues.
+	DueFrom string
+	DueTo   string
+	NoDue   bool
+	// NoProject matches only issues that belong to no project.
+	NoProject bool
+	// Unblocked leaves out issues that depend on an issue neither done nor
+	// archived.
+	Unblocked bool
+	Sort      string
 }
 
+// labelExprSQL turns a label expression into a WHERE clause that matches
+// exact labels through json_each over labels_json.
+func labelExprSQL(e issue.LabelExpr) (string, []any) {
+	switch e := e.(type) {
+	case issue.LabelNot:
+		clause, args := labelExprSQL(e.X)
+		return `NOT ` + clause, args
+	case issue.LabelAnd:
+		x, xArgs := labelExprSQL(e.X)
+		y, yArgs := labelExprSQL(e.Y)
+		return `(` + x + ` AND ` + y + `)`, append(xArgs, yArgs...)
+	case issue.LabelOr:
+		x, xArgs := labelExprSQL(e.X)
+		y, yArgs := labelExprSQL(e.Y)
+		return `(` + x + ` OR ` + y + `)`, append(xArgs, yArgs...)
+	case issue.LabelTerm:
+		return `EXISTS (SELECT 1 FROM json_each(issues.labels_json) WHERE json_each.value = ?)`, []any{e.Label}
+	}
+	return `1=1`, nil
+}
+
+// UpdateIssueInput lists the fields to change; nil fields are left as they
+// are. AppendBody and PrependBody add a line to the body as stored at write
+// time, so concurrent appends are all kept; they cannot be combined with Body.
 type UpdateIssueInput struct {
-	Title      *string
-	Body       *string
-	Status     *string
-	Priority   *string
-	Due        *string
-	Assignee   *string
-	NextAction *string
+	Title       *string
+	Body        *string
+	AppendBody  *string
+	PrependBody *string
+	Status      *string
+	Priority    *string
+	Due         *string
+	Assignee    *string
+	NextAction  *string
+	Estimate    *string
+	ExternalID  *string
+	// BlockedReason says why the issue is blocked; it is only accepted when
+	// the issue is or becomes issue.StatusBlocked.
+	BlockedReason *string
 }
 
 func (s *Store) CreateIssue(ctx context.Context, item issue.Item) (issue.Item, error) {
@@ -40,9 +87,15 @@ func (s *Store) CreateIssue(ctx context.Context, item issue.Item) (issue.Item, e
 	if err := issue.ValidatePriority(item.Priority); err != nil {
 		return issue.Item{}, err
 	}
-	if err := issue.ValidateDue(item.Due); err != nil {
+	due, err := normalizeDue(item.Due)
+	if err != nil {
+		return issue.Item{}, err
+	}
+	item.Due = due
+	if err := s.validateAssignee(ctx, issue.NormalizeAssignee(item.Assignee)); err != nil {
 		return issue.Item{}, err
 	}
+	item.ExternalID = strings.TrimSpace(item.ExternalID)
 
 	id, err := s.NextIssueID(ctx)
 	if err != nil {
@@ -57,28 +110,53 @@ func (s *Store) CreateIssue(ctx context.Context, item issue.Item) (issue.Item, e
 	if err != nil {
 		return issue.Item{}, fmt.Errorf("marshal labels: %w", err)
 	}
-	nextOrder, err := s.nextOrderIndex(ctx)
+	// The new issue goes last in the manual order; reading the position in
+	// the INSERT itself keeps concurrent creates from sharing it.
+	insert, err := s.prepared(ctx, `INSERT INTO issues(
+			id, title, status, priority, assignee, due, labels_json, next_action, body, external_id, order_index, created_at, updated_at
+		) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, (SELECT COALESCE(MAX(order_index), 0) + 1 FROM issues), ?, ?)`)
 	if err != nil {
 		return issue.Item{}, err
 	}
-
-	_, err = s.db.ExecContext(
+	_, err = insert.ExecContext(
 		ctx,
-		`INSERT INTO issues(
-			id, title, status, priority, assignee, due, labels_json, next_action, body, order_index, created_at, updated_at
-		) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		item.ID, item.Title, item.Status, item.Priority, nullable(item.Assignee), nullable(item.Due), string(labelsJSON), nullable(item.NextAction), nullable(item.Body), nextOrder, item.CreatedAt, item.UpdatedAt,
+		item.ID, item.Title, item.Status, item.Priority, nullable(item.Assignee), nullable(item.Due), string(labelsJSON), nullable(item.NextAction), nullable(item.Body), nullable(item.ExternalID), item.CreatedAt, item.UpdatedAt,
 	)
 	if err != nil {
-		return issue.Item{}, fmt.Errorf("insert issue: %w", err)
+		return issue.Item{}, externalIDError(err, item.ExternalID, "insert issue")
+	}
+	if err := s.RecordActivity(ctx, ActivityCreated, item.ID, item.Title); err != nil {
+		return item, err
+	}
+	if err := s.snapshotBody(ctx, item.ID, "", item.Body); err != nil {
+		return item, err
+	}
+	if err := s.setBodyMentions(ctx, item.ID, item.Body); err != nil {
+		return item, err
 	}
 
-	return item, nil
+	return item, s.publish(ctx, item.ID, EventIssueCreated)
 }
 
 func (s *Store) GetIssue(ctx context.Context, id string) (issue.Item, error) {
-	row := s.db.QueryRowContext(ctx, `SELECT id, title, status, priority, assignee, due, labels_json, next_action, body, created_at, updated_at FROM issues WHERE id = ?`, id)
-	return scanIssueRow(row)
+	stmt, err := s.prepared(ctx, `SELECT id, title, status, priority, assignee, due, labels_json, next_action, body, estimate, pinned, external_id, created_at, updated_at FROM issues WHERE id = ?`)
+	if err != nil {
+		return issue.Item{}, err
+	}
+	return scanIssueRow(stmt.QueryRowContext(ctx, id))
+}
+
+// GetIssueByExternalID returns the issue imported with the given external ID.
+func (s *Store) GetIssueByExternalID(ctx context.Context, externalID string) (issue.Item, error) {
+	var id string
+	err := s.db.QueryRowContext(ctx, `SELECT id FROM issues WHERE external_id = ?`, strings.TrimSpace(externalID)).Scan(&id)
+	if err == sql.ErrNoRows {
+		return issue.Item{}, fmt.Errorf("no issue with external id: %s", externalID)
+	}
+	if err != nil {
+		return issue.Item{}, fmt.Errorf("get issue by external id: %w", err)
+	}
+	return s.GetIssue(ctx, id)
 }
 
 func (s *Store) UpdateIssue(ctx context.Context, id string, in UpdateIssueInput) (issue.Item, error) {
@@ -86,13 +164,26 @@ func (s *Store) UpdateIssue(ctx context.Context, id string, in UpdateIssueInput)
 	if err != nil {
 		return issue.Item{}, err
 	}
+	from := current.Status
+	before := current
 
 	if in.Title != nil {
 		current.Title = *in.Title
 	}
 	if in.Body != nil {
+		if in.AppendBody != nil || in.PrependBody != nil {
+			return issue.Item{}, fmt.Errorf("body cannot be replaced and extended at once")
+		}
 		current.Body = *in.Body
 	}
+	var prefix, suffix string
+	if in.PrependBody != nil {
+		prefix = *in.PrependBody + "\n"
+	}
+	if in.AppendBody != nil {
+		suffix = "\n" + *in.AppendBody
+	}
+	extendBody := prefix != "" || suffix != ""
 	if in.Status != nil {
 		if err := s.ValidateStatus(ctx, *in.Status); err != nil {
 			return issue.Item{}, err
@@ -106,36 +197,150 @@ func (s *Store) UpdateIssue(ctx context.Context, id string, in UpdateIssueInput)
 		current.Priority = *in.Priority
 	}
 	if in.Due != nil {
-		if err := issue.ValidateDue(*in.Due); err != nil {
+		due, err := normalizeDue(*in.Due)
+		if err != nil {
 			return issue.Item{}, err
 		}
-		current.Due = *in.Due
+		current.Due = due
 	}
 	if in.Assignee != nil {
-		current.Assignee = issue.NormalizeAssignee(*in.Assignee)
+		if assignee := issue.NormalizeAssignee(*in.Assignee); assignee != current.Assignee {
+			if err := s.validateAssignee(ctx, assignee); err != nil {
+				return issue.Item{}, err
+			}
+			current.Assignee = assignee
+		}
 	}
 	if in.NextAction != nil {
 		current.NextAction = strings.TrimSpace(*in.NextAction)
 	}
+	if in.Estimate != nil {
+		estimate, err := issue.NormalizeEstimate(*in.Estimate)
+		if err != nil {
+			return issue.Item{}, err
+		}
+		current.Estimate = estimate
+	}
+	if in.ExternalID != nil {
+		current.ExternalID = strings.TrimSpace(*in.ExternalID)
+	}
+	var reason *string
+	if in.BlockedReason != nil {
+		if current.Status != issue.StatusBlocked {
+			return issue.Item{}, fmt.Errorf("a blocked reason needs status %s", issue.StatusBlocked)
+		}
+		r := strings.TrimSpace(*in.BlockedReason)
+		reason = &r
+	}
 
 	current.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
 
-	_, err = s.db.ExecContext(
+	// Extending the body builds on the stored column rather than the copy
+	// read above; the returned body is what was actually written.
+	// An empty body gets just the new lines, without the separators.
+	alone := strings.TrimSuffix(strings.TrimPrefix(prefix+suffix, "\n"), "\n")
+	bodyExpr, bodyArgs := `?`, []any{nullable(current.Body)}
+	if extendBody {
+		bodyExpr = `CASE WHEN COALESCE(body, '') = '' THEN ? ELSE ? || body || ? END`
+		bodyArgs = []any{alone, prefix, suffix}
+	}
+	args := []any{current.Title, current.Status, current.Priority, nullable(current.Assignee), nullable(current.Due), nullable(current.NextAction)}
+	args = append(append(args, bodyArgs...), current.Estimate, nullable(current.ExternalID), current.UpdatedAt, id)
+	var written string
+	err = s.db.QueryRowContext(
 		ctx,
-		`UPDATE issues SET title=?, status=?, priority=?, assignee=?, due=?, next_action=?, body=?, updated_at=? WHERE id=?`,
-		current.Title, current.Status, current.Priority, nullable(current.Assignee), nullable(current.Due), nullable(current.NextAction), nullable(current.Body), current.UpdatedAt, id,
-	)
+		`UPDATE issues SET title=?, status=?, priority=?, assignee=?, due=?, next_action=?, body=`+bodyExpr+`, estimate=?, external_id=?, updated_at=? WHERE id=? RETURNING COALESCE(body, '')`,
+		args...,
+	).Scan(&written)
 	if err != nil {
-		return issue.Item{}, fmt.Errorf("update issue: %w", err)
+		return issue.Item{}, externalIDError(err, current.ExternalID, "update issue")
+	}
+	if extendBody {
+		current.Body, before.Body = written, ""
+		if written != alone {
+			before.Body = strings.TrimPrefix(strings.TrimSuffix(written, suffix), prefix)
+		}
+	}
+	if changed := changedFields(before, current); len(changed) > 0 {
+		if err := s.RecordActivity(ctx, ActivityUpdated, current.ID, strings.Join(changed, ", ")); err != nil {
+			return current, err
+		}
+	}
+	if err := s.recordBodyChange(ctx, current.ID, before.Body, current.Body); err != nil {
+		return current, err
+	}
+	if from != current.Status {
+		if err := s.RecordActivity(ctx, ActivityStatus, current.ID, from+" -> "+current.Status); err != nil {
+			return current, err
+		}
+	}
+	if err := s.recordBlock(ctx, current.ID, from, current.Status, reason, current.UpdatedAt); err != nil {
+		return current, err
 	}
 
-	return current, nil
+	return current, s.publish(ctx, current.ID, statusEvents(from, current.Status)...)
 }
 
 func (s *Store) ListIssues(ctx context.Context, f ListFilter) ([]issue.Item, error) {
-	base := `SELECT id, title, status, priority, assignee, due, labels_json, next_action, body, created_at, updated_at FROM issues WHERE 1=1`
+	where, args, err := listWhere(f)
+	if err != nil {
+		return nil, err
+	}
+	base := `SELECT id, title, status, priority, assignee, due, labels_json, next_action, body, estimate, pinned, external_id, created_at, updated_at FROM issues` + where
+
+	orderBy, err := orderByClause(f.Sort)
+	if err != nil {
+		return nil, err
+	}
+	base += orderBy
+
+	stmt, err := s.prepared(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list issues: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]issue.Item, 0)
+	for rows.Next() {
+		item, err := scanIssueRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate issues: %w", err)
+	}
+	return items, nil
+}
+
+// CountIssues returns how many issues match f. Sort is ignored. Counts are
+// cached per filter until the database changes.
+func (s *Store) CountIssues(ctx context.Context, f ListFilter) (int, error) {
+	where, args, err := listWhere(f)
+	if err != nil {
+		return 0, err
+	}
+	return s.cachedCount(ctx, where, args)
+}
+
+// listWhere builds the WHERE clause shared by ListIssues and CountIssues.
+func listWhere(f ListFilter) (string, []any, error) {
+	base := ` WHERE 1=1`
 	args := make([]any, 0, 4)
 
+	if f.IDs != nil {
+		ids, err := json.Marshal(f.IDs)
+		if err != nil {
+			return "", nil, fmt.Errorf("encode issue ids: %w", err)
+		}
+		base += ` AND id IN (SELECT value FROM json_each(?))`
+		args = append(args, string(ids))
+	}
 	if len(f.Statuses) > 0 {
 		base += ` AND status IN (`
 		for i, st := range f.Statuses {
@@ -156,6 +361,21 @@ func (s *Store) ListIssues(ctx context.Context, f ListFilter) ([]issue.Item, err
 			args = append(args, issue.StatusArchived)
 		}
 	}
+	for _, st := range f.ExcludeStatuses {
+		base += ` AND status <> ?`
+		args = append(args, st)
+	}
+	if len(f.Priorities) > 0 {
+		base += ` AND priority IN (`
+		for i, p := range f.Priorities {
+			if i > 0 {
+				base += `, `
+			}
+			base += `?`
+			args = append(args, p)
+		}
+		base += `)`
+	}
 	if f.Assignee != "" {
 		base += ` AND assignee = ?`
 		args = append(args, f.Assignee)
@@ -166,46 +386,37 @@ func (s *Store) ListIssues(ctx context.Context, f ListFilter) ([]issue.Item, err
 		args = append(args, needle, needle)
 	}
 	if f.Label != "" {
-		base += ` AND labels_json LIKE ?`
-		args = append(args, "%\""+f.Label+"\"%")
+		expr, err := issue.ParseLabelExpr(f.Label)
+		if err != nil {
+			return "", nil, err
+		}
+		clause, exprArgs := labelExprSQL(expr)
+		base += ` AND ` + clause
+		args = append(args, exprArgs...)
+	}
+	if f.DueFrom != "" {
+		base += ` AND due IS NOT NULL AND due <> '' AND substr(due, 1, 10) >= ?`
+		args = append(args, f.DueFrom)
+	}
+	if f.DueTo != "" {
+		base += ` AND due IS NOT NULL AND due <> '' AND substr(due, 1, 10) <= ?`
+		args = append(args, f.DueTo)
+	}
+	if f.NoDue {
+		base += ` AND (due IS NULL OR due = '')`
 	}
 	if f.Project != "" {
 		base += ` AND EXISTS (SELECT 1 FROM project_issue_links pil WHERE pil.issue_id = issues.id AND pil.project_key = ?)`
 		args = append(args, f.Project)
 	}
-
-	sort := strings.ToLower(f.Sort)
-	switch sort {
-	case "priority":
-		base += ` ORDER BY CASE priority WHEN 'p0' THEN 0 WHEN 'p1' THEN 1 WHEN 'p2' THEN 2 WHEN 'p3' THEN 3 WHEN 'none' THEN 4 ELSE 5 END, updated_at DESC`
-	case "priority_manual":
-		base += ` ORDER BY CASE priority WHEN 'p0' THEN 0 WHEN 'p1' THEN 1 WHEN 'p2' THEN 2 WHEN 'p3' THEN 3 WHEN 'none' THEN 4 ELSE 5 END, order_index ASC, updated_at DESC`
-	case "due":
-		base += ` ORDER BY CASE WHEN due IS NULL OR due = '' THEN 1 ELSE 0 END, due ASC, updated_at DESC`
-	case "manual":
-		base += ` ORDER BY order_index ASC, updated_at DESC`
-	default:
-		base += ` ORDER BY updated_at DESC`
-	}
-
-	rows, err := s.db.QueryContext(ctx, base, args...)
-	if err != nil {
-		return nil, fmt.Errorf("list issues: %w", err)
-	}
-	defer rows.Close()
-
-	items := make([]issue.Item, 0)
-	for rows.Next() {
-		item, err := scanIssueRows(rows)
-		if err != nil {
-			return nil, err
-		}
-		items = append(items, item)
+	if f.NoProject {
+		base += ` AND NOT EXISTS (SELECT 1 FROM project_issue_links pil WHERE pil.issue_id = issues.id)`
 	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate issues: %w", err)
+	if f.Unblocked {
+		base += ` AND NOT EXISTS (SELECT 1 FROM issue_dependencies d JOIN issues b ON b.id = d.depends_on WHERE d.issue_id = issues.id AND b.status NOT IN (?, ?))`
+		args = append(args, issue.StatusDone, issue.StatusArchived)
 	}
-	return items, nil
+	return base, args, nil
 }
 
 func (s *Store) AddLabel(ctx context.Context, id, label string) (issue.Item, error) {
@@ -237,6 +448,27 @@ func (s *Store) RemoveLabel(ctx context.Context, id, label string) (issue.Item,
 	return s.updateLabels(ctx, it)
 }
 
+// SetLabels replaces an issue's labels. Labels are trimmed and de-duplicated
+// in order; empty labels are rejected like AddLabel.
+func (s *Store) SetLabels(ctx context.Context, id string, labels []string) (issue.Item, error) {
+	next := make([]string, 0, len(labels))
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" {
+			return issue.Item{}, fmt.Errorf("label must not be empty")
+		}
+		if !slices.Contains(next, label) {
+			next = append(next, label)
+		}
+	}
+	it, err := s.GetIssue(ctx, id)
+	if err != nil {
+		return issue.Item{}, err
+	}
+	it.Labels = next
+	return s.updateLabels(ctx, it)
+}
+
 func (s *Store) SetNextAction(ctx context.Context, id, text string) (issue.Item, error) {
 	it, err := s.GetIssue(ctx, id)
 	if err != nil {
@@ -248,7 +480,49 @@ func (s *Store) SetNextAction(ctx context.Context, id, text string) (issue.Item,
 	if err != nil {
 		return issue.Item{}, fmt.Errorf("set next_action: %w", err)
 	}
-	return it, nil
+	if err := s.RecordActivity(ctx, ActivityUpdated, it.ID, "next_action"); err != nil {
+		return it, err
+	}
+	return it, s.publish(ctx, it.ID, EventIssueUpdated)
+}
+
+// NextIssue returns the top actionable issue: todo or ready and not waiting
+// on an issue that is not done, ordered by priority and then manual order.
+// The second return value is false when nothing is actionable.
+func (s *Store) NextIssue(ctx context.Context) (issue.Item, bool, error) {
+	items, err := s.ListIssues(ctx, ListFilter{
+		Statuses:  []string{issue.StatusTodo, issue.StatusReady},
+		Unblocked: true,
+		Sort:      "priority_manual",
+	})
+	if err != nil {
+		return issue.Item{}, false, err
+	}
+	if len(items) == 0 {
+		return issue.Item{}, false, nil
+	}
+	return items[0], true, nil
+}
+
+// SetPinned pins or unpins an issue. Pinned issues sort above all others
+// whatever the sort, keeping their own order among themselves.
+func (s *Store) SetPinned(ctx context.Context, id string, pinned bool) (issue.Item, error) {
+	it, err := s.GetIssue(ctx, id)
+	if err != nil {
+		return issue.Item{}, err
+	}
+	if it.Pinned == pinned {
+		return it, nil
+	}
+	it.Pinned = pinned
+	it.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	if _, err := s.db.ExecContext(ctx, `UPDATE issues SET pinned=?, updated_at=? WHERE id=?`, pinned, it.UpdatedAt, id); err != nil {
+		return issue.Item{}, fmt.Errorf("set pinned: %w", err)
+	}
+	if err := s.RecordActivity(ctx, ActivityUpdated, it.ID, "pinned"); err != nil {
+		return it, err
+	}
+	return it, s.publish(ctx, it.ID, EventIssueUpdated)
 }
 
 func (s *Store) Reorder(ctx context.Context, id, beforeID, afterID string) error {
@@ -286,6 +560,56 @@ func (s *Store) Reorder(ctx context.Context, id, beforeID, afterID string) error
 	}
 	ids = slices.Insert(ids, dst, id)
 
+	if err := s.writeManualOrder(ctx, ids); err != nil {
+		return err
+	}
+	if err := s.RecordActivity(ctx, ActivityUpdated, id, "order"); err != nil {
+		return err
+	}
+	return s.publish(ctx, id, EventIssueUpdated)
+}
+
+// RankIssues reorders the given issues among themselves in the manual
+// queue: they keep the positions they occupy between them, filled in the
+// given order, and every other issue stays where it is.
+func (s *Store) RankIssues(ctx context.Context, ranked []string) error {
+	items, err := s.ListIssues(ctx, ListFilter{Sort: "manual"})
+	if err != nil {
+		return err
+	}
+	ids := make([]string, 0, len(items))
+	for _, it := range items {
+		ids = append(ids, it.ID)
+	}
+	var slots []int
+	for _, id := range ranked {
+		idx := slices.Index(ids, id)
+		if idx == -1 {
+			return fmt.Errorf("issue not found: %s", id)
+		}
+		slots = append(slots, idx)
+	}
+	slices.Sort(slots)
+	for i, slot := range slots {
+		ids[slot] = ranked[i]
+	}
+
+	if err := s.writeManualOrder(ctx, ids); err != nil {
+		return err
+	}
+	for _, id := range ranked {
+		if err := s.RecordActivity(ctx, ActivityUpdated, id, "order"); err != nil {
+			return err
+		}
+		if err := s.publish(ctx, id, EventIssueUpdated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeManualOrder numbers ids from 1 in the manual queue.
+func (s *Store) writeManualOrder(ctx context.Context, ids []string) error {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
@@ -312,18 +636,10 @@ func (s *Store) updateLabels(ctx context.Context, it issue.Item) (issue.Item, er
 	if err != nil {
 		return issue.Item{}, fmt.Errorf("update labels: %w", err)
 	}
-	return it, nil
-}
-
-func (s *Store) nextOrderIndex(ctx context.Context) (int, error) {
-	var max sql.NullInt64
-	if err := s.db.QueryRowContext(ctx, `SELECT MAX(order_index) FROM issues`).Scan(&max); err != nil {
-		return 0, fmt.Errorf("read max order index: %w", err)
-	}
-	if !max.Valid {
-		return 1, nil
+	if err := s.RecordActivity(ctx, ActivityUpdated, it.ID, "labels"); err != nil {
+		return it, err
 	}
-	return int(max.Int64) + 1, nil
+	return it, s.publish(ctx, it.ID, EventIssueUpdated)
 }
 
 func scanIssueRow(row *sql.Row) (issue.Item, error) {
@@ -333,6 +649,7 @@ func scanIssueRow(row *sql.Row) (issue.Item, error) {
 		due       sql.NullString
 		nextAct   sql.NullString
 		body      sql.NullString
+		extID     sql.NullString
 		labelsRaw string
 	)
 	if err := row.Scan(
@@ -345,6 +662,9 @@ func scanIssueRow(row *sql.Row) (issue.Item, error) {
 		&labelsRaw,
 		&nextAct,
 		&body,
+		&item.Estimate,
+		&item.Pinned,
+		&extID,
 		&item.CreatedAt,
 		&item.UpdatedAt,
 	); err != nil {
@@ -365,6 +685,7 @@ func scanIssueRow(row *sql.Row) (issue.Item, error) {
 	if body.Valid {
 		item.Body = body.String
 	}
+	item.ExternalID = extID.String
 	if err := json.Unmarshal([]byte(labelsRaw), &item.Labels); err != nil {
 		return issue.Item{}, fmt.Errorf("decode labels: %w", err)
 	}
@@ -378,6 +699,7 @@ func scanIssueRows(rows *sql.Rows) (issue.Item, error) {
 		due       sql.NullString
 		nextAct   sql.NullString
 		body      sql.NullString
+		extID     sql.NullString
 		labelsRaw string
 	)
 	if err := rows.Scan(
@@ -390,6 +712,9 @@ func scanIssueRows(rows *sql.Rows) (issue.Item, error) {
 		&labelsRaw,
 		&nextAct,
 		&body,
+		&item.Estimate,
+		&item.Pinned,
+		&extID,
 		&item.CreatedAt,
 		&item.UpdatedAt,
 	); err != nil {
@@ -407,15 +732,63 @@ func scanIssueRows(rows *sql.Rows) (issue.Item, error) {
 	if body.Valid {
 		item.Body = body.String
 	}
+	item.ExternalID = extID.String
 	if err := json.Unmarshal([]byte(labelsRaw), &item.Labels); err != nil {
 		return issue.Item{}, fmt.Errorf("decode labels: %w", err)
 	}
 	return item, nil
 }
 
+// changedFields lists the fields other than status that differ between two
+// versions of an issue.
+func changedFields(before, after issue.Item) []string {
+	var out []string
+	for _, f := range []struct {
+		name      string
+		old, next string
+	}{
+		{"title", before.Title, after.Title},
+		{"body", before.Body, after.Body},
+		{"priority", before.Priority, after.Priority},
+		{"assignee", before.Assignee, after.Assignee},
+		{"due", before.Due, after.Due},
+		{"next_action", before.NextAction, after.NextAction},
+		{"estimate", before.Estimate, after.Estimate},
+		{"external_id", before.ExternalID, after.ExternalID},
+	} {
+		if f.old != f.next {
+			out = append(out, f.name)
+		}
+	}
+	return out
+}
+
+// externalIDError reports a clash on the unique external ID index plainly
+// and wraps anything else with op.
+func externalIDError(err error, externalID, op string) error {
+	if externalID != "" && strings.Contains(err.Error(), "issues.external_id") {
+		return fmt.Errorf("external id already used: %s", externalID)
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
 func nullable(v string) any {
 	if strings.TrimSpace(v) == "" {
 		return nil
 	}
 	return v
 }
+
+// normalizeDue validates a due value, reading times without an offset in
+// the configured timezone.
+func normalizeDue(v string) (string, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return "", nil
+	}
+	loc, err := appconfig.LoadLocation()
+	if err != nil {
+		return "", err
+	}
+	return issue.NormalizeDue(v, loc)
+}
@@ -2,8 +2,11 @@ package sqlite
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"testing"
 
+	appconfig "github.com/myuon/track/internal/config"
 	"github.com/myuon/track/internal/issue"
 )
 
@@ -182,6 +185,59 @@ func TestListSortByUpdated(t *testing.T) {
 	}
 }
 
+func TestListSortByMultipleKeysAndDirections(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	for _, it := range []struct{ priority, updated string }{
+		{"p1", "2026-01-01T00:00:00Z"},
+		{"p0", "2026-01-02T00:00:00Z"},
+		{"p1", "2026-01-03T00:00:00Z"},
+		{"p0", "2026-01-04T00:00:00Z"},
+	} {
+		created, err := store.CreateIssue(ctx, issue.Item{Title: it.priority, Status: issue.StatusTodo, Priority: it.priority})
+		if err != nil {
+			t.Fatalf("CreateIssue() error: %v", err)
+		}
+		if _, err := store.db.ExecContext(ctx, `UPDATE issues SET updated_at=? WHERE id=?`, it.updated, created.ID); err != nil {
+			t.Fatalf("set updated_at error: %v", err)
+		}
+	}
+
+	cases := map[string]string{
+		"priority,-updated": "TRK-4,TRK-2,TRK-3,TRK-1",
+		"priority,+updated": "TRK-2,TRK-4,TRK-1,TRK-3",
+		"-priority,updated": "TRK-3,TRK-1,TRK-4,TRK-2",
+		"-id":               "TRK-4,TRK-3,TRK-2,TRK-1",
+		"created":           "TRK-4,TRK-3,TRK-2,TRK-1",
+	}
+	for sort, want := range cases {
+		items, err := store.ListIssues(ctx, ListFilter{Sort: sort})
+		if err != nil {
+			t.Fatalf("ListIssues(%q) error: %v", sort, err)
+		}
+		got := make([]string, 0, len(items))
+		for _, it := range items {
+			got = append(got, it.ID)
+		}
+		if strings.Join(got, ",") != want {
+			t.Fatalf("ListIssues(%q) = %v, want %s", sort, got, want)
+		}
+	}
+
+	for _, bad := range []string{"bogus", "priority,", "--due", "updated_at"} {
+		if _, err := store.ListIssues(ctx, ListFilter{Sort: bad}); err == nil || !strings.Contains(err.Error(), "invalid sort key") {
+			t.Fatalf("ListIssues(%q) error = %v, want invalid sort key", bad, err)
+		}
+	}
+}
+
 func TestListIssuesExcludeDoneAndArchived(t *testing.T) {
 	tmp := t.TempDir()
 	t.Setenv("TRACK_HOME", tmp)
@@ -302,3 +358,479 @@ func TestLabelsNextAndReorder(t *testing.T) {
 		t.Fatalf("first issue = %s, want %s", items[0].ID, b.ID)
 	}
 }
+
+func TestListIssuesFiltersByPriorities(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	for _, p := range []string{"p0", "p1", "p2", "none"} {
+		if _, err := store.CreateIssue(ctx, issue.Item{Title: p, Status: issue.StatusTodo, Priority: p}); err != nil {
+			t.Fatalf("CreateIssue(%s) error: %v", p, err)
+		}
+	}
+
+	items, err := store.ListIssues(ctx, ListFilter{Priorities: []string{"p0", "none"}, Sort: "priority"})
+	if err != nil {
+		t.Fatalf("ListIssues() error: %v", err)
+	}
+	if len(items) != 2 || items[0].Priority != "p0" || items[1].Priority != "none" {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+}
+
+func TestListIssuesFiltersByLabelExpression(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	for _, labels := range [][]string{{"bug"}, {"bug", "wontfix"}, {"ui"}, {"bugfix"}, nil} {
+		if _, err := store.CreateIssue(ctx, issue.Item{Title: "t", Status: issue.StatusTodo, Priority: "none", Labels: labels}); err != nil {
+			t.Fatalf("CreateIssue() error: %v", err)
+		}
+	}
+
+	cases := map[string][]string{
+		"bug":                 {"TRK-1", "TRK-2"},
+		"bug AND NOT wontfix": {"TRK-1"},
+		"bug,ui":              {"TRK-1", "TRK-2", "TRK-3"},
+		"NOT (bug OR ui)":     {"TRK-4", "TRK-5"},
+	}
+	for expr, want := range cases {
+		items, err := store.ListIssues(ctx, ListFilter{Label: expr, Sort: "manual"})
+		if err != nil {
+			t.Fatalf("ListIssues(%q) error: %v", expr, err)
+		}
+		got := make([]string, 0, len(items))
+		for _, it := range items {
+			got = append(got, it.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("ListIssues(%q) = %v, want %v", expr, got, want)
+		}
+	}
+
+	if _, err := store.ListIssues(ctx, ListFilter{Label: "bug AND"}); err == nil {
+		t.Fatalf("expected invalid label expression error")
+	}
+}
+
+func TestCountIssuesUsesListFilter(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	for _, st := range []string{issue.StatusTodo, issue.StatusReady, issue.StatusReady, issue.StatusDone} {
+		if _, err := store.CreateIssue(ctx, issue.Item{Title: "t", Status: st, Priority: "p2"}); err != nil {
+			t.Fatalf("CreateIssue() error: %v", err)
+		}
+	}
+
+	cases := []struct {
+		filter ListFilter
+		want   int
+	}{
+		{ListFilter{}, 4},
+		{ListFilter{Statuses: []string{issue.StatusReady}}, 2},
+		{ListFilter{ExcludeStatuses: []string{issue.StatusDone}}, 3},
+		{ListFilter{Label: "bug"}, 0},
+	}
+	for _, tc := range cases {
+		got, err := store.CountIssues(ctx, tc.filter)
+		if err != nil {
+			t.Fatalf("CountIssues(%+v) error: %v", tc.filter, err)
+		}
+		if got != tc.want {
+			t.Fatalf("CountIssues(%+v) = %d, want %d", tc.filter, got, tc.want)
+		}
+	}
+}
+
+func TestDueTimesUseConfiguredTimezone(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	cfg := appconfig.Default()
+	cfg.Timezone = "Asia/Tokyo"
+	if err := appconfig.Save(cfg); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	created, err := store.CreateIssue(ctx, issue.Item{Title: "t", Status: issue.StatusTodo, Priority: "p2", Due: "2026-03-01T17:00"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	if created.Due != "2026-03-01T17:00:00+09:00" {
+		t.Fatalf("due = %q, want RFC3339 in Asia/Tokyo", created.Due)
+	}
+	due := "2026-03-02"
+	dated, err := store.CreateIssue(ctx, issue.Item{Title: "d", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	if updated, err := store.UpdateIssue(ctx, dated.ID, UpdateIssueInput{Due: &due}); err != nil || updated.Due != due {
+		t.Fatalf("UpdateIssue(date-only due) = %q, %v", updated.Due, err)
+	}
+
+	items, err := store.ListIssues(ctx, ListFilter{DueFrom: "2026-03-01", DueTo: "2026-03-01"})
+	if err != nil {
+		t.Fatalf("ListIssues() error: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != created.ID {
+		t.Fatalf("due:2026-03-01 should match the timed issue, got %+v", items)
+	}
+}
+
+func TestSetLabelsReplacesAndDedupes(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "A", Status: issue.StatusTodo, Priority: "p2", Labels: []string{"old"}})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	got, err := store.SetLabels(ctx, it.ID, []string{" bug ", "api", "bug"})
+	if err != nil {
+		t.Fatalf("SetLabels() error: %v", err)
+	}
+	if strings.Join(got.Labels, ",") != "bug,api" {
+		t.Fatalf("labels = %v, want [bug api]", got.Labels)
+	}
+	if _, err := store.SetLabels(ctx, it.ID, []string{"ok", " "}); err == nil {
+		t.Fatalf("SetLabels() with empty label should fail")
+	}
+	cleared, err := store.SetLabels(ctx, it.ID, []string{})
+	if err != nil {
+		t.Fatalf("SetLabels(empty) error: %v", err)
+	}
+	reloaded, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error: %v", err)
+	}
+	if len(cleared.Labels) != 0 || len(reloaded.Labels) != 0 {
+		t.Fatalf("labels should be cleared: %v / %v", cleared.Labels, reloaded.Labels)
+	}
+}
+
+func TestPinnedIssuesSortFirst(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	var ids []string
+	for _, p := range []string{"p0", "p1", "p3"} {
+		it, err := store.CreateIssue(ctx, issue.Item{Title: "issue " + p, Status: issue.StatusTodo, Priority: p})
+		if err != nil {
+			t.Fatalf("CreateIssue() error: %v", err)
+		}
+		ids = append(ids, it.ID)
+	}
+	pinned, err := store.SetPinned(ctx, ids[2], true)
+	if err != nil || !pinned.Pinned {
+		t.Fatalf("SetPinned() = %+v, %v", pinned, err)
+	}
+
+	for _, sort := range []string{"", "priority", "manual", "-created"} {
+		items, err := store.ListIssues(ctx, ListFilter{Sort: sort})
+		if err != nil {
+			t.Fatalf("ListIssues(%q) error: %v", sort, err)
+		}
+		if items[0].ID != ids[2] || !items[0].Pinned {
+			t.Fatalf("ListIssues(%q) should put the pinned issue first, got %s", sort, items[0].ID)
+		}
+	}
+	next, ok, err := store.NextIssue(ctx)
+	if err != nil || !ok || next.ID != ids[2] {
+		t.Fatalf("NextIssue() = %s, %v, %v; want pinned %s", next.ID, ok, err, ids[2])
+	}
+
+	if err := store.Reorder(ctx, ids[2], "", ids[1]); err != nil {
+		t.Fatalf("Reorder() error: %v", err)
+	}
+	items, err := store.ListIssues(ctx, ListFilter{Sort: "manual"})
+	if err != nil {
+		t.Fatalf("ListIssues() error: %v", err)
+	}
+	if items[0].ID != ids[2] {
+		t.Fatalf("pinned issue should stay first after reorder, got %s", items[0].ID)
+	}
+
+	if _, err := store.SetPinned(ctx, ids[2], false); err != nil {
+		t.Fatalf("SetPinned(false) error: %v", err)
+	}
+	if next, _, _ := store.NextIssue(ctx); next.ID != ids[0] {
+		t.Fatalf("NextIssue() after unpin = %s, want %s", next.ID, ids[0])
+	}
+}
+
+func TestRankIssuesKeepsOtherSlots(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	var ids []string
+	for _, title := range []string{"A", "B", "C", "D"} {
+		it, err := store.CreateIssue(ctx, issue.Item{Title: title, Status: issue.StatusTodo, Priority: "p2"})
+		if err != nil {
+			t.Fatalf("CreateIssue() error: %v", err)
+		}
+		ids = append(ids, it.ID)
+	}
+
+	// A and C swap; B and D are not ranked and stay where they are.
+	if err := store.RankIssues(ctx, []string{ids[2], ids[0]}); err != nil {
+		t.Fatalf("RankIssues() error: %v", err)
+	}
+	items, err := store.ListIssues(ctx, ListFilter{Sort: "manual"})
+	if err != nil {
+		t.Fatalf("ListIssues() error: %v", err)
+	}
+	var got []string
+	for _, it := range items {
+		got = append(got, it.ID)
+	}
+	want := []string{ids[2], ids[1], ids[0], ids[3]}
+	if !slices.Equal(got, want) {
+		t.Fatalf("manual order = %v, want %v", got, want)
+	}
+
+	if err := store.RankIssues(ctx, []string{"TRK-999"}); err == nil {
+		t.Fatalf("RankIssues() should reject unknown issues")
+	}
+}
+
+func TestAppendAndPrependBody(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	s, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	it, err := s.CreateIssue(ctx, issue.Item{Title: "notes", Status: issue.StatusTodo, Priority: "none"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	str := func(v string) *string { return &v }
+	if _, err := s.UpdateIssue(ctx, it.ID, UpdateIssueInput{AppendBody: str("first")}); err != nil {
+		t.Fatalf("append to empty body error: %v", err)
+	}
+	// Another writer replaces the body; the next append builds on it rather
+	// than on a stale copy.
+	if _, err := s.UpdateIssue(ctx, it.ID, UpdateIssueInput{Body: str("first\nsecond")}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+	got, err := s.UpdateIssue(ctx, it.ID, UpdateIssueInput{AppendBody: str("third"), PrependBody: str("zeroth")})
+	if err != nil {
+		t.Fatalf("append/prepend error: %v", err)
+	}
+	if got.Body != "zeroth\nfirst\nsecond\nthird" {
+		t.Fatalf("body = %q", got.Body)
+	}
+	revs, err := s.ListRevisions(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("ListRevisions() error: %v", err)
+	}
+	if len(revs) != 4 || revs[len(revs)-1].Body != got.Body {
+		t.Fatalf("revisions = %+v", revs)
+	}
+	if _, err := s.UpdateIssue(ctx, it.ID, UpdateIssueInput{Body: str("x"), AppendBody: str("y")}); err == nil {
+		t.Fatalf("replacing and appending at once should fail")
+	}
+}
+
+func TestExternalIDLookupAndUniqueness(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	created, err := store.CreateIssue(ctx, issue.Item{Title: "Imported", Status: issue.StatusTodo, Priority: "none", ExternalID: " JIRA-123 "})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	got, err := store.GetIssueByExternalID(ctx, "JIRA-123")
+	if err != nil {
+		t.Fatalf("GetIssueByExternalID() error: %v", err)
+	}
+	if got.ID != created.ID || got.ExternalID != "JIRA-123" {
+		t.Fatalf("GetIssueByExternalID() = %+v, want %s with JIRA-123", got, created.ID)
+	}
+	if _, err := store.GetIssueByExternalID(ctx, "JIRA-999"); err == nil {
+		t.Fatalf("GetIssueByExternalID() for an unknown id should fail")
+	}
+
+	_, err = store.CreateIssue(ctx, issue.Item{Title: "Again", Status: issue.StatusTodo, Priority: "none", ExternalID: "JIRA-123"})
+	if err == nil || !strings.Contains(err.Error(), "external id already used: JIRA-123") {
+		t.Fatalf("duplicate external id error = %v", err)
+	}
+	if _, err := store.CreateIssue(ctx, issue.Item{Title: "Local", Status: issue.StatusTodo, Priority: "none"}); err != nil {
+		t.Fatalf("issues without external ids should not clash: %v", err)
+	}
+}
+
+func TestNextIssueSkipsBlockedIssues(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	urgent, err := store.CreateIssue(ctx, issue.Item{Title: "Urgent", Status: issue.StatusTodo, Priority: "p0"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	blocker, err := store.CreateIssue(ctx, issue.Item{Title: "Blocker", Status: issue.StatusTodo, Priority: "p3"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	if err := store.AddIssueDependency(ctx, urgent.ID, blocker.ID); err != nil {
+		t.Fatalf("AddIssueDependency() error: %v", err)
+	}
+
+	if next, ok, err := store.NextIssue(ctx); err != nil || !ok || next.ID != blocker.ID {
+		t.Fatalf("NextIssue() = %s, %v, %v; want blocker %s", next.ID, ok, err, blocker.ID)
+	}
+	if n, err := store.CountIssues(ctx, ListFilter{Unblocked: true}); err != nil || n != 1 {
+		t.Fatalf("CountIssues(Unblocked) = %d, %v; want 1", n, err)
+	}
+	blockedBy, err := store.BlockedBy(ctx)
+	if err != nil || len(blockedBy) != 1 || len(blockedBy[urgent.ID]) != 1 {
+		t.Fatalf("BlockedBy() = %v, %v", blockedBy, err)
+	}
+
+	done := issue.StatusDone
+	if _, err := store.UpdateIssue(ctx, blocker.ID, UpdateIssueInput{Status: &done}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+	if next, ok, err := store.NextIssue(ctx); err != nil || !ok || next.ID != urgent.ID {
+		t.Fatalf("NextIssue() after blocker done = %s, %v, %v; want %s", next.ID, ok, err, urgent.ID)
+	}
+	if blockedBy, err := store.BlockedBy(ctx); err != nil || len(blockedBy) != 0 {
+		t.Fatalf("BlockedBy() after blocker done = %v, %v", blockedBy, err)
+	}
+}
+
+func TestListDependencyClosure(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	var ids []string
+	for _, title := range []string{"epic", "task", "subtask", "sibling", "unrelated", "unrelated blocker"} {
+		it, err := store.CreateIssue(ctx, issue.Item{Title: title, Status: issue.StatusTodo, Priority: "none"})
+		if err != nil {
+			t.Fatalf("CreateIssue() error: %v", err)
+		}
+		ids = append(ids, it.ID)
+	}
+	epic, task, subtask, sibling, unrelated, unrelatedBlocker := ids[0], ids[1], ids[2], ids[3], ids[4], ids[5]
+	for _, d := range [][2]string{{epic, task}, {task, subtask}, {epic, sibling}, {unrelated, unrelatedBlocker}} {
+		if err := store.AddIssueDependency(ctx, d[0], d[1]); err != nil {
+			t.Fatalf("AddIssueDependency() error: %v", err)
+		}
+	}
+
+	got, err := store.ListDependencyClosure(ctx, task)
+	if err != nil {
+		t.Fatalf("ListDependencyClosure() error: %v", err)
+	}
+	want := map[string][]string{epic: {task, sibling}, task: {subtask}}
+	if len(got) != len(want) || !slices.Equal(got[epic], want[epic]) || !slices.Equal(got[task], want[task]) {
+		t.Fatalf("ListDependencyClosure(%s) = %v, want %v", task, got, want)
+	}
+
+	items, err := store.ListIssues(ctx, ListFilter{IDs: []string{subtask, epic}, Sort: "id"})
+	if err != nil || len(items) != 2 || items[0].ID != epic || items[1].ID != subtask {
+		t.Fatalf("ListIssues(IDs) = %+v, %v", items, err)
+	}
+}
+
+func TestArchivedBlockersDoNotBlock(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	urgent, err := store.CreateIssue(ctx, issue.Item{Title: "Urgent", Status: issue.StatusTodo, Priority: "p0"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	blocker, err := store.CreateIssue(ctx, issue.Item{Title: "Abandoned", Status: issue.StatusTodo, Priority: "p3"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	if err := store.AddIssueDependency(ctx, urgent.ID, blocker.ID); err != nil {
+		t.Fatalf("AddIssueDependency() error: %v", err)
+	}
+	archived := issue.StatusArchived
+	if _, err := store.UpdateIssue(ctx, blocker.ID, UpdateIssueInput{Status: &archived}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+
+	if next, ok, err := store.NextIssue(ctx); err != nil || !ok || next.ID != urgent.ID {
+		t.Fatalf("NextIssue() = %s, %v, %v; want %s", next.ID, ok, err, urgent.ID)
+	}
+	if n, err := store.CountIssues(ctx, ListFilter{Unblocked: true, ExcludeArchived: true}); err != nil || n != 1 {
+		t.Fatalf("CountIssues(Unblocked) = %d, %v; want 1", n, err)
+	}
+	if blockedBy, err := store.BlockedBy(ctx); err != nil || len(blockedBy) != 0 {
+		t.Fatalf("BlockedBy() = %v, %v", blockedBy, err)
+	}
+	if blockers, err := store.UnmergedBlockers(ctx, urgent.ID); err != nil || len(blockers) != 0 {
+		t.Fatalf("UnmergedBlockers() = %+v, %v", blockers, err)
+	}
+}
@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type JobState struct {
+	Name      string
+	Enabled   bool
+	Interval  time.Duration
+	LastRunAt string
+	LastError string
+}
+
+// GetJobState returns the stored scheduler state for a job. The second
+// return value is false when the job has never been configured or run.
+func (s *Store) GetJobState(ctx context.Context, name string) (JobState, bool, error) {
+	var (
+		st        JobState
+		enabled   int
+		seconds   int64
+		lastRun   sql.NullString
+		lastError sql.NullString
+	)
+	err := s.db.QueryRowContext(ctx, `SELECT name, enabled, interval_seconds, last_run_at, last_error FROM jobs WHERE name = ?`, name).
+		Scan(&st.Name, &enabled, &seconds, &lastRun, &lastError)
+	if errors.Is(err, sql.ErrNoRows) {
+		return JobState{}, false, nil
+	}
+	if err != nil {
+		return JobState{}, false, fmt.Errorf("get job state: %w", err)
+	}
+	st.Enabled = enabled != 0
+	st.Interval = time.Duration(seconds) * time.Second
+	st.LastRunAt = lastRun.String
+	st.LastError = lastError.String
+	return st, true, nil
+}
+
+func (s *Store) SaveJobState(ctx context.Context, st JobState) error {
+	enabled := 0
+	if st.Enabled {
+		enabled = 1
+	}
+	return withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `
+			INSERT INTO jobs(name, enabled, interval_seconds, last_run_at, last_error)
+			VALUES(?, ?, ?, ?, ?)
+			ON CONFLICT(name) DO UPDATE SET enabled=excluded.enabled, interval_seconds=excluded.interval_seconds,
+				last_run_at=excluded.last_run_at, last_error=excluded.last_error
+		`, st.Name, enabled, int64(st.Interval/time.Second), nullable(st.LastRunAt), nullable(st.LastError))
+		if err != nil {
+			return fmt.Errorf("save job state: %w", err)
+		}
+		return nil
+	})
+}
+
+// MarkJobKey records that a job already handled key. It reports false when
+// the key was marked before, letting jobs avoid repeating side effects.
+func (s *Store) MarkJobKey(ctx context.Context, job, key string) (bool, error) {
+	var n int64
+	err := withSQLiteRetry(ctx, func() error {
+		res, err := s.db.ExecContext(ctx, `INSERT INTO job_marks(job, key, created_at) VALUES(?, ?, ?) ON CONFLICT(job, key) DO NOTHING`,
+			job, key, time.Now().UTC().Format(time.RFC3339))
+		if err != nil {
+			return err
+		}
+		n, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("mark job key: %w", err)
+	}
+	return n > 0, nil
+}
+
+// BackupTo writes a consistent copy of the database to path.
+func (s *Store) BackupTo(ctx context.Context, path string) error {
+	if _, err := s.db.ExecContext(ctx, `VACUUM INTO ?`, path); err != nil {
+		return fmt.Errorf("backup database: %w", err)
+	}
+	return nil
+}
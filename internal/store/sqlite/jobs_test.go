@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJobStateRoundTripAndMarks(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	if _, ok, err := store.GetJobState(ctx, "backup"); err != nil || ok {
+		t.Fatalf("GetJobState() before save = ok %v, err %v", ok, err)
+	}
+	want := JobState{Name: "backup", Enabled: true, Interval: 6 * time.Hour, LastRunAt: "2026-01-01T00:00:00Z", LastError: "disk full"}
+	if err := store.SaveJobState(ctx, want); err != nil {
+		t.Fatalf("SaveJobState() error: %v", err)
+	}
+	got, ok, err := store.GetJobState(ctx, "backup")
+	if err != nil || !ok {
+		t.Fatalf("GetJobState() = ok %v, err %v", ok, err)
+	}
+	if got != want {
+		t.Fatalf("GetJobState() = %+v, want %+v", got, want)
+	}
+
+	first, err := store.MarkJobKey(ctx, "due-soon", "TRK-1:2026-01-02")
+	if err != nil || !first {
+		t.Fatalf("MarkJobKey() first = %v, err %v", first, err)
+	}
+	again, err := store.MarkJobKey(ctx, "due-soon", "TRK-1:2026-01-02")
+	if err != nil || again {
+		t.Fatalf("MarkJobKey() repeat = %v, err %v", again, err)
+	}
+
+	path := filepath.Join(tmp, "backup.db")
+	if err := store.BackupTo(ctx, path); err != nil {
+		t.Fatalf("BackupTo() error: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("backup file missing: %v", err)
+	}
+}
+
+func TestRecurringIssuesCRUD(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	if _, err := store.AddRecurringIssue(ctx, RecurringIssue{Title: "no schedule"}); err == nil {
+		t.Fatalf("AddRecurringIssue() without interval should fail")
+	}
+	r, err := store.AddRecurringIssue(ctx, RecurringIssue{
+		Title:     "Weekly review",
+		Priority:  "p2",
+		Labels:    []string{"chore"},
+		Every:     "weekly",
+		NextRunAt: "2026-01-05T09:00:00Z",
+	})
+	if err != nil {
+		t.Fatalf("AddRecurringIssue() error: %v", err)
+	}
+	if err := store.SetRecurringNextRun(ctx, r.ID, "2026-01-12T09:00:00Z"); err != nil {
+		t.Fatalf("SetRecurringNextRun() error: %v", err)
+	}
+
+	recs, err := store.ListRecurringIssues(ctx)
+	if err != nil {
+		t.Fatalf("ListRecurringIssues() error: %v", err)
+	}
+	if len(recs) != 1 || recs[0].NextRunAt != "2026-01-12T09:00:00Z" || len(recs[0].Labels) != 1 || recs[0].Labels[0] != "chore" {
+		t.Fatalf("unexpected recurring issues: %+v", recs)
+	}
+
+	if err := store.RemoveRecurringIssue(ctx, r.ID); err != nil {
+		t.Fatalf("RemoveRecurringIssue() error: %v", err)
+	}
+	if err := store.RemoveRecurringIssue(ctx, r.ID); err == nil {
+		t.Fatalf("RemoveRecurringIssue() twice should fail")
+	}
+}
@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+// AddLink attaches an external URL to an issue. Adding a URL the issue
+// already links to updates its title.
+func (s *Store) AddLink(ctx context.Context, issueID, rawURL, title string) error {
+	rawURL, err := normalizeLinkURL(rawURL)
+	if err != nil {
+		return err
+	}
+	if _, err := s.GetIssue(ctx, issueID); err != nil {
+		return err
+	}
+	err = withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `
+			INSERT INTO issue_links(issue_id, url, title, created_at)
+			VALUES(?, ?, ?, ?)
+			ON CONFLICT(issue_id, url) DO UPDATE SET title=excluded.title
+		`, issueID, rawURL, strings.TrimSpace(title), time.Now().UTC().Format(time.RFC3339))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("add link: %w", err)
+	}
+	if err := s.RecordActivity(ctx, ActivityUpdated, issueID, "links"); err != nil {
+		return err
+	}
+	return s.publish(ctx, issueID, EventIssueUpdated)
+}
+
+func normalizeLinkURL(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("invalid link url: %s", rawURL)
+	}
+	return rawURL, nil
+}
+
+// RemoveLink detaches a URL from an issue.
+func (s *Store) RemoveLink(ctx context.Context, issueID, rawURL string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM issue_links WHERE issue_id = ? AND url = ?`, issueID, strings.TrimSpace(rawURL))
+	if err != nil {
+		return fmt.Errorf("remove link: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("link not found: %s", rawURL)
+	}
+	if err := s.RecordActivity(ctx, ActivityUpdated, issueID, "links"); err != nil {
+		return err
+	}
+	return s.publish(ctx, issueID, EventIssueUpdated)
+}
+
+// ListLinks returns an issue's links in the order they were added.
+func (s *Store) ListLinks(ctx context.Context, issueID string) ([]issue.Link, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT url, title FROM issue_links WHERE issue_id = ? ORDER BY created_at, rowid`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("list links: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]issue.Link, 0)
+	for rows.Next() {
+		var l issue.Link
+		if err := rows.Scan(&l.URL, &l.Title); err != nil {
+			return nil, fmt.Errorf("scan link: %w", err)
+		}
+		out = append(out, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate links: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,119 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+// Mention origins. Body mentions are replaced whenever the body changes;
+// comment mentions accumulate.
+const (
+	mentionBody    = "body"
+	mentionComment = "comment"
+)
+
+// setBodyMentions replaces the mentions an issue's body makes.
+func (s *Store) setBodyMentions(ctx context.Context, issueID, body string) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	err := withSQLiteRetry(ctx, func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM issue_mentions WHERE source_id = ? AND origin = ?`, issueID, mentionBody); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		for _, target := range issue.Mentions(body) {
+			if target == issueID {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, `INSERT INTO issue_mentions(source_id, target_id, origin, created_at) VALUES(?, ?, ?, ?)`, issueID, target, mentionBody, now); err != nil {
+				_ = tx.Rollback()
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return fmt.Errorf("record mentions: %w", err)
+	}
+	return nil
+}
+
+// addCommentMentions records the mentions made in a comment on an issue.
+func (s *Store) addCommentMentions(ctx context.Context, issueID, text string) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, target := range issue.Mentions(text) {
+		if target == issueID {
+			continue
+		}
+		if err := withSQLiteRetry(ctx, func() error {
+			_, err := s.db.ExecContext(ctx, `INSERT INTO issue_mentions(source_id, target_id, origin, created_at) VALUES(?, ?, ?, ?)
+				ON CONFLICT DO NOTHING`, issueID, target, mentionComment, now)
+			return err
+		}); err != nil {
+			return fmt.Errorf("record mentions: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReferencedBy returns the IDs of issues whose body or comments mention
+// issueID, in the order they first did.
+func (s *Store) ReferencedBy(ctx context.Context, issueID string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT source_id FROM issue_mentions
+		WHERE target_id = ?
+		GROUP BY source_id
+		ORDER BY MIN(created_at), MIN(rowid)
+	`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("list mentions: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan mention: %w", err)
+		}
+		out = append(out, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate mentions: %w", err)
+	}
+	return out, nil
+}
+
+// backfillMentions records the body mentions of issues written before
+// mentions were tracked.
+func (s *Store) backfillMentions(ctx context.Context) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, COALESCE(body, '') FROM issues WHERE body LIKE '%TRK-%'`)
+	if err != nil {
+		return fmt.Errorf("backfill mentions: %w", err)
+	}
+	type pending struct{ id, body string }
+	var todo []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.id, &p.body); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("backfill mentions: %w", err)
+		}
+		todo = append(todo, p)
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("backfill mentions: %w", err)
+	}
+	for _, p := range todo {
+		if err := s.setBodyMentions(ctx, p.id, p.body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+func TestReferencedByTracksBodyAndCommentMentions(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	target, err := store.CreateIssue(ctx, issue.Item{Title: "target", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	fromBody, err := store.CreateIssue(ctx, issue.Item{Title: "body", Status: issue.StatusTodo, Priority: "p2", Body: "depends on " + target.ID})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	fromComment, err := store.CreateIssue(ctx, issue.Item{Title: "comment", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	if err := store.RecordActivity(ctx, ActivityComment, fromComment.ID, "same root cause as "+target.ID); err != nil {
+		t.Fatalf("RecordActivity() error: %v", err)
+	}
+	if err := store.RecordActivity(ctx, ActivityComment, target.ID, "self reference "+target.ID); err != nil {
+		t.Fatalf("RecordActivity() error: %v", err)
+	}
+
+	got, err := store.ReferencedBy(ctx, target.ID)
+	if err != nil {
+		t.Fatalf("ReferencedBy() error: %v", err)
+	}
+	if want := []string{fromBody.ID, fromComment.ID}; !slices.Equal(got, want) {
+		t.Fatalf("ReferencedBy() = %v, want %v", got, want)
+	}
+
+	// Editing the mention out of the body drops it; the comment stays.
+	body := "no longer related"
+	if _, err := store.UpdateIssue(ctx, fromBody.ID, UpdateIssueInput{Body: &body}); err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+	got, err = store.ReferencedBy(ctx, target.ID)
+	if err != nil {
+		t.Fatalf("ReferencedBy() error: %v", err)
+	}
+	if want := []string{fromComment.ID}; !slices.Equal(got, want) {
+		t.Fatalf("ReferencedBy() after edit = %v, want %v", got, want)
+	}
+}
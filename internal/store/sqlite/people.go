@@ -0,0 +1,161 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+)
+
+// Assignee kinds. Agents carry a profile saying how to run them.
+const (
+	KindPerson = "person"
+	KindAgent  = "agent"
+)
+
+// Person is an entry in the assignee directory. With strict_assignees set,
+// issues can only be assigned to people listed here.
+//
+// Agent entries say how run, planning, and dispatch start the agent for the
+// issues assigned to it: Runner is codex, claude, or a custom command line,
+// Sandbox is handed to the runner, and MaxConcurrency caps the sessions
+// running at once (0 means no cap).
+type Person struct {
+	Name           string
+	Email          string
+	Kind           string
+	Runner         string
+	Sandbox        string
+	MaxConcurrency int
+	CreatedAt      string
+}
+
+const personColumns = `name, email, kind, runner, sandbox, max_concurrency, created_at`
+
+func (s *Store) AddPerson(ctx context.Context, p Person) error {
+	p.Name = issue.NormalizeAssignee(p.Name)
+	if p.Name == "" || strings.ContainsAny(p.Name, " \t,") {
+		return fmt.Errorf("invalid person name: %q", p.Name)
+	}
+	p.Email = strings.TrimSpace(p.Email)
+	if p.Email != "" {
+		if _, err := mail.ParseAddress(p.Email); err != nil {
+			return fmt.Errorf("invalid email: %s", p.Email)
+		}
+	}
+	if p.Kind == "" {
+		p.Kind = KindPerson
+	}
+	p.Runner, p.Sandbox = strings.TrimSpace(p.Runner), strings.TrimSpace(p.Sandbox)
+	switch {
+	case p.Kind != KindPerson && p.Kind != KindAgent:
+		return fmt.Errorf("invalid kind: %s", p.Kind)
+	case p.Kind == KindAgent && p.Runner == "":
+		return fmt.Errorf("agent %s needs a runner", p.Name)
+	case p.Kind == KindPerson && (p.Runner != "" || p.Sandbox != "" || p.MaxConcurrency != 0):
+		return fmt.Errorf("runner settings are only for agents")
+	case p.MaxConcurrency < 0:
+		return fmt.Errorf("max concurrency must be >= 0")
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	err := withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `INSERT INTO people(name, email, kind, runner, sandbox, max_concurrency, created_at) VALUES(?, ?, ?, ?, ?, ?, ?)`,
+			p.Name, p.Email, p.Kind, p.Runner, p.Sandbox, p.MaxConcurrency, now)
+		return err
+	})
+	if err != nil {
+		msg := strings.ToLower(err.Error())
+		if strings.Contains(msg, "unique") || strings.Contains(msg, "constraint failed") {
+			return fmt.Errorf("person already exists: %s", p.Name)
+		}
+		return fmt.Errorf("insert person: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) GetPerson(ctx context.Context, name string) (Person, error) {
+	var p Person
+	err := s.db.QueryRowContext(ctx, `SELECT `+personColumns+` FROM people WHERE name = ?`, strings.TrimSpace(name)).
+		Scan(&p.Name, &p.Email, &p.Kind, &p.Runner, &p.Sandbox, &p.MaxConcurrency, &p.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Person{}, fmt.Errorf("person not found: %s", name)
+	}
+	if err != nil {
+		return Person{}, fmt.Errorf("get person: %w", err)
+	}
+	return p, nil
+}
+
+func (s *Store) ListPeople(ctx context.Context) ([]Person, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+personColumns+` FROM people ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("list people: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]Person, 0)
+	for rows.Next() {
+		var p Person
+		if err := rows.Scan(&p.Name, &p.Email, &p.Kind, &p.Runner, &p.Sandbox, &p.MaxConcurrency, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan person: %w", err)
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate people: %w", err)
+	}
+	return out, nil
+}
+
+func (s *Store) RemovePerson(ctx context.Context, name string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM people WHERE name = ?`, strings.TrimSpace(name))
+	if err != nil {
+		return fmt.Errorf("delete person: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("person not found: %s", name)
+	}
+	return nil
+}
+
+// validateAssignee rejects assignees missing from the people directory when
+// strict_assignees is set. Unassigned is always allowed.
+func (s *Store) validateAssignee(ctx context.Context, assignee string) error {
+	if assignee == "" {
+		return nil
+	}
+	cfg, err := appconfig.Load()
+	if err != nil {
+		return err
+	}
+	if !cfg.StrictAssignees {
+		return nil
+	}
+	if _, err := s.GetPerson(ctx, assignee); err != nil {
+		return fmt.Errorf("unknown assignee: %s (see track people add)", assignee)
+	}
+	return nil
+}
+
+// AgentProfile returns the agent entry for an assignee. ok is false when the
+// assignee is unset, not in the directory, or a person.
+func (s *Store) AgentProfile(ctx context.Context, assignee string) (p Person, ok bool, err error) {
+	if assignee == "" {
+		return Person{}, false, nil
+	}
+	err = s.db.QueryRowContext(ctx, `SELECT `+personColumns+` FROM people WHERE name = ? AND kind = ?`, assignee, KindAgent).
+		Scan(&p.Name, &p.Email, &p.Kind, &p.Runner, &p.Sandbox, &p.MaxConcurrency, &p.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Person{}, false, nil
+	}
+	if err != nil {
+		return Person{}, false, fmt.Errorf("get agent profile: %w", err)
+	}
+	return p, true, nil
+}
@@ -0,0 +1,175 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+// ProjectProgress counts a project's issues. Archived issues are left out.
+type ProjectProgress struct {
+	Done  int
+	Total int
+}
+
+// AddProjectDependency records that key cannot start before dependsOn is
+// finished. Dependencies that would form a cycle are rejected.
+func (s *Store) AddProjectDependency(ctx context.Context, key, dependsOn string) error {
+	key, dependsOn = strings.TrimSpace(key), strings.TrimSpace(dependsOn)
+	if key == dependsOn {
+		return fmt.Errorf("project cannot depend on itself: %s", key)
+	}
+	for _, k := range []string{key, dependsOn} {
+		if _, err := s.GetProject(ctx, k); err != nil {
+			return err
+		}
+	}
+	deps, err := s.ListProjectDependencies(ctx)
+	if err != nil {
+		return err
+	}
+	if dependsOnTransitively(deps, dependsOn, key) {
+		return fmt.Errorf("dependency cycle: %s already depends on %s", dependsOn, key)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO project_dependencies(project_key, depends_on, created_at)
+		VALUES(?, ?, ?)
+		ON CONFLICT(project_key, depends_on) DO NOTHING
+	`, key, dependsOn, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("add project dependency: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) RemoveProjectDependency(ctx context.Context, key, dependsOn string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM project_dependencies WHERE project_key = ? AND depends_on = ?`, strings.TrimSpace(key), strings.TrimSpace(dependsOn))
+	if err != nil {
+		return fmt.Errorf("remove project dependency: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("project dependency not found: %s -> %s", key, dependsOn)
+	}
+	return nil
+}
+
+// ListProjectDependencies maps each project key to the projects it depends
+// on.
+func (s *Store) ListProjectDependencies(ctx context.Context) (map[string][]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT project_key, depends_on FROM project_dependencies ORDER BY project_key, depends_on`)
+	if err != nil {
+		return nil, fmt.Errorf("list project dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	out := map[string][]string{}
+	for rows.Next() {
+		var key, dep string
+		if err := rows.Scan(&key, &dep); err != nil {
+			return nil, fmt.Errorf("scan project dependency: %w", err)
+		}
+		out[key] = append(out[key], dep)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate project dependencies: %w", err)
+	}
+	return out, nil
+}
+
+// ListProjectProgress returns how many of each project's issues are done.
+func (s *Store) ListProjectProgress(ctx context.Context) (map[string]ProjectProgress, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT l.project_key, SUM(CASE WHEN i.status = ? THEN 1 ELSE 0 END), COUNT(1)
+		FROM project_issue_links l
+		JOIN issues i ON i.id = l.issue_id
+		WHERE i.status <> ?
+		GROUP BY l.project_key
+	`, issue.StatusDone, issue.StatusArchived)
+	if err != nil {
+		return nil, fmt.Errorf("list project progress: %w", err)
+	}
+	defer rows.Close()
+
+	out := map[string]ProjectProgress{}
+	for rows.Next() {
+		var key string
+		var p ProjectProgress
+		if err := rows.Scan(&key, &p.Done, &p.Total); err != nil {
+			return nil, fmt.Errorf("scan project progress: %w", err)
+		}
+		out[key] = p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate project progress: %w", err)
+	}
+	return out, nil
+}
+
+// ReorderProject moves a project before or after another in the roadmap
+// order.
+func (s *Store) ReorderProject(ctx context.Context, key, beforeKey, afterKey string) error {
+	if (beforeKey == "" && afterKey == "") || (beforeKey != "" && afterKey != "") {
+		return fmt.Errorf("specify either --before or --after")
+	}
+	projects, err := s.ListProjects(ctx)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(projects))
+	for _, p := range projects {
+		keys = append(keys, p.Key)
+	}
+	src := slices.Index(keys, key)
+	if src == -1 {
+		return fmt.Errorf("project not found: %s", key)
+	}
+	keys = slices.Delete(keys, src, src+1)
+
+	ref, offset := beforeKey, 0
+	if afterKey != "" {
+		ref, offset = afterKey, 1
+	}
+	idx := slices.Index(keys, ref)
+	if idx == -1 {
+		return fmt.Errorf("reference project not found: %s", ref)
+	}
+	keys = slices.Insert(keys, idx+offset, key)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	for i, k := range keys {
+		if _, err := tx.ExecContext(ctx, `UPDATE projects SET order_index=? WHERE key=?`, i+1, k); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("update project order: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit project reorder: %w", err)
+	}
+	return nil
+}
+
+// dependsOnTransitively reports whether from reaches to through deps.
+func dependsOnTransitively(deps map[string][]string, from, to string) bool {
+	seen := map[string]bool{}
+	stack := []string{from}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if cur == to {
+			return true
+		}
+		if seen[cur] {
+			continue
+		}
+		seen[cur] = true
+		stack = append(stack, deps[cur]...)
+	}
+	return false
+}
@@ -3,10 +3,14 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/myuon/track/internal/issue"
 )
 
 var projectKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{1,31}$`)
@@ -15,9 +19,38 @@ type Project struct {
 	Key         string
 	Name        string
 	Description string
-	IssueCount  int
-	CreatedAt   string
-	UpdatedAt   string
+	Defaults    ProjectDefaults
+	// BranchPrefix names the branches `track dispatch` creates for the
+	// project's issues; empty uses dispatch's default.
+	BranchPrefix string
+	IssueCount   int
+	CreatedAt    string
+	UpdatedAt    string
+}
+
+// ProjectDefaults fill in issues created in or linked to a project. They
+// only fill gaps: a priority other than none or an assignee already set is
+// kept, and labels are added to the issue's own.
+type ProjectDefaults struct {
+	Labels   []string
+	Priority string
+	Assignee string
+}
+
+// Apply returns it with the defaults filled in.
+func (d ProjectDefaults) Apply(it issue.Item) issue.Item {
+	if d.Priority != "" && (it.Priority == "" || it.Priority == "none") {
+		it.Priority = d.Priority
+	}
+	if d.Assignee != "" && it.Assignee == "" {
+		it.Assignee = d.Assignee
+	}
+	for _, label := range d.Labels {
+		if !slices.Contains(it.Labels, label) {
+			it.Labels = append(slices.Clone(it.Labels), label)
+		}
+	}
+	return it
 }
 
 func ValidateProjectKey(key string) error {
@@ -41,8 +74,8 @@ func (s *Store) CreateProject(ctx context.Context, key, name, description string
 
 	now := time.Now().UTC().Format(time.RFC3339)
 	_, err := s.db.ExecContext(ctx, `
-		INSERT INTO projects(key, name, description, created_at, updated_at)
-		VALUES(?, ?, ?, ?, ?)
+		INSERT INTO projects(key, name, description, order_index, created_at, updated_at)
+		VALUES(?, ?, ?, (SELECT COALESCE(MAX(order_index), 0) + 1 FROM projects), ?, ?)
 	`, key, name, nullable(description), now, now)
 	if err != nil {
 		msg := strings.ToLower(err.Error())
@@ -65,13 +98,17 @@ func (s *Store) CreateProject(ctx context.Context, key, name, description string
 func (s *Store) GetProject(ctx context.Context, key string) (Project, error) {
 	key = strings.TrimSpace(key)
 	var out Project
+	var defaultLabels string
 	err := s.db.QueryRowContext(ctx, `
-		SELECT p.key, p.name, COALESCE(p.description, ''), p.created_at, p.updated_at, COUNT(l.issue_id)
+		SELECT p.key, p.name, COALESCE(p.description, ''), p.default_labels_json, p.default_priority, p.default_assignee, p.branch_prefix, p.created_at, p.updated_at, COUNT(l.issue_id)
 		FROM projects p
 		LEFT JOIN project_issue_links l ON p.key = l.project_key
 		WHERE p.key = ?
-		GROUP BY p.key, p.name, p.description, p.created_at, p.updated_at
-	`, key).Scan(&out.Key, &out.Name, &out.Description, &out.CreatedAt, &out.UpdatedAt, &out.IssueCount)
+		GROUP BY p.key
+	`, key).Scan(&out.Key, &out.Name, &out.Description, &defaultLabels, &out.Defaults.Priority, &out.Defaults.Assignee, &out.BranchPrefix, &out.CreatedAt, &out.UpdatedAt, &out.IssueCount)
+	if err == nil {
+		err = decodeProjectLabels(defaultLabels, &out)
+	}
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return Project{}, fmt.Errorf("project not found: %s", key)
@@ -81,13 +118,19 @@ func (s *Store) GetProject(ctx context.Context, key string) (Project, error) {
 	return out, nil
 }
 
+// ListProjects returns the projects in their manual order. The list is
+// cached until the database changes.
 func (s *Store) ListProjects(ctx context.Context) ([]Project, error) {
+	return s.cachedProjects(ctx)
+}
+
+func (s *Store) queryProjects(ctx context.Context) ([]Project, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT p.key, p.name, COALESCE(p.description, ''), p.created_at, p.updated_at, COUNT(l.issue_id)
+		SELECT p.key, p.name, COALESCE(p.description, ''), p.default_labels_json, p.default_priority, p.default_assignee, p.branch_prefix, p.created_at, p.updated_at, COUNT(l.issue_id)
 		FROM projects p
 		LEFT JOIN project_issue_links l ON p.key = l.project_key
-		GROUP BY p.key, p.name, p.description, p.created_at, p.updated_at
-		ORDER BY p.created_at ASC, p.key ASC
+		GROUP BY p.key
+		ORDER BY p.order_index ASC, p.created_at ASC, p.key ASC
 	`)
 	if err != nil {
 		return nil, fmt.Errorf("list projects: %w", err)
@@ -97,9 +140,13 @@ func (s *Store) ListProjects(ctx context.Context) ([]Project, error) {
 	out := make([]Project, 0)
 	for rows.Next() {
 		var p Project
-		if err := rows.Scan(&p.Key, &p.Name, &p.Description, &p.CreatedAt, &p.UpdatedAt, &p.IssueCount); err != nil {
+		var defaultLabels string
+		if err := rows.Scan(&p.Key, &p.Name, &p.Description, &defaultLabels, &p.Defaults.Priority, &p.Defaults.Assignee, &p.BranchPrefix, &p.CreatedAt, &p.UpdatedAt, &p.IssueCount); err != nil {
 			return nil, fmt.Errorf("scan project: %w", err)
 		}
+		if err := decodeProjectLabels(defaultLabels, &p); err != nil {
+			return nil, err
+		}
 		out = append(out, p)
 	}
 	if err := rows.Err(); err != nil {
@@ -120,18 +167,22 @@ func (s *Store) SetIssueProject(ctx context.Context, issueID, projectKey string)
 		if _, err := s.db.ExecContext(ctx, `DELETE FROM project_issue_links WHERE issue_id = ?`, issueID); err != nil {
 			return fmt.Errorf("unlink issue project: %w", err)
 		}
-		return nil
+		if err := s.RecordActivity(ctx, ActivityUpdated, issueID, "project"); err != nil {
+			return err
+		}
+		return s.publish(ctx, issueID, EventIssueUpdated)
 	}
 
 	if err := ValidateProjectKey(projectKey); err != nil {
 		return err
 	}
-	if _, err := s.GetProject(ctx, projectKey); err != nil {
+	project, err := s.GetProject(ctx, projectKey)
+	if err != nil {
 		return err
 	}
 
 	now := time.Now().UTC().Format(time.RFC3339)
-	_, err := s.db.ExecContext(ctx, `
+	_, err = s.db.ExecContext(ctx, `
 		INSERT INTO project_issue_links(issue_id, project_key, created_at, updated_at)
 		VALUES(?, ?, ?, ?)
 		ON CONFLICT(issue_id) DO UPDATE SET
@@ -141,6 +192,98 @@ func (s *Store) SetIssueProject(ctx context.Context, issueID, projectKey string)
 	if err != nil {
 		return fmt.Errorf("link issue project: %w", err)
 	}
+	if err := s.RecordActivity(ctx, ActivityUpdated, issueID, "project"); err != nil {
+		return err
+	}
+	if err := s.publish(ctx, issueID, EventIssueUpdated); err != nil {
+		return err
+	}
+	return s.applyProjectDefaults(ctx, issueID, project.Defaults)
+}
+
+// applyProjectDefaults fills the gaps in an issue from its project's
+// defaults, writing only the fields that change.
+func (s *Store) applyProjectDefaults(ctx context.Context, issueID string, d ProjectDefaults) error {
+	it, err := s.GetIssue(ctx, issueID)
+	if err != nil {
+		return err
+	}
+	want := d.Apply(it)
+	var in UpdateIssueInput
+	if want.Priority != it.Priority {
+		in.Priority = &want.Priority
+	}
+	if want.Assignee != it.Assignee {
+		in.Assignee = &want.Assignee
+	}
+	if in.Priority != nil || in.Assignee != nil {
+		if _, err := s.UpdateIssue(ctx, issueID, in); err != nil {
+			return err
+		}
+	}
+	if !slices.Equal(want.Labels, it.Labels) {
+		if _, err := s.SetLabels(ctx, issueID, want.Labels); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetProjectDefaults replaces a project's defaults. Empty fields have no
+// default.
+func (s *Store) SetProjectDefaults(ctx context.Context, key string, d ProjectDefaults) (Project, error) {
+	key = strings.TrimSpace(key)
+	if _, err := s.GetProject(ctx, key); err != nil {
+		return Project{}, err
+	}
+	d.Priority = strings.TrimSpace(d.Priority)
+	if d.Priority != "" {
+		if err := issue.ValidatePriority(d.Priority); err != nil {
+			return Project{}, err
+		}
+	}
+	d.Assignee = issue.NormalizeAssignee(d.Assignee)
+	labels := make([]string, 0, len(d.Labels))
+	for _, label := range d.Labels {
+		if label = strings.TrimSpace(label); label != "" && !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	labelsJSON, err := json.Marshal(labels)
+	if err != nil {
+		return Project{}, fmt.Errorf("encode default labels: %w", err)
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE projects SET default_labels_json=?, default_priority=?, default_assignee=?, updated_at=? WHERE key=?
+	`, string(labelsJSON), d.Priority, d.Assignee, now, key); err != nil {
+		return Project{}, fmt.Errorf("set project defaults: %w", err)
+	}
+	return s.GetProject(ctx, key)
+}
+
+// SetProjectBranchPrefix sets the prefix of the branches dispatch creates for
+// the project's issues, such as "auth/". Empty restores the default.
+func (s *Store) SetProjectBranchPrefix(ctx context.Context, key, prefix string) (Project, error) {
+	key = strings.TrimSpace(key)
+	if _, err := s.GetProject(ctx, key); err != nil {
+		return Project{}, err
+	}
+	prefix = strings.TrimSpace(prefix)
+	if strings.ContainsAny(prefix, " \t~^:?*[\\") || strings.Contains(prefix, "..") {
+		return Project{}, fmt.Errorf("invalid branch prefix: %q", prefix)
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, err := s.db.ExecContext(ctx, `UPDATE projects SET branch_prefix=?, updated_at=? WHERE key=?`, prefix, now, key); err != nil {
+		return Project{}, fmt.Errorf("set project branch prefix: %w", err)
+	}
+	return s.GetProject(ctx, key)
+}
+
+func decodeProjectLabels(raw string, p *Project) error {
+	if err := json.Unmarshal([]byte(raw), &p.Defaults.Labels); err != nil {
+		return fmt.Errorf("decode default labels for %s: %w", p.Key, err)
+	}
 	return nil
 }
 
@@ -180,6 +323,11 @@ func (s *Store) DeleteProject(ctx context.Context, key string, force bool) error
 		}
 	}
 
+	if _, err := tx.ExecContext(ctx, `DELETE FROM project_dependencies WHERE project_key = ? OR depends_on = ?`, key, key); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("delete project dependencies: %w", err)
+	}
+
 	res, err := tx.ExecContext(ctx, `DELETE FROM projects WHERE key = ?`, key)
 	if err != nil {
 		_ = tx.Rollback()
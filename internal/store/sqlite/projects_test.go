@@ -135,3 +135,43 @@ func TestCreateProjectValidatesKey(t *testing.T) {
 		t.Fatalf("CreateProject(duplicate key) should fail")
 	}
 }
+
+func TestProjectDependenciesRejectCycles(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	for _, key := range []string{"api", "web", "docs"} {
+		if _, err := store.CreateProject(ctx, key, strings.ToUpper(key), ""); err != nil {
+			t.Fatalf("CreateProject(%s) error: %v", key, err)
+		}
+	}
+	if err := store.AddProjectDependency(ctx, "web", "api"); err != nil {
+		t.Fatalf("AddProjectDependency(web, api) error: %v", err)
+	}
+	if err := store.AddProjectDependency(ctx, "docs", "web"); err != nil {
+		t.Fatalf("AddProjectDependency(docs, web) error: %v", err)
+	}
+	if err := store.AddProjectDependency(ctx, "api", "docs"); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("AddProjectDependency(api, docs) should reject a cycle, got %v", err)
+	}
+	if err := store.AddProjectDependency(ctx, "api", "api"); err == nil {
+		t.Fatalf("AddProjectDependency(api, api) should fail")
+	}
+
+	if err := store.DeleteProject(ctx, "web", false); err != nil {
+		t.Fatalf("DeleteProject() error: %v", err)
+	}
+	deps, err := store.ListProjectDependencies(ctx)
+	if err != nil {
+		t.Fatalf("ListProjectDependencies() error: %v", err)
+	}
+	if len(deps) != 0 {
+		t.Fatalf("deleting a project should drop its dependencies: %v", deps)
+	}
+}
@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+type RecurringIssue struct {
+	ID        int
+	Title     string
+	Body      string
+	Priority  string
+	Labels    []string
+	Every     string
+	NextRunAt string
+	CreatedAt string
+}
+
+func (s *Store) AddRecurringIssue(ctx context.Context, in RecurringIssue) (RecurringIssue, error) {
+	if err := issue.ValidateTitle(in.Title); err != nil {
+		return RecurringIssue{}, err
+	}
+	if in.Priority == "" {
+		in.Priority = "none"
+	}
+	if err := issue.ValidatePriority(in.Priority); err != nil {
+		return RecurringIssue{}, err
+	}
+	if strings.TrimSpace(in.Every) == "" || in.NextRunAt == "" {
+		return RecurringIssue{}, fmt.Errorf("recurring issue needs an interval and next run")
+	}
+	if in.Labels == nil {
+		in.Labels = []string{}
+	}
+	labels, err := json.Marshal(in.Labels)
+	if err != nil {
+		return RecurringIssue{}, fmt.Errorf("marshal labels: %w", err)
+	}
+	in.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO recurring_issues(title, body, priority, labels_json, every, next_run_at, created_at)
+		VALUES(?, ?, ?, ?, ?, ?, ?)
+	`, in.Title, nullable(in.Body), in.Priority, string(labels), in.Every, in.NextRunAt, in.CreatedAt)
+	if err != nil {
+		return RecurringIssue{}, fmt.Errorf("insert recurring issue: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return RecurringIssue{}, fmt.Errorf("read recurring issue id: %w", err)
+	}
+	in.ID = int(id)
+	return in, nil
+}
+
+func (s *Store) ListRecurringIssues(ctx context.Context) ([]RecurringIssue, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, title, body, priority, labels_json, every, next_run_at, created_at FROM recurring_issues ORDER BY id ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("list recurring issues: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]RecurringIssue, 0)
+	for rows.Next() {
+		var (
+			r         RecurringIssue
+			body      sql.NullString
+			labelsRaw string
+		)
+		if err := rows.Scan(&r.ID, &r.Title, &body, &r.Priority, &labelsRaw, &r.Every, &r.NextRunAt, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan recurring issue: %w", err)
+		}
+		r.Body = body.String
+		if err := json.Unmarshal([]byte(labelsRaw), &r.Labels); err != nil {
+			return nil, fmt.Errorf("decode labels: %w", err)
+		}
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate recurring issues: %w", err)
+	}
+	return out, nil
+}
+
+func (s *Store) SetRecurringNextRun(ctx context.Context, id int, nextRunAt string) error {
+	if _, err := s.db.ExecContext(ctx, `UPDATE recurring_issues SET next_run_at = ? WHERE id = ?`, nextRunAt, id); err != nil {
+		return fmt.Errorf("update recurring issue: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) RemoveRecurringIssue(ctx context.Context, id int) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM recurring_issues WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete recurring issue: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("recurring issue rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("recurring issue not found: %d", id)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Release is a named snapshot of the issues finished since a project's
+// previous release. The issues carry Label, so they can be listed with
+// `track list --label`. URL is the GitHub release, when one was created.
+type Release struct {
+	Name      string
+	Project   string
+	Label     string
+	Notes     string
+	URL       string
+	CreatedAt string
+}
+
+const releaseColumns = `name, project, label, notes, url, created_at`
+
+// CreateRelease records r. Release names are unique per project.
+func (s *Store) CreateRelease(ctx context.Context, r Release) (Release, error) {
+	r.Name, r.Project = strings.TrimSpace(r.Name), strings.TrimSpace(r.Project)
+	if r.Name == "" || strings.ContainsAny(r.Name, " \t,") {
+		return Release{}, fmt.Errorf("invalid release name: %q", r.Name)
+	}
+	r.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	err := withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `INSERT INTO releases(`+releaseColumns+`) VALUES(?, ?, ?, ?, ?, ?)`,
+			r.Name, r.Project, r.Label, r.Notes, r.URL, r.CreatedAt)
+		return err
+	})
+	if err != nil {
+		msg := strings.ToLower(err.Error())
+		if strings.Contains(msg, "unique") || strings.Contains(msg, "constraint failed") {
+			return Release{}, fmt.Errorf("release already exists: %s", r.Name)
+		}
+		return Release{}, fmt.Errorf("insert release: %w", err)
+	}
+	return r, nil
+}
+
+func (s *Store) GetRelease(ctx context.Context, project, name string) (Release, error) {
+	var r Release
+	err := s.db.QueryRowContext(ctx, `SELECT `+releaseColumns+` FROM releases WHERE project = ? AND name = ?`,
+		strings.TrimSpace(project), strings.TrimSpace(name)).
+		Scan(&r.Name, &r.Project, &r.Label, &r.Notes, &r.URL, &r.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Release{}, fmt.Errorf("release not found: %s", name)
+	}
+	if err != nil {
+		return Release{}, fmt.Errorf("get release: %w", err)
+	}
+	return r, nil
+}
+
+// ListReleases returns the releases of project, newest first. An empty
+// project lists every release.
+func (s *Store) ListReleases(ctx context.Context, project string) ([]Release, error) {
+	query := `SELECT ` + releaseColumns + ` FROM releases`
+	var args []any
+	if project = strings.TrimSpace(project); project != "" {
+		query += ` WHERE project = ?`
+		args = append(args, project)
+	}
+	rows, err := s.db.QueryContext(ctx, query+` ORDER BY created_at DESC, rowid DESC`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list releases: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]Release, 0)
+	for rows.Next() {
+		var r Release
+		if err := rows.Scan(&r.Name, &r.Project, &r.Label, &r.Notes, &r.URL, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan release: %w", err)
+		}
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate releases: %w", err)
+	}
+	return out, nil
+}
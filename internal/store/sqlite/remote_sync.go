@@ -0,0 +1,142 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Remote operation kinds queued while a remote is unreachable.
+const (
+	RemoteOpCreate = "create"
+	RemoteOpUpdate = "update"
+)
+
+// RemoteOp is a change made with --remote while the remote was unreachable,
+// waiting for `track push`. Payload is the JSON request body. For updates,
+// Base holds the remote's values of the changed fields as last seen, which
+// is how push and pull tell a conflicting remote edit from a stale copy.
+type RemoteOp struct {
+	ID        int64
+	Remote    string
+	Kind      string
+	IssueID   string
+	Payload   string
+	Base      string
+	CreatedAt string
+}
+
+// RemoteIssue is the last copy of a remote issue seen by this CLI, as the
+// API's JSON. It lets --remote reads work offline.
+type RemoteIssue struct {
+	Remote   string
+	IssueID  string
+	Data     string
+	PulledAt string
+}
+
+func (s *Store) QueueRemoteOp(ctx context.Context, op RemoteOp) (RemoteOp, error) {
+	if op.Kind != RemoteOpCreate && op.Kind != RemoteOpUpdate {
+		return RemoteOp{}, fmt.Errorf("invalid remote op: %s", op.Kind)
+	}
+	op.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	err := withSQLiteRetry(ctx, func() error {
+		res, err := s.db.ExecContext(ctx, `INSERT INTO remote_ops(remote, kind, issue_id, payload, base, created_at) VALUES(?, ?, ?, ?, ?, ?)`,
+			op.Remote, op.Kind, op.IssueID, op.Payload, op.Base, op.CreatedAt)
+		if err != nil {
+			return err
+		}
+		op.ID, err = res.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return RemoteOp{}, fmt.Errorf("queue remote op: %w", err)
+	}
+	return op, nil
+}
+
+// ListRemoteOps returns the queued operations for remote, oldest first.
+func (s *Store) ListRemoteOps(ctx context.Context, remote string) ([]RemoteOp, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, remote, kind, issue_id, payload, base, created_at FROM remote_ops WHERE remote = ? ORDER BY id`, remote)
+	if err != nil {
+		return nil, fmt.Errorf("list remote ops: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]RemoteOp, 0)
+	for rows.Next() {
+		var op RemoteOp
+		if err := rows.Scan(&op.ID, &op.Remote, &op.Kind, &op.IssueID, &op.Payload, &op.Base, &op.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan remote op: %w", err)
+		}
+		out = append(out, op)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate remote ops: %w", err)
+	}
+	return out, nil
+}
+
+// UpdateRemoteOp rewrites a queued operation's payload and base, after a
+// conflict was resolved.
+func (s *Store) UpdateRemoteOp(ctx context.Context, op RemoteOp) error {
+	return withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `UPDATE remote_ops SET payload = ?, base = ? WHERE id = ?`, op.Payload, op.Base, op.ID)
+		return err
+	})
+}
+
+func (s *Store) DeleteRemoteOp(ctx context.Context, id int64) error {
+	return withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `DELETE FROM remote_ops WHERE id = ?`, id)
+		return err
+	})
+}
+
+func (s *Store) PutRemoteIssue(ctx context.Context, remote, issueID, data string) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	return withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `INSERT INTO remote_issues(remote, issue_id, data, pulled_at) VALUES(?, ?, ?, ?)
+			ON CONFLICT(remote, issue_id) DO UPDATE SET data = excluded.data, pulled_at = excluded.pulled_at`,
+			remote, issueID, data, now)
+		return err
+	})
+}
+
+// GetRemoteIssue returns the cached copy of a remote issue. The second
+// return value is false when none was cached.
+func (s *Store) GetRemoteIssue(ctx context.Context, remote, issueID string) (RemoteIssue, bool, error) {
+	r := RemoteIssue{Remote: remote, IssueID: issueID}
+	err := s.db.QueryRowContext(ctx, `SELECT data, pulled_at FROM remote_issues WHERE remote = ? AND issue_id = ?`, remote, issueID).
+		Scan(&r.Data, &r.PulledAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return RemoteIssue{}, false, nil
+	}
+	if err != nil {
+		return RemoteIssue{}, false, fmt.Errorf("read remote issue: %w", err)
+	}
+	return r, true, nil
+}
+
+func (s *Store) ListRemoteIssues(ctx context.Context, remote string) ([]RemoteIssue, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT issue_id, data, pulled_at FROM remote_issues WHERE remote = ? ORDER BY issue_id`, remote)
+	if err != nil {
+		return nil, fmt.Errorf("list remote issues: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]RemoteIssue, 0)
+	for rows.Next() {
+		r := RemoteIssue{Remote: remote}
+		if err := rows.Scan(&r.IssueID, &r.Data, &r.PulledAt); err != nil {
+			return nil, fmt.Errorf("scan remote issue: %w", err)
+		}
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate remote issues: %w", err)
+	}
+	return out, nil
+}
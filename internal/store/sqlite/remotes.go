@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Remote is another track server reachable over its REST API, used by
+// `--remote <name>`. Token is the bearer token sent to it, if any, normally
+// a {{secret:...}} reference so the token itself stays out of the database.
+type Remote struct {
+	Name      string
+	URL       string
+	Token     string
+	CreatedAt string
+}
+
+func (s *Store) AddRemote(ctx context.Context, r Remote) error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return fmt.Errorf("remote name must not be empty")
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid remote url: %s", r.URL)
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	err = withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `INSERT INTO remotes(name, url, token, created_at) VALUES(?, ?, ?, ?)`,
+			r.Name, strings.TrimRight(r.URL, "/"), r.Token, now)
+		return err
+	})
+	if err != nil {
+		msg := strings.ToLower(err.Error())
+		if strings.Contains(msg, "unique") || strings.Contains(msg, "constraint failed") {
+			return fmt.Errorf("remote already exists: %s", r.Name)
+		}
+		return fmt.Errorf("insert remote: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) GetRemote(ctx context.Context, name string) (Remote, error) {
+	var r Remote
+	err := s.db.QueryRowContext(ctx, `SELECT name, url, token, created_at FROM remotes WHERE name = ?`, name).
+		Scan(&r.Name, &r.URL, &r.Token, &r.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Remote{}, fmt.Errorf("remote not found: %s", name)
+	}
+	if err != nil {
+		return Remote{}, fmt.Errorf("read remote: %w", err)
+	}
+	return r, nil
+}
+
+func (s *Store) ListRemotes(ctx context.Context) ([]Remote, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT name, url, token, created_at FROM remotes ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("list remotes: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]Remote, 0)
+	for rows.Next() {
+		var r Remote
+		if err := rows.Scan(&r.Name, &r.URL, &r.Token, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan remote: %w", err)
+		}
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate remotes: %w", err)
+	}
+	return out, nil
+}
+
+// SetRemoteToken replaces the token sent to the named remote.
+func (s *Store) SetRemoteToken(ctx context.Context, name, token string) error {
+	err := withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `UPDATE remotes SET token = ? WHERE name = ?`, token, name)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("update remote token: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) RemoveRemote(ctx context.Context, name string) error {
+	var res sql.Result
+	err := withSQLiteRetry(ctx, func() error {
+		var err error
+		res, err = s.db.ExecContext(ctx, `DELETE FROM remotes WHERE name = ?`, name)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("delete remote: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("remote not found: %s", name)
+	}
+	for _, table := range []string{"remote_ops", "remote_issues"} {
+		if err := withSQLiteRetry(ctx, func() error {
+			_, err := s.db.ExecContext(ctx, `DELETE FROM `+table+` WHERE remote = ?`, name)
+			return err
+		}); err != nil {
+			return fmt.Errorf("delete remote %s: %w", table, err)
+		}
+	}
+	return nil
+}
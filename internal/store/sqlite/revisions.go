@@ -0,0 +1,95 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Revision is a snapshot of an issue body. Revisions are numbered from 1 per
+// issue; a new one is stored each time the body changes.
+type Revision struct {
+	IssueID   string
+	Rev       int
+	Body      string
+	Actor     string
+	CreatedAt string
+}
+
+// recordBodyChange snapshots a changed body and records which of its
+// sections changed and which issues it now mentions.
+func (s *Store) recordBodyChange(ctx context.Context, issueID, before, after string) error {
+	if before == after {
+		return nil
+	}
+	if err := s.snapshotBody(ctx, issueID, before, after); err != nil {
+		return err
+	}
+	if err := s.setBodyMentions(ctx, issueID, after); err != nil {
+		return err
+	}
+	return s.recordSectionChanges(ctx, issueID, before, after)
+}
+
+// snapshotBody stores body as the issue's next revision. Issues created
+// before revisions were kept get their previous body stored first, without
+// an actor, so the first diff has a base.
+func (s *Store) snapshotBody(ctx context.Context, issueID, before, body string) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	actor := Actor(ctx)
+	err := withSQLiteRetry(ctx, func() error {
+		var last int
+		if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(rev), 0) FROM issue_revisions WHERE issue_id = ?`, issueID).Scan(&last); err != nil {
+			return err
+		}
+		if last == 0 && before != "" {
+			last++
+			if _, err := s.db.ExecContext(ctx, `INSERT INTO issue_revisions(issue_id, rev, body, actor, created_at) VALUES(?, ?, ?, '', ?)`, issueID, last, before, now); err != nil {
+				return err
+			}
+		}
+		_, err := s.db.ExecContext(ctx, `INSERT INTO issue_revisions(issue_id, rev, body, actor, created_at) VALUES(?, ?, ?, ?, ?)`, issueID, last+1, body, actor, now)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("snapshot issue body: %w", err)
+	}
+	return nil
+}
+
+// ListRevisions returns an issue's body revisions, oldest first.
+func (s *Store) ListRevisions(ctx context.Context, issueID string) ([]Revision, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT issue_id, rev, body, actor, created_at FROM issue_revisions WHERE issue_id = ? ORDER BY rev`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("list revisions: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]Revision, 0)
+	for rows.Next() {
+		var r Revision
+		if err := rows.Scan(&r.IssueID, &r.Rev, &r.Body, &r.Actor, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan revision: %w", err)
+		}
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate revisions: %w", err)
+	}
+	return out, nil
+}
+
+func (s *Store) GetRevision(ctx context.Context, issueID string, rev int) (Revision, error) {
+	r := Revision{IssueID: issueID, Rev: rev}
+	err := s.db.QueryRowContext(ctx, `SELECT body, actor, created_at FROM issue_revisions WHERE issue_id = ? AND rev = ?`, issueID, rev).
+		Scan(&r.Body, &r.Actor, &r.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Revision{}, fmt.Errorf("revision not found: %s@%d", issueID, rev)
+	}
+	if err != nil {
+		return Revision{}, fmt.Errorf("read revision: %w", err)
+	}
+	return r, nil
+}
@@ -0,0 +1,126 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Rule struct {
+	ID        int
+	Expr      string
+	Enabled   bool
+	CreatedAt string
+}
+
+func (s *Store) AddRule(ctx context.Context, expr string) (Rule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return Rule{}, fmt.Errorf("rule must not be empty")
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	res, err := s.db.ExecContext(ctx, `INSERT INTO rules(expr, enabled, created_at) VALUES(?, 1, ?)`, expr, now)
+	if err != nil {
+		return Rule{}, fmt.Errorf("insert rule: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return Rule{}, fmt.Errorf("read rule id: %w", err)
+	}
+	return Rule{ID: int(id), Expr: expr, Enabled: true, CreatedAt: now}, nil
+}
+
+func (s *Store) ListRules(ctx context.Context) ([]Rule, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, expr, enabled, created_at FROM rules ORDER BY id ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("list rules: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]Rule, 0)
+	for rows.Next() {
+		var (
+			r       Rule
+			enabled int
+		)
+		if err := rows.Scan(&r.ID, &r.Expr, &enabled, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan rule: %w", err)
+		}
+		r.Enabled = enabled != 0
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rules: %w", err)
+	}
+	return out, nil
+}
+
+func (s *Store) GetRule(ctx context.Context, id int) (Rule, error) {
+	var (
+		r       Rule
+		enabled int
+	)
+	err := s.db.QueryRowContext(ctx, `SELECT id, expr, enabled, created_at FROM rules WHERE id = ?`, id).Scan(&r.ID, &r.Expr, &enabled, &r.CreatedAt)
+	if err != nil {
+		return Rule{}, fmt.Errorf("rule not found: %d", id)
+	}
+	r.Enabled = enabled != 0
+	return r, nil
+}
+
+func (s *Store) RemoveRule(ctx context.Context, id int) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM rules WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete rule: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rule rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("rule not found: %d", id)
+	}
+	return nil
+}
+
+// NextRuleAssignee returns the pool member a round-robin rule assigns next
+// and advances the rule's cursor.
+func (s *Store) NextRuleAssignee(ctx context.Context, ruleID int, pool []string) (string, error) {
+	if len(pool) == 0 {
+		return "", fmt.Errorf("empty assignee pool")
+	}
+	var cursor int
+	err := withSQLiteRetry(ctx, func() error {
+		return s.db.QueryRowContext(ctx, `UPDATE rules SET assign_cursor = assign_cursor + 1 WHERE id = ? RETURNING assign_cursor - 1`, ruleID).Scan(&cursor)
+	})
+	if err != nil {
+		return "", fmt.Errorf("advance rule cursor: %w", err)
+	}
+	return pool[cursor%len(pool)], nil
+}
+
+// CountIssuesByAssignee counts the issues with the given status per assignee.
+func (s *Store) CountIssuesByAssignee(ctx context.Context, status string) (map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT assignee, COUNT(1) FROM issues WHERE status = ? GROUP BY assignee`, status)
+	if err != nil {
+		return nil, fmt.Errorf("count issues by assignee: %w", err)
+	}
+	defer rows.Close()
+
+	out := map[string]int{}
+	for rows.Next() {
+		var (
+			assignee string
+			n        int
+		)
+		if err := rows.Scan(&assignee, &n); err != nil {
+			return nil, fmt.Errorf("scan assignee count: %w", err)
+		}
+		out[assignee] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate assignee counts: %w", err)
+	}
+	return out, nil
+}
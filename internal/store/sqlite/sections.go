@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+// GetIssueSection returns the content of the named markdown section of an
+// issue body. The second return value is false when the section is absent.
+func (s *Store) GetIssueSection(ctx context.Context, id, title string) (string, bool, error) {
+	it, err := s.GetIssue(ctx, id)
+	if err != nil {
+		return "", false, err
+	}
+	sec, ok := issue.FindSection(it.Body, title)
+	return sec.Content, ok, nil
+}
+
+// SetIssueSection replaces the content of a named body section, creating it
+// when missing, in a single transaction.
+func (s *Store) SetIssueSection(ctx context.Context, id, title, content string) (issue.Item, error) {
+	return s.editIssueBody(ctx, id, func(body string) (string, error) {
+		return issue.SetSection(body, title, content), nil
+	})
+}
+
+// AppendIssueSection appends entry as a new line of a named body section in a
+// single transaction.
+func (s *Store) AppendIssueSection(ctx context.Context, id, title, entry string) (issue.Item, error) {
+	return s.editIssueBody(ctx, id, func(body string) (string, error) {
+		return issue.AppendToSection(body, title, entry), nil
+	})
+}
+
+// AnswerIssueQuestion records answer under the n-th question of the
+// Questions for user section in a single transaction.
+func (s *Store) AnswerIssueQuestion(ctx context.Context, id string, n int, answer string) (issue.Item, issue.Question, error) {
+	var answered issue.Question
+	it, err := s.editIssueBody(ctx, id, func(body string) (string, error) {
+		next, q, err := issue.AnswerQuestion(body, n, answer)
+		answered = q
+		return next, err
+	})
+	if err != nil {
+		return it, issue.Question{}, err
+	}
+	return it, answered, nil
+}
+
+func (s *Store) editIssueBody(ctx context.Context, id string, edit func(body string) (string, error)) (issue.Item, error) {
+	var before string
+	err := withSQLiteRetry(ctx, func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("begin tx: %w", err)
+		}
+
+		var body sql.NullString
+		if err := tx.QueryRowContext(ctx, `SELECT body FROM issues WHERE id = ?`, id).Scan(&body); err != nil {
+			_ = tx.Rollback()
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("issue not found")
+			}
+			return fmt.Errorf("read issue body: %w", err)
+		}
+
+		before = body.String
+		next, err := edit(body.String)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE issues SET body=?, updated_at=? WHERE id=?`, nullable(next), time.Now().UTC().Format(time.RFC3339), id); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("update issue body: %w", err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("commit issue body: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return issue.Item{}, err
+	}
+	it, err := s.GetIssue(ctx, id)
+	if err != nil {
+		return issue.Item{}, err
+	}
+	if err := s.RecordActivity(ctx, ActivityUpdated, it.ID, "body"); err != nil {
+		return it, err
+	}
+	if err := s.recordBodyChange(ctx, it.ID, before, it.Body); err != nil {
+		return it, err
+	}
+	return it, s.publish(ctx, it.ID, EventIssueUpdated)
+}
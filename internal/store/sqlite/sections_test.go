@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+func TestSetAndAppendIssueSection(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "sections", Status: issue.StatusTodo, Priority: "none", Body: "intro\n"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	if _, ok, err := store.GetIssueSection(ctx, it.ID, issue.SectionSpec); err != nil || ok {
+		t.Fatalf("GetIssueSection(missing) = ok=%v err=%v", ok, err)
+	}
+
+	updated, err := store.SetIssueSection(ctx, it.ID, issue.SectionSpec, "do it")
+	if err != nil {
+		t.Fatalf("SetIssueSection() error: %v", err)
+	}
+	if updated.Body != "intro\n\n## Spec\ndo it\n" {
+		t.Fatalf("unexpected body: %q", updated.Body)
+	}
+
+	if _, err := store.AppendIssueSection(ctx, it.ID, issue.SectionUserReplies, "- one"); err != nil {
+		t.Fatalf("AppendIssueSection(one) error: %v", err)
+	}
+	if _, err := store.AppendIssueSection(ctx, it.ID, issue.SectionUserReplies, "- two"); err != nil {
+		t.Fatalf("AppendIssueSection(two) error: %v", err)
+	}
+	content, ok, err := store.GetIssueSection(ctx, it.ID, issue.SectionUserReplies)
+	if err != nil || !ok || content != "- one\n- two" {
+		t.Fatalf("GetIssueSection(replies) = %q ok=%v err=%v", content, ok, err)
+	}
+
+	if _, err := store.SetIssueSection(ctx, "TRK-999", issue.SectionSpec, "x"); err == nil {
+		t.Fatalf("expected not found error")
+	}
+}
+
+func TestAnswerIssueQuestion(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "q", Status: issue.StatusTodo, Priority: "none", Body: "## Questions for user\n- one?\n- two?\n"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	updated, q, err := store.AnswerIssueQuestion(ctx, it.ID, 2, "yes")
+	if err != nil {
+		t.Fatalf("AnswerIssueQuestion() error: %v", err)
+	}
+	if q.Text != "two?" || updated.Body != "## Questions for user\n- one?\n- two?\n  - A: yes\n" {
+		t.Fatalf("unexpected answer result: %+v %q", q, updated.Body)
+	}
+	if _, _, err := store.AnswerIssueQuestion(ctx, it.ID, 3, "no"); err == nil {
+		t.Fatalf("expected out of range error")
+	}
+}
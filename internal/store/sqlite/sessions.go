@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+type Session struct {
+	ID         int
+	IssueID    string
+	Kind       string
+	Runner     string
+	LogPath    string
+	ExitCode   int
+	StartedAt  string
+	FinishedAt string
+}
+
+func (s *Store) AddSession(ctx context.Context, in Session) (Session, error) {
+	in.IssueID = strings.TrimSpace(in.IssueID)
+	if in.IssueID == "" {
+		return Session{}, fmt.Errorf("session issue id must not be empty")
+	}
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO sessions(issue_id, kind, runner, log_path, exit_code, started_at, finished_at)
+		VALUES(?, ?, ?, ?, ?, ?, ?)
+	`, in.IssueID, in.Kind, in.Runner, in.LogPath, in.ExitCode, in.StartedAt, in.FinishedAt)
+	if err != nil {
+		return Session{}, fmt.Errorf("insert session: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return Session{}, fmt.Errorf("read session id: %w", err)
+	}
+	in.ID = int(id)
+	return in, nil
+}
+
+func (s *Store) ListSessions(ctx context.Context, issueID string) ([]Session, error) {
+	query := `SELECT id, issue_id, kind, runner, log_path, exit_code, started_at, finished_at FROM sessions`
+	args := []any{}
+	if issueID != "" {
+		query += ` WHERE issue_id = ?`
+		args = append(args, issueID)
+	}
+	query += ` ORDER BY started_at ASC, id ASC`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]Session, 0)
+	for rows.Next() {
+		var sess Session
+		if err := rows.Scan(&sess.ID, &sess.IssueID, &sess.Kind, &sess.Runner, &sess.LogPath, &sess.ExitCode, &sess.StartedAt, &sess.FinishedAt); err != nil {
+			return nil, fmt.Errorf("scan session: %w", err)
+		}
+		out = append(out, sess)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate sessions: %w", err)
+	}
+	return out, nil
+}
+
+func (s *Store) GetSession(ctx context.Context, id int) (Session, error) {
+	var sess Session
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, issue_id, kind, runner, log_path, exit_code, started_at, finished_at
+		FROM sessions
+		WHERE id = ?
+	`, id).Scan(&sess.ID, &sess.IssueID, &sess.Kind, &sess.Runner, &sess.LogPath, &sess.ExitCode, &sess.StartedAt, &sess.FinishedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Session{}, fmt.Errorf("session not found: %d", id)
+		}
+		return Session{}, fmt.Errorf("get session: %w", err)
+	}
+	return sess, nil
+}
@@ -0,0 +1,47 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAddAndListSessions(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TRACK_HOME", tmp)
+
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	first, err := store.AddSession(ctx, Session{IssueID: "TRK-1", Kind: "run", Runner: "codex", LogPath: "/tmp/a.log", StartedAt: "2026-01-01T00:00:00Z", FinishedAt: "2026-01-01T00:01:00Z"})
+	if err != nil {
+		t.Fatalf("AddSession(first) error: %v", err)
+	}
+	if first.ID == 0 {
+		t.Fatalf("session id should be assigned")
+	}
+	if _, err := store.AddSession(ctx, Session{IssueID: "TRK-2", Kind: "run", Runner: "claude", LogPath: "/tmp/b.log", ExitCode: 1, StartedAt: "2026-01-02T00:00:00Z", FinishedAt: "2026-01-02T00:01:00Z"}); err != nil {
+		t.Fatalf("AddSession(second) error: %v", err)
+	}
+	if _, err := store.AddSession(ctx, Session{Kind: "run"}); err == nil {
+		t.Fatalf("AddSession() should reject empty issue id")
+	}
+
+	all, err := store.ListSessions(ctx, "")
+	if err != nil {
+		t.Fatalf("ListSessions(all) error: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("len(all) = %d, want 2", len(all))
+	}
+	one, err := store.ListSessions(ctx, "TRK-2")
+	if err != nil {
+		t.Fatalf("ListSessions(TRK-2) error: %v", err)
+	}
+	if len(one) != 1 || one[0].Runner != "claude" || one[0].ExitCode != 1 {
+		t.Fatalf("unexpected sessions: %+v", one)
+	}
+}
@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ShareSecret returns the key that signs share links, creating it on first
+// use. It is cached with the store's other hot reads.
+func (s *Store) ShareSecret(ctx context.Context) ([]byte, error) {
+	return s.cachedShareSecret(ctx)
+}
+
+func (s *Store) queryShareSecret(ctx context.Context) ([]byte, error) {
+	var v string
+	err := withSQLiteRetry(ctx, func() error {
+		return s.db.QueryRowContext(ctx, `SELECT value FROM meta WHERE key = 'share_secret'`).Scan(&v)
+	})
+	if err == nil {
+		return []byte(v), nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("read share secret: %w", err)
+	}
+
+	fresh, err := newShareSecret()
+	if err != nil {
+		return nil, err
+	}
+	err = withSQLiteRetry(ctx, func() error {
+		if _, err := s.db.ExecContext(ctx, `INSERT INTO meta(key, value) VALUES('share_secret', ?) ON CONFLICT(key) DO NOTHING`, fresh); err != nil {
+			return err
+		}
+		return s.db.QueryRowContext(ctx, `SELECT value FROM meta WHERE key = 'share_secret'`).Scan(&v)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create share secret: %w", err)
+	}
+	return []byte(v), nil
+}
+
+// RotateShareSecret replaces the share key, invalidating every link signed
+// with the old one.
+func (s *Store) RotateShareSecret(ctx context.Context) error {
+	fresh, err := newShareSecret()
+	if err != nil {
+		return err
+	}
+	err = withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `INSERT INTO meta(key, value) VALUES('share_secret', ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value`, fresh)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("rotate share secret: %w", err)
+	}
+	return nil
+}
+
+func newShareSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate share secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
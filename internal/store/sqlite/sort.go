@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sortKeys maps list sort keys to their ORDER BY expressions and whether
+// the key sorts descending when no direction is given.
+var sortKeys = map[string]struct {
+	expr string
+	desc bool
+}{
+	"priority": {expr: `CASE priority WHEN 'p0' THEN 0 WHEN 'p1' THEN 1 WHEN 'p2' THEN 2 WHEN 'p3' THEN 3 WHEN 'none' THEN 4 ELSE 5 END`},
+	"due":      {expr: `due`},
+	"manual":   {expr: `order_index`},
+	"title":    {expr: `title COLLATE NOCASE`},
+	"id":       {expr: `CAST(SUBSTR(id, INSTR(id, '-') + 1) AS INTEGER)`},
+	"updated":  {expr: `updated_at`, desc: true},
+	"created":  {expr: `created_at`, desc: true},
+}
+
+// SortKeys lists the accepted sort keys in help order.
+var SortKeys = []string{"priority", "due", "manual", "title", "id", "updated", "created"}
+
+// sortAliases are the single-word sorts older versions accepted.
+var sortAliases = map[string]string{
+	"priority":        "priority,updated",
+	"priority_manual": "priority,manual,updated",
+	"due":             "due,updated",
+	"manual":          "manual,updated",
+}
+
+// ValidateSort reports whether sort is a valid ListFilter.Sort value.
+func ValidateSort(sort string) error {
+	_, err := orderByClause(sort)
+	return err
+}
+
+// orderByClause builds the ORDER BY clause for a comma-separated sort such
+// as "priority,-updated". A "-" prefix sorts descending and "+" ascending;
+// without one, updated and created sort newest first and the other keys
+// ascending. Pinned issues always sort first, issues without a due date
+// always sort last, and ties fall back to the most recently updated issue.
+func orderByClause(sort string) (string, error) {
+	sort = strings.ToLower(strings.TrimSpace(sort))
+	if sort == "" {
+		return ` ORDER BY pinned DESC, updated_at DESC`, nil
+	}
+	if alias, ok := sortAliases[sort]; ok {
+		sort = alias
+	}
+
+	terms := []string{`pinned DESC`}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(sort, ",") {
+		part = strings.TrimSpace(part)
+		name := strings.TrimLeft(part, "+-")
+		key, ok := sortKeys[name]
+		if !ok || len(part)-len(name) > 1 {
+			return "", fmt.Errorf("invalid sort key %q (use %s, optionally prefixed with - or +, separated by commas)", part, strings.Join(SortKeys, ", "))
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		desc := key.desc
+		switch part[0] {
+		case '-':
+			desc = true
+		case '+':
+			desc = false
+		}
+		if name == "due" {
+			terms = append(terms, `CASE WHEN due IS NULL OR due = '' THEN 1 ELSE 0 END`)
+		}
+		if desc {
+			terms = append(terms, key.expr+` DESC`)
+		} else {
+			terms = append(terms, key.expr+` ASC`)
+		}
+	}
+	if !seen["updated"] {
+		terms = append(terms, `updated_at DESC`)
+	}
+	return ` ORDER BY ` + strings.Join(terms, `, `), nil
+}
@@ -13,7 +13,13 @@ import (
 
 var statusNameRe = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
 
+// ListStatuses returns the built-in statuses in workflow order, then the
+// custom ones by name. The list is cached until the database changes.
 func (s *Store) ListStatuses(ctx context.Context) ([]string, error) {
+	return s.cachedStatuses(ctx)
+}
+
+func (s *Store) queryStatuses(ctx context.Context) ([]string, error) {
 	rows, err := s.db.QueryContext(ctx, `
 		SELECT name
 		FROM statuses
@@ -18,8 +18,25 @@ const (
 	issueIDPrefix = "TRK"
 )
 
+// maxOpenConns bounds each store's connection pool. WAL lets its readers run
+// alongside one writer, so concurrent agents sharing a store only queue up
+// for writes.
+const maxOpenConns = 4
+
+// connParams configures every pooled connection: writers wait up to five
+// seconds for each other, and transactions take the write lock when they
+// begin, so one that reads before writing waits out busy_timeout instead of
+// failing to upgrade.
+const connParams = "?_pragma=busy_timeout(5000)&_pragma=synchronous(NORMAL)&_txlock=immediate"
+
+// SchemaVersion is the schema this build migrates databases to. Bump it
+// whenever initSchema gains a table or column.
+const SchemaVersion = 21
+
 type Store struct {
-	db *sql.DB
+	db    *sql.DB
+	stmts stmtCache
+	reads readCache
 }
 
 func DBPath() (string, error) {
@@ -40,12 +57,13 @@ func Open(ctx context.Context) (*Store, error) {
 		return nil, err
 	}
 
-	db, err := sql.Open(driverName, path)
+	db, err := sql.Open(driverName, path+connParams)
 	if err != nil {
 		return nil, fmt.Errorf("open sqlite: %w", err)
 	}
 
-	db.SetMaxOpenConns(1)
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxOpenConns)
 	db.SetConnMaxLifetime(0)
 	db.SetConnMaxIdleTime(0)
 
@@ -61,16 +79,25 @@ func Open(ctx context.Context) (*Store, error) {
 	}
 
 	s := &Store{db: db}
+	prev, err := s.storedSchemaVersion(ctx)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
 	if err := s.initSchema(ctx); err != nil {
 		_ = db.Close()
 		return nil, err
 	}
+	if err := s.initHookApprovals(ctx, prev); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
 
 	return s, nil
 }
 
 func (s *Store) Close() error {
-	return s.db.Close()
+	return errors.Join(s.stmts.close(), s.db.Close())
 }
 
 func (s *Store) initSchema(ctx context.Context) error {
@@ -140,6 +167,188 @@ func (s *Store) initSchema(ctx context.Context) error {
 			created_at TEXT NOT NULL,
 			updated_at TEXT NOT NULL
 		);`,
+		`CREATE TABLE IF NOT EXISTS sessions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			issue_id TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			runner TEXT NOT NULL,
+			log_path TEXT NOT NULL,
+			exit_code INTEGER NOT NULL DEFAULT 0,
+			started_at TEXT NOT NULL,
+			finished_at TEXT NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS automations (
+			name TEXT PRIMARY KEY,
+			enabled INTEGER NOT NULL DEFAULT 0,
+			settings_json TEXT NOT NULL DEFAULT '{}',
+			updated_at TEXT NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS rules (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			expr TEXT NOT NULL,
+			enabled INTEGER NOT NULL DEFAULT 1,
+			created_at TEXT NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS jobs (
+			name TEXT PRIMARY KEY,
+			enabled INTEGER NOT NULL DEFAULT 0,
+			interval_seconds INTEGER NOT NULL DEFAULT 0,
+			last_run_at TEXT,
+			last_error TEXT
+		);`,
+		`CREATE TABLE IF NOT EXISTS job_marks (
+			job TEXT NOT NULL,
+			key TEXT NOT NULL,
+			created_at TEXT NOT NULL,
+			PRIMARY KEY(job, key)
+		);`,
+		`CREATE TABLE IF NOT EXISTS recurring_issues (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			title TEXT NOT NULL,
+			body TEXT,
+			priority TEXT NOT NULL DEFAULT 'none',
+			labels_json TEXT NOT NULL DEFAULT '[]',
+			every TEXT NOT NULL,
+			next_run_at TEXT NOT NULL,
+			created_at TEXT NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS hook_runs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			hook_id INTEGER NOT NULL,
+			event TEXT NOT NULL,
+			issue_id TEXT,
+			success INTEGER NOT NULL DEFAULT 0,
+			error TEXT,
+			ran_at TEXT NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS idempotency_keys (
+			key TEXT PRIMARY KEY,
+			fingerprint TEXT NOT NULL,
+			issue_id TEXT NOT NULL,
+			created_at TEXT NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS activity (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			kind TEXT NOT NULL,
+			issue_id TEXT NOT NULL,
+			detail TEXT NOT NULL DEFAULT '',
+			actor TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS api_tokens (
+			name TEXT PRIMARY KEY,
+			token_hash TEXT NOT NULL UNIQUE,
+			role TEXT NOT NULL,
+			created_at TEXT NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS remotes (
+			name TEXT PRIMARY KEY,
+			url TEXT NOT NULL,
+			token TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS remote_ops (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			remote TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			issue_id TEXT NOT NULL DEFAULT '',
+			payload TEXT NOT NULL,
+			base TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS remote_issues (
+			remote TEXT NOT NULL,
+			issue_id TEXT NOT NULL,
+			data TEXT NOT NULL,
+			pulled_at TEXT NOT NULL,
+			PRIMARY KEY(remote, issue_id)
+		);`,
+		`CREATE TABLE IF NOT EXISTS issue_revisions (
+			issue_id TEXT NOT NULL,
+			rev INTEGER NOT NULL,
+			body TEXT NOT NULL,
+			actor TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL,
+			PRIMARY KEY(issue_id, rev)
+		);`,
+		`CREATE TABLE IF NOT EXISTS time_entries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			issue_id TEXT NOT NULL,
+			started_at TEXT NOT NULL,
+			ended_at TEXT NOT NULL,
+			actor TEXT NOT NULL DEFAULT ''
+		);`,
+		`CREATE TABLE IF NOT EXISTS issue_links (
+			issue_id TEXT NOT NULL,
+			url TEXT NOT NULL,
+			title TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL,
+			PRIMARY KEY(issue_id, url)
+		);`,
+		`CREATE TABLE IF NOT EXISTS project_dependencies (
+			project_key TEXT NOT NULL,
+			depends_on TEXT NOT NULL,
+			created_at TEXT NOT NULL,
+			PRIMARY KEY(project_key, depends_on)
+		);`,
+		`CREATE TABLE IF NOT EXISTS people (
+			name TEXT PRIMARY KEY,
+			email TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS agent_leases (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			agent TEXT NOT NULL,
+			issue_id TEXT NOT NULL,
+			pid INTEGER NOT NULL,
+			started_at TEXT NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS issue_mentions (
+			source_id TEXT NOT NULL,
+			target_id TEXT NOT NULL,
+			origin TEXT NOT NULL,
+			created_at TEXT NOT NULL,
+			PRIMARY KEY(source_id, target_id, origin)
+		);`,
+		`CREATE TABLE IF NOT EXISTS templates (
+			name TEXT PRIMARY KEY,
+			description TEXT NOT NULL DEFAULT '',
+			title TEXT NOT NULL DEFAULT '',
+			labels_json TEXT NOT NULL DEFAULT '[]',
+			assignee TEXT NOT NULL DEFAULT '',
+			body TEXT NOT NULL DEFAULT '',
+			source TEXT NOT NULL DEFAULT '',
+			updated_at TEXT NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS releases (
+			name TEXT NOT NULL,
+			project TEXT NOT NULL DEFAULT '',
+			label TEXT NOT NULL,
+			notes TEXT NOT NULL DEFAULT '',
+			url TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL,
+			PRIMARY KEY(project, name)
+		);`,
+		`CREATE TABLE IF NOT EXISTS issue_dependencies (
+			issue_id TEXT NOT NULL,
+			depends_on TEXT NOT NULL,
+			created_at TEXT NOT NULL,
+			PRIMARY KEY(issue_id, depends_on)
+		);`,
+		`CREATE TABLE IF NOT EXISTS issue_blocks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			issue_id TEXT NOT NULL,
+			reason TEXT NOT NULL DEFAULT '',
+			since TEXT NOT NULL,
+			until TEXT NOT NULL DEFAULT ''
+		);`,
+		`CREATE TABLE IF NOT EXISTS comments (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			issue_id TEXT NOT NULL,
+			author TEXT NOT NULL DEFAULT '',
+			body TEXT NOT NULL,
+			created_at TEXT NOT NULL
+		);`,
 		`INSERT INTO statuses(name, system, created_at, updated_at)
 		 VALUES('todo', 1, datetime('now'), datetime('now'))
 		 ON CONFLICT(name) DO NOTHING;`,
@@ -168,39 +377,168 @@ func (s *Store) initSchema(ctx context.Context) error {
 		}
 	}
 
+	columns := []struct {
+		table, name, decl string
+	}{
+		{"hooks", "enabled", "INTEGER NOT NULL DEFAULT 1"},
+		{"hooks", "order_index", "INTEGER NOT NULL DEFAULT 0"},
+		{"activity", "actor", "TEXT NOT NULL DEFAULT ''"},
+		{"issues", "pinned", "INTEGER NOT NULL DEFAULT 0"},
+		{"issues", "estimate", "TEXT NOT NULL DEFAULT ''"},
+		{"projects", "default_labels_json", "TEXT NOT NULL DEFAULT '[]'"},
+		{"projects", "default_priority", "TEXT NOT NULL DEFAULT ''"},
+		{"projects", "default_assignee", "TEXT NOT NULL DEFAULT ''"},
+		{"projects", "order_index", "INTEGER NOT NULL DEFAULT 0"},
+		{"projects", "branch_prefix", "TEXT NOT NULL DEFAULT ''"},
+		{"people", "kind", "TEXT NOT NULL DEFAULT 'person'"},
+		{"people", "runner", "TEXT NOT NULL DEFAULT ''"},
+		{"people", "sandbox", "TEXT NOT NULL DEFAULT ''"},
+		{"people", "max_concurrency", "INTEGER NOT NULL DEFAULT 0"},
+		{"rules", "assign_cursor", "INTEGER NOT NULL DEFAULT 0"},
+		{"issues", "external_id", "TEXT"},
+	}
+	for _, c := range columns {
+		if err := s.ensureColumn(ctx, c.table, c.name, c.decl); err != nil {
+			return fmt.Errorf("init schema: %w", err)
+		}
+	}
+	if err := withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `CREATE UNIQUE INDEX IF NOT EXISTS issues_external_id ON issues(external_id) WHERE external_id IS NOT NULL`)
+		return err
+	}); err != nil {
+		return fmt.Errorf("init schema: %w", err)
+	}
+
+	// Mentions are tracked from schema 9; earlier databases get their
+	// existing bodies scanned once.
+	if v, err := s.SchemaVersion(ctx); err != nil {
+		return fmt.Errorf("init schema: %w", err)
+	} else if v < 9 {
+		if err := s.backfillMentions(ctx); err != nil {
+			return fmt.Errorf("init schema: %w", err)
+		}
+	}
+
+	// Record the version last, so a failed migration never claims to be
+	// complete. A newer version written by a newer build is left alone.
+	if err := withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `INSERT INTO meta(key, value) VALUES('schema_version', ?)
+			ON CONFLICT(key) DO UPDATE SET value = excluded.value
+			WHERE CAST(meta.value AS INTEGER) < CAST(excluded.value AS INTEGER)`, fmt.Sprint(SchemaVersion))
+		return err
+	}); err != nil {
+		return fmt.Errorf("init schema: %w", err)
+	}
+
 	return nil
 }
 
-func (s *Store) NextIssueID(ctx context.Context) (string, error) {
-	var id string
+// storedSchemaVersion is SchemaVersion for a database Open has not
+// migrated yet, which may not even have a meta table.
+func (s *Store) storedSchemaVersion(ctx context.Context) (int, error) {
+	var n int
 	err := withSQLiteRetry(ctx, func() error {
-		tx, err := s.db.BeginTx(ctx, nil)
-		if err != nil {
-			return fmt.Errorf("begin tx: %w", err)
-		}
+		return s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'meta'`).Scan(&n)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	return s.SchemaVersion(ctx)
+}
 
-		var n int
-		if err := tx.QueryRowContext(ctx, `SELECT value FROM meta WHERE key = 'next_issue_number'`).Scan(&n); err != nil {
-			_ = tx.Rollback()
-			return fmt.Errorf("read next issue number: %w", err)
-		}
+// SchemaVersion returns the schema version recorded in the database, or 0
+// when no migration has completed yet.
+func (s *Store) SchemaVersion(ctx context.Context) (int, error) {
+	var v string
+	err := withSQLiteRetry(ctx, func() error {
+		return s.db.QueryRowContext(ctx, `SELECT value FROM meta WHERE key = 'schema_version'`).Scan(&v)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	var n int
+	if _, err := fmt.Sscanf(v, "%d", &n); err != nil {
+		return 0, fmt.Errorf("invalid schema version: %q", v)
+	}
+	return n, nil
+}
 
-		if _, err := tx.ExecContext(ctx, `UPDATE meta SET value = ? WHERE key = 'next_issue_number'`, n+1); err != nil {
-			_ = tx.Rollback()
-			return fmt.Errorf("update next issue number: %w", err)
+// ensureColumn adds a column to an existing table when databases created by
+// older versions do not have it yet.
+func (s *Store) ensureColumn(ctx context.Context, table, column, decl string) error {
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`PRAGMA table_info(%s)`, table))
+	if err != nil {
+		return fmt.Errorf("read %s columns: %w", table, err)
+	}
+	found := false
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scan %s columns: %w", table, err)
 		}
-
-		if err := tx.Commit(); err != nil {
-			return fmt.Errorf("commit next issue number: %w", err)
+		if name == column {
+			found = true
 		}
-
-		id = fmt.Sprintf("%s-%d", issueIDPrefix, n)
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("close %s columns: %w", table, err)
+	}
+	if found {
 		return nil
+	}
+	return withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, decl))
+		// Another process opening the same database may have added it
+		// since the check above.
+		if err != nil && strings.Contains(err.Error(), "duplicate column name") {
+			return nil
+		}
+		return err
+	})
+}
+
+// NextIssueID allocates the next issue ID. The counter is bumped and read
+// in one statement, so concurrent writers never get the same ID.
+func (s *Store) NextIssueID(ctx context.Context) (string, error) {
+	var n int
+	err := withSQLiteRetry(ctx, func() error {
+		var err error
+		n, err = reserveIssueNumbers(ctx, s.db, 1)
+		return err
 	})
 	if err != nil {
 		return "", err
 	}
-	return id, nil
+	return fmt.Sprintf("%s-%d", issueIDPrefix, n), nil
+}
+
+// rowQuerier is a *sql.DB or *sql.Tx.
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// reserveIssueNumbers takes n consecutive numbers from the issue counter and
+// returns the first.
+func reserveIssueNumbers(ctx context.Context, q rowQuerier, n int) (int, error) {
+	var first int
+	if err := q.QueryRowContext(ctx, `UPDATE meta SET value = CAST(value AS INTEGER) + ? WHERE key = 'next_issue_number' RETURNING CAST(value AS INTEGER) - ?`, n, n).Scan(&first); err != nil {
+		return 0, fmt.Errorf("reserve issue numbers: %w", err)
+	}
+	return first, nil
 }
 
 func (s *Store) Ping(ctx context.Context) error {
@@ -210,8 +548,6 @@ func (s *Store) Ping(ctx context.Context) error {
 func applySQLitePragmas(ctx context.Context, db *sql.DB) error {
 	pragmas := []string{
 		`PRAGMA journal_mode=WAL;`,
-		`PRAGMA synchronous=NORMAL;`,
-		`PRAGMA busy_timeout=5000;`,
 	}
 	for _, stmt := range pragmas {
 		if err := withSQLiteRetry(ctx, func() error {
@@ -184,3 +184,60 @@ func TestConcurrentCreateIssueAvoidsLockedErrors(t *testing.T) {
 		t.Fatalf("len(items) = %d, want %d", len(items), want)
 	}
 }
+
+func TestSharedStoreConcurrentCreatesGetUniqueIDs(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	const workers = 8
+	const perWorker = 10
+	ids := make(chan string, workers*perWorker)
+	errCh := make(chan error, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				it, err := store.CreateIssue(ctx, issue.Item{Title: fmt.Sprintf("w%d-%d", w, i), Status: issue.StatusTodo, Priority: "p2"})
+				if err != nil {
+					errCh <- err
+					return
+				}
+				ids <- it.ID
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+	close(errCh)
+	for err := range errCh {
+		t.Fatalf("concurrent CreateIssue() error: %v", err)
+	}
+
+	seen := map[string]bool{}
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("ID %s was allocated twice", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != workers*perWorker {
+		t.Fatalf("allocated %d IDs, want %d", len(seen), workers*perWorker)
+	}
+	var orders int
+	if err := store.db.QueryRowContext(ctx, `SELECT COUNT(DISTINCT order_index) FROM issues`).Scan(&orders); err != nil {
+		t.Fatalf("count order indexes: %v", err)
+	}
+	if orders != workers*perWorker {
+		t.Fatalf("distinct order indexes = %d, want %d", orders, workers*perWorker)
+	}
+	if id, err := store.NextIssueID(ctx); err != nil || id != fmt.Sprintf("TRK-%d", workers*perWorker+1) {
+		t.Fatalf("NextIssueID() = %s, %v", id, err)
+	}
+}
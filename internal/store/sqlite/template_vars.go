@@ -0,0 +1,43 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+// TemplateVars returns the live values issue.ExpandTemplate substitutes into
+// an issue's next action and body: due, branch, and pr_url. Values the issue
+// does not have are omitted.
+func (s *Store) TemplateVars(ctx context.Context, it issue.Item) (map[string]string, error) {
+	vars := map[string]string{"due": it.Due}
+	branch, err := s.GetGitBranchLink(ctx, it.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	vars["branch"] = branch.BranchName
+	pr, err := s.GetGitHubLink(ctx, it.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	vars["pr_url"] = pullRequestURL(pr)
+	return vars, nil
+}
+
+// pullRequestURL builds a GitHub URL for a linked PR. Refs stored as a bare
+// number need the link's repo.
+func pullRequestURL(link GitHubLink) string {
+	ref := strings.TrimSpace(link.PRRef)
+	switch {
+	case ref == "":
+		return ""
+	case strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://"):
+		return ref
+	case link.Repo != "":
+		return "https://github.com/" + link.Repo + "/pull/" + strings.TrimPrefix(ref, "#")
+	}
+	return ""
+}
@@ -0,0 +1,129 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+var templateNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// Template prefills new issues: `track new --template NAME` puts Title in
+// front of the issue title and starts it with Labels, Assignee, and Body.
+// Source records the file a template was imported from, if any.
+type Template struct {
+	Name        string
+	Description string
+	Title       string
+	Labels      []string
+	Assignee    string
+	Body        string
+	Source      string
+	UpdatedAt   string
+}
+
+const templateColumns = `name, description, title, labels_json, assignee, body, source, updated_at`
+
+// SaveTemplate creates or replaces the template with t.Name.
+func (s *Store) SaveTemplate(ctx context.Context, t Template) error {
+	t.Name = strings.TrimSpace(t.Name)
+	if !templateNameRe.MatchString(t.Name) {
+		return fmt.Errorf("invalid template name: %q", t.Name)
+	}
+	next := make([]string, 0, len(t.Labels))
+	for _, label := range t.Labels {
+		if label = strings.TrimSpace(label); label != "" && !slices.Contains(next, label) {
+			next = append(next, label)
+		}
+	}
+	labels, err := json.Marshal(next)
+	if err != nil {
+		return fmt.Errorf("marshal labels: %w", err)
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	err = withSQLiteRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, `
+			INSERT INTO templates(name, description, title, labels_json, assignee, body, source, updated_at)
+			VALUES(?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(name) DO UPDATE SET
+				description = excluded.description,
+				title = excluded.title,
+				labels_json = excluded.labels_json,
+				assignee = excluded.assignee,
+				body = excluded.body,
+				source = excluded.source,
+				updated_at = excluded.updated_at`,
+			t.Name, strings.TrimSpace(t.Description), t.Title, string(labels), issue.NormalizeAssignee(t.Assignee), t.Body, t.Source, now)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("save template: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) GetTemplate(ctx context.Context, name string) (Template, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+templateColumns+` FROM templates WHERE name = ?`, strings.TrimSpace(name))
+	t, err := scanTemplate(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Template{}, fmt.Errorf("template not found: %s", name)
+	}
+	if err != nil {
+		return Template{}, fmt.Errorf("get template: %w", err)
+	}
+	return t, nil
+}
+
+func (s *Store) ListTemplates(ctx context.Context) ([]Template, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+templateColumns+` FROM templates ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("list templates: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]Template, 0)
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan template: %w", err)
+		}
+		out = append(out, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate templates: %w", err)
+	}
+	return out, nil
+}
+
+func (s *Store) RemoveTemplate(ctx context.Context, name string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM templates WHERE name = ?`, strings.TrimSpace(name))
+	if err != nil {
+		return fmt.Errorf("delete template: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("template not found: %s", name)
+	}
+	return nil
+}
+
+func scanTemplate(row interface{ Scan(...any) error }) (Template, error) {
+	var (
+		t      Template
+		labels string
+	)
+	if err := row.Scan(&t.Name, &t.Description, &t.Title, &labels, &t.Assignee, &t.Body, &t.Source, &t.UpdatedAt); err != nil {
+		return Template{}, err
+	}
+	if err := json.Unmarshal([]byte(labels), &t.Labels); err != nil {
+		return Template{}, fmt.Errorf("decode labels: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+// TimeEntry is an interval of work logged against an issue, attributed to
+// Actor(ctx) when it was logged.
+type TimeEntry struct {
+	ID        int
+	IssueID   string
+	StartedAt string
+	EndedAt   string
+	Actor     string
+}
+
+// Duration is how long the entry lasted.
+func (e TimeEntry) Duration() time.Duration {
+	start, err1 := time.Parse(time.RFC3339, e.StartedAt)
+	end, err2 := time.Parse(time.RFC3339, e.EndedAt)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// LogTime records work on an issue from start to end.
+func (s *Store) LogTime(ctx context.Context, issueID string, start, end time.Time) (TimeEntry, error) {
+	if end.Before(start) {
+		return TimeEntry{}, fmt.Errorf("time entry ends before it starts")
+	}
+	if _, err := s.GetIssue(ctx, issueID); err != nil {
+		return TimeEntry{}, err
+	}
+	e := TimeEntry{
+		IssueID:   issueID,
+		StartedAt: start.UTC().Format(time.RFC3339),
+		EndedAt:   end.UTC().Format(time.RFC3339),
+		Actor:     Actor(ctx),
+	}
+	err := withSQLiteRetry(ctx, func() error {
+		res, err := s.db.ExecContext(ctx, `INSERT INTO time_entries(issue_id, started_at, ended_at, actor) VALUES(?, ?, ?, ?)`,
+			e.IssueID, e.StartedAt, e.EndedAt, e.Actor)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		e.ID = int(id)
+		return err
+	})
+	if err != nil {
+		return TimeEntry{}, fmt.Errorf("log time: %w", err)
+	}
+	if err := s.RecordActivity(ctx, ActivityTime, issueID, issue.FormatEstimate(e.Duration().Round(time.Minute))); err != nil {
+		return e, err
+	}
+	return e, nil
+}
+
+// ListTimeEntries returns the work logged against an issue, oldest first.
+func (s *Store) ListTimeEntries(ctx context.Context, issueID string) ([]TimeEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, issue_id, started_at, ended_at, actor FROM time_entries WHERE issue_id = ? ORDER BY id`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("list time entries: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]TimeEntry, 0)
+	for rows.Next() {
+		var e TimeEntry
+		if err := rows.Scan(&e.ID, &e.IssueID, &e.StartedAt, &e.EndedAt, &e.Actor); err != nil {
+			return nil, fmt.Errorf("scan time entry: %w", err)
+		}
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate time entries: %w", err)
+	}
+	return out, nil
+}
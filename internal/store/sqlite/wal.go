@@ -0,0 +1,118 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// FileSizes reports the size in bytes of the database file and of its
+// write-ahead log. A missing WAL counts as empty.
+func FileSizes() (db, wal int64, err error) {
+	path, err := DBPath()
+	if err != nil {
+		return 0, 0, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, 0, fmt.Errorf("stat database: %w", err)
+	}
+	db = info.Size()
+	info, err = os.Stat(path + "-wal")
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return db, 0, nil
+	case err != nil:
+		return 0, 0, fmt.Errorf("stat wal: %w", err)
+	}
+	return db, info.Size(), nil
+}
+
+// CheckpointResult is what PRAGMA wal_checkpoint reports. Busy is set when
+// readers kept the checkpoint from finishing, in which case the WAL was not
+// truncated.
+type CheckpointResult struct {
+	Busy         bool
+	LogFrames    int
+	Checkpointed int
+}
+
+// Checkpoint copies the WAL back into the database and truncates it. It
+// waits up to the busy timeout for other connections' readers and writers.
+func (s *Store) Checkpoint(ctx context.Context) (CheckpointResult, error) {
+	var (
+		res  CheckpointResult
+		busy int
+	)
+	err := withSQLiteRetry(ctx, func() error {
+		return s.db.QueryRowContext(ctx, `PRAGMA wal_checkpoint(TRUNCATE)`).Scan(&busy, &res.LogFrames, &res.Checkpointed)
+	})
+	if err != nil {
+		return CheckpointResult{}, fmt.Errorf("wal checkpoint: %w", err)
+	}
+	res.Busy = busy != 0
+	return res, nil
+}
+
+// Checkpoint counts of this process's MaintainLoop, for /metrics.
+var checkpointsDone, checkpointsBusy, checkpointsFailed atomic.Uint64
+
+// CheckpointCounts reports how many periodic checkpoints truncated the WAL,
+// were kept from it by readers, and failed.
+func CheckpointCounts() (done, busy, failed uint64) {
+	return checkpointsDone.Load(), checkpointsBusy.Load(), checkpointsFailed.Load()
+}
+
+// MaintainLoop keeps the database healthy for a long-running process until
+// ctx is done. Every tick it checks that its connection still answers,
+// reopening it when it does not, and checkpoints the WAL with TRUNCATE so
+// that CLI writes made alongside the process do not grow it without bound.
+// Failures go to errOut and are retried on the next tick.
+func MaintainLoop(ctx context.Context, tick time.Duration, errOut io.Writer) error {
+	var store *Store
+	defer func() {
+		if store != nil {
+			_ = store.Close()
+		}
+	}()
+
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if store != nil {
+			if err := store.Ping(ctx); err != nil {
+				fmt.Fprintf(errOut, "db maintenance: connection check failed, reopening: %v\n", err)
+				_ = store.Close()
+				store = nil
+			}
+		}
+		if store == nil {
+			var err error
+			if store, err = Open(ctx); err != nil {
+				fmt.Fprintf(errOut, "db maintenance: %v\n", err)
+				continue
+			}
+		}
+		res, err := store.Checkpoint(ctx)
+		switch {
+		case err != nil:
+			checkpointsFailed.Add(1)
+			fmt.Fprintf(errOut, "db maintenance: %v\n", err)
+		case res.Busy:
+			checkpointsBusy.Add(1)
+			fmt.Fprintf(errOut, "db maintenance: wal checkpoint blocked by readers after %d of %d frames; retrying next tick\n", res.Checkpointed, res.LogFrames)
+		default:
+			checkpointsDone.Add(1)
+		}
+	}
+}
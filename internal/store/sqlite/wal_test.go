@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+)
+
+func TestCheckpointTruncatesWAL(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	for i := 0; i < 20; i++ {
+		if _, err := store.CreateIssue(ctx, issue.Item{Title: "A", Status: issue.StatusTodo, Priority: "p2"}); err != nil {
+			t.Fatalf("CreateIssue() error: %v", err)
+		}
+	}
+	if _, wal, err := FileSizes(); err != nil || wal == 0 {
+		t.Fatalf("FileSizes() wal = %d, %v; want a non-empty WAL", wal, err)
+	}
+	res, err := store.Checkpoint(ctx)
+	if err != nil {
+		t.Fatalf("Checkpoint() error: %v", err)
+	}
+	if res.Busy || res.Checkpointed != res.LogFrames {
+		t.Fatalf("Checkpoint() = %+v", res)
+	}
+	db, wal, err := FileSizes()
+	if err != nil || wal != 0 || db == 0 {
+		t.Fatalf("FileSizes() after checkpoint = %d, %d, %v", db, wal, err)
+	}
+}
+
+func TestMaintainLoopCheckpoints(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	if _, err := store.CreateIssue(ctx, issue.Item{Title: "A", Status: issue.StatusTodo, Priority: "p2"}); err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	before, _, _ := CheckpointCounts()
+	var errOut bytes.Buffer
+	done := make(chan error, 1)
+	go func() { done <- MaintainLoop(ctx, 10*time.Millisecond, &errOut) }()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if n, _, _ := CheckpointCounts(); n > before {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("no checkpoint ran; errors: %s", errOut.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("MaintainLoop() error: %v", err)
+	}
+	if _, wal, err := FileSizes(); err != nil || wal != 0 {
+		t.Fatalf("FileSizes() wal = %d, %v; want it truncated", wal, err)
+	}
+}
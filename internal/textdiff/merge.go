@@ -0,0 +1,116 @@
+package textdiff
+
+import (
+	"slices"
+	"strings"
+)
+
+// Conflict markers written by Merge3 around changes both sides made.
+const (
+	MarkerOurs   = "<<<<<<< yours"
+	MarkerSep    = "======="
+	MarkerTheirs = ">>>>>>> theirs"
+)
+
+// change replaces base[start:end] with lines.
+type change struct {
+	start, end int
+	lines      []string
+}
+
+// Merge3 merges the line changes ours and theirs each made to base. Changes
+// that overlap or touch and differ are kept side by side between conflict
+// markers; clean is false when there are any. The result has no trailing
+// newline.
+func Merge3(base, ours, theirs string) (merged string, clean bool) {
+	b := splitLines(base)
+	a, t := changes(b, splitLines(ours)), changes(b, splitLines(theirs))
+
+	var out []string
+	clean = true
+	pos, i, j := 0, 0, 0
+	for i < len(a) || j < len(t) {
+		start := 0
+		if j >= len(t) || (i < len(a) && a[i].start <= t[j].start) {
+			start = a[i].start
+		} else {
+			start = t[j].start
+		}
+		// Grow the region until no change on either side touches it.
+		end, ai, tj := start, i, j
+		for grew := true; grew; {
+			grew = false
+			for ; ai < len(a) && a[ai].start <= end; ai++ {
+				end, grew = max(end, a[ai].end), true
+			}
+			for ; tj < len(t) && t[tj].start <= end; tj++ {
+				end, grew = max(end, t[tj].end), true
+			}
+		}
+
+		out = append(out, b[pos:start]...)
+		oursRegion := applyChanges(b, start, end, a[i:ai])
+		theirsRegion := applyChanges(b, start, end, t[j:tj])
+		switch {
+		case ai == i:
+			out = append(out, theirsRegion...)
+		case tj == j || slices.Equal(oursRegion, theirsRegion):
+			out = append(out, oursRegion...)
+		default:
+			clean = false
+			out = append(out, MarkerOurs)
+			out = append(out, oursRegion...)
+			out = append(out, MarkerSep)
+			out = append(out, theirsRegion...)
+			out = append(out, MarkerTheirs)
+		}
+		pos, i, j = end, ai, tj
+	}
+	out = append(out, b[pos:]...)
+	return strings.Join(out, "\n"), clean
+}
+
+// changes lists the regions of base that other replaced, in order.
+func changes(base, other []string) []change {
+	var (
+		out []change
+		cur *change
+		i   int
+	)
+	for _, o := range diffLines(base, other) {
+		switch o.kind {
+		case ' ':
+			if cur != nil {
+				out = append(out, *cur)
+				cur = nil
+			}
+			i++
+		case '-':
+			if cur == nil {
+				cur = &change{start: i, end: i}
+			}
+			cur.end++
+			i++
+		case '+':
+			if cur == nil {
+				cur = &change{start: i, end: i}
+			}
+			cur.lines = append(cur.lines, o.line)
+		}
+	}
+	if cur != nil {
+		out = append(out, *cur)
+	}
+	return out
+}
+
+func applyChanges(base []string, start, end int, cs []change) []string {
+	var out []string
+	p := start
+	for _, c := range cs {
+		out = append(out, base[p:c.start]...)
+		out = append(out, c.lines...)
+		p = c.end
+	}
+	return append(out, base[p:end]...)
+}
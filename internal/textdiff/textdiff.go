@@ -0,0 +1,149 @@
+// Package textdiff renders line-based unified diffs, as used by `track diff`
+// and the web UI's revision view, plus the three-way merge behind
+// `track edit --interactive`.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Context is the number of unchanged lines kept around each change.
+const Context = 3
+
+type op struct {
+	kind byte // ' ', '-', or '+'
+	line string
+}
+
+// Unified returns a unified diff from a to b with the given file names in
+// its header, or "" when they are equal.
+func Unified(fromName, toName, a, b string) string {
+	if a == b {
+		return ""
+	}
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+	for _, h := range hunks(ops) {
+		out.WriteString(h)
+	}
+	return out.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines aligns a and b along their longest common subsequence.
+func diffLines(a, b []string) []op {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, op{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, op{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, op{'+', b[j]})
+	}
+	return ops
+}
+
+// hunks groups changes that are within 2*Context lines of each other and
+// renders each group with its @@ header.
+func hunks(ops []op) []string {
+	var out []string
+	aLine, bLine := 1, 1
+	for start := 0; start < len(ops); {
+		if ops[start].kind == ' ' {
+			aLine++
+			bLine++
+			start++
+			continue
+		}
+		// Extend the hunk until the changes are separated by a long enough
+		// unchanged run.
+		end := start
+		for k := start; k < len(ops); k++ {
+			if ops[k].kind != ' ' {
+				end = k + 1
+				continue
+			}
+			if k-end >= 2*Context {
+				break
+			}
+		}
+		from := max(0, start-Context)
+		to := min(len(ops), end+Context)
+		aStart, bStart := aLine-(start-from), bLine-(start-from)
+
+		var body strings.Builder
+		aCount, bCount := 0, 0
+		for _, o := range ops[from:to] {
+			body.WriteByte(o.kind)
+			body.WriteString(o.line)
+			body.WriteByte('\n')
+			if o.kind != '+' {
+				aCount++
+			}
+			if o.kind != '-' {
+				bCount++
+			}
+		}
+		out = append(out, fmt.Sprintf("@@ -%s +%s @@\n%s", hunkRange(aStart, aCount), hunkRange(bStart, bCount), body.String()))
+
+		for _, o := range ops[start:to] {
+			if o.kind != '+' {
+				aLine++
+			}
+			if o.kind != '-' {
+				bLine++
+			}
+		}
+		start = to
+	}
+	return out
+}
+
+// hunkRange formats a hunk's start and length; an empty range starts on the
+// line before it, as in GNU diff.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
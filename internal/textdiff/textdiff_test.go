@@ -0,0 +1,46 @@
+package textdiff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	b := "one\nTWO\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n"
+	got := Unified("a", "b", a, b)
+	want := "--- a\n+++ b\n" +
+		"@@ -1,5 +1,5 @@\n one\n-two\n+TWO\n three\n four\n five\n" +
+		"@@ -8,3 +8,4 @@\n eight\n nine\n ten\n+eleven\n"
+	if got != want {
+		t.Fatalf("Unified() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUnifiedFromEmpty(t *testing.T) {
+	got := Unified("a", "b", "", "new\n")
+	want := "--- a\n+++ b\n@@ -0,0 +1 @@\n+new\n"
+	if got != want {
+		t.Fatalf("Unified() = %q, want %q", got, want)
+	}
+	if got := Unified("a", "b", "same", "same"); got != "" {
+		t.Fatalf("Unified(equal) = %q, want empty", got)
+	}
+}
+
+func TestMerge3(t *testing.T) {
+	base := "one\ntwo\nthree\nfour\nfive"
+	ours := "ONE\ntwo\nthree\nfour\nfive"
+	theirs := "one\ntwo\nthree\nfour\nFIVE\nsix"
+	got, clean := Merge3(base, ours, theirs)
+	if !clean || got != "ONE\ntwo\nthree\nfour\nFIVE\nsix" {
+		t.Fatalf("Merge3() = %q, clean=%v", got, clean)
+	}
+
+	got, clean = Merge3(base, "one\nmine\nthree\nfour\nfive", "one\nyours\nthree\nfour\nfive")
+	want := "one\n" + MarkerOurs + "\nmine\n" + MarkerSep + "\nyours\n" + MarkerTheirs + "\nthree\nfour\nfive"
+	if clean || got != want {
+		t.Fatalf("Merge3(conflict) = %q, clean=%v", got, clean)
+	}
+
+	if got, clean := Merge3(base, "one\nsame\nthree\nfour\nfive", "one\nsame\nthree\nfour\nfive"); !clean || got != "one\nsame\nthree\nfour\nfive" {
+		t.Fatalf("Merge3(same change) = %q, clean=%v", got, clean)
+	}
+}
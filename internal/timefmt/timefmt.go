@@ -0,0 +1,100 @@
+// Package timefmt renders stored RFC3339 timestamps for display according to
+// the time_format and timezone config.
+package timefmt
+
+import (
+	"fmt"
+	"time"
+
+	appconfig "github.com/myuon/track/internal/config"
+)
+
+// Display formats accepted by the time_format config.
+const (
+	ISO      = "iso"
+	Local    = "local"
+	Relative = "relative"
+)
+
+// localLayout is used by the local format, and by relative once a timestamp
+// is too old for "ago" to be useful.
+const localLayout = "2006-01-02 15:04 MST"
+
+// relativeLimit is how far back relative output keeps counting.
+const relativeLimit = 30 * 24 * time.Hour
+
+type Formatter struct {
+	Format string
+	Loc    *time.Location
+	Now    func() time.Time
+}
+
+// Load reads the formatter from the config.
+func Load() (Formatter, error) {
+	cfg, err := appconfig.Load()
+	if err != nil {
+		return Formatter{}, err
+	}
+	loc, err := cfg.Location()
+	if err != nil {
+		return Formatter{}, err
+	}
+	return Formatter{Format: cfg.TimeFormat, Loc: loc, Now: time.Now}, nil
+}
+
+// Static returns f with relative output replaced by local time, for output
+// such as exports that is read long after it is written.
+func (f Formatter) Static() Formatter {
+	if f.Format == Relative {
+		f.Format = Local
+	}
+	return f
+}
+
+// Time formats a stored timestamp. Values that do not parse are returned
+// unchanged.
+func (f Formatter) Time(ts string) string {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return ts
+	}
+	loc := f.Loc
+	if loc == nil {
+		loc = time.Local
+	}
+	switch f.Format {
+	case Local:
+		return t.In(loc).Format(localLayout)
+	case Relative:
+		now := time.Now()
+		if f.Now != nil {
+			now = f.Now()
+		}
+		d := now.Sub(t)
+		if d > relativeLimit || d < -relativeLimit {
+			return t.In(loc).Format(localLayout)
+		}
+		if d < 0 {
+			return "in " + span(-d)
+		}
+		if d < time.Minute {
+			return "just now"
+		}
+		return span(d) + " ago"
+	default:
+		return ts
+	}
+}
+
+func span(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,35 @@
+package timefmt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatterTime(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(format string) Formatter {
+		return Formatter{Format: format, Loc: tokyo, Now: func() time.Time { return now }}
+	}
+
+	cases := []struct {
+		f    Formatter
+		ts   string
+		want string
+	}{
+		{at(ISO), "2026-03-01T09:00:00Z", "2026-03-01T09:00:00Z"},
+		{at(Local), "2026-03-01T09:00:00Z", "2026-03-01 18:00 JST"},
+		{at(Relative), "2026-03-01T11:59:30Z", "just now"},
+		{at(Relative), "2026-03-01T09:00:00Z", "3h ago"},
+		{at(Relative), "2026-02-27T12:00:00Z", "2d ago"},
+		{at(Relative), "2026-03-01T12:05:00Z", "in 5m"},
+		{at(Relative), "2025-12-01T00:00:00Z", "2025-12-01 09:00 JST"},
+		{at(Relative).Static(), "2026-03-01T09:00:00Z", "2026-03-01 18:00 JST"},
+		{at(Local), "not a time", "not a time"},
+	}
+	for _, tc := range cases {
+		if got := tc.f.Time(tc.ts); got != tc.want {
+			t.Fatalf("%s Time(%q) = %q, want %q", tc.f.Format, tc.ts, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// Package timeline schedules issues on a calendar for `track timeline` and
+// the web UI's timeline page, from when work started, due dates, and
+// estimates.
+package timeline
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+// Workday is how much of an estimate fits in one day of the schedule.
+const Workday = 8 * time.Hour
+
+// Timeline is the schedule, one section per project. Issues without a
+// project come last, in a section with an empty Project.
+type Timeline struct {
+	Sections []Section
+	// Unscheduled counts the issues left out because they have neither
+	// started nor been given a due date.
+	Unscheduled int
+}
+
+type Section struct {
+	Project string
+	Entries []Entry
+}
+
+// Entry is one issue's bar. Start and End are dates (YYYY-MM-DD); End is
+// the last day of work, so a one-day task starts and ends on the same date.
+type Entry struct {
+	ID       string
+	Title    string
+	Status   string
+	Start    string
+	End      string
+	Estimate string `json:",omitempty"`
+	Due      string `json:",omitempty"`
+	// Overdue is set for open issues whose due date has passed.
+	Overdue bool `json:",omitempty"`
+}
+
+// Build schedules the open and done issues of project, or of every project
+// when project is empty. An issue starts on the day it first moved to
+// in_progress, or, before that, as many workdays before its due date as its
+// estimate needs. It ends on the day it was done, on its due date, or once
+// its estimate is used up, in that order.
+func Build(ctx context.Context, store *sqlite.Store, project string, now time.Time, loc *time.Location) (Timeline, error) {
+	started, finished, err := statusDays(ctx, store, loc)
+	if err != nil {
+		return Timeline{}, err
+	}
+
+	var keys []string
+	if project != "" {
+		keys = []string{project}
+	} else {
+		projects, err := store.ListProjects(ctx)
+		if err != nil {
+			return Timeline{}, err
+		}
+		for _, p := range projects {
+			keys = append(keys, p.Key)
+		}
+	}
+
+	var tl Timeline
+	seen := map[string]bool{}
+	add := func(key string, items []issue.Item) {
+		sec := Section{Project: key}
+		for _, it := range items {
+			if seen[it.ID] {
+				continue
+			}
+			seen[it.ID] = true
+			e, ok := schedule(it, started[it.ID], finished[it.ID], now, loc)
+			if !ok {
+				tl.Unscheduled++
+				continue
+			}
+			sec.Entries = append(sec.Entries, e)
+		}
+		if len(sec.Entries) > 0 {
+			tl.Sections = append(tl.Sections, sec)
+		}
+	}
+	for _, key := range keys {
+		items, err := store.ListIssues(ctx, sqlite.ListFilter{Project: key, ExcludeArchived: true, Sort: "id"})
+		if err != nil {
+			return Timeline{}, err
+		}
+		add(key, items)
+	}
+	if project == "" {
+		items, err := store.ListIssues(ctx, sqlite.ListFilter{ExcludeArchived: true, Sort: "id"})
+		if err != nil {
+			return Timeline{}, err
+		}
+		add("", items)
+	}
+	return tl, nil
+}
+
+// statusDays returns, per issue, the day it first moved to in_progress and
+// the day it last moved to done, from the activity log.
+func statusDays(ctx context.Context, store *sqlite.Store, loc *time.Location) (started, finished map[string]time.Time, err error) {
+	entries, err := store.ListActivity(ctx, "", 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	started, finished = map[string]time.Time{}, map[string]time.Time{}
+	for _, a := range entries {
+		if a.Kind != sqlite.ActivityStatus {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, a.CreatedAt)
+		if err != nil {
+			continue
+		}
+		day := dayOf(at, loc)
+		switch {
+		case strings.HasSuffix(a.Detail, "-> "+issue.StatusInProgress):
+			if _, ok := started[a.IssueID]; !ok {
+				started[a.IssueID] = day
+			}
+		case strings.HasSuffix(a.Detail, "-> "+issue.StatusDone):
+			finished[a.IssueID] = day
+		}
+	}
+	return started, finished, nil
+}
+
+func schedule(it issue.Item, started, finished time.Time, now time.Time, loc *time.Location) (Entry, bool) {
+	days := estimateDays(it.Estimate)
+	var due time.Time
+	if t, ok := issue.DueTime(it.Due, loc); ok {
+		// DueTime is the deadline; the bar ends on the day before it for
+		// date-only values and on the deadline's own day otherwise.
+		due = dayOf(t.Add(-time.Nanosecond), loc)
+	}
+
+	start := started
+	if start.IsZero() {
+		if it.Status == issue.StatusDone && !finished.IsZero() {
+			start = finished.AddDate(0, 0, 1-days)
+		} else if !due.IsZero() {
+			start = due.AddDate(0, 0, 1-days)
+		} else {
+			return Entry{}, false
+		}
+	}
+	var end time.Time
+	switch {
+	case it.Status == issue.StatusDone && !finished.IsZero():
+		end = finished
+	case !due.IsZero():
+		end = due
+	default:
+		end = start.AddDate(0, 0, days-1)
+	}
+	if end.Before(start) {
+		end = start
+	}
+
+	return Entry{
+		ID:       it.ID,
+		Title:    it.Title,
+		Status:   it.Status,
+		Start:    start.Format(issue.DueDateLayout),
+		End:      end.Format(issue.DueDateLayout),
+		Estimate: it.Estimate,
+		Due:      it.Due,
+		Overdue:  it.Status != issue.StatusDone && it.Due != "" && issue.IsOverdue(it.Due, now, loc),
+	}, true
+}
+
+// estimateDays is how many workdays an estimate spans, at least one.
+func estimateDays(estimate string) int {
+	d := issue.EstimateDuration(estimate)
+	days := int((d + Workday - 1) / Workday)
+	return max(days, 1)
+}
+
+func dayOf(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,72 @@
+package timeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestBuild(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if _, err := store.CreateProject(ctx, "cli", "CLI", ""); err != nil {
+		t.Fatal(err)
+	}
+	planned, err := store.CreateIssue(ctx, issue.Item{Title: "Planned", Status: issue.StatusTodo, Priority: "none", Due: "2030-01-10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	twoDays := "16h"
+	if _, err := store.UpdateIssue(ctx, planned.ID, sqlite.UpdateIssueInput{Estimate: &twoDays}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetIssueProject(ctx, planned.ID, "cli"); err != nil {
+		t.Fatal(err)
+	}
+	started, err := store.CreateIssue(ctx, issue.Item{Title: "Started", Status: issue.StatusTodo, Priority: "none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inProgress := issue.StatusInProgress
+	if _, err := store.UpdateIssue(ctx, started.ID, sqlite.UpdateIssueInput{Status: &inProgress}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.CreateIssue(ctx, issue.Item{Title: "Someday", Status: issue.StatusTodo, Priority: "none"}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	tl, err := Build(ctx, store, "", now, time.UTC)
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	if tl.Unscheduled != 1 || len(tl.Sections) != 2 {
+		t.Fatalf("timeline = %+v", tl)
+	}
+	cli := tl.Sections[0]
+	if cli.Project != "cli" || len(cli.Entries) != 1 {
+		t.Fatalf("cli section = %+v", cli)
+	}
+	if e := cli.Entries[0]; e.Start != "2030-01-09" || e.End != "2030-01-10" || e.Overdue {
+		t.Fatalf("planned entry = %+v", e)
+	}
+	today := now.UTC().Format(issue.DueDateLayout)
+	rest := tl.Sections[1]
+	if rest.Project != "" || len(rest.Entries) != 1 || rest.Entries[0].Start != today || rest.Entries[0].End != today {
+		t.Fatalf("no-project section = %+v", rest)
+	}
+
+	only, err := Build(ctx, store, "cli", now, time.UTC)
+	if err != nil || len(only.Sections) != 1 || only.Unscheduled != 0 {
+		t.Fatalf("Build(cli) = %+v, %v", only, err)
+	}
+}
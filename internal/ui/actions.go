@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/service"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+// quickKeysScript drives the list page from the keyboard: j/k move the
+// selection, o or enter open it, e opens its edit form, d marks it done, 1-4
+// set p0-p3, and a asks whom to assign it to. The changes go through the
+// quickActions endpoints and are patched into the row.
+const quickKeysScript = `<script>
+(function () {
+  var rows = [].slice.call(document.querySelectorAll("li[data-id]")), cur = rows.length ? 0 : -1;
+  function mark() {
+    rows.forEach(function (r, i) { r.classList.toggle("sel", i === cur); });
+    if (cur >= 0) rows[cur].scrollIntoView({block: "nearest"});
+  }
+  function post(row, action, body) {
+    fetch("/issues/" + row.dataset.id + "/" + action, {
+      method: "POST",
+      headers: {"Content-Type": "application/json"},
+      body: JSON.stringify(body || {})
+    }).then(function (res) {
+      return res.json().then(function (it) {
+        if (!res.ok) throw new Error(it.error);
+        row.querySelector(".status").textContent = it.status;
+        row.querySelector(".priority").textContent = it.priority;
+        row.querySelector(".assignee").textContent = it.assignee ? "@" + it.assignee : "";
+      });
+    }).catch(function (err) { alert(err.message); });
+  }
+  document.addEventListener("keydown", function (e) {
+    var tag = e.target.tagName;
+    if (tag === "INPUT" && e.target.type === "checkbox") tag = "";
+    if (cur < 0 || e.ctrlKey || e.metaKey || e.altKey || tag === "INPUT" || tag === "TEXTAREA" || tag === "SELECT") return;
+    var row = rows[cur], id = row.dataset.id;
+    switch (e.key) {
+    case "j": cur = Math.min(cur + 1, rows.length - 1); mark(); break;
+    case "k": cur = Math.max(cur - 1, 0); mark(); break;
+    case "o": case "Enter": location.href = "/issues/" + id; break;
+    case "e": location.href = "/issues/" + id + "#edit"; break;
+    case "d": post(row, "done"); break;
+    case "1": case "2": case "3": case "4": post(row, "priority", {priority: "p" + (Number(e.key) - 1)}); break;
+    case "a":
+      var who = prompt("Assign " + id + " to (empty to unassign)", row.querySelector(".assignee").textContent.replace("@", ""));
+      if (who !== null) post(row, "assign", {assignee: who});
+      break;
+    default: return;
+    }
+    e.preventDefault();
+  });
+  mark();
+})();
+</script>`
+
+// quickRequest is the JSON body of a quick action; each action reads the
+// field it needs.
+type quickRequest struct {
+	Priority string `json:"priority"`
+	Assignee string `json:"assignee"`
+}
+
+// quickResponse is what a quick action leaves of the issue, for the list
+// page to patch its row.
+type quickResponse struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Priority string `json:"priority"`
+	Assignee string `json:"assignee"`
+}
+
+// quickActions are the JSON endpoints behind the keyboard shortcuts, served
+// as POST /issues/<id>/<action>.
+var quickActions = map[string]func(ctx context.Context, store *sqlite.Store, id string, req quickRequest) (issue.Item, error){
+	"done": func(ctx context.Context, store *sqlite.Store, id string, _ quickRequest) (issue.Item, error) {
+		current, err := store.GetIssue(ctx, id)
+		if err != nil {
+			return issue.Item{}, err
+		}
+		if done, total := issue.ChecklistProgress(current.Body); done < total {
+			return issue.Item{}, fmt.Errorf("%s has unchecked acceptance criteria (%d/%d)", current.ID, done, total)
+		}
+		return service.SetStatus(ctx, store, id, issue.StatusDone)
+	},
+	"priority": func(ctx context.Context, store *sqlite.Store, id string, req quickRequest) (issue.Item, error) {
+		if err := issue.ValidatePriority(req.Priority); err != nil {
+			return issue.Item{}, err
+		}
+		return service.UpdateIssue(ctx, store, id, service.Update{UpdateIssueInput: sqlite.UpdateIssueInput{Priority: &req.Priority}})
+	},
+	"assign": func(ctx context.Context, store *sqlite.Store, id string, req quickRequest) (issue.Item, error) {
+		assignee := issue.NormalizeAssignee(req.Assignee)
+		return service.UpdateIssue(ctx, store, id, service.Update{UpdateIssueInput: sqlite.UpdateIssueInput{Assignee: &assignee}})
+	},
+}
+
+// serveQuickAction runs a quick action.
+func serveQuickAction(w http.ResponseWriter, r *http.Request, id, action string) {
+	var req quickRequest
+	if !decodeJSONPost(w, r, &req) {
+		return
+	}
+
+	ctx := sqlite.WithActor(context.Background(), "ui")
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer store.Close()
+	it, err := quickActions[action](ctx, store, id, req)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, quickResponse{ID: it.ID, Status: it.Status, Priority: it.Priority, Assignee: it.Assignee})
+}
+
+// decodeJSONPost reads the JSON body of a POST into v, answering the
+// request itself when it cannot. Only JSON bodies are accepted, so a plain
+// form on another site cannot post to the endpoints that use it.
+func decodeJSONPost(w http.ResponseWriter, r *http.Request, v any) bool {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return false
+	}
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "application/json" {
+		writeJSONError(w, http.StatusUnsupportedMediaType, "content type must be application/json")
+		return false
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid JSON body")
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
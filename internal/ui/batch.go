@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/service"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+// bulkBarScript shows the bulk action bar while list rows are checked and
+// posts its choices to /issues/batch. x checks the row selected from the
+// keyboard.
+const bulkBarScript = `<script>
+(function () {
+  var bar = document.getElementById("bulk"), boxes = [].slice.call(document.querySelectorAll("li[data-id] input[type=checkbox]"));
+  function checked() { return boxes.filter(function (b) { return b.checked; }).map(function (b) { return b.closest("li").dataset.id; }); }
+  function update() {
+    var n = checked().length;
+    bar.hidden = n === 0;
+    document.getElementById("bulk-count").textContent = n + " selected";
+  }
+  boxes.forEach(function (b) { b.addEventListener("change", update); });
+  document.addEventListener("keydown", function (e) {
+    var sel = document.querySelector("li.sel input[type=checkbox]");
+    if (e.key !== "x" || !sel || e.ctrlKey || e.metaKey || e.altKey) return;
+    sel.checked = !sel.checked;
+    update();
+    e.preventDefault();
+  });
+  bar.addEventListener("submit", function (e) {
+    e.preventDefault();
+    var body = {ids: checked()};
+    ["status", "priority", "project"].forEach(function (name) {
+      var v = bar.elements[name].value;
+      if (v) body[name] = v;
+    });
+    var labels = bar.elements.labels.value.split(",").map(function (l) { return l.trim(); }).filter(Boolean);
+    if (labels.length) body.labels = labels;
+    fetch("/issues/batch", {
+      method: "POST",
+      headers: {"Content-Type": "application/json"},
+      body: JSON.stringify(body)
+    }).then(function (res) {
+      return res.json().then(function (out) {
+        if (!res.ok) throw new Error(out.error);
+        var failed = out.results.filter(function (r) { return r.error; });
+        if (failed.length) alert(failed.map(function (r) { return r.id + ": " + r.error; }).join("\n"));
+        location.reload();
+      });
+    }).catch(function (err) { alert(err.message); });
+  });
+  update();
+})();
+</script>`
+
+// batchRequest sets the same fields on every issue in IDs, as `track set`
+// does for several IDs. Empty fields are left as they are; Labels are
+// attached next to the labels each issue already has, and a Project of
+// "none" takes the issues out of their project.
+type batchRequest struct {
+	IDs      []string `json:"ids"`
+	Status   string   `json:"status"`
+	Priority string   `json:"priority"`
+	Labels   []string `json:"labels"`
+	Project  string   `json:"project"`
+}
+
+// batchResult reports one issue of a batch; Error is empty when it was
+// updated.
+type batchResult struct {
+	ID    string `json:"id"`
+	Error string `json:"error,omitempty"`
+}
+
+// serveBatch applies a batchRequest. The request fails as a whole when a
+// field is invalid; otherwise each issue is reported separately.
+func serveBatch(w http.ResponseWriter, r *http.Request) {
+	var req batchRequest
+	if !decodeJSONPost(w, r, &req) {
+		return
+	}
+
+	ctx := sqlite.WithActor(context.Background(), "ui")
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer store.Close()
+	u, err := batchUpdate(ctx, store, req)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results := make([]batchResult, 0, len(req.IDs))
+	for _, id := range req.IDs {
+		res := batchResult{ID: id}
+		if err := applyBatch(ctx, store, id, u, req.Labels); err != nil {
+			res.Error = err.Error()
+		}
+		results = append(results, res)
+	}
+	writeJSON(w, http.StatusOK, map[string][]batchResult{"results": results})
+}
+
+// batchUpdate checks the fields of req and turns them into the update every
+// issue gets.
+func batchUpdate(ctx context.Context, store *sqlite.Store, req batchRequest) (service.Update, error) {
+	var u service.Update
+	if len(req.IDs) == 0 {
+		return u, fmt.Errorf("no issues selected")
+	}
+	if req.Status != "" {
+		if err := store.ValidateStatus(ctx, req.Status); err != nil {
+			return u, err
+		}
+		u.Status = &req.Status
+	}
+	if req.Priority != "" {
+		if err := issue.ValidatePriority(req.Priority); err != nil {
+			return u, err
+		}
+		u.Priority = &req.Priority
+	}
+	if req.Project != "" {
+		u.Project = &req.Project
+	}
+	for _, label := range req.Labels {
+		if strings.TrimSpace(label) == "" {
+			return u, fmt.Errorf("label must not be empty")
+		}
+	}
+	if u.Status == nil && u.Priority == nil && u.Project == nil && len(req.Labels) == 0 {
+		return u, fmt.Errorf("nothing to change")
+	}
+	return u, nil
+}
+
+func applyBatch(ctx context.Context, store *sqlite.Store, id string, u service.Update, labels []string) error {
+	if len(labels) > 0 {
+		current, err := store.GetIssue(ctx, id)
+		if err != nil {
+			return err
+		}
+		next := append(slices.Clone(current.Labels), labels...)
+		u.Labels = &next
+	}
+	_, err := service.UpdateIssue(ctx, store, id, u)
+	return err
+}
@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+const boardTpl = `<!doctype html><html><head><style>table{border-collapse:collapse}th,td{border:1px solid #ddd;padding:4px;vertical-align:top}td ul{margin:0;padding-left:1em}.over{background:#fee2e2}</style></head><body><p><a href="/">Back</a></p><h1>Board</h1><form method="post" action="/board/prefs"><label>Swimlanes <select name="swimlanes">{{range .Options}}<option value="{{.}}"{{if eq . $.Swimlanes}} selected{{end}}>{{.}}</option>{{end}}</select></label> <label>WIP limit <input name="wip_limit" type="number" min="0" value="{{.WIPLimit}}"></label> <button type="submit">Save</button></form><table><tr><th>{{if ne .Swimlanes "none"}}{{.Swimlanes}}{{end}}</th>{{range .Statuses}}<th>{{.}}</th>{{end}}</tr>{{range .Lanes}}<tr{{if .Over}} class="over"{{end}}><th>{{.Name}}<br>WIP {{.WIP}}{{if $.WIPLimit}}/{{$.WIPLimit}}{{end}}</th>{{range .Cells}}<td><ul>{{range .}}<li><a href="/issues/{{.ID}}">{{.ID}}</a> {{.Title}}</li>{{end}}</ul></td>{{end}}</tr>{{else}}<tr><td>No issues</td></tr>{{end}}</table></body></html>`
+
+// boardPage is the board: one column per status but archived, and one row
+// per swimlane. WIP counts a lane's in_progress issues; Over is set when it
+// exceeds WIPLimit.
+type boardPage struct {
+	Swimlanes string
+	WIPLimit  int
+	Options   []string
+	Statuses  []string
+	Lanes     []boardLane
+}
+
+type boardLane struct {
+	Name string
+	// Cells line up with boardPage.Statuses.
+	Cells [][]issue.Item
+	WIP   int
+	Over  bool
+}
+
+// noLane names the lane of issues without a project or assignee.
+var noLane = map[string]string{
+	appconfig.SwimlanesProject:  "No project",
+	appconfig.SwimlanesAssignee: "Unassigned",
+}
+
+func newBoardPage(ctx context.Context, store *sqlite.Store, prefs appconfig.BoardPrefs) (boardPage, error) {
+	page := boardPage{
+		Swimlanes: prefs.Swimlanes,
+		WIPLimit:  prefs.WIPLimit,
+		Options:   []string{appconfig.SwimlanesNone, appconfig.SwimlanesProject, appconfig.SwimlanesAssignee, appconfig.SwimlanesPriority},
+	}
+	if page.Swimlanes == "" {
+		page.Swimlanes = appconfig.SwimlanesNone
+	}
+	statuses, err := store.ListStatuses(ctx)
+	if err != nil {
+		return boardPage{}, err
+	}
+	page.Statuses = slices.DeleteFunc(statuses, func(st string) bool { return st == issue.StatusArchived })
+	items, err := store.ListIssues(ctx, sqlite.ListFilter{ExcludeArchived: true, Sort: "manual"})
+	if err != nil {
+		return boardPage{}, err
+	}
+
+	keys := map[string]string{}
+	for _, it := range items {
+		switch page.Swimlanes {
+		case appconfig.SwimlanesProject:
+			if keys[it.ID], err = store.GetIssueProject(ctx, it.ID); err != nil {
+				return boardPage{}, err
+			}
+		case appconfig.SwimlanesAssignee:
+			keys[it.ID] = it.Assignee
+		case appconfig.SwimlanesPriority:
+			keys[it.ID] = it.Priority
+		}
+	}
+	order, err := laneOrder(ctx, store, page.Swimlanes, keys)
+	if err != nil {
+		return boardPage{}, err
+	}
+
+	lanes := map[string]*boardLane{}
+	for _, key := range order {
+		name := key
+		if name == "" {
+			name = noLane[page.Swimlanes]
+		}
+		if page.Swimlanes == appconfig.SwimlanesNone {
+			name = "All"
+		}
+		lanes[key] = &boardLane{Name: name, Cells: make([][]issue.Item, len(page.Statuses))}
+	}
+	for _, it := range items {
+		i := slices.Index(page.Statuses, it.Status)
+		if i < 0 {
+			continue
+		}
+		lane := lanes[keys[it.ID]]
+		lane.Cells[i] = append(lane.Cells[i], it)
+		if it.Status == issue.StatusInProgress {
+			lane.WIP++
+		}
+	}
+	for _, key := range order {
+		lane := lanes[key]
+		lane.Over = page.WIPLimit > 0 && lane.WIP > page.WIPLimit
+		page.Lanes = append(page.Lanes, *lane)
+	}
+	return page, nil
+}
+
+// laneOrder lists the lanes that have issues: projects in their own order,
+// priorities from p0 down, and assignees by name, with the lane of issues
+// lacking the field last.
+func laneOrder(ctx context.Context, store *sqlite.Store, swimlanes string, keys map[string]string) ([]string, error) {
+	used := map[string]bool{}
+	for _, k := range keys {
+		used[k] = true
+	}
+	var order []string
+	switch swimlanes {
+	case appconfig.SwimlanesNone:
+		return []string{""}, nil
+	case appconfig.SwimlanesProject:
+		projects, err := store.ListProjects(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range projects {
+			if used[p.Key] {
+				order = append(order, p.Key)
+			}
+		}
+	case appconfig.SwimlanesPriority:
+		for _, p := range []string{"p0", "p1", "p2", "p3", "none"} {
+			if used[p] {
+				order = append(order, p)
+			}
+		}
+		return order, nil
+	default:
+		for k := range used {
+			if k != "" {
+				order = append(order, k)
+			}
+		}
+		sort.Strings(order)
+	}
+	if used[""] {
+		order = append(order, "")
+	}
+	return order, nil
+}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/service"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+const quickTpl = `<!doctype html><html><head><meta name="viewport" content="width=device-width, initial-scale=1"><title>Quick add</title><style>body{font-family:sans-serif;margin:0;padding:16px;max-width:480px}input,button{display:block;width:100%;box-sizing:border-box;font-size:18px;padding:12px;margin:8px 0}button{background:#2563eb;color:#fff;border:0;border-radius:6px}.added{background:#dcfce7;padding:8px;border-radius:6px}.error{background:#fee2e2;padding:8px;border-radius:6px}</style></head><body><h1>Quick add</h1>{{if .Added}}<p class="added">Added <a href="/issues/{{.Added}}">{{.Added}}</a></p>{{end}}{{if .Error}}<p class="error">{{.Error}}</p>{{end}}<form method="post" action="/quick"><input name="title" placeholder="What's the idea?" value="{{.Title}}" autofocus required><input name="label" placeholder="Label (optional)" value="{{.Label}}" autocapitalize="none"><button type="submit">Add</button></form><p><a href="/">All issues</a></p></body></html>`
+
+// quickPage is the quick add form. Label is kept from the last issue added,
+// so a run of ideas can share it.
+type quickPage struct {
+	Added, Error string
+	Title, Label string
+}
+
+// quickAdd creates a todo issue from the quick add form.
+func quickAdd(ctx context.Context, title, label string) (issue.Item, error) {
+	title = strings.TrimSpace(title)
+	if err := issue.ValidateTitle(title); err != nil {
+		return issue.Item{}, err
+	}
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return issue.Item{}, err
+	}
+	defer store.Close()
+	it := issue.Item{Title: title, Status: issue.StatusTodo, Priority: "none"}
+	if label = strings.TrimSpace(label); label != "" {
+		it.Labels = []string{label}
+	}
+	return service.CreateIssue(ctx, store, it)
+}
+
+// quickRedirect is where a successful quick add goes, so reloading the page
+// does not post the form again.
+func quickRedirect(id, label string) string {
+	q := url.Values{"added": {id}}
+	if label != "" {
+		q.Set("label", label)
+	}
+	return "/quick?" + q.Encode()
+}
+
+func serveQuick(w http.ResponseWriter, r *http.Request, render func(http.ResponseWriter, quickPage)) {
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		render(w, quickPage{Added: q.Get("added"), Label: q.Get("label")})
+	case http.MethodPost:
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		title, label := r.Form.Get("title"), strings.TrimSpace(r.Form.Get("label"))
+		it, err := quickAdd(sqlite.WithActor(context.Background(), "ui"), title, label)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render(w, quickPage{Error: err.Error(), Title: title, Label: label})
+			return
+		}
+		http.Redirect(w, r, quickRedirect(it.ID, label), http.StatusSeeOther)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -4,18 +4,80 @@ import (
 	"context"
 	"fmt"
 	"html/template"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
+	appconfig "github.com/myuon/track/internal/config"
+	"github.com/myuon/track/internal/httpmw"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/service"
 	"github.com/myuon/track/internal/store/sqlite"
+	"github.com/myuon/track/internal/textdiff"
+	"github.com/myuon/track/internal/timefmt"
+	"github.com/myuon/track/internal/timeline"
 )
 
-const listTpl = `<!doctype html><html><body><h1>Track Issues</h1><ul>{{range .}}<li><a href="/issues/{{.ID}}">{{.ID}}</a> [{{.Status}}] {{.Title}}</li>{{else}}<li>No issues</li>{{end}}</ul></body></html>`
-const detailTpl = `<!doctype html><html><body><p><a href="/">Back</a></p><h1>{{.ID}} {{.Title}}</h1><p>Status: {{.Status}}</p><p>Priority: {{.Priority}}</p><form method="post" action="/issues/{{.ID}}/edit"><label>Title <input name="title" value="{{.Title}}"></label><br><label>Body <textarea name="body">{{.Body}}</textarea></label><br><button type="submit">Save</button></form></body></html>`
+const listTpl = `<!doctype html><html><head><style>li.sel{background:#fef3c7}#bulk{position:sticky;top:0;background:#f3f4f6;padding:4px}</style></head><body><h1>Track Issues</h1><p><a href="/board">Board</a> <a href="/timeline">Timeline</a> <a href="/quick">Quick add</a></p><p>Keys: j/k move, o open, e edit, d done, 1-4 priority p0-p3, a assign, x select</p><form id="bulk" hidden><span id="bulk-count"></span> <select name="status"><option value="">status</option>{{range .Statuses}}<option>{{.}}</option>{{end}}</select> <select name="priority"><option value="">priority</option>{{range .Priorities}}<option>{{.}}</option>{{end}}</select> <input name="labels" placeholder="labels, comma separated"> <select name="project"><option value="">project</option>{{range .Projects}}<option>{{.}}</option>{{end}}<option value="none">no project</option></select> <button type="submit">Apply</button></form><ul>{{range .Items}}<li data-id="{{.ID}}"><input type="checkbox" aria-label="select {{.ID}}"> <a href="/issues/{{.ID}}">{{.ID}}</a> [<span class="status">{{.Status}}</span>] <span class="priority">{{.Priority}}</span> {{.Title}} <span class="assignee">{{if .Assignee}}@{{.Assignee}}{{end}}</span>{{if .Pinned}} (pinned){{end}}</li>{{else}}<li>No issues</li>{{end}}</ul>` + quickKeysScript + bulkBarScript + `</body></html>`
+const detailTpl = `<!doctype html><html><body><p><a href="/">Back</a></p><h1>{{.ID}} {{.Title}}</h1><p>Status: {{.Status}}</p><p>Priority: {{.Priority}}</p>{{if .NextAction}}<p>Next action: {{.NextAction}}</p>{{end}}<p>Created: {{when .CreatedAt}}</p><p>Updated: {{when .UpdatedAt}}</p>{{if .Links}}<h2>Links</h2><ul>{{range .Links}}<li><a href="{{.URL}}">{{if .Title}}{{.Title}}{{else}}{{.URL}}{{end}}</a></li>{{end}}</ul>{{end}}<form id="edit" method="post" action="/issues/{{.ID}}/edit"><label>Title <input name="title" value="{{.Title}}"></label><br><label>Body <textarea name="body">{{.Body}}</textarea></label><br><button type="submit">Save</button></form>{{if .Revisions}}<h2>Revisions</h2><form method="get" action="/issues/{{.ID}}"><label>From <select name="from">{{range .Revisions}}<option value="{{.Rev}}"{{if eq .Rev $.From}} selected{{end}}>{{.Rev}} {{.Actor}} {{when .CreatedAt}}</option>{{end}}</select></label> <label>To <select name="to">{{range .Revisions}}<option value="{{.Rev}}"{{if eq .Rev $.To}} selected{{end}}>{{.Rev}} {{.Actor}} {{when .CreatedAt}}</option>{{end}}</select></label> <button type="submit">Diff</button></form>{{if .Diff}}<pre>{{.Diff}}</pre>{{end}}{{end}}<script>if (location.hash === "#edit") document.querySelector("#edit input").focus();</script></body></html>`
+const timelineTpl = `<!doctype html><html><head><style>.bar{background:#bfdbfe;height:1em}.done{background:#bbf7d0}.in_progress{background:#fde68a}.overdue{background:#fca5a5}td.track{width:50%}</style></head><body><p><a href="/">Back</a></p><h1>Timeline</h1>{{if .Days}}<p>{{.First}} to {{.Last}}</p>{{range .Sections}}<h2>{{if .Project}}{{.Project}}{{else}}No project{{end}}</h2><table>{{range .Rows}}<tr><td><a href="/issues/{{.ID}}">{{.ID}}</a></td><td>{{.Title}}</td><td>{{.Start}} to {{.End}}</td><td class="track"><div class="bar {{.Status}}{{if .Overdue}} overdue{{end}}" style="margin-left: {{.Offset}}%; width: {{.Width}}%"></div></td></tr>{{end}}</table>{{end}}{{else}}<p>No scheduled issues</p>{{end}}{{if .Unscheduled}}<p>{{.Unscheduled}} issues have no start or due date.</p>{{end}}</body></html>`
 
-func NewHandler() http.Handler {
+// timelinePage lays out the timeline's bars as percentages of the span
+// from the earliest start to the latest end.
+type timelinePage struct {
+	First, Last string
+	Days        int
+	Sections    []timelineSection
+	Unscheduled int
+}
+
+type timelineSection struct {
+	Project string
+	Rows    []timelineRow
+}
+
+type timelineRow struct {
+	timeline.Entry
+	Offset, Width float64
+}
+
+// listPage is the issue list, with the choices of its bulk action bar.
+type listPage struct {
+	Items      []issue.Item
+	Statuses   []string
+	Priorities []string
+	Projects   []string
+}
+
+// detailPage is the issue detail view. From and To select the revisions
+// whose diff is shown, defaulting to the latest edit.
+type detailPage struct {
+	issue.Item
+	Revisions []sqlite.Revision
+	From, To  int
+	Diff      string
+}
+
+// NewHandler serves the web UI. hosts are the names, besides localhost and IP
+// addresses, that requests may address it by.
+func NewHandler(hosts ...string) http.Handler {
 	listT := template.Must(template.New("list").Parse(listTpl))
-	detailT := template.Must(template.New("detail").Parse(detailTpl))
+	detailT := template.Must(template.New("detail").Funcs(template.FuncMap{
+		"when": func(ts string) string {
+			times, err := timefmt.Load()
+			if err != nil {
+				return ts
+			}
+			return times.Time(ts)
+		},
+	}).Parse(detailTpl))
+
+	timelineT := template.Must(template.New("timeline").Parse(timelineTpl))
+	boardT := template.Must(template.New("board").Parse(boardTpl))
+	quickT := template.Must(template.New("quick").Parse(quickTpl))
 
 	mux := http.NewServeMux()
 
@@ -32,16 +94,103 @@ func NewHandler() http.Handler {
 		}
 		defer store.Close()
 
-		items, err := store.ListIssues(ctx, sqlite.ListFilter{Sort: "manual"})
+		page, err := newListPage(ctx, store)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := listT.Execute(w, page); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+
+	mux.HandleFunc("/timeline", func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.Background()
+		store, err := sqlite.Open(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer store.Close()
+
+		loc, err := appconfig.LoadLocation()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		tl, err := timeline.Build(ctx, store, r.URL.Query().Get("project"), time.Now(), loc)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := timelineT.Execute(w, newTimelinePage(tl)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+
+	mux.HandleFunc("/board", func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.Background()
+		store, err := sqlite.Open(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer store.Close()
+
+		cfg, err := appconfig.Load()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		page, err := newBoardPage(ctx, store, cfg.Board)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		if err := listT.Execute(w, items); err != nil {
+		if err := boardT.Execute(w, page); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
 	})
 
+	mux.HandleFunc("/board/prefs", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		cfg, err := appconfig.Load()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, key := range []string{"swimlanes", "wip_limit"} {
+			if v := r.Form.Get(key); v != "" {
+				if err := appconfig.Set(&cfg, "board."+key, v); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+		}
+		if err := appconfig.Save(cfg); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, "/board", http.StatusSeeOther)
+	})
+
+	mux.HandleFunc("/issues/batch", serveBatch)
+
+	mux.HandleFunc("/quick", func(w http.ResponseWriter, r *http.Request) {
+		serveQuick(w, r, func(w http.ResponseWriter, page quickPage) {
+			if err := quickT.Execute(w, page); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		})
+	})
+
 	mux.HandleFunc("/issues/", func(w http.ResponseWriter, r *http.Request) {
 		rest := strings.TrimPrefix(r.URL.Path, "/issues/")
 		if rest == "" {
@@ -49,6 +198,11 @@ func NewHandler() http.Handler {
 			return
 		}
 
+		if id, action, ok := strings.Cut(rest, "/"); ok && quickActions[action] != nil {
+			serveQuickAction(w, r, id, action)
+			return
+		}
+
 		if strings.HasSuffix(rest, "/edit") {
 			if r.Method != http.MethodPost {
 				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -68,14 +222,14 @@ func NewHandler() http.Handler {
 			body := r.Form.Get("body")
 			in := sqlite.UpdateIssueInput{Title: &title, Body: &body}
 
-			ctx := context.Background()
+			ctx := sqlite.WithActor(context.Background(), "ui")
 			store, err := sqlite.Open(ctx)
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
 			defer store.Close()
-			if _, err := store.UpdateIssue(ctx, id, in); err != nil {
+			if _, err := service.UpdateIssue(ctx, store, id, service.Update{UpdateIssueInput: in}); err != nil {
 				http.Error(w, err.Error(), http.StatusBadRequest)
 				return
 			}
@@ -96,10 +250,165 @@ func NewHandler() http.Handler {
 			http.Error(w, fmt.Sprintf("issue not found: %v", err), http.StatusNotFound)
 			return
 		}
-		if err := detailT.Execute(w, it); err != nil {
+		page, err := newDetailPage(ctx, store, it, r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := detailT.Execute(w, page); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
 	})
 
-	return mux
+	perMinute := 0
+	if cfg, err := appconfig.Load(); err == nil {
+		perMinute = cfg.RateLimit
+	}
+	limiter := httpmw.NewRateLimiter(perMinute)
+	return httpmw.Log(httpmw.Recover(httpmw.Limit(limiter, httpmw.ClientIP, sameOrigin(mux, hosts))))
+}
+
+// sameOrigin refuses requests addressed to a name the UI was not started
+// under, so a DNS-rebinding page cannot reach it through its own domain, and
+// refuses requests that change data unless the browser says they come from
+// the UI's own pages, so a site open in the same browser cannot post to it.
+func sameOrigin(next http.Handler, hosts []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowedHost(r.Host, hosts) {
+			http.Error(w, "unknown host refused", http.StatusForbidden)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			origin := r.Header.Get("Origin")
+			if origin == "" && r.Header.Get("Sec-Fetch-Site") != "same-origin" {
+				http.Error(w, "request without an origin refused", http.StatusForbidden)
+				return
+			}
+			if origin != "" {
+				u, err := url.Parse(origin)
+				if err != nil || u.Host != r.Host {
+					http.Error(w, "cross-origin request refused", http.StatusForbidden)
+					return
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowedHost reports whether a Host header names the UI: an IP address,
+// localhost, or one of hosts.
+func allowedHost(hostport string, hosts []string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSuffix(strings.Trim(host, "[]"), "."))
+	if net.ParseIP(host) != nil || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	for _, h := range hosts {
+		if strings.EqualFold(host, h) {
+			return true
+		}
+	}
+	return false
+}
+
+func newListPage(ctx context.Context, store *sqlite.Store) (listPage, error) {
+	items, err := store.ListIssues(ctx, sqlite.ListFilter{Sort: "manual"})
+	if err != nil {
+		return listPage{}, err
+	}
+	statuses, err := store.ListStatuses(ctx)
+	if err != nil {
+		return listPage{}, err
+	}
+	projects, err := store.ListProjects(ctx)
+	if err != nil {
+		return listPage{}, err
+	}
+	page := listPage{Items: items, Statuses: statuses, Priorities: []string{"p0", "p1", "p2", "p3", "none"}}
+	for _, p := range projects {
+		page.Projects = append(page.Projects, p.Key)
+	}
+	return page, nil
+}
+
+func newTimelinePage(tl timeline.Timeline) timelinePage {
+	page := timelinePage{Unscheduled: tl.Unscheduled}
+	var first, last time.Time
+	for _, sec := range tl.Sections {
+		for _, e := range sec.Entries {
+			start, _ := time.Parse(issue.DueDateLayout, e.Start)
+			end, _ := time.Parse(issue.DueDateLayout, e.End)
+			if first.IsZero() || start.Before(first) {
+				first = start
+			}
+			if end.After(last) {
+				last = end
+			}
+		}
+	}
+	if first.IsZero() {
+		return page
+	}
+	page.First, page.Last = first.Format(issue.DueDateLayout), last.Format(issue.DueDateLayout)
+	page.Days = int(last.Sub(first).Hours()/24) + 1
+	for _, sec := range tl.Sections {
+		ps := timelineSection{Project: sec.Project}
+		for _, e := range sec.Entries {
+			start, _ := time.Parse(issue.DueDateLayout, e.Start)
+			end, _ := time.Parse(issue.DueDateLayout, e.End)
+			offset := start.Sub(first).Hours() / 24
+			days := end.Sub(start).Hours()/24 + 1
+			ps.Rows = append(ps.Rows, timelineRow{
+				Entry:  e,
+				Offset: 100 * offset / float64(page.Days),
+				Width:  100 * days / float64(page.Days),
+			})
+		}
+		page.Sections = append(page.Sections, ps)
+	}
+	return page
+}
+
+func newDetailPage(ctx context.Context, store *sqlite.Store, it issue.Item, q url.Values) (detailPage, error) {
+	revs, err := store.ListRevisions(ctx, it.ID)
+	if err != nil {
+		return detailPage{}, err
+	}
+	if it.Links, err = store.ListLinks(ctx, it.ID); err != nil {
+		return detailPage{}, err
+	}
+	vars, err := store.TemplateVars(ctx, it)
+	if err != nil {
+		return detailPage{}, err
+	}
+	it.NextAction = issue.ExpandTemplate(it.NextAction, vars)
+	page := detailPage{Item: it, Revisions: revs}
+	if len(revs) == 0 {
+		return page, nil
+	}
+	latest := revs[len(revs)-1].Rev
+	page.From, page.To = max(1, latest-1), latest
+	for name, dst := range map[string]*int{"from": &page.From, "to": &page.To} {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return detailPage{}, fmt.Errorf("invalid revision: %s", v)
+			}
+			*dst = n
+		}
+	}
+	a, err := store.GetRevision(ctx, it.ID, page.From)
+	if err != nil {
+		return detailPage{}, err
+	}
+	b, err := store.GetRevision(ctx, it.ID, page.To)
+	if err != nil {
+		return detailPage{}, err
+	}
+	page.Diff = textdiff.Unified(fmt.Sprintf("%s@%d", it.ID, page.From), fmt.Sprintf("%s@%d", it.ID, page.To), a.Body, b.Body)
+	return page, nil
 }
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"context"
+	"html"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -27,7 +28,7 @@ func TestListAndDetailHandlers(t *testing.T) {
 		t.Fatalf("create issue: %v", err)
 	}
 
-	h := NewHandler()
+	h := NewHandler("example.com")
 
 	rr := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
@@ -49,3 +50,406 @@ func TestListAndDetailHandlers(t *testing.T) {
 		t.Fatalf("detail should contain title: %s", rr.Body.String())
 	}
 }
+
+func TestDetailShowsRevisionDiff(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "UI issue", Status: issue.StatusTodo, Priority: "p2", Body: "first"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	for _, body := range []string{"second", "third"} {
+		if _, err := store.UpdateIssue(ctx, it.ID, sqlite.UpdateIssueInput{Body: &body}); err != nil {
+			t.Fatalf("update issue: %v", err)
+		}
+	}
+
+	h := NewHandler("example.com")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/issues/"+it.ID, nil))
+	if body := html.UnescapeString(rr.Body.String()); !strings.Contains(body, "-second\n+third") || !strings.Contains(body, `<option value="3" selected>`) {
+		t.Fatalf("detail should diff the latest edit: %s", body)
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/issues/"+it.ID+"?from=1&to=3", nil))
+	if body := html.UnescapeString(rr.Body.String()); !strings.Contains(body, "-first\n+third") {
+		t.Fatalf("detail should diff the selected revisions: %s", body)
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/issues/"+it.ID+"?from=9", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("unknown revision status = %d", rr.Code)
+	}
+}
+
+func TestTimelinePage(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	for _, due := range []string{"2030-01-01", "2030-01-04"} {
+		if _, err := store.CreateIssue(ctx, issue.Item{Title: "Due " + due, Status: issue.StatusTodo, Priority: "p2", Due: due}); err != nil {
+			t.Fatalf("create issue: %v", err)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	NewHandler("example.com").ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/timeline", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("timeline status = %d", rr.Code)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{"2030-01-01 to 2030-01-04", "Due 2030-01-04", "margin-left: 75%; width: 25%"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("timeline missing %q: %s", want, body)
+		}
+	}
+}
+
+func TestQuickActions(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "UI issue", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	h := NewHandler("example.com")
+	post := func(action, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/issues/"+it.ID+"/"+action, strings.NewReader(body))
+		req.Header.Set("Origin", "http://example.com")
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if body := rr.Body.String(); rr.Code != http.StatusOK || !strings.Contains(body, `<li data-id="`+it.ID+`">`) || !strings.Contains(body, `case "j"`) {
+		t.Fatalf("list should carry the keyboard script: %d %s", rr.Code, body)
+	}
+
+	if rr := post("priority", `{"priority":"p0"}`); rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"priority":"p0"`) {
+		t.Fatalf("priority = %d %s", rr.Code, rr.Body.String())
+	}
+	if rr := post("priority", `{"priority":"p9"}`); rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), `"error"`) {
+		t.Fatalf("invalid priority = %d %s", rr.Code, rr.Body.String())
+	}
+	if rr := post("assign", `{"assignee":"alice"}`); rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"assignee":"alice"`) {
+		t.Fatalf("assign = %d %s", rr.Code, rr.Body.String())
+	}
+	if rr := post("done", `{}`); rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"status":"done"`) {
+		t.Fatalf("done = %d %s", rr.Code, rr.Body.String())
+	}
+
+	got, err := store.GetIssue(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	if got.Status != issue.StatusDone || got.Priority != "p0" || got.Assignee != "alice" {
+		t.Fatalf("issue after quick actions = %+v", got)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/issues/"+it.ID+"/done", strings.NewReader("x=1"))
+	req.Header.Set("Origin", "http://example.com")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("form post status = %d", rr.Code)
+	}
+}
+
+func TestBoardSwimlanes(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	for _, it := range []issue.Item{
+		{Title: "Fix login", Status: issue.StatusInProgress, Priority: "p1", Assignee: "alice"},
+		{Title: "Fix logout", Status: issue.StatusInProgress, Priority: "p2", Assignee: "alice"},
+		{Title: "Write docs", Status: issue.StatusTodo, Priority: "p2"},
+	} {
+		if _, err := store.CreateIssue(ctx, it); err != nil {
+			t.Fatalf("create issue: %v", err)
+		}
+	}
+	h := NewHandler("example.com")
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/board", nil))
+	if body := rr.Body.String(); rr.Code != http.StatusOK || !strings.Contains(body, "<th>All<br>WIP 2</th>") {
+		t.Fatalf("board without swimlanes: %d %s", rr.Code, body)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/board/prefs", strings.NewReader("swimlanes=assignee&wip_limit=1"))
+	req.Header.Set("Origin", "http://example.com")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("prefs status = %d %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/board", nil))
+	body := rr.Body.String()
+	for _, want := range []string{`<tr class="over"><th>alice<br>WIP 2/1</th>`, `<tr><th>Unassigned<br>WIP 0/1</th>`, `<option value="assignee" selected>`} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("board missing %q: %s", want, body)
+		}
+	}
+	if strings.Index(body, "alice") > strings.Index(body, "Unassigned") {
+		t.Fatalf("unassigned lane should come last: %s", body)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/board/prefs", strings.NewReader("swimlanes=labels"))
+	req.Header.Set("Origin", "http://example.com")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("invalid swimlanes status = %d", rr.Code)
+	}
+}
+
+func TestBatchEndpoint(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	if _, err := store.CreateProject(ctx, "web", "Web", ""); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	var ids []string
+	for _, title := range []string{"one", "two"} {
+		it, err := store.CreateIssue(ctx, issue.Item{Title: title, Status: issue.StatusTodo, Priority: "none", Labels: []string{"ui"}})
+		if err != nil {
+			t.Fatalf("create issue: %v", err)
+		}
+		ids = append(ids, it.ID)
+	}
+	h := NewHandler("example.com")
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/issues/batch", strings.NewReader(body))
+		req.Header.Set("Origin", "http://example.com")
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if body := rr.Body.String(); !strings.Contains(body, `<form id="bulk" hidden>`) || !strings.Contains(body, "<option>web</option>") {
+		t.Fatalf("list should carry the bulk bar: %s", body)
+	}
+
+	rr = post(`{"ids":["` + ids[0] + `","` + ids[1] + `","TRK-99"],"status":"ready","priority":"p1","labels":["bulk"],"project":"web"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("batch status = %d %s", rr.Code, rr.Body.String())
+	}
+	if body := rr.Body.String(); !strings.Contains(body, `{"id":"`+ids[0]+`"}`) || !strings.Contains(body, `{"id":"TRK-99","error":`) {
+		t.Fatalf("batch results = %s", body)
+	}
+	for _, id := range ids {
+		it, err := store.GetIssue(ctx, id)
+		if err != nil {
+			t.Fatalf("get issue: %v", err)
+		}
+		project, err := store.GetIssueProject(ctx, id)
+		if err != nil {
+			t.Fatalf("get project: %v", err)
+		}
+		if it.Status != "ready" || it.Priority != "p1" || strings.Join(it.Labels, ",") != "ui,bulk" || project != "web" {
+			t.Fatalf("%s after batch = %+v, project %q", id, it, project)
+		}
+	}
+
+	for _, body := range []string{`{"ids":[],"status":"ready"}`, `{"ids":["` + ids[0] + `"]}`, `{"ids":["` + ids[0] + `"],"status":"nope"}`} {
+		if rr := post(body); rr.Code != http.StatusBadRequest {
+			t.Fatalf("batch %s status = %d", body, rr.Code)
+		}
+	}
+}
+
+func TestQuickAdd(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	h := NewHandler("example.com")
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/quick", nil))
+	if body := rr.Body.String(); rr.Code != http.StatusOK || !strings.Contains(body, `name="viewport"`) || !strings.Contains(body, `name="title"`) {
+		t.Fatalf("quick page = %d %s", rr.Code, body)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/quick", strings.NewReader("title=Call+the+plumber&label=home"))
+	req.Header.Set("Origin", "http://example.com")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("quick add status = %d %s", rr.Code, rr.Body.String())
+	}
+	items, err := store.ListIssues(ctx, sqlite.ListFilter{Sort: "id"})
+	if err != nil {
+		t.Fatalf("list issues: %v", err)
+	}
+	if len(items) != 1 || items[0].Title != "Call the plumber" || strings.Join(items[0].Labels, ",") != "home" || items[0].Status != issue.StatusTodo {
+		t.Fatalf("issues after quick add = %+v", items)
+	}
+	if loc := rr.Header().Get("Location"); loc != "/quick?added="+items[0].ID+"&label=home" {
+		t.Fatalf("redirect = %q", loc)
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/quick?added="+items[0].ID+"&label=home", nil))
+	if body := rr.Body.String(); !strings.Contains(body, "Added <a href=\"/issues/"+items[0].ID+"\">") || !strings.Contains(body, `value="home"`) {
+		t.Fatalf("quick page after add = %s", body)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/quick", strings.NewReader("title=+"))
+	req.Header.Set("Origin", "http://example.com")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), `class="error"`) {
+		t.Fatalf("empty title = %d %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestCrossOriginPostsAreRefused(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "UI issue", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	h := NewHandler("example.com")
+	post := func(path, contentType, body, origin string) int {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	for _, tc := range []struct {
+		path, contentType, body string
+	}{
+		{"/issues/" + it.ID + "/done", "application/json", `{}`},
+		{"/issues/batch", "application/json", `{"ids":["` + it.ID + `"],"status":"done"}`},
+		{"/issues/" + it.ID + "/edit", "application/x-www-form-urlencoded", "title=Hijacked&body="},
+		{"/quick", "application/x-www-form-urlencoded", "title=Spam"},
+	} {
+		if code := post(tc.path, tc.contentType, tc.body, "https://evil.example"); code != http.StatusForbidden {
+			t.Fatalf("cross-origin POST %s = %d, want 403", tc.path, code)
+		}
+		if code := post(tc.path, tc.contentType, tc.body, ""); code != http.StatusForbidden {
+			t.Fatalf("POST %s without an origin = %d, want 403", tc.path, code)
+		}
+	}
+	got, err := store.GetIssue(ctx, it.ID)
+	if err != nil || got.Title != "UI issue" || got.Status != issue.StatusTodo {
+		t.Fatalf("issue after refused posts = %+v, %v", got, err)
+	}
+	if n, err := store.CountIssues(ctx, sqlite.ListFilter{}); err != nil || n != 1 {
+		t.Fatalf("CountIssues() after refused posts = %d, %v", n, err)
+	}
+
+	// httptest requests are addressed to example.com.
+	if code := post("/issues/"+it.ID+"/done", "application/json", `{}`, "http://example.com"); code != http.StatusOK {
+		t.Fatalf("same-origin POST = %d, want 200", code)
+	}
+}
+
+func TestRequestsToUnknownHostsAreRefused(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	it, err := store.CreateIssue(ctx, issue.Item{Title: "UI issue", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+
+	h := NewHandler("track.lan")
+	get := func(host string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Host = host
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr.Code
+	}
+	// A DNS-rebinding page reaches the UI under its own domain name.
+	if code := get("evil.example:8787"); code != http.StatusForbidden {
+		t.Fatalf("GET for evil.example = %d, want 403", code)
+	}
+	for _, host := range []string{"127.0.0.1:8787", "localhost:8787", "[::1]:8787", "192.168.1.20:8787", "track.lan:8787", "TRACK.LAN"} {
+		if code := get(host); code != http.StatusOK {
+			t.Fatalf("GET for %s = %d, want 200", host, code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/issues/"+it.ID+"/done", strings.NewReader(`{}`))
+	req.Host = "localhost:8787"
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Sec-Fetch-Site", "same-origin")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("POST with Sec-Fetch-Site: same-origin = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,336 @@
+// Package client is a typed Go client for the track HTTP API served by
+// `track api` and `track serve`. The API is described by /openapi.json.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultBaseURL    = "http://127.0.0.1:8788"
+	defaultMaxRetries = 3
+	defaultBackoff    = 200 * time.Millisecond
+)
+
+type Issue struct {
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	Status     string   `json:"status"`
+	Priority   string   `json:"priority"`
+	Assignee   string   `json:"assignee"`
+	Due        string   `json:"due"`
+	Labels     []string `json:"labels"`
+	NextAction string   `json:"next_action"`
+	Project    string   `json:"project,omitempty"`
+	Body       string   `json:"body"`
+	CreatedAt  string   `json:"created_at"`
+	UpdatedAt  string   `json:"updated_at"`
+}
+
+// NewIssue is the body of CreateIssue. IdempotencyKey is sent as the
+// Idempotency-Key header; when empty, CreateIssue generates one so its own
+// retries never create duplicates.
+type NewIssue struct {
+	Title          string   `json:"title"`
+	Body           string   `json:"body,omitempty"`
+	Priority       string   `json:"priority,omitempty"`
+	Assignee       string   `json:"assignee,omitempty"`
+	Due            string   `json:"due,omitempty"`
+	NextAction     string   `json:"next_action,omitempty"`
+	Labels         []string `json:"labels,omitempty"`
+	IdempotencyKey string   `json:"-"`
+}
+
+// IssuePatch lists the fields to change; nil fields are left untouched.
+// Labels replaces the full label set and Project "none" unlinks the project.
+type IssuePatch struct {
+	Title       *string   `json:"title,omitempty"`
+	Body        *string   `json:"body,omitempty"`
+	AppendBody  *string   `json:"append_body,omitempty"`
+	PrependBody *string   `json:"prepend_body,omitempty"`
+	Status      *string   `json:"status,omitempty"`
+	Priority    *string   `json:"priority,omitempty"`
+	Assignee    *string   `json:"assignee,omitempty"`
+	Due         *string   `json:"due,omitempty"`
+	NextAction  *string   `json:"next_action,omitempty"`
+	Labels      *[]string `json:"labels,omitempty"`
+	Project     *string   `json:"project,omitempty"`
+}
+
+type Section struct {
+	Name    string `json:"name"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	Present bool   `json:"present"`
+}
+
+type ListOptions struct {
+	Statuses []string
+	Label    string
+	Assignee string
+	Search   string
+	Sort     string
+	// Query is a query-language string such as "status:ready label:bug",
+	// ANDed with the other options.
+	Query string
+}
+
+// APIError is returned for non-2xx responses.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("track api: %d %s", e.StatusCode, e.Message)
+}
+
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	// Token is sent as a bearer token when set (see `track token add`).
+	Token string
+	// MaxRetries is how many times a request is retried after a transport
+	// error or a 429/502/503/504 response.
+	MaxRetries int
+	// Backoff is the delay before the first retry; it doubles each attempt.
+	Backoff time.Duration
+}
+
+// New returns a client for baseURL (DefaultBaseURL when empty).
+func New(baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: http.DefaultClient,
+		MaxRetries: defaultMaxRetries,
+		Backoff:    defaultBackoff,
+	}
+}
+
+func (c *Client) Health(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/healthz", nil, nil)
+}
+
+func (c *Client) ListIssues(ctx context.Context, opts ListOptions) ([]Issue, error) {
+	var resp struct {
+		Items []Issue `json:"items"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/issues"+opts.encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// CountIssues returns how many issues match opts without fetching them.
+func (c *Client) CountIssues(ctx context.Context, opts ListOptions) (int, error) {
+	var resp struct {
+		Count int `json:"count"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/issues:count"+opts.encode(), nil, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Count, nil
+}
+
+func (opts ListOptions) encode() string {
+	q := url.Values{}
+	if len(opts.Statuses) > 0 {
+		q.Set("status", strings.Join(opts.Statuses, ","))
+	}
+	if opts.Label != "" {
+		q.Set("label", opts.Label)
+	}
+	if opts.Assignee != "" {
+		q.Set("assignee", opts.Assignee)
+	}
+	if opts.Search != "" {
+		q.Set("search", opts.Search)
+	}
+	if opts.Sort != "" {
+		q.Set("sort", opts.Sort)
+	}
+	if opts.Query != "" {
+		q.Set("q", opts.Query)
+	}
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
+
+func (c *Client) CreateIssue(ctx context.Context, in NewIssue) (Issue, error) {
+	key := in.IdempotencyKey
+	if key == "" {
+		var b [16]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return Issue{}, fmt.Errorf("generate idempotency key: %w", err)
+		}
+		key = hex.EncodeToString(b[:])
+	}
+	var out Issue
+	err := c.doWithHeader(ctx, http.MethodPost, "/issues", http.Header{"Idempotency-Key": {key}}, in, &out)
+	return out, err
+}
+
+func (c *Client) GetIssue(ctx context.Context, id string) (Issue, error) {
+	var out Issue
+	err := c.do(ctx, http.MethodGet, "/issues/"+url.PathEscape(id), nil, &out)
+	return out, err
+}
+
+func (c *Client) UpdateIssue(ctx context.Context, id string, patch IssuePatch) (Issue, error) {
+	var out Issue
+	err := c.do(ctx, http.MethodPatch, "/issues/"+url.PathEscape(id), patch, &out)
+	return out, err
+}
+
+// Reorder moves id directly before beforeID, or after afterID when beforeID
+// is empty.
+func (c *Client) Reorder(ctx context.Context, id, beforeID, afterID string) (Issue, error) {
+	req := map[string]string{}
+	if beforeID != "" {
+		req["before"] = beforeID
+	}
+	if afterID != "" {
+		req["after"] = afterID
+	}
+	var out Issue
+	err := c.do(ctx, http.MethodPost, "/issues/"+url.PathEscape(id)+"/reorder", req, &out)
+	return out, err
+}
+
+// Next returns the top actionable issue, or nil when there is none.
+func (c *Client) Next(ctx context.Context) (*Issue, error) {
+	var resp struct {
+		Item *Issue `json:"item"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/next", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Item, nil
+}
+
+func (c *Client) GetSection(ctx context.Context, id, name string) (Section, error) {
+	var out Section
+	err := c.do(ctx, http.MethodGet, "/issues/"+url.PathEscape(id)+"/sections/"+url.PathEscape(name), nil, &out)
+	return out, err
+}
+
+// SetSection replaces a body section, or appends to it when appendContent is
+// set.
+func (c *Client) SetSection(ctx context.Context, id, name, content string, appendContent bool) (Section, error) {
+	req := struct {
+		Content string `json:"content"`
+		Append  bool   `json:"append"`
+	}{content, appendContent}
+	var out Section
+	err := c.do(ctx, http.MethodPatch, "/issues/"+url.PathEscape(id)+"/sections/"+url.PathEscape(name), req, &out)
+	return out, err
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	return c.doWithHeader(ctx, method, path, nil, body, out)
+}
+
+func (c *Client) doWithHeader(ctx context.Context, method, path string, header http.Header, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	backoff := c.Backoff
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("build request: %w", err)
+		}
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Accept", "application/json")
+		if c.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.Token)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+
+		resp, err := httpClient.Do(req)
+		if err == nil && !retryableStatus(resp.StatusCode) {
+			return decodeResponse(resp, out)
+		}
+		if attempt >= c.MaxRetries {
+			if err != nil {
+				return fmt.Errorf("track api: %w", err)
+			}
+			return decodeResponse(resp, out)
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func decodeResponse(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var e struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&e)
+		if e.Error == "" {
+			e.Error = http.StatusText(resp.StatusCode)
+		}
+		return &APIError{StatusCode: resp.StatusCode, Message: e.Error}
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
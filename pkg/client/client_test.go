@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/myuon/track/internal/api"
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+func TestClientAgainstAPI(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	a, err := store.CreateIssue(ctx, issue.Item{Title: "a", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	b, err := store.CreateIssue(ctx, issue.Item{Title: "b", Status: issue.StatusTodo, Priority: "p2"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+
+	srv := httptest.NewServer(api.NewHandler())
+	t.Cleanup(srv.Close)
+	c := New(srv.URL)
+
+	if err := c.Health(ctx); err != nil {
+		t.Fatalf("Health() error: %v", err)
+	}
+	items, err := c.ListIssues(ctx, ListOptions{Statuses: []string{issue.StatusTodo}})
+	if err != nil || len(items) != 2 {
+		t.Fatalf("ListIssues() = %d items, err %v", len(items), err)
+	}
+	if n, err := c.CountIssues(ctx, ListOptions{Query: "status:todo"}); err != nil || n != 2 {
+		t.Fatalf("CountIssues() = %d, err %v", n, err)
+	}
+
+	status := issue.StatusReady
+	labels := []string{"api"}
+	updated, err := c.UpdateIssue(ctx, a.ID, IssuePatch{Status: &status, Labels: &labels})
+	if err != nil {
+		t.Fatalf("UpdateIssue() error: %v", err)
+	}
+	if updated.Status != issue.StatusReady || len(updated.Labels) != 1 {
+		t.Fatalf("unexpected update result: %+v", updated)
+	}
+
+	if _, err := c.Reorder(ctx, b.ID, a.ID, ""); err != nil {
+		t.Fatalf("Reorder() error: %v", err)
+	}
+	next, err := c.Next(ctx)
+	if err != nil || next == nil || next.ID != b.ID {
+		t.Fatalf("Next() = %+v, err %v", next, err)
+	}
+
+	sec, err := c.SetSection(ctx, a.ID, "spec", "Do the thing.", false)
+	if err != nil || !sec.Present || sec.Content != "Do the thing." {
+		t.Fatalf("SetSection() = %+v, err %v", sec, err)
+	}
+	if got, err := c.GetSection(ctx, a.ID, "spec"); err != nil || got.Content != "Do the thing." {
+		t.Fatalf("GetSection() = %+v, err %v", got, err)
+	}
+
+	created, err := c.CreateIssue(ctx, NewIssue{Title: "c", Priority: "p1", IdempotencyKey: "k1"})
+	if err != nil || created.Status != issue.StatusTodo {
+		t.Fatalf("CreateIssue() = %+v, err %v", created, err)
+	}
+	if again, err := c.CreateIssue(ctx, NewIssue{Title: "c", Priority: "p1", IdempotencyKey: "k1"}); err != nil || again.ID != created.ID {
+		t.Fatalf("CreateIssue() retry = %+v, err %v, want %s", again, err, created.ID)
+	}
+
+	if _, err := c.GetIssue(ctx, "TRK-999"); !IsNotFound(err) {
+		t.Fatalf("GetIssue(missing) error = %v, want not found", err)
+	}
+}
+
+func TestClientRetriesUnavailable(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := New(srv.URL)
+	c.Backoff = time.Millisecond
+	if err := c.Health(context.Background()); err != nil {
+		t.Fatalf("Health() error: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("calls = %d, want 3", calls)
+	}
+
+	var keys []string
+	srv2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) < 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"TRK-1"}`))
+	}))
+	t.Cleanup(srv2.Close)
+	c2 := New(srv2.URL)
+	c2.Backoff = time.Millisecond
+	if _, err := c2.CreateIssue(context.Background(), NewIssue{Title: "x"}); err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Fatalf("retries should reuse one generated idempotency key, got %q", keys)
+	}
+
+	atomic.StoreInt32(&calls, -10)
+	c.MaxRetries = 1
+	err := c.Health(context.Background())
+	if apiErr, ok := err.(*APIError); !ok || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 after retries, got %v", err)
+	}
+}
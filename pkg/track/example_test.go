@@ -0,0 +1,27 @@
+package track_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/myuon/track/pkg/track"
+)
+
+func Example() {
+	ctx := context.Background()
+	tr, err := track.Open(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer tr.Close()
+
+	it, err := tr.CreateIssue(ctx, track.NewIssue{Title: "Write release notes", Priority: "p2"})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if _, err := tr.Transition(ctx, it.ID, track.StatusInProgress); err != nil {
+		fmt.Println(err)
+	}
+}
@@ -0,0 +1,240 @@
+// Package track lets Go programs embed the tracker directly. It opens the same
+// database as the CLI ($TRACK_HOME/track.db, default ~/.track) and applies
+// the same validation, automations, and hooks.
+package track
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/myuon/track/internal/issue"
+	"github.com/myuon/track/internal/service"
+	"github.com/myuon/track/internal/store/sqlite"
+)
+
+const (
+	StatusTodo       = issue.StatusTodo
+	StatusReady      = issue.StatusReady
+	StatusInProgress = issue.StatusInProgress
+	StatusDone       = issue.StatusDone
+	StatusArchived   = issue.StatusArchived
+)
+
+type Issue struct {
+	ID         string
+	Title      string
+	Status     string
+	Priority   string
+	Assignee   string
+	Due        string
+	Labels     []string
+	NextAction string
+	Body       string
+	CreatedAt  string
+	UpdatedAt  string
+}
+
+// NewIssue is the input for CreateIssue. Priority defaults to "none".
+// Project links the new issue to a project key and fills in that project's
+// default labels, priority, and assignee.
+// ExternalID records the issue's ID in the system it came from and must be
+// unique. When IdempotencyKey is set, repeating the same request within 24
+// hours returns the issue created the first time instead of a duplicate.
+type NewIssue struct {
+	Title          string
+	Body           string
+	Priority       string
+	Assignee       string
+	Due            string
+	Labels         []string
+	Project        string
+	ExternalID     string
+	IdempotencyKey string
+}
+
+// Update lists the fields to change; nil fields are left untouched. Labels
+// replaces the full label set and Project "none" unlinks the project.
+type Update struct {
+	Title      *string
+	Body       *string
+	Status     *string
+	Priority   *string
+	Assignee   *string
+	Due        *string
+	NextAction *string
+	Labels     *[]string
+	Project    *string
+}
+
+// Query filters issues. With no Statuses, done and archived issues are
+// excluded, matching `track list`.
+type Query struct {
+	Statuses   []string
+	Priorities []string
+	Label      string
+	Assignee   string
+	Search     string
+	Project    string
+	// Sort is a comma-separated list of keys such as "priority,-updated";
+	// the default is most recently updated first.
+	Sort string
+}
+
+type Tracker struct {
+	store *sqlite.Store
+}
+
+func Open(ctx context.Context) (*Tracker, error) {
+	store, err := sqlite.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Tracker{store: store}, nil
+}
+
+func (t *Tracker) Close() error {
+	return t.store.Close()
+}
+
+// CreateIssue creates a todo issue and fires issue.created.
+func (t *Tracker) CreateIssue(ctx context.Context, in NewIssue) (Issue, error) {
+	if err := issue.ValidateTitle(in.Title); err != nil {
+		return Issue{}, err
+	}
+	if in.Priority == "" {
+		in.Priority = "none"
+	}
+	if err := issue.ValidatePriority(in.Priority); err != nil {
+		return Issue{}, err
+	}
+	if err := issue.ValidateDue(in.Due); err != nil {
+		return Issue{}, err
+	}
+	var defaults sqlite.ProjectDefaults
+	project := strings.TrimSpace(in.Project)
+	if project != "" {
+		p, err := t.store.GetProject(ctx, project)
+		if err != nil {
+			return Issue{}, err
+		}
+		defaults = p.Defaults
+	}
+	batchCtx, flush := sqlite.BatchEvents(ctx)
+	it, _, err := service.CreateIssueIdempotent(batchCtx, t.store, in.IdempotencyKey, defaults.Apply(issue.Item{
+		Title:      in.Title,
+		Status:     issue.StatusTodo,
+		Priority:   in.Priority,
+		Assignee:   issue.NormalizeAssignee(in.Assignee),
+		Due:        in.Due,
+		Labels:     in.Labels,
+		Body:       in.Body,
+		ExternalID: in.ExternalID,
+	}), project)
+	if err != nil {
+		return Issue{}, err
+	}
+	return fromItem(it), flush()
+}
+
+// Get accepts "TRK-12" or just "12".
+func (t *Tracker) Get(ctx context.Context, id string) (Issue, error) {
+	it, err := t.store.GetIssue(ctx, NormalizeID(id))
+	if err != nil {
+		return Issue{}, err
+	}
+	return fromItem(it), nil
+}
+
+// Update applies u and fires issue.updated, plus issue.status_changed and
+// issue.completed when the status changes.
+func (t *Tracker) Update(ctx context.Context, id string, u Update) (Issue, error) {
+	it, err := service.UpdateIssue(ctx, t.store, NormalizeID(id), service.Update{
+		UpdateIssueInput: sqlite.UpdateIssueInput{
+			Title:      u.Title,
+			Body:       u.Body,
+			Status:     u.Status,
+			Priority:   u.Priority,
+			Assignee:   u.Assignee,
+			Due:        u.Due,
+			NextAction: u.NextAction,
+		},
+		Labels:  u.Labels,
+		Project: u.Project,
+	})
+	if err != nil {
+		return Issue{}, err
+	}
+	return fromItem(it), nil
+}
+
+// Transition moves an issue to status.
+func (t *Tracker) Transition(ctx context.Context, id, status string) (Issue, error) {
+	it, err := service.SetStatus(ctx, t.store, NormalizeID(id), status)
+	if err != nil {
+		return Issue{}, err
+	}
+	return fromItem(it), nil
+}
+
+func (t *Tracker) Query(ctx context.Context, q Query) ([]Issue, error) {
+	for _, st := range q.Statuses {
+		if err := t.store.ValidateStatus(ctx, st); err != nil {
+			return nil, err
+		}
+	}
+	items, err := t.store.ListIssues(ctx, sqlite.ListFilter{
+		Statuses:        q.Statuses,
+		Priorities:      q.Priorities,
+		ExcludeDone:     len(q.Statuses) == 0,
+		ExcludeArchived: len(q.Statuses) == 0,
+		Label:           strings.TrimSpace(q.Label),
+		Assignee:        issue.NormalizeAssignee(q.Assignee),
+		Search:          strings.TrimSpace(q.Search),
+		Project:         strings.TrimSpace(q.Project),
+		Sort:            q.Sort,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Issue, 0, len(items))
+	for _, it := range items {
+		out = append(out, fromItem(it))
+	}
+	return out, nil
+}
+
+// Next returns the top actionable issue, using the same selection as
+// `track next`. The second return value is false when nothing is actionable.
+func (t *Tracker) Next(ctx context.Context) (Issue, bool, error) {
+	it, ok, err := t.store.NextIssue(ctx)
+	if err != nil || !ok {
+		return Issue{}, ok, err
+	}
+	return fromItem(it), true, nil
+}
+
+// NormalizeID turns a bare issue number into a TRK- ID.
+func NormalizeID(raw string) string {
+	id := strings.TrimSpace(raw)
+	if _, err := strconv.Atoi(id); err == nil {
+		return "TRK-" + id
+	}
+	return id
+}
+
+func fromItem(it issue.Item) Issue {
+	return Issue{
+		ID:         it.ID,
+		Title:      it.Title,
+		Status:     it.Status,
+		Priority:   it.Priority,
+		Assignee:   it.Assignee,
+		Due:        it.Due,
+		Labels:     it.Labels,
+		NextAction: it.NextAction,
+		Body:       it.Body,
+		CreatedAt:  it.CreatedAt,
+		UpdatedAt:  it.UpdatedAt,
+	}
+}
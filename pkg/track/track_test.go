@@ -0,0 +1,65 @@
+package track
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTrackerLifecycle(t *testing.T) {
+	t.Setenv("TRACK_HOME", t.TempDir())
+	ctx := context.Background()
+
+	tr, err := Open(ctx)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = tr.Close() })
+
+	if _, err := tr.CreateIssue(ctx, NewIssue{Title: ""}); err == nil {
+		t.Fatalf("CreateIssue() with empty title should fail")
+	}
+	a, err := tr.CreateIssue(ctx, NewIssue{Title: "embed track", Labels: []string{"lib"}})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+	if a.Status != StatusTodo || a.Priority != "none" {
+		t.Fatalf("unexpected defaults: %+v", a)
+	}
+	b, err := tr.CreateIssue(ctx, NewIssue{Title: "urgent", Priority: "p0"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error: %v", err)
+	}
+
+	next, ok, err := tr.Next(ctx)
+	if err != nil || !ok || next.ID != b.ID {
+		t.Fatalf("Next() = %+v, %v, %v; want %s", next, ok, err, b.ID)
+	}
+
+	if _, err := tr.Transition(ctx, b.ID, StatusDone); err != nil {
+		t.Fatalf("Transition() error: %v", err)
+	}
+	open, err := tr.Query(ctx, Query{})
+	if err != nil {
+		t.Fatalf("Query() error: %v", err)
+	}
+	if len(open) != 1 || open[0].ID != a.ID {
+		t.Fatalf("default query should exclude done issues: %+v", open)
+	}
+	done, err := tr.Query(ctx, Query{Statuses: []string{StatusDone}})
+	if err != nil || len(done) != 1 || done[0].ID != b.ID {
+		t.Fatalf("Query(done) = %+v, err %v", done, err)
+	}
+	if _, err := tr.Query(ctx, Query{Statuses: []string{"bogus"}}); err == nil {
+		t.Fatalf("Query() with unknown status should fail")
+	}
+
+	title := "embed track as a library"
+	updated, err := tr.Update(ctx, "1", Update{Title: &title})
+	if err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	got, err := tr.Get(ctx, updated.ID)
+	if err != nil || got.Title != title {
+		t.Fatalf("Get() = %+v, err %v", got, err)
+	}
+}